@@ -0,0 +1,120 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+const clientTimeout = time.Minute
+
+// Client manages the enumerations of a remote Amass instance through the REST API served by 'amass api'.
+type Client struct {
+	// The URL of the instance, such as http://10.0.0.2:8080
+	URL string
+	// The key required by the REST API, which is not sent when empty
+	Key  string
+	http *http.Client
+}
+
+// NewClient returns the Client of the REST API served at the URL.
+func NewClient(url, key string) *Client {
+	return &Client{
+		URL:  strings.TrimSuffix(url, "/"),
+		Key:  key,
+		http: &http.Client{Timeout: clientTimeout},
+	}
+}
+
+// Start queues the enumeration on the remote instance and returns its status.
+func (c *Client) Start(ctx context.Context, req *EnumRequest) (*EnumStatus, error) {
+	var status EnumStatus
+
+	if err := c.do(ctx, http.MethodPost, "/v1/enumerations", req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Status returns the status of the remote enumeration.
+func (c *Client) Status(ctx context.Context, id string) (*EnumStatus, error) {
+	var status EnumStatus
+
+	if err := c.do(ctx, http.MethodGet, "/v1/enumerations/"+id, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Results returns the findings of the remote enumeration.
+func (c *Client) Results(ctx context.Context, id string) ([]*requests.Output, error) {
+	var results []*requests.Output
+
+	if err := c.do(ctx, http.MethodGet, "/v1/enumerations/"+id+"/results", nil, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Stop interrupts the remote enumeration.
+func (c *Client) Stop(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/v1/enumerations/"+id+"/stop", nil, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Key != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Key)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(io.LimitReader(resp.Body, restMaxRequestSize)).Decode(&e); err != nil || e.Error == "" {
+			e.Error = resp.Status
+		}
+		return fmt.Errorf("%s %s returned %d: %s", method, c.URL+path, resp.StatusCode, e.Error)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Terminal returns true when the enumeration has reached a state it will not leave.
+func (s *EnumStatus) Terminal() bool {
+	return s.State == EnumFinished || s.State == EnumFailed || s.State == EnumStopped
+}
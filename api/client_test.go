@@ -0,0 +1,63 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "client")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	m := NewEnumManager(dir, func(ctx context.Context, req *EnumRequest, out string) error {
+		line := `{"name":"www.` + req.Domains[0] + `","domain":"` + req.Domains[0] + "\"}\n"
+		return ioutil.WriteFile(filepath.Join(out, ResultsFileName), []byte(line), 0644)
+	})
+	defer m.Close()
+	srv := httptest.NewServer(RequireAPIKey("secret", NewRESTHandler(m, nil)))
+	defer srv.Close()
+
+	ctx := context.Background()
+	if _, err := NewClient(srv.URL, "wrong").Start(ctx, &EnumRequest{Domains: []string{"owasp.org"}}); err == nil {
+		t.Errorf("The request with the wrong API key was accepted")
+	}
+
+	c := NewClient(srv.URL+"/", "secret")
+	if _, err := c.Start(ctx, &EnumRequest{Domains: []string{"owasp.org"}, BruteShard: "1/2"}); err == nil {
+		t.Errorf("The brute forcing shard was accepted without brute forcing")
+	}
+
+	status, err := c.Start(ctx, &EnumRequest{Domains: []string{"owasp.org"}, Brute: true, BruteShard: "1/2", CIDRs: []string{"10.0.0.0/24"}})
+	if err != nil {
+		t.Fatalf("Failed to start the enumeration: %v", err)
+	}
+	for i := 0; i < 100 && !status.Terminal(); i++ {
+		time.Sleep(20 * time.Millisecond)
+		if status, err = c.Status(ctx, status.ID); err != nil {
+			t.Fatalf("Failed to obtain the status: %v", err)
+		}
+	}
+	if status.State != EnumFinished || status.Request.BruteShard != "1/2" {
+		t.Fatalf("The enumeration did not finish: %v", status)
+	}
+
+	results, err := c.Results(ctx, status.ID)
+	if err != nil || len(results) != 1 || results[0].Name != "www.owasp.org" {
+		t.Errorf("The results were not returned: %v: %v", results, err)
+	}
+	if err := c.Stop(ctx, status.ID); err == nil {
+		t.Errorf("The finished enumeration was stopped")
+	}
+}
@@ -0,0 +1,548 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// The schema only provides queries, since the API does not modify the graph database.
+const graphQLSchema = `
+schema {
+	query: Query
+}
+
+type Query {
+	events(domain: String): [Event!]!
+	names(domain: String!): [Name!]!
+	name(name: String!): Name
+	address(address: String!): Address
+	asn(number: Int!): ASN
+	certificate(serial: String!): Certificate
+}
+
+type Event {
+	id: String!
+	start: String!
+	finish: String!
+	domains: [String!]!
+	names: [Name!]!
+}
+
+type Name {
+	name: String!
+	addresses: [Address!]!
+	cnames: [Name!]!
+	sources: [String!]!
+	tags: [String!]!
+	notes: [String!]!
+	certificates: [Certificate!]!
+	services: [HTTPService!]!
+}
+
+type Address {
+	address: String!
+	names: [Name!]!
+	netblock: Netblock
+	tags: [String!]!
+	openPorts: [String!]!
+	certificates: [Certificate!]!
+}
+
+type Netblock {
+	cidr: String!
+	asn: ASN
+}
+
+type Certificate {
+	serial: String!
+	issuer: String!
+	subject: String!
+	notBefore: String!
+	notAfter: String!
+	sans: [String!]!
+	names: [Name!]!
+	addresses: [Address!]!
+}
+
+type HTTPService {
+	url: String!
+	statusCode: Int!
+	server: String!
+	title: String!
+	technologies: [String!]!
+}
+
+type ASN {
+	number: Int!
+	description: String!
+	netblocks: [Netblock!]!
+}
+`
+
+const (
+	// The deepest selection accepted, which is enough to reach the ASN of a name through its addresses
+	graphQLMaxDepth = 8
+	// The longest query document accepted, since the relationships between the nodes can be nested without bound
+	graphQLMaxQueryLength = 4096
+	// The largest request accepted, including the variables provided with the query
+	graphQLMaxBodySize = 64 * 1024
+)
+
+// NewGraphQLHandler returns the handler that serves the read-only GraphQL API over the graph database.
+// The queries exceeding the maximum depth or length are rejected before they are executed.
+func NewGraphQLHandler(db *netmap.Graph) (http.Handler, error) {
+	schema, err := graphql.ParseSchema(graphQLSchema, &queryResolver{db: db}, graphql.MaxDepth(graphQLMaxDepth))
+	if err != nil {
+		return nil, err
+	}
+	return &graphQLHandler{relay: &relay.Handler{Schema: schema}}, nil
+}
+
+// graphQLHandler limits the length of the query documents served by the relay handler.
+type graphQLHandler struct {
+	relay *relay.Handler
+}
+
+func (h *graphQLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, graphQLMaxBodySize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > graphQLMaxBodySize {
+		http.Error(w, "the request body is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var params struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(params.Query) > graphQLMaxQueryLength {
+		http.Error(w, fmt.Sprintf("the query exceeds the maximum length of %d", graphQLMaxQueryLength), http.StatusBadRequest)
+		return
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	h.relay.ServeHTTP(w, r)
+}
+
+type queryResolver struct {
+	db *netmap.Graph
+}
+
+func (q *queryResolver) Events(ctx context.Context, args struct{ Domain *string }) []*eventResolver {
+	var events []string
+	if args.Domain != nil && *args.Domain != "" {
+		events = q.db.EventsInScope(ctx, strings.ToLower(*args.Domain))
+	} else {
+		events = q.db.EventList(ctx)
+	}
+
+	var list []*eventResolver
+	for _, event := range events {
+		start, finish := eventDateRange(ctx, q.db, event)
+		// Events without a start time have been pruned from the graph
+		if start.IsZero() {
+			continue
+		}
+		list = append(list, &eventResolver{db: q.db, id: event, start: start, finish: finish})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].start.Before(list[j].start)
+	})
+	return list
+}
+
+func (q *queryResolver) Names(ctx context.Context, args struct{ Domain string }) []*nameResolver {
+	domain := strings.ToLower(args.Domain)
+	names := stringset.New()
+	defer names.Close()
+
+	for _, event := range q.db.EventsInScope(ctx, domain) {
+		for _, name := range q.db.EventFQDNs(ctx, event) {
+			if name == domain || strings.HasSuffix(name, "."+domain) {
+				names.Insert(name)
+			}
+		}
+	}
+	return newNameResolvers(q.db, names.Slice())
+}
+
+func (q *queryResolver) Name(ctx context.Context, args struct{ Name string }) *nameResolver {
+	name := strings.ToLower(args.Name)
+
+	if _, err := q.db.ReadNode(ctx, name, netmap.TypeFQDN); err != nil {
+		return nil
+	}
+	return &nameResolver{db: q.db, name: name}
+}
+
+func (q *queryResolver) Address(ctx context.Context, args struct{ Address string }) *addrResolver {
+	if _, err := q.db.ReadNode(ctx, args.Address, netmap.TypeAddr); err != nil {
+		return nil
+	}
+	return &addrResolver{db: q.db, addr: args.Address}
+}
+
+func (q *queryResolver) Asn(ctx context.Context, args struct{ Number int32 }) *asnResolver {
+	if _, err := q.db.ReadNode(ctx, strconv.Itoa(int(args.Number)), netmap.TypeAS); err != nil {
+		return nil
+	}
+	return &asnResolver{db: q.db, asn: int(args.Number)}
+}
+
+func (q *queryResolver) Certificate(ctx context.Context, args struct{ Serial string }) *certResolver {
+	id := requests.CertNodeID(args.Serial)
+
+	if _, err := q.db.ReadNode(ctx, id, "certificate"); err != nil {
+		return nil
+	}
+	return &certResolver{db: q.db, id: id}
+}
+
+type eventResolver struct {
+	db     *netmap.Graph
+	id     string
+	start  time.Time
+	finish time.Time
+}
+
+func (e *eventResolver) ID() string {
+	return e.id
+}
+
+func (e *eventResolver) Start() string {
+	return e.start.Format(time.RFC3339)
+}
+
+func (e *eventResolver) Finish() string {
+	return e.finish.Format(time.RFC3339)
+}
+
+func (e *eventResolver) Domains(ctx context.Context) []string {
+	domains := e.db.EventDomains(ctx, e.id)
+
+	sort.Strings(domains)
+	return domains
+}
+
+func (e *eventResolver) Names(ctx context.Context) []*nameResolver {
+	return newNameResolvers(e.db, e.db.EventFQDNs(ctx, e.id))
+}
+
+type nameResolver struct {
+	db   *netmap.Graph
+	name string
+}
+
+func newNameResolvers(db *netmap.Graph, names []string) []*nameResolver {
+	sort.Strings(names)
+
+	var list []*nameResolver
+	for _, name := range names {
+		list = append(list, &nameResolver{db: db, name: name})
+	}
+	return list
+}
+
+func (n *nameResolver) Name() string {
+	return n.name
+}
+
+func (n *nameResolver) Addresses(ctx context.Context) []*addrResolver {
+	var list []*addrResolver
+
+	for _, addr := range outNodes(ctx, n.db, n.name, "a_record", "aaaa_record") {
+		list = append(list, &addrResolver{db: n.db, addr: addr})
+	}
+	return list
+}
+
+func (n *nameResolver) Cnames(ctx context.Context) []*nameResolver {
+	return newNameResolvers(n.db, outNodes(ctx, n.db, n.name, "cname_record"))
+}
+
+func (n *nameResolver) Sources(ctx context.Context) []string {
+	sources, err := n.db.NodeSources(ctx, netmap.Node(n.name))
+	if err != nil {
+		return []string{}
+	}
+
+	sort.Strings(sources)
+	return sources
+}
+
+func (n *nameResolver) Tags(ctx context.Context) []string {
+	return nodeProperties(ctx, n.db, n.name, "user_tag")
+}
+
+func (n *nameResolver) Notes(ctx context.Context) []string {
+	return nodeProperties(ctx, n.db, n.name, "user_note")
+}
+
+func (n *nameResolver) Certificates(ctx context.Context) []*certResolver {
+	return newCertResolvers(ctx, n.db, n.name)
+}
+
+func (n *nameResolver) Services(ctx context.Context) []*serviceResolver {
+	services := outNodes(ctx, n.db, n.name, "http_service")
+	sort.Strings(services)
+
+	var list []*serviceResolver
+	for _, u := range services {
+		list = append(list, &serviceResolver{db: n.db, url: u})
+	}
+	return list
+}
+
+type addrResolver struct {
+	db   *netmap.Graph
+	addr string
+}
+
+func (a *addrResolver) Address() string {
+	return a.addr
+}
+
+func (a *addrResolver) Names(ctx context.Context) []*nameResolver {
+	return newNameResolvers(a.db, inNodes(ctx, a.db, a.addr, "a_record", "aaaa_record"))
+}
+
+func (a *addrResolver) Netblock(ctx context.Context) *netblockResolver {
+	cidrs := inNodes(ctx, a.db, a.addr, "contains")
+	if len(cidrs) == 0 {
+		return nil
+	}
+	return &netblockResolver{db: a.db, cidr: cidrs[0]}
+}
+
+func (a *addrResolver) Tags(ctx context.Context) []string {
+	return nodeProperties(ctx, a.db, a.addr, "user_tag")
+}
+
+// OpenPorts returns the ports imported from the nmap and masscan scans, such as "443/tcp https nginx 1.18".
+func (a *addrResolver) OpenPorts(ctx context.Context) []string {
+	return nodeProperties(ctx, a.db, a.addr, "open_port")
+}
+
+func (a *addrResolver) Certificates(ctx context.Context) []*certResolver {
+	return newCertResolvers(ctx, a.db, a.addr)
+}
+
+type netblockResolver struct {
+	db   *netmap.Graph
+	cidr string
+}
+
+func (n *netblockResolver) Cidr() string {
+	return n.cidr
+}
+
+func (n *netblockResolver) Asn(ctx context.Context) *asnResolver {
+	for _, as := range inNodes(ctx, n.db, n.cidr, "prefix") {
+		if asn, err := strconv.Atoi(as); err == nil {
+			return &asnResolver{db: n.db, asn: asn}
+		}
+	}
+	return nil
+}
+
+type asnResolver struct {
+	db  *netmap.Graph
+	asn int
+}
+
+func (a *asnResolver) Number() int32 {
+	return int32(a.asn)
+}
+
+func (a *asnResolver) Description(ctx context.Context) string {
+	return a.db.ReadASDescription(ctx, a.asn)
+}
+
+func (a *asnResolver) Netblocks(ctx context.Context) []*netblockResolver {
+	prefixes := a.db.ReadASPrefixes(ctx, a.asn)
+	sort.Strings(prefixes)
+
+	var list []*netblockResolver
+	for _, cidr := range prefixes {
+		list = append(list, &netblockResolver{db: a.db, cidr: cidr})
+	}
+	return list
+}
+
+type certResolver struct {
+	db *netmap.Graph
+	id string
+}
+
+func newCertResolvers(ctx context.Context, db *netmap.Graph, id string) []*certResolver {
+	certs := outNodes(ctx, db, id, "certificate")
+	sort.Strings(certs)
+
+	var list []*certResolver
+	for _, cert := range certs {
+		list = append(list, &certResolver{db: db, id: cert})
+	}
+	return list
+}
+
+func (c *certResolver) property(ctx context.Context, predicate string) string {
+	if values := nodeProperties(ctx, c.db, c.id, predicate); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c *certResolver) Serial(ctx context.Context) string {
+	return c.property(ctx, "serial")
+}
+
+func (c *certResolver) Issuer(ctx context.Context) string {
+	return c.property(ctx, "issuer")
+}
+
+func (c *certResolver) Subject(ctx context.Context) string {
+	return c.property(ctx, "subject")
+}
+
+func (c *certResolver) NotBefore(ctx context.Context) string {
+	return c.property(ctx, "not_before")
+}
+
+func (c *certResolver) NotAfter(ctx context.Context) string {
+	return c.property(ctx, "not_after")
+}
+
+func (c *certResolver) Sans(ctx context.Context) []string {
+	return nodeProperties(ctx, c.db, c.id, "san")
+}
+
+func (c *certResolver) Names(ctx context.Context) []*nameResolver {
+	var names []string
+
+	for _, id := range inNodes(ctx, c.db, c.id, "certificate") {
+		if net.ParseIP(id) == nil {
+			names = append(names, id)
+		}
+	}
+	return newNameResolvers(c.db, names)
+}
+
+func (c *certResolver) Addresses(ctx context.Context) []*addrResolver {
+	var list []*addrResolver
+
+	for _, id := range inNodes(ctx, c.db, c.id, "certificate") {
+		if net.ParseIP(id) != nil {
+			list = append(list, &addrResolver{db: c.db, addr: id})
+		}
+	}
+	return list
+}
+
+type serviceResolver struct {
+	db  *netmap.Graph
+	url string
+}
+
+func (s *serviceResolver) property(ctx context.Context, predicate string) string {
+	if values := nodeProperties(ctx, s.db, s.url, predicate); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (s *serviceResolver) URL() string {
+	return s.url
+}
+
+func (s *serviceResolver) StatusCode(ctx context.Context) int32 {
+	code, _ := strconv.Atoi(s.property(ctx, "status_code"))
+	return int32(code)
+}
+
+func (s *serviceResolver) Server(ctx context.Context) string {
+	return s.property(ctx, "server")
+}
+
+func (s *serviceResolver) Title(ctx context.Context) string {
+	return s.property(ctx, "title")
+}
+
+func (s *serviceResolver) Technologies(ctx context.Context) []string {
+	return nodeProperties(ctx, s.db, s.url, "technology")
+}
+
+// Returns the start and finish times of the event, including the events that were compacted into it.
+func eventDateRange(ctx context.Context, db *netmap.Graph, uuid string) (time.Time, time.Time) {
+	start, finish := db.EventDateRange(ctx, uuid)
+
+	for _, v := range nodeProperties(ctx, db, uuid, requests.LastSeenPredicate) {
+		if t, err := time.Parse(time.RFC3339, v); err == nil && t.After(finish) {
+			finish = t
+		}
+	}
+	return start, finish
+}
+
+func outNodes(ctx context.Context, db *netmap.Graph, id string, predicates ...string) []string {
+	var nodes []string
+
+	if edges, err := db.ReadOutEdges(ctx, netmap.Node(id), predicates...); err == nil {
+		for _, edge := range edges {
+			nodes = append(nodes, db.NodeToID(edge.To))
+		}
+	}
+	return nodes
+}
+
+func inNodes(ctx context.Context, db *netmap.Graph, id string, predicates ...string) []string {
+	var nodes []string
+
+	if edges, err := db.ReadInEdges(ctx, netmap.Node(id), predicates...); err == nil {
+		for _, edge := range edges {
+			nodes = append(nodes, db.NodeToID(edge.From))
+		}
+	}
+	return nodes
+}
+
+func nodeProperties(ctx context.Context, db *netmap.Graph, id, predicate string) []string {
+	values := []string{}
+
+	if props, err := db.ReadProperties(ctx, netmap.Node(id), predicate); err == nil {
+		for _, p := range props {
+			if v, ok := p.Value.Native().(string); ok && v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+
+	sort.Strings(values)
+	return values
+}
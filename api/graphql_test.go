@@ -0,0 +1,242 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/caffix/netmap"
+)
+
+func TestGraphQLHandler(t *testing.T) {
+	ctx := context.Background()
+	db := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer db.Close()
+
+	if err := db.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", "event"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := db.UpsertInfrastructure(ctx, 1234, "OWASP", "192.168.1.1", "192.168.1.0/24", "RIR", "event"); err != nil {
+		t.Fatalf("Failed to insert the infrastructure: %v", err)
+	}
+
+	if err := db.UpsertProperty(ctx, netmap.Node("www.owasp.org"), "user_tag", "in-scope"); err != nil {
+		t.Fatalf("Failed to tag the name: %v", err)
+	}
+
+	h, err := NewGraphQLHandler(db)
+	if err != nil {
+		t.Fatalf("Failed to create the handler: %v", err)
+	}
+
+	query := `{"query": "{ names(domain: \"owasp.org\") { name tags addresses { address netblock { cidr asn { number description } } } } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var resp struct {
+		Data struct {
+			Names []struct {
+				Name      string
+				Tags      []string
+				Addresses []struct {
+					Address  string
+					Netblock struct {
+						Cidr string
+						Asn  struct {
+							Number      int
+							Description string
+						}
+					}
+				}
+			}
+		}
+		Errors []interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Errors) > 0 {
+		t.Fatalf("The query failed: %v %s", err, rec.Body.String())
+	}
+
+	var found bool
+	for _, n := range resp.Data.Names {
+		if n.Name != "www.owasp.org" {
+			continue
+		}
+		found = true
+		if len(n.Addresses) != 1 || n.Addresses[0].Netblock.Asn.Number != 1234 || n.Addresses[0].Netblock.Cidr != "192.168.1.0/24" {
+			t.Errorf("The relationships of the name were not returned: %s", rec.Body.String())
+		}
+		if len(n.Tags) != 1 || n.Tags[0] != "in-scope" {
+			t.Errorf("The tags of the name were not returned: %s", rec.Body.String())
+		}
+	}
+	if !found {
+		t.Errorf("The name was not returned: %s", rec.Body.String())
+	}
+
+	mutation := `{"query": "mutation { names(domain: \"owasp.org\") { name } }"}`
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(mutation)))
+	if !strings.Contains(rec.Body.String(), "errors") {
+		t.Errorf("The mutation was accepted: %s", rec.Body.String())
+	}
+}
+
+func TestGraphQLCertificates(t *testing.T) {
+	ctx := context.Background()
+	db := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer db.Close()
+
+	if err := db.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", "event"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	cert, err := db.UpsertNode(ctx, "cert:abc", "certificate")
+	if err != nil {
+		t.Fatalf("Failed to insert the certificate: %v", err)
+	}
+	if err := db.UpsertProperty(ctx, cert, "serial", "abc"); err != nil {
+		t.Fatalf("Failed to insert the serial number: %v", err)
+	}
+	for _, id := range []string{"www.owasp.org", "192.168.1.1"} {
+		if err := db.UpsertEdge(ctx, &netmap.Edge{
+			Predicate: "certificate",
+			From:      netmap.Node(id),
+			To:        cert,
+		}); err != nil {
+			t.Fatalf("Failed to link the certificate: %v", err)
+		}
+	}
+
+	h, err := NewGraphQLHandler(db)
+	if err != nil {
+		t.Fatalf("Failed to create the handler: %v", err)
+	}
+
+	query := `{"query": "{ certificate(serial: \"00:AB:C\") { serial names { name } addresses { address certificates { serial } } } }"}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query)))
+
+	var resp struct {
+		Data struct {
+			Certificate struct {
+				Serial string
+				Names  []struct {
+					Name string
+				}
+				Addresses []struct {
+					Address      string
+					Certificates []struct {
+						Serial string
+					}
+				}
+			}
+		}
+		Errors []interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Errors) > 0 {
+		t.Fatalf("The query failed: %v %s", err, rec.Body.String())
+	}
+
+	c := resp.Data.Certificate
+	if c.Serial != "abc" || len(c.Names) != 1 || c.Names[0].Name != "www.owasp.org" {
+		t.Errorf("The certificate was not returned: %s", rec.Body.String())
+	}
+	if len(c.Addresses) != 1 || len(c.Addresses[0].Certificates) != 1 {
+		t.Errorf("The hosts sharing the certificate were not returned: %s", rec.Body.String())
+	}
+}
+
+func TestGraphQLServices(t *testing.T) {
+	ctx := context.Background()
+	db := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer db.Close()
+
+	fqdn, err := db.UpsertFQDN(ctx, "www.owasp.org", "DNS", "event")
+	if err != nil {
+		t.Fatalf("Failed to insert the name: %v", err)
+	}
+	svc, err := db.UpsertNode(ctx, "https://www.owasp.org:443", "http_service")
+	if err != nil {
+		t.Fatalf("Failed to insert the web service: %v", err)
+	}
+	if err := db.UpsertEdge(ctx, &netmap.Edge{Predicate: "http_service", From: fqdn, To: svc}); err != nil {
+		t.Fatalf("Failed to link the web service: %v", err)
+	}
+	for pred, val := range map[string]string{"status_code": "200", "server": "nginx", "technology": "PHP"} {
+		if err := db.UpsertProperty(ctx, svc, pred, val); err != nil {
+			t.Fatalf("Failed to insert the %s property: %v", pred, err)
+		}
+	}
+
+	h, err := NewGraphQLHandler(db)
+	if err != nil {
+		t.Fatalf("Failed to create the handler: %v", err)
+	}
+
+	query := `{"query": "{ name(name: \"www.owasp.org\") { services { url statusCode server technologies } } }"}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query)))
+
+	var resp struct {
+		Data struct {
+			Name struct {
+				Services []struct {
+					URL          string
+					StatusCode   int
+					Server       string
+					Technologies []string
+				}
+			}
+		}
+		Errors []interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Errors) > 0 {
+		t.Fatalf("The query failed: %v %s", err, rec.Body.String())
+	}
+
+	s := resp.Data.Name.Services
+	if len(s) != 1 || s[0].StatusCode != 200 || s[0].Server != "nginx" || len(s[0].Technologies) != 1 {
+		t.Errorf("The web service was not returned: %s", rec.Body.String())
+	}
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	db := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer db.Close()
+
+	h, err := NewGraphQLHandler(db)
+	if err != nil {
+		t.Fatalf("Failed to create the handler: %v", err)
+	}
+
+	// Each level follows the addresses of the names and then the names sharing the addresses
+	deep := "name"
+	for i := 0; i < graphQLMaxDepth; i++ {
+		deep = "addresses { names { " + deep + " } }"
+	}
+	query := `{"query": "{ names(domain: \"owasp.org\") { ` + deep + ` } }"}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query)))
+
+	var resp struct {
+		Errors []interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Errors) == 0 {
+		t.Errorf("The query exceeding the maximum depth was executed: %s", rec.Body.String())
+	}
+
+	long := strings.Repeat(" ", graphQLMaxQueryLength)
+	query = `{"query": "{` + long + `names(domain: \"owasp.org\") { name } }"}`
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("The query exceeding the maximum length returned status %d", rec.Code)
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"io"
+	"time"
+
+	amasspb "github.com/OWASP/Amass/v3/api/proto"
+	"github.com/OWASP/Amass/v3/requests"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// How often Watch checks the enumeration for new findings and state changes
+	grpcWatchInterval = time.Second
+	// The size reached by the chunks of the N-Quads file before they are sent by Graph
+	grpcGraphChunkSize = 64 * 1024
+)
+
+// NewGRPCServer returns the gRPC server providing the Enumerations service defined by api/proto/amass.proto
+// over the EnumManager. When the key is not empty, the calls must provide it in the authorization
+// metadata using the Bearer scheme, like the REST API.
+func NewGRPCServer(m *EnumManager, key string) *grpc.Server {
+	var opts []grpc.ServerOption
+	if key != "" {
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+				if err := checkGRPCKey(ctx, key); err != nil {
+					return nil, err
+				}
+				return h(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+				if err := checkGRPCKey(ss.Context(), key); err != nil {
+					return err
+				}
+				return h(srv, ss)
+			}),
+		)
+	}
+
+	s := grpc.NewServer(opts...)
+	amasspb.RegisterEnumerationsServer(s, &grpcEnumerations{m: m})
+	return s
+}
+
+// DialGRPC returns the connection with the gRPC API served at the address, which sends the key
+// with each call when it is not empty.
+func DialGRPC(addr, key string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{grpc.WithInsecure()}, opts...)
+	if key != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(grpcKey(key)))
+	}
+	return grpc.Dial(addr, opts...)
+}
+
+// grpcKey provides the API key in the authorization metadata of the calls.
+type grpcKey string
+
+// GetRequestMetadata implements the credentials.PerRPCCredentials interface.
+func (k grpcKey) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(k)}, nil
+}
+
+// RequireTransportSecurity implements the credentials.PerRPCCredentials interface.
+func (k grpcKey) RequireTransportSecurity() bool {
+	return false
+}
+
+func checkGRPCKey(ctx context.Context, key string) error {
+	expected := []byte("Bearer " + key)
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, v := range md.Get("authorization") {
+			if subtle.ConstantTimeCompare([]byte(v), expected) == 1 {
+				return nil
+			}
+		}
+	}
+	return status.Error(codes.Unauthenticated, "the API key is missing or not valid")
+}
+
+// grpcEnumerations implements the Enumerations service using the same EnumManager as the REST API.
+type grpcEnumerations struct {
+	amasspb.UnimplementedEnumerationsServer
+	m *EnumManager
+}
+
+func (s *grpcEnumerations) Start(ctx context.Context, req *amasspb.EnumRequest) (*amasspb.EnumStatus, error) {
+	st, err := s.m.Start(enumRequestFromPB(req))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return enumStatusToPB(st), nil
+}
+
+func (s *grpcEnumerations) Stop(ctx context.Context, id *amasspb.EnumID) (*amasspb.EnumStatus, error) {
+	if _, found := s.m.Status(id.GetId()); !found {
+		return nil, status.Errorf(codes.NotFound, "the enumeration %s was not found", id.GetId())
+	}
+
+	st, err := s.m.Stop(id.GetId())
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return enumStatusToPB(st), nil
+}
+
+func (s *grpcEnumerations) Status(ctx context.Context, id *amasspb.EnumID) (*amasspb.EnumStatus, error) {
+	st, found := s.m.Status(id.GetId())
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "the enumeration %s was not found", id.GetId())
+	}
+	return enumStatusToPB(st), nil
+}
+
+func (s *grpcEnumerations) List(ctx context.Context, req *amasspb.ListRequest) (*amasspb.EnumList, error) {
+	list := &amasspb.EnumList{}
+
+	for _, st := range s.m.List() {
+		list.Enumerations = append(list.Enumerations, enumStatusToPB(st))
+	}
+	return list, nil
+}
+
+// Watch sends the findings already written by the enumeration, followed by the findings and state
+// changes as they occur, until the enumeration ends or the client cancels the call.
+func (s *grpcEnumerations) Watch(id *amasspb.EnumID, stream amasspb.Enumerations_WatchServer) error {
+	t := time.NewTicker(grpcWatchInterval)
+	defer t.Stop()
+
+	var sent int
+	var state string
+	for {
+		st, found := s.m.Status(id.GetId())
+		if !found {
+			return status.Errorf(codes.NotFound, "the enumeration %s was not found", id.GetId())
+		}
+
+		if st.State != EnumQueued {
+			results, err := s.m.Results(id.GetId())
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			// The findings are appended to the output file, so only the new lines need to be sent
+			for ; sent < len(results); sent++ {
+				if err := stream.Send(&amasspb.EnumEvent{
+					Event: &amasspb.EnumEvent_Finding{Finding: findingToPB(results[sent])},
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		if st.State != state {
+			state = st.State
+			if err := stream.Send(&amasspb.EnumEvent{
+				Event: &amasspb.EnumEvent_Status{Status: enumStatusToPB(st)},
+			}); err != nil {
+				return err
+			}
+		}
+		if state == EnumFinished || state == EnumFailed || state == EnumStopped {
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Graph sends the N-Quads file written by the ended enumeration in chunks ending at a line boundary.
+func (s *grpcEnumerations) Graph(id *amasspb.EnumID, stream amasspb.Enumerations_GraphServer) error {
+	if _, found := s.m.Status(id.GetId()); !found {
+		return status.Errorf(codes.NotFound, "the enumeration %s was not found", id.GetId())
+	}
+
+	f, err := s.m.Graph(id.GetId())
+	if err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	defer f.Close()
+
+	var chunk []byte
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		chunk = append(chunk, line...)
+
+		if len(chunk) >= grpcGraphChunkSize || (err != nil && len(chunk) > 0) {
+			if serr := stream.Send(&amasspb.GraphChunk{Nquads: chunk}); serr != nil {
+				return serr
+			}
+			chunk = nil
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+}
+
+func enumRequestFromPB(req *amasspb.EnumRequest) *EnumRequest {
+	r := &EnumRequest{
+		Domains:     req.GetDomains(),
+		Active:      req.GetActive(),
+		Brute:       req.GetBrute(),
+		Alterations: req.GetAlterations(),
+		Passive:     req.GetPassive(),
+		Timeout:     int(req.GetTimeout()),
+		Profile:     req.GetProfile(),
+		CIDRs:       req.GetCidrs(),
+		BruteShard:  req.GetBruteShard(),
+	}
+
+	for _, asn := range req.GetAsns() {
+		r.ASNs = append(r.ASNs, int(asn))
+	}
+	return r
+}
+
+func enumRequestToPB(req *EnumRequest) *amasspb.EnumRequest {
+	if req == nil {
+		return nil
+	}
+
+	r := &amasspb.EnumRequest{
+		Domains:     req.Domains,
+		Active:      req.Active,
+		Brute:       req.Brute,
+		Alterations: req.Alterations,
+		Passive:     req.Passive,
+		Timeout:     int32(req.Timeout),
+		Profile:     req.Profile,
+		Cidrs:       req.CIDRs,
+		BruteShard:  req.BruteShard,
+	}
+
+	for _, asn := range req.ASNs {
+		r.Asns = append(r.Asns, int32(asn))
+	}
+	return r
+}
+
+var enumStatesPB = map[string]amasspb.EnumStatus_State{
+	EnumQueued:   amasspb.EnumStatus_QUEUED,
+	EnumRunning:  amasspb.EnumStatus_RUNNING,
+	EnumFinished: amasspb.EnumStatus_FINISHED,
+	EnumFailed:   amasspb.EnumStatus_FAILED,
+	EnumStopped:  amasspb.EnumStatus_STOPPED,
+}
+
+func enumStatusToPB(st *EnumStatus) *amasspb.EnumStatus {
+	return &amasspb.EnumStatus{
+		Id:       st.ID,
+		Request:  enumRequestToPB(st.Request),
+		State:    enumStatesPB[st.State],
+		Error:    st.Error,
+		Created:  st.Created,
+		Started:  st.Started,
+		Finished: st.Finished,
+		Names:    int32(st.Names),
+	}
+}
+
+func findingToPB(out *requests.Output) *amasspb.Finding {
+	f := &amasspb.Finding{
+		Name:       out.Name,
+		Domain:     out.Domain,
+		Tag:        out.Tag,
+		Sources:    out.Sources,
+		Confidence: int32(out.Confidence),
+		UserTags:   out.UserTags,
+		Notes:      out.Notes,
+	}
+
+	for _, a := range out.Addresses {
+		f.Addresses = append(f.Addresses, &amasspb.Address{
+			Ip:         a.Address.String(),
+			Cidr:       a.CIDRStr,
+			Asn:        int32(a.ASN),
+			Desc:       a.Description,
+			Confidence: int32(a.Confidence),
+		})
+	}
+	return f
+}
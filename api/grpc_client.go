@@ -0,0 +1,168 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	amasspb "github.com/OWASP/Amass/v3/api/proto"
+	"github.com/OWASP/Amass/v3/requests"
+	"google.golang.org/grpc"
+)
+
+// GRPCClient manages the enumerations of a remote Amass instance through the gRPC API served by 'amass api -grpc-addr'.
+type GRPCClient struct {
+	// The address of the instance, such as 10.0.0.2:9090
+	Addr   string
+	conn   *grpc.ClientConn
+	client amasspb.EnumerationsClient
+}
+
+// NewGRPCClient returns the GRPCClient of the gRPC API served at the address, which sends the key
+// with each call when it is not empty.
+func NewGRPCClient(addr, key string, opts ...grpc.DialOption) (*GRPCClient, error) {
+	conn, err := DialGRPC(addr, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCClient{
+		Addr:   addr,
+		conn:   conn,
+		client: amasspb.NewEnumerationsClient(conn),
+	}, nil
+}
+
+// Close releases the connection with the remote instance.
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// Start queues the enumeration on the remote instance and returns its status.
+func (c *GRPCClient) Start(ctx context.Context, req *EnumRequest) (*EnumStatus, error) {
+	st, err := c.client.Start(ctx, enumRequestToPB(req))
+	if err != nil {
+		return nil, err
+	}
+	return enumStatusFromPB(st), nil
+}
+
+// Status returns the status of the remote enumeration.
+func (c *GRPCClient) Status(ctx context.Context, id string) (*EnumStatus, error) {
+	st, err := c.client.Status(ctx, &amasspb.EnumID{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return enumStatusFromPB(st), nil
+}
+
+// Stop interrupts the remote enumeration and returns its status.
+func (c *GRPCClient) Stop(ctx context.Context, id string) (*EnumStatus, error) {
+	st, err := c.client.Stop(ctx, &amasspb.EnumID{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return enumStatusFromPB(st), nil
+}
+
+// Watch calls the function with each finding of the remote enumeration, starting from the first one,
+// and returns the status of the enumeration once it ends.
+func (c *GRPCClient) Watch(ctx context.Context, id string, f func(*requests.Output)) (*EnumStatus, error) {
+	stream, err := c.client.Watch(ctx, &amasspb.EnumID{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	var last *EnumStatus
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return last, err
+		}
+
+		if finding := event.GetFinding(); finding != nil {
+			f(findingFromPB(finding))
+		}
+		if st := event.GetStatus(); st != nil {
+			last = enumStatusFromPB(st)
+		}
+	}
+
+	if last == nil || !last.Terminal() {
+		return last, errors.New("the watch ended before the enumeration")
+	}
+	return last, nil
+}
+
+// Graph writes the graph data stored by the ended remote enumeration, using the N-Quads format.
+func (c *GRPCClient) Graph(ctx context.Context, id string, w io.Writer) error {
+	stream, err := c.client.Graph(ctx, &amasspb.EnumID{Id: id})
+	if err != nil {
+		return err
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(chunk.GetNquads()); err != nil {
+			return err
+		}
+	}
+}
+
+func enumStatusFromPB(st *amasspb.EnumStatus) *EnumStatus {
+	s := &EnumStatus{
+		ID:       st.GetId(),
+		Error:    st.GetError(),
+		Created:  st.GetCreated(),
+		Started:  st.GetStarted(),
+		Finished: st.GetFinished(),
+		Names:    int(st.GetNames()),
+	}
+
+	if req := st.GetRequest(); req != nil {
+		s.Request = enumRequestFromPB(req)
+	}
+	for state, pb := range enumStatesPB {
+		if pb == st.GetState() {
+			s.State = state
+			break
+		}
+	}
+	return s
+}
+
+func findingFromPB(f *amasspb.Finding) *requests.Output {
+	out := &requests.Output{
+		Name:       f.GetName(),
+		Domain:     f.GetDomain(),
+		Tag:        f.GetTag(),
+		Sources:    f.GetSources(),
+		Confidence: int(f.GetConfidence()),
+		UserTags:   f.GetUserTags(),
+		Notes:      f.GetNotes(),
+	}
+
+	for _, a := range f.GetAddresses() {
+		out.Addresses = append(out.Addresses, requests.AddressInfo{
+			Address:     net.ParseIP(a.GetIp()),
+			CIDRStr:     a.GetCidr(),
+			ASN:         int(a.GetAsn()),
+			Description: a.GetDesc(),
+			Confidence:  int(a.GetConfidence()),
+		})
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grpcclient")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	m := NewEnumManager(dir, func(ctx context.Context, req *EnumRequest, out string) error {
+		line := `{"name":"www.` + req.Domains[0] + `","domain":"` + req.Domains[0] + `","addresses":[{"ip":"192.168.1.1","cidr":"192.168.1.0/24","asn":64512,"desc":"Test"}]}` + "\n"
+		if err := ioutil.WriteFile(filepath.Join(out, ResultsFileName), []byte(line), 0644); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(out, GraphFileName), []byte(testGraphQuads), 0644)
+	})
+	defer m.Close()
+
+	lis := bufconn.Listen(1 << 20)
+	s := NewGRPCServer(m, "secret")
+	go func() { _ = s.Serve(lis) }()
+	defer s.Stop()
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.Dial()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	wrong, err := NewGRPCClient("bufnet", "wrong", dialer)
+	if err != nil {
+		t.Fatalf("Failed to connect with the gRPC server: %v", err)
+	}
+	defer wrong.Close()
+	if _, err := wrong.Start(ctx, &EnumRequest{Domains: []string{"owasp.org"}}); err == nil {
+		t.Errorf("The request with the wrong API key was accepted")
+	}
+
+	c, err := NewGRPCClient("bufnet", "secret", dialer)
+	if err != nil {
+		t.Fatalf("Failed to connect with the gRPC server: %v", err)
+	}
+	defer c.Close()
+
+	st, err := c.Start(ctx, &EnumRequest{Domains: []string{"owasp.org"}, Brute: true, BruteShard: "1/2", ASNs: []int{64512}})
+	if err != nil {
+		t.Fatalf("Failed to start the enumeration: %v", err)
+	}
+	if st.State != EnumQueued || st.Request.BruteShard != "1/2" || len(st.Request.ASNs) != 1 {
+		t.Errorf("The enumeration was not queued: %v", st)
+	}
+
+	var results []*requests.Output
+	st, err = c.Watch(ctx, st.ID, func(o *requests.Output) { results = append(results, o) })
+	if err != nil {
+		t.Fatalf("The watch failed: %v", err)
+	}
+	if st.State != EnumFinished {
+		t.Errorf("The watch did not return the finished state: %v", st)
+	}
+	if len(results) != 1 || results[0].Name != "www.owasp.org" || len(results[0].Addresses) != 1 ||
+		!results[0].Addresses[0].Address.Equal(net.ParseIP("192.168.1.1")) || results[0].Addresses[0].ASN != 64512 {
+		t.Errorf("The findings were not streamed: %v", results)
+	}
+
+	var graph bytes.Buffer
+	if err := c.Graph(ctx, st.ID, &graph); err != nil || graph.String() != testGraphQuads {
+		t.Errorf("The graph data was not streamed: %q: %v", graph.String(), err)
+	}
+	if _, err := c.Stop(ctx, st.ID); err == nil {
+		t.Errorf("The finished enumeration was stopped")
+	}
+	if _, err := c.Status(ctx, "missing"); err == nil {
+		t.Errorf("The status of the missing enumeration was returned")
+	}
+}
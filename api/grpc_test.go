@@ -0,0 +1,166 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	amasspb "github.com/OWASP/Amass/v3/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testGraphQuads = "<www.owasp.org> <type> \"fqdn\" <fqdn> .\n<www.owasp.org> <a_record> <192.168.1.1> .\n"
+
+func TestGRPCServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grpc")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	release := make(chan struct{})
+	// The enumerations write a finding and run until they are released or stopped
+	m := NewEnumManager(dir, func(ctx context.Context, req *EnumRequest, out string) error {
+		line := `{"name":"www.` + req.Domains[0] + `","domain":"` + req.Domains[0] + `","addresses":[{"ip":"192.168.1.1","cidr":"192.168.1.0/24","asn":64512,"desc":"Test"}]}` + "\n"
+		if err := ioutil.WriteFile(filepath.Join(out, ResultsFileName), []byte(line), 0644); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-release:
+		}
+		// The graph data is written once the enumeration ends
+		return ioutil.WriteFile(filepath.Join(out, GraphFileName), []byte(testGraphQuads), 0644)
+	})
+	defer m.Close()
+
+	lis := bufconn.Listen(1 << 20)
+	s := NewGRPCServer(m, "secret")
+	go func() { _ = s.Serve(lis) }()
+	defer s.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	if err != nil {
+		t.Fatalf("Failed to connect with the gRPC server: %v", err)
+	}
+	defer conn.Close()
+	client := amasspb.NewEnumerationsClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.List(ctx, &amasspb.ListRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("The call without the API key returned %v", err)
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret")
+
+	keyed, err := DialGRPC("bufnet", "secret", grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	if err != nil {
+		t.Fatalf("Failed to connect with the gRPC server: %v", err)
+	}
+	defer keyed.Close()
+	if _, err := amasspb.NewEnumerationsClient(keyed).List(context.Background(), &amasspb.ListRequest{}); err != nil {
+		t.Errorf("The call providing the API key through the connection failed: %v", err)
+	}
+
+	if _, err := client.Start(ctx, &amasspb.EnumRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("The enumeration without domains returned %v", err)
+	}
+	first, err := client.Start(ctx, &amasspb.EnumRequest{Domains: []string{"OWASP.org"}, Brute: true})
+	if err != nil {
+		t.Fatalf("Failed to start the enumeration: %v", err)
+	}
+	if first.GetState() != amasspb.EnumStatus_QUEUED || first.GetRequest().GetDomains()[0] != "owasp.org" {
+		t.Errorf("The enumeration was not queued: %v", first)
+	}
+	second, err := client.Start(ctx, &amasspb.EnumRequest{Domains: []string{"example.com"}})
+	if err != nil {
+		t.Fatalf("Failed to start the enumeration: %v", err)
+	}
+	if _, err := client.Stop(ctx, &amasspb.EnumID{Id: second.GetId()}); err != nil {
+		t.Errorf("Failed to stop the queued enumeration: %v", err)
+	}
+	if _, err := client.Status(ctx, &amasspb.EnumID{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("The status of the missing enumeration returned %v", err)
+	}
+	if list, err := client.List(ctx, &amasspb.ListRequest{}); err != nil || len(list.GetEnumerations()) != 2 {
+		t.Errorf("The enumerations were not listed: %v", err)
+	}
+
+	stream, err := client.Watch(ctx, &amasspb.EnumID{Id: first.GetId()})
+	if err != nil {
+		t.Fatalf("Failed to watch the enumeration: %v", err)
+	}
+
+	var finding *amasspb.Finding
+	var states []amasspb.EnumStatus_State
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("The watch failed: %v", err)
+		}
+
+		if f := event.GetFinding(); f != nil {
+			// The enumeration finishes once the finding has been streamed
+			if finding == nil {
+				close(release)
+			}
+			finding = f
+		}
+		if st := event.GetStatus(); st != nil {
+			states = append(states, st.GetState())
+		}
+	}
+	if finding == nil || finding.GetName() != "www.owasp.org" || len(finding.GetAddresses()) != 1 || finding.GetAddresses()[0].GetAsn() != 64512 {
+		t.Errorf("The finding was not streamed: %v", finding)
+	}
+	if n := len(states); n == 0 || states[n-1] != amasspb.EnumStatus_FINISHED {
+		t.Errorf("The watch did not end with the finished state: %v", states)
+	}
+
+	chunks, err := client.Graph(ctx, &amasspb.EnumID{Id: first.GetId()})
+	if err != nil {
+		t.Fatalf("Failed to obtain the graph data: %v", err)
+	}
+	var graph []byte
+	for {
+		chunk, err := chunks.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Failed to receive the graph data: %v", err)
+		}
+		graph = append(graph, chunk.GetNquads()...)
+	}
+	if string(graph) != testGraphQuads {
+		t.Errorf("The graph data was not streamed: %q", graph)
+	}
+
+	// The queued enumeration was stopped before it could write the graph data
+	chunks, err = client.Graph(ctx, &amasspb.EnumID{Id: second.GetId()})
+	if err == nil {
+		_, err = chunks.Recv()
+	}
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("The graph data of the stopped enumeration returned %v", err)
+	}
+}
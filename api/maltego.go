@@ -0,0 +1,268 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+)
+
+// The longest request body accepted from the Maltego client.
+const maltegoMaxRequestSize = 1 << 20
+
+type maltegoEntity struct {
+	Type   string `xml:"Type,attr"`
+	Value  string `xml:"Value"`
+	Weight int    `xml:"Weight"`
+}
+
+type maltegoLimits struct {
+	SoftLimit int `xml:"SoftLimit,attr"`
+	HardLimit int `xml:"HardLimit,attr"`
+}
+
+type maltegoRequest struct {
+	XMLName  xml.Name        `xml:"MaltegoMessage"`
+	Entities []maltegoEntity `xml:"MaltegoTransformRequestMessage>Entities>Entity"`
+	Limits   maltegoLimits   `xml:"MaltegoTransformRequestMessage>Limits"`
+}
+
+type maltegoUIMessage struct {
+	Type    string `xml:"MessageType,attr"`
+	Message string `xml:",chardata"`
+}
+
+type maltegoResponse struct {
+	XMLName    xml.Name           `xml:"MaltegoMessage"`
+	Entities   []maltegoEntity    `xml:"MaltegoTransformResponseMessage>Entities>Entity"`
+	UIMessages []maltegoUIMessage `xml:"MaltegoTransformResponseMessage>UIMessages>UIMessage"`
+}
+
+type maltegoException struct {
+	XMLName    xml.Name `xml:"MaltegoMessage"`
+	Exceptions []string `xml:"MaltegoTransformExceptionMessage>Exceptions>Exception"`
+}
+
+// The transforms return the entities related to the input entity in the graph database.
+type maltegoTransform func(ctx context.Context, db *netmap.Graph, value string) []maltegoEntity
+
+// The transforms served by the Maltego handler, identified by the path of the requests.
+var maltegoTransforms = map[string]maltegoTransform{
+	"domaintodnsnames":   domainToDNSNames,
+	"dnsnametoaddresses": dnsNameToAddresses,
+	"addresstodnsnames":  addressToDNSNames,
+	"addresstonetblock":  addressToNetblock,
+	"netblocktoas":       netblockToAS,
+	"astonetblocks":      asToNetblocks,
+}
+
+// NewMaltegoHandler returns the handler that serves the graph database as Maltego transforms.
+// The Maltego client runs each transform by posting the input entity to /run/<transform name>.
+func NewMaltegoHandler(db *netmap.Graph) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/run/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/run/")
+
+		transform, found := maltegoTransforms[strings.ToLower(name)]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "The transforms must be requested using POST", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req maltegoRequest
+		if err := xml.NewDecoder(io.LimitReader(r.Body, maltegoMaxRequestSize)).Decode(&req); err != nil {
+			writeMaltegoXML(w, &maltegoException{Exceptions: []string{"The request is not a valid Maltego message"}})
+			return
+		}
+		if len(req.Entities) == 0 {
+			writeMaltegoXML(w, &maltegoException{Exceptions: []string{"The request did not provide an entity"}})
+			return
+		}
+
+		resp := &maltegoResponse{Entities: []maltegoEntity{}}
+		for _, e := range req.Entities {
+			resp.Entities = append(resp.Entities, transform(r.Context(), db, strings.TrimSpace(e.Value))...)
+		}
+		if limit := req.Limits.SoftLimit; limit > 0 && len(resp.Entities) > limit {
+			resp.UIMessages = append(resp.UIMessages, maltegoUIMessage{
+				Type:    "PartialError",
+				Message: fmt.Sprintf("Only %d of the %d entities were returned", limit, len(resp.Entities)),
+			})
+			resp.Entities = resp.Entities[:limit]
+		}
+		writeMaltegoXML(w, resp)
+	})
+
+	// The root lists the transforms to ease the configuration of the Maltego client
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var names []string
+		for name := range maltegoTransforms {
+			names = append(names, "/run/"+name)
+		}
+		sort.Strings(names)
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, strings.Join(names, "\n"))
+	})
+	return mux
+}
+
+func writeMaltegoXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func domainToDNSNames(ctx context.Context, db *netmap.Graph, value string) []maltegoEntity {
+	domain := strings.ToLower(value)
+	names := stringset.New()
+	defer names.Close()
+
+	for _, event := range db.EventsInScope(ctx, domain) {
+		for _, name := range db.EventFQDNs(ctx, event) {
+			if name != domain && strings.HasSuffix(name, "."+domain) {
+				names.Insert(name)
+			}
+		}
+	}
+
+	list := names.Slice()
+	sort.Strings(list)
+
+	var entities []maltegoEntity
+	for _, name := range list {
+		entities = append(entities, maltegoEntity{Type: "maltego.DNSName", Value: name, Weight: 100})
+	}
+	return entities
+}
+
+func dnsNameToAddresses(ctx context.Context, db *netmap.Graph, value string) []maltegoEntity {
+	name := strings.ToLower(value)
+	// Follow the CNAME records to the names holding the addresses
+	for i := 0; i < 10; i++ {
+		cnames := outNodes(ctx, db, name, "cname_record")
+		if len(cnames) == 0 {
+			break
+		}
+		name = cnames[0]
+	}
+
+	addrs := outNodes(ctx, db, name, "a_record", "aaaa_record")
+	sort.Strings(addrs)
+
+	var entities []maltegoEntity
+	for _, addr := range addrs {
+		entities = append(entities, maltegoAddressEntity(addr))
+	}
+	return entities
+}
+
+func addressToDNSNames(ctx context.Context, db *netmap.Graph, value string) []maltegoEntity {
+	names := inNodes(ctx, db, value, "a_record", "aaaa_record")
+	sort.Strings(names)
+
+	var entities []maltegoEntity
+	for _, name := range names {
+		entities = append(entities, maltegoEntity{Type: "maltego.DNSName", Value: name, Weight: 100})
+	}
+	return entities
+}
+
+func addressToNetblock(ctx context.Context, db *netmap.Graph, value string) []maltegoEntity {
+	var entities []maltegoEntity
+
+	for _, cidr := range inNodes(ctx, db, value, "contains") {
+		if r := cidrToMaltegoRange(cidr); r != "" {
+			entities = append(entities, maltegoEntity{Type: "maltego.Netblock", Value: r, Weight: 100})
+		}
+	}
+	return entities
+}
+
+func netblockToAS(ctx context.Context, db *netmap.Graph, value string) []maltegoEntity {
+	var entities []maltegoEntity
+
+	for _, as := range inNodes(ctx, db, maltegoRangeToCIDR(value), "prefix") {
+		if _, err := strconv.Atoi(as); err == nil {
+			entities = append(entities, maltegoEntity{Type: "maltego.AS", Value: as, Weight: 100})
+		}
+	}
+	return entities
+}
+
+func asToNetblocks(ctx context.Context, db *netmap.Graph, value string) []maltegoEntity {
+	asn, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(value), "AS"))
+	if err != nil {
+		return nil
+	}
+
+	prefixes := db.ReadASPrefixes(ctx, asn)
+	sort.Strings(prefixes)
+
+	var entities []maltegoEntity
+	for _, cidr := range prefixes {
+		if r := cidrToMaltegoRange(cidr); r != "" {
+			entities = append(entities, maltegoEntity{Type: "maltego.Netblock", Value: r, Weight: 100})
+		}
+	}
+	return entities
+}
+
+func maltegoAddressEntity(addr string) maltegoEntity {
+	etype := "maltego.IPv4Address"
+	if ip := net.ParseIP(addr); ip != nil && ip.To4() == nil {
+		etype = "maltego.IPv6Address"
+	}
+	return maltegoEntity{Type: etype, Value: addr, Weight: 100}
+}
+
+// Maltego represents the netblocks as address ranges, such as 192.168.1.0-192.168.1.255.
+func cidrToMaltegoRange(cidr string) string {
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return ""
+	}
+
+	first, last := amassnet.FirstLast(ipnet)
+	return first.String() + "-" + last.String()
+}
+
+// Returns the netblock in CIDR notation, accepting both the Maltego address ranges and CIDRs.
+func maltegoRangeToCIDR(value string) string {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 2 {
+		return value
+	}
+
+	first := net.ParseIP(strings.TrimSpace(parts[0]))
+	last := net.ParseIP(strings.TrimSpace(parts[1]))
+	if first == nil || last == nil {
+		return value
+	}
+	return amassnet.Range2CIDR(first, last).String()
+}
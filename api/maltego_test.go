@@ -0,0 +1,70 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/caffix/netmap"
+)
+
+func TestMaltegoHandler(t *testing.T) {
+	ctx := context.Background()
+	db := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer db.Close()
+
+	if err := db.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", "event"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := db.UpsertInfrastructure(ctx, 1234, "OWASP", "192.168.1.1", "192.168.1.0/24", "RIR", "event"); err != nil {
+		t.Fatalf("Failed to insert the infrastructure: %v", err)
+	}
+
+	h := NewMaltegoHandler(db)
+	run := func(transform, etype, value string) []maltegoEntity {
+		body := `<MaltegoMessage><MaltegoTransformRequestMessage><Entities><Entity Type="` + etype +
+			`"><Value>` + value + `</Value><Weight>100</Weight></Entity></Entities>` +
+			`<Limits SoftLimit="12" HardLimit="12"/></MaltegoTransformRequestMessage></MaltegoMessage>`
+
+		req := httptest.NewRequest(http.MethodPost, "/run/"+transform, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		var resp maltegoResponse
+		if err := xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("The %s transform returned an invalid response: %v %s", transform, err, rec.Body.String())
+		}
+		return resp.Entities
+	}
+
+	tests := []struct {
+		transform, etype, value string
+		expectedType, expected  string
+	}{
+		{"dnsnametoaddresses", "maltego.DNSName", "www.owasp.org", "maltego.IPv4Address", "192.168.1.1"},
+		{"addresstodnsnames", "maltego.IPv4Address", "192.168.1.1", "maltego.DNSName", "www.owasp.org"},
+		{"addresstonetblock", "maltego.IPv4Address", "192.168.1.1", "maltego.Netblock", "192.168.1.0-192.168.1.255"},
+		{"netblocktoas", "maltego.Netblock", "192.168.1.0-192.168.1.255", "maltego.AS", "1234"},
+		{"astonetblocks", "maltego.AS", "1234", "maltego.Netblock", "192.168.1.0-192.168.1.255"},
+	}
+
+	for _, test := range tests {
+		entities := run(test.transform, test.etype, test.value)
+		if len(entities) != 1 || entities[0].Type != test.expectedType || entities[0].Value != test.expected {
+			t.Errorf("The %s transform returned %v", test.transform, entities)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/run/bogus", strings.NewReader(""))
+	rec := httptest.NewRecorder()
+	if h.ServeHTTP(rec, req); rec.Code != http.StatusNotFound {
+		t.Errorf("An unknown transform returned the status code %d", rec.Code)
+	}
+}
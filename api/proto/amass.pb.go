@@ -0,0 +1,1042 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        (unknown)
+// source: amass.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EnumStatus_State int32
+
+const (
+	EnumStatus_STATE_UNSPECIFIED EnumStatus_State = 0
+	EnumStatus_QUEUED            EnumStatus_State = 1
+	EnumStatus_RUNNING           EnumStatus_State = 2
+	EnumStatus_FINISHED          EnumStatus_State = 3
+	EnumStatus_FAILED            EnumStatus_State = 4
+	EnumStatus_STOPPED           EnumStatus_State = 5
+)
+
+// Enum value maps for EnumStatus_State.
+var (
+	EnumStatus_State_name = map[int32]string{
+		0: "STATE_UNSPECIFIED",
+		1: "QUEUED",
+		2: "RUNNING",
+		3: "FINISHED",
+		4: "FAILED",
+		5: "STOPPED",
+	}
+	EnumStatus_State_value = map[string]int32{
+		"STATE_UNSPECIFIED": 0,
+		"QUEUED":            1,
+		"RUNNING":           2,
+		"FINISHED":          3,
+		"FAILED":            4,
+		"STOPPED":           5,
+	}
+)
+
+func (x EnumStatus_State) Enum() *EnumStatus_State {
+	p := new(EnumStatus_State)
+	*p = x
+	return p
+}
+
+func (x EnumStatus_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EnumStatus_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_amass_proto_enumTypes[0].Descriptor()
+}
+
+func (EnumStatus_State) Type() protoreflect.EnumType {
+	return &file_amass_proto_enumTypes[0]
+}
+
+func (x EnumStatus_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EnumStatus_State.Descriptor instead.
+func (EnumStatus_State) EnumDescriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{4, 0}
+}
+
+type EnumRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Domains     []string `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	Active      bool     `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Brute       bool     `protobuf:"varint,3,opt,name=brute,proto3" json:"brute,omitempty"`
+	Alterations bool     `protobuf:"varint,4,opt,name=alterations,proto3" json:"alterations,omitempty"`
+	Passive     bool     `protobuf:"varint,5,opt,name=passive,proto3" json:"passive,omitempty"`
+	Timeout     int32    `protobuf:"varint,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Profile     string   `protobuf:"bytes,7,opt,name=profile,proto3" json:"profile,omitempty"`
+	Cidrs       []string `protobuf:"bytes,8,rep,name=cidrs,proto3" json:"cidrs,omitempty"`
+	Asns        []int32  `protobuf:"varint,9,rep,packed,name=asns,proto3" json:"asns,omitempty"`
+	BruteShard  string   `protobuf:"bytes,10,opt,name=brute_shard,json=bruteShard,proto3" json:"brute_shard,omitempty"`
+}
+
+func (x *EnumRequest) Reset() {
+	*x = EnumRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_amass_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumRequest) ProtoMessage() {}
+
+func (x *EnumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_amass_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumRequest.ProtoReflect.Descriptor instead.
+func (*EnumRequest) Descriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EnumRequest) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *EnumRequest) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *EnumRequest) GetBrute() bool {
+	if x != nil {
+		return x.Brute
+	}
+	return false
+}
+
+func (x *EnumRequest) GetAlterations() bool {
+	if x != nil {
+		return x.Alterations
+	}
+	return false
+}
+
+func (x *EnumRequest) GetPassive() bool {
+	if x != nil {
+		return x.Passive
+	}
+	return false
+}
+
+func (x *EnumRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *EnumRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *EnumRequest) GetCidrs() []string {
+	if x != nil {
+		return x.Cidrs
+	}
+	return nil
+}
+
+func (x *EnumRequest) GetAsns() []int32 {
+	if x != nil {
+		return x.Asns
+	}
+	return nil
+}
+
+func (x *EnumRequest) GetBruteShard() string {
+	if x != nil {
+		return x.BruteShard
+	}
+	return ""
+}
+
+type EnumID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *EnumID) Reset() {
+	*x = EnumID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_amass_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnumID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumID) ProtoMessage() {}
+
+func (x *EnumID) ProtoReflect() protoreflect.Message {
+	mi := &file_amass_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumID.ProtoReflect.Descriptor instead.
+func (*EnumID) Descriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EnumID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_amass_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_amass_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{2}
+}
+
+type EnumList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enumerations []*EnumStatus `protobuf:"bytes,1,rep,name=enumerations,proto3" json:"enumerations,omitempty"`
+}
+
+func (x *EnumList) Reset() {
+	*x = EnumList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_amass_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnumList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumList) ProtoMessage() {}
+
+func (x *EnumList) ProtoReflect() protoreflect.Message {
+	mi := &file_amass_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumList.ProtoReflect.Descriptor instead.
+func (*EnumList) Descriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EnumList) GetEnumerations() []*EnumStatus {
+	if x != nil {
+		return x.Enumerations
+	}
+	return nil
+}
+
+type EnumStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Request  *EnumRequest     `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	State    EnumStatus_State `protobuf:"varint,3,opt,name=state,proto3,enum=amass.v1.EnumStatus_State" json:"state,omitempty"`
+	Error    string           `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Created  string           `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"`
+	Started  string           `protobuf:"bytes,6,opt,name=started,proto3" json:"started,omitempty"`
+	Finished string           `protobuf:"bytes,7,opt,name=finished,proto3" json:"finished,omitempty"`
+	Names    int32            `protobuf:"varint,8,opt,name=names,proto3" json:"names,omitempty"`
+}
+
+func (x *EnumStatus) Reset() {
+	*x = EnumStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_amass_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnumStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumStatus) ProtoMessage() {}
+
+func (x *EnumStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_amass_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumStatus.ProtoReflect.Descriptor instead.
+func (*EnumStatus) Descriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EnumStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EnumStatus) GetRequest() *EnumRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *EnumStatus) GetState() EnumStatus_State {
+	if x != nil {
+		return x.State
+	}
+	return EnumStatus_STATE_UNSPECIFIED
+}
+
+func (x *EnumStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *EnumStatus) GetCreated() string {
+	if x != nil {
+		return x.Created
+	}
+	return ""
+}
+
+func (x *EnumStatus) GetStarted() string {
+	if x != nil {
+		return x.Started
+	}
+	return ""
+}
+
+func (x *EnumStatus) GetFinished() string {
+	if x != nil {
+		return x.Finished
+	}
+	return ""
+}
+
+func (x *EnumStatus) GetNames() int32 {
+	if x != nil {
+		return x.Names
+	}
+	return 0
+}
+
+type EnumEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*EnumEvent_Finding
+	//	*EnumEvent_Status
+	Event isEnumEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *EnumEvent) Reset() {
+	*x = EnumEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_amass_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnumEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumEvent) ProtoMessage() {}
+
+func (x *EnumEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_amass_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumEvent.ProtoReflect.Descriptor instead.
+func (*EnumEvent) Descriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{5}
+}
+
+func (m *EnumEvent) GetEvent() isEnumEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *EnumEvent) GetFinding() *Finding {
+	if x, ok := x.GetEvent().(*EnumEvent_Finding); ok {
+		return x.Finding
+	}
+	return nil
+}
+
+func (x *EnumEvent) GetStatus() *EnumStatus {
+	if x, ok := x.GetEvent().(*EnumEvent_Status); ok {
+		return x.Status
+	}
+	return nil
+}
+
+type isEnumEvent_Event interface {
+	isEnumEvent_Event()
+}
+
+type EnumEvent_Finding struct {
+	Finding *Finding `protobuf:"bytes,1,opt,name=finding,proto3,oneof"`
+}
+
+type EnumEvent_Status struct {
+	Status *EnumStatus `protobuf:"bytes,2,opt,name=status,proto3,oneof"`
+}
+
+func (*EnumEvent_Finding) isEnumEvent_Event() {}
+
+func (*EnumEvent_Status) isEnumEvent_Event() {}
+
+type Finding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name       string     `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Domain     string     `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	Addresses  []*Address `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Tag        string     `protobuf:"bytes,4,opt,name=tag,proto3" json:"tag,omitempty"`
+	Sources    []string   `protobuf:"bytes,5,rep,name=sources,proto3" json:"sources,omitempty"`
+	Confidence int32      `protobuf:"varint,6,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	UserTags   []string   `protobuf:"bytes,7,rep,name=user_tags,json=userTags,proto3" json:"user_tags,omitempty"`
+	Notes      []string   `protobuf:"bytes,8,rep,name=notes,proto3" json:"notes,omitempty"`
+}
+
+func (x *Finding) Reset() {
+	*x = Finding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_amass_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Finding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Finding) ProtoMessage() {}
+
+func (x *Finding) ProtoReflect() protoreflect.Message {
+	mi := &file_amass_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Finding.ProtoReflect.Descriptor instead.
+func (*Finding) Descriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Finding) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Finding) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Finding) GetAddresses() []*Address {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Finding) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *Finding) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *Finding) GetConfidence() int32 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *Finding) GetUserTags() []string {
+	if x != nil {
+		return x.UserTags
+	}
+	return nil
+}
+
+func (x *Finding) GetNotes() []string {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+type Address struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip         string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Cidr       string `protobuf:"bytes,2,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Asn        int32  `protobuf:"varint,3,opt,name=asn,proto3" json:"asn,omitempty"`
+	Desc       string `protobuf:"bytes,4,opt,name=desc,proto3" json:"desc,omitempty"`
+	Confidence int32  `protobuf:"varint,5,opt,name=confidence,proto3" json:"confidence,omitempty"`
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_amass_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_amass_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Address) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Address) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *Address) GetAsn() int32 {
+	if x != nil {
+		return x.Asn
+	}
+	return 0
+}
+
+func (x *Address) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *Address) GetConfidence() int32 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+type GraphChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nquads []byte `protobuf:"bytes,1,opt,name=nquads,proto3" json:"nquads,omitempty"`
+}
+
+func (x *GraphChunk) Reset() {
+	*x = GraphChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_amass_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphChunk) ProtoMessage() {}
+
+func (x *GraphChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_amass_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphChunk.ProtoReflect.Descriptor instead.
+func (*GraphChunk) Descriptor() ([]byte, []int) {
+	return file_amass_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GraphChunk) GetNquads() []byte {
+	if x != nil {
+		return x.Nquads
+	}
+	return nil
+}
+
+var File_amass_proto protoreflect.FileDescriptor
+
+var file_amass_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x61,
+	0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x22, 0x90, 0x02, 0x0a, 0x0b, 0x45, 0x6e, 0x75, 0x6d,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x72, 0x75,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x62, 0x72, 0x75, 0x74, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x61, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x61, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05,
+	0x63, 0x69, 0x64, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x73, 0x6e, 0x73, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x05, 0x52, 0x04, 0x61, 0x73, 0x6e, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x72, 0x75,
+	0x74, 0x65, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x62, 0x72, 0x75, 0x74, 0x65, 0x53, 0x68, 0x61, 0x72, 0x64, 0x22, 0x18, 0x0a, 0x06, 0x45, 0x6e,
+	0x75, 0x6d, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x22, 0x0d, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x44, 0x0a, 0x08, 0x45, 0x6e, 0x75, 0x6d, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x38, 0x0a, 0x0c, 0x65, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0c, 0x65, 0x6e, 0x75,
+	0x6d, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xdb, 0x02, 0x0a, 0x0a, 0x45, 0x6e,
+	0x75, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2f, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61, 0x6d, 0x61, 0x73,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x22, 0x5e, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x15, 0x0a, 0x11, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x51, 0x55, 0x45, 0x55, 0x45,
+	0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x02,
+	0x12, 0x0c, 0x0a, 0x08, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0a,
+	0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x54,
+	0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x05, 0x22, 0x73, 0x0a, 0x09, 0x45, 0x6e, 0x75, 0x6d, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x46, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x07, 0x66, 0x69, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x12, 0x2e, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45,
+	0x6e, 0x75, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0xe5, 0x01, 0x0a,
+	0x07, 0x46, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x12, 0x2f, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x07,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x54, 0x61, 0x67, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e,
+	0x6f, 0x74, 0x65, 0x73, 0x22, 0x73, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12,
+	0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63,
+	0x69, 0x64, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x73, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x03, 0x61, 0x73, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x24, 0x0a, 0x0a, 0x47, 0x72, 0x61,
+	0x70, 0x68, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x71, 0x75, 0x61, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x71, 0x75, 0x61, 0x64, 0x73, 0x32,
+	0xbe, 0x02, 0x0a, 0x0c, 0x45, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x34, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x15, 0x2e, 0x61, 0x6d, 0x61, 0x73,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x14, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2e, 0x0a, 0x04, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x10,
+	0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x49, 0x44,
+	0x1a, 0x14, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x30, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x10, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d,
+	0x49, 0x44, 0x1a, 0x14, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e,
+	0x75, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x31, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x15, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x05, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x10, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x6e, 0x75, 0x6d, 0x49, 0x44, 0x1a, 0x13, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x31, 0x0a,
+	0x05, 0x47, 0x72, 0x61, 0x70, 0x68, 0x12, 0x10, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x49, 0x44, 0x1a, 0x14, 0x2e, 0x61, 0x6d, 0x61, 0x73, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30, 0x01,
+	0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4f,
+	0x57, 0x41, 0x53, 0x50, 0x2f, 0x41, 0x6d, 0x61, 0x73, 0x73, 0x2f, 0x76, 0x33, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_amass_proto_rawDescOnce sync.Once
+	file_amass_proto_rawDescData = file_amass_proto_rawDesc
+)
+
+func file_amass_proto_rawDescGZIP() []byte {
+	file_amass_proto_rawDescOnce.Do(func() {
+		file_amass_proto_rawDescData = protoimpl.X.CompressGZIP(file_amass_proto_rawDescData)
+	})
+	return file_amass_proto_rawDescData
+}
+
+var file_amass_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_amass_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_amass_proto_goTypes = []interface{}{
+	(EnumStatus_State)(0), // 0: amass.v1.EnumStatus.State
+	(*EnumRequest)(nil),   // 1: amass.v1.EnumRequest
+	(*EnumID)(nil),        // 2: amass.v1.EnumID
+	(*ListRequest)(nil),   // 3: amass.v1.ListRequest
+	(*EnumList)(nil),      // 4: amass.v1.EnumList
+	(*EnumStatus)(nil),    // 5: amass.v1.EnumStatus
+	(*EnumEvent)(nil),     // 6: amass.v1.EnumEvent
+	(*Finding)(nil),       // 7: amass.v1.Finding
+	(*Address)(nil),       // 8: amass.v1.Address
+	(*GraphChunk)(nil),    // 9: amass.v1.GraphChunk
+}
+var file_amass_proto_depIdxs = []int32{
+	5,  // 0: amass.v1.EnumList.enumerations:type_name -> amass.v1.EnumStatus
+	1,  // 1: amass.v1.EnumStatus.request:type_name -> amass.v1.EnumRequest
+	0,  // 2: amass.v1.EnumStatus.state:type_name -> amass.v1.EnumStatus.State
+	7,  // 3: amass.v1.EnumEvent.finding:type_name -> amass.v1.Finding
+	5,  // 4: amass.v1.EnumEvent.status:type_name -> amass.v1.EnumStatus
+	8,  // 5: amass.v1.Finding.addresses:type_name -> amass.v1.Address
+	1,  // 6: amass.v1.Enumerations.Start:input_type -> amass.v1.EnumRequest
+	2,  // 7: amass.v1.Enumerations.Stop:input_type -> amass.v1.EnumID
+	2,  // 8: amass.v1.Enumerations.Status:input_type -> amass.v1.EnumID
+	3,  // 9: amass.v1.Enumerations.List:input_type -> amass.v1.ListRequest
+	2,  // 10: amass.v1.Enumerations.Watch:input_type -> amass.v1.EnumID
+	2,  // 11: amass.v1.Enumerations.Graph:input_type -> amass.v1.EnumID
+	5,  // 12: amass.v1.Enumerations.Start:output_type -> amass.v1.EnumStatus
+	5,  // 13: amass.v1.Enumerations.Stop:output_type -> amass.v1.EnumStatus
+	5,  // 14: amass.v1.Enumerations.Status:output_type -> amass.v1.EnumStatus
+	4,  // 15: amass.v1.Enumerations.List:output_type -> amass.v1.EnumList
+	6,  // 16: amass.v1.Enumerations.Watch:output_type -> amass.v1.EnumEvent
+	9,  // 17: amass.v1.Enumerations.Graph:output_type -> amass.v1.GraphChunk
+	12, // [12:18] is the sub-list for method output_type
+	6,  // [6:12] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_amass_proto_init() }
+func file_amass_proto_init() {
+	if File_amass_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_amass_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnumRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_amass_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnumID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_amass_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_amass_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnumList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_amass_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnumStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_amass_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnumEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_amass_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Finding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_amass_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Address); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_amass_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GraphChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_amass_proto_msgTypes[5].OneofWrappers = []interface{}{
+		(*EnumEvent_Finding)(nil),
+		(*EnumEvent_Status)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_amass_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_amass_proto_goTypes,
+		DependencyIndexes: file_amass_proto_depIdxs,
+		EnumInfos:         file_amass_proto_enumTypes,
+		MessageInfos:      file_amass_proto_msgTypes,
+	}.Build()
+	File_amass_proto = out.File
+	file_amass_proto_rawDesc = nil
+	file_amass_proto_goTypes = nil
+	file_amass_proto_depIdxs = nil
+}
@@ -0,0 +1,340 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: amass.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// EnumerationsClient is the client API for Enumerations service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EnumerationsClient interface {
+	Start(ctx context.Context, in *EnumRequest, opts ...grpc.CallOption) (*EnumStatus, error)
+	Stop(ctx context.Context, in *EnumID, opts ...grpc.CallOption) (*EnumStatus, error)
+	Status(ctx context.Context, in *EnumID, opts ...grpc.CallOption) (*EnumStatus, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*EnumList, error)
+	Watch(ctx context.Context, in *EnumID, opts ...grpc.CallOption) (Enumerations_WatchClient, error)
+	Graph(ctx context.Context, in *EnumID, opts ...grpc.CallOption) (Enumerations_GraphClient, error)
+}
+
+type enumerationsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEnumerationsClient(cc grpc.ClientConnInterface) EnumerationsClient {
+	return &enumerationsClient{cc}
+}
+
+func (c *enumerationsClient) Start(ctx context.Context, in *EnumRequest, opts ...grpc.CallOption) (*EnumStatus, error) {
+	out := new(EnumStatus)
+	err := c.cc.Invoke(ctx, "/amass.v1.Enumerations/Start", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enumerationsClient) Stop(ctx context.Context, in *EnumID, opts ...grpc.CallOption) (*EnumStatus, error) {
+	out := new(EnumStatus)
+	err := c.cc.Invoke(ctx, "/amass.v1.Enumerations/Stop", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enumerationsClient) Status(ctx context.Context, in *EnumID, opts ...grpc.CallOption) (*EnumStatus, error) {
+	out := new(EnumStatus)
+	err := c.cc.Invoke(ctx, "/amass.v1.Enumerations/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enumerationsClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*EnumList, error) {
+	out := new(EnumList)
+	err := c.cc.Invoke(ctx, "/amass.v1.Enumerations/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enumerationsClient) Watch(ctx context.Context, in *EnumID, opts ...grpc.CallOption) (Enumerations_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Enumerations_ServiceDesc.Streams[0], "/amass.v1.Enumerations/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &enumerationsWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Enumerations_WatchClient interface {
+	Recv() (*EnumEvent, error)
+	grpc.ClientStream
+}
+
+type enumerationsWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *enumerationsWatchClient) Recv() (*EnumEvent, error) {
+	m := new(EnumEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *enumerationsClient) Graph(ctx context.Context, in *EnumID, opts ...grpc.CallOption) (Enumerations_GraphClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Enumerations_ServiceDesc.Streams[1], "/amass.v1.Enumerations/Graph", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &enumerationsGraphClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Enumerations_GraphClient interface {
+	Recv() (*GraphChunk, error)
+	grpc.ClientStream
+}
+
+type enumerationsGraphClient struct {
+	grpc.ClientStream
+}
+
+func (x *enumerationsGraphClient) Recv() (*GraphChunk, error) {
+	m := new(GraphChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EnumerationsServer is the server API for Enumerations service.
+// All implementations must embed UnimplementedEnumerationsServer
+// for forward compatibility
+type EnumerationsServer interface {
+	Start(context.Context, *EnumRequest) (*EnumStatus, error)
+	Stop(context.Context, *EnumID) (*EnumStatus, error)
+	Status(context.Context, *EnumID) (*EnumStatus, error)
+	List(context.Context, *ListRequest) (*EnumList, error)
+	Watch(*EnumID, Enumerations_WatchServer) error
+	Graph(*EnumID, Enumerations_GraphServer) error
+	mustEmbedUnimplementedEnumerationsServer()
+}
+
+// UnimplementedEnumerationsServer must be embedded to have forward compatible implementations.
+type UnimplementedEnumerationsServer struct {
+}
+
+func (UnimplementedEnumerationsServer) Start(context.Context, *EnumRequest) (*EnumStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Start not implemented")
+}
+func (UnimplementedEnumerationsServer) Stop(context.Context, *EnumID) (*EnumStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (UnimplementedEnumerationsServer) Status(context.Context, *EnumID) (*EnumStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedEnumerationsServer) List(context.Context, *ListRequest) (*EnumList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedEnumerationsServer) Watch(*EnumID, Enumerations_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedEnumerationsServer) Graph(*EnumID, Enumerations_GraphServer) error {
+	return status.Errorf(codes.Unimplemented, "method Graph not implemented")
+}
+func (UnimplementedEnumerationsServer) mustEmbedUnimplementedEnumerationsServer() {}
+
+// UnsafeEnumerationsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EnumerationsServer will
+// result in compilation errors.
+type UnsafeEnumerationsServer interface {
+	mustEmbedUnimplementedEnumerationsServer()
+}
+
+func RegisterEnumerationsServer(s grpc.ServiceRegistrar, srv EnumerationsServer) {
+	s.RegisterService(&Enumerations_ServiceDesc, srv)
+}
+
+func _Enumerations_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnumerationsServer).Start(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/amass.v1.Enumerations/Start",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnumerationsServer).Start(ctx, req.(*EnumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Enumerations_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnumID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnumerationsServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/amass.v1.Enumerations/Stop",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnumerationsServer).Stop(ctx, req.(*EnumID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Enumerations_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnumID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnumerationsServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/amass.v1.Enumerations/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnumerationsServer).Status(ctx, req.(*EnumID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Enumerations_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnumerationsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/amass.v1.Enumerations/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnumerationsServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Enumerations_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EnumID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EnumerationsServer).Watch(m, &enumerationsWatchServer{stream})
+}
+
+type Enumerations_WatchServer interface {
+	Send(*EnumEvent) error
+	grpc.ServerStream
+}
+
+type enumerationsWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *enumerationsWatchServer) Send(m *EnumEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Enumerations_Graph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EnumID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EnumerationsServer).Graph(m, &enumerationsGraphServer{stream})
+}
+
+type Enumerations_GraphServer interface {
+	Send(*GraphChunk) error
+	grpc.ServerStream
+}
+
+type enumerationsGraphServer struct {
+	grpc.ServerStream
+}
+
+func (x *enumerationsGraphServer) Send(m *GraphChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Enumerations_ServiceDesc is the grpc.ServiceDesc for Enumerations service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Enumerations_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "amass.v1.Enumerations",
+	HandlerType: (*EnumerationsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Start",
+			Handler:    _Enumerations_Start_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _Enumerations_Stop_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _Enumerations_Status_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Enumerations_List_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Enumerations_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Graph",
+			Handler:       _Enumerations_Graph_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "amass.proto",
+}
@@ -0,0 +1,575 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	amassdns "github.com/OWASP/Amass/v3/net/dns"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/google/uuid"
+)
+
+const (
+	// The longest request body accepted by the REST API.
+	restMaxRequestSize = 1 << 20
+	// ResultsFileName is the JSON output file written by each enumeration within its directory.
+	ResultsFileName = "amass.json"
+	// GraphFileName is the N-Quads file of the graph data written by each enumeration once it ends.
+	GraphFileName = "amass.nq"
+)
+
+// Matches the complete DNS names with at least two labels, as required for the domains in scope.
+var domainNameRegex = regexp.MustCompile("^" + amassdns.AnySubdomainRegexString() + "$")
+
+// The states of the enumerations managed by the REST API.
+const (
+	EnumQueued   = "queued"
+	EnumRunning  = "running"
+	EnumFinished = "finished"
+	EnumFailed   = "failed"
+	EnumStopped  = "stopped"
+)
+
+// EnumRequest provides the settings of an enumeration started through the REST API.
+type EnumRequest struct {
+	Domains     []string `json:"domains"`
+	Active      bool     `json:"active,omitempty"`
+	Brute       bool     `json:"brute,omitempty"`
+	Alterations bool     `json:"alterations,omitempty"`
+	Passive     bool     `json:"passive,omitempty"`
+	// The number of minutes the enumeration is allowed to run, where zero does not limit it
+	Timeout int `json:"timeout,omitempty"`
+	// The named profile of the configuration file used by the enumeration
+	Profile string `json:"profile,omitempty"`
+	// The netblocks and autonomous systems swept by the enumeration
+	CIDRs []string `json:"cidrs,omitempty"`
+	ASNs  []int    `json:"asns,omitempty"`
+	// The shard I/N of the brute forcing wordlists used by the enumeration, such as 2/4
+	BruteShard string `json:"brute_shard,omitempty"`
+}
+
+// EnumStatus is the progress of an enumeration managed by the REST API.
+type EnumStatus struct {
+	ID       string       `json:"id"`
+	Request  *EnumRequest `json:"request"`
+	State    string       `json:"state"`
+	Error    string       `json:"error,omitempty"`
+	Created  string       `json:"created"`
+	Started  string       `json:"started,omitempty"`
+	Finished string       `json:"finished,omitempty"`
+	// The number of names discovered so far
+	Names int `json:"names"`
+}
+
+// EnumRunner executes the enumeration until it completes or the context is cancelled, writing the
+// findings to the ResultsFileName file within the directory while the enumeration is running.
+type EnumRunner func(ctx context.Context, req *EnumRequest, dir string) error
+
+// EnumReloader applies the configuration file again to the running enumeration, without restarting it.
+type EnumReloader func() error
+
+type managedEnum struct {
+	status *EnumStatus
+	dir    string
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// EnumManager executes the enumerations requested through the REST API one at a time,
+// since the enumerations share the graph database, and keeps the status of each of them.
+type EnumManager struct {
+	sync.Mutex
+	runner   EnumRunner
+	reloader EnumReloader
+	dir      string
+	enums    map[string]*managedEnum
+	order    []string
+	pending  []*managedEnum
+	wake     chan struct{}
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// NewEnumManager returns the EnumManager that stores the output of each enumeration within the directory.
+func NewEnumManager(dir string, runner EnumRunner) *EnumManager {
+	m := &EnumManager{
+		runner:  runner,
+		dir:     dir,
+		enums:   make(map[string]*managedEnum),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go m.processQueue()
+	return m
+}
+
+// Close stops the enumerations and waits for the running enumeration to finish.
+func (m *EnumManager) Close() {
+	m.Lock()
+	for _, e := range m.enums {
+		if e.status.State == EnumQueued {
+			e.status.State = EnumStopped
+		} else if e.status.State == EnumRunning {
+			e.cancel()
+		}
+	}
+	m.pending = nil
+	m.Unlock()
+
+	close(m.done)
+	<-m.stopped
+}
+
+// Start queues the enumeration and returns its status.
+func (m *EnumManager) Start(req *EnumRequest) (*EnumStatus, error) {
+	var domains []string
+	for _, d := range req.Domains {
+		if d = strings.ToLower(strings.TrimSpace(d)); d == "" {
+			continue
+		}
+		// The configuration of the enumeration would silently drop the domains that are not valid
+		if len(d) > 253 || !domainNameRegex.MatchString(d) {
+			return nil, fmt.Errorf("the domain %s is not a valid DNS name", d)
+		}
+		domains = append(domains, d)
+	}
+	if len(domains) == 0 {
+		return nil, errors.New("the enumeration requires at least one domain")
+	}
+	if req.Passive && (req.Active || req.Brute || req.Alterations) {
+		return nil, errors.New("the passive enumeration cannot use active, brute or alterations")
+	}
+	if req.Timeout < 0 {
+		return nil, errors.New("the timeout must be zero or more minutes")
+	}
+	for _, cidr := range req.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("the CIDR %s is not valid", cidr)
+		}
+	}
+	for _, asn := range req.ASNs {
+		if asn <= 0 {
+			return nil, fmt.Errorf("the ASN %d is not valid", asn)
+		}
+	}
+	if req.BruteShard != "" {
+		if !req.Brute {
+			return nil, errors.New("the brute forcing shard requires brute forcing")
+		}
+		if _, err := config.ParseBruteShard(req.BruteShard); err != nil {
+			return nil, err
+		}
+	}
+	req.Domains = domains
+
+	id := uuid.New().String()
+	e := &managedEnum{
+		dir: filepath.Join(m.dir, id),
+		status: &EnumStatus{
+			ID:      id,
+			Request: req,
+			State:   EnumQueued,
+			Created: restTime(time.Now()),
+		},
+	}
+
+	m.Lock()
+	m.enums[id] = e
+	m.order = append(m.order, id)
+	m.pending = append(m.pending, e)
+	status := *e.status
+	m.Unlock()
+
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+	return &status, nil
+}
+
+// Stop removes the queued enumeration from the queue, or interrupts the running enumeration.
+func (m *EnumManager) Stop(id string) (*EnumStatus, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	e, found := m.enums[id]
+	if !found {
+		return nil, fmt.Errorf("the enumeration %s was not found", id)
+	}
+
+	switch e.status.State {
+	case EnumQueued:
+		e.status.State = EnumStopped
+		e.status.Finished = restTime(time.Now())
+	case EnumRunning:
+		e.cancel()
+	default:
+		return nil, fmt.Errorf("the enumeration %s has already finished", id)
+	}
+
+	status := *e.status
+	return &status, nil
+}
+
+// Status returns the status of the enumeration, or false when it was not found.
+func (m *EnumManager) Status(id string) (*EnumStatus, bool) {
+	m.Lock()
+	e, found := m.enums[id]
+	if !found {
+		m.Unlock()
+		return nil, false
+	}
+	status := *e.status
+	dir := e.dir
+	m.Unlock()
+
+	if status.State != EnumQueued {
+		if results, err := readResults(dir); err == nil {
+			status.Names = len(results)
+		}
+	}
+	return &status, true
+}
+
+// List returns the status of the enumerations in the order they were requested.
+func (m *EnumManager) List() []*EnumStatus {
+	m.Lock()
+	ids := append([]string(nil), m.order...)
+	m.Unlock()
+
+	list := []*EnumStatus{}
+	for _, id := range ids {
+		if status, found := m.Status(id); found {
+			list = append(list, status)
+		}
+	}
+	return list
+}
+
+// Running returns true while an enumeration is being executed.
+func (m *EnumManager) Running() bool {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, e := range m.enums {
+		if e.status.State == EnumRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// SetReloader provides the function executed by Reload.
+func (m *EnumManager) SetReloader(f EnumReloader) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.reloader = f
+}
+
+// Reload applies the configuration file again to the running enumeration and returns its status.
+func (m *EnumManager) Reload() (*EnumStatus, error) {
+	m.Lock()
+	reloader := m.reloader
+	var id string
+	for _, e := range m.enums {
+		if e.status.State == EnumRunning {
+			id = e.status.ID
+		}
+	}
+	m.Unlock()
+
+	if reloader == nil {
+		return nil, errors.New("the configuration cannot be reloaded")
+	}
+	if id == "" {
+		return nil, errors.New("no enumeration is running")
+	}
+	if err := reloader(); err != nil {
+		return nil, fmt.Errorf("failed to reload the configuration: %v", err)
+	}
+
+	status, _ := m.Status(id)
+	return status, nil
+}
+
+// Results returns the findings written by the enumeration so far.
+func (m *EnumManager) Results(id string) ([]*requests.Output, error) {
+	m.Lock()
+	e, found := m.enums[id]
+	m.Unlock()
+
+	if !found {
+		return nil, fmt.Errorf("the enumeration %s was not found", id)
+	}
+	return readResults(e.dir)
+}
+
+// Graph returns the graph data written by the enumeration in the N-Quads format once it has ended.
+func (m *EnumManager) Graph(id string) (io.ReadCloser, error) {
+	m.Lock()
+	e, found := m.enums[id]
+	var ended bool
+	if found {
+		ended = e.status.Terminal()
+	}
+	m.Unlock()
+
+	if !found {
+		return nil, fmt.Errorf("the enumeration %s was not found", id)
+	}
+	if !ended {
+		return nil, fmt.Errorf("the enumeration %s has not ended", id)
+	}
+
+	f, err := os.Open(filepath.Join(e.dir, GraphFileName))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("the enumeration %s did not write its graph data", id)
+	} else if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (m *EnumManager) processQueue() {
+	defer close(m.stopped)
+
+	for {
+		if e := m.next(); e != nil {
+			m.run(e)
+			continue
+		}
+
+		select {
+		case <-m.done:
+			return
+		case <-m.wake:
+		}
+	}
+}
+
+// Returns the next queued enumeration, which is marked as running.
+func (m *EnumManager) next() *managedEnum {
+	m.Lock()
+	defer m.Unlock()
+
+	for len(m.pending) > 0 {
+		e := m.pending[0]
+		m.pending = m.pending[1:]
+
+		if e.status.State == EnumQueued {
+			e.ctx, e.cancel = context.WithCancel(context.Background())
+			e.status.State = EnumRunning
+			e.status.Started = restTime(time.Now())
+			return e
+		}
+	}
+	return nil
+}
+
+func (m *EnumManager) run(e *managedEnum) {
+	defer e.cancel()
+
+	err := os.MkdirAll(e.dir, 0755)
+	if err == nil {
+		err = m.runner(e.ctx, e.status.Request, e.dir)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	e.status.Finished = restTime(time.Now())
+	switch {
+	case e.ctx.Err() != nil:
+		e.status.State = EnumStopped
+	case err != nil:
+		e.status.State = EnumFailed
+		e.status.Error = err.Error()
+	default:
+		e.status.State = EnumFinished
+	}
+}
+
+// Reads the findings written to the JSON output file of the enumeration, one per line.
+func readResults(dir string) ([]*requests.Output, error) {
+	results := []*requests.Output{}
+
+	f, err := os.Open(filepath.Join(dir, ResultsFileName))
+	if os.IsNotExist(err) {
+		return results, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		// A partial line is still being written by the enumeration
+		if err != nil {
+			break
+		}
+
+		var out requests.Output
+		if json.Unmarshal(line, &out) == nil && out.Name != "" {
+			results = append(results, &out)
+		}
+	}
+	return results, nil
+}
+
+func restTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// NewRESTHandler returns the handler that serves the REST API managing the enumerations:
+//
+//	GET  /v1/enumerations              lists the enumerations
+//	POST /v1/enumerations              queues the enumeration provided by the EnumRequest in the body
+//	GET  /v1/enumerations/{id}         returns the status of the enumeration
+//	GET  /v1/enumerations/{id}/results returns the findings of the enumeration
+//	POST /v1/enumerations/{id}/stop    stops the enumeration
+//	POST /v1/reload                    reloads the configuration file of the running enumeration
+//
+// The graph handler, when provided, is served at /v1/graphql.
+func NewRESTHandler(m *EnumManager, graph http.Handler) http.Handler {
+	mux := http.NewServeMux()
+
+	if graph != nil {
+		mux.Handle("/v1/graphql", graph)
+	}
+	mux.HandleFunc("/v1/enumerations", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeRESTJSON(w, http.StatusOK, m.List())
+		case http.MethodPost:
+			var req EnumRequest
+			if err := json.NewDecoder(io.LimitReader(r.Body, restMaxRequestSize)).Decode(&req); err != nil {
+				writeRESTError(w, http.StatusBadRequest, fmt.Errorf("the request body is not valid: %v", err))
+				return
+			}
+
+			status, err := m.Start(&req)
+			if err != nil {
+				writeRESTError(w, http.StatusBadRequest, err)
+				return
+			}
+			w.Header().Set("Location", "/v1/enumerations/"+status.ID)
+			writeRESTJSON(w, http.StatusCreated, status)
+		default:
+			writeRESTError(w, http.StatusMethodNotAllowed, errors.New("the method is not allowed"))
+		}
+	})
+	mux.HandleFunc("/v1/enumerations/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/enumerations/"), "/")
+		id := parts[0]
+
+		var action string
+		if len(parts) > 1 {
+			action = strings.Join(parts[1:], "/")
+		}
+
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			if status, found := m.Status(id); found {
+				writeRESTJSON(w, http.StatusOK, status)
+				return
+			}
+			writeRESTError(w, http.StatusNotFound, fmt.Errorf("the enumeration %s was not found", id))
+		case action == "results" && r.Method == http.MethodGet:
+			if _, found := m.Status(id); !found {
+				writeRESTError(w, http.StatusNotFound, fmt.Errorf("the enumeration %s was not found", id))
+				return
+			}
+			results, err := m.Results(id)
+			if err != nil {
+				writeRESTError(w, http.StatusInternalServerError, err)
+				return
+			}
+			writeRESTJSON(w, http.StatusOK, results)
+		case action == "stop" && r.Method == http.MethodPost:
+			if _, found := m.Status(id); !found {
+				writeRESTError(w, http.StatusNotFound, fmt.Errorf("the enumeration %s was not found", id))
+				return
+			}
+			status, err := m.Stop(id)
+			if err != nil {
+				writeRESTError(w, http.StatusConflict, err)
+				return
+			}
+			writeRESTJSON(w, http.StatusOK, status)
+		case action == "" || action == "results" || action == "stop":
+			writeRESTError(w, http.StatusMethodNotAllowed, errors.New("the method is not allowed"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("/v1/reload", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeRESTError(w, http.StatusMethodNotAllowed, errors.New("the method is not allowed"))
+			return
+		}
+
+		status, err := m.Reload()
+		if err != nil {
+			writeRESTError(w, http.StatusConflict, err)
+			return
+		}
+		writeRESTJSON(w, http.StatusOK, status)
+	})
+	return mux
+}
+
+// CheckAPIKey returns an error when the API would be served without a key authenticating the
+// requests, unless serving the API without authentication was explicitly requested.
+func CheckAPIKey(key string, insecure bool) error {
+	if key == "" && !insecure {
+		return errors.New("the API key is required to serve the API with authentication")
+	}
+	return nil
+}
+
+// RequireAPIKey returns the handler that rejects the requests without the API key
+// in the Authorization header, using the Bearer scheme.
+func RequireAPIKey(key string, h http.Handler) http.Handler {
+	expected := []byte("Bearer " + key)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeRESTError(w, http.StatusUnauthorized, errors.New("the API key is missing or not valid"))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func writeRESTJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeRESTError(w http.ResponseWriter, code int, err error) {
+	writeRESTJSON(w, code, map[string]string{"error": err.Error()})
+}
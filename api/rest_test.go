@@ -0,0 +1,158 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestRESTHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rest")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	release := make(chan struct{})
+	// The enumerations write a finding and run until they are released or stopped
+	m := NewEnumManager(dir, func(ctx context.Context, req *EnumRequest, out string) error {
+		line := `{"name":"www.` + req.Domains[0] + `","domain":"` + req.Domains[0] + "\"}\n"
+		if err := ioutil.WriteFile(filepath.Join(out, ResultsFileName), []byte(line), 0644); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-release:
+		}
+		return nil
+	})
+	defer m.Close()
+	h := RequireAPIKey("secret", NewRESTHandler(m, nil))
+
+	do := func(method, path, body string, v interface{}) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if v != nil {
+			if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+				t.Fatalf("Failed to decode the response of %s %s: %v", method, path, err)
+			}
+		}
+		return rec.Code
+	}
+	wait := func(id, state string) *EnumStatus {
+		for i := 0; i < 100; i++ {
+			var status EnumStatus
+			if do(http.MethodGet, "/v1/enumerations/"+id, "", &status) == http.StatusOK && status.State == state {
+				return &status
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("The enumeration %s did not reach the %s state", id, state)
+		return nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/enumerations", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("The request without the API key returned %d", rec.Code)
+	}
+
+	var first, second EnumStatus
+	if code := do(http.MethodPost, "/v1/enumerations", `{"domains":["OWASP.org"],"brute":true}`, &first); code != http.StatusCreated {
+		t.Fatalf("Starting the enumeration returned %d", code)
+	}
+	if first.Request.Domains[0] != "owasp.org" || !first.Request.Brute {
+		t.Errorf("The enumeration request was not accepted: %v", first.Request)
+	}
+	if code := do(http.MethodPost, "/v1/enumerations", `{"domains":["example.com"]}`, &second); code != http.StatusCreated {
+		t.Fatalf("Starting the enumeration returned %d", code)
+	}
+	if code := do(http.MethodPost, "/v1/enumerations", `{"domains":["example.com"],"passive":true,"active":true}`, nil); code != http.StatusBadRequest {
+		t.Errorf("The enumeration with conflicting settings returned %d", code)
+	}
+	for _, domain := range []string{"localhost", "owasp..org", "owasp.org/path", "-owasp.org", "owasp.org."} {
+		if code := do(http.MethodPost, "/v1/enumerations", `{"domains":["`+domain+`"]}`, nil); code != http.StatusBadRequest {
+			t.Errorf("The enumeration of the domain %q returned %d", domain, code)
+		}
+	}
+	// The enumerations are executed one at a time
+	if status := wait(first.ID, EnumRunning); status.Names != 1 {
+		t.Errorf("The running enumeration reported %d names", status.Names)
+	}
+	if status := wait(second.ID, EnumQueued); status.Started != "" {
+		t.Errorf("The queued enumeration was started")
+	}
+
+	var results []*requests.Output
+	if code := do(http.MethodGet, "/v1/enumerations/"+first.ID+"/results", "", &results); code != http.StatusOK ||
+		len(results) != 1 || results[0].Name != "www.owasp.org" {
+		t.Errorf("The results were not returned: %d %v", code, results)
+	}
+
+	if code := do(http.MethodPost, "/v1/reload", "", nil); code != http.StatusConflict {
+		t.Errorf("Reloading without the reloader returned %d", code)
+	}
+	var reloads int
+	m.SetReloader(func() error {
+		reloads++
+		return nil
+	})
+	var reloaded EnumStatus
+	if code := do(http.MethodPost, "/v1/reload", "", &reloaded); code != http.StatusOK || reloaded.ID != first.ID || reloads != 1 {
+		t.Errorf("The running enumeration was not reloaded: %d %v", code, reloaded)
+	}
+
+	if code := do(http.MethodPost, "/v1/enumerations/"+first.ID+"/stop", "", nil); code != http.StatusOK {
+		t.Errorf("Stopping the enumeration returned %d", code)
+	}
+	wait(first.ID, EnumStopped)
+	wait(second.ID, EnumRunning)
+	close(release)
+	wait(second.ID, EnumFinished)
+
+	if code := do(http.MethodPost, "/v1/enumerations/"+second.ID+"/stop", "", nil); code != http.StatusConflict {
+		t.Errorf("Stopping the finished enumeration returned %d", code)
+	}
+	if code := do(http.MethodPost, "/v1/reload", "", nil); code != http.StatusConflict {
+		t.Errorf("Reloading without a running enumeration returned %d", code)
+	}
+	if code := do(http.MethodGet, "/v1/enumerations/unknown", "", nil); code != http.StatusNotFound {
+		t.Errorf("The unknown enumeration returned %d", code)
+	}
+
+	var list []*EnumStatus
+	if code := do(http.MethodGet, "/v1/enumerations", "", &list); code != http.StatusOK ||
+		len(list) != 2 || list[0].ID != first.ID || list[1].ID != second.ID {
+		t.Errorf("The enumerations were not listed in order: %d %v", code, list)
+	}
+}
+
+func TestCheckAPIKey(t *testing.T) {
+	if err := CheckAPIKey("", false); err == nil {
+		t.Errorf("The API was allowed to be served without a key")
+	}
+	if err := CheckAPIKey("", true); err != nil {
+		t.Errorf("The API was not allowed to be served without a key in the insecure mode: %v", err)
+	}
+	if err := CheckAPIKey("secret", false); err != nil {
+		t.Errorf("The API was not allowed to be served with a key: %v", err)
+	}
+}
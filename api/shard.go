@@ -0,0 +1,72 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "fmt"
+
+// ShardEnumRequest divides the scope of the enumeration across at most the number of workers. When there
+// are enough domains, each worker receives its own domains. Otherwise, each worker receives all the domains
+// and its own shard of the brute forcing wordlists. The netblocks and autonomous systems are swept once,
+// by the worker they are assigned to, which also receives all the domains when it has none of its own, since
+// the enumerations require them. The workers that would receive no scope are not included in the shards.
+func ShardEnumRequest(req *EnumRequest, workers int) []*EnumRequest {
+	count := workers
+	if count < 1 {
+		count = 1
+	}
+	split := len(req.Domains) >= count
+	if !split && !req.Brute {
+		// Only the domains and netblocks can be divided
+		count = maxInt(len(req.Domains), minInt(count, len(req.CIDRs)+len(req.ASNs)))
+		split = true
+	}
+	if count <= 1 {
+		shard := *req
+		return []*EnumRequest{&shard}
+	}
+
+	shards := make([]*EnumRequest, count)
+	for i := range shards {
+		shard := *req
+		shard.Domains, shard.CIDRs, shard.ASNs = nil, nil, nil
+		if !split {
+			shard.Domains = append([]string(nil), req.Domains...)
+			shard.BruteShard = fmt.Sprintf("%d/%d", i+1, count)
+		}
+		shards[i] = &shard
+	}
+	if split {
+		for i, d := range req.Domains {
+			shards[i%count].Domains = append(shards[i%count].Domains, d)
+		}
+	}
+	for i, cidr := range req.CIDRs {
+		shards[i%count].CIDRs = append(shards[i%count].CIDRs, cidr)
+	}
+	for i, asn := range req.ASNs {
+		shards[i%count].ASNs = append(shards[i%count].ASNs, asn)
+	}
+
+	for _, shard := range shards {
+		if len(shard.Domains) == 0 {
+			shard.Domains = append([]string(nil), req.Domains...)
+		}
+	}
+	return shards
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,44 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "testing"
+
+func TestShardEnumRequest(t *testing.T) {
+	// Enough domains for each worker
+	shards := ShardEnumRequest(&EnumRequest{
+		Domains: []string{"a.com", "b.com", "c.com", "d.com", "e.com"},
+		Brute:   true,
+		CIDRs:   []string{"10.0.0.0/24"},
+	}, 2)
+	if len(shards) != 2 || len(shards[0].Domains) != 3 || len(shards[1].Domains) != 2 {
+		t.Fatalf("The domains were not divided across the workers: %v", shards)
+	}
+	if shards[0].BruteShard != "" || len(shards[0].CIDRs) != 1 || len(shards[1].CIDRs) != 0 {
+		t.Errorf("The domain shards were not assigned correctly: %v %v", shards[0], shards[1])
+	}
+
+	// The brute forcing wordlists are divided across the workers
+	shards = ShardEnumRequest(&EnumRequest{Domains: []string{"a.com"}, Brute: true, Timeout: 30}, 3)
+	if len(shards) != 3 {
+		t.Fatalf("The brute forcing was divided into %d shards", len(shards))
+	}
+	for i, shard := range shards {
+		if want := []string{"1/3", "2/3", "3/3"}[i]; shard.BruteShard != want || len(shard.Domains) != 1 || shard.Timeout != 30 {
+			t.Errorf("The shard %d was %v instead of using the wordlist shard %s", i, shard, want)
+		}
+	}
+
+	// Only the netblocks can be divided without brute forcing
+	shards = ShardEnumRequest(&EnumRequest{Domains: []string{"a.com"}, ASNs: []int{1, 2, 3}}, 2)
+	if len(shards) != 2 || len(shards[0].ASNs) != 2 || len(shards[1].ASNs) != 1 || shards[1].Domains[0] != "a.com" {
+		t.Errorf("The netblocks were not divided across the workers: %v %v", shards[0], shards[1])
+	}
+
+	// The workers without scope are not used
+	if shards = ShardEnumRequest(&EnumRequest{Domains: []string{"a.com"}}, 4); len(shards) != 1 {
+		t.Errorf("The enumeration without divisible scope used %d workers", len(shards))
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/api"
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/fatih/color"
+)
+
+const (
+	apiUsageMsg = "api [options]"
+	// The environment variable providing the key required by the REST API
+	apiKeyEnv = "AMASS_API_KEY"
+)
+
+type apiArgs struct {
+	Addr     string
+	GRPCAddr string
+	Insecure bool
+	daemonArgs
+}
+
+func runAPICommand(clArgs []string) {
+	var args apiArgs
+	var help1, help2 bool
+	apiCommand := flag.NewFlagSet("api", flag.ContinueOnError)
+
+	apiBuf := new(bytes.Buffer)
+	apiCommand.SetOutput(apiBuf)
+
+	apiCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	apiCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	apiCommand.StringVar(&args.Addr, "addr", "127.0.0.1:8080", "Address the REST API is served on")
+	apiCommand.StringVar(&args.GRPCAddr, "grpc-addr", "", "Address the gRPC API is served on, in addition to the REST API")
+	apiCommand.BoolVar(&args.Insecure, "insecure", false, "Serve the APIs without a key when "+apiKeyEnv+" is not set")
+	apiCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	apiCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	apiCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
+	apiCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+	apiCommand.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
+
+	if err := apiCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(apiUsageMsg, apiCommand, apiBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = ioutil.Discard
+		color.Error = ioutil.Discard
+	}
+
+	// Anyone reaching the APIs can send DNS and HTTP traffic from this host, so the key is required
+	key := os.Getenv(apiKeyEnv)
+	if err := api.CheckAPIKey(key, args.Insecure); err != nil {
+		r.Fprintf(color.Error, "%v: set %s or use the -insecure flag\n", err, apiKeyEnv)
+		os.Exit(1)
+	}
+
+	cfg := config.NewConfig()
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	dir := projectDirectory(args.Filepaths.Directory, args.Project, cfg)
+
+	exe, err := os.Executable()
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to find the amass executable: %v\n", err)
+		os.Exit(1)
+	}
+	// The enumerations are executed as enum subcommands, like the scheduled enumerations of the daemon
+	d := &daemon{args: &args.daemonArgs, exe: exe}
+	m := api.NewEnumManager(filepath.Join(config.OutputDirectory(dir), "api"), func(ctx context.Context, req *api.EnumRequest, out string) error {
+		enumArgs := d.enumArgs(&config.Schedule{
+			Domains: req.Domains,
+			Active:  req.Active,
+			Brute:   req.Brute,
+			Alts:    req.Alterations,
+			Passive: req.Passive,
+			Timeout: req.Timeout,
+			Profile: req.Profile,
+		})
+		// The scope assigned to the workers of a distributed enumeration
+		if len(req.CIDRs) > 0 {
+			enumArgs = append(enumArgs, "-cidr", strings.Join(req.CIDRs, ","))
+		}
+		if len(req.ASNs) > 0 {
+			var asns []string
+			for _, asn := range req.ASNs {
+				asns = append(asns, strconv.Itoa(asn))
+			}
+			enumArgs = append(enumArgs, "-asn", strings.Join(asns, ","))
+		}
+		if req.BruteShard != "" {
+			enumArgs = append(enumArgs, "-brute-shard", req.BruteShard)
+		}
+
+		prefix := filepath.Join(out, strings.TrimSuffix(api.ResultsFileName, filepath.Ext(api.ResultsFileName)))
+		return runSubcommand(ctx, exe, append(enumArgs, "-oA", prefix, "-log", prefix+".log",
+			"-onq", filepath.Join(out, api.GraphFileName)))
+	})
+	defer m.Close()
+	// The running enumeration applies the configuration file again after POST /v1/reload or the hangup signal
+	m.SetReloader(func() error {
+		_, err := reloadEnumProcs()
+		return err
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloadOnHangup(ctx)
+
+	var h http.Handler = api.NewRESTHandler(m, graphQLPerRequest(dir, cfg, m))
+	if key != "" {
+		h = api.RequireAPIKey(key, h)
+	} else {
+		r.Fprintf(color.Error, "The APIs do not require a key, since the -insecure flag was provided\n")
+	}
+	// The gRPC API manages the same enumerations and requires the same key
+	if args.GRPCAddr != "" {
+		lis, err := net.Listen("tcp", args.GRPCAddr)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to listen for the gRPC API: %v\n", err)
+			os.Exit(1)
+		}
+
+		srv := api.NewGRPCServer(m, key)
+		defer srv.Stop()
+		go func() {
+			if err := srv.Serve(lis); err != nil {
+				r.Fprintf(color.Error, "The gRPC API failed: %v\n", err)
+			}
+		}()
+		g.Fprintf(color.Error, "The gRPC API is available at %s\n", args.GRPCAddr)
+	}
+
+	g.Fprintf(color.Error, "The REST API is available at http://%s/v1/enumerations\n", args.Addr)
+	if err := serveHTTP(args.Addr, h); err != nil {
+		r.Fprintf(color.Error, "The REST API failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Returns the handler serving the GraphQL API over a connection to the graph database opened for each request.
+// The local database is locked by the running enumeration, so it can only be queried between enumerations.
+func graphQLPerRequest(dir string, cfg *config.Config, m *api.EnumManager) http.Handler {
+	var remote bool
+	for _, db := range cfg.GraphDBs {
+		if db.Primary {
+			remote = true
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !remote && m.Running() {
+			w.Header().Set("Retry-After", "60")
+			apiError(w, http.StatusServiceUnavailable, errors.New("the graph database is in use by the running enumeration"))
+			return
+		}
+
+		db := openGraphDatabase(dir, cfg)
+		if db == nil {
+			apiError(w, http.StatusInternalServerError, errors.New("failed to connect with the graph database"))
+			return
+		}
+		defer db.Close()
+
+		h, err := api.NewGraphQLHandler(db)
+		if err != nil {
+			apiError(w, http.StatusInternalServerError, err)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+func apiError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,149 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/fatih/color"
+)
+
+// Certificates expiring within this number of days are highlighted.
+const certExpiryWarningDays = 30
+
+type certSummary struct {
+	Serial    string
+	Issuer    string
+	Subject   string
+	NotBefore time.Time
+	NotAfter  time.Time
+	Names     []string
+	Addresses []string
+}
+
+// Returns the certificates observed during the events that cover names in scope, ordered by expiration.
+func eventCertificates(ctx context.Context, uuids, domains []string, db *netmap.Graph) []*certSummary {
+	nodes, err := db.AllNodesOfType(ctx, "certificate", uuids...)
+	if err != nil {
+		return nil
+	}
+
+	var certs []*certSummary
+	for _, node := range nodes {
+		c := readCertificate(ctx, db, node)
+
+		var sans []string
+		if props, err := db.ReadProperties(ctx, node, "san"); err == nil {
+			for _, p := range props {
+				if v, ok := p.Value.Native().(string); ok {
+					sans = append(sans, v)
+				}
+			}
+		}
+
+		var inscope bool
+		for _, name := range append(sans, c.Names...) {
+			if len(domains) == 0 || domainNameInScope(name, domains) {
+				inscope = true
+				break
+			}
+		}
+		if inscope {
+			certs = append(certs, c)
+		}
+	}
+
+	sort.Slice(certs, func(i, j int) bool {
+		return certs[i].NotAfter.Before(certs[j].NotAfter)
+	})
+	return certs
+}
+
+func readCertificate(ctx context.Context, db *netmap.Graph, node netmap.Node) *certSummary {
+	c := new(certSummary)
+
+	if props, err := db.ReadProperties(ctx, node, "serial", "issuer", "subject", "not_before", "not_after"); err == nil {
+		for _, p := range props {
+			v, ok := p.Value.Native().(string)
+			if !ok {
+				continue
+			}
+
+			switch p.Predicate {
+			case "serial":
+				c.Serial = v
+			case "issuer":
+				c.Issuer = v
+			case "subject":
+				c.Subject = v
+			case "not_before":
+				c.NotBefore, _ = time.Parse(time.RFC3339, v)
+			case "not_after":
+				c.NotAfter, _ = time.Parse(time.RFC3339, v)
+			}
+		}
+	}
+	// The names and addresses that the certificate was linked to
+	if edges, err := db.ReadInEdges(ctx, node, "certificate"); err == nil {
+		for _, edge := range edges {
+			id := db.NodeToID(edge.From)
+
+			if net.ParseIP(id) != nil {
+				c.Addresses = append(c.Addresses, id)
+			} else {
+				c.Names = append(c.Names, id)
+			}
+		}
+	}
+
+	sort.Strings(c.Names)
+	sort.Strings(c.Addresses)
+	return c
+}
+
+func showCertificates(uuids, domains []string, db *netmap.Graph) {
+	certs := eventCertificates(context.Background(), uuids, domains, db)
+	if len(certs) == 0 {
+		r.Println("No certificates were observed")
+		return
+	}
+
+	now := time.Now()
+	for _, c := range certs {
+		expiry := "unknown"
+		if !c.NotAfter.IsZero() {
+			days := int(c.NotAfter.Sub(now).Hours() / 24)
+
+			switch {
+			case c.NotAfter.Before(now):
+				expiry = red(fmt.Sprintf("%s (expired)", c.NotAfter.Format(timeFormat)))
+			case days < certExpiryWarningDays:
+				expiry = yellow(fmt.Sprintf("%s (%d days)", c.NotAfter.Format(timeFormat), days))
+			default:
+				expiry = green(fmt.Sprintf("%s (%d days)", c.NotAfter.Format(timeFormat), days))
+			}
+		}
+
+		blueLine()
+		fmt.Fprintf(color.Output, "%s\t%s\n", blue("Serial:"), green(c.Serial))
+		fmt.Fprintf(color.Output, "%s\t%s\n", blue("Issuer:"), yellow(c.Issuer))
+		if c.Subject != "" {
+			fmt.Fprintf(color.Output, "%s\t%s\n", blue("Subject:"), yellow(c.Subject))
+		}
+		fmt.Fprintf(color.Output, "%s\t%s\n", blue("Expires:"), expiry)
+		if len(c.Names) > 0 {
+			fmt.Fprintf(color.Output, "%s\t%s\n", blue("Names:"), green(strings.Join(c.Names, ", ")))
+		}
+		if len(c.Addresses) > 0 {
+			fmt.Fprintf(color.Output, "%s\t%s\n", blue("Hosts:"), yellow(strings.Join(c.Addresses, ", ")))
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+)
+
+// compactEvents merges the events sharing the same scope, except the keep most recent events of
+// each scope, into the oldest of them. The nodes of the merged events receive the first and last
+// times they were observed, so the history is preserved by a single event. When domains are
+// provided, only the events in scope are considered. The number of events removed is returned.
+func compactEvents(ctx context.Context, db *netmap.Graph, domains []string, keep int) (int, error) {
+	var events []string
+	if len(domains) > 0 {
+		events = db.EventsInScope(ctx, domains...)
+	} else {
+		events = db.EventList(ctx)
+	}
+
+	var scopes []string
+	groups := make(map[string][]string)
+	// The events are ordered from the oldest to the most recent
+	events, earliest, _ := enum.OrderedEvents(ctx, events, db)
+	for i, event := range events {
+		// Events without a start time were pruned previously
+		if earliest[i].IsZero() {
+			continue
+		}
+
+		d := db.EventDomains(ctx, event)
+		sort.Strings(d)
+		scope := strings.Join(d, ",")
+		if _, found := groups[scope]; !found {
+			scopes = append(scopes, scope)
+		}
+		groups[scope] = append(groups[scope], event)
+	}
+
+	var num int
+	for _, scope := range scopes {
+		group := groups[scope]
+		if keep > 0 && len(group) <= keep {
+			continue
+		}
+		if keep > 0 {
+			group = group[:len(group)-keep]
+		}
+		if len(group) < 2 {
+			continue
+		}
+
+		if err := mergeEvents(ctx, db, group[0], group[1:]); err != nil {
+			return num, err
+		}
+		num += len(group) - 1
+	}
+	return num, nil
+}
+
+// Merges the events into the target event and removes them from the graph.
+func mergeEvents(ctx context.Context, db *netmap.Graph, target string, events []string) error {
+	first := make(map[string]time.Time)
+	last := make(map[string]time.Time)
+	var finish time.Time
+
+	for _, event := range append([]string{target}, events...) {
+		start, end := enum.EventDateRange(ctx, db, event)
+		if end.After(finish) {
+			finish = end
+		}
+
+		sources := stringset.New()
+		if edges, err := db.ReadOutEdges(ctx, netmap.Node(event), "used"); err == nil {
+			for _, edge := range edges {
+				sources.Insert(db.NodeToID(edge.To))
+			}
+		}
+
+		if nodes, err := db.AllOutNodes(ctx, netmap.Node(event)); err == nil {
+			for _, node := range nodes {
+				id := db.NodeToID(node)
+				if sources.Has(id) {
+					continue
+				}
+				if t, found := first[id]; !found || start.Before(t) {
+					first[id] = start
+				}
+				if end.After(last[id]) {
+					last[id] = end
+				}
+			}
+		}
+		sources.Close()
+
+		if event == target {
+			continue
+		}
+		if edges, err := db.ReadOutEdges(ctx, netmap.Node(event)); err == nil {
+			for _, edge := range edges {
+				if err := db.UpsertEdge(ctx, &netmap.Edge{
+					Predicate: edge.Predicate,
+					From:      netmap.Node(target),
+					To:        edge.To,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		if err := enum.DeleteGraphNode(ctx, db, event); err != nil {
+			return err
+		}
+	}
+
+	for id, t := range first {
+		if err := replaceSeenTime(ctx, db, id, requests.FirstSeenPredicate, t, t.Before); err != nil {
+			return err
+		}
+		if err := replaceSeenTime(ctx, db, id, requests.LastSeenPredicate, last[id], last[id].After); err != nil {
+			return err
+		}
+	}
+	return replaceSeenTime(ctx, db, target, requests.LastSeenPredicate, finish, finish.After)
+}
+
+// Stores the time in the property of the node, unless the time already stored is preferred.
+func replaceSeenTime(ctx context.Context, db *netmap.Graph, id, predicate string, t time.Time, prefer func(time.Time) bool) error {
+	if t.IsZero() {
+		return nil
+	}
+
+	node := netmap.Node(id)
+	if props, err := db.ReadProperties(ctx, node, predicate); err == nil {
+		for _, p := range props {
+			if v, ok := p.Value.Native().(string); ok {
+				if stored, err := time.Parse(time.RFC3339, v); err == nil && !prefer(stored) {
+					return nil
+				}
+			}
+			_ = db.DeleteProperty(ctx, node, p.Predicate, p.Value)
+		}
+	}
+	return db.UpsertProperty(ctx, node, predicate, t.UTC().Format(time.RFC3339))
+}
+
+// Returns the time stored in the property of the node, or the zero time when it is not available.
+func seenTime(ctx context.Context, db *netmap.Graph, id, predicate string) time.Time {
+	var seen time.Time
+
+	if props, err := db.ReadProperties(ctx, netmap.Node(id), predicate); err == nil {
+		for _, p := range props {
+			if v, ok := p.Value.Native().(string); ok {
+				if t, err := time.Parse(time.RFC3339, v); err == nil {
+					seen = t
+				}
+			}
+		}
+	}
+	return seen
+}
@@ -0,0 +1,242 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/OWASP/Amass/v3/integrations"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/resolve"
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+	"github.com/miekg/dns"
+)
+
+const (
+	configUsageMsg         = "config convert|validate [options]"
+	configConvertUsageMsg  = "config convert [options] -i config.ini"
+	configValidateUsageMsg = "config validate [options] [-config path]"
+	configResolverTimeout  = 5 * time.Second
+)
+
+type configValidateArgs struct {
+	Options struct {
+		NoColor bool
+		Offline bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+	}
+}
+
+type configConvertArgs struct {
+	Options struct {
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		Input  string
+		Output string
+	}
+}
+
+func runConfigCommand(clArgs []string) {
+	configCommand := flag.NewFlagSet("config", flag.ContinueOnError)
+	configBuf := new(bytes.Buffer)
+	configCommand.SetOutput(configBuf)
+
+	if len(clArgs) < 1 {
+		commandUsage(configUsageMsg, configCommand, configBuf)
+		return
+	}
+	switch clArgs[0] {
+	case "convert":
+		runConfigConvertCommand(clArgs[1:])
+	case "validate":
+		runConfigValidateCommand(clArgs[1:])
+	default:
+		commandUsage(configUsageMsg, configCommand, configBuf)
+	}
+}
+
+func runConfigConvertCommand(clArgs []string) {
+	var args configConvertArgs
+	var help1, help2 bool
+	convertCommand := flag.NewFlagSet("convert", flag.ContinueOnError)
+
+	convertBuf := new(bytes.Buffer)
+	convertCommand.SetOutput(convertBuf)
+
+	convertCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	convertCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	convertCommand.StringVar(&args.Filepaths.Input, "i", "", "Path to the INI configuration file that will be converted")
+	convertCommand.StringVar(&args.Filepaths.Output, "o", "", "Path to the YAML configuration file, or standard output when not provided")
+	convertCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	convertCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+
+	if len(clArgs) < 1 {
+		commandUsage(configConvertUsageMsg, convertCommand, convertBuf)
+		return
+	}
+	if err := convertCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(configConvertUsageMsg, convertCommand, convertBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = ioutil.Discard
+		color.Error = ioutil.Discard
+	}
+	if args.Filepaths.Input == "" {
+		r.Fprintln(color.Error, "The INI configuration file must be provided using the -i flag")
+		os.Exit(1)
+	}
+	if config.IsYAMLFile(args.Filepaths.Input) {
+		r.Fprintf(color.Error, "The %s configuration file already uses the YAML format\n", args.Filepaths.Input)
+		os.Exit(1)
+	}
+
+	// The settings are checked, so the conversion does not produce a file Amass fails to load
+	if err := config.NewConfig().LoadSettings(args.Filepaths.Input); err != nil {
+		r.Fprintf(color.Error, "The INI configuration file is not valid: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := config.ConvertINIToYAML(args.Filepaths.Input)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	if args.Filepaths.Output == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := ioutil.WriteFile(args.Filepaths.Output, data, 0600); err != nil {
+		r.Fprintf(color.Error, "Failed to write the YAML configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	g.Fprintf(color.Error, "The settings of %s were written to %s\n", args.Filepaths.Input, args.Filepaths.Output)
+}
+
+func runConfigValidateCommand(clArgs []string) {
+	var args configValidateArgs
+	var help1, help2 bool
+	validateCommand := flag.NewFlagSet("validate", flag.ContinueOnError)
+
+	validateBuf := new(bytes.Buffer)
+	validateCommand.SetOutput(validateBuf)
+
+	validateCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	validateCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	validateCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
+	validateCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+	validateCommand.BoolVar(&args.Options.Offline, "offline", false, "Skip the checks requiring network access, such as the reachability of the resolvers")
+	validateCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	validateCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+
+	if err := validateCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(configValidateUsageMsg, validateCommand, validateBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = ioutil.Discard
+		color.Error = ioutil.Discard
+	}
+
+	path := config.ConfigFilePath(args.Filepaths.Directory, args.Filepaths.ConfigFile)
+	if path == "" {
+		r.Fprintln(color.Error, "No configuration file was provided or discovered")
+		os.Exit(1)
+	}
+
+	cfg, problems := config.ValidateSettings(path)
+	if cfg != nil {
+		cfg.Dir = args.Filepaths.Directory
+
+		if !args.Options.Offline {
+			if err := integrations.LoadSecrets(context.Background(), cfg); err != nil {
+				problems = append(problems, err)
+			}
+			problems = append(problems, checkConfigResolvers(cfg)...)
+		}
+		problems = append(problems, datasrcs.CheckDataSourceConfigs(&systems.SimpleSystem{Cfg: cfg})...)
+	}
+
+	if len(problems) == 0 {
+		g.Fprintf(color.Error, "The %s configuration file is valid\n", path)
+		return
+	}
+	r.Fprintf(color.Error, "The %s configuration file has the following problems:\n", path)
+	for _, p := range problems {
+		msg := p.Error()
+		r.Fprintf(color.Error, "  - %s%s\n", strings.ToUpper(msg[:1]), msg[1:])
+	}
+	os.Exit(1)
+}
+
+// Sends a query to each resolver of the configuration, including the resolvers of specific zones,
+// and returns the problems of the resolvers that provide no response.
+func checkConfigResolvers(cfg *config.Config) []error {
+	addrs := stringset.New(cfg.Resolvers...)
+	defer addrs.Close()
+	for _, list := range cfg.DomainResolvers {
+		addrs.InsertMany(list...)
+	}
+
+	var lock sync.Mutex
+	var problems []error
+	var wg sync.WaitGroup
+	for _, addr := range addrs.Slice() {
+		wg.Add(1)
+
+		go func(addr string) {
+			defer wg.Done()
+
+			res, err := resolvers.NewResolver(addr)
+			if err == nil {
+				res.SetTimeout(configResolverTimeout)
+				_, err = res.Exchange(context.Background(), resolve.QueryMsg("owasp.org", dns.TypeNS))
+				res.Stop()
+			}
+			if err != nil {
+				lock.Lock()
+				problems = append(problems, fmt.Errorf("the resolver %s is not reachable: %v", addr, err))
+				lock.Unlock()
+			}
+		}(addr)
+	}
+	wg.Wait()
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Error() < problems[j].Error() })
+	return problems
+}
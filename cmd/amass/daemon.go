@@ -0,0 +1,322 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/fatih/color"
+)
+
+const daemonUsageMsg = "daemon [options]"
+
+type daemonArgs struct {
+	Project string
+	Options struct {
+		List    bool
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+	}
+}
+
+// The enum subcommands running in other processes, which receive the reload requests.
+var enumProcs = struct {
+	sync.Mutex
+	procs map[*os.Process]struct{}
+}{procs: make(map[*os.Process]struct{})}
+
+// The daemon executes the scheduled enumerations as enum subcommands, one at a time,
+// since the enumerations share the graph database of the output directory.
+type daemon struct {
+	args *daemonArgs
+	exe  string
+	// Set when the configuration provides the webhooks or email settings notified of the changes
+	notify bool
+	// Held by the schedule executing its enumeration
+	sem chan struct{}
+}
+
+func runDaemonCommand(clArgs []string) {
+	var args daemonArgs
+	var help1, help2 bool
+	daemonCommand := flag.NewFlagSet("daemon", flag.ContinueOnError)
+
+	daemonBuf := new(bytes.Buffer)
+	daemonCommand.SetOutput(daemonBuf)
+
+	daemonCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	daemonCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	daemonCommand.BoolVar(&args.Options.List, "list", false, "Print the schedules and the time of their next enumeration")
+	daemonCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	daemonCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	daemonCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file providing the schedules")
+	daemonCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+	daemonCommand.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
+
+	if err := daemonCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(daemonUsageMsg, daemonCommand, daemonBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = ioutil.Discard
+		color.Error = ioutil.Discard
+	}
+
+	cfg := config.NewConfig()
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err != nil {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	if len(cfg.Schedules) == 0 {
+		r.Fprintln(color.Error, "No schedules were provided by the configuration")
+		os.Exit(1)
+	}
+	if args.Options.List {
+		now := time.Now()
+		for _, s := range cfg.Schedules {
+			next := "never"
+			if t := s.Spec.Next(now); !t.IsZero() {
+				next = t.Format(timeFormat)
+			}
+			fmt.Fprintf(color.Output, "%s%s %s%s %s%s\n", blue("Schedule: "), green(s.Name),
+				blue("Domains: "), yellow(strings.Join(s.Domains, ", ")), blue("Next: "), yellow(next))
+		}
+		return
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to find the amass executable: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Monitor for cancellation by the user
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(quit)
+
+		<-quit
+		cancel()
+	}()
+	// The following enumerations read the configuration file when they start
+	go reloadOnHangup(ctx)
+
+	d := &daemon{
+		args:   &args,
+		exe:    exe,
+		notify: len(cfg.Webhooks) > 0 || cfg.Email != nil,
+		sem:    make(chan struct{}, 1),
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range cfg.Schedules {
+		wg.Add(1)
+		go d.schedule(ctx, s, &wg)
+	}
+	wg.Wait()
+}
+
+// Executes the enumerations of the schedule until the daemon is stopped. The next enumeration is
+// scheduled once the previous one has finished, so the start times missed while running are skipped.
+func (d *daemon) schedule(ctx context.Context, s *config.Schedule, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		next := s.Spec.Next(time.Now())
+		if next.IsZero() {
+			r.Fprintf(color.Error, "The %s schedule does not match any time in the following years\n", s.Name)
+			return
+		}
+		g.Fprintf(color.Error, "The %s enumeration is scheduled for %s\n", s.Name, next.Format(timeFormat))
+
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case d.sem <- struct{}{}:
+		}
+		d.execute(ctx, s)
+		<-d.sem
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (d *daemon) execute(ctx context.Context, s *config.Schedule) {
+	start := time.Now()
+	g.Fprintf(color.Error, "Starting the %s enumeration of %s\n", s.Name, strings.Join(s.Domains, ", "))
+
+	err := runSubcommand(ctx, d.exe, d.enumArgs(s))
+	if ctx.Err() != nil {
+		r.Fprintf(color.Error, "The %s enumeration was interrupted\n", s.Name)
+		return
+	}
+	if err != nil {
+		r.Fprintf(color.Error, "The %s enumeration failed: %v\n", s.Name, err)
+		return
+	}
+	g.Fprintf(color.Error, "The %s enumeration finished after %s\n", s.Name, time.Since(start).Round(time.Second))
+	// Send the changes found by the enumeration, compared with the previous enumerations
+	if s.Notify && d.notify {
+		if err := runSubcommand(ctx, d.exe, append([]string{"track", "-notify", "-d", strings.Join(s.Domains, ",")}, d.commonArgs()...)); err != nil {
+			r.Fprintf(color.Error, "Failed to notify the changes of the %s enumeration: %v\n", s.Name, err)
+		}
+	}
+}
+
+func (d *daemon) enumArgs(s *config.Schedule) []string {
+	args := []string{"enum", "-d", strings.Join(s.Domains, ",")}
+
+	if s.Active {
+		args = append(args, "-active")
+	}
+	if s.Brute {
+		args = append(args, "-brute")
+	}
+	if s.Alts {
+		args = append(args, "-alts")
+	}
+	if s.Passive {
+		args = append(args, "-passive")
+	}
+	if s.Timeout > 0 {
+		args = append(args, "-timeout", strconv.Itoa(s.Timeout))
+	}
+	if s.Profile != "" {
+		args = append(args, "-profile", s.Profile)
+	}
+	return append(args, d.commonArgs()...)
+}
+
+// Returns the arguments selecting the configuration file and output directory of the daemon.
+func (d *daemon) commonArgs() []string {
+	var args []string
+
+	if d.args.Filepaths.ConfigFile != "" {
+		args = append(args, "-config", d.args.Filepaths.ConfigFile)
+	}
+	if d.args.Filepaths.Directory != "" {
+		args = append(args, "-dir", d.args.Filepaths.Directory)
+	}
+	if d.args.Project != "" {
+		args = append(args, "-project", d.args.Project)
+	}
+	if d.args.Options.NoColor {
+		args = append(args, "-nocolor")
+	}
+	return args
+}
+
+// Executes the amass subcommand in another process, which is interrupted when the context
+// is cancelled and allowed to finish saving its findings.
+func runSubcommand(ctx context.Context, exe string, args []string) error {
+	cmd := exec.Command(exe, args...)
+	// The names discovered are available from the output files and graph database
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = color.Error
+	cmd.SysProcAttr = daemonProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Only the enum subcommand handles the hangup signal, which terminates the other subcommands
+	if len(args) > 0 && args[0] == "enum" {
+		enumProcs.Lock()
+		enumProcs.procs[cmd.Process] = struct{}{}
+		enumProcs.Unlock()
+
+		defer func() {
+			enumProcs.Lock()
+			delete(enumProcs.procs, cmd.Process)
+			enumProcs.Unlock()
+		}()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		_ = cmd.Process.Signal(os.Interrupt)
+		return <-done
+	}
+}
+
+// Sends the hangup signal to the running enum subcommands, so they apply the configuration file again,
+// and returns the number of subcommands signaled.
+func reloadEnumProcs() (int, error) {
+	enumProcs.Lock()
+	defer enumProcs.Unlock()
+
+	var num int
+	for proc := range enumProcs.procs {
+		if err := proc.Signal(syscall.SIGHUP); err != nil {
+			return num, err
+		}
+		num++
+	}
+	return num, nil
+}
+
+// Forwards the hangup signals received to the running enum subcommands until the context is cancelled,
+// since the subcommands are placed in their own process group.
+func reloadOnHangup(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+
+		num, err := reloadEnumProcs()
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to reload the configuration of the running enumerations: %v\n", err)
+			continue
+		}
+		g.Fprintf(color.Error, "The configuration file is being reloaded by %d running enumerations\n", num)
+	}
+}
@@ -13,12 +13,19 @@ import (
 	"io"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
+	"github.com/OWASP/Amass/v3/api"
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/OWASP/Amass/v3/enum"
 	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/integrations"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/netmap"
@@ -31,30 +38,63 @@ const (
 )
 
 type dbArgs struct {
-	Domains *stringset.Set
-	Enum    int
-	Options struct {
+	Domains       *stringset.Set
+	Tags          *tagFilter
+	Enum          int
+	GraphQL       string
+	Maltego       string
+	NmapXML       string
+	PruneDays     int
+	PruneKeep     int
+	PurgeEnum     int
+	PurgeDomain   string
+	PurgeNames    string
+	Compact       int
+	Columns       string
+	TargetsFormat string
+	Search        string
+	Project       string
+	Options       struct {
 		DemoMode         bool
 		IPs              bool
 		IPv4             bool
 		IPv6             bool
 		ListEnumerations bool
+		Live             bool
 		ASNTableSummary  bool
+		Certificates     bool
+		Compact          bool
 		DiscoveredNames  bool
+		DryRun           bool
+		Glob             bool
 		NoColor          bool
+		Prune            bool
+		Push             bool
+		Projects         bool
 		ShowAll          bool
 		Silent           bool
 		Sources          bool
+		Takeovers        bool
 	}
 	Filepaths struct {
 		ConfigFile string
+		CSVOutput  string
 		Directory  string
 		Domains    string
 		JSONOutput string
+		MDOutput   string
+		STIXOutput string
+		Targets    string
 		TermOut    string
 	}
 }
 
+// Returns true when the findings are exported to one of the output files.
+func (a *dbArgs) exportOutput() bool {
+	return a.Options.Push || a.Filepaths.JSONOutput != "" || a.Filepaths.CSVOutput != "" ||
+		a.Filepaths.MDOutput != "" || a.Filepaths.STIXOutput != "" || a.Filepaths.Targets != ""
+}
+
 func runDBCommand(clArgs []string) {
 	var args dbArgs
 	var help1, help2 bool
@@ -64,26 +104,57 @@ func runDBCommand(clArgs []string) {
 	dbCommand.SetOutput(dbBuf)
 	args.Domains = stringset.New()
 	defer args.Domains.Close()
+	args.Tags = newTagFilter()
+	defer args.Tags.Close()
 
 	dbCommand.BoolVar(&help1, "h", false, "Show the program usage message")
 	dbCommand.BoolVar(&help2, "help", false, "Show the program usage message")
 	dbCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	dbCommand.BoolVar(&args.Options.Certificates, "certs", false, "Print the certificates observed for the domains, ordered by expiration")
+	dbCommand.BoolVar(&args.Options.Compact, "compact", false, "Merge the historical events of each scope into a single event")
+	dbCommand.IntVar(&args.Compact, "compact-keep", 1, "Number of the most recent events of each scope left intact by compaction")
+	dbCommand.StringVar(&args.Columns, "csv-columns", "", "Columns of the CSV output separated by commas (default: all)")
 	dbCommand.IntVar(&args.Enum, "enum", 0, "Identify an enumeration via an index from the listing")
+	dbCommand.Var(args.Tags.Exclude, "exclude-tags", "Exclude names and addresses with these tags")
+	dbCommand.BoolVar(&args.Options.Glob, "glob", false, "Interpret the search pattern as a glob using the '*' and '?' wildcards")
+	dbCommand.StringVar(&args.GraphQL, "graphql", "", "Serve the read-only GraphQL API on the address (e.g. 127.0.0.1:8080)")
+	dbCommand.StringVar(&args.Maltego, "maltego", "", "Serve the Maltego transforms on the address (e.g. 127.0.0.1:8081)")
+	dbCommand.StringVar(&args.NmapXML, "import-nmap", "", "Path to the nmap or masscan XML output providing the open ports of the addresses")
 	dbCommand.BoolVar(&args.Options.DemoMode, "demo", false, "Censor output to make it suitable for demonstrations")
+	dbCommand.Var(args.Tags.Include, "include-tags", "Only include names and addresses with these tags")
+	dbCommand.IntVar(&args.Tags.MinConfidence, "min-confidence", 0, "Only include names and addresses with at least this confidence (0-100)")
 	dbCommand.BoolVar(&args.Options.IPs, "ip", false, "Show the IP addresses for discovered names")
 	dbCommand.BoolVar(&args.Options.IPv4, "ipv4", false, "Show the IPv4 addresses for discovered names")
 	dbCommand.BoolVar(&args.Options.IPv6, "ipv6", false, "Show the IPv6 addresses for discovered names")
 	dbCommand.BoolVar(&args.Options.ListEnumerations, "list", false, "Numbered list of enums filtered on provided domains")
+	dbCommand.BoolVar(&args.Options.Live, "live", false, "Only include the names with web services responding to the probes")
 	dbCommand.BoolVar(&args.Options.Sources, "src", false, "Print data sources for the discovered names")
 	dbCommand.BoolVar(&args.Options.ASNTableSummary, "summary", false, "Print Just ASN Table Summary")
 	dbCommand.BoolVar(&args.Options.DiscoveredNames, "names", false, "Print Just Discovered Names")
 	dbCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	dbCommand.BoolVar(&args.Options.Prune, "prune", false, "Remove the events outside of the retention policy")
+	dbCommand.IntVar(&args.PruneDays, "prune-days", 0, "Prune the events that finished more than this number of days ago")
+	dbCommand.BoolVar(&args.Options.Projects, "projects", false, "Print the names of the projects in the output directory")
+	dbCommand.IntVar(&args.PruneKeep, "prune-keep", 0, "Prune all but this number of the most recent events for each domain")
+	dbCommand.IntVar(&args.PurgeEnum, "purge-enum", 0, "Remove the enumeration identified via an index from the listing")
+	dbCommand.StringVar(&args.PurgeDomain, "purge-domain", "", "Remove the enumerations and names of the domain")
+	dbCommand.StringVar(&args.PurgeNames, "purge-names", "", "Remove the stored names matching the regular expression")
+	dbCommand.BoolVar(&args.Options.DryRun, "dry-run", false, "List the data selected by the purge flags without removing it")
+	dbCommand.StringVar(&args.Search, "search", "", "Print the stored names matching the regular expression (e.g. 'vpn|citrix|owa')")
 	dbCommand.BoolVar(&args.Options.ShowAll, "show", false, "Print the results for the enumeration index + domains provided")
+	dbCommand.BoolVar(&args.Options.Takeovers, "takeovers", false, "Print the names aliased or delegated to services prone to subdomain takeovers")
+	dbCommand.BoolVar(&args.Options.Push, "push", false, "Push the findings into the integrations provided by the configuration")
 	dbCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
-	dbCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	dbCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
 	dbCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	dbCommand.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
 	dbCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
 	dbCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file")
+	dbCommand.StringVar(&args.Filepaths.MDOutput, "md", "", "Path to the Markdown report output file or '-'")
+	dbCommand.StringVar(&args.Filepaths.CSVOutput, "ocsv", "", "Path to the CSV output file or '-'")
+	dbCommand.StringVar(&args.Filepaths.STIXOutput, "stix", "", "Path to the STIX 2.1 bundle output file or '-'")
+	dbCommand.StringVar(&args.Filepaths.Targets, "targets", "", "Path to the nmap/masscan target list output file or '-'")
+	dbCommand.StringVar(&args.TargetsFormat, "targets-format", format.TargetsFormatNmap, "Format of the target list: nmap (addresses and names) or masscan (addresses)")
 	dbCommand.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
 
 	if len(clArgs) < 1 {
@@ -128,18 +199,74 @@ func runDBCommand(clArgs []string) {
 		os.Exit(1)
 	}
 
+	if args.Options.Projects {
+		for _, name := range config.ProjectNames(args.Filepaths.Directory) {
+			g.Println(name)
+		}
+		return
+	}
+
 	srcs := datasrcs.GetAllSources(&systems.LocalSystem{Cfg: cfg})
 	initializeSourceTags(srcs)
 	for _, src := range srcs {
 		_ = src.Stop()
 	}
 
+	args.Filepaths.Directory = projectDirectory(args.Filepaths.Directory, args.Project, cfg)
 	db := openGraphDatabase(args.Filepaths.Directory, cfg)
 	if db == nil {
 		r.Fprintln(color.Error, "Failed to connect with the database")
 		os.Exit(1)
 	}
 	defer db.Close()
+
+	if args.Options.Prune {
+		pruneDatabase(&args, cfg, db)
+		return
+	}
+	if args.purge() {
+		purgeDatabase(&args, cfg, db)
+		return
+	}
+	if args.Options.Compact {
+		num, err := compactEvents(context.Background(), db, args.Domains.Slice(), args.Compact)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to compact the database: %v\n", err)
+			os.Exit(1)
+		}
+		g.Printf("%d events were merged in the %s database\n", num, db.String())
+		return
+	}
+	if args.GraphQL != "" {
+		serveGraphQL(args.GraphQL, db)
+		return
+	}
+	if args.Maltego != "" {
+		serveMaltego(args.Maltego, db)
+		return
+	}
+	if args.NmapXML != "" {
+		importNmapFile(args.NmapXML, db)
+		return
+	}
+	if args.Search != "" {
+		re, err := searchRegexp(args.Search, args.Options.Glob)
+		if err != nil {
+			r.Fprintf(color.Error, "The search pattern is not valid: %v\n", err)
+			os.Exit(1)
+		}
+
+		var uuids []string
+		if args.Domains.Len() > 0 {
+			uuids = db.EventsInScope(context.Background(), args.Domains.Slice()...)
+			if len(uuids) == 0 {
+				r.Fprintln(color.Error, "Failed to find the domains of interest in the database")
+				os.Exit(1)
+			}
+		}
+		showSearchResults(searchNames(context.Background(), db, cfg, re, uuids))
+		return
+	}
 	// Create the in-memory graph database for events that have information in scope
 	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), db)
 	if err != nil {
@@ -157,7 +284,15 @@ func runDBCommand(clArgs []string) {
 		listEvents(uuids, memDB)
 		return
 	}
-	if args.Options.ShowAll || args.Filepaths.JSONOutput != "" {
+	if args.Options.Certificates {
+		showCertificates(uuids, args.Domains.Slice(), memDB)
+		return
+	}
+	if args.Options.Takeovers {
+		showTakeovers(uuids, args.Domains.Slice(), cfg, memDB)
+		return
+	}
+	if args.Options.ShowAll || args.exportOutput() {
 		args.Options.DiscoveredNames = true
 		args.Options.ASNTableSummary = true
 	}
@@ -166,7 +301,7 @@ func runDBCommand(clArgs []string) {
 		return
 	}
 	// Put the events in chronological order
-	uuids, _, _ = orderedEvents(context.Background(), uuids, memDB)
+	uuids, _, _ = enum.OrderedEvents(context.Background(), uuids, memDB)
 	if len(uuids) == 0 {
 		r.Fprintln(color.Error, "Failed to sort the events")
 		os.Exit(1)
@@ -183,11 +318,95 @@ func runDBCommand(clArgs []string) {
 		asninfo = true
 	}
 
-	showEventData(&args, uuids, asninfo, memDB)
+	var ins []integrations.Integration
+	if args.Options.Push {
+		if ins = integrations.NewIntegrations(cfg); len(ins) == 0 {
+			r.Fprintln(color.Error, "No integrations were provided by the configuration")
+			os.Exit(1)
+		}
+	}
+
+	showEventData(&args, uuids, asninfo, ins, memDB)
+}
+
+func pruneDatabase(args *dbArgs, cfg *config.Config, db *netmap.Graph) {
+	days, keep := cfg.RetentionDays, cfg.RetentionEvents
+	if args.PruneDays > 0 {
+		days = args.PruneDays
+	}
+	if args.PruneKeep > 0 {
+		keep = args.PruneKeep
+	}
+	if days <= 0 && keep <= 0 {
+		r.Fprintln(color.Error, "No retention policy was provided by the configuration or the -prune-days and -prune-keep flags")
+		os.Exit(1)
+	}
+
+	num, err := enum.PruneEvents(context.Background(), db, args.Domains.Slice(), days, keep)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to prune the database: %v\n", err)
+		os.Exit(1)
+	}
+	g.Printf("%d events were pruned from the %s database\n", num, db.String())
+}
+
+func serveGraphQL(addr string, db *netmap.Graph) {
+	h, err := api.NewGraphQLHandler(db)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to create the GraphQL API: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The GraphQL API requires the same key as the REST API of the api subcommand
+	if key := os.Getenv(apiKeyEnv); key != "" {
+		h = api.RequireAPIKey(key, h)
+	} else {
+		r.Fprintf(color.Error, "The GraphQL API does not require a key, since %s is not set\n", apiKeyEnv)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/graphql", h)
+
+	g.Fprintf(color.Error, "The GraphQL API for the %s database is available at http://%s/graphql\n", db.String(), addr)
+	if err := serveHTTP(addr, mux); err != nil {
+		r.Fprintf(color.Error, "The GraphQL API failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func serveMaltego(addr string, db *netmap.Graph) {
+	g.Fprintf(color.Error, "The Maltego transforms for the %s database are available at http://%s/run/\n", db.String(), addr)
+	if err := serveHTTP(addr, api.NewMaltegoHandler(db)); err != nil {
+		r.Fprintf(color.Error, "The Maltego transform server failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Serves the handler on the address until the user interrupts the program.
+func serveHTTP(addr string, h http.Handler) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// Stop serving when the user interrupts the program
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(quit)
+
+		<-quit
+		_ = srv.Shutdown(context.Background())
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
 func listEvents(uuids []string, db *netmap.Graph) {
-	events, earliest, latest := orderedEvents(context.Background(), uuids, db)
+	events, earliest, latest := enum.OrderedEvents(context.Background(), uuids, db)
 	// Check if the user has requested the list of enumerations
 	for pos, idx := 0, len(events)-1; idx >= 0; idx-- {
 		if pos != 0 {
@@ -207,7 +426,7 @@ func listEvents(uuids []string, db *netmap.Graph) {
 	}
 }
 
-func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph) {
+func showEventData(args *dbArgs, uuids []string, asninfo bool, ins []integrations.Integration, db *netmap.Graph) {
 	var total int
 	var err error
 	var outfile *os.File
@@ -239,11 +458,15 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 
 	tags := make(map[string]int)
 	asns := make(map[int]*format.ASNSummaryData)
-	for _, out := range getEventOutput(context.Background(), uuids, asninfo, db, cache) {
+	for _, out := range getEventOutput(context.Background(), uuids, asninfo, args.Tags, db, cache) {
 		if len(domains) > 0 && !domainNameInScope(out.Name, domains) {
 			continue
 		}
 
+		if args.Options.Live && len(out.HTTP) == 0 {
+			continue
+		}
+
 		out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
 		if l := len(out.Addresses); (args.Options.IPs || args.Options.IPv4 || args.Options.IPv6) && l == 0 {
 			continue
@@ -264,7 +487,7 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 				fmt.Fprintf(outfile, "%s%s%s\n", source, name, ips)
 				written = true
 			}
-			if args.Filepaths.JSONOutput != "" {
+			if args.exportOutput() {
 				discovered = append(discovered, out)
 				written = true
 			}
@@ -278,9 +501,32 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 		r.Println("No names were discovered")
 		return
 	}
+	if args.Filepaths.CSVOutput != "" {
+		if err := writeCSV(args, uuids, discovered, db); err != nil {
+			r.Fprintf(color.Error, "Failed to write the CSV output: %v\n", err)
+		}
+	}
+	if len(ins) > 0 {
+		pushFindings(context.Background(), ins, discovered)
+	}
+	if args.Filepaths.MDOutput != "" {
+		if err := writeMarkdown(args, uuids, discovered, db); err != nil {
+			r.Fprintf(color.Error, "Failed to write the Markdown report: %v\n", err)
+		}
+	}
+	if args.Filepaths.STIXOutput != "" {
+		if err := writeSTIX(args.Filepaths.STIXOutput, discovered); err != nil {
+			r.Fprintf(color.Error, "Failed to write the STIX output: %v\n", err)
+		}
+	}
+	if args.Filepaths.Targets != "" {
+		if err := writeScanTargets(args, discovered); err != nil {
+			r.Fprintf(color.Error, "Failed to write the scan targets: %v\n", err)
+		}
+	}
 	if args.Filepaths.JSONOutput != "" {
 		writeJSON(args, uuids, discovered, db)
-	} else if args.Options.ASNTableSummary {
+	} else if !args.exportOutput() && args.Options.ASNTableSummary {
 		var out io.Writer
 		status := color.NoColor
 
@@ -294,10 +540,35 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 		}
 
 		format.FprintEnumerationSummary(out, total, tags, asns, args.Options.DemoMode)
+		format.FprintSourceStats(out, eventSourceStats(context.Background(), uuids, db))
 		color.NoColor = status
 	}
 }
 
+func eventSourceStats(ctx context.Context, uuids []string, db *netmap.Graph) []*requests.SourceStats {
+	var lists [][]*requests.SourceStats
+
+	for _, uuid := range uuids {
+		props, err := db.ReadProperties(ctx, netmap.Node(uuid), enum.SourceStatsPredicate)
+		if err != nil {
+			continue
+		}
+
+		for _, p := range props {
+			data, ok := p.Value.Native().(string)
+			if !ok {
+				continue
+			}
+
+			var stats []*requests.SourceStats
+			if err := json.Unmarshal([]byte(data), &stats); err == nil {
+				lists = append(lists, stats)
+			}
+		}
+	}
+	return requests.MergeSourceStats(lists...)
+}
+
 type jsonEvent struct {
 	UUID   string `json:"uuid"`
 	Start  string `json:"start"`
@@ -319,7 +590,7 @@ func writeJSON(args *dbArgs, uuids []string, assets []*requests.Output, db *netm
 	var output jsonOutput
 
 	// Add the event data to the JSON
-	events, earliest, latest := orderedEvents(context.Background(), uuids, db)
+	events, earliest, latest := enum.OrderedEvents(context.Background(), uuids, db)
 	for i, uuid := range events {
 		output.Events = append(output.Events, &jsonEvent{
 			UUID:   uuid,
@@ -371,6 +642,129 @@ func writeJSON(args *dbArgs, uuids []string, assets []*requests.Output, db *netm
 	_ = jsonptr.Close()
 }
 
+func writeSTIX(path string, assets []*requests.Output) error {
+	bundle := format.STIXOutput(assets, time.Now())
+	// Write to STDOUT and not a file if named "-"
+	if path == "-" {
+		return format.WriteSTIXJSON(os.Stdout, bundle)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Sync()
+		_ = f.Close()
+	}()
+
+	return format.WriteSTIXJSON(f, bundle)
+}
+
+// Writes the report of the findings, including the changes since the enumerations that preceded the latest
+// of the selected events.
+func writeMarkdown(args *dbArgs, uuids []string, assets []*requests.Output, db *netmap.Graph) error {
+	ctx := context.Background()
+	rep := &format.MarkdownReport{
+		Title:     "OWASP Amass Report",
+		Generated: time.Now(),
+		Output:    assets,
+	}
+
+	// The selected events are already in chronological order
+	first, _ := enum.EventDateRange(ctx, db, uuids[0])
+	_, last := enum.EventDateRange(ctx, db, uuids[len(uuids)-1])
+	rep.Period = first.Format(timeFormat) + " -> " + last.Format(timeFormat)
+
+	events, earliest, latest := enum.OrderedEvents(ctx, db.EventList(ctx), db)
+	for i, uuid := range events {
+		if i == 0 || uuid != uuids[len(uuids)-1] {
+			continue
+		}
+
+		// The infrastructure details are not required for comparing the addresses
+		domains := args.Domains.Slice()
+		rep.Diff = format.DiffOutput(
+			getScopedOutput(events[:i], domains, args.Tags, db, nil),
+			getScopedOutput([]string{uuid}, domains, args.Tags, db, nil),
+		)
+		rep.Diff.From = earliest[0].Format(timeFormat) + " -> " + latest[i-1].Format(timeFormat)
+		break
+	}
+
+	var out io.Writer = os.Stdout
+	// Write to STDOUT and not a file if named "-"
+	if args.Filepaths.MDOutput != "-" {
+		f, err := os.OpenFile(args.Filepaths.MDOutput, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Sync()
+			_ = f.Close()
+		}()
+		out = f
+	}
+
+	return format.WriteMarkdownReport(out, rep)
+}
+
+func writeCSV(args *dbArgs, uuids []string, assets []*requests.Output, db *netmap.Graph) error {
+	columns, err := format.ParseCSVColumns(args.Columns)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	// Write to STDOUT and not a file if named "-"
+	if args.Filepaths.CSVOutput != "-" {
+		f, err := os.OpenFile(args.Filepaths.CSVOutput, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Sync()
+			_ = f.Close()
+		}()
+		out = f
+	}
+
+	w, err := format.NewCSVWriter(out, columns)
+	if err != nil {
+		return err
+	}
+
+	first := namesFirstSeen(context.Background(), uuids, db)
+	for _, asset := range assets {
+		if err := w.Write(asset, first[asset.Name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the time each name was first seen, based on the events in chronological
+// order and the times preserved for the names when the events were compacted.
+func namesFirstSeen(ctx context.Context, uuids []string, db *netmap.Graph) map[string]time.Time {
+	first := make(map[string]time.Time)
+
+	for _, uuid := range uuids {
+		start, _ := enum.EventDateRange(ctx, db, uuid)
+
+		for _, name := range db.EventFQDNs(ctx, uuid) {
+			if t, found := first[name]; !found || start.Before(t) {
+				first[name] = start
+			}
+		}
+	}
+	for name, t := range first {
+		if seen := seenTime(ctx, db, name, requests.FirstSeenPredicate); !seen.IsZero() && seen.Before(t) {
+			first[name] = seen
+		}
+	}
+	return first
+}
+
 func fillCache(cache *requests.ASNCache, db *netmap.Graph) error {
 	aslist, err := db.AllNodesOfType(context.Background(), netmap.TypeAS)
 	if err != nil {
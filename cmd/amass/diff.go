@@ -0,0 +1,270 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+)
+
+const diffUsageMsg = "diff [options] -d domain"
+
+type diffArgs struct {
+	Domains *stringset.Set
+	Tags    *tagFilter
+	From    int
+	To      int
+	Project string
+	Options struct {
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Directory2 string
+		Domains    string
+		CSVOutput  string
+		JSONOutput string
+	}
+}
+
+func runDiffCommand(clArgs []string) {
+	var args diffArgs
+	var help1, help2 bool
+	diffCommand := flag.NewFlagSet("diff", flag.ContinueOnError)
+
+	args.Domains = stringset.New()
+	defer args.Domains.Close()
+	args.Tags = newTagFilter()
+	defer args.Tags.Close()
+
+	diffBuf := new(bytes.Buffer)
+	diffCommand.SetOutput(diffBuf)
+
+	diffCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	diffCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	diffCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	diffCommand.Var(args.Tags.Exclude, "exclude-tags", "Exclude names and addresses with these tags")
+	diffCommand.Var(args.Tags.Include, "include-tags", "Only include names and addresses with these tags")
+	diffCommand.IntVar(&args.Tags.MinConfidence, "min-confidence", 0, "Only include names and addresses with at least this confidence (0-100)")
+	diffCommand.IntVar(&args.From, "from", 2, "Index of the older enumeration from the listing")
+	diffCommand.IntVar(&args.To, "to", 1, "Index of the newer enumeration from the listing")
+	diffCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	diffCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	diffCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
+	diffCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	diffCommand.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
+	diffCommand.StringVar(&args.Filepaths.Directory2, "dir2", "", "Path to the directory containing the graph database to compare with")
+	diffCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	diffCommand.StringVar(&args.Filepaths.CSVOutput, "csv", "", "Path to the CSV output file or '-'")
+	diffCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file or '-'")
+
+	if len(clArgs) < 1 {
+		commandUsage(diffUsageMsg, diffCommand, diffBuf)
+		return
+	}
+	if err := diffCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(diffUsageMsg, diffCommand, diffBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = ioutil.Discard
+		color.Error = ioutil.Discard
+	}
+	if args.From < 1 || args.To < 1 {
+		r.Fprintln(color.Error, "The enumeration indexes start at one")
+		os.Exit(1)
+	}
+	if args.Filepaths.Domains != "" {
+		list, err := config.GetListFromFile(args.Filepaths.Domains)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the domain names file: %v\n", err)
+			os.Exit(1)
+		}
+		args.Domains.InsertMany(list...)
+	}
+
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+		if args.Domains.Len() == 0 {
+			args.Domains.InsertMany(cfg.Domains()...)
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	if args.Domains.Len() == 0 {
+		r.Fprintln(color.Error, "No root domain names were provided")
+		os.Exit(1)
+	}
+
+	args.Filepaths.Directory = projectDirectory(args.Filepaths.Directory, args.Project, cfg)
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		r.Fprintln(color.Error, "Failed to connect with the database")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	var diff *format.Diff
+	domains := args.Domains.Slice()
+	if args.Filepaths.Directory2 != "" {
+		// The second database is always the local database in the provided directory
+		db2 := openGraphDatabase(args.Filepaths.Directory2, config.NewConfig())
+		if db2 == nil {
+			r.Fprintln(color.Error, "Failed to connect with the second database")
+			os.Exit(1)
+		}
+		defer db2.Close()
+
+		diff = diffDatabases(&args, domains, db, db2)
+		diff.From = db.String() + " (" + args.Filepaths.Directory + ")"
+		diff.To = db2.String() + " (" + args.Filepaths.Directory2 + ")"
+	} else {
+		diff = diffEvents(&args, domains, db)
+	}
+
+	if err := writeDiff(args.Filepaths.JSONOutput, diff, format.WriteDiffJSON); err != nil {
+		r.Fprintf(color.Error, "Failed to write the JSON output: %v\n", err)
+		os.Exit(1)
+	}
+	if err := writeDiff(args.Filepaths.CSVOutput, diff, format.WriteDiffCSV); err != nil {
+		r.Fprintf(color.Error, "Failed to write the CSV output: %v\n", err)
+		os.Exit(1)
+	}
+	// The terminal output is not mixed with a document written to stdout
+	if args.Filepaths.JSONOutput != "-" && args.Filepaths.CSVOutput != "-" {
+		printDiff(diff)
+	}
+}
+
+// Compares two of the enumerations in scope, identified by their index in the listing.
+func diffEvents(args *diffArgs, domains []string, db *netmap.Graph) *format.Diff {
+	memDB, err := memGraphForScope(context.Background(), domains, db)
+	if err != nil {
+		r.Fprintln(color.Error, err.Error())
+		os.Exit(1)
+	}
+	defer memDB.Close()
+
+	uuids := memDB.EventsInScope(context.Background(), domains...)
+	if len(uuids) == 0 {
+		r.Fprintln(color.Error, "Failed to find the domains of interest in the database")
+		os.Exit(1)
+	}
+
+	uuids, earliest, latest := enum.OrderedEvents(context.Background(), uuids, memDB)
+	if args.From > len(uuids) || args.To > len(uuids) {
+		r.Fprintf(color.Error, "%d enumerations are available\n", len(uuids))
+		os.Exit(1)
+	}
+
+	from := len(uuids) - args.From
+	to := len(uuids) - args.To
+	cache := cacheWithData()
+	diff := format.DiffOutput(
+		getScopedOutput([]string{uuids[from]}, domains, args.Tags, memDB, cache),
+		getScopedOutput([]string{uuids[to]}, domains, args.Tags, memDB, cache),
+	)
+
+	diff.From = earliest[from].Format(timeFormat) + " -> " + latest[from].Format(timeFormat)
+	diff.To = earliest[to].Format(timeFormat) + " -> " + latest[to].Format(timeFormat)
+	return diff
+}
+
+// Compares the findings in scope across all the enumerations stored in each of the databases.
+func diffDatabases(args *diffArgs, domains []string, db1, db2 *netmap.Graph) *format.Diff {
+	var output [][]*requests.Output
+
+	cache := cacheWithData()
+	for _, db := range []*netmap.Graph{db1, db2} {
+		memDB, err := memGraphForScope(context.Background(), domains, db)
+		if err != nil {
+			r.Fprintln(color.Error, err.Error())
+			os.Exit(1)
+		}
+
+		uuids := memDB.EventsInScope(context.Background(), domains...)
+		uuids, _, _ = enum.OrderedEvents(context.Background(), uuids, memDB)
+		output = append(output, getScopedOutput(uuids, domains, args.Tags, memDB, cache))
+		memDB.Close()
+	}
+
+	return format.DiffOutput(output[0], output[1])
+}
+
+func writeDiff(path string, diff *format.Diff, write func(io.Writer, *format.Diff) error) error {
+	if path == "" {
+		return nil
+	}
+	if path == "-" {
+		return write(os.Stdout, diff)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Sync()
+		_ = f.Close()
+	}()
+
+	return write(f, diff)
+}
+
+func printDiff(diff *format.Diff) {
+	blueLine()
+	fmt.Fprintf(color.Output, "%s\t%s\n%s\t%s\n", blue("Between"), yellow(diff.From), blue("and"), yellow(diff.To))
+	blueLine()
+
+	if len(diff.Changes) == 0 {
+		g.Println("No differences discovered")
+		return
+	}
+
+	for _, c := range diff.Changes {
+		switch c.Change {
+		case format.DiffAdded:
+			fmt.Fprintf(color.Output, "%s%s %s\n", blue("Added: "), green(c.Name), yellow(strings.Join(c.Addresses, ",")))
+		case format.DiffRemoved:
+			fmt.Fprintf(color.Output, "%s%s %s\n", blue("Removed: "), green(c.Name), yellow(strings.Join(c.Addresses, ",")))
+		case format.DiffChanged:
+			fmt.Fprintf(color.Output, "%s%s\n", blue("Changed: "), green(c.Name))
+			if len(c.AddedAddresses) > 0 {
+				fmt.Fprintf(color.Output, "\t%s\t%s\n", blue("added"), yellow(strings.Join(c.AddedAddresses, ",")))
+			}
+			if len(c.RemovedAddresses) > 0 {
+				fmt.Fprintf(color.Output, "\t%s\t%s\n", blue("removed"), yellow(strings.Join(c.RemovedAddresses, ",")))
+			}
+		}
+	}
+}
@@ -0,0 +1,513 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/OWASP/Amass/v3/api"
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	distributeUsageMsg = "distribute [options] -workers ADDR,ADDR -d domain"
+	// The time before watching the worker enumeration again after the connection failed
+	distributeInterval = 15 * time.Second
+	// The time allowed for the stopped worker enumerations to end, and for obtaining their graph data
+	distributeEndTimeout = time.Minute
+)
+
+type distributeArgs struct {
+	Domains *stringset.Set
+	Workers format.ParseStrings
+	ASNs    format.ParseInts
+	CIDRs   format.ParseCIDRs
+	Timeout int
+	Profile string
+	Project string
+	Options struct {
+		Active      bool
+		Alterations bool
+		BruteForce  bool
+		IPs         bool
+		NoColor     bool
+		Passive     bool
+		Silent      bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Domains    string
+		JSONOutput string
+	}
+}
+
+// The enumeration assigned to one of the workers.
+type workerEnum struct {
+	client  *api.GRPCClient
+	req     *api.EnumRequest
+	status  *api.EnumStatus
+	results []*requests.Output
+	// The N-Quads of the graph data stored by the enumeration
+	graph []byte
+	err   error
+}
+
+func runDistributeCommand(clArgs []string) {
+	var args distributeArgs
+	var help1, help2 bool
+	distCommand := flag.NewFlagSet("distribute", flag.ContinueOnError)
+
+	args.Domains = stringset.New()
+	defer args.Domains.Close()
+
+	distBuf := new(bytes.Buffer)
+	distCommand.SetOutput(distBuf)
+
+	distCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	distCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	distCommand.Var(&args.Workers, "workers", "Addresses of the gRPC APIs served by the workers, separated by commas")
+	distCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	distCommand.Var(&args.ASNs, "asn", "ASNs separated by commas (can be used multiple times)")
+	distCommand.Var(&args.CIDRs, "cidr", "CIDRs separated by commas (can be used multiple times)")
+	distCommand.IntVar(&args.Timeout, "timeout", 0, "Number of minutes to let the worker enumerations run before quitting")
+	distCommand.StringVar(&args.Profile, "profile", "", "Name of the profile in the configuration file of the workers")
+	distCommand.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
+	distCommand.BoolVar(&args.Options.Active, "active", false, "Attempt zone transfers and certificate name grabs")
+	distCommand.BoolVar(&args.Options.Alterations, "alts", false, "Enable generation of altered names")
+	distCommand.BoolVar(&args.Options.BruteForce, "brute", false, "Execute brute forcing after searches")
+	distCommand.BoolVar(&args.Options.IPs, "ip", false, "Show the IP addresses for discovered names")
+	distCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	distCommand.BoolVar(&args.Options.Passive, "passive", false, "Disable DNS resolution of names and dependent features")
+	distCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	distCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
+	distCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+	distCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	distCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file of the merged findings")
+
+	if len(clArgs) < 1 {
+		commandUsage(distributeUsageMsg, distCommand, distBuf)
+		return
+	}
+	if err := distCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(distributeUsageMsg, distCommand, distBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = ioutil.Discard
+		color.Error = ioutil.Discard
+	}
+	if args.Filepaths.Domains != "" {
+		list, err := config.GetListFromFile(args.Filepaths.Domains)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the domain names file: %v\n", err)
+			os.Exit(1)
+		}
+		args.Domains.InsertMany(list...)
+	}
+	if len(args.Workers) == 0 {
+		r.Fprintln(color.Error, "The workers must be provided using the workers flag")
+		os.Exit(1)
+	}
+	if args.Options.Passive && (args.Options.Active || args.Options.BruteForce || args.Options.Alterations) {
+		r.Fprintln(color.Error, "The passive enumeration cannot use active, brute or alterations")
+		os.Exit(1)
+	}
+
+	cfg := config.NewConfig()
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	if args.Domains.Len() == 0 {
+		args.Domains.InsertMany(cfg.Domains()...)
+	}
+	if args.Domains.Len() == 0 {
+		r.Fprintln(color.Error, "No root domain names were provided")
+		os.Exit(1)
+	}
+
+	req := newDistributedRequest(&args, cfg)
+	clients := make([]*api.GRPCClient, 0, len(args.Workers))
+	for _, addr := range args.Workers {
+		c, err := api.NewGRPCClient(addr, os.Getenv(apiKeyEnv))
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to connect with the worker %s: %v\n", addr, err)
+			os.Exit(1)
+		}
+		defer c.Close()
+		clients = append(clients, c)
+	}
+
+	cfg.Dir = args.Filepaths.Directory
+	if project := args.Project; project != "" || cfg.Project != "" {
+		if project == "" {
+			project = cfg.Project
+		}
+		dir, err := config.ProjectDirectory(cfg.Dir, project)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to select the project: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.Dir = dir
+	}
+	createOutputDirectory(cfg)
+
+	db := openGraphDatabase(cfg.Dir, cfg)
+	if db == nil {
+		r.Fprintln(color.Error, "Failed to connect with the database")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The worker enumerations are stopped when the user interrupts the program
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(quit)
+
+		select {
+		case <-quit:
+			r.Fprintln(color.Error, "Stopping the worker enumerations")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	shards := api.ShardEnumRequest(req, len(clients))
+	workers := make([]*workerEnum, len(shards))
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		workers[i] = &workerEnum{client: clients[i], req: shard}
+
+		wg.Add(1)
+		go workers[i].run(ctx, &wg)
+	}
+	wg.Wait()
+
+	findings := mergeWorkerResults(workers)
+	if len(findings) == 0 {
+		r.Println("No names were discovered")
+		if failedWorkers(workers) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	id := uuid.New().String()
+	if err := storeWorkerGraphs(context.Background(), db, id, workers); err != nil {
+		r.Fprintf(color.Error, "Failed to store the graph data in the database: %v\n", err)
+		os.Exit(1)
+	}
+	for _, o := range findings {
+		source, name, ips := format.OutputLineParts(o, false, args.Options.IPs, false)
+		if ips != "" {
+			ips = " " + ips
+		}
+		fmt.Fprintf(color.Output, "%s%s%s\n", blue(source), green(name), yellow(ips))
+	}
+	if args.Filepaths.JSONOutput != "" {
+		if err := writeDistributedJSON(args.Filepaths.JSONOutput, findings); err != nil {
+			r.Fprintf(color.Error, "Failed to write the JSON output file: %v\n", err)
+		}
+	}
+
+	g.Fprintf(color.Error, "The %d names discovered by %d workers were merged into the enumeration %s\n",
+		len(findings), len(workers), id)
+	if failedWorkers(workers) > 0 {
+		os.Exit(1)
+	}
+}
+
+func newDistributedRequest(args *distributeArgs, cfg *config.Config) *api.EnumRequest {
+	req := &api.EnumRequest{
+		Domains:     args.Domains.Slice(),
+		Active:      args.Options.Active,
+		Brute:       args.Options.BruteForce,
+		Alterations: args.Options.Alterations,
+		Passive:     args.Options.Passive,
+		Timeout:     args.Timeout,
+		Profile:     args.Profile,
+		ASNs:        args.ASNs,
+	}
+	sort.Strings(req.Domains)
+	if len(req.ASNs) == 0 {
+		req.ASNs = cfg.ASNs
+	}
+
+	cidrs := args.CIDRs
+	if len(cidrs) == 0 {
+		cidrs = cfg.CIDRs
+	}
+	for _, cidr := range cidrs {
+		req.CIDRs = append(req.CIDRs, cidr.String())
+	}
+	return req
+}
+
+// Starts the enumeration on the worker and watches it until it ends, before obtaining its graph data.
+func (w *workerEnum) run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if w.status, w.err = w.client.Start(ctx, w.req); w.err != nil {
+		r.Fprintf(color.Error, "Failed to start the enumeration on %s: %v\n", w.client.Addr, w.err)
+		return
+	}
+	g.Fprintf(color.Error, "The worker %s started the enumeration of %s\n", w.client.Addr, w.describe())
+
+	w.watch(ctx)
+	if ctx.Err() != nil && !w.status.Terminal() {
+		// The stopped enumeration saves the findings and graph data it has discovered
+		if _, err := w.client.Stop(context.Background(), w.status.ID); err != nil {
+			r.Fprintf(color.Error, "Failed to stop the enumeration on %s: %v\n", w.client.Addr, err)
+		}
+
+		stopCtx, cancel := context.WithTimeout(context.Background(), distributeEndTimeout)
+		defer cancel()
+		w.watch(stopCtx)
+	}
+	g.Fprintf(color.Error, "The worker %s discovered %d names\n", w.client.Addr, len(w.results))
+
+	switch {
+	case w.err != nil:
+		return
+	case !w.status.Terminal():
+		w.err = errors.New("the enumeration did not end")
+	case w.status.State == api.EnumFailed:
+		w.err = fmt.Errorf("the enumeration failed: %s", w.status.Error)
+	}
+	if w.err != nil {
+		r.Fprintf(color.Error, "The worker %s %v\n", w.client.Addr, w.err)
+		return
+	}
+	w.collectGraph()
+}
+
+// Receives the findings of the enumeration until it ends, watching it again when the connection fails.
+func (w *workerEnum) watch(ctx context.Context) {
+	for {
+		var results []*requests.Output
+		st, err := w.client.Watch(ctx, w.status.ID, func(o *requests.Output) {
+			results = append(results, o)
+		})
+		// Each watch sends the findings starting from the first one
+		if len(results) >= len(w.results) {
+			w.results = results
+		}
+		if st != nil {
+			w.status = st
+		}
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if status.Code(err) == codes.NotFound {
+			w.err = err
+			return
+		}
+
+		r.Fprintf(color.Error, "Failed to watch the enumeration on %s: %v\n", w.client.Addr, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(distributeInterval):
+		}
+	}
+}
+
+func (w *workerEnum) collectGraph() {
+	ctx, cancel := context.WithTimeout(context.Background(), distributeEndTimeout)
+	defer cancel()
+
+	var buf bytes.Buffer
+	if err := w.client.Graph(ctx, w.status.ID, &buf); err != nil {
+		r.Fprintf(color.Error, "Failed to obtain the graph data of the enumeration on %s, so only its findings are stored: %v\n", w.client.Addr, err)
+		return
+	}
+	w.graph = buf.Bytes()
+}
+
+func (w *workerEnum) describe() string {
+	desc := strings.Join(w.req.Domains, ", ")
+	if w.req.BruteShard != "" {
+		desc += " using the brute forcing shard " + w.req.BruteShard
+	}
+	if n := len(w.req.CIDRs) + len(w.req.ASNs); n > 0 {
+		desc += fmt.Sprintf(" and %d netblocks", n)
+	}
+	return desc
+}
+
+func failedWorkers(workers []*workerEnum) int {
+	var failed int
+
+	for _, w := range workers {
+		if w.err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Returns the findings of the workers, where the findings of the same name are combined.
+func mergeWorkerResults(workers []*workerEnum) []*requests.Output {
+	byName := make(map[string]*requests.Output)
+
+	for _, w := range workers {
+		for _, o := range w.results {
+			cur, found := byName[o.Name]
+			if !found {
+				byName[o.Name] = o
+				continue
+			}
+
+			for _, src := range o.Sources {
+				if !containsString(cur.Sources, src) {
+					cur.Sources = append(cur.Sources, src)
+				}
+			}
+			for _, a := range o.Addresses {
+				if !containsAddress(cur.Addresses, a) {
+					cur.Addresses = append(cur.Addresses, a)
+				}
+			}
+		}
+	}
+
+	findings := make([]*requests.Output, 0, len(byName))
+	for _, o := range byName {
+		findings = append(findings, o)
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Name < findings[j].Name })
+	return findings
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAddress(addrs []requests.AddressInfo, a requests.AddressInfo) bool {
+	for _, cur := range addrs {
+		if cur.Address.Equal(a.Address) {
+			return true
+		}
+	}
+	return false
+}
+
+// Stores the graph data of the workers in the graph database as a single enumeration, keeping every
+// node and edge of the worker enumerations. The findings of the workers that did not provide their
+// graph data are stored instead.
+func storeWorkerGraphs(ctx context.Context, db *netmap.Graph, id string, workers []*workerEnum) error {
+	var findings []*requests.Output
+
+	for _, w := range workers {
+		if len(w.graph) == 0 {
+			findings = append(findings, w.results...)
+			continue
+		}
+		if err := enum.ImportEvent(ctx, db, id, bytes.NewReader(w.graph)); err != nil {
+			return fmt.Errorf("failed to merge the graph data of %s: %v", w.client.Addr, err)
+		}
+	}
+	return storeDistributedFindings(ctx, db, id, findings)
+}
+
+// Stores the findings in the graph database as part of the enumeration.
+func storeDistributedFindings(ctx context.Context, db *netmap.Graph, id string, findings []*requests.Output) error {
+	if _, err := db.UpsertEvent(ctx, id); err != nil {
+		return err
+	}
+
+	for _, o := range findings {
+		sources := o.Sources
+		if len(sources) == 0 {
+			sources = []string{"DNS"}
+		}
+		for _, src := range sources {
+			if _, err := db.UpsertFQDN(ctx, o.Name, src, id); err != nil {
+				return err
+			}
+		}
+
+		for _, a := range o.Addresses {
+			addr := a.Address.String()
+
+			var err error
+			if a.Address.To4() != nil {
+				err = db.UpsertA(ctx, o.Name, addr, "DNS", id)
+			} else {
+				err = db.UpsertAAAA(ctx, o.Name, addr, "DNS", id)
+			}
+			if err != nil {
+				return err
+			}
+			if a.CIDRStr != "" {
+				if err := db.UpsertInfrastructure(ctx, a.ASN, a.Description, addr, a.CIDRStr, "RIR", id); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Update the time the enumeration finished
+	_, err := db.UpsertEvent(ctx, id)
+	return err
+}
+
+func writeDistributedJSON(path string, findings []*requests.Output) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, o := range findings {
+		if err := enc.Encode(o); err != nil {
+			return err
+		}
+	}
+	return f.Sync()
+}
@@ -0,0 +1,239 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+)
+
+const (
+	dnsUsageMsg          = "dns benchmark [options] -rf path"
+	defaultBenchParallel = 25
+	defaultBenchMinRate  = 0.9
+)
+
+type dnsBenchmarkArgs struct {
+	Resolvers   *stringset.Set
+	Trusted     *stringset.Set
+	Names       *stringset.Set
+	Queries     int
+	Concurrency int
+	Parallel    int
+	Timeout     int
+	MinRate     float64
+	Options     struct {
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		Output    string
+		Resolvers format.ParseStrings
+	}
+}
+
+func runDNSCommand(clArgs []string) {
+	dnsCommand := flag.NewFlagSet("dns", flag.ContinueOnError)
+	dnsBuf := new(bytes.Buffer)
+	dnsCommand.SetOutput(dnsBuf)
+
+	if len(clArgs) < 1 || clArgs[0] != "benchmark" {
+		commandUsage(dnsUsageMsg, dnsCommand, dnsBuf)
+		return
+	}
+	runDNSBenchmarkCommand(clArgs[1:])
+}
+
+func runDNSBenchmarkCommand(clArgs []string) {
+	var args dnsBenchmarkArgs
+	var help1, help2 bool
+	benchCommand := flag.NewFlagSet("benchmark", flag.ContinueOnError)
+
+	args.Resolvers = stringset.New()
+	defer args.Resolvers.Close()
+	args.Trusted = stringset.New()
+	defer args.Trusted.Close()
+	args.Names = stringset.New()
+	defer args.Names.Close()
+
+	benchBuf := new(bytes.Buffer)
+	benchCommand.SetOutput(benchBuf)
+
+	opts := resolvers.DefaultBenchmarkOptions()
+	benchCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	benchCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	benchCommand.Var(args.Resolvers, "r", "IP addresses of the DNS resolvers to benchmark (can be used multiple times)")
+	benchCommand.Var(&args.Filepaths.Resolvers, "rf", "Path to a file providing the DNS resolvers to benchmark")
+	benchCommand.Var(args.Trusted, "tr", "IP addresses of the trusted DNS resolvers providing the reference answers")
+	benchCommand.Var(args.Names, "name", "Names expected to resolve from any location, used to detect false answers")
+	benchCommand.IntVar(&args.Queries, "queries", opts.Queries, "Number of queries for nonexistent names sent to each resolver")
+	benchCommand.IntVar(&args.Concurrency, "c", opts.Concurrency, "Number of queries sent to each resolver at the same time")
+	benchCommand.IntVar(&args.Parallel, "parallel", defaultBenchParallel, "Number of resolvers benchmarked at the same time")
+	benchCommand.IntVar(&args.Timeout, "timeout", int(opts.Timeout.Seconds()), "Number of seconds to wait for each response")
+	benchCommand.Float64Var(&args.MinRate, "min-rate", defaultBenchMinRate, "Minimum fraction of the queries that must receive a response")
+	benchCommand.StringVar(&args.Filepaths.Output, "o", "", "Path to the file that will list the ranked resolvers")
+	benchCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	benchCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+
+	if len(clArgs) < 1 {
+		commandUsage(dnsUsageMsg, benchCommand, benchBuf)
+		return
+	}
+	if err := benchCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(dnsUsageMsg, benchCommand, benchBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = ioutil.Discard
+		color.Error = ioutil.Discard
+	}
+	for _, f := range args.Filepaths.Resolvers {
+		list, err := config.GetListFromFile(f)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the resolvers file: %v\n", err)
+			os.Exit(1)
+		}
+		args.Resolvers.InsertMany(list...)
+	}
+	if args.Resolvers.Len() == 0 {
+		r.Fprintln(color.Error, "No DNS resolvers were provided to benchmark")
+		os.Exit(1)
+	}
+	if args.Parallel <= 0 || args.Timeout <= 0 || args.MinRate < 0 || args.MinRate > 1 {
+		r.Fprintln(color.Error, "The parallel and timeout flags must be greater than zero, and min-rate between zero and one")
+		os.Exit(1)
+	}
+
+	opts.Queries = args.Queries
+	opts.Concurrency = args.Concurrency
+	opts.Timeout = time.Duration(args.Timeout) * time.Second
+	if args.Names.Len() > 0 {
+		opts.Names = args.Names.Slice()
+	}
+
+	trusted := args.Trusted.Slice()
+	if len(trusted) == 0 {
+		trusted = resolvers.FilterByTransport(append(config.DefaultBaselineResolvers,
+			config.DefaultBaselineResolversIPv6...), false)
+	}
+
+	baseline := resolvers.NewPool()
+	defer baseline.Stop()
+	baseline.SetTimeout(opts.Timeout)
+	if err := baseline.AddResolvers(config.DefaultQueriesPerBaselineResolver, trusted...); err != nil {
+		r.Fprintf(color.Error, "Failed to use the trusted DNS resolvers: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	bench, err := resolvers.NewBenchmarker(ctx, baseline, opts)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	results := benchmarkResolvers(ctx, bench, args.Resolvers.Slice(), args.Parallel)
+	ranked := resolvers.RankBenchmarkResults(results, args.MinRate)
+	printBenchmarkResults(results, ranked)
+
+	if args.Filepaths.Output != "" {
+		if err := writeRankedResolvers(args.Filepaths.Output, ranked); err != nil {
+			r.Fprintf(color.Error, "Failed to write the ranked resolvers: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func benchmarkResolvers(ctx context.Context, bench *resolvers.Benchmarker, addrs []string, parallel int) []*resolvers.BenchmarkResult {
+	var lock sync.Mutex
+	var results []*resolvers.BenchmarkResult
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+	for _, addr := range addrs {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(a string) {
+			defer func() { <-sem }()
+			defer wg.Done()
+
+			res := bench.Benchmark(ctx, a)
+			lock.Lock()
+			results = append(results, res)
+			lock.Unlock()
+		}(addr)
+	}
+	wg.Wait()
+	return results
+}
+
+func printBenchmarkResults(results, ranked []*resolvers.BenchmarkResult) {
+	usable := make(map[*resolvers.BenchmarkResult]struct{}, len(ranked))
+	for _, res := range ranked {
+		usable[res] = struct{}{}
+		fmt.Fprintf(color.Output, "%-40s %s %s %s\n", green(res.Address),
+			blue(fmt.Sprintf("%8s", res.Latency.Round(time.Millisecond))),
+			yellow(fmt.Sprintf("%8.1f qps", res.QPS)),
+			yellow(fmt.Sprintf("%5.1f%% responses", res.ResponseRate()*100)))
+	}
+
+	var rejected []*resolvers.BenchmarkResult
+	for _, res := range results {
+		if _, found := usable[res]; !found {
+			rejected = append(rejected, res)
+		}
+	}
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i].Address < rejected[j].Address })
+
+	for _, res := range rejected {
+		reason := res.Failure
+		if reason == "" {
+			reason = fmt.Sprintf("%.1f%% of the queries received a response", res.ResponseRate()*100)
+		}
+		fmt.Fprintf(color.Output, "%-40s %s\n", red(res.Address), reason)
+	}
+
+	fmt.Fprintf(color.Output, "\n%s of the %s resolvers can be used\n",
+		green(len(ranked)), green(len(results)))
+}
+
+// The file lists one resolver per line, so it can be provided to the rf flag of the enum subcommand.
+func writeRankedResolvers(path string, ranked []*resolvers.BenchmarkResult) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, res := range ranked {
+		if _, err := fmt.Fprintln(w, res.Address); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -8,6 +8,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -29,43 +30,79 @@ import (
 	"github.com/OWASP/Amass/v3/datasrcs"
 	"github.com/OWASP/Amass/v3/enum"
 	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/integrations"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/netmap"
 	"github.com/caffix/stringset"
 	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"golang.org/x/term"
 )
 
-const enumUsageMsg = "enum [options] -d DOMAIN"
+const (
+	enumUsageMsg = "enum [options] -d DOMAIN"
+
+	maxMigrationAttempts = 3
+	migrationRetryDelay  = 5 * time.Second
+
+	outputBatchSize    = 100
+	outputSyncInterval = 5 * time.Second
+)
 
 type enumArgs struct {
 	Addresses         format.ParseIPs
 	ASNs              format.ParseInts
 	CIDRs             format.ParseCIDRs
+	CSVColumns        string
 	AltWordList       *stringset.Set
 	AltWordListMask   *stringset.Set
+	AltRules          []string
+	Checkpoint        int
 	BruteWordList     *stringset.Set
 	BruteWordListMask *stringset.Set
+	BruteShard        string
 	Blacklist         *stringset.Set
+	BlacklistGlobs    format.ParseStrings
+	BlacklistRegexps  patternList
 	Domains           *stringset.Set
 	Excluded          *stringset.Set
 	Included          *stringset.Set
 	Interface         string
 	MaxDNSQueries     int
+	DNSBudget         int
+	Freshness         int
+	MaxGuesses        int
 	ResolverQPS       int
 	TrustedQPS        int
+	Confirmations     int
 	MaxDepth          int
 	MinForRecursive   int
+	MinConfidence     int
 	Names             *stringset.Set
 	Ports             format.ParseInts
+	ProbePorts        format.ParseInts
+	ScanPorts         format.ParseInts
+	ScanRate          int
 	Resolvers         *stringset.Set
 	Trusted           *stringset.Set
+	Syslog            string
+	SyslogFormat      string
 	Timeout           int
+	BruteTimeout      int
+	AltTimeout        int
+	Project           string
+	Profile           string
 	Options           struct {
 		Active          bool
 		Alterations     bool
+		Authoritative   bool
 		BruteForcing    bool
 		DemoMode        bool
+		DiskQueue       bool
+		DNSCache        bool
+		DNSSEC          bool
+		DryRun          bool
 		IPs             bool
 		IPv4            bool
 		IPv6            bool
@@ -75,25 +112,36 @@ type enumArgs struct {
 		NoLocalDatabase bool
 		NoRecursive     bool
 		Passive         bool
+		PortScan        bool
+		PreferIPv6      bool
+		Probe           bool
+		Resume          bool
 		Silent          bool
 		Sources         bool
+		Takeovers       bool
+		TUI             bool
 		Verbose         bool
 	}
 	Filepaths struct {
 		AllFilePrefix    string
+		AltRules         format.ParseStrings
 		AltWordlist      format.ParseStrings
 		Blacklist        string
 		BruteWordlist    format.ParseStrings
 		ConfigFile       string
+		CSVOutput        string
 		Directory        string
 		Domains          format.ParseStrings
 		ExcludedSrcs     string
 		IncludedSrcs     string
 		JSONOutput       string
 		LogFile          string
+		NQuadsOutput     string
+		Progress         string
 		Names            format.ParseStrings
 		Resolvers        format.ParseStrings
 		Trusted          format.ParseStrings
+		Scope            string
 		ScriptsDirectory string
 		TermOut          string
 	}
@@ -104,7 +152,12 @@ func defineEnumArgumentFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.Var(args.AltWordListMask, "awm", "\"hashcat-style\" wordlist masks for name alterations")
 	enumFlags.Var(&args.ASNs, "asn", "ASNs separated by commas (can be used multiple times)")
 	enumFlags.Var(&args.CIDRs, "cidr", "CIDRs separated by commas (can be used multiple times)")
+	enumFlags.StringVar(&args.CSVColumns, "csv-columns", "", "Columns of the CSV output separated by commas (default: all)")
+	enumFlags.IntVar(&args.Checkpoint, "checkpoint", 5, "Number of minutes between the checkpoints saved for -resume (0 disables them)")
 	enumFlags.Var(args.Blacklist, "bl", "Blacklist of subdomain names that will not be investigated")
+	enumFlags.Var(&args.BlacklistGlobs, "blg", "Glob patterns of names separated by commas (e.g. '*.dev.example.com') that will not be investigated")
+	enumFlags.Var(&args.BlacklistRegexps, "blr", "Regular expression of names that will not be investigated (can be used multiple times)")
+	enumFlags.StringVar(&args.BruteShard, "brute-shard", "", "Brute force only the shard I/N of the wordlists, such as 2/4 (used by distribute)")
 	enumFlags.Var(args.BruteWordListMask, "wm", "\"hashcat-style\" wordlist masks for DNS brute forcing")
 	enumFlags.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
 	enumFlags.Var(args.Excluded, "exclude", "Data source names separated by commas to be excluded")
@@ -112,21 +165,38 @@ func defineEnumArgumentFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.StringVar(&args.Interface, "iface", "", "Provide the network interface to send traffic through")
 	enumFlags.IntVar(&args.MaxDNSQueries, "max-dns-queries", 0, "Deprecated flag to be replaced by dns-qps in version 4.0")
 	enumFlags.IntVar(&args.MaxDNSQueries, "dns-qps", 0, "Maximum number of DNS queries per second across all resolvers")
+	enumFlags.IntVar(&args.DNSBudget, "dns-budget", 0, "Number of DNS queries sent before brute forcing and alterations stop")
+	enumFlags.IntVar(&args.Freshness, "fresh", 0, "Number of hours the names resolved by earlier enumerations are not resolved again")
 	enumFlags.IntVar(&args.ResolverQPS, "rqps", 0, "Maximum number of DNS queries per second for each untrusted resolver")
 	enumFlags.IntVar(&args.TrustedQPS, "trqps", 0, "Maximum number of DNS queries per second for each trusted resolver")
+	enumFlags.IntVar(&args.Confirmations, "confirmations", 0, "Number of trusted resolvers that must confirm a name before it is reported (Default: 1)")
 	enumFlags.IntVar(&args.MaxDepth, "max-depth", 0, "Maximum number of subdomain labels for brute forcing")
+	enumFlags.IntVar(&args.MaxGuesses, "max-guesses", 0, "Maximum number of names generated by alterations that will be resolved")
 	enumFlags.IntVar(&args.MinForRecursive, "min-for-recursive", 1, "Subdomain labels seen before recursive brute forcing (Default: 1)")
+	enumFlags.IntVar(&args.MinConfidence, "min-confidence", 0, "Only print names with at least this confidence (0-100)")
+	enumFlags.StringVar(&args.Syslog, "syslog", "", "Send the findings to the syslog receiver at this URL (udp://, tcp:// or tls://host:port)")
+	enumFlags.StringVar(&args.SyslogFormat, "syslog-format", format.SIEMFormatCEF, "Format of the syslog messages: cef or leef")
 	enumFlags.Var(&args.Ports, "p", "Ports separated by commas (default: 80, 443)")
+	enumFlags.Var(&args.ProbePorts, "probe-ports", "Ports probed for web services separated by commas (default: the -p ports)")
+	enumFlags.Var(&args.ScanPorts, "portscan-ports", "Ports scanned on the in-scope addresses separated by commas")
+	enumFlags.IntVar(&args.ScanRate, "portscan-rate", 0, "Number of TCP connections attempted per second by the port scans")
 	enumFlags.Var(args.Resolvers, "r", "IP addresses of untrusted DNS resolvers (can be used multiple times)")
 	enumFlags.Var(args.Resolvers, "tr", "IP addresses of trusted DNS resolvers (can be used multiple times)")
 	enumFlags.IntVar(&args.Timeout, "timeout", 0, "Number of minutes to let enumeration run before quitting")
+	enumFlags.IntVar(&args.BruteTimeout, "brute-timeout", 0, "Number of minutes brute forcing generates names before stopping")
+	enumFlags.IntVar(&args.AltTimeout, "alts-timeout", 0, "Number of minutes alterations generate names before stopping")
 }
 
 func defineEnumOptionFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	var placeholder bool
 	enumFlags.BoolVar(&args.Options.Active, "active", false, "Attempt zone transfers and certificate name grabs")
+	enumFlags.BoolVar(&args.Options.Authoritative, "auth", false, "Send the DNS queries directly to the authoritative name servers")
 	enumFlags.BoolVar(&args.Options.BruteForcing, "brute", false, "Execute brute forcing after searches")
 	enumFlags.BoolVar(&args.Options.DemoMode, "demo", false, "Censor output to make it suitable for demonstrations")
+	enumFlags.BoolVar(&args.Options.DNSCache, "dns-cache", false, "Cache the DNS responses on disk for later enumerations")
+	enumFlags.BoolVar(&args.Options.DNSSEC, "dnssec", false, "Validate the DNSSEC chains of the resolved names in scope")
+	enumFlags.BoolVar(&args.Options.DiskQueue, "disk-queue", false, "Store the names waiting to be resolved beyond the memory limit on disk")
+	enumFlags.BoolVar(&args.Options.DryRun, "dry-run", false, "Print the data sources, resolvers and techniques of the enumeration and exit")
 	enumFlags.BoolVar(&args.Options.IPs, "ip", false, "Show the IP addresses for discovered names")
 	enumFlags.BoolVar(&args.Options.IPv4, "ipv4", false, "Show the IPv4 addresses for discovered names")
 	enumFlags.BoolVar(&args.Options.IPv6, "ipv6", false, "Show the IPv6 addresses for discovered names")
@@ -137,27 +207,40 @@ func defineEnumOptionFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.BoolVar(&placeholder, "nolocaldb", false, "Deprecated feature to be removed in version 4.0")
 	enumFlags.BoolVar(&args.Options.NoRecursive, "norecursive", false, "Turn off recursive brute forcing")
 	enumFlags.BoolVar(&args.Options.Passive, "passive", false, "Disable DNS resolution of names and dependent features")
+	enumFlags.BoolVar(&args.Options.PortScan, "portscan", false, "Scan the in-scope addresses for open TCP ports")
+	enumFlags.BoolVar(&args.Options.PreferIPv6, "prefer-ipv6", false, "Send the DNS queries over IPv6 when the transport is available")
+	enumFlags.BoolVar(&args.Options.Probe, "probe", false, "Probe the web services of the resolved names and record their responses")
+	enumFlags.BoolVar(&args.Options.Resume, "resume", false, "Continue the interrupted enumeration from the checkpoint in the output directory")
 	enumFlags.BoolVar(&placeholder, "share", false, "Deprecated feature to be removed in version 4.0")
 	enumFlags.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
 	enumFlags.BoolVar(&args.Options.Sources, "src", false, "Print data sources for the discovered names")
+	enumFlags.BoolVar(&args.Options.Takeovers, "takeovers", false, "Check the names aliased or delegated to services prone to subdomain takeovers")
+	enumFlags.BoolVar(&args.Options.TUI, "tui", false, "Show the progress in an interactive terminal UI that can pause the enumeration")
 	enumFlags.BoolVar(&args.Options.Verbose, "v", false, "Output status / debug / troubleshooting info")
 }
 
 func defineEnumFilepathFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.StringVar(&args.Filepaths.AllFilePrefix, "oA", "", "Path prefix used for naming all output files")
+	enumFlags.Var(&args.Filepaths.AltRules, "ar", "Path to a file providing \"hashcat-style\" rules for name alterations")
 	enumFlags.Var(&args.Filepaths.AltWordlist, "aw", "Path to a different wordlist file for alterations")
 	enumFlags.StringVar(&args.Filepaths.Blacklist, "blf", "", "Path to a file providing blacklisted subdomains")
 	enumFlags.Var(&args.Filepaths.BruteWordlist, "w", "Path to a different wordlist file for brute forcing")
-	enumFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	enumFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
 	enumFlags.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+	enumFlags.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
+	enumFlags.StringVar(&args.Profile, "profile", "", "Name of the configuration file profile applied over its other settings")
 	enumFlags.Var(&args.Filepaths.Domains, "df", "Path to a file providing root domain names")
 	enumFlags.StringVar(&args.Filepaths.ExcludedSrcs, "ef", "", "Path to a file providing data sources to exclude")
 	enumFlags.StringVar(&args.Filepaths.IncludedSrcs, "if", "", "Path to a file providing data sources to include")
+	enumFlags.StringVar(&args.Filepaths.CSVOutput, "ocsv", "", "Path to the CSV output file")
+	enumFlags.StringVar(&args.Filepaths.NQuadsOutput, "onq", "", "Path to the N-Quads file of the graph data stored by the enumeration")
 	enumFlags.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file")
 	enumFlags.StringVar(&args.Filepaths.LogFile, "log", "", "Path to the log file where errors will be written")
+	enumFlags.StringVar(&args.Filepaths.Progress, "progress", "", "Path to the file or named pipe receiving JSON progress records ('-' for stderr)")
 	enumFlags.Var(&args.Filepaths.Names, "nf", "Path to a file providing already known subdomain names (from other tools/sources)")
 	enumFlags.Var(&args.Filepaths.Resolvers, "rf", "Path to a file providing untrusted DNS resolvers")
 	enumFlags.Var(&args.Filepaths.Trusted, "trf", "Path to a file providing trusted DNS resolvers")
+	enumFlags.StringVar(&args.Filepaths.Scope, "scope", "", "Path to a scope file providing the domains, addresses, netblocks, ASNs and exclusions")
 	enumFlags.StringVar(&args.Filepaths.ScriptsDirectory, "scripts", "", "Path to a directory containing ADS scripts")
 	enumFlags.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
 }
@@ -171,6 +254,11 @@ func runEnumCommand(clArgs []string) {
 		return
 	}
 	createOutputDirectory(cfg)
+	// The scripts in the output directory are included in the data sources of the plan
+	if args.Options.DryRun {
+		printEnumPlan(cfg, args)
+		return
+	}
 
 	rLog, wLog := io.Pipe()
 	// Setup logging so that messages can be written to the file and used by the program
@@ -179,6 +267,12 @@ func runEnumCommand(clArgs []string) {
 	if args.Filepaths.LogFile != "" {
 		logfile = args.Filepaths.LogFile
 	}
+	// The messages are kept while the terminal UI is drawn over the terminal
+	var msgs *messageLog
+	if args.Options.TUI {
+		msgs = newMessageLog(color.Error)
+		color.Error = msgs
+	}
 	// Start handling the log messages
 	go writeLogsAndMessages(rLog, logfile, args.Options.Verbose)
 	// Create the System that will provide architecture to this enumeration
@@ -205,13 +299,38 @@ func runEnumCommand(clArgs []string) {
 		r.Fprintf(color.Error, "%s\n", "Failed to setup the enumeration")
 		os.Exit(1)
 	}
+	if args.Options.Resume {
+		cp, err := enum.LoadCheckpoint(enum.CheckpointPath(cfg))
+		if err != nil {
+			r.Fprintf(color.Error, "failed to load the checkpoint: %v\n", err)
+			os.Exit(1)
+		}
+		e.Resume(cp)
+	}
 
 	var wg sync.WaitGroup
 	var outChans []chan *requests.Output
 	// This channel sends the signal for goroutines to terminate
 	done := make(chan struct{})
-	// Print output only if JSONOutput is not meant for STDOUT
-	if args.Filepaths.JSONOutput != "-" {
+	// Closed when the user quits from the terminal UI
+	var ui *terminalUI
+	var quitUI <-chan struct{}
+	if args.Options.TUI {
+		ui, err = newTerminalUI(e, args, msgs)
+		if err != nil {
+			msgs.release()
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+		quitUI = ui.Quit()
+
+		wg.Add(1)
+		// This goroutine will handle drawing the terminal UI
+		uiOutChan := make(chan *requests.Output, 10)
+		go ui.run(uiOutChan, &wg)
+		outChans = append(outChans, uiOutChan)
+	} else if args.Filepaths.JSONOutput != "-" {
+		// Print output only if JSONOutput is not meant for STDOUT
 		wg.Add(1)
 		// This goroutine will handle printing the output
 		printOutChan := make(chan *requests.Output, 10)
@@ -219,6 +338,23 @@ func runEnumCommand(clArgs []string) {
 		outChans = append(outChans, printOutChan)
 	}
 
+	if args.Filepaths.Progress != "" {
+		out, err := openProgressOutput(args.Filepaths.Progress)
+		if err != nil {
+			if ui != nil {
+				ui.close()
+			}
+			r.Fprintf(color.Error, "Failed to open the progress output: %v\n", err)
+			os.Exit(1)
+		}
+
+		wg.Add(1)
+		// This goroutine will handle writing the progress records
+		progressOutChan := make(chan *requests.Output, 10)
+		go writeProgress(e, args, out, progressOutChan, &wg)
+		outChans = append(outChans, progressOutChan)
+	}
+
 	wg.Add(1)
 	// This goroutine will handle saving the output to the text file
 	txtOutChan := make(chan *requests.Output, 10)
@@ -231,6 +367,36 @@ func runEnumCommand(clArgs []string) {
 	go saveJSONOutput(e, args, jsonOutChan, &wg)
 	outChans = append(outChans, jsonOutChan)
 
+	if args.Filepaths.CSVOutput != "" || args.Filepaths.AllFilePrefix != "" {
+		wg.Add(1)
+		// This goroutine will handle saving the output to the CSV file
+		csvOutChan := make(chan *requests.Output, 10)
+		go saveCSVOutput(e, args, csvOutChan, &wg)
+		outChans = append(outChans, csvOutChan)
+	}
+
+	if args.Syslog != "" {
+		s, err := integrations.NewSyslog(args.Syslog, args.SyslogFormat)
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+
+		wg.Add(1)
+		// This goroutine will handle sending the output to the syslog receiver
+		syslogOutChan := make(chan *requests.Output, 10)
+		go sendSyslogOutput(e, args, s, syslogOutChan, &wg)
+		outChans = append(outChans, syslogOutChan)
+	}
+
+	if hooks := integrations.NewWebhooks(cfg); len(hooks) > 0 {
+		wg.Add(1)
+		// This goroutine will handle notifying the webhooks of the new assets
+		hookOutChan := make(chan *requests.Output, 10)
+		go notifyNewAssets(e, hooks, hookOutChan, &wg)
+		outChans = append(outChans, hookOutChan)
+	}
+
 	var ctx context.Context
 	var cancel context.CancelFunc
 	if args.Timeout == 0 {
@@ -241,7 +407,7 @@ func runEnumCommand(clArgs []string) {
 	defer cancel()
 
 	wg.Add(1)
-	go processOutput(ctx, graph, e, outChans, done, &wg)
+	go processOutput(ctx, graph, e, args.MinConfidence, outChans, done, &wg)
 	// Monitor for cancellation by the user
 	go func(d chan struct{}, c context.Context, f context.CancelFunc) {
 		quit := make(chan os.Signal, 1)
@@ -251,18 +417,51 @@ func runEnumCommand(clArgs []string) {
 		select {
 		case <-quit:
 			f()
+		case <-quitUI:
+			f()
 		case <-d:
 		case <-c.Done():
 		}
 	}(done, ctx, cancel)
+	// Apply the configuration file again when the hangup signal is received
+	go reloadOnEnumHangup(done, e, args)
 	// Start the enumeration process
+	start := time.Now()
 	if err := e.Start(ctx); err != nil {
+		if ui != nil {
+			ui.close()
+		}
 		r.Println(err)
 		os.Exit(1)
 	}
 	// Let all the output goroutines know that the enumeration has finished
 	close(done)
 	wg.Wait()
+	format.PrintSourceStats(e.SourceStats())
+	// The takeover candidates were checked and stored before the enumeration finished
+	if cfg.Active && cfg.Takeovers && args.Filepaths.JSONOutput != "-" {
+		showTakeovers([]string{cfg.UUID.String()}, cfg.Domains(), cfg, graph)
+	}
+	// The graph data can be merged into another database, such as by the coordinator of a distributed enumeration
+	if path := args.Filepaths.NQuadsOutput; path != "" {
+		if err := writeGraphOutput(graph, cfg.UUID.String(), path); err != nil {
+			r.Fprintf(color.Error, "Failed to write the N-Quads file: %v\n", err)
+		}
+	}
+	// Push the findings into the integrations provided by the configuration
+	if ins := integrations.NewIntegrations(cfg); len(ins) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		pushFindings(ctx, ins, enumFindings(ctx, graph, e, args.MinConfidence))
+	}
+	// Upload the output files to the object storage provided by the configuration
+	if cfg.Upload != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		uploadOutputFiles(ctx, integrations.NewUploader(cfg.Upload), e, args, start)
+	}
 	// If necessary, handle graph database migration
 	if len(e.Sys.GraphDatabases()) > 0 {
 		fmt.Fprintf(color.Error, "\n%s\n", green("The enumeration has finished"))
@@ -283,14 +482,123 @@ func runEnumCommand(clArgs []string) {
 			fmt.Fprintf(color.Error, "%s%s%s\n",
 				yellow("Discoveries are being migrated into the "), yellow(g.String()), yellow(" database"))
 
-			if err := graph.Migrate(ctx, g); err != nil {
+			if err := migrateFindings(ctx, graph, g); err != nil {
 				fmt.Fprintf(color.Error, "%s%s%s%s\n",
 					red("The database migration to "), red(g.String()), red(" failed: "), red(err.Error()))
+				continue
+			}
+			// Apply the retention policy to the events in scope
+			if _, err := enum.PruneEvents(ctx, g, cfg.Domains(), cfg.RetentionDays, cfg.RetentionEvents); err != nil {
+				fmt.Fprintf(color.Error, "%s%s%s%s\n",
+					red("Failed to prune the "), red(g.String()), red(" database: "), red(err.Error()))
 			}
 		}
 	}
 }
 
+// Uploads the text, JSON and CSV output files of the enumeration, which would otherwise be lost
+// when Amass runs in an ephemeral container.
+func uploadOutputFiles(ctx context.Context, up *integrations.Uploader, e *enum.Enumeration, args *enumArgs, start time.Time) {
+	files := []string{textOutputPath(e, args)}
+	if args.Filepaths.JSONOutput != "-" {
+		files = append(files, jsonOutputPath(e, args))
+	}
+	if path := csvOutputPath(args); path != "" {
+		files = append(files, path)
+	}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		ctype := "text/plain"
+		switch filepath.Ext(file) {
+		case ".json":
+			ctype = "application/json"
+		case ".csv":
+			ctype = "text/csv"
+		}
+
+		key := up.ObjectKey(e.Config.Domains(), e.Config.UUID.String(), start, file)
+		if err := up.Upload(ctx, key, ctype, data); err != nil {
+			r.Fprintf(color.Error, "Failed to upload %s to %s: %v\n", file, up, err)
+			continue
+		}
+		g.Fprintf(color.Error, "%s was uploaded to %s/%s\n", file, up, key)
+	}
+}
+
+// Databases shared by several Amass instances can reject transactions that conflict with concurrent
+// writes, so the migration is attempted again. The duplicate quads written previously are ignored.
+func migrateFindings(ctx context.Context, from, to *netmap.Graph) error {
+	var err error
+
+	for attempt := 1; attempt <= maxMigrationAttempts; attempt++ {
+		if err = from.Migrate(ctx, to); err == nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * migrationRetryDelay):
+		}
+	}
+	return err
+}
+
+// Applies the configuration file to the running enumeration each time the hangup signal is received, so the
+// data source keys, the rate limits and the scope additions take effect without restarting the enumeration.
+func reloadOnEnumHangup(done chan struct{}, e *enum.Enumeration, args *enumArgs) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-hup:
+		}
+
+		cfg, err := reloadEnumConfig(args)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to reload the configuration file: %v\n", err)
+			continue
+		}
+
+		changes := e.Reload(cfg)
+		if !changes.Changed() {
+			g.Fprintln(color.Error, "The reloaded configuration file did not change the enumeration")
+			continue
+		}
+		g.Fprintf(color.Error, "The reloaded configuration added %d domains and %d ASNs, and updated the settings of %d data sources\n",
+			len(changes.Domains), len(changes.ASNs), len(changes.Sources))
+		if changes.MaxDNSQueries {
+			g.Fprintf(color.Error, "The DNS queries are now limited to %d per second\n", e.Config.MaxDNSQueries)
+		}
+	}
+}
+
+// Loads the configuration file the same way as argsAndConfig, so the command-line arguments still take precedence.
+func reloadEnumConfig(args *enumArgs) (*config.Config, error) {
+	cfg := config.NewConfig()
+	cfg.Profile = args.Profile
+
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.UpdateConfig(*args); err != nil {
+		return nil, err
+	}
+	if err := integrations.LoadSecrets(context.Background(), cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 	args := enumArgs{
 		AltWordList:       stringset.New(),
@@ -348,6 +656,18 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		color.Output = ioutil.Discard
 		color.Error = ioutil.Discard
 	}
+	if args.Options.TUI && (args.Options.Silent || args.Filepaths.JSONOutput == "-") {
+		r.Fprintln(color.Error, "The terminal UI cannot be used with the silent mode or JSON written to stdout")
+		os.Exit(1)
+	}
+	if args.Options.TUI && args.Filepaths.Progress == "-" {
+		r.Fprintln(color.Error, "The terminal UI cannot be used with the progress records written to stderr")
+		os.Exit(1)
+	}
+	if args.Options.TUI && (!term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd()))) {
+		r.Fprintln(color.Error, "The terminal UI requires an interactive terminal")
+		os.Exit(1)
+	}
 	if args.AltWordListMask.Len() > 0 {
 		args.AltWordList.Union(args.AltWordListMask)
 	}
@@ -360,19 +680,28 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		commandUsage(enumUsageMsg, enumCommand, enumBuf)
 		os.Exit(1)
 	}
+	if _, err := format.ParseCSVColumns(args.CSVColumns); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if _, err := format.SIEMMessage(args.SyslogFormat, &requests.Output{}); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
 	if err := processEnumInputFiles(&args); err != nil {
 		fmt.Fprintf(color.Error, "%v\n", err)
 		os.Exit(1)
 	}
 
 	cfg := config.NewConfig()
+	cfg.Profile = args.Profile
 	// Check if a configuration file was provided, and if so, load the settings
 	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
 		// Check if a config file was provided that has DNS resolvers specified
 		if len(cfg.Resolvers) > 0 && args.Resolvers.Len() == 0 {
 			args.Resolvers = stringset.New(cfg.Resolvers...)
 		}
-	} else if args.Filepaths.ConfigFile != "" {
+	} else if args.Filepaths.ConfigFile != "" || args.Profile != "" {
 		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
 		os.Exit(1)
 	}
@@ -381,6 +710,23 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		r.Fprintf(color.Error, "Configuration error: %v\n", err)
 		os.Exit(1)
 	}
+	// Retrieve the data source credentials stored by the secrets backend
+	if err := integrations.LoadSecrets(context.Background(), cfg); err != nil {
+		r.Fprintf(color.Error, "Configuration error: %v\n", err)
+		os.Exit(1)
+	}
+	// Expand the templates of the output directory and files, such as {{.Domain}}/{{.Date}}
+	if err := expandEnumPaths(cfg, &args); err != nil {
+		r.Fprintf(color.Error, "Configuration error: %v\n", err)
+		os.Exit(1)
+	}
+	// Continue the enumeration saved in the checkpoint using its UUID and domains
+	if args.Options.Resume {
+		if err := resumeConfig(cfg); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+	}
 	// Check if the user has requested the data source names
 	if args.Options.ListSources {
 		for _, line := range GetAllSourceInfo(cfg) {
@@ -397,6 +743,30 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		r.Fprintln(color.Error, "Ports can only be scanned in the active mode")
 		os.Exit(1)
 	}
+	if cfg.Passive && args.Options.DNSSEC {
+		r.Fprintln(color.Error, "DNSSEC chains cannot be validated without DNS resolution")
+		os.Exit(1)
+	}
+	if cfg.Passive && args.Options.Probe {
+		r.Fprintln(color.Error, "Web services cannot be probed without DNS resolution")
+		os.Exit(1)
+	}
+	if !cfg.HTTPProbes && len(args.ProbePorts) > 0 {
+		r.Fprintln(color.Error, "The probe ports can only be provided when probing web services")
+		os.Exit(1)
+	}
+	if cfg.Passive && args.Options.PortScan {
+		r.Fprintln(color.Error, "Ports cannot be scanned in the passive mode")
+		os.Exit(1)
+	}
+	if !cfg.PortScan && (len(args.ScanPorts) > 0 || args.ScanRate > 0) {
+		r.Fprintln(color.Error, "The port scan settings can only be provided with the -portscan flag")
+		os.Exit(1)
+	}
+	if !cfg.Active && args.Options.Takeovers {
+		r.Fprintln(color.Error, "Takeover candidates can only be checked in the active mode")
+		os.Exit(1)
+	}
 	if len(cfg.Domains()) == 0 {
 		r.Fprintln(color.Error, "Configuration error: No root domain names were provided")
 		os.Exit(1)
@@ -438,96 +808,307 @@ func printOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Outp
 	}
 }
 
-func saveTextOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Output, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	dir := config.OutputDirectory(e.Config.Dir)
-	txtfile := filepath.Join(dir, "amass.txt")
+func textOutputPath(e *enum.Enumeration, args *enumArgs) string {
+	if args.Filepaths.AllFilePrefix != "" {
+		return args.Filepaths.AllFilePrefix + ".txt"
+	}
 	if args.Filepaths.TermOut != "" {
-		txtfile = args.Filepaths.TermOut
+		return args.Filepaths.TermOut
+	}
+	return filepath.Join(config.OutputDirectory(e.Config.Dir), "amass.txt")
+}
+
+func jsonOutputPath(e *enum.Enumeration, args *enumArgs) string {
+	if args.Filepaths.AllFilePrefix != "" {
+		return args.Filepaths.AllFilePrefix + ".json"
+	}
+	if args.Filepaths.JSONOutput != "" {
+		return args.Filepaths.JSONOutput
+	}
+	return filepath.Join(config.OutputDirectory(e.Config.Dir), "amass.json")
+}
+
+func writeGraphOutput(db *netmap.Graph, uuid, path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
 	}
+	defer f.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if err := enum.ExportEvent(ctx, db, uuid, f); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func csvOutputPath(args *enumArgs) string {
 	if args.Filepaths.AllFilePrefix != "" {
-		txtfile = args.Filepaths.AllFilePrefix + ".txt"
+		return args.Filepaths.AllFilePrefix + ".csv"
 	}
+	return args.Filepaths.CSVOutput
+}
+
+func saveTextOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Output, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	txtfile := textOutputPath(e, args)
 	if txtfile == "" {
 		return
 	}
 
-	outptr, err := os.OpenFile(txtfile, os.O_WRONLY|os.O_CREATE, 0644)
+	outptr, written, err := openOutputFile(txtfile, args.Options.Resume, textOutputName)
 	if err != nil {
 		r.Fprintf(color.Error, "Failed to open the text output file: %v\n", err)
 		os.Exit(1)
 	}
+	defer written.Close()
 	defer func() {
 		_ = outptr.Sync()
 		_ = outptr.Close()
 	}()
 
-	_ = outptr.Truncate(0)
-	_, _ = outptr.Seek(0, 0)
+	t := time.NewTicker(outputSyncInterval)
+	defer t.Stop()
 	// Save all the output returned by the enumeration
-	for out := range output {
-		out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
-		if !e.Config.Passive && len(out.Addresses) <= 0 {
-			continue
-		}
+	for {
+		select {
+		case out, ok := <-output:
+			if !ok {
+				return
+			}
 
-		source, name, ips := format.OutputLineParts(out, args.Options.Sources,
-			args.Options.IPs || args.Options.IPv4 || args.Options.IPv6, args.Options.DemoMode)
-		if ips != "" {
-			ips = " " + ips
+			out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
+			if !e.Config.Passive && len(out.Addresses) <= 0 {
+				continue
+			}
+
+			source, name, ips := format.OutputLineParts(out, args.Options.Sources,
+				args.Options.IPs || args.Options.IPv4 || args.Options.IPv6, args.Options.DemoMode)
+			if ips != "" {
+				ips = " " + ips
+			}
+			// The resumed enumeration does not repeat the findings already written
+			if written.Has(name) {
+				continue
+			}
+			// Write the line to the output file
+			fmt.Fprintf(outptr, "%s%s%s\n", source, name, ips)
+		case <-t.C:
+			// Findings written so far survive the process being killed or the system crashing
+			_ = outptr.Sync()
 		}
-		// Write the line to the output file
-		fmt.Fprintf(outptr, "%s%s%s\n", source, name, ips)
 	}
 }
 
 func saveJSONOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Output, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	dir := config.OutputDirectory(e.Config.Dir)
-	jsonfile := filepath.Join(dir, "amass.json")
-	if args.Filepaths.JSONOutput != "" {
-		jsonfile = args.Filepaths.JSONOutput
-	}
-	if args.Filepaths.AllFilePrefix != "" {
-		jsonfile = args.Filepaths.AllFilePrefix + ".json"
-	}
+	jsonfile := jsonOutputPath(e, args)
 	if jsonfile == "" {
 		return
 	}
 
 	var jsonptr *os.File
+	var written *stringset.Set
 	var err error
 
 	// Write to STDOUT and not a file if named "-"
 	if args.Filepaths.JSONOutput == "-" {
 		jsonptr = os.Stdout
+		written = stringset.New()
 	} else {
-		jsonptr, err = os.OpenFile(jsonfile, os.O_WRONLY|os.O_CREATE, 0644)
+		jsonptr, written, err = openOutputFile(jsonfile, args.Options.Resume, jsonOutputName)
 		if err != nil {
 			r.Fprintf(color.Error, "Failed to open the JSON output file: %v\n", err)
 			os.Exit(1)
 		}
 	}
+	defer written.Close()
 
 	defer func() {
 		_ = jsonptr.Sync()
 		_ = jsonptr.Close()
 	}()
 
-	_ = jsonptr.Truncate(0)
-	_, _ = jsonptr.Seek(0, 0)
-
+	t := time.NewTicker(outputSyncInterval)
+	defer t.Stop()
+	// Each finding is encoded on a separate line as soon as it is received
 	enc := json.NewEncoder(jsonptr)
-	// Save all the output returned by the enumeration
+	for {
+		select {
+		case out, ok := <-output:
+			if !ok {
+				return
+			}
+			if written.Has(out.Name) {
+				continue
+			}
+			// Handle encoding the result as JSON
+			_ = enc.Encode(out)
+		case <-t.C:
+			_ = jsonptr.Sync()
+		}
+	}
+}
+
+func saveCSVOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Output, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	outptr, written, err := openOutputFile(csvOutputPath(args), args.Options.Resume, csvOutputName())
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to open the CSV output file: %v\n", err)
+		os.Exit(1)
+	}
+	defer written.Close()
+	defer func() {
+		_ = outptr.Sync()
+		_ = outptr.Close()
+	}()
+
+	columns, _ := format.ParseCSVColumns(args.CSVColumns)
+	// The header row was already written to the output of the resumed enumeration
+	var w *format.CSVWriter
+	if info, err := outptr.Stat(); err == nil && info.Size() > 0 {
+		w = format.AppendCSVWriter(outptr, columns)
+	} else if w, err = format.NewCSVWriter(outptr, columns); err != nil {
+		r.Fprintf(color.Error, "Failed to write the CSV output file: %v\n", err)
+		os.Exit(1)
+	}
+
+	t := time.NewTicker(outputSyncInterval)
+	defer t.Stop()
+	for {
+		select {
+		case out, ok := <-output:
+			if !ok {
+				return
+			}
+
+			out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
+			if !e.Config.Passive && len(out.Addresses) <= 0 {
+				continue
+			}
+			if written.Has(out.Name) {
+				continue
+			}
+			// The finding was first seen when this enumeration confirmed it
+			_ = w.Write(out, time.Now())
+		case <-t.C:
+			_ = outptr.Sync()
+		}
+	}
+}
+
+// Opens the output file in append mode, so each finding is kept once it has been written. A new enumeration
+// rotates the output of the previous one, while a resumed enumeration continues the output it had written
+// before the interruption, and the names already written are returned using the key function.
+func openOutputFile(path string, resume bool, key func(line string) string) (*os.File, *stringset.Set, error) {
+	written := stringset.New()
+
+	if resume {
+		lines, err := format.RecoverLines(path)
+		if err != nil {
+			return nil, written, err
+		}
+		for _, line := range lines {
+			if name := key(line); name != "" {
+				written.Insert(name)
+			}
+		}
+	} else if err := format.RotateFile(path); err != nil {
+		return nil, written, err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	return f, written, err
+}
+
+// Returns the name of the text output line, which can start with the data source.
+func textOutputName(line string) string {
+	if strings.HasPrefix(line, "[") {
+		if i := strings.Index(line, "] "); i >= 0 {
+			line = line[i+2:]
+		}
+	}
+	if fields := strings.Fields(line); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+func jsonOutputName(line string) string {
+	var out requests.Output
+
+	if err := json.Unmarshal([]byte(line), &out); err != nil {
+		return ""
+	}
+	return out.Name
+}
+
+// Returns the key function finding the name column using the header row of the CSV output.
+func csvOutputName() func(line string) string {
+	header := true
+	col := -1
+
+	return func(line string) string {
+		rec, err := csv.NewReader(strings.NewReader(line)).Read()
+		if err != nil {
+			return ""
+		}
+		if header {
+			header = false
+			for i, c := range rec {
+				if c == "name" {
+					col = i
+				}
+			}
+			return ""
+		}
+		if col < 0 || col >= len(rec) {
+			return ""
+		}
+		return rec[col]
+	}
+}
+
+func sendSyslogOutput(e *enum.Enumeration, args *enumArgs, s *integrations.Syslog, output chan *requests.Output, wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer func() { _ = s.Close() }()
+
+	var reported bool
 	for out := range output {
-		// Handle encoding the result as JSON
-		_ = enc.Encode(out)
+		out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
+		if !e.Config.Passive && len(out.Addresses) <= 0 {
+			continue
+		}
+		// Only the first failure is reported, since the receiver is likely unavailable for the others
+		if err := s.Write(out); err != nil && !reported {
+			reported = true
+			e.Config.Log.Printf("Failed to send the finding to the syslog receiver: %v", err)
+		}
 	}
 }
 
-func processOutput(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, outputs []chan *requests.Output, done chan struct{}, wg *sync.WaitGroup) {
+// Returns all the findings of the enumeration that would be included in the output.
+func enumFindings(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, minConf int) []*requests.Output {
+	var results []*requests.Output
+
+	for _, o := range ExtractOutput(ctx, g, e, nil, true, 0) {
+		if includeFinding(e, o, minConf) {
+			results = append(results, o)
+		}
+	}
+	return results
+}
+
+func includeFinding(e *enum.Enumeration, o *requests.Output, minConf int) bool {
+	return o.Complete(e.Config.Passive) && e.Config.IsDomainInScope(o.Name) && o.Confidence >= minConf
+}
+
+func processOutput(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, minConf int, outputs []chan *requests.Output, done chan struct{}, wg *sync.WaitGroup) {
 	defer wg.Done()
 	defer func() {
 		// Signal all the other output goroutines to terminate
@@ -540,15 +1121,17 @@ func processOutput(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, ou
 	known := stringset.New()
 	defer known.Close()
 	// The function that obtains output from the enum and puts it on the channel
-	extract := func(limit int) {
-		for _, o := range ExtractOutput(ctx, g, e, known, true, limit) {
-			if !o.Complete(e.Config.Passive) || !e.Config.IsDomainInScope(o.Name) {
+	extract := func(limit int) int {
+		results := ExtractOutput(ctx, g, e, known, true, limit)
+		for _, o := range results {
+			if !includeFinding(e, o, minConf) {
 				continue
 			}
 			for _, ch := range outputs {
 				ch <- o
 			}
 		}
+		return len(results)
 	}
 
 	t := time.NewTicker(3 * time.Second)
@@ -562,7 +1145,12 @@ func processOutput(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, ou
 			extract(0)
 			return
 		case <-t.C:
-			extract(100)
+			// Keep up with the discoveries, so the findings are written as they are confirmed
+			for extract(outputBatchSize) == outputBatchSize {
+				if ctx.Err() != nil {
+					break
+				}
+			}
 		}
 	}
 }
@@ -633,6 +1221,18 @@ func processEnumInputFiles(args *enumArgs) error {
 			args.AltWordList.InsertMany(list...)
 		}
 	}
+	if !args.Options.NoAlts && len(args.Filepaths.AltRules) > 0 {
+		for _, f := range args.Filepaths.AltRules {
+			list, err := config.GetListFromFile(f)
+			if err != nil {
+				return fmt.Errorf("failed to parse the alteration rules file: %v", err)
+			}
+			if _, err := config.ParseAlterationRules(list); err != nil {
+				return err
+			}
+			args.AltRules = append(args.AltRules, list...)
+		}
+	}
 	if args.Filepaths.Blacklist != "" {
 		list, err := config.GetListFromFile(args.Filepaths.Blacklist)
 		if err != nil {
@@ -684,6 +1284,59 @@ func processEnumInputFiles(args *enumArgs) error {
 	return nil
 }
 
+// Applies the UUID and domains of the checkpoint saved by the interrupted enumeration.
+// Expands the templates of the output paths using the settings of the enumeration, so the scheduled
+// enumerations and those of different clients organize their output without wrapper scripts.
+func expandEnumPaths(cfg *config.Config, args *enumArgs) error {
+	data := cfg.OutputPathData(time.Now())
+
+	dir, err := config.ExpandOutputPath(cfg.Dir, data)
+	if err != nil {
+		return err
+	}
+	cfg.Dir = dir
+
+	for _, path := range []*string{
+		&args.Filepaths.AllFilePrefix,
+		&args.Filepaths.CSVOutput,
+		&args.Filepaths.JSONOutput,
+		&args.Filepaths.LogFile,
+		&args.Filepaths.NQuadsOutput,
+		&args.Filepaths.Progress,
+		&args.Filepaths.TermOut,
+	} {
+		expanded, err := config.ExpandOutputPath(*path, data)
+		if err != nil {
+			return err
+		}
+		// The templates can select directories that do not exist yet
+		if expanded != *path {
+			if err := os.MkdirAll(filepath.Dir(expanded), 0755); err != nil {
+				return fmt.Errorf("failed to create the directory of %s: %v", expanded, err)
+			}
+		}
+		*path = expanded
+	}
+	return nil
+}
+
+func resumeConfig(cfg *config.Config) error {
+	path := enum.CheckpointPath(cfg)
+
+	cp, err := enum.LoadCheckpoint(path)
+	if err != nil {
+		return fmt.Errorf("failed to load the checkpoint %s: %v", path, err)
+	}
+
+	id, err := uuid.Parse(cp.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to parse the UUID of the checkpoint: %v", err)
+	}
+	cfg.UUID = id
+	cfg.AddDomains(cp.Domains...)
+	return nil
+}
+
 // Setup the amass enumeration settings
 func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if len(e.Addresses) > 0 {
@@ -701,6 +1354,16 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.Filepaths.Directory != "" {
 		conf.Dir = e.Filepaths.Directory
 	}
+	if e.Project != "" {
+		conf.Project = e.Project
+	}
+	if conf.Project != "" {
+		dir, err := config.ProjectDirectory(conf.Dir, conf.Project)
+		if err != nil {
+			return err
+		}
+		conf.Dir = dir
+	}
 	if e.Filepaths.ScriptsDirectory != "" {
 		conf.ScriptsDirectory = e.Filepaths.ScriptsDirectory
 	}
@@ -713,6 +1376,9 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.AltWordList.Len() > 0 {
 		conf.AltWordlist = e.AltWordList.Slice()
 	}
+	if len(e.AltRules) > 0 {
+		conf.AltRules = e.AltRules
+	}
 	if e.Options.BruteForcing {
 		conf.BruteForcing = true
 	}
@@ -728,19 +1394,73 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.MaxDepth != 0 {
 		conf.MaxDepth = e.MaxDepth
 	}
+	if e.BruteShard != "" {
+		bs, err := config.ParseBruteShard(e.BruteShard)
+		if err != nil {
+			return err
+		}
+		conf.BruteShard = bs
+	}
 	if e.Options.Active {
 		conf.Active = true
 		conf.Passive = false
 	}
+	if e.Options.Authoritative {
+		conf.Authoritative = true
+	}
+	if e.Options.DNSCache {
+		conf.DNSCache = true
+	}
+	if e.Options.DiskQueue {
+		conf.DiskQueue = true
+	}
+	if e.Options.DNSSEC {
+		conf.DNSSEC = true
+	}
+	if e.Options.PreferIPv6 {
+		conf.PreferIPv6 = true
+	}
+	if e.Options.Probe {
+		conf.HTTPProbes = true
+	}
+	if len(e.ProbePorts) > 0 {
+		conf.HTTPProbePorts = e.ProbePorts
+	}
+	if e.Options.PortScan {
+		conf.PortScan = true
+	}
+	if len(e.ScanPorts) > 0 {
+		conf.PortScanPorts = e.ScanPorts
+	}
+	if e.ScanRate > 0 {
+		conf.PortScanRate = e.ScanRate
+	}
+	if e.Options.Takeovers {
+		conf.Takeovers = true
+	}
 	if e.Options.Passive {
 		conf.Passive = true
 		conf.Active = false
+		conf.Authoritative = false
 		conf.BruteForcing = false
 		conf.Alterations = false
+		conf.DNSSEC = false
+		conf.HTTPProbes = false
+		conf.PortScan = false
 	}
 	if e.Blacklist.Len() > 0 {
 		conf.Blacklist = e.Blacklist.Slice()
 	}
+	for _, pattern := range e.BlacklistGlobs {
+		if err := conf.BlacklistGlob(pattern); err != nil {
+			return fmt.Errorf("the blacklisted glob %s is not valid: %v", pattern, err)
+		}
+	}
+	for _, expr := range e.BlacklistRegexps {
+		if err := conf.BlacklistRegex(expr); err != nil {
+			return fmt.Errorf("the blacklisted regex %s is not valid: %v", expr, err)
+		}
+	}
 	if e.Options.Verbose {
 		conf.Verbose = true
 	}
@@ -750,6 +1470,9 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.TrustedQPS > 0 {
 		conf.TrustedQPS = e.TrustedQPS
 	}
+	if e.Confirmations > 0 {
+		conf.TrustedConfirmations = e.Confirmations
+	}
 	if e.Resolvers.Len() > 0 {
 		conf.SetResolvers(e.Resolvers.Slice()...)
 	}
@@ -759,6 +1482,24 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.MaxDNSQueries > 0 {
 		conf.MaxDNSQueries = e.MaxDNSQueries
 	}
+	if e.MaxGuesses > 0 {
+		conf.MaxGuesses = e.MaxGuesses
+	}
+	if e.DNSBudget > 0 {
+		conf.DNSBudget = e.DNSBudget
+	}
+	if e.Freshness > 0 {
+		conf.FreshnessHours = e.Freshness
+	}
+	if e.BruteTimeout > 0 {
+		conf.BruteTimeout = time.Duration(e.BruteTimeout) * time.Minute
+	}
+	if e.AltTimeout > 0 {
+		conf.AltTimeout = time.Duration(e.AltTimeout) * time.Minute
+	}
+	if e.Checkpoint > 0 {
+		conf.CheckpointInterval = time.Duration(e.Checkpoint) * time.Minute
+	}
 	if e.Included.Len() > 0 {
 		conf.SourceFilter.Include = true
 		// Check if brute forcing and alterations should be added
@@ -782,5 +1523,31 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	}
 	// Attempt to add the provided domains to the configuration
 	conf.AddDomains(e.Domains.Slice()...)
+	// The scope file adds to the scope provided by the other flags and the configuration file
+	if e.Filepaths.Scope != "" {
+		if err := conf.LoadScopeFile(e.Filepaths.Scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patternList implements the flag.Value interface for the patterns that can contain commas.
+type patternList []string
+
+func (p *patternList) String() string {
+	if p == nil {
+		return ""
+	}
+	return strings.Join(*p, " ")
+}
+
+// Set implements the flag.Value interface.
+func (p *patternList) Set(s string) error {
+	if s == "" {
+		return fmt.Errorf("the pattern cannot be empty")
+	}
+
+	*p = append(*p, s)
 	return nil
 }
@@ -15,12 +15,26 @@ func runHelpCommand(clArgs []string) {
 		return
 	}
 	switch clArgs[0] {
+	case "api":
+		runAPICommand(help)
+	case "config":
+		runConfigCommand(help)
+	case "daemon":
+		runDaemonCommand(help)
 	case "db":
 		runDBCommand(help)
+	case "diff":
+		runDiffCommand(help)
+	case "distribute":
+		runDistributeCommand(help)
+	case "dns":
+		runDNSBenchmarkCommand(help)
 	case "enum":
 		runEnumCommand(help)
 	case "intel":
 		runIntelCommand(help)
+	case "tag":
+		runTagCommand(help)
 	case "track":
 		runTrackCommand(help)
 	case "viz":
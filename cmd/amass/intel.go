@@ -22,6 +22,7 @@ import (
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/datasrcs"
 	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/integrations"
 	"github.com/OWASP/Amass/v3/intel"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/stringset"
@@ -44,6 +45,8 @@ type intelArgs struct {
 	Ports            format.ParseInts
 	Resolvers        *stringset.Set
 	Timeout          int
+	Project          string
+	Profile          string
 	Options          struct {
 		Active       bool
 		DemoMode     bool
@@ -94,8 +97,10 @@ func defineIntelOptionFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 }
 
 func defineIntelFilepathFlags(intelFlags *flag.FlagSet, args *intelArgs) {
-	intelFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	intelFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
 	intelFlags.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+	intelFlags.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
+	intelFlags.StringVar(&args.Profile, "profile", "", "Name of the configuration file profile applied over its other settings")
 	intelFlags.Var(&args.Filepaths.Domains, "df", "Path to a file providing root domain names")
 	intelFlags.StringVar(&args.Filepaths.ExcludedSrcs, "ef", "", "Path to a file providing data sources to exclude")
 	intelFlags.StringVar(&args.Filepaths.IncludedSrcs, "if", "", "Path to a file providing data sources to include")
@@ -149,13 +154,14 @@ func runIntelCommand(clArgs []string) {
 	}
 
 	cfg := config.NewConfig()
+	cfg.Profile = args.Profile
 	// Check if a configuration file was provided, and if so, load the settings
 	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
 		// Check if a config file was provided that has DNS resolvers specified
 		if len(cfg.Resolvers) > 0 && args.Resolvers.Len() == 0 {
 			args.Resolvers = stringset.New(cfg.Resolvers...)
 		}
-	} else if args.Filepaths.ConfigFile != "" {
+	} else if args.Filepaths.ConfigFile != "" || args.Profile != "" {
 		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
 		os.Exit(1)
 	}
@@ -165,6 +171,11 @@ func runIntelCommand(clArgs []string) {
 		r.Fprintf(color.Error, "Configuration error: %v\n", err)
 		os.Exit(1)
 	}
+	// Retrieve the data source credentials stored by the secrets backend
+	if err := integrations.LoadSecrets(context.Background(), cfg); err != nil {
+		r.Fprintf(color.Error, "Configuration error: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Some input validation
 	if !args.Options.ReverseWhois && args.OrganizationName == "" && !args.Options.ListSources &&
@@ -379,6 +390,16 @@ func (i intelArgs) OverrideConfig(conf *config.Config) error {
 	if i.Filepaths.Directory != "" {
 		conf.Dir = i.Filepaths.Directory
 	}
+	if i.Project != "" {
+		conf.Project = i.Project
+	}
+	if conf.Project != "" {
+		dir, err := config.ProjectDirectory(conf.Dir, conf.Project)
+		if err != nil {
+			return err
+		}
+		conf.Dir = dir
+	}
 	if i.Options.Verbose {
 		conf.Verbose = true
 	}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"math/rand"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/OWASP/Amass/v3/enum"
@@ -66,6 +67,9 @@ func EventOutput(ctx context.Context, g *netmap.Graph, uuid string, f *stringset
 			}
 		}
 	}
+	for _, o := range lookup {
+		setConfidence(ctx, g, o)
+	}
 
 	if !asninfo || cache == nil {
 		return removeDuplicates(lookup, f)
@@ -118,6 +122,7 @@ func addInfrastructureInfo(lookup outLookup, filter *stringset.Set, cache *reque
 				CIDRStr:     i.Prefix,
 				Netblock:    netblock,
 				Description: i.Description,
+				Confidence:  a.Confidence,
 			})
 		}
 
@@ -149,6 +154,7 @@ func EventNames(ctx context.Context, g *netmap.Graph, uuid string, f *stringset.
 	var results []*requests.Output
 	for _, o := range buildNameInfo(ctx, g, uuid, names) {
 		if !f.Has(o.Name) {
+			setConfidence(ctx, g, o)
 			results = append(results, o)
 			f.Insert(o.Name)
 		}
@@ -182,6 +188,8 @@ func buildNameInfo(ctx context.Context, g *netmap.Graph, uuid string, names []st
 		o.Domain = d
 
 		o.Tag = selectTag(o.Sources)
+		o.Takeovers = enum.ReadTakeovers(ctx, g, o.Name)
+		o.HTTP = enum.ReadHTTPServices(ctx, g, o.Name)
 		final = append(final, o)
 	}
 	return final
@@ -194,12 +202,54 @@ func initializeSourceTags(srcs []service.Service) {
 	sourceTags["DNS Zone XFR"] = requests.AXFR
 	sourceTags["Active Crawl"] = requests.CRAWL
 	sourceTags["Active Cert"] = requests.CERT
+	sourceTags["Alteration Rules"] = requests.ALT
+	sourceTags["Alteration Keywords"] = requests.ALT
 
 	for _, src := range srcs {
 		sourceTags[src.String()] = src.Description()
 	}
 }
 
+// Sets the confidence of the name and addresses using the values stored in the graph. When the
+// enumeration has not scored the name yet, the confidence is computed from the sources.
+func setConfidence(ctx context.Context, g *netmap.Graph, o *requests.Output) {
+	o.Confidence = storedConfidence(ctx, g, o.Name)
+	if o.Confidence == 0 {
+		var tags []string
+		for _, src := range o.Sources {
+			tags = append(tags, sourceTags[src])
+		}
+		o.Confidence = requests.Confidence(len(o.Addresses) > 0, tags)
+	}
+
+	for i, a := range o.Addresses {
+		if a.Address == nil {
+			continue
+		}
+		if c := storedConfidence(ctx, g, a.Address.String()); c > 0 {
+			o.Addresses[i].Confidence = c
+		} else {
+			o.Addresses[i].Confidence = o.Confidence
+		}
+	}
+}
+
+// Returns the highest confidence stored for the node by the enumerations, or zero when not available.
+func storedConfidence(ctx context.Context, g *netmap.Graph, id string) int {
+	var conf int
+
+	if props, err := g.ReadProperties(ctx, netmap.Node(id), requests.ConfidencePredicate); err == nil {
+		for _, p := range props {
+			if v, ok := p.Value.Native().(string); ok {
+				if c, err := strconv.Atoi(v); err == nil && c > conf {
+					conf = c
+				}
+			}
+		}
+	}
+	return conf
+}
+
 func selectTag(sources []string) string {
 	var trusted, others []string
 
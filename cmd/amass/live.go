@@ -0,0 +1,144 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/viz"
+	"github.com/fatih/color"
+)
+
+const (
+	// The graph database is locked by a running enumeration, so it is read less often
+	liveDatabaseInterval = 30 * time.Second
+	liveFindingsInterval = 2 * time.Second
+)
+
+// The graph presented by the live web view, which combines the graph database
+// with the findings written to the JSON output file of a running enumeration.
+type liveGraph struct {
+	sync.Mutex
+	nodes    []viz.Node
+	edges    []viz.Edge
+	findings []*viz.Finding
+}
+
+func (lg *liveGraph) load() ([]viz.Node, []viz.Edge) {
+	lg.Lock()
+	defer lg.Unlock()
+
+	return viz.MergeFindings(lg.nodes, lg.edges, lg.findings)
+}
+
+func serveLiveViz(args *vizArgs, cfg *config.Config, f *viz.Filter) {
+	lg := new(liveGraph)
+
+	go lg.readDatabase(args, cfg)
+	path := args.Filepaths.JSONOutput
+	if path == "" {
+		path = filepath.Join(config.OutputDirectory(args.Filepaths.Directory), "amass.json")
+	}
+	go lg.tailFindings(path, args)
+
+	g.Fprintf(color.Error, "The live view of the graph is available at http://%s/\n", args.Serve)
+	if err := serveHTTP(args.Serve, viz.NewServer(func() ([]viz.Node, []viz.Edge) {
+		nodes, edges := lg.load()
+		// The selected node can be absent until it is discovered
+		if nodes, edges, err := selectVizGraph(nodes, edges, args, f); err == nil {
+			return nodes, edges
+		}
+		return nil, nil
+	})); err != nil {
+		r.Fprintf(color.Error, "The live view server failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Periodically replaces the graph with the data read from the graph database. Opening the local
+// database waits for a running enumeration to release it, so the graph is updated once it finishes.
+func (lg *liveGraph) readDatabase(args *vizArgs, cfg *config.Config) {
+	for {
+		if nodes, edges, err := loadVizGraph(args, cfg); err == nil {
+			lg.Lock()
+			lg.nodes, lg.edges = nodes, edges
+			lg.Unlock()
+		}
+		time.Sleep(liveDatabaseInterval)
+	}
+}
+
+// Follows the JSON output file of the enumeration, adding each finding as soon as it is written.
+// The findings are discarded when the file is truncated by the start of another enumeration.
+func (lg *liveGraph) tailFindings(path string, args *vizArgs) {
+	var offset int64
+
+	for {
+		if offset = lg.readFindings(path, offset, args); offset < 0 {
+			offset = 0
+			continue
+		}
+		time.Sleep(liveFindingsInterval)
+	}
+}
+
+// Returns the offset following the last complete line, or -1 when the file was truncated.
+func (lg *liveGraph) readFindings(path string, offset int64, args *vizArgs) int64 {
+	f, err := os.Open(path)
+	if err != nil {
+		return offset
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return offset
+	}
+	if info.Size() < offset {
+		lg.Lock()
+		lg.findings = nil
+		lg.Unlock()
+		return -1
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset
+	}
+
+	var findings []*viz.Finding
+	now := time.Now()
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		// A partial line is read again once the enumeration has finished writing it
+		if err != nil {
+			break
+		}
+		offset += int64(len(line))
+
+		var o requests.Output
+		if json.Unmarshal(line, &o) != nil || o.Name == "" {
+			continue
+		}
+		if args.Domains.Len() > 0 && !args.Domains.Has(o.Domain) {
+			continue
+		}
+		findings = append(findings, &viz.Finding{Output: &o, Seen: now})
+	}
+
+	if len(findings) > 0 {
+		lg.Lock()
+		lg.findings = append(lg.findings, findings...)
+		lg.Unlock()
+	}
+	return offset
+}
@@ -30,9 +30,7 @@ import (
 	"net"
 	"os"
 	"path"
-	"sort"
 	"strings"
-	"time"
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/datasrcs"
@@ -48,7 +46,7 @@ import (
 )
 
 const (
-	mainUsageMsg         = "intel|enum|viz|track|db [options]"
+	mainUsageMsg         = "intel|enum|viz|track|diff|db|tag|dns|daemon|api|distribute [options]"
 	exampleConfigFileURL = "https://github.com/OWASP/Amass/blob/master/examples/config.ini"
 	userGuideURL         = "https://github.com/OWASP/Amass/blob/master/doc/user_guide.md"
 	tutorialURL          = "https://github.com/OWASP/Amass/blob/master/doc/tutorial.md"
@@ -79,7 +77,14 @@ func commandUsage(msg string, cmdFlagSet *flag.FlagSet, errBuf *bytes.Buffer) {
 		g.Fprintf(color.Error, "\t%-11s - Perform enumerations and network mapping\n", "amass enum")
 		g.Fprintf(color.Error, "\t%-11s - Visualize enumeration results\n", "amass viz")
 		g.Fprintf(color.Error, "\t%-11s - Track differences between enumerations\n", "amass track")
+		g.Fprintf(color.Error, "\t%-11s - Compare two enumerations or databases\n", "amass diff")
 		g.Fprintf(color.Error, "\t%-11s - Manipulate the Amass graph database\n", "amass db")
+		g.Fprintf(color.Error, "\t%-11s - Tag and annotate names and addresses\n", "amass tag")
+		g.Fprintf(color.Error, "\t%-11s - Benchmark and rank DNS resolvers\n", "amass dns")
+		g.Fprintf(color.Error, "\t%-11s - Execute the scheduled enumerations\n", "amass daemon")
+		g.Fprintf(color.Error, "\t%-11s - Serve the REST API managing the enumerations\n", "amass api")
+		g.Fprintf(color.Error, "\t%-11s - Divide an enumeration across the gRPC APIs of several workers\n", "amass distribute")
+		g.Fprintf(color.Error, "\t%-11s - Validate the configuration file or convert it to the YAML format\n", "amass config")
 	}
 
 	g.Fprintln(color.Error)
@@ -117,12 +122,26 @@ func main() {
 	}
 
 	switch os.Args[1] {
+	case "api":
+		runAPICommand(os.Args[2:])
+	case "config":
+		runConfigCommand(os.Args[2:])
+	case "daemon":
+		runDaemonCommand(os.Args[2:])
 	case "db":
 		runDBCommand(os.Args[2:])
+	case "diff":
+		runDiffCommand(os.Args[2:])
+	case "distribute":
+		runDistributeCommand(os.Args[2:])
+	case "dns":
+		runDNSCommand(os.Args[2:])
 	case "enum":
 		runEnumCommand(os.Args[2:])
 	case "intel":
 		runIntelCommand(os.Args[2:])
+	case "tag":
+		runTagCommand(os.Args[2:])
 	case "track":
 		runTrackCommand(os.Args[2:])
 	case "viz":
@@ -197,6 +216,28 @@ func createOutputDirectory(cfg *config.Config) {
 	}
 }
 
+// Returns the directory of the project selected on the command line or in the configuration,
+// or the provided directory when no project was selected.
+func projectDirectory(dir, project string, cfg *config.Config) string {
+	if project == "" {
+		project = cfg.Project
+	}
+	if project == "" {
+		return dir
+	}
+
+	pdir, err := config.ProjectDirectory(dir, project)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to select the project: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := os.Stat(pdir); err != nil {
+		r.Fprintf(color.Error, "The project %s does not exist in the output directory\n", project)
+		os.Exit(1)
+	}
+	return pdir
+}
+
 func generateCategoryMap(sys systems.System) map[string][]string {
 	catToSources := make(map[string][]string)
 
@@ -230,7 +271,13 @@ func openGraphDatabase(dir string, cfg *config.Config) *netmap.Graph {
 			continue
 		}
 
-		cayley := netmap.NewCayleyGraph(db.System, db.URL, db.Options)
+		u, err := db.ConnectionURL()
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			return nil
+		}
+
+		cayley := netmap.NewCayleyGraph(db.System, u, db.Options)
 		if cayley == nil {
 			return nil
 		}
@@ -278,31 +325,7 @@ func memGraphForScope(ctx context.Context, domains []string, from *netmap.Graph)
 	return db, nil
 }
 
-func orderedEvents(ctx context.Context, events []string, db *netmap.Graph) ([]string, []time.Time, []time.Time) {
-	sort.Slice(events, func(i, j int) bool {
-		var less bool
-
-		e1, l1 := db.EventDateRange(ctx, events[i])
-		e2, l2 := db.EventDateRange(ctx, events[j])
-		if l2.After(l1) || e1.Before(e2) {
-			less = true
-		}
-
-		return less
-	})
-
-	var earliest, latest []time.Time
-	for _, event := range events {
-		e, l := db.EventDateRange(ctx, event)
-
-		earliest = append(earliest, e)
-		latest = append(latest, l)
-	}
-
-	return events, earliest, latest
-}
-
-func getEventOutput(ctx context.Context, uuids []string, asninfo bool, db *netmap.Graph, cache *requests.ASNCache) []*requests.Output {
+func getEventOutput(ctx context.Context, uuids []string, asninfo bool, tf *tagFilter, db *netmap.Graph, cache *requests.ASNCache) []*requests.Output {
 	filter := stringset.New()
 	defer filter.Close()
 
@@ -310,7 +333,7 @@ func getEventOutput(ctx context.Context, uuids []string, asninfo bool, db *netma
 	for i := len(uuids) - 1; i >= 0; i-- {
 		output = append(output, EventOutput(ctx, db, uuids[i], filter, asninfo, cache, 0)...)
 	}
-	return output
+	return tagOutput(ctx, db, output, tf)
 }
 
 func domainNameInScope(name string, scope []string) bool {
@@ -0,0 +1,86 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/fatih/color"
+)
+
+func importNmapFile(path string, db *netmap.Graph) {
+	f, err := os.Open(path)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to open the nmap XML file: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	hosts, err := format.ParseNmapXML(f)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	matched, ports, err := importNmapHosts(context.Background(), db, hosts)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to import the nmap results: %v\n", err)
+		os.Exit(1)
+	}
+	g.Printf("%d open ports were attached to %d of the %d scanned addresses in the %s database\n",
+		ports, matched, len(hosts), db.String())
+}
+
+// Attaches the open ports to the address nodes already in the graph and returns the number of addresses
+// and ports updated. The ports observed previously are replaced, since the scan provides the current state.
+func importNmapHosts(ctx context.Context, db *netmap.Graph, hosts []*format.NmapHost) (int, int, error) {
+	var matched, ports int
+
+	for _, h := range hosts {
+		node, err := db.ReadNode(ctx, h.Address, netmap.TypeAddr)
+		if err != nil {
+			// The scan included an address that was not discovered by Amass
+			continue
+		}
+
+		if props, err := db.ReadProperties(ctx, node, enum.OpenPortPredicate); err == nil {
+			for _, p := range props {
+				_ = db.DeleteProperty(ctx, node, p.Predicate, p.Value)
+			}
+		}
+		for _, p := range h.Ports {
+			if err := db.UpsertProperty(ctx, node, enum.OpenPortPredicate, p.String()); err != nil {
+				return matched, ports, err
+			}
+			ports++
+		}
+		matched++
+	}
+	return matched, ports, nil
+}
+
+func writeScanTargets(args *dbArgs, assets []*requests.Output) error {
+	var out io.Writer = os.Stdout
+	// Write to STDOUT and not a file if named "-"
+	if args.Filepaths.Targets != "-" {
+		f, err := os.OpenFile(args.Filepaths.Targets, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Sync()
+			_ = f.Close()
+		}()
+		out = f
+	}
+
+	return format.WriteScanTargets(out, assets, args.TargetsFormat)
+}
@@ -0,0 +1,214 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/fatih/color"
+)
+
+// The number of resolvers printed before the remaining resolvers are only counted.
+const planMaxResolvers = 20
+
+// Prints the work the enumeration would perform using the configuration, without starting the data
+// sources or sending any requests, so the settings can be reviewed before consuming the API quotas.
+func printEnumPlan(cfg *config.Config, args *enumArgs) {
+	if err := cfg.CheckSettings(); err != nil {
+		r.Fprintf(color.Error, "Configuration error: %v\n", err)
+		os.Exit(1)
+	}
+
+	planLine("Domains", strings.Join(cfg.Domains(), ", "))
+	mode := "normal"
+	if cfg.Passive {
+		mode = "passive"
+	} else if cfg.Active {
+		mode = "active"
+	}
+	planLine("Mode", mode)
+	if cfg.Profile != "" {
+		planLine("Profile", cfg.Profile)
+	}
+	timeout := "none"
+	if args.Timeout > 0 {
+		timeout = fmt.Sprintf("%d minutes", args.Timeout)
+	}
+	planLine("Timeout", timeout)
+
+	printPlanSources(cfg)
+	if !cfg.Passive {
+		printPlanResolvers(cfg)
+	}
+
+	brute := "disabled"
+	if cfg.BruteForcing {
+		brute = fmt.Sprintf("%d words", len(cfg.Wordlist))
+		if cfg.BruteShard != nil {
+			brute += fmt.Sprintf(" in the shard %s", cfg.BruteShard)
+		}
+		if cfg.Recursive {
+			brute += fmt.Sprintf(", recursive after %d discoveries", cfg.MinForRecursive)
+			if cfg.MaxDepth > 0 {
+				brute += fmt.Sprintf(" up to the depth of %d labels", cfg.MaxDepth)
+			}
+			if n := len(cfg.BruteLevels); n > 0 {
+				brute += fmt.Sprintf(", %d level settings", n)
+			}
+		}
+		if cfg.BruteTimeout > 0 {
+			brute += fmt.Sprintf(", for at most %s", cfg.BruteTimeout)
+		}
+	}
+	planLine("Brute forcing", brute)
+
+	alts := "disabled"
+	if cfg.Alterations {
+		alts = fmt.Sprintf("%d words, %d rules, %d keyword sets, %d guessers",
+			len(cfg.AltWordlist), len(cfg.AltRules), len(cfg.AltKeywords), len(cfg.AltGuessers))
+		if cfg.MaxGuesses > 0 {
+			alts += fmt.Sprintf(", at most %d guesses", cfg.MaxGuesses)
+		}
+		if cfg.AltTimeout > 0 {
+			alts += fmt.Sprintf(", for at most %s", cfg.AltTimeout)
+		}
+	}
+	planLine("Alterations", alts)
+
+	var active []string
+	if cfg.Active {
+		var ports []string
+		for _, port := range cfg.Ports {
+			ports = append(ports, strconv.Itoa(port))
+		}
+		active = append(active, "zone transfers", "certificate grabs on ports "+strings.Join(ports, ", "))
+	}
+	if cfg.Authoritative {
+		active = append(active, "authoritative queries")
+	}
+	if cfg.ReverseSweeps && !cfg.Passive {
+		active = append(active, "reverse DNS sweeps")
+	}
+	if cfg.DNSSEC && !cfg.Passive {
+		active = append(active, "DNSSEC validation")
+	}
+	if cfg.HTTPProbes && !cfg.Passive {
+		ports := cfg.HTTPProbePorts
+		if len(ports) == 0 {
+			ports = cfg.Ports
+		}
+
+		var list []string
+		for _, port := range ports {
+			list = append(list, strconv.Itoa(port))
+		}
+		active = append(active, "web service probes on ports "+strings.Join(list, ", "))
+	}
+	if cfg.PortScan && !cfg.Passive {
+		ports := cfg.PortScanPorts
+		if len(ports) == 0 {
+			ports = config.DefaultPortScanPorts
+		}
+		rate := cfg.PortScanRate
+		if rate <= 0 {
+			rate = config.DefaultPortScanRate
+		}
+		active = append(active, fmt.Sprintf("TCP port scans of %d ports at %d connections per second", len(ports), rate))
+	}
+	if cfg.Active && cfg.Takeovers {
+		active = append(active, "subdomain takeover checks")
+	}
+	if len(active) == 0 {
+		active = append(active, "none")
+	}
+	planLine("Active techniques", strings.Join(active, ", "))
+
+	if cfg.FreshnessHours > 0 && !cfg.Passive {
+		planLine("Freshness window", fmt.Sprintf("names resolved within %d hours are not resolved again", cfg.FreshnessHours))
+	}
+	planLine("Output directory", config.OutputDirectory(cfg.Dir))
+}
+
+// Prints the data sources selected by the configuration and whether credentials were provided for them.
+func printPlanSources(cfg *config.Config) {
+	// The data sources are created without being started
+	sys := &systems.SimpleSystem{Cfg: cfg}
+	all := datasrcs.GetAllSources(sys)
+
+	categories := make(map[string][]string)
+	for _, src := range all {
+		categories[src.Description()] = append(categories[src.Description()], src.String())
+	}
+
+	filter := cfg.SourceFilter.Sources
+	cfg.SourceFilter.Sources = expandCategoryNames(filter, categories)
+	srcs := datasrcs.SelectedDataSources(cfg, all)
+	cfg.SourceFilter.Sources = filter
+
+	var keys int
+	lines := make([]string, 0, len(srcs))
+	for _, src := range srcs {
+		creds := "no credentials"
+		if dsc := cfg.GetDataSourceConfig(src.String()); dsc != nil && dsc.GetCredentials() != nil {
+			creds = "credentials"
+			keys++
+		}
+		lines = append(lines, fmt.Sprintf("    %-30s%-15s%s", green(src.String()), yellow(src.Description()), yellow(creds)))
+	}
+
+	planLine("Data sources", fmt.Sprintf("%d of %d, %d with credentials", len(srcs), len(all), keys))
+	for _, line := range lines {
+		fmt.Fprintln(color.Output, line)
+	}
+}
+
+func printPlanResolvers(cfg *config.Config) {
+	if len(cfg.Resolvers) == 0 {
+		planLine("Resolvers", "the public DNS resolvers validated at startup")
+	} else {
+		planLine("Resolvers", planList(cfg.Resolvers))
+	}
+
+	trusted := cfg.TrustedResolvers
+	if len(trusted) == 0 {
+		trusted = config.DefaultBaselineResolvers
+	}
+	planLine("Trusted resolvers", planList(trusted))
+
+	confirmations := "one trusted resolver verifies each answer"
+	if num := cfg.TrustedConfirmations; num > 1 {
+		confirmations = fmt.Sprintf("%d trusted resolvers verify each answer", num)
+	}
+	planLine("Verification", confirmations)
+
+	queries := "set by the number of resolvers"
+	if cfg.MaxDNSQueries > 0 {
+		queries = strconv.Itoa(cfg.MaxDNSQueries)
+	}
+	planLine("Maximum DNS queries", queries)
+
+	budget := "none"
+	if cfg.DNSBudget > 0 {
+		budget = fmt.Sprintf("%d queries before brute forcing and alterations stop", cfg.DNSBudget)
+	}
+	planLine("DNS query budget", budget)
+}
+
+func planList(list []string) string {
+	if len(list) <= planMaxResolvers {
+		return strings.Join(list, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(list[:planMaxResolvers], ", "), len(list)-planMaxResolvers)
+}
+
+func planLine(label, value string) {
+	fmt.Fprintf(color.Output, "%s%s\n", blue(fmt.Sprintf("%-21s", label+":")), yellow(value))
+}
@@ -0,0 +1,144 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/fatih/color"
+)
+
+const progressInterval = 5 * time.Second
+
+// progressRecord is the JSON line periodically written to the destination of the -progress flag.
+type progressRecord struct {
+	Time    string `json:"time"`
+	Stage   string `json:"stage"`
+	Paused  bool   `json:"paused"`
+	Elapsed int64  `json:"elapsed_seconds"`
+	Names   int    `json:"names"`
+	// The names and addresses waiting to enter the enumeration pipeline
+	QueuedNames int               `json:"queued_names"`
+	Resolvers   *progressPool     `json:"resolvers,omitempty"`
+	Trusted     *progressPool     `json:"trusted_resolvers,omitempty"`
+	Sources     []*progressSource `json:"sources"`
+}
+
+type progressPool struct {
+	Active  int `json:"active"`
+	Evicted int `json:"evicted"`
+	QPS     int `json:"qps"`
+	Pending int `json:"pending"`
+	// The number of queries sent to the resolvers
+	Queries int `json:"queries"`
+}
+
+type progressSource struct {
+	*requests.SourceStats
+	Backlog  int  `json:"backlog"`
+	Disabled bool `json:"disabled"`
+}
+
+// Opens the destination of the progress records, where the hyphen selects stderr. Opening a named
+// pipe blocks until the program reading the records has opened the other end.
+func openProgressOutput(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return os.Stderr, nil
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+}
+
+// Writes a progress record at each interval and after the enumeration has finished.
+func writeProgress(e *enum.Enumeration, args *enumArgs, out io.WriteCloser, output chan *requests.Output, wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer func() {
+		if out != os.Stderr {
+			_ = out.Close()
+		}
+	}()
+
+	var names int
+	var failed bool
+	start := time.Now()
+	enc := json.NewEncoder(out)
+	write := func() {
+		if failed {
+			return
+		}
+		if err := enc.Encode(newProgressRecord(e, start, names)); err != nil {
+			r.Fprintf(color.Error, "Failed to write the progress: %v\n", err)
+			// The output continues to be drained after the reader has gone away
+			failed = true
+		}
+	}
+
+	t := time.NewTicker(progressInterval)
+	defer t.Stop()
+	for {
+		select {
+		case o, ok := <-output:
+			if !ok {
+				write()
+				return
+			}
+
+			addrs := format.DesiredAddrTypes(o.Addresses, args.Options.IPv4, args.Options.IPv6)
+			if e.Config.Passive || len(addrs) > 0 {
+				names++
+			}
+		case <-t.C:
+			write()
+		}
+	}
+}
+
+func newProgressRecord(e *enum.Enumeration, start time.Time, names int) *progressRecord {
+	p := e.Progress()
+	rec := &progressRecord{
+		Time:        time.Now().UTC().Format(time.RFC3339),
+		Stage:       p.Stage,
+		Paused:      p.Paused,
+		Elapsed:     int64(time.Since(start).Seconds()),
+		Names:       names,
+		QueuedNames: p.QueuedNames,
+		Sources:     []*progressSource{},
+	}
+	if !e.Config.Passive {
+		rec.Resolvers = newProgressPool(e.Sys.Resolvers())
+		rec.Trusted = newProgressPool(e.Sys.TrustedResolvers())
+	}
+
+	for _, s := range e.SourceStats() {
+		rec.Sources = append(rec.Sources, &progressSource{
+			SourceStats: s,
+			Backlog:     p.SourceBacklog[s.Source],
+			Disabled:    e.SourceDisabled(s.Source),
+		})
+	}
+	return rec
+}
+
+func newProgressPool(p *resolvers.Pool) *progressPool {
+	if p == nil {
+		return nil
+	}
+
+	evicted := len(p.Evicted())
+	return &progressPool{
+		Active:  p.Len() - evicted,
+		Evicted: evicted,
+		QPS:     p.QPS(),
+		Pending: p.Pending(),
+		Queries: p.Queries(),
+	}
+}
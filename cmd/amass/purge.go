@@ -0,0 +1,181 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+)
+
+// purgeTargets contains the data selected for removal by the purge flags of the db subcommand.
+type purgeTargets struct {
+	// The events removed along with the nodes only discovered during them
+	Events []string
+	// The events that remain, but no longer include the purged domain
+	Unscoped []string
+	Names    []string
+}
+
+// Returns true when one of the purge flags was provided.
+func (a *dbArgs) purge() bool {
+	return a.PurgeEnum > 0 || a.PurgeDomain != "" || a.PurgeNames != ""
+}
+
+func purgeDatabase(args *dbArgs, cfg *config.Config, db *netmap.Graph) {
+	ctx := context.Background()
+
+	targets, err := selectPurgeTargets(ctx, args, cfg, db)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if len(targets.Events) == 0 && len(targets.Unscoped) == 0 && len(targets.Names) == 0 {
+		r.Println("No data matched the purge flags")
+		return
+	}
+
+	if args.Options.DryRun {
+		for _, event := range targets.Events {
+			earliest, latest := enum.EventDateRange(ctx, db, event)
+			fmt.Fprintf(color.Output, "%s%s %s -> %s: %s\n", blue("Event: "), green(event), yellow(earliest.Format(timeFormat)),
+				yellow(latest.Format(timeFormat)), yellow(strings.Join(db.EventDomains(ctx, event), ", ")))
+		}
+		for _, event := range targets.Unscoped {
+			fmt.Fprintf(color.Output, "%s%s %s\n", blue("Scope: "), green(event), yellow("(the domain is removed from the event)"))
+		}
+		for _, name := range targets.Names {
+			fmt.Fprintf(color.Output, "%s%s\n", blue("Name: "), green(name))
+		}
+		g.Printf("%d events and %d names would be removed from the %s database\n", len(targets.Events), len(targets.Names), db.String())
+		return
+	}
+
+	if err := enum.RemoveEvents(ctx, db, targets.Events); err != nil {
+		r.Fprintf(color.Error, "Failed to remove the events: %v\n", err)
+		os.Exit(1)
+	}
+	for _, event := range targets.Unscoped {
+		if err := removeEventDomain(ctx, db, event, args.PurgeDomain); err != nil {
+			r.Fprintf(color.Error, "Failed to remove the domain from the event %s: %v\n", event, err)
+			os.Exit(1)
+		}
+	}
+	for _, name := range targets.Names {
+		if err := enum.DeleteGraphNode(ctx, db, name); err != nil {
+			r.Fprintf(color.Error, "Failed to remove the name %s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+	g.Printf("%d events and %d names were removed from the %s database\n", len(targets.Events), len(targets.Names), db.String())
+}
+
+// Returns the events and names selected by the purge flags. Each flag adds to the selection.
+func selectPurgeTargets(ctx context.Context, args *dbArgs, cfg *config.Config, db *netmap.Graph) (*purgeTargets, error) {
+	targets := new(purgeTargets)
+	events := stringset.New()
+	defer events.Close()
+	names := stringset.New()
+	defer names.Close()
+
+	if args.PurgeEnum > 0 {
+		event, err := listedEvent(ctx, db, args.Domains.Slice(), args.PurgeEnum)
+		if err != nil {
+			return nil, err
+		}
+		events.Insert(event)
+	}
+
+	if d := strings.ToLower(strings.TrimSpace(args.PurgeDomain)); d != "" {
+		args.PurgeDomain = d
+
+		for _, event := range db.EventsInScope(ctx, d) {
+			// Events including other domains keep the findings of those domains
+			only := true
+			for _, domain := range db.EventDomains(ctx, event) {
+				if domain != d && !strings.HasSuffix(domain, "."+d) {
+					only = false
+					break
+				}
+			}
+
+			if only {
+				events.Insert(event)
+			} else {
+				targets.Unscoped = append(targets.Unscoped, event)
+			}
+		}
+		if nodes, err := db.AllNodesOfType(ctx, netmap.TypeFQDN); err == nil {
+			for _, node := range nodes {
+				if name := db.NodeToID(node); name == d || strings.HasSuffix(name, "."+d) {
+					names.Insert(name)
+				}
+			}
+		}
+	}
+
+	if args.PurgeNames != "" {
+		re, err := searchRegexp(args.PurgeNames, args.Options.Glob)
+		if err != nil {
+			return nil, fmt.Errorf("the purge pattern is not valid: %v", err)
+		}
+
+		// The names already purged remain in the graph without belonging to any event
+		uuids := db.EventList(ctx)
+		if args.Domains.Len() > 0 {
+			if uuids = db.EventsInScope(ctx, args.Domains.Slice()...); len(uuids) == 0 {
+				return nil, fmt.Errorf("failed to find the domains of interest in the database")
+			}
+		}
+		for _, res := range searchNames(ctx, db, cfg, re, uuids) {
+			names.Insert(res.Name)
+		}
+	}
+
+	targets.Events = events.Slice()
+	targets.Names = names.Slice()
+	sort.Strings(targets.Names)
+	return targets, nil
+}
+
+// Returns the event identified by the index from the db -list output.
+func listedEvent(ctx context.Context, db *netmap.Graph, domains []string, index int) (string, error) {
+	memDB, err := memGraphForScope(ctx, domains, db)
+	if err != nil {
+		return "", err
+	}
+	defer memDB.Close()
+
+	events, _, _ := enum.OrderedEvents(ctx, memDB.EventList(ctx), memDB)
+	if index > len(events) {
+		return "", fmt.Errorf("%d enumerations are available", len(events))
+	}
+	return events[len(events)-index], nil
+}
+
+// Removes the edge including the domain in the scope of the event.
+func removeEventDomain(ctx context.Context, db *netmap.Graph, event, domain string) error {
+	edges, err := db.ReadOutEdges(ctx, netmap.Node(event), "domain")
+	if err != nil {
+		return err
+	}
+
+	for _, edge := range edges {
+		if db.NodeToID(edge.To) == domain {
+			if err := db.DeleteEdge(ctx, edge); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/integrations"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+)
+
+// Pushes the findings into each of the integrations and reports the outcome.
+func pushFindings(ctx context.Context, ins []integrations.Integration, output []*requests.Output) {
+	for _, in := range ins {
+		num, err := in.Push(ctx, output)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to push the findings into %s: %v\n", in, err)
+			continue
+		}
+		g.Fprintf(color.Error, "%d findings were pushed into %s\n", num, in)
+	}
+}
+
+// Notifies the webhooks of the names, addresses and netblocks in the findings that were not present
+// in any of the graph databases before the enumeration, and posts the summary of the enumeration.
+func notifyNewAssets(e *enum.Enumeration, hooks []*integrations.Webhook, output chan *requests.Output, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	found := make(map[string]int)
+	discovered := make(map[string]int)
+	defer func() {
+		summary := fmt.Sprintf("OWASP Amass finished the enumeration of %s: %d names and %d addresses "+
+			"were discovered, including %d new names, %d new addresses and %d new netblocks",
+			strings.Join(e.Config.Domains(), ", "), found["fqdn"], found["address"],
+			discovered["fqdn"], discovered["address"], discovered["netblock"])
+
+		for _, wh := range hooks {
+			if err := wh.Message(summary); err != nil {
+				r.Fprintf(color.Error, "The %s webhook failed: %v\n", wh, err)
+			}
+			for _, err := range wh.Close() {
+				r.Fprintf(color.Error, "The %s webhook failed: %v\n", wh, err)
+			}
+		}
+	}()
+
+	ctx := context.Background()
+	uuid := e.Config.UUID.String()
+	dbs := e.Sys.GraphDatabases()
+	// Assets are only reported once per enumeration
+	seen := stringset.New()
+	defer seen.Close()
+
+	notify := func(atype, ntype, asset string, out *requests.Output) {
+		if asset == "" || seen.Has(atype+asset) {
+			return
+		}
+		seen.Insert(atype + asset)
+
+		found[atype]++
+		for _, db := range dbs {
+			if _, err := db.ReadNode(ctx, asset, ntype); err == nil {
+				return
+			}
+		}
+		discovered[atype]++
+
+		a := &integrations.WebhookAsset{
+			Type:      atype,
+			Asset:     asset,
+			Domain:    out.Domain,
+			Sources:   out.Sources,
+			EventID:   uuid,
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		}
+		if atype != "fqdn" {
+			a.Name = out.Name
+		}
+		for _, wh := range hooks {
+			wh.Notify(a)
+		}
+	}
+
+	for out := range output {
+		notify("fqdn", netmap.TypeFQDN, out.Name, out)
+
+		for _, addr := range out.Addresses {
+			if addr.Address != nil {
+				notify("address", netmap.TypeAddr, addr.Address.String(), out)
+			}
+			notify("netblock", netmap.TypeNetblock, addr.CIDRStr, out)
+		}
+	}
+}
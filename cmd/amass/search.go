@@ -0,0 +1,181 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/net/dns"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+)
+
+// The trigram index allows PostgreSQL to answer the regular expression searches over the node values.
+var pgSearchIndexStmts = []string{
+	"CREATE EXTENSION IF NOT EXISTS pg_trgm;",
+	"CREATE INDEX IF NOT EXISTS nodes_value_string_trgm_idx ON nodes USING gin (value_string gin_trgm_ops);",
+}
+
+type searchResult struct {
+	Name string
+	// The addresses that the name was returned for by reverse DNS queries
+	PTR []string
+}
+
+// Returns the case-insensitive regular expression for the search pattern.
+// Glob patterns support the '*' and '?' wildcards and must match the entire name.
+func searchRegexp(pattern string, glob bool) (*regexp.Regexp, error) {
+	if !glob {
+		return regexp.Compile("(?i)" + pattern)
+	}
+
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// Returns the stored names matching the regular expression. When events are provided,
+// only the names discovered during those events are considered.
+func searchNames(ctx context.Context, db *netmap.Graph, cfg *config.Config, re *regexp.Regexp, uuids []string) []*searchResult {
+	var names []string
+	// The SQL database servers are able to perform the search using their indexes
+	if candidates, err := sqlSearch(cfg, re); err == nil {
+		for _, name := range candidates {
+			if _, err := db.ReadNode(ctx, name, "fqdn"); err == nil {
+				names = append(names, name)
+			}
+		}
+	} else if nodes, err := db.AllNodesOfType(ctx, "fqdn"); err == nil {
+		for _, node := range nodes {
+			names = append(names, db.NodeToID(node))
+		}
+	}
+
+	var filter *stringset.Set
+	if len(uuids) > 0 {
+		filter = stringset.New()
+		defer filter.Close()
+
+		if nodes, err := db.AllNodesOfType(ctx, "fqdn", uuids...); err == nil {
+			for _, node := range nodes {
+				filter.Insert(db.NodeToID(node))
+			}
+		}
+	}
+
+	var results []*searchResult
+	for _, name := range names {
+		if !re.MatchString(name) || (filter != nil && !filter.Has(name)) {
+			continue
+		}
+
+		res := &searchResult{Name: name}
+		if edges, err := db.ReadInEdges(ctx, netmap.Node(name), "ptr_record"); err == nil {
+			for _, edge := range edges {
+				res.PTR = append(res.PTR, ptrAddress(db.NodeToID(edge.From)))
+			}
+			sort.Strings(res.PTR)
+		}
+		results = append(results, res)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// Returns the values matching the regular expression from the nodes table of the
+// primary PostgreSQL graph database, after making sure the search index exists.
+func sqlSearch(cfg *config.Config, re *regexp.Regexp) ([]string, error) {
+	var u string
+	for _, gdb := range cfg.GraphDBs {
+		if gdb.Primary && gdb.System == "postgres" {
+			var err error
+
+			u, err = gdb.ConnectionURL()
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if u == "" {
+		return nil, fmt.Errorf("no PostgreSQL graph database was configured")
+	}
+
+	conn, err := sql.Open("postgres", u)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	for _, stmt := range pgSearchIndexStmts {
+		if _, err := conn.Exec(stmt); err != nil {
+			// The search still works without the index, only slower
+			fgY.Fprintf(color.Error, "Failed to create the search index: %v\n", err)
+			break
+		}
+	}
+
+	// PostgreSQL does not support the (?i) flag, so the case-insensitive operator is used instead
+	pattern := strings.TrimPrefix(re.String(), "(?i)")
+	rows, err := conn.Query("SELECT value_string FROM nodes WHERE iri AND value_string ~* $1;", pattern)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+
+		if err := rows.Scan(&v); err == nil {
+			values = append(values, v)
+		}
+	}
+	return values, rows.Err()
+}
+
+// Returns the address in the reverse DNS name, or the name when it cannot be converted.
+func ptrAddress(name string) string {
+	if strings.HasSuffix(name, ".in-addr.arpa") {
+		return dns.ReverseIP(strings.TrimSuffix(name, ".in-addr.arpa"))
+	}
+	return name
+}
+
+func showSearchResults(results []*searchResult) {
+	if len(results) == 0 {
+		r.Println("No names matched the search pattern")
+		return
+	}
+
+	for _, res := range results {
+		if len(res.PTR) > 0 {
+			fmt.Fprintf(color.Output, "%s %s\n", green(res.Name), yellow("(PTR for "+strings.Join(res.PTR, ",")+")"))
+			continue
+		}
+		fmt.Fprintln(color.Output, green(res.Name))
+	}
+}
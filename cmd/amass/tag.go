@@ -0,0 +1,384 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+	"github.com/cayleygraph/quad"
+	"github.com/fatih/color"
+)
+
+const (
+	tagUsageMsg = "tag [options] -name name|-addr addr -tag tag"
+
+	// The graph properties holding the user-defined tags and notes
+	userTagPredicate  = "user_tag"
+	userNotePredicate = "user_note"
+)
+
+type tagArgs struct {
+	Addrs   *stringset.Set
+	Domains *stringset.Set
+	Names   *stringset.Set
+	Tags    *stringset.Set
+	Note    string
+	Project string
+	Options struct {
+		List    bool
+		NoColor bool
+		Remove  bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Domains    string
+		Names      string
+	}
+}
+
+func runTagCommand(clArgs []string) {
+	var args tagArgs
+	var help1, help2 bool
+	tagCommand := flag.NewFlagSet("tag", flag.ContinueOnError)
+
+	args.Addrs = stringset.New()
+	defer args.Addrs.Close()
+	args.Domains = stringset.New()
+	defer args.Domains.Close()
+	args.Names = stringset.New()
+	defer args.Names.Close()
+	args.Tags = stringset.New()
+	defer args.Tags.Close()
+
+	tagBuf := new(bytes.Buffer)
+	tagCommand.SetOutput(tagBuf)
+
+	tagCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	tagCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	tagCommand.Var(args.Addrs, "addr", "IP addresses separated by commas (can be used multiple times)")
+	tagCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	tagCommand.Var(args.Names, "name", "DNS names separated by commas (can be used multiple times)")
+	tagCommand.Var(args.Tags, "tag", "Tags separated by commas (can be used multiple times)")
+	tagCommand.StringVar(&args.Note, "note", "", "Note attached to the names and addresses")
+	tagCommand.BoolVar(&args.Options.List, "list", false, "Print the tags and notes of the names and addresses")
+	tagCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	tagCommand.BoolVar(&args.Options.Remove, "remove", false, "Remove the tags, or all tags and notes when none are provided")
+	tagCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	tagCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
+	tagCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	tagCommand.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
+	tagCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	tagCommand.StringVar(&args.Filepaths.Names, "nf", "", "Path to a file providing DNS names")
+
+	if len(clArgs) < 1 {
+		commandUsage(tagUsageMsg, tagCommand, tagBuf)
+		return
+	}
+	if err := tagCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(tagUsageMsg, tagCommand, tagBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = ioutil.Discard
+		color.Error = ioutil.Discard
+	}
+	if args.Filepaths.Domains != "" {
+		list, err := config.GetListFromFile(args.Filepaths.Domains)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the domain names file: %v\n", err)
+			os.Exit(1)
+		}
+		args.Domains.InsertMany(list...)
+	}
+	if args.Filepaths.Names != "" {
+		list, err := config.GetListFromFile(args.Filepaths.Names)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the DNS names file: %v\n", err)
+			os.Exit(1)
+		}
+		args.Names.InsertMany(list...)
+	}
+	for _, addr := range args.Addrs.Slice() {
+		if net.ParseIP(addr) == nil {
+			r.Fprintf(color.Error, "%s is not a valid IP address\n", addr)
+			os.Exit(1)
+		}
+	}
+	if !args.Options.List && args.Names.Len() == 0 && args.Addrs.Len() == 0 {
+		r.Fprintln(color.Error, "No DNS names or IP addresses were provided")
+		os.Exit(1)
+	}
+	if !args.Options.List && !args.Options.Remove && args.Tags.Len() == 0 && args.Note == "" {
+		r.Fprintln(color.Error, "No tags or notes were provided")
+		os.Exit(1)
+	}
+
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+
+	args.Filepaths.Directory = projectDirectory(args.Filepaths.Directory, args.Project, cfg)
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		r.Fprintln(color.Error, "Failed to connect with the database")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if args.Options.List {
+		listTags(ctx, &args, db)
+		return
+	}
+
+	targets := tagTargets(&args)
+	for _, t := range targets {
+		var err error
+
+		if args.Options.Remove {
+			err = removeTags(ctx, db, t.id, t.ntype, args.Tags.Slice())
+		} else {
+			err = addTags(ctx, db, t.id, t.ntype, args.Tags.Slice(), args.Note)
+		}
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to update %s: %v\n", t.id, err)
+			os.Exit(1)
+		}
+	}
+	g.Fprintf(color.Output, "Updated %d names and addresses\n", len(targets))
+}
+
+type tagTarget struct {
+	id    string
+	ntype string
+}
+
+func tagTargets(args *tagArgs) []tagTarget {
+	var targets []tagTarget
+
+	for _, name := range args.Names.Slice() {
+		targets = append(targets, tagTarget{id: strings.ToLower(name), ntype: netmap.TypeFQDN})
+	}
+	for _, addr := range args.Addrs.Slice() {
+		targets = append(targets, tagTarget{id: addr, ntype: netmap.TypeAddr})
+	}
+	return targets
+}
+
+func addTags(ctx context.Context, db *netmap.Graph, id, ntype string, tags []string, note string) error {
+	node, err := db.ReadNode(ctx, id, ntype)
+	if err != nil {
+		return fmt.Errorf("the node was not found in the graph database")
+	}
+
+	existing := stringset.New(readNodeTags(ctx, db, id)...)
+	defer existing.Close()
+
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag == "" || existing.Has(tag) {
+			continue
+		}
+		if err := db.UpsertProperty(ctx, node, userTagPredicate, tag); err != nil {
+			return err
+		}
+	}
+	if note != "" {
+		return db.UpsertProperty(ctx, node, userNotePredicate, note)
+	}
+	return nil
+}
+
+func removeTags(ctx context.Context, db *netmap.Graph, id, ntype string, tags []string) error {
+	node, err := db.ReadNode(ctx, id, ntype)
+	if err != nil {
+		return fmt.Errorf("the node was not found in the graph database")
+	}
+
+	if len(tags) > 0 {
+		for _, tag := range tags {
+			_ = db.DeleteProperty(ctx, node, userTagPredicate, quad.String(strings.ToLower(strings.TrimSpace(tag))))
+		}
+		return nil
+	}
+
+	props, err := db.ReadProperties(ctx, node, userTagPredicate, userNotePredicate)
+	if err != nil {
+		return nil
+	}
+	for _, p := range props {
+		if err := db.DeleteProperty(ctx, node, p.Predicate, p.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func listTags(ctx context.Context, args *tagArgs, db *netmap.Graph) {
+	targets := tagTargets(args)
+	// Without names or addresses, all the tagged names in scope are listed
+	if len(targets) == 0 {
+		domains := args.Domains.Slice()
+
+		if nodes, err := db.AllNodesOfType(ctx, netmap.TypeFQDN); err == nil {
+			for _, node := range nodes {
+				if name := db.NodeToID(node); len(domains) == 0 || domainNameInScope(name, domains) {
+					targets = append(targets, tagTarget{id: name, ntype: netmap.TypeFQDN})
+				}
+			}
+		}
+		if nodes, err := db.AllNodesOfType(ctx, netmap.TypeAddr); err == nil && len(domains) == 0 {
+			for _, node := range nodes {
+				targets = append(targets, tagTarget{id: db.NodeToID(node), ntype: netmap.TypeAddr})
+			}
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].id < targets[j].id
+	})
+
+	var total int
+	for _, t := range targets {
+		tags := readNodeTags(ctx, db, t.id)
+		notes := readNodeNotes(ctx, db, t.id)
+		if len(tags) == 0 && len(notes) == 0 {
+			continue
+		}
+
+		total++
+		fmt.Fprintf(color.Output, "%s %s\n", green(t.id), yellow(strings.Join(tags, ",")))
+		for _, note := range notes {
+			fmt.Fprintf(color.Output, "\t%s\n", blue(note))
+		}
+	}
+	if total == 0 {
+		r.Println("No tagged names or addresses were found")
+	}
+}
+
+func readNodeTags(ctx context.Context, db *netmap.Graph, id string) []string {
+	tags := readNodeProperties(ctx, db, id, userTagPredicate)
+
+	sort.Strings(tags)
+	return tags
+}
+
+func readNodeNotes(ctx context.Context, db *netmap.Graph, id string) []string {
+	return readNodeProperties(ctx, db, id, userNotePredicate)
+}
+
+func readNodeProperties(ctx context.Context, db *netmap.Graph, id, predicate string) []string {
+	var values []string
+
+	if props, err := db.ReadProperties(ctx, netmap.Node(id), predicate); err == nil {
+		for _, p := range props {
+			if v, ok := p.Value.Native().(string); ok && v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// tagFilter selects the names and addresses using the tags and confidence attached to them.
+type tagFilter struct {
+	Include       *stringset.Set
+	Exclude       *stringset.Set
+	MinConfidence int
+}
+
+func newTagFilter() *tagFilter {
+	return &tagFilter{
+		Include: stringset.New(),
+		Exclude: stringset.New(),
+	}
+}
+
+// Close releases the resources allocated by the filter.
+func (tf *tagFilter) Close() {
+	tf.Include.Close()
+	tf.Exclude.Close()
+}
+
+// Allowed returns true when none of the tags are excluded, and at least
+// one of them is included when the filter provides included tags.
+func (tf *tagFilter) Allowed(tags []string) bool {
+	if tf == nil {
+		return true
+	}
+
+	included := tf.Include.Len() == 0
+	for _, tag := range tags {
+		if tf.Exclude.Has(tag) {
+			return false
+		}
+		if tf.Include.Has(tag) {
+			included = true
+		}
+	}
+	return included
+}
+
+// Confident returns true when the confidence meets the minimum required by the filter.
+// Nodes without a confidence value, such as those in older databases, are not rejected.
+func (tf *tagFilter) Confident(conf int) bool {
+	return tf == nil || conf == 0 || conf >= tf.MinConfidence
+}
+
+// Attaches the tags and notes to the output, and removes the names and addresses rejected by the filter.
+func tagOutput(ctx context.Context, db *netmap.Graph, output []*requests.Output, tf *tagFilter) []*requests.Output {
+	var results []*requests.Output
+
+	for _, out := range output {
+		out.UserTags = readNodeTags(ctx, db, out.Name)
+		out.Notes = readNodeNotes(ctx, db, out.Name)
+		if !tf.Allowed(out.UserTags) || !tf.Confident(out.Confidence) {
+			continue
+		}
+
+		var addrs []requests.AddressInfo
+		for _, a := range out.Addresses {
+			if a.Address == nil || (tf.Allowed(readNodeTags(ctx, db, a.Address.String())) && tf.Confident(a.Confidence)) {
+				addrs = append(addrs, a)
+			}
+		}
+		// Names are removed when all their addresses have been filtered
+		if len(out.Addresses) > 0 && len(addrs) == 0 {
+			continue
+		}
+
+		out.Addresses = addrs
+		results = append(results, out)
+	}
+	return results
+}
@@ -0,0 +1,47 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/resources"
+	"github.com/caffix/netmap"
+	"github.com/fatih/color"
+)
+
+func showTakeovers(uuids, domains []string, cfg *config.Config, db *netmap.Graph) {
+	fps, err := resources.LoadTakeoverFingerprints(cfg.TakeoverFingerprints)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to load the takeover fingerprints: %v\n", err)
+		return
+	}
+
+	candidates := enum.TakeoverCandidates(context.Background(), db, uuids, domains, fps)
+	if len(candidates) == 0 {
+		g.Println("No takeover candidates were discovered")
+		return
+	}
+
+	for _, c := range candidates {
+		confidence := yellow(strconv.Itoa(c.Confidence) + "%")
+		if c.Confidence >= enum.TakeoverConfirmed {
+			confidence = red(strconv.Itoa(c.Confidence) + "%")
+		}
+
+		blueLine()
+		fmt.Fprintf(color.Output, "%s\t%s\n", blue("Name:"), green(c.Name))
+		fmt.Fprintf(color.Output, "%s\t%s\n", blue("Service:"), yellow(c.Service+" ("+c.Record+")"))
+		fmt.Fprintf(color.Output, "%s\t%s\n", blue("Confidence:"), confidence)
+		fmt.Fprintf(color.Output, "%s\t%s\n", blue("Evidence:"), yellow(c.Evidence))
+		if c.Dangling {
+			fmt.Fprintf(color.Output, "%s\t%s\n", blue("Status:"), red("The CNAME target does not resolve"))
+		}
+	}
+}
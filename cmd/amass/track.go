@@ -12,9 +12,13 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/integrations"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/caffix/netmap"
 	"github.com/caffix/stringset"
@@ -24,21 +28,31 @@ import (
 const (
 	timeFormat    = "01/02 15:04:05 2006 MST"
 	trackUsageMsg = "track [options] -d domain"
+	// The number of changes listed by the notifications
+	trackMaxChanges = 25
+	// The exit code used when the names added by the latest enumeration exceed the threshold
+	trackFailExitCode = 2
 )
 
 type trackArgs struct {
-	Domains *stringset.Set
-	Last    int
-	Since   string
-	Options struct {
-		History bool
-		NoColor bool
-		Silent  bool
+	Domains   *stringset.Set
+	Tags      *tagFilter
+	Last      int
+	Since     string
+	Project   string
+	Threshold int
+	Options   struct {
+		FailOnNew bool
+		History   bool
+		NoColor   bool
+		Notify    bool
+		Silent    bool
 	}
 	Filepaths struct {
 		ConfigFile string
 		Directory  string
 		Domains    string
+		JSONOutput string
 	}
 }
 
@@ -49,6 +63,8 @@ func runTrackCommand(clArgs []string) {
 
 	args.Domains = stringset.New()
 	defer args.Domains.Close()
+	args.Tags = newTagFilter()
+	defer args.Tags.Close()
 
 	trackBuf := new(bytes.Buffer)
 	trackCommand.SetOutput(trackBuf)
@@ -56,14 +72,22 @@ func runTrackCommand(clArgs []string) {
 	trackCommand.BoolVar(&help1, "h", false, "Show the program usage message")
 	trackCommand.BoolVar(&help2, "help", false, "Show the program usage message")
 	trackCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	trackCommand.Var(args.Tags.Exclude, "exclude-tags", "Exclude names and addresses with these tags")
+	trackCommand.Var(args.Tags.Include, "include-tags", "Only include names and addresses with these tags")
+	trackCommand.IntVar(&args.Tags.MinConfidence, "min-confidence", 0, "Only include names and addresses with at least this confidence (0-100)")
 	trackCommand.IntVar(&args.Last, "last", 0, "The number of recent enumerations to include in the tracking")
 	trackCommand.StringVar(&args.Since, "since", "", "Exclude all enumerations before (format: "+timeFormat+")")
+	trackCommand.IntVar(&args.Threshold, "threshold", 0, "Number of names the latest enumeration can add before -fail-on-new fails")
+	trackCommand.BoolVar(&args.Options.FailOnNew, "fail-on-new", false, fmt.Sprintf("Exit with code %d when the latest enumeration adds names", trackFailExitCode))
 	trackCommand.BoolVar(&args.Options.History, "history", false, "Show the difference between all enumeration pairs")
 	trackCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	trackCommand.BoolVar(&args.Options.Notify, "notify", false, "Send the changes to the chat webhooks and email recipients provided by the configuration")
 	trackCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
-	trackCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	trackCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
 	trackCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	trackCommand.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
 	trackCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	trackCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON file or '-' receiving the changes of the latest enumeration")
 
 	if len(clArgs) < 1 {
 		commandUsage(trackUsageMsg, trackCommand, trackBuf)
@@ -89,6 +113,10 @@ func runTrackCommand(clArgs []string) {
 		r.Fprintln(color.Error, "The since flag cannot be used with the last or all flags")
 		os.Exit(1)
 	}
+	if args.Threshold < 0 {
+		r.Fprintln(color.Error, "The threshold must be zero or more")
+		os.Exit(1)
+	}
 	if args.Last > 0 && args.Last < 2 {
 		r.Fprintln(color.Error, "Tracking requires more than one enumeration")
 		os.Exit(1)
@@ -132,6 +160,7 @@ func runTrackCommand(clArgs []string) {
 		os.Exit(1)
 	}
 	// Connect with the graph database containing the enumeration data
+	args.Filepaths.Directory = projectDirectory(args.Filepaths.Directory, args.Project, cfg)
 	db := openGraphDatabase(args.Filepaths.Directory, cfg)
 	if db == nil {
 		r.Fprintln(color.Error, "Failed to connect with the database")
@@ -154,7 +183,7 @@ func runTrackCommand(clArgs []string) {
 
 	var earliest, latest []time.Time
 	// Put the events in chronological order
-	uuids, earliest, latest = orderedEvents(context.Background(), uuids, memDB)
+	uuids, earliest, latest = enum.OrderedEvents(context.Background(), uuids, memDB)
 	if len(uuids) == 0 {
 		r.Fprintln(color.Error, "Failed to sort the events")
 		os.Exit(1)
@@ -186,19 +215,51 @@ func runTrackCommand(clArgs []string) {
 	earliest = earliest[begin:]
 	latest = latest[begin:]
 
+	var hooks []*integrations.Webhook
+	var mailer *integrations.Mailer
+	if args.Options.Notify {
+		hooks = integrations.NewWebhooks(cfg)
+		if mailer = integrations.NewMailer(cfg); len(hooks) == 0 && mailer == nil {
+			r.Fprintln(color.Error, "No webhooks or email settings were provided by the configuration")
+			os.Exit(1)
+		}
+	}
+
 	cache := cacheWithData()
-	if len(uuids) == 1 {
-		printOneEvent(uuids, args.Domains.Slice(), earliest[0], latest[0], memDB, cache)
-		return
-	} else if args.Options.History {
-		completeHistoryOutput(uuids, args.Domains.Slice(), earliest, latest, memDB, cache)
+	domains := args.Domains.Slice()
+	// The terminal output is not mixed with a document written to stdout
+	if args.Filepaths.JSONOutput != "-" {
+		if len(uuids) == 1 {
+			printOneEvent(uuids, domains, earliest[0], latest[0], args.Tags, memDB, cache)
+		} else if args.Options.History {
+			completeHistoryOutput(uuids, domains, earliest, latest, args.Tags, memDB, cache)
+		} else {
+			cumulativeOutput(uuids, domains, earliest, latest, args.Tags, memDB, cache)
+		}
+	}
+
+	if len(hooks) == 0 && mailer == nil && args.Filepaths.JSONOutput == "" && !args.Options.FailOnNew {
 		return
 	}
-	cumulativeOutput(uuids, args.Domains.Slice(), earliest, latest, memDB, cache)
+	diff := latestChanges(uuids, domains, earliest, latest, args.Tags, memDB, cache)
+	if err := writeDiff(args.Filepaths.JSONOutput, diff, format.WriteDiffJSON); err != nil {
+		r.Fprintf(color.Error, "Failed to write the JSON output: %v\n", err)
+		os.Exit(1)
+	}
+	if len(hooks) > 0 {
+		notifyTrackChanges(hooks, domains, diff)
+	}
+	if mailer != nil {
+		emailTrackChanges(mailer, domains, diff)
+	}
+	if added := countChanges(diff)[format.DiffAdded]; args.Options.FailOnNew && added > args.Threshold {
+		r.Fprintf(color.Error, "The latest enumeration added %d names, exceeding the threshold of %d\n", added, args.Threshold)
+		os.Exit(trackFailExitCode)
+	}
 }
 
-func printOneEvent(uuid, domains []string, earliest, latest time.Time, db *netmap.Graph, cache *requests.ASNCache) {
-	one := getScopedOutput(uuid, domains, db, cache)
+func printOneEvent(uuid, domains []string, earliest, latest time.Time, tf *tagFilter, db *netmap.Graph, cache *requests.ASNCache) {
+	one := getScopedOutput(uuid, domains, tf, db, cache)
 
 	blueLine()
 	fmt.Fprintf(color.Output, "%s\t%s%s%s\n%s\t%s%s%s\n", blue("Between"),
@@ -211,9 +272,9 @@ func printOneEvent(uuid, domains []string, earliest, latest time.Time, db *netma
 	}
 }
 
-func cumulativeOutput(uuids, domains []string, ea, la []time.Time, db *netmap.Graph, cache *requests.ASNCache) {
+func cumulativeOutput(uuids, domains []string, ea, la []time.Time, tf *tagFilter, db *netmap.Graph, cache *requests.ASNCache) {
 	idx := len(uuids) - 1
-	cum := getScopedOutput(uuids[:idx], domains, db, cache)
+	cum := getScopedOutput(uuids[:idx], domains, tf, db, cache)
 
 	blueLine()
 	fmt.Fprintf(color.Output, "%s\t%s%s%s\n%s\t%s%s%s\n", blue("Between"),
@@ -222,7 +283,7 @@ func cumulativeOutput(uuids, domains []string, ea, la []time.Time, db *netmap.Gr
 	blueLine()
 
 	var updates bool
-	out := getScopedOutput([]string{uuids[idx]}, domains, db, cache)
+	out := getScopedOutput([]string{uuids[idx]}, domains, tf, db, cache)
 	for _, d := range diffEnumOutput(cum, out) {
 		updates = true
 		fmt.Fprintln(color.Output, d)
@@ -232,10 +293,76 @@ func cumulativeOutput(uuids, domains []string, ea, la []time.Time, db *netmap.Gr
 	}
 }
 
-func getScopedOutput(uuids, domains []string, db *netmap.Graph, cache *requests.ASNCache) []*requests.Output {
+// Returns the changes found by the latest enumeration, compared with the preceding enumerations.
+func latestChanges(uuids, domains []string, ea, la []time.Time, tf *tagFilter, db *netmap.Graph, cache *requests.ASNCache) *format.Diff {
+	idx := len(uuids) - 1
+
+	var older []*requests.Output
+	if idx > 0 {
+		older = getScopedOutput(uuids[:idx], domains, tf, db, cache)
+	}
+	diff := format.DiffOutput(older, getScopedOutput([]string{uuids[idx]}, domains, tf, db, cache))
+
+	if idx > 0 {
+		diff.From = ea[0].Format(timeFormat) + " -> " + la[idx-1].Format(timeFormat)
+	}
+	diff.To = ea[idx].Format(timeFormat) + " -> " + la[idx].Format(timeFormat)
+	return diff
+}
+
+func countChanges(diff *format.Diff) map[string]int {
+	counts := make(map[string]int)
+
+	for _, c := range diff.Changes {
+		counts[c.Change]++
+	}
+	return counts
+}
+
+// Posts the changes found by the latest enumeration to the chat webhooks.
+func notifyTrackChanges(hooks []*integrations.Webhook, domains []string, diff *format.Diff) {
+	counts := countChanges(diff)
+
+	lines := []string{fmt.Sprintf("OWASP Amass tracking of %s for the enumeration %s: %d names were added, %d removed and %d changed",
+		strings.Join(domains, ", "), diff.To, counts[format.DiffAdded], counts[format.DiffRemoved], counts[format.DiffChanged])}
+	for i, c := range diff.Changes {
+		// The chat services limit the length of the messages
+		if i == trackMaxChanges {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(diff.Changes)-trackMaxChanges))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("- %s %s %s", c.Change, c.Name, strings.Join(c.Addresses, ",")))
+	}
+
+	msg := strings.Join(lines, "\n")
+	for _, wh := range hooks {
+		if err := wh.Message(msg); err != nil {
+			r.Fprintf(color.Error, "The %s webhook failed: %v\n", wh, err)
+		}
+		_ = wh.Close()
+	}
+}
+
+// Sends the message summarizing the changes found by the latest enumeration to the email recipients.
+func emailTrackChanges(mailer *integrations.Mailer, domains []string, diff *format.Diff) {
+	counts := countChanges(diff)
+
+	if err := mailer.Send(&integrations.EmailChanges{
+		Domains:     domains,
+		Enumeration: diff.To,
+		Added:       counts[format.DiffAdded],
+		Removed:     counts[format.DiffRemoved],
+		Changed:     counts[format.DiffChanged],
+		Changes:     diff.Changes,
+	}); err != nil {
+		r.Fprintf(color.Error, "Failed to send the email: %v\n", err)
+	}
+}
+
+func getScopedOutput(uuids, domains []string, tf *tagFilter, db *netmap.Graph, cache *requests.ASNCache) []*requests.Output {
 	var output []*requests.Output
 
-	for _, out := range getEventOutput(context.TODO(), uuids, false, db, cache) {
+	for _, out := range getEventOutput(context.TODO(), uuids, false, tf, db, cache) {
 		if len(domains) > 0 && !domainNameInScope(out.Name, domains) {
 			continue
 		}
@@ -244,7 +371,7 @@ func getScopedOutput(uuids, domains []string, db *netmap.Graph, cache *requests.
 	return output
 }
 
-func completeHistoryOutput(uuids, domains []string, ea, la []time.Time, db *netmap.Graph, cache *requests.ASNCache) {
+func completeHistoryOutput(uuids, domains []string, ea, la []time.Time, tf *tagFilter, db *netmap.Graph, cache *requests.ASNCache) {
 	var prev string
 
 	for i, uuid := range uuids {
@@ -263,8 +390,8 @@ func completeHistoryOutput(uuids, domains []string, ea, la []time.Time, db *netm
 		blueLine()
 
 		var updates bool
-		out1 := getScopedOutput([]string{prev}, domains, db, cache)
-		out2 := getScopedOutput([]string{uuid}, domains, db, cache)
+		out1 := getScopedOutput([]string{prev}, domains, tf, db, cache)
+		out2 := getScopedOutput([]string{uuid}, domains, tf, db, cache)
 		for _, d := range diffEnumOutput(out1, out2) {
 			updates = true
 			fmt.Fprintln(color.Output, d)
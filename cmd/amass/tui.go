@@ -0,0 +1,373 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"golang.org/x/term"
+)
+
+const (
+	tuiRefreshInterval = time.Second
+	// The window used to compute the rate of discovered names
+	tuiRateWindow = time.Minute
+	// The number of findings and messages kept for the screen
+	tuiRecentFindings = 8
+	tuiRecentMessages = 50
+)
+
+// The escape sequences selecting the alternate screen and hiding the cursor while the UI is shown.
+const (
+	tuiEnterScreen = "\x1b[?1049h\x1b[?25l"
+	tuiLeaveScreen = "\x1b[?25h\x1b[?1049l"
+	tuiClearScreen = "\x1b[H\x1b[2J"
+)
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// messageLog keeps the messages written to the terminal while the UI is drawn over it,
+// and writes the messages to the terminal again once the UI has been closed.
+type messageLog struct {
+	sync.Mutex
+	out      io.Writer
+	captured bool
+	lines    []string
+}
+
+func newMessageLog(out io.Writer) *messageLog {
+	return &messageLog{out: out, captured: true}
+}
+
+// Write implements the io.Writer interface.
+func (m *messageLog) Write(p []byte) (int, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if !m.captured {
+		return m.out.Write(p)
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line = strings.TrimSpace(ansiEscape.ReplaceAllString(line, "")); line != "" {
+			m.lines = append(m.lines, line)
+		}
+	}
+	if n := len(m.lines); n > tuiRecentMessages {
+		m.lines = m.lines[n-tuiRecentMessages:]
+	}
+	return len(p), nil
+}
+
+func (m *messageLog) last() string {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(m.lines) == 0 {
+		return ""
+	}
+	return m.lines[len(m.lines)-1]
+}
+
+// Stops capturing the messages and writes the messages kept to the terminal.
+func (m *messageLog) release() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.captured = false
+	for _, line := range m.lines {
+		fmt.Fprintln(m.out, line)
+	}
+	m.lines = nil
+}
+
+// terminalUI draws the progress of a running enumeration over the whole terminal,
+// and handles the keys pausing the enumeration and disabling its data sources.
+type terminalUI struct {
+	e     *enum.Enumeration
+	args  *enumArgs
+	msgs  *messageLog
+	fd    int
+	state *term.State
+	keys  chan string
+	quit  chan struct{}
+	once  sync.Once
+	start time.Time
+	// The findings and summary data collected from the output
+	total  int
+	tags   map[string]int
+	asns   map[int]*format.ASNSummaryData
+	found  []time.Time
+	recent []string
+	// The data source rows of the last screen and the row selected by the user
+	sources  []string
+	selected int
+	status   string
+}
+
+func newTerminalUI(e *enum.Enumeration, args *enumArgs, msgs *messageLog) (*terminalUI, error) {
+	fd := int(os.Stdin.Fd())
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the terminal: %v", err)
+	}
+	fmt.Fprint(os.Stdout, tuiEnterScreen)
+
+	return &terminalUI{
+		e:     e,
+		args:  args,
+		msgs:  msgs,
+		fd:    fd,
+		state: state,
+		keys:  make(chan string, 10),
+		quit:  make(chan struct{}),
+		start: time.Now(),
+		tags:  make(map[string]int),
+		asns:  make(map[int]*format.ASNSummaryData),
+	}, nil
+}
+
+// Quit returns a channel that is closed when the user asks to stop the enumeration.
+func (ui *terminalUI) Quit() <-chan struct{} {
+	return ui.quit
+}
+
+func (ui *terminalUI) run(output chan *requests.Output, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	go ui.readKeys()
+	t := time.NewTicker(tuiRefreshInterval)
+	defer t.Stop()
+
+	ui.draw()
+loop:
+	for {
+		select {
+		case out, ok := <-output:
+			if !ok {
+				break loop
+			}
+			ui.addOutput(out)
+		case key := <-ui.keys:
+			ui.handleKey(key)
+			ui.draw()
+		case <-t.C:
+			ui.draw()
+		}
+	}
+
+	ui.close()
+	if ui.total == 0 {
+		r.Println("No names were discovered")
+	} else if !ui.args.Options.Passive {
+		format.PrintEnumerationSummary(ui.total, ui.tags, ui.asns, ui.args.Options.DemoMode)
+	}
+}
+
+// Restores the terminal and writes the messages kept while the UI was shown.
+func (ui *terminalUI) close() {
+	ui.once.Do(func() {
+		fmt.Fprint(os.Stdout, tuiLeaveScreen)
+		_ = term.Restore(ui.fd, ui.state)
+		ui.msgs.release()
+	})
+}
+
+func (ui *terminalUI) addOutput(out *requests.Output) {
+	out.Addresses = format.DesiredAddrTypes(out.Addresses, ui.args.Options.IPv4, ui.args.Options.IPv6)
+	if !ui.e.Config.Passive && len(out.Addresses) <= 0 {
+		return
+	}
+
+	ui.total++
+	ui.found = append(ui.found, time.Now())
+	if !ui.args.Options.Passive {
+		format.UpdateSummaryData(out, ui.tags, ui.asns)
+	}
+
+	source, name, ips := format.OutputLineParts(out, ui.args.Options.Sources,
+		ui.args.Options.IPs || ui.args.Options.IPv4 || ui.args.Options.IPv6, ui.args.Options.DemoMode)
+	ui.recent = append(ui.recent, strings.TrimSpace(source+name+" "+ips))
+	if n := len(ui.recent); n > tuiRecentFindings {
+		ui.recent = ui.recent[n-tuiRecentFindings:]
+	}
+}
+
+// Reads the keys pressed by the user, including the escape sequences of the arrow keys.
+func (ui *terminalUI) readKeys() {
+	buf := make([]byte, 16)
+
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+
+		switch in := string(buf[:n]); in {
+		case "\x1b[A", "\x1b[B":
+			ui.keys <- in
+		default:
+			for _, c := range in {
+				ui.keys <- string(c)
+			}
+		}
+	}
+}
+
+func (ui *terminalUI) handleKey(key string) {
+	switch key {
+	case "q", "\x03":
+		select {
+		case <-ui.quit:
+		default:
+			close(ui.quit)
+			ui.status = "Stopping the enumeration and saving the findings"
+		}
+	case "p":
+		if ui.e.Paused() {
+			ui.e.Unpause()
+			ui.status = "The enumeration has been resumed"
+		} else {
+			ui.e.Pause()
+			ui.status = "The enumeration has been paused"
+		}
+	case "\x1b[A", "k":
+		if ui.selected > 0 {
+			ui.selected--
+		}
+	case "\x1b[B", "j":
+		if ui.selected < len(ui.sources)-1 {
+			ui.selected++
+		}
+	case "d":
+		if ui.selected >= len(ui.sources) {
+			return
+		}
+
+		name := ui.sources[ui.selected]
+		if ui.e.SourceDisabled(name) {
+			ui.e.EnableSource(name)
+			ui.status = fmt.Sprintf("The %s data source has been enabled", name)
+		} else if err := ui.e.DisableSource(name); err != nil {
+			ui.status = fmt.Sprintf("The %s source cannot be disabled: %v", name, err)
+		} else {
+			ui.status = fmt.Sprintf("The %s data source has been disabled", name)
+		}
+	}
+}
+
+func (ui *terminalUI) draw() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	var b strings.Builder
+	b.WriteString(tuiClearScreen)
+	line := func(colorize func(a ...interface{}) string, text string) {
+		if runes := []rune(text); len(runes) > width {
+			text = string(runes[:width])
+		}
+		b.WriteString(colorize(text) + "\r\n")
+	}
+	plain := fmt.Sprint
+
+	p := ui.e.Progress()
+	state := "RUNNING"
+	if p.Paused {
+		state = "PAUSED"
+	}
+	line(green, fmt.Sprintf("OWASP Amass %s  %s  [%s]  %s", format.Version,
+		strings.Join(ui.e.Config.Domains(), ", "), state, time.Since(ui.start).Round(time.Second)))
+	line(yellow, fmt.Sprintf("Names: %d  Rate: %d/min  Queued: %d", ui.total, ui.rate(), p.QueuedNames))
+	line(plain, "Resolvers: "+poolSummary(ui.e.Sys.Resolvers())+"  Trusted: "+poolSummary(ui.e.Sys.TrustedResolvers()))
+	line(plain, "")
+
+	stats := ui.e.SourceStats()
+	ui.sources = ui.sources[:0]
+	for _, s := range stats {
+		ui.sources = append(ui.sources, s.Source)
+	}
+	if ui.selected >= len(ui.sources) {
+		ui.selected = len(ui.sources) - 1
+	}
+	if ui.selected < 0 {
+		ui.selected = 0
+	}
+
+	recent := ui.recent
+	// The header, table heading, findings heading, status and key lines
+	rows := height - 11 - len(recent)
+	if rows < 1 {
+		rows = 1
+	}
+	offset := 0
+	if ui.selected >= rows {
+		offset = ui.selected - rows + 1
+	}
+
+	line(blue, fmt.Sprintf("  %-22s %9s %9s %9s %9s %7s %8s  %s",
+		"SOURCE", "REQUESTS", "NAMES", "UNIQUE", "RESOLVED", "ERRORS", "BACKLOG", "STATE"))
+	for i := offset; i < len(stats) && i < offset+rows; i++ {
+		s := stats[i]
+		cursor := "  "
+		if i == ui.selected {
+			cursor = "> "
+		}
+		srcState := ""
+		if ui.e.SourceDisabled(s.Source) {
+			srcState = "disabled"
+		}
+		line(plain, fmt.Sprintf("%s%-22s %9d %9d %9d %9d %7d %8d  %s", cursor, s.Source,
+			s.Requests, s.Names, s.UniqueNames, s.Resolved, s.Errors, p.SourceBacklog[s.Source], srcState))
+	}
+	line(plain, "")
+
+	line(blue, "Recent findings")
+	for _, f := range recent {
+		line(plain, "  "+f)
+	}
+	line(plain, "")
+
+	// The last message of the enumeration is shown until the user presses a key
+	status := ui.status
+	if status == "" {
+		status = ui.msgs.last()
+	}
+	line(yellow, status)
+	line(blue, "p: pause/resume  up/down: select source  d: disable/enable source  q: quit")
+
+	fmt.Fprint(os.Stdout, b.String())
+}
+
+// Returns the number of names discovered within the rate window.
+func (ui *terminalUI) rate() int {
+	cutoff := time.Now().Add(-tuiRateWindow)
+
+	var i int
+	for i < len(ui.found) && ui.found[i].Before(cutoff) {
+		i++
+	}
+	ui.found = ui.found[i:]
+	return len(ui.found)
+}
+
+func poolSummary(p *resolvers.Pool) string {
+	if p == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%d active, %d evicted, %d QPS, %d pending",
+		p.Len()-len(p.Evicted()), len(p.Evicted()), p.QPS(), p.Pending())
+}
@@ -7,32 +7,54 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/enum"
 	"github.com/OWASP/Amass/v3/viz"
+	"github.com/caffix/netmap"
 	"github.com/caffix/stringset"
 	"github.com/fatih/color"
 )
 
 const (
-	vizUsageMsg = "viz -d3|-dot||-gexf|-graphistry|-maltego [options]"
+	vizUsageMsg = "viz -d3|-dot||-gexf|-graphml|-graphistry|-maltego|-mtgx|-serve ADDR [options]"
 )
 
 type vizArgs struct {
 	Domains *stringset.Set
+	Tags    *tagFilter
 	Enum    int
+	Project string
+	Serve   string
+	Cluster string
+	Filter  struct {
+		Domains *stringset.Set
+		Types   *stringset.Set
+		Source  string
+		Since   string
+		Until   string
+		ASN     int
+		Node    string
+		Hops    int
+	}
 	Options struct {
 		D3         bool
 		DOT        bool
 		GEXF       bool
+		GraphML    bool
 		Graphistry bool
 		Maltego    bool
+		MTGX       bool
 		NoColor    bool
 		Silent     bool
 	}
@@ -41,6 +63,7 @@ type vizArgs struct {
 		Directory     string
 		Domains       string
 		Input         string
+		JSONOutput    string
 		Output        string
 		AllFilePrefix string
 	}
@@ -53,6 +76,12 @@ func runVizCommand(clArgs []string) {
 
 	args.Domains = stringset.New()
 	defer args.Domains.Close()
+	args.Tags = newTagFilter()
+	defer args.Tags.Close()
+	args.Filter.Domains = stringset.New()
+	defer args.Filter.Domains.Close()
+	args.Filter.Types = stringset.New()
+	defer args.Filter.Types.Close()
 
 	vizBuf := new(bytes.Buffer)
 	vizCommand.SetOutput(vizBuf)
@@ -60,9 +89,22 @@ func runVizCommand(clArgs []string) {
 	vizCommand.BoolVar(&help1, "h", false, "Show the program usage message")
 	vizCommand.BoolVar(&help2, "help", false, "Show the program usage message")
 	vizCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	vizCommand.Var(args.Tags.Exclude, "exclude-tags", "Exclude names and addresses with these tags")
+	vizCommand.Var(args.Tags.Include, "include-tags", "Only include names and addresses with these tags")
+	vizCommand.IntVar(&args.Tags.MinConfidence, "min-confidence", 0, "Only include names and addresses with at least this confidence (0-100)")
+	vizCommand.Var(args.Filter.Domains, "filter-domain", "Only include the names within these domains, separated by commas")
+	vizCommand.Var(args.Filter.Types, "types", "Only include these node types: "+strings.Join(viz.NodeTypes, ","))
+	vizCommand.StringVar(&args.Filter.Source, "source", "", "Only include the names discovered by this data source")
+	vizCommand.StringVar(&args.Filter.Since, "since", "", "Only include the names first seen at or after this date (2006-01-02)")
+	vizCommand.StringVar(&args.Filter.Until, "until", "", "Only include the names first seen at or before this date (2006-01-02)")
+	vizCommand.IntVar(&args.Filter.ASN, "asn", 0, "Only include the names resolving to addresses announced by this ASN")
+	vizCommand.StringVar(&args.Filter.Node, "node", "", "Only include the nodes within the maximum hops from this name, address, netblock or ASN")
+	vizCommand.IntVar(&args.Filter.Hops, "hops", 2, "Maximum number of hops from the node selected by -node")
+	vizCommand.StringVar(&args.Cluster, "cluster", "", "Collapse the names into their infrastructure: asn or netblock")
 	vizCommand.IntVar(&args.Enum, "enum", 0, "Identify an enumeration via an index from the listing")
-	vizCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	vizCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI or YAML configuration file. Additional details below")
 	vizCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	vizCommand.StringVar(&args.Project, "project", "", "Name of the project isolating the database within the output directory")
 	vizCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
 	vizCommand.StringVar(&args.Filepaths.Input, "i", "", "The Amass data operations JSON file")
 	vizCommand.StringVar(&args.Filepaths.Output, "o", "", "Path to the directory for output files being generated")
@@ -70,8 +112,12 @@ func runVizCommand(clArgs []string) {
 	vizCommand.BoolVar(&args.Options.D3, "d3", false, "Generate the D3 v4 force simulation HTML file")
 	vizCommand.BoolVar(&args.Options.DOT, "dot", false, "Generate the DOT output file")
 	vizCommand.BoolVar(&args.Options.GEXF, "gexf", false, "Generate the Gephi Graph Exchange XML Format (GEXF) file")
+	vizCommand.BoolVar(&args.Options.GraphML, "graphml", false, "Generate the GraphML file")
 	vizCommand.BoolVar(&args.Options.Graphistry, "graphistry", false, "Generate the Graphistry JSON file")
 	vizCommand.BoolVar(&args.Options.Maltego, "maltego", false, "Generate the Maltego csv file")
+	vizCommand.BoolVar(&args.Options.MTGX, "mtgx", false, "Generate the Maltego graph (mtgx) file")
+	vizCommand.StringVar(&args.Serve, "serve", "", "Serve the live web view of the graph on the address, such as localhost:8080")
+	vizCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file of the running enumeration")
 	vizCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
 	vizCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
 
@@ -95,11 +141,16 @@ func runVizCommand(clArgs []string) {
 		color.Error = ioutil.Discard
 	}
 	// Make sure at least one graph file format has been identified on the command-line
-	if !args.Options.D3 && !args.Options.DOT &&
-		!args.Options.GEXF && !args.Options.Graphistry && !args.Options.Maltego {
+	if args.Serve == "" && !args.Options.D3 && !args.Options.DOT &&
+		!args.Options.GEXF && !args.Options.GraphML && !args.Options.Graphistry && !args.Options.Maltego && !args.Options.MTGX {
 		r.Fprintln(color.Error, "At least one file format must be selected")
 		os.Exit(1)
 	}
+	filter, err := vizFilter(&args)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
 	if args.Filepaths.Domains != "" {
 		list, err := config.GetListFromFile(args.Filepaths.Domains)
 		if err != nil {
@@ -125,37 +176,22 @@ func runVizCommand(clArgs []string) {
 		os.Exit(1)
 	}
 
-	db := openGraphDatabase(args.Filepaths.Directory, cfg)
-	if db == nil {
-		r.Fprintln(color.Error, "Failed to connect with the database")
-		os.Exit(1)
+	args.Filepaths.Directory = projectDirectory(args.Filepaths.Directory, args.Project, cfg)
+	if args.Serve != "" {
+		serveLiveViz(&args, cfg, filter)
+		return
 	}
-	defer db.Close()
-	// Create the in-memory graph database
-	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), db)
+
+	nodes, edges, err := loadVizGraph(&args, cfg)
 	if err != nil {
-		r.Fprintln(color.Error, err.Error())
+		r.Fprintf(color.Error, "Failed to obtain the graph: %v\n", err)
 		os.Exit(1)
 	}
-	defer memDB.Close()
-	// Get all the UUIDs for events that have information in scope
-	uuids := memDB.EventsInScope(context.Background(), args.Domains.Slice()...)
-	if len(uuids) == 0 {
-		r.Fprintln(color.Error, "Failed to find the domains of interest in the database")
-		os.Exit(1)
-	}
-	// Put the events in chronological order
-	uuids, _, _ = orderedEvents(context.Background(), uuids, memDB)
-	if len(uuids) == 0 {
-		r.Fprintln(color.Error, "Failed to sort the events")
+	nodes, edges, err = selectVizGraph(nodes, edges, &args, filter)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to select the graph: %v\n", err)
 		os.Exit(1)
 	}
-	// Select the enumeration that the user specified
-	if args.Enum > 0 && len(uuids) > args.Enum {
-		uuids = []string{uuids[args.Enum]}
-	}
-	// Obtain the visualization nodes & edges from the graph
-	nodes, edges := viz.VizData(context.Background(), memDB, uuids)
 	// Get the directory to save the files into
 	dir := args.Filepaths.Directory
 
@@ -184,6 +220,10 @@ func runVizCommand(clArgs []string) {
 		path := filepath.Join(dir, prefix+".gexf")
 		err = writeGraphOutputFile("gexf", path, nodes, edges)
 	}
+	if args.Options.GraphML {
+		path := filepath.Join(dir, prefix+".graphml")
+		err = writeGraphOutputFile("graphml", path, nodes, edges)
+	}
 	if args.Options.Graphistry {
 		path := filepath.Join(dir, prefix+"_graphistry.json")
 		err = writeGraphOutputFile("graphistry", path, nodes, edges)
@@ -192,12 +232,149 @@ func runVizCommand(clArgs []string) {
 		path := filepath.Join(dir, prefix+"_maltego.csv")
 		err = writeGraphOutputFile("maltego", path, nodes, edges)
 	}
+	if args.Options.MTGX {
+		path := filepath.Join(dir, prefix+".mtgx")
+		err = writeGraphOutputFile("mtgx", path, nodes, edges)
+	}
 	if err != nil {
 		r.Fprintf(color.Error, "Failed to write the output file: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// Obtains the visualization nodes and edges for the domains of interest from the graph database.
+func loadVizGraph(args *vizArgs, cfg *config.Config) ([]viz.Node, []viz.Edge, error) {
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		return nil, nil, errors.New("failed to connect with the database")
+	}
+	defer db.Close()
+	// Create the in-memory graph database
+	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), db)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer memDB.Close()
+	// Get all the UUIDs for events that have information in scope
+	uuids := memDB.EventsInScope(context.Background(), args.Domains.Slice()...)
+	if len(uuids) == 0 {
+		return nil, nil, errors.New("failed to find the domains of interest in the database")
+	}
+	// Put the events in chronological order
+	uuids, _, _ = enum.OrderedEvents(context.Background(), uuids, memDB)
+	if len(uuids) == 0 {
+		return nil, nil, errors.New("failed to sort the events")
+	}
+	// Select the enumeration that the user specified
+	if args.Enum > 0 && len(uuids) > args.Enum {
+		uuids = []string{uuids[args.Enum]}
+	}
+	// Obtain the visualization nodes & edges from the graph
+	nodes, edges := viz.VizData(context.Background(), memDB, uuids)
+	return nodes, edges, nil
+}
+
+// Builds the filter of the visualization from the command-line arguments.
+func vizFilter(args *vizArgs) (*viz.Filter, error) {
+	f := &viz.Filter{
+		Domains: args.Filter.Domains.Slice(),
+		Source:  args.Filter.Source,
+		ASN:     args.Filter.ASN,
+		Types:   args.Filter.Types.Slice(),
+	}
+
+	types := stringset.New(viz.NodeTypes...)
+	defer types.Close()
+
+	for _, t := range f.Types {
+		if !types.Has(t) {
+			return nil, fmt.Errorf("the node type %s is not one of %s", t, strings.Join(viz.NodeTypes, ", "))
+		}
+	}
+	if c := args.Cluster; c != "" && c != viz.ClusterASN && c != viz.ClusterNetblock {
+		return nil, fmt.Errorf("the cluster type %s must be %s or %s", c, viz.ClusterASN, viz.ClusterNetblock)
+	}
+	if args.Filter.Hops < 0 {
+		return nil, errors.New("the maximum number of hops must not be negative")
+	}
+
+	var err error
+	if args.Filter.Since != "" {
+		if f.Since, err = viz.ParseFilterTime(args.Filter.Since, false); err != nil {
+			return nil, err
+		}
+	}
+	if args.Filter.Until != "" {
+		if f.Until, err = viz.ParseFilterTime(args.Filter.Until, true); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Applies the tag filter, the visualization filter, the clustering and the neighborhood selection to the graph.
+func selectVizGraph(nodes []viz.Node, edges []viz.Edge, args *vizArgs, f *viz.Filter) ([]viz.Node, []viz.Edge, error) {
+	nodes, edges = filterVizNodes(nodes, edges, args.Tags)
+	nodes, edges = viz.FilterGraph(nodes, edges, f)
+
+	if args.Cluster != "" {
+		var err error
+
+		nodes, edges, err = viz.ClusterGraph(nodes, edges, args.Cluster)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	node := args.Filter.Node
+	if node == "" {
+		return nodes, edges, nil
+	}
+	// The autonomous systems are labeled by their numbers
+	if asn := strings.TrimPrefix(strings.ToUpper(node), "AS"); asn != strings.ToUpper(node) {
+		if _, err := strconv.Atoi(asn); err == nil {
+			node = asn
+		}
+	}
+	return viz.Neighborhood(nodes, edges, node, args.Filter.Hops)
+}
+
+// Removes the names and addresses rejected by the tag filter, along with their edges.
+func filterVizNodes(nodes []viz.Node, edges []viz.Edge, tf *tagFilter) ([]viz.Node, []viz.Edge) {
+	if tf.Include.Len() == 0 && tf.Exclude.Len() == 0 && tf.MinConfidence == 0 {
+		return nodes, edges
+	}
+
+	var results []viz.Node
+	idToIdx := make(map[int]int)
+	for _, n := range nodes {
+		if (n.ActualType == netmap.TypeFQDN || n.ActualType == netmap.TypeAddr) && (!tf.Allowed(n.Tags) || !tf.Confident(n.Confidence)) {
+			continue
+		}
+
+		idToIdx[n.ID] = len(results)
+		n.ID = len(results)
+		results = append(results, n)
+	}
+
+	var kept []viz.Edge
+	for _, e := range edges {
+		from, found := idToIdx[e.From]
+		if !found {
+			continue
+		}
+		to, found := idToIdx[e.To]
+		if !found {
+			continue
+		}
+
+		e.From = from
+		e.To = to
+		kept = append(kept, e)
+	}
+	return results, kept
+}
+
 func writeGraphOutputFile(t string, path string, nodes []viz.Node, edges []viz.Edge) error {
 	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
 	if err != nil {
@@ -218,10 +395,14 @@ func writeGraphOutputFile(t string, path string, nodes []viz.Node, edges []viz.E
 		err = viz.WriteDOTData(f, nodes, edges)
 	case "gexf":
 		err = viz.WriteGEXFData(f, nodes, edges)
+	case "graphml":
+		err = viz.WriteGraphMLData(f, nodes, edges)
 	case "graphistry":
 		err = viz.WriteGraphistryData(f, nodes, edges)
 	case "maltego":
 		viz.WriteMaltegoData(f, nodes, edges)
+	case "mtgx":
+		err = viz.WriteMaltegoGraph(f, nodes, edges)
 	}
 	return err
 }
@@ -5,6 +5,10 @@ package config
 
 import (
 	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/caffix/stringset"
 	"github.com/go-ini/ini"
@@ -25,6 +29,12 @@ func (c *Config) loadBruteForceSettings(cfg *ini.File) error {
 	c.MinForRecursive = bruteforce.Key("minimum_for_recursive").MustInt(0)
 	c.MaxDepth = bruteforce.Key("max_depth").MustInt(0)
 
+	timeout := bruteforce.Key("timeout").MustInt(0)
+	if timeout < 0 {
+		return fmt.Errorf("The bruteforce timeout setting cannot be negative: %d", timeout)
+	}
+	c.BruteTimeout = time.Duration(timeout) * time.Minute
+
 	if bruteforce.HasKey("wordlist_file") {
 		for _, wordlist := range bruteforce.Key("wordlist_file").ValueWithShadows() {
 			list, err := GetListFromFile(wordlist)
@@ -36,9 +46,114 @@ func (c *Config) loadBruteForceSettings(cfg *ini.File) error {
 	}
 
 	c.Wordlist = stringset.Deduplicate(c.Wordlist)
+	return c.loadBruteForceLevelSettings(bruteforce)
+}
+
+// BruteLevel contains the brute forcing settings of the subdomains found at one number of labels
+// below the root domain, such as dev.example.com at level one and api.dev.example.com at level two.
+type BruteLevel struct {
+	// The words prepended to the subdomains of the level, instead of the brute forcing wordlist
+	Wordlist []string
+	// The times a subdomain of the level must be seen before it is brute forced, where a negative
+	// number selects the minimum_for_recursive setting of the bruteforce section
+	MinForRecursive int
+}
+
+func (c *Config) loadBruteForceLevelSettings(bruteforce *ini.Section) error {
+	for _, child := range bruteforce.ChildSections() {
+		name := strings.TrimPrefix(child.Name(), bruteforce.Name()+".")
+
+		n, err := strconv.Atoi(strings.TrimPrefix(name, "level"))
+		if !strings.HasPrefix(name, "level") || err != nil || n < 1 {
+			return fmt.Errorf("The bruteforce.%s section must be named after a level of one or more, such as bruteforce.level2", name)
+		}
+
+		level := &BruteLevel{MinForRecursive: child.Key("minimum_for_recursive").MustInt(-1)}
+		if child.HasKey("wordlist_file") {
+			for _, wordlist := range child.Key("wordlist_file").ValueWithShadows() {
+				list, err := GetListFromFile(wordlist)
+				if err != nil {
+					return fmt.Errorf("Unable to load the file in the bruteforce.%s wordlist_file setting: %s: %v", name, wordlist, err)
+				}
+				level.Wordlist = append(level.Wordlist, list...)
+			}
+		}
+		level.Wordlist = stringset.Deduplicate(level.Wordlist)
+
+		if c.BruteLevels == nil {
+			c.BruteLevels = make(map[int]*BruteLevel)
+		}
+		c.BruteLevels[n] = level
+	}
 	return nil
 }
 
+// BruteWordlist returns the words prepended to the subdomains found at the level, which is zero for
+// the root domain names. The brute forcing wordlist is returned when the level has no wordlist.
+func (c *Config) BruteWordlist(level int) []string {
+	if l, found := c.BruteLevels[level]; found && len(l.Wordlist) > 0 {
+		return l.Wordlist
+	}
+	return c.Wordlist
+}
+
+// MinForRecursiveAt returns the times a subdomain found at the level must be seen before it is brute forced.
+func (c *Config) MinForRecursiveAt(level int) int {
+	if l, found := c.BruteLevels[level]; found && l.MinForRecursive >= 0 {
+		return l.MinForRecursive
+	}
+	return c.MinForRecursive
+}
+
+// BruteShard selects one of the shards the brute forcing wordlists are divided into, so the words can be
+// distributed across the workers of a distributed enumeration. Each word is assigned to a shard using its
+// hash, which selects the same words regardless of the order of the wordlists.
+type BruteShard struct {
+	// The shard selected, starting at one
+	Index int
+	// The number of shards the wordlists are divided into
+	Count int
+}
+
+// ParseBruteShard returns the shard provided as I/N, such as 2/4 for the second of four shards.
+func ParseBruteShard(s string) (*BruteShard, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("the brute forcing shard must be provided as I/N: %s", s)
+	}
+
+	index, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("the brute forcing shard must be provided as I/N: %s", s)
+	}
+	count, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("the brute forcing shard must be provided as I/N: %s", s)
+	}
+	if count < 1 || index < 1 || index > count {
+		return nil, fmt.Errorf("the brute forcing shard must be between 1/N and N/N: %s", s)
+	}
+	return &BruteShard{Index: index, Count: count}, nil
+}
+
+func (bs *BruteShard) String() string {
+	return fmt.Sprintf("%d/%d", bs.Index, bs.Count)
+}
+
+// Words returns the words of the list assigned to the shard.
+func (bs *BruteShard) Words(list []string) []string {
+	var words []string
+
+	for _, word := range list {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(word))
+		if int(h.Sum32()%uint32(bs.Count)) == bs.Index-1 {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
 func (c *Config) loadAlterationSettings(cfg *ini.File) error {
 	alterations, err := cfg.GetSection("alterations")
 	if err != nil {
@@ -68,5 +183,90 @@ func (c *Config) loadAlterationSettings(cfg *ini.File) error {
 	}
 
 	c.AltWordlist = stringset.Deduplicate(c.AltWordlist)
+
+	if alterations.HasKey("rules_file") {
+		for _, path := range alterations.Key("rules_file").ValueWithShadows() {
+			list, err := GetListFromFile(path)
+			if err != nil {
+				return fmt.Errorf("Unable to load the file in the alterations rules_file setting: %s: %v", path, err)
+			}
+			if _, err := ParseAlterationRules(list); err != nil {
+				return err
+			}
+			c.AltRules = append(c.AltRules, list...)
+		}
+	}
+
+	c.AltRules = stringset.Deduplicate(c.AltRules)
+
+	c.AltRulesBudget = alterations.Key("rules_budget").MustInt(0)
+	if c.AltRulesBudget < 0 {
+		return fmt.Errorf("The alterations rules_budget setting cannot be negative: %d", c.AltRulesBudget)
+	}
+
+	if alterations.HasKey("guesser_cmd") {
+		for _, command := range alterations.Key("guesser_cmd").ValueWithShadows() {
+			if command = strings.TrimSpace(command); command != "" {
+				c.AltGuessers = append(c.AltGuessers, command)
+			}
+		}
+	}
+
+	c.MaxGuesses = alterations.Key("max_guesses").MustInt(0)
+	if c.MaxGuesses < 0 {
+		return fmt.Errorf("The alterations max_guesses setting cannot be negative: %d", c.MaxGuesses)
+	}
+
+	timeout := alterations.Key("timeout").MustInt(0)
+	if timeout < 0 {
+		return fmt.Errorf("The alterations timeout setting cannot be negative: %d", timeout)
+	}
+	c.AltTimeout = time.Duration(timeout) * time.Minute
+	return c.loadAlterationKeywordSettings(cfg)
+}
+
+func (c *Config) loadAlterationKeywordSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("alterations.keywords")
+	if err != nil {
+		return nil
+	}
+
+	for _, key := range sec.Keys() {
+		var words []string
+
+		for _, value := range key.ValueWithShadows() {
+			for _, word := range strings.Split(value, ",") {
+				if word = strings.ToLower(strings.TrimSpace(word)); word == "" {
+					continue
+				}
+				if !validLabel(word) {
+					return fmt.Errorf("The keyword %s in the %s set of the alterations.keywords section is not valid within a DNS label", word, key.Name())
+				}
+				words = append(words, word)
+			}
+		}
+		if len(words) < 2 {
+			return fmt.Errorf("The %s set in the alterations.keywords section must provide at least two keywords", key.Name())
+		}
+
+		c.AddAltKeywords(key.Name(), words...)
+	}
 	return nil
 }
+
+// AddAltKeywords appends the keywords to the named set, which is combined with the labels of resolved names
+// during alterations. Keywords found in a label are exchanged for the other keywords in the same set.
+func (c *Config) AddAltKeywords(set string, keywords ...string) {
+	c.Lock()
+	defer c.Unlock()
+
+	set = strings.ToLower(strings.TrimSpace(set))
+	if set == "" || len(keywords) == 0 {
+		return
+	}
+
+	if c.AltKeywords == nil {
+		c.AltKeywords = make(map[string][]string)
+	}
+	c.AltKeywords[set] = stringset.Deduplicate(append(c.AltKeywords[set], keywords...))
+}
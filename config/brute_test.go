@@ -4,7 +4,9 @@
 package config
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-ini/ini"
 )
@@ -40,6 +42,30 @@ func TestConfigloadBruteForceSettings(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "success - time budget",
+			args: args{cfg: []byte(`
+			[bruteforce]
+			enabled = true
+			timeout = 30
+			`)},
+			wantErr: false,
+			assertionFunc: func(t *testing.T, c *Config) {
+				if c.BruteTimeout != 30*time.Minute {
+					t.Errorf("Config.loadBruteForceSettings(): timeout = %s, want 30m", c.BruteTimeout)
+				}
+			},
+		},
+		{
+			name: "failure - negative time budget",
+			args: args{cfg: []byte(`
+			[bruteforce]
+			enabled = true
+			timeout = -5
+			`)},
+			wantErr:       true,
+			assertionFunc: func(t *testing.T, c *Config) {},
+		},
 		{
 			name: "failure - missing section",
 			args: args{cfg: []byte(`
@@ -85,6 +111,52 @@ func TestConfigloadBruteForceSettings(t *testing.T) {
 			assertionFunc: func(t *testing.T, c *Config) {
 			},
 		},
+		{
+			name: "success - level settings",
+			args: args{cfg: []byte(`
+			[bruteforce]
+			enabled = true
+			minimum_for_recursive = 1
+			[bruteforce.level1]
+			minimum_for_recursive = 0
+			[bruteforce.level2]
+			wordlist_file = ./test_wordlist.txt
+			minimum_for_recursive = 5
+			`)},
+			wantErr: false,
+			assertionFunc: func(t *testing.T, c *Config) {
+				if c.MinForRecursiveAt(1) != 0 || c.MinForRecursiveAt(2) != 5 || c.MinForRecursiveAt(3) != 1 {
+					t.Errorf("Config.loadBruteForceSettings(): the level thresholds were not loaded")
+				}
+				if len(c.BruteWordlist(2)) == 0 || len(c.BruteWordlist(1)) != len(c.Wordlist) {
+					t.Errorf("Config.loadBruteForceSettings(): the level wordlists were not loaded")
+				}
+			},
+		},
+		{
+			name: "failure - level name",
+			args: args{cfg: []byte(`
+			[bruteforce]
+			enabled = true
+			[bruteforce.deep]
+			minimum_for_recursive = 2
+			`)},
+			wantErr: true,
+			assertionFunc: func(t *testing.T, c *Config) {
+			},
+		},
+		{
+			name: "failure - missing level wordlist",
+			args: args{cfg: []byte(`
+			[bruteforce]
+			enabled = true
+			[bruteforce.level2]
+			wordlist_file = ./nonexistant_file
+			`)},
+			wantErr: true,
+			assertionFunc: func(t *testing.T, c *Config) {
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -151,6 +223,70 @@ func TestConfigloadAlterationSettings(t *testing.T) {
 			assertionFunc: func(t *testing.T, c *Config) {
 			},
 		},
+		{
+			name: "failure - rules file without valid rules",
+			args: args{cfg: []byte(`
+			[alterations]
+			enabled: true
+			rules_file: ./test_wordlist.txt
+			`)},
+			wantErr: true,
+			assertionFunc: func(t *testing.T, c *Config) {
+				if len(c.AltRules) != 0 {
+					t.Errorf("Config.loadAlterationSettings(): invalid rules were loaded")
+				}
+			},
+		},
+		{
+			name: "success - global guess budget",
+			args: args{cfg: []byte(`
+			[alterations]
+			enabled: true
+			max_guesses: 5000
+			`)},
+			wantErr: false,
+			assertionFunc: func(t *testing.T, c *Config) {
+				if c.MaxGuesses != 5000 {
+					t.Errorf("Config.loadAlterationSettings(): max_guesses = %d, want 5000", c.MaxGuesses)
+				}
+			},
+		},
+		{
+			name: "success - time budget",
+			args: args{cfg: []byte(`
+			[alterations]
+			enabled: true
+			timeout: 15
+			`)},
+			wantErr: false,
+			assertionFunc: func(t *testing.T, c *Config) {
+				if c.AltTimeout != 15*time.Minute {
+					t.Errorf("Config.loadAlterationSettings(): timeout = %s, want 15m", c.AltTimeout)
+				}
+			},
+		},
+		{
+			name: "failure - negative guess budget",
+			args: args{cfg: []byte(`
+			[alterations]
+			enabled: true
+			max_guesses: -1
+			`)},
+			wantErr: true,
+			assertionFunc: func(t *testing.T, c *Config) {
+			},
+		},
+		{
+			name: "failure - missing rules file",
+			args: args{cfg: []byte(`
+			[alterations]
+			enabled: true
+			rules_file: ./nonexistant_file
+			`)},
+			wantErr: true,
+			assertionFunc: func(t *testing.T, c *Config) {
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -168,3 +304,64 @@ func TestConfigloadAlterationSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadAlterationKeywordSettings(t *testing.T) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(`
+[alterations]
+enabled = true
+
+[alterations.keywords]
+environment = prod, stage,DEV
+region = us-east
+region = eu-west
+`))
+	if err != nil {
+		t.Fatalf("Failed to load the test settings: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.loadAlterationSettings(cfg); err != nil {
+		t.Fatalf("Failed to load the alteration settings: %v", err)
+	}
+	if len(c.AltKeywords) != 2 || len(c.AltKeywords["environment"]) != 3 || len(c.AltKeywords["region"]) != 2 {
+		t.Errorf("The keyword sets were not loaded correctly: %v", c.AltKeywords)
+	}
+
+	for _, bad := range []string{"env = prod", "env = prod, dev.test"} {
+		cfg, _ := ini.Load([]byte("[alterations]\nenabled = true\n[alterations.keywords]\n" + bad))
+		if err := NewConfig().loadAlterationSettings(cfg); err == nil {
+			t.Errorf("Failed to reject the invalid keyword set: %s", bad)
+		}
+	}
+}
+
+func TestBruteShard(t *testing.T) {
+	words := []string{"www", "api", "dev", "mail", "vpn", "admin", "test", "stage", "ftp", "portal"}
+
+	seen := make(map[string]int)
+	for i := 1; i <= 3; i++ {
+		bs, err := ParseBruteShard(fmt.Sprintf("%d/3", i))
+		if err != nil {
+			t.Fatalf("Failed to parse the shard: %v", err)
+		}
+
+		shard := bs.Words(words)
+		if again := bs.Words(shard); len(again) != len(shard) {
+			t.Errorf("The shard %s selected %d words from its own %d words", bs, len(again), len(shard))
+		}
+		for _, w := range shard {
+			seen[w]++
+		}
+	}
+	for _, w := range words {
+		if seen[w] != 1 {
+			t.Errorf("The word %s was selected by %d shards", w, seen[w])
+		}
+	}
+
+	for _, bad := range []string{"", "3", "0/2", "3/2", "1/0", "a/2"} {
+		if _, err := ParseBruteShard(bad); err == nil {
+			t.Errorf("The shard %q was accepted", bad)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/OWASP/Amass/v3/resources"
 	"github.com/caffix/stringset"
@@ -29,12 +30,17 @@ import (
 )
 
 const (
-	outputDirName  = "amass"
-	defaultCfgFile = "config.ini"
-	cfgEnvironVar  = "AMASS_CONFIG"
-	systemCfgDir   = "/etc"
+	outputDirName      = "amass"
+	defaultCfgFile     = "config.ini"
+	defaultYAMLCfgFile = "config.yaml"
+	cfgEnvironVar      = "AMASS_CONFIG"
+	systemCfgDir       = "/etc"
+	projectsDirName    = "projects"
 )
 
+// The project names are used as directory names within the output directory.
+var projectNameRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
 // Updater allows an object to implement a method that updates a configuration.
 type Updater interface {
 	OverrideConfig(*Config) error
@@ -53,15 +59,51 @@ type Config struct {
 	// The directory that stores the bolt db and other files created
 	Dir string `ini:"output_directory"`
 
+	// The project that isolates the database and other files within the output directory
+	Project string `ini:"project"`
+
 	// Alternative directory for scripts provided by the user
 	ScriptsDirectory string `ini:"scripts_directory"`
 
+	// The named profile of the configuration file applied over its other settings
+	Profile string `ini:"-"`
+
 	// The graph databases used by the system / enumerations
 	GraphDBs []*Database
+	// Store the findings in the local database as well as the remote graph databases?
+	LocalDatabase bool
+	// Events finished more than this number of days ago are pruned, where zero keeps all of them
+	RetentionDays int
+	// Number of the most recent events kept for each domain, where zero keeps all of them
+	RetentionEvents int
+	// The names resolved by enumerations finished within this number of hours are reported
+	// without being resolved again, where zero processes all of them
+	FreshnessHours int
+
+	// The systems that the findings are pushed into
+	Integrations []*Integration
+	// The endpoints notified when new assets enter the graph
+	Webhooks []*Webhook
+	// The messages summarizing the changes found by the track subcommand, which is nil when not configured
+	Email *Email
+	// The object storage receiving the output files, which is nil when not configured
+	Upload *Upload
+	// The backend storing the data source credentials, which is nil when not configured
+	Secrets *Secrets
+	// The recurring enumerations executed by the daemon
+	Schedules []*Schedule
 
 	// The maximum number of concurrent DNS queries
 	MaxDNSQueries int `ini:"maximum_dns_queries"`
 
+	// The number of DNS queries sent before the names generated by brute forcing and alterations
+	// are no longer resolved, where zero does not limit the queries
+	DNSBudget int `ini:"dns_budget"`
+
+	// Determines if the names waiting to be resolved beyond the memory limit are stored
+	// in a file of the output directory, instead of slowing down the data sources and guessers
+	DiskQueue bool `ini:"disk_queue"`
+
 	// Names provided to seed the enumeration
 	ProvidedNames []string
 
@@ -74,6 +116,10 @@ type Config struct {
 	// ASNs specified as in scope
 	ASNs []int
 
+	// The IP addresses and netblocks excluded from the scope, such as those provided by the scope file
+	ExcludedAddresses []net.IP
+	ExcludedCIDRs     []*net.IPNet
+
 	// The ports that will be checked for certificates
 	Ports []int
 
@@ -92,6 +138,15 @@ type Config struct {
 	// Maximum depth for bruteforcing
 	MaxDepth int
 
+	// The time brute forcing continues after generating its first name, where zero does not limit it
+	BruteTimeout time.Duration
+
+	// The brute forcing settings of the subdomains found at each number of labels below the root domain
+	BruteLevels map[int]*BruteLevel
+
+	// The shard of the brute forcing wordlists used by the worker of a distributed enumeration
+	BruteShard *BruteShard
+
 	// Will discovered subdomain name alterations be generated?
 	Alterations    bool
 	FlipWords      bool
@@ -101,6 +156,18 @@ type Config struct {
 	MinForWordFlip int
 	EditDistance   int
 	AltWordlist    []string
+	// "hashcat-style" rules applied to the first label of resolved names
+	AltRules []string
+	// Maximum number of names generated by the rules, where zero is unlimited
+	AltRulesBudget int
+	// Named sets of keywords, such as environments or regions, combined with the labels of resolved names
+	AltKeywords map[string][]string
+	// External commands that receive the resolved names on stdin and print the generated names
+	AltGuessers []string
+	// Maximum number of generated names resolved across all the alterations and guessers, where zero is unlimited
+	MaxGuesses int
+	// The time alterations continue after generating their first name, where zero does not limit them
+	AltTimeout time.Duration
 
 	// Only access the data sources for names and return results?
 	Passive bool
@@ -108,9 +175,44 @@ type Config struct {
 	// Determines if zone transfers will be attempted
 	Active bool
 
+	// Reverse DNS sweeps across the netblocks of in-scope addresses and ASNs
+	ReverseSweeps           bool
+	ReverseSweepConcurrency int
+	ReverseSweepQPS         int
+	// Number of addresses swept around each in-scope address, where zero selects the default
+	ReverseSweepSize int
+
+	// Determines if the DNSSEC chains of the resolved names in scope are validated
+	DNSSEC bool
+	// Number of names validated concurrently, where zero selects the DefaultDNSSECConcurrency
+	DNSSECConcurrency int
+
+	// Determines if the web services of the resolved names are probed
+	HTTPProbes bool
+	// The ports probed for web services, where none selects the Ports
+	HTTPProbePorts       []int
+	HTTPProbeConcurrency int
+
+	// Determines if the TCP connect scans of the in-scope addresses are performed
+	PortScan bool
+	// The ports scanned, where none selects the DefaultPortScanPorts
+	PortScanPorts []int
+	// Number of TCP connections attempted per second, where zero selects the DefaultPortScanRate
+	PortScanRate int
+
+	// Determines if the names aliased or delegated to services prone to subdomain takeovers are checked
+	Takeovers bool
+	// The file updating the fingerprints of the services prone to subdomain takeovers
+	TakeoverFingerprints string
+
 	// A blacklist of subdomain names that will not be investigated
 	Blacklist     []string
 	blacklistLock sync.Mutex
+	// The regular expressions matching the names that will not be investigated
+	BlacklistPatterns []string
+	blacklistRegexps  []*regexp.Regexp
+	// The top-level domains and public suffixes whose names will not be investigated
+	BlacklistTLDs []string
 
 	// A list of data sources that should not be utilized
 	SourceFilter struct {
@@ -124,11 +226,37 @@ type Config struct {
 	// Type of DNS records to query for
 	RecordTypes []string
 
+	// The SRV service names queried for each discovered zone, such as _ldap._tcp
+	SRVServices []string
+
 	// Resolver settings
 	Resolvers        []string
 	ResolversQPS     int
 	TrustedResolvers []string
 	TrustedQPS       int
+	// The number of trusted resolvers that must confirm the answers before a name is reported
+	TrustedConfirmations int
+
+	// Resolvers that answer the queries for specific zones, such as internal zones in split-horizon DNS
+	DomainResolvers map[string][]string
+
+	// Determines if DNS queries are sent directly to the authoritative name servers
+	Authoritative    bool
+	AuthoritativeQPS int
+
+	// Determines if the DNS responses are cached on disk and reused by later enumerations
+	DNSCache bool
+
+	// Determines if the DNS queries are sent over IPv6 when the transport is available
+	PreferIPv6 bool
+
+	// EDNS0 settings applied to the DNS queries
+	EDNS0BufferSize   int
+	EDNS0Cookies      bool
+	EDNS0ClientSubnet string
+
+	// The interval between the checkpoints saved to resume an interrupted enumeration, where zero disables them
+	CheckpointInterval time.Duration
 
 	// Option for verbose logging and output
 	Verbose bool
@@ -150,6 +278,7 @@ func NewConfig() *Config {
 		Log:             log.New(ioutil.Discard, "", 0),
 		Ports:           []int{80, 443},
 		MinForRecursive: 1,
+		LocalDatabase:   true,
 		// The following is enum-only, but intel will just ignore them anyway
 		FlipWords:      true,
 		FlipNumbers:    true,
@@ -161,6 +290,14 @@ func NewConfig() *Config {
 		MinimumTTL:     1440,
 		ResolversQPS:   DefaultQueriesPerPublicResolver,
 		TrustedQPS:     DefaultQueriesPerBaselineResolver,
+		// A single trusted resolver verifies the answers of the untrusted resolvers
+		TrustedConfirmations: 1,
+		// The authoritative name servers are queried conservatively
+		AuthoritativeQPS: DefaultQueriesPerAuthoritativeServer,
+		// The reverse DNS sweeps have their own concurrency and rate limits
+		ReverseSweeps:           true,
+		ReverseSweepConcurrency: DefaultReverseSweepConcurrency,
+		ReverseSweepQPS:         DefaultReverseSweepQPS,
 	}
 }
 
@@ -191,6 +328,9 @@ func (c *Config) CheckSettings() error {
 	if c.Passive && c.Active {
 		return errors.New("active enumeration cannot be performed without DNS resolution")
 	}
+	if c.Passive && c.Authoritative {
+		return errors.New("authoritative queries cannot be performed without DNS resolution")
+	}
 	if c.Alterations {
 		if len(c.AltWordlist) == 0 {
 			f, err := resources.GetResourceFile("alterations.txt")
@@ -210,6 +350,21 @@ func (c *Config) CheckSettings() error {
 		return err
 	}
 
+	for _, level := range c.BruteLevels {
+		level.Wordlist, err = ExpandMaskWordlist(level.Wordlist)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Selecting the words of the shard again returns the same words
+	if c.BruteShard != nil {
+		c.Wordlist = c.BruteShard.Words(c.Wordlist)
+		for _, level := range c.BruteLevels {
+			level.Wordlist = c.BruteShard.Words(level.Wordlist)
+		}
+	}
+
 	c.AltWordlist, err = ExpandMaskWordlist(c.AltWordlist)
 	if err != nil {
 		return err
@@ -217,18 +372,39 @@ func (c *Config) CheckSettings() error {
 	return err
 }
 
-// LoadSettings parses settings from an .ini file and assigns them to the Config.
+// LoadSettings parses settings from an .ini or YAML file and assigns them to the Config.
 func (c *Config) LoadSettings(path string) error {
-	cfg, err := ini.LoadSources(ini.LoadOptions{
-		Insensitive:  true,
-		AllowShadows: true,
-	}, path)
+	cfg, err := readSettingsFile(path)
 	if err != nil {
 		return fmt.Errorf("failed to load the configuration file: %v", err)
 	}
+	if errs := c.loadSettings(cfg, false); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// Assigns the settings of the configuration file to the Config. When all is true, the remaining
+// sections are loaded after a section fails, so every problem of the file can be reported.
+func (c *Config) loadSettings(cfg *ini.File, all bool) []error {
+	if err := expandEnvironment(cfg); err != nil {
+		return []error{err}
+	}
+	// The profile selected by the user takes precedence over the one selected by the file
+	if c.Profile == "" && cfg.Section(ini.DefaultSection).HasKey("profile") {
+		c.Profile = cfg.Section(ini.DefaultSection).Key("profile").String()
+	}
+	if c.Profile != "" {
+		if err := applyProfile(cfg, c.Profile); err != nil {
+			return []error{err}
+		}
+	}
 	// Get the easy ones out of the way using mapping
-	if err = cfg.MapTo(c); err != nil {
-		return fmt.Errorf("error mapping configuration settings to internal values: %v", err)
+	if err := cfg.MapTo(c); err != nil {
+		return []error{fmt.Errorf("error mapping configuration settings to internal values: %v", err)}
+	}
+	if c.DNSBudget < 0 {
+		return []error{fmt.Errorf("The dns_budget setting cannot be negative: %d", c.DNSBudget)}
 	}
 	// Attempt to load a special mode of operation specified by the user
 	if cfg.Section(ini.DefaultSection).HasKey("mode") {
@@ -243,32 +419,69 @@ func (c *Config) LoadSettings(path string) error {
 
 	loads := []func(cfg *ini.File) error{
 		c.loadResolverSettings,
+		c.loadDomainResolverSettings,
 		c.loadScopeSettings,
 		c.loadAlterationSettings,
 		c.loadBruteForceSettings,
+		c.loadRecordSettings,
+		c.loadReverseSweepSettings,
+		c.loadDNSSECSettings,
+		c.loadHTTPProbeSettings,
+		c.loadPortScanSettings,
+		c.loadTakeoverSettings,
 		c.loadDatabaseSettings,
+		c.loadIntegrationSettings,
+		c.loadWebhookSettings,
+		c.loadEmailSettings,
+		c.loadUploadSettings,
+		c.loadScheduleSettings,
+		c.loadSecretsSettings,
 		c.loadDataSourceSettings,
 	}
+
+	var errs []error
 	for _, load := range loads {
 		if err := load(cfg); err != nil {
-			return err
+			errs = append(errs, err)
+			if !all {
+				break
+			}
 		}
 	}
+	return errs
+}
 
-	return nil
+// Reads the sections of the .ini or YAML configuration file.
+func readSettingsFile(path string) (*ini.File, error) {
+	opts := ini.LoadOptions{
+		Insensitive:  true,
+		AllowShadows: true,
+	}
+
+	if IsYAMLFile(path) {
+		return loadYAMLSettings(opts, path)
+	}
+	return ini.LoadSources(opts, path)
 }
 
 // AcquireConfig populates the Config struct provided by the Config argument.
 func AcquireConfig(dir, file string, cfg *Config) error {
+	return cfg.LoadSettings(ConfigFilePath(dir, file))
+}
+
+// ConfigFilePath returns the path of the configuration file that will be loaded: the file provided,
+// the file of the AMASS_CONFIG environment variable, or the file discovered in the output directory
+// or the system configuration directory.
+func ConfigFilePath(dir, file string) string {
 	var path, dircfg, syscfg string
 
 	d := OutputDirectory(dir)
 	if finfo, err := os.Stat(d); d != "" && !os.IsNotExist(err) && finfo.IsDir() {
-		dircfg = filepath.Join(d, defaultCfgFile)
+		dircfg = defaultConfigFile(d)
 	}
 
 	if runtime.GOOS != "windows" {
-		syscfg = filepath.Join(filepath.Join(systemCfgDir, outputDirName), defaultCfgFile)
+		syscfg = defaultConfigFile(filepath.Join(systemCfgDir, outputDirName))
 	}
 
 	if file != "" {
@@ -280,8 +493,19 @@ func AcquireConfig(dir, file string, cfg *Config) error {
 	} else if _, err := os.Stat(syscfg); err == nil {
 		path = syscfg
 	}
+	return path
+}
+
+// Returns the path of the .ini configuration file within the directory, unless only the YAML file is present.
+func defaultConfigFile(dir string) string {
+	path := filepath.Join(dir, defaultCfgFile)
 
-	return cfg.LoadSettings(path)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if _, err := os.Stat(filepath.Join(dir, defaultYAMLCfgFile)); err == nil {
+			return filepath.Join(dir, defaultYAMLCfgFile)
+		}
+	}
+	return path
 }
 
 // OutputDirectory returns the file path of the Amass output directory. A suitable
@@ -298,6 +522,36 @@ func OutputDirectory(dir ...string) string {
 	return ""
 }
 
+// ProjectDirectory returns the directory within the output directory that holds the
+// database and other files of the named project.
+func ProjectDirectory(dir, project string) (string, error) {
+	if !projectNameRE.MatchString(project) {
+		return "", fmt.Errorf("the project name %q is not valid", project)
+	}
+
+	base := OutputDirectory(dir)
+	if base == "" {
+		return "", errors.New("failed to obtain the output directory")
+	}
+	return filepath.Join(base, projectsDirName, project), nil
+}
+
+// ProjectNames returns the names of the projects stored within the output directory.
+func ProjectNames(dir string) []string {
+	entries, err := ioutil.ReadDir(filepath.Join(OutputDirectory(dir), projectsDirName))
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && projectNameRE.MatchString(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
 // GetListFromFile reads a wordlist text or gzip file and returns the slice of words.
 func GetListFromFile(path string) ([]string, error) {
 	var reader io.Reader
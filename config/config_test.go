@@ -5,6 +5,8 @@ package config
 
 import (
 	"net"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"testing"
@@ -164,3 +166,24 @@ func TestConfigGetListFromFile(t *testing.T) {
 		t.Errorf("GetListFromFile() error = %v", err)
 	}
 }
+
+func TestProjectDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"", "..", "../other", "client/a", ".hidden"} {
+		if _, err := ProjectDirectory(dir, name); err == nil {
+			t.Errorf("The project name %q was accepted", name)
+		}
+	}
+
+	pdir, err := ProjectDirectory(dir, "client-a")
+	if err != nil || pdir != filepath.Join(dir, "projects", "client-a") {
+		t.Fatalf("The project directory was %s: %v", pdir, err)
+	}
+	if err := os.MkdirAll(pdir, 0755); err != nil {
+		t.Fatalf("Failed to create the project directory: %v", err)
+	}
+	if names := ProjectNames(dir); len(names) != 1 || names[0] != "client-a" {
+		t.Errorf("The projects listed were %v, expected client-a", names)
+	}
+}
@@ -7,7 +7,10 @@ package config
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/caffix/stringset"
 	"github.com/go-ini/ini"
@@ -15,9 +18,16 @@ import (
 
 // DataSourceConfig contains the configurations specific to a data source.
 type DataSourceConfig struct {
-	Name  string
-	TTL   int `ini:"ttl"`
-	creds map[string]*Credentials
+	Name string
+	TTL  int `ini:"ttl"`
+	// The time waited for each HTTP response and the minimum delay between the requests, which
+	// override the values selected by the data source when greater than zero
+	Timeout time.Duration `ini:"-"`
+	Delay   time.Duration `ini:"-"`
+	// The path of the secret providing the credentials within the secrets backend
+	SecretPath string `ini:"secret_path"`
+	lock       sync.Mutex
+	creds      map[string]*Credentials
 }
 
 // Credentials contains values required for authenticating with web APIs.
@@ -47,12 +57,28 @@ func (c *Config) GetDataSourceConfig(source string) *DataSourceConfig {
 	return c.datasrcConfigs[key]
 }
 
+// DataSourceConfigs returns the configurations of the data sources, sorted by name.
+func (c *Config) DataSourceConfigs() []*DataSourceConfig {
+	c.Lock()
+	defer c.Unlock()
+
+	var configs []*DataSourceConfig
+	for _, dsc := range c.datasrcConfigs {
+		configs = append(configs, dsc)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Name < configs[j].Name })
+	return configs
+}
+
 // AddCredentials adds the Credentials provided to the configuration.
 func (dsc *DataSourceConfig) AddCredentials(cred *Credentials) error {
 	if cred == nil || cred.Name == "" {
 		return fmt.Errorf("AddCredentials: The Credentials argument is invalid")
 	}
 
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
 	if dsc.creds == nil {
 		dsc.creds = make(map[string]*Credentials)
 	}
@@ -61,8 +87,20 @@ func (dsc *DataSourceConfig) AddCredentials(cred *Credentials) error {
 	return nil
 }
 
+// Limits returns the HTTP timeout and the minimum delay between the requests of the data source,
+// which are zero when the data source selects them.
+func (dsc *DataSourceConfig) Limits() (timeout, delay time.Duration) {
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
+	return dsc.Timeout, dsc.Delay
+}
+
 // GetCredentials returns randomly selected Credentials associated with the receiver configuration.
 func (dsc *DataSourceConfig) GetCredentials() *Credentials {
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
 	if num := len(dsc.creds); num > 0 {
 		var creds []*Credentials
 		for _, c := range dsc.creds {
@@ -101,9 +139,18 @@ func (c *Config) loadDataSourceSettings(cfg *ini.File) error {
 			continue
 		}
 
+		if dsc.SecretPath != "" && c.Secrets == nil {
+			return fmt.Errorf("The %s data source requires the secrets section to use the secret_path setting", name)
+		}
 		if c.MinimumTTL > dsc.TTL {
 			dsc.TTL = c.MinimumTTL
 		}
+		if dsc.Timeout, err = sourceDuration(child, "timeout"); err != nil {
+			return fmt.Errorf("The %s data source %v", name, err)
+		}
+		if dsc.Delay, err = sourceDuration(child, "delay"); err != nil {
+			return fmt.Errorf("The %s data source %v", name, err)
+		}
 		// Check for data source credentials
 		for _, cr := range child.ChildSections() {
 			setName := strings.Split(cr.Name(), ".")[2]
@@ -119,3 +166,17 @@ func (c *Config) loadDataSourceSettings(cfg *ini.File) error {
 	}
 	return nil
 }
+
+// Parses the duration of the data source setting, such as 30s or 500ms, where the unit is required.
+func sourceDuration(sec *ini.Section, key string) (time.Duration, error) {
+	if !sec.HasKey(key) {
+		return 0, nil
+	}
+
+	value := sec.Key(key).String()
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s setting must be a positive duration, such as 30s or 500ms: %s", key, value)
+	}
+	return d, nil
+}
@@ -5,6 +5,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-ini/ini"
 )
@@ -86,6 +87,8 @@ func TestLoadDataSourceSettings(t *testing.T) {
 
 		[data_sources.AlienVault]
 		ttl = 4320
+		timeout = 2m
+		delay = 500ms
 		[data_sources.AlienVault.Credentials]
 		apikey = fake
 
@@ -109,4 +112,17 @@ func TestLoadDataSourceSettings(t *testing.T) {
 	if creds := dsc.GetCredentials(); creds == nil || creds.Key != "fake" {
 		t.Errorf("Failed to load data source credentials")
 	}
+	if timeout, delay := dsc.Limits(); timeout != 2*time.Minute || delay != 500*time.Millisecond {
+		t.Errorf("Failed to load the data source timeout and delay: %v %v", timeout, delay)
+	}
+
+	cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+		[data_sources]
+		[data_sources.Shodan]
+		delay = 2
+		`),
+	)
+	if err := NewConfig().loadDataSourceSettings(cfg); err == nil {
+		t.Errorf("The delay without a unit was accepted")
+	}
 }
@@ -0,0 +1,33 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/go-ini/ini"
+)
+
+// DefaultDNSSECConcurrency is the number of names validated concurrently by the DNSSEC validation.
+const DefaultDNSSECConcurrency = 10
+
+func (c *Config) loadDNSSECSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("dnssec")
+	if err != nil {
+		return nil
+	}
+
+	// The validation sends DS and DNSKEY queries for each zone, so it is only performed when enabled
+	c.DNSSEC = sec.Key("enabled").MustBool(false)
+	if !c.DNSSEC {
+		return nil
+	}
+
+	c.DNSSECConcurrency = sec.Key("concurrency").MustInt(DefaultDNSSECConcurrency)
+	if c.DNSSECConcurrency <= 0 {
+		return fmt.Errorf("the dnssec concurrency setting must be greater than zero: %d", c.DNSSECConcurrency)
+	}
+	return nil
+}
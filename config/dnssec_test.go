@@ -0,0 +1,47 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadDNSSECSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, _ := ini.Load([]byte(`
+	[dnssec]
+	concurrency = 5
+	`))
+
+	if err := c.loadDNSSECSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.DNSSEC {
+		t.Errorf("The DNSSEC validation was enabled without the explicit opt-in")
+	}
+
+	cfg, _ = ini.Load([]byte(`
+	[dnssec]
+	enabled = true
+	concurrency = 5
+	`))
+	if err := c.loadDNSSECSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !c.DNSSEC || c.DNSSECConcurrency != 5 {
+		t.Errorf("The DNSSEC settings were not loaded: %v %d", c.DNSSEC, c.DNSSECConcurrency)
+	}
+
+	cfg, _ = ini.Load([]byte(`
+	[dnssec]
+	enabled = true
+	concurrency = 0
+	`))
+	if err := NewConfig().loadDNSSECSettings(cfg); err == nil {
+		t.Errorf("The concurrency of zero was accepted")
+	}
+}
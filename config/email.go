@@ -0,0 +1,124 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/mail"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/go-ini/ini"
+)
+
+const (
+	defaultEmailSubject = `OWASP Amass: {{.Added}} names added and {{.Removed}} removed for {{join .Domains ", "}}`
+	defaultEmailBody    = `OWASP Amass tracking of {{join .Domains ", "}} for the enumeration {{.Enumeration}}:
+{{.Added}} names were added, {{.Removed}} removed and {{.Changed}} changed.
+{{range .Changes}}
+{{.Change}}	{{.Name}}	{{join .Addresses ","}}{{end}}
+`
+)
+
+// The TLS modes of the SMTP connections, where starttls upgrades the plain connection.
+var emailTLSModes = []string{"starttls", "tls", "none"}
+
+var emailFuncs = template.FuncMap{"join": strings.Join}
+
+// Email contains the settings of the messages summarizing the changes found by the track subcommand.
+type Email struct {
+	// The address of the SMTP server, such as smtp.example.com:587
+	Server   string   `ini:"server"`
+	Username string   `ini:"username"`
+	Password string   `ini:"password"`
+	From     string   `ini:"from"`
+	To       []string `ini:"to" delim:","`
+	// The TLS mode, which is starttls by default
+	TLS     string `ini:"tls"`
+	subject *template.Template
+	body    *template.Template
+}
+
+func (c *Config) loadEmailSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("email")
+	if err != nil {
+		return nil
+	}
+
+	em := new(Email)
+	if err := sec.MapTo(em); err != nil {
+		return err
+	}
+
+	if host, port, err := net.SplitHostPort(em.Server); err != nil || host == "" || port == "" {
+		return fmt.Errorf("The email server must be provided as host:port: %s", em.Server)
+	}
+	if em.TLS = strings.ToLower(strings.TrimSpace(em.TLS)); em.TLS == "" {
+		em.TLS = "starttls"
+	} else if !stringInList(em.TLS, emailTLSModes) {
+		return fmt.Errorf("The email tls setting is not supported: %s", em.TLS)
+	}
+	if em.Password == "" {
+		em.Password = os.Getenv("AMASS_SMTP_PASSWORD")
+	}
+	if _, err := mail.ParseAddress(em.From); err != nil {
+		return fmt.Errorf("The email from address is not valid: %s", em.From)
+	}
+
+	var to []string
+	for _, addr := range em.To {
+		if addr = strings.TrimSpace(addr); addr == "" {
+			continue
+		}
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("The email to address is not valid: %s", addr)
+		}
+		to = append(to, addr)
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("The email settings require at least one to address")
+	}
+	em.To = to
+
+	subject := defaultEmailSubject
+	if sec.HasKey("subject") {
+		subject = sec.Key("subject").String()
+	}
+	if em.subject, err = template.New("subject").Funcs(emailFuncs).Parse(subject); err != nil {
+		return fmt.Errorf("The email subject template is not valid: %v", err)
+	}
+
+	body := defaultEmailBody
+	if path := sec.Key("body_file").String(); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Unable to load the file in the email body_file setting: %s: %v", path, err)
+		}
+		body = string(b)
+	}
+	if em.body, err = template.New("body").Funcs(emailFuncs).Parse(body); err != nil {
+		return fmt.Errorf("The email body template is not valid: %v", err)
+	}
+
+	c.Email = em
+	return nil
+}
+
+// Render executes the subject and body templates using the data.
+func (em *Email) Render(data interface{}) (string, string, error) {
+	var subject, body bytes.Buffer
+
+	if err := em.subject.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := em.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+	// The subject is a single header line
+	return strings.Join(strings.Fields(subject.String()), " "), body.String(), nil
+}
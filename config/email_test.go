@@ -0,0 +1,57 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadEmailSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+		[email]
+		server = smtp.example.com:587
+		from = Amass <amass@example.com>
+		to = team@example.com, soc@example.com
+		subject = {{.Added}} new names for {{join .Domains ","}}
+		`),
+	)
+	if err := c.loadEmailSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if em := c.Email; em == nil || em.TLS != "starttls" || len(em.To) != 2 || em.To[1] != "soc@example.com" {
+		t.Fatalf("The email settings were not loaded: %v", em)
+	}
+
+	subject, body, err := c.Email.Render(map[string]interface{}{
+		"Domains":     []string{"example.com", "example.org"},
+		"Enumeration": "event",
+		"Added":       2,
+		"Removed":     0,
+		"Changed":     0,
+		"Changes":     []map[string]interface{}{},
+	})
+	if err != nil || subject != "2 new names for example.com,example.org" ||
+		!strings.Contains(body, "2 names were added, 0 removed and 0 changed") {
+		t.Errorf("The templates rendered %q and %q: %v", subject, body, err)
+	}
+
+	for _, bad := range []string{
+		"[email]\nserver = smtp.example.com\nfrom = a@example.com\nto = b@example.com",
+		"[email]\nserver = smtp.example.com:25\nfrom = a@example.com",
+		"[email]\nserver = smtp.example.com:25\nfrom = a@example.com\nto = b@example.com\ntls = ssl",
+		"[email]\nserver = smtp.example.com:25\nfrom = a@example.com\nto = b@example.com\nsubject = {{.Added",
+		"[email]\nserver = smtp.example.com:25\nfrom = a@example.com\nto = b@example.com\nbody_file = ./nonexistant_file",
+	} {
+		cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(bad))
+		if err := NewConfig().loadEmailSettings(cfg); err == nil {
+			t.Errorf("The settings were accepted: %s", bad)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/go-ini/ini"
+)
+
+// The references to environment variables, such as ${SHODAN_KEY}. The $VAR form is not expanded,
+// since the values of some settings, such as the rules and the regular expressions, use the dollar sign.
+var envReferenceRE = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Replaces the ${VAR} references within the values of the configuration file with the values of the
+// environment variables, so secrets like the API keys can be provided by the environment of a container.
+func expandEnvironment(cfg *ini.File) error {
+	for _, sec := range cfg.Sections() {
+		for _, key := range sec.Keys() {
+			values := key.ValueWithShadows()
+
+			var changed bool
+			for i, value := range values {
+				expanded, err := expandEnvValue(value)
+				if err != nil {
+					return fmt.Errorf("The %s setting of the %s section: %v", key.Name(), sec.Name(), err)
+				}
+				if expanded != value {
+					values[i] = expanded
+					changed = true
+				}
+			}
+			if !changed {
+				continue
+			}
+
+			sec.DeleteKey(key.Name())
+			for _, value := range values {
+				if _, err := sec.NewKey(key.Name(), value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func expandEnvValue(value string) (string, error) {
+	var err error
+
+	expanded := envReferenceRE.ReplaceAllStringFunc(value, func(ref string) string {
+		name := envReferenceRE.FindStringSubmatch(ref)[1]
+
+		v, set := os.LookupEnv(name)
+		if !set && err == nil {
+			err = fmt.Errorf("the environment variable %s is not set", name)
+		}
+		return v
+	})
+	return expanded, err
+}
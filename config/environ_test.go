@@ -0,0 +1,50 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestExpandEnvironment(t *testing.T) {
+	os.Setenv("AMASS_TEST_KEY", "secret")
+	os.Setenv("AMASS_TEST_RESOLVER", "9.9.9.9")
+	defer os.Unsetenv("AMASS_TEST_KEY")
+	defer os.Unsetenv("AMASS_TEST_RESOLVER")
+
+	cfg, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true, AllowShadows: true}, []byte(`
+		[resolvers]
+		resolver = 8.8.8.8
+		resolver = ${AMASS_TEST_RESOLVER}
+
+		[alterations]
+		rules_file = $-$d$e$v
+
+		[data_sources.Shodan.Credentials]
+		apikey = ${AMASS_TEST_KEY}
+		`),
+	)
+	if err := expandEnvironment(cfg); err != nil {
+		t.Fatalf("The expansion failed: %v", err)
+	}
+	if key := cfg.Section("data_sources.shodan.credentials").Key("apikey").String(); key != "secret" {
+		t.Errorf("The API key was expanded to %q", key)
+	}
+	if values := cfg.Section("resolvers").Key("resolver").ValueWithShadows(); len(values) != 2 || values[1] != "9.9.9.9" {
+		t.Errorf("The resolvers were expanded to %v", values)
+	}
+	if rules := cfg.Section("alterations").Key("rules_file").String(); rules != "$-$d$e$v" {
+		t.Errorf("The value without references was changed to %q", rules)
+	}
+
+	os.Unsetenv("AMASS_TEST_MISSING")
+	cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte("[email]\npassword = ${AMASS_TEST_MISSING}"))
+	if err := expandEnvironment(cfg); err == nil {
+		t.Errorf("The reference to the missing environment variable was accepted")
+	}
+}
@@ -5,11 +5,19 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/go-ini/ini"
 )
 
+// The graph database systems supported in addition to the local database.
+var graphDBSystems = map[string]struct{}{
+	"postgres": {},
+	"mysql":    {},
+}
+
 // Database contains values required for connecting with graph databases.
 type Database struct {
 	System   string
@@ -31,18 +39,52 @@ func (c *Config) loadDatabaseSettings(cfg *ini.File) error {
 		db := new(Database)
 		name := strings.Split(child.Name(), ".")[1]
 
+		if _, found := graphDBSystems[name]; !found {
+			return fmt.Errorf("The graph database system %s is not supported", name)
+		}
 		// Parse the Database information and assign to the Config
 		if err := child.MapTo(db); err == nil {
 			db.System = name
+			if db.System == "postgres" {
+				if _, err := db.ConnectionURL(); err != nil {
+					return err
+				}
+			}
 			c.GraphDBs = append(c.GraphDBs, db)
 		}
 	}
 
+	c.LocalDatabase = sec.Key("local_database").MustBool(true)
+
+	c.RetentionDays = sec.Key("retention_days").MustInt(0)
+	c.RetentionEvents = sec.Key("retention_events").MustInt(0)
+	if c.RetentionDays < 0 || c.RetentionEvents < 0 {
+		return fmt.Errorf("The graphdbs retention settings cannot be negative")
+	}
+	c.FreshnessHours = sec.Key("freshness_hours").MustInt(0)
+	if c.FreshnessHours < 0 {
+		return fmt.Errorf("The graphdbs freshness_hours setting cannot be negative: %d", c.FreshnessHours)
+	}
+	// Without the local database, one of the shared databases needs to be the primary
+	if !c.LocalDatabase && len(c.GraphDBs) > 0 {
+		var primary bool
+		for _, db := range c.GraphDBs {
+			primary = primary || db.Primary
+		}
+		if !primary {
+			c.GraphDBs[0].Primary = true
+		}
+	}
 	return nil
 }
 
-// LocalDatabaseSettings returns the Database for the local bolt store.
+// LocalDatabaseSettings returns the Database for the local bolt store, or nil when the
+// local database has been disabled in favor of the remote graph databases.
 func (c *Config) LocalDatabaseSettings(dbs []*Database) *Database {
+	if !c.LocalDatabase && len(dbs) > 0 {
+		return nil
+	}
+
 	bolt := &Database{
 		System:  "local",
 		Primary: true,
@@ -58,3 +100,31 @@ func (c *Config) LocalDatabaseSettings(dbs []*Database) *Database {
 
 	return bolt
 }
+
+// ConnectionURL returns the URL used to connect with the database. For PostgreSQL, the username,
+// password and database settings are added to the URL when it does not already provide them.
+func (db *Database) ConnectionURL() (string, error) {
+	if db.System != "postgres" {
+		return db.URL, nil
+	}
+
+	u, err := url.Parse(db.URL)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+		return "", fmt.Errorf("The PostgreSQL graph database URL is not valid: %s", db.URL)
+	}
+
+	if u.User == nil && db.Username != "" {
+		if db.Password != "" {
+			u.User = url.UserPassword(db.Username, db.Password)
+		} else {
+			u.User = url.User(db.Username)
+		}
+	}
+	if strings.Trim(u.Path, "/") == "" && db.DBName != "" {
+		u.Path = "/" + db.DBName
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		return "", fmt.Errorf("The PostgreSQL graph database URL does not name a database: %s", db.URL)
+	}
+	return u.String(), nil
+}
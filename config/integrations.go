@@ -0,0 +1,61 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+// The systems that the findings can be pushed into.
+var integrationSystems = map[string]struct{}{
+	"defectdojo": {},
+	"cmdb":       {},
+	"splunk":     {},
+}
+
+// Integration contains the values required for pushing the findings into another system.
+type Integration struct {
+	System string
+	URL    string `ini:"url"`
+	Key    string `ini:"apikey"`
+	// The DefectDojo product that receives the endpoints
+	ProductID int `ini:"product_id"`
+	// The Splunk index receiving the events, which defaults to the index of the HEC token
+	Index string `ini:"index"`
+	// The maximum number of events sent to Splunk in each request
+	BatchSize int `ini:"batch_size"`
+}
+
+func (c *Config) loadIntegrationSettings(cfg *ini.File) error {
+	// The parent section does not need to be present in the file
+	for _, child := range cfg.Section("integrations").ChildSections() {
+		in := new(Integration)
+		name := strings.Split(child.Name(), ".")[1]
+
+		if _, found := integrationSystems[name]; !found {
+			return fmt.Errorf("The integration with %s is not supported", name)
+		}
+		if err := child.MapTo(in); err != nil {
+			return err
+		}
+
+		in.System = name
+		if u, err := url.Parse(in.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("The %s integration URL is not valid: %s", name, in.URL)
+		}
+		if in.System == "defectdojo" && (in.Key == "" || in.ProductID <= 0) {
+			return fmt.Errorf("The defectdojo integration requires the apikey and product_id settings")
+		}
+		if in.System == "splunk" && in.Key == "" {
+			return fmt.Errorf("The splunk integration requires the apikey setting providing the HEC token")
+		}
+		c.Integrations = append(c.Integrations, in)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadIntegrationSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[integrations.defectdojo]
+		url = https://defectdojo.example.com
+		apikey = secret
+		product_id = 3
+
+		[integrations.cmdb]
+		url = https://cmdb.example.com/api/assets
+
+		[integrations.splunk]
+		url = https://splunk.example.com:8088
+		apikey = token
+		index = attack_surface
+		batch_size = 20
+		`),
+	)
+	if err := c.loadIntegrationSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(c.Integrations) != 3 {
+		t.Fatalf("%d integrations were loaded, expected 3", len(c.Integrations))
+	}
+	if dd := c.Integrations[0]; dd.System != "defectdojo" || dd.Key != "secret" || dd.ProductID != 3 {
+		t.Errorf("The defectdojo settings were not loaded: %v", dd)
+	}
+	if s := c.Integrations[2]; s.System != "splunk" || s.Index != "attack_surface" || s.BatchSize != 20 {
+		t.Errorf("The splunk settings were not loaded: %v", s)
+	}
+
+	for _, bad := range []string{
+		"[integrations.unknown]\nurl = https://example.com",
+		"[integrations.cmdb]\nurl = ftp://example.com",
+		"[integrations.defectdojo]\nurl = https://defectdojo.example.com",
+		"[integrations.splunk]\nurl = https://splunk.example.com:8088",
+	} {
+		cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(bad))
+		if err := NewConfig().loadIntegrationSettings(cfg); err == nil {
+			t.Errorf("The settings were accepted: %s", bad)
+		}
+	}
+}
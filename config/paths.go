@@ -0,0 +1,68 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// OutputPathData provides the values of the templates in the output paths, such as {{.Domain}}/{{.Date}}.
+type OutputPathData struct {
+	// The root domain names in alphabetical order, so the paths do not change between the runs
+	Domains []string
+	// The date and time when the enumeration started, such as 2022-03-28 and 154502
+	Date    string
+	Time    string
+	UUID    string
+	Project string
+	Profile string
+}
+
+// Domain returns the first root domain name of the enumeration in alphabetical order.
+func (d *OutputPathData) Domain() (string, error) {
+	if len(d.Domains) == 0 {
+		return "", errors.New("no root domain names were provided")
+	}
+	return d.Domains[0], nil
+}
+
+// OutputPathData returns the values of the output path templates for the enumeration started at the time.
+func (c *Config) OutputPathData(start time.Time) *OutputPathData {
+	domains := append([]string(nil), c.Domains()...)
+	sort.Strings(domains)
+
+	return &OutputPathData{
+		Domains: domains,
+		Date:    start.Format("2006-01-02"),
+		Time:    start.Format("150405"),
+		UUID:    c.UUID.String(),
+		Project: c.Project,
+		Profile: c.Profile,
+	}
+}
+
+// ExpandOutputPath executes the template of the output path, such as {{.Domain}}/{{.Date}}, using the data.
+// The paths without templates are returned unchanged.
+func ExpandOutputPath(path string, data *OutputPathData) (string, error) {
+	if !strings.Contains(path, "{{") {
+		return path, nil
+	}
+
+	tmpl, err := template.New("path").Funcs(emailFuncs).Option("missingkey=error").Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("the output path template %s is not valid: %v", path, err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to expand the output path template %s: %v", path, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,38 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandOutputPath(t *testing.T) {
+	c := NewConfig()
+	c.AddDomains("owasp.org", "example.net")
+	c.Project = "acme"
+	data := c.OutputPathData(time.Date(2022, 3, 28, 15, 45, 2, 0, time.UTC))
+
+	for path, expected := range map[string]string{
+		"/var/amass":                            "/var/amass",
+		"/var/amass/{{.Domain}}/{{.Date}}":      "/var/amass/example.net/2022-03-28",
+		"{{.Project}}/{{.Date}}T{{.Time}}.json": "acme/2022-03-28T154502.json",
+		`out/{{join .Domains "_"}}`:             "out/example.net_owasp.org",
+		"runs/{{.UUID}}":                        "runs/" + c.UUID.String(),
+	} {
+		if got, err := ExpandOutputPath(path, data); err != nil || got != expected {
+			t.Errorf("ExpandOutputPath(%s) = %s, expected %s: %v", path, got, expected, err)
+		}
+	}
+
+	for _, bad := range []string{"{{.Domain", "{{.Client}}/out.txt"} {
+		if _, err := ExpandOutputPath(bad, data); err == nil {
+			t.Errorf("ExpandOutputPath(%s) did not fail", bad)
+		}
+	}
+	if _, err := ExpandOutputPath("{{.Domain}}", NewConfig().OutputPathData(time.Now())); err == nil {
+		t.Errorf("The domain template was expanded without root domain names")
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-ini/ini"
+)
+
+// DefaultPortScanRate is the number of TCP connections attempted per second by the port scans.
+const DefaultPortScanRate = 20
+
+// DefaultPortScanPorts are the ports scanned on the in-scope addresses when none are provided.
+var DefaultPortScanPorts = []int{21, 22, 23, 25, 53, 80, 110, 143, 443, 445, 993, 995,
+	1433, 3306, 3389, 5432, 5900, 6379, 8080, 8443}
+
+func (c *Config) loadPortScanSettings(cfg *ini.File) error {
+	scan, err := cfg.GetSection("port_scan")
+	if err != nil {
+		return nil
+	}
+
+	// The port scans are only performed when explicitly enabled
+	c.PortScan = scan.Key("enabled").MustBool(false)
+	if !c.PortScan {
+		return nil
+	}
+
+	for _, port := range scan.Key("port").ValueWithShadows() {
+		if port == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+			return fmt.Errorf("the port_scan port %s is not a valid port number", port)
+		}
+		c.PortScanPorts = uniqueIntAppend(c.PortScanPorts, port)
+	}
+
+	c.PortScanRate = scan.Key("rate").MustInt(DefaultPortScanRate)
+	if c.PortScanRate <= 0 {
+		return fmt.Errorf("the port_scan rate setting must be greater than zero: %d", c.PortScanRate)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadPortScanSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, _ := ini.Load([]byte(`
+	[port_scan]
+	port = 22
+	`))
+
+	if err := c.loadPortScanSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.PortScan {
+		t.Errorf("The port scans were enabled without the explicit opt-in")
+	}
+
+	cfg, _ = ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(`
+	[port_scan]
+	enabled = true
+	port = 22
+	port = 3389
+	rate = 5
+	`))
+	if err := c.loadPortScanSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !c.PortScan || len(c.PortScanPorts) != 2 || c.PortScanRate != 5 {
+		t.Errorf("The port scan settings were not loaded: %v %d", c.PortScanPorts, c.PortScanRate)
+	}
+
+	cfg, _ = ini.Load([]byte(`
+	[port_scan]
+	enabled = true
+	rate = 0
+	`))
+	if err := NewConfig().loadPortScanSettings(cfg); err == nil {
+		t.Errorf("The rate of zero was accepted")
+	}
+}
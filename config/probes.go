@@ -0,0 +1,43 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-ini/ini"
+)
+
+// DefaultHTTPProbeConcurrency is the number of web services probed concurrently.
+const DefaultHTTPProbeConcurrency = 25
+
+func (c *Config) loadHTTPProbeSettings(cfg *ini.File) error {
+	probes, err := cfg.GetSection("http_probes")
+	if err != nil {
+		return nil
+	}
+
+	c.HTTPProbes = probes.Key("enabled").MustBool(true)
+	if !c.HTTPProbes {
+		return nil
+	}
+
+	for _, port := range probes.Key("port").ValueWithShadows() {
+		if port == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+			return fmt.Errorf("the http_probes port %s is not a valid port number", port)
+		}
+		c.HTTPProbePorts = uniqueIntAppend(c.HTTPProbePorts, port)
+	}
+
+	c.HTTPProbeConcurrency = probes.Key("concurrency").MustInt(DefaultHTTPProbeConcurrency)
+	if c.HTTPProbeConcurrency <= 0 {
+		return fmt.Errorf("the http_probes concurrency setting must be greater than zero: %d", c.HTTPProbeConcurrency)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadHTTPProbeSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, _ := ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(`
+	[http_probes]
+	port = 80
+	port = 8443
+	`))
+
+	if err := c.loadHTTPProbeSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !c.HTTPProbes || len(c.HTTPProbePorts) != 2 || c.HTTPProbePorts[1] != 8443 {
+		t.Errorf("The probe ports were not loaded: %v", c.HTTPProbePorts)
+	}
+	if c.HTTPProbeConcurrency != DefaultHTTPProbeConcurrency {
+		t.Errorf("The default concurrency was not selected: %d", c.HTTPProbeConcurrency)
+	}
+
+	cfg, _ = ini.Load([]byte(`
+	[http_probes]
+	port = 70000
+	`))
+	if err := NewConfig().loadHTTPProbeSettings(cfg); err == nil {
+		t.Errorf("The invalid port was accepted")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+// The section holding the named profiles of the configuration file.
+const profilesSection = "profiles"
+
+// Profiles returns the names of the profiles provided by the configuration file.
+func Profiles(cfg *ini.File) []string {
+	var names []string
+
+	for _, sec := range cfg.Sections() {
+		if !strings.HasPrefix(sec.Name(), profilesSection+".") {
+			continue
+		}
+
+		name := strings.TrimPrefix(sec.Name(), profilesSection+".")
+		if i := strings.Index(name, "."); i >= 0 {
+			name = name[:i]
+		}
+		if name != "" && !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Copies the settings of the named profile over the settings of the configuration file.
+// The keys of the [profiles.NAME] section replace the keys of the default section, and the
+// keys of the [profiles.NAME.SECTION] sections replace the keys of SECTION, such as
+// [profiles.stealth.resolvers] or [profiles.stealth.data_sources.Shodan].
+func applyProfile(cfg *ini.File, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !containsString(Profiles(cfg), name) {
+		return fmt.Errorf("The %s profile was not found in the configuration file", name)
+	}
+
+	prefix := profilesSection + "." + name
+	for _, sec := range cfg.Sections() {
+		var target string
+
+		if sec.Name() == prefix {
+			target = ini.DefaultSection
+		} else if strings.HasPrefix(sec.Name(), prefix+".") {
+			target = strings.TrimPrefix(sec.Name(), prefix+".")
+		} else {
+			continue
+		}
+
+		if err := overrideKeys(sec, cfg.Section(target)); err != nil {
+			return fmt.Errorf("The %s profile cannot override the %s section: %v", name, target, err)
+		}
+	}
+	return nil
+}
+
+// Replaces the keys of the section with the keys of the profile, including all the values
+// provided for keys that can be repeated, such as the resolver key.
+func overrideKeys(from, to *ini.Section) error {
+	for _, key := range from.Keys() {
+		to.DeleteKey(key.Name())
+
+		for i, value := range key.ValueWithShadows() {
+			if i == 0 {
+				if _, err := to.NewKey(key.Name(), value); err != nil {
+					return err
+				}
+			} else if err := to.Key(key.Name()).AddShadow(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const profileTestConfig = `
+maximum_dns_queries = 1000
+
+[resolvers]
+resolver = 8.8.8.8
+resolver = 1.1.1.1
+
+[bruteforce]
+enabled = false
+
+[data_sources]
+minimum_ttl = 1440
+
+[profiles.passive-quick]
+mode = passive
+
+[profiles.passive-quick.data_sources.disabled]
+data_source = CommonCrawl
+
+[profiles.thorough-active]
+mode = active
+maximum_dns_queries = 250
+
+[profiles.thorough-active.resolvers]
+resolver = 9.9.9.9
+
+[profiles.thorough-active.bruteforce]
+enabled = true
+`
+
+func TestLoadProfileSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(path, []byte(profileTestConfig), 0644); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.LoadSettings(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.Active || c.Passive || c.BruteForcing || len(c.Resolvers) != 2 || c.MaxDNSQueries != 1000 {
+		t.Errorf("The settings were changed without selecting a profile")
+	}
+
+	c = NewConfig()
+	c.Profile = "Thorough-Active"
+	if err := c.LoadSettings(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !c.Active || !c.BruteForcing || c.MaxDNSQueries != 250 {
+		t.Errorf("The settings of the thorough-active profile were not applied")
+	}
+	if len(c.Resolvers) != 1 || c.Resolvers[0] != "9.9.9.9" {
+		t.Errorf("The profile resolvers did not replace the resolvers: %v", c.Resolvers)
+	}
+	if c.MinimumTTL != 1440 {
+		t.Errorf("The settings not provided by the profile were changed")
+	}
+
+	c = NewConfig()
+	c.Profile = "passive-quick"
+	if err := c.LoadSettings(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !c.Passive || len(c.SourceFilter.Sources) != 1 || c.SourceFilter.Sources[0] != "commoncrawl" {
+		t.Errorf("The settings of the passive-quick profile were not applied")
+	}
+
+	c = NewConfig()
+	c.Profile = "stealth"
+	if err := c.LoadSettings(path); err == nil {
+		t.Errorf("The missing profile was accepted")
+	}
+}
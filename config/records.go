@@ -0,0 +1,48 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caffix/stringset"
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadRecordSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("dns_records")
+	if err != nil {
+		return nil
+	}
+
+	var services []string
+	if sec.HasKey("srv_service") {
+		services = append(services, sec.Key("srv_service").ValueWithShadows()...)
+	}
+	if sec.HasKey("srv_services_file") {
+		for _, path := range sec.Key("srv_services_file").ValueWithShadows() {
+			list, err := GetListFromFile(path)
+			if err != nil {
+				return fmt.Errorf("Unable to load the file in the dns_records srv_services_file setting: %s: %v", path, err)
+			}
+			services = append(services, list...)
+		}
+	}
+
+	for _, service := range services {
+		service = strings.Trim(strings.ToLower(strings.TrimSpace(service)), ".")
+		if service == "" {
+			continue
+		}
+		if !strings.HasPrefix(service, "_") || !strings.Contains(service, "._") {
+			return fmt.Errorf("the SRV service %s must have the form _service._proto", service)
+		}
+		c.SRVServices = append(c.SRVServices, service)
+	}
+
+	c.SRVServices = stringset.Deduplicate(c.SRVServices)
+	return nil
+}
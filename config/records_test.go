@@ -0,0 +1,39 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/caffix/stringset"
+	"github.com/go-ini/ini"
+)
+
+func TestLoadRecordSettings(t *testing.T) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(`
+[dns_records]
+srv_service = _ldap._tcp
+srv_service = _SIP._udp.
+srv_service = _ldap._tcp
+`))
+	if err != nil {
+		t.Fatalf("Failed to load the test settings: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.loadRecordSettings(cfg); err != nil {
+		t.Fatalf("Failed to load the DNS record settings: %v", err)
+	}
+	services := stringset.New(c.SRVServices...)
+	defer services.Close()
+	if len(c.SRVServices) != 2 || !services.Has("_ldap._tcp") || !services.Has("_sip._udp") {
+		t.Errorf("The SRV services were not loaded correctly: %v", c.SRVServices)
+	}
+
+	bad, _ := ini.Load([]byte("[dns_records]\nsrv_service = ldap.tcp"))
+	if err := NewConfig().loadRecordSettings(bad); err == nil {
+		t.Errorf("Failed to reject the invalid SRV service")
+	}
+}
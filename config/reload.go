@@ -0,0 +1,153 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"net"
+	"reflect"
+	"sort"
+)
+
+// ReloadChanges describes the settings changed by Reload.
+type ReloadChanges struct {
+	// The root domain names and the ASNs added to the scope
+	Domains []string
+	ASNs    []int
+	// The data sources that received new credentials, HTTP timeouts or delays between the requests
+	Sources []string
+	// Set when the maximum number of DNS queries per second was changed
+	MaxDNSQueries bool
+}
+
+// Changed returns true when Reload modified the configuration.
+func (rc *ReloadChanges) Changed() bool {
+	return len(rc.Domains) > 0 || len(rc.ASNs) > 0 || len(rc.Sources) > 0 || rc.MaxDNSQueries
+}
+
+// Reload applies the settings of the newer configuration that can change while an enumeration is
+// running: the data source credentials and rate limits, the maximum number of DNS queries and the
+// additions to the scope. The entries removed from the scope are kept, since the findings already made depend on them.
+func (c *Config) Reload(newer *Config) *ReloadChanges {
+	changes := new(ReloadChanges)
+
+	current := make(map[string]struct{})
+	for _, d := range c.Domains() {
+		current[d] = struct{}{}
+	}
+	for _, d := range newer.Domains() {
+		if _, found := current[d]; !found {
+			c.AddDomain(d)
+			changes.Domains = append(changes.Domains, d)
+		}
+	}
+
+	for _, name := range newer.Blacklist {
+		if !c.Blacklisted(name) {
+			c.BlacklistSubdomain(name)
+		}
+	}
+	patterns := make(map[string]struct{})
+	for _, expr := range c.BlacklistPatterns {
+		patterns[expr] = struct{}{}
+	}
+	for _, expr := range newer.BlacklistPatterns {
+		if _, found := patterns[expr]; !found {
+			_ = c.BlacklistRegex(expr)
+		}
+	}
+	for _, tld := range newer.BlacklistTLDs {
+		_ = c.BlacklistTLD(tld)
+	}
+
+	c.Lock()
+	for _, addr := range newer.Addresses {
+		if !containsIP(c.Addresses, addr.String()) {
+			c.Addresses = append(c.Addresses, addr)
+		}
+	}
+	for _, cidr := range newer.CIDRs {
+		if !containsCIDR(c.CIDRs, cidr.String()) {
+			c.CIDRs = append(c.CIDRs, cidr)
+		}
+	}
+	for _, addr := range newer.ExcludedAddresses {
+		if !containsIP(c.ExcludedAddresses, addr.String()) {
+			c.ExcludedAddresses = append(c.ExcludedAddresses, addr)
+		}
+	}
+	for _, cidr := range newer.ExcludedCIDRs {
+		if !containsCIDR(c.ExcludedCIDRs, cidr.String()) {
+			c.ExcludedCIDRs = append(c.ExcludedCIDRs, cidr)
+		}
+	}
+	for _, asn := range newer.ASNs {
+		if !containsInt(c.ASNs, asn) {
+			c.ASNs = append(c.ASNs, asn)
+			changes.ASNs = append(changes.ASNs, asn)
+		}
+	}
+	if newer.MaxDNSQueries > 0 && newer.MaxDNSQueries != c.MaxDNSQueries {
+		c.MaxDNSQueries = newer.MaxDNSQueries
+		changes.MaxDNSQueries = true
+	}
+	c.Unlock()
+
+	for _, ndsc := range newer.DataSourceConfigs() {
+		dsc := c.GetDataSourceConfig(ndsc.Name)
+
+		ndsc.lock.Lock()
+		creds := make(map[string]*Credentials, len(ndsc.creds))
+		for name, cr := range ndsc.creds {
+			creds[name] = cr
+		}
+		ndsc.lock.Unlock()
+
+		dsc.lock.Lock()
+		var changed bool
+		if len(creds) > 0 && !reflect.DeepEqual(dsc.creds, creds) {
+			dsc.creds = creds
+			changed = true
+		}
+		if ndsc.Timeout != dsc.Timeout || ndsc.Delay != dsc.Delay {
+			dsc.Timeout = ndsc.Timeout
+			dsc.Delay = ndsc.Delay
+			changed = true
+		}
+		dsc.lock.Unlock()
+
+		if changed {
+			changes.Sources = append(changes.Sources, dsc.Name)
+		}
+	}
+	sort.Strings(changes.Sources)
+	return changes
+}
+
+func containsIP(addrs []net.IP, addr string) bool {
+	for _, a := range addrs {
+		if a.String() == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCIDR(cidrs []*net.IPNet, cidr string) bool {
+	for _, c := range cidrs {
+		if c.String() == cidr {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
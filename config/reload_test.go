@@ -0,0 +1,60 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"net"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	c := NewConfig()
+	c.AddDomain("owasp.org")
+	c.ASNs = []int{26808}
+	c.MaxDNSQueries = 1000
+	_ = c.GetDataSourceConfig("Shodan").AddCredentials(&Credentials{Name: "shodan", Key: "old"})
+
+	newer := NewConfig()
+	newer.AddDomains("owasp.org", "example.com")
+	newer.ASNs = []int{26808, 13374}
+	_, cidr, _ := net.ParseCIDR("192.168.1.0/24")
+	newer.CIDRs = []*net.IPNet{cidr}
+	newer.Blacklist = []string{"dev.example.com"}
+	newer.MaxDNSQueries = 500
+	_ = newer.GetDataSourceConfig("Shodan").AddCredentials(&Credentials{Name: "shodan", Key: "new"})
+	_ = newer.GetDataSourceConfig("AlienVault").AddCredentials(&Credentials{Name: "otx", Key: "key"})
+	newer.GetDataSourceConfig("Censys")
+
+	changes := c.Reload(newer)
+	if !changes.Changed() {
+		t.Fatalf("The changes were not reported")
+	}
+	if len(changes.Domains) != 1 || changes.Domains[0] != "example.com" || !c.IsDomainInScope("www.example.com") {
+		t.Errorf("The domain added to the scope was not applied: %v", changes.Domains)
+	}
+	if len(changes.ASNs) != 1 || changes.ASNs[0] != 13374 || len(c.ASNs) != 2 {
+		t.Errorf("The ASN added to the scope was not applied: %v", changes.ASNs)
+	}
+	if !c.IsAddressInScope("192.168.1.20") || !c.Blacklisted("www.dev.example.com") {
+		t.Errorf("The netblock and blacklisted name were not applied")
+	}
+	if !changes.MaxDNSQueries || c.MaxDNSQueries != 500 {
+		t.Errorf("The maximum number of DNS queries was not applied: %d", c.MaxDNSQueries)
+	}
+	if len(changes.Sources) != 2 || changes.Sources[0] != "alienvault" || changes.Sources[1] != "shodan" {
+		t.Errorf("The data sources with new credentials were not reported: %v", changes.Sources)
+	}
+	if creds := c.GetDataSourceConfig("Shodan").GetCredentials(); creds == nil || creds.Key != "new" {
+		t.Errorf("The new credentials were not applied: %v", creds)
+	}
+	// The settings removed from the configuration file are kept
+	if !c.IsDomainInScope("www.owasp.org") {
+		t.Errorf("The domain was removed from the scope")
+	}
+
+	if changes := c.Reload(newer); changes.Changed() {
+		t.Errorf("Reloading the same configuration reported changes: %v", changes)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"strconv"
 	"strings"
 
@@ -24,8 +25,16 @@ const DefaultQueriesPerPublicResolver = 5
 // DefaultQueriesPerBaselineResolver is the number of queries sent to each trusted DNS resolver per second.
 const DefaultQueriesPerBaselineResolver = 10
 
+// DefaultQueriesPerAuthoritativeServer is the number of queries sent to each authoritative name server per second.
+const DefaultQueriesPerAuthoritativeServer = 5
+
 const minResolverReliability = 0.85
 
+const (
+	minEDNS0BufferSize = 512
+	maxEDNS0BufferSize = 65535
+)
+
 // DefaultBaselineResolvers is a list of trusted public DNS resolvers.
 var DefaultBaselineResolvers = []string{
 	"8.8.8.8",        // Google
@@ -47,6 +56,20 @@ var DefaultBaselineResolvers = []string{
 	"76.76.2.0",      // ControlD
 }
 
+// DefaultBaselineResolversIPv6 is a list of trusted public DNS resolvers reachable over IPv6.
+var DefaultBaselineResolversIPv6 = []string{
+	"2001:4860:4860::8888",       // Google
+	"2606:4700:4700::1111",       // Cloudflare
+	"2620:fe::fe",                // Quad9
+	"2620:119:35::35",            // Cisco OpenDNS
+	"2001:1608:10:25::1c04:b12f", // DNS.WATCH
+	"2a0d:2a00:1::2",             // CleanBrowsing
+	"2a02:6b8::feed:ff",          // Yandex.DNS
+	"2a10:50c0::ad1:ff",          // AdGuard
+	"2001:470:20::2",             // Hurricane Electric
+	"2606:1a40::",                // ControlD
+}
+
 // PublicResolvers includes the addresses of public resolvers obtained dynamically.
 var PublicResolvers []string
 
@@ -85,7 +108,7 @@ func GetPublicDNSResolvers() error {
 	}
 loop:
 	for _, addr := range resolvers {
-		for _, br := range DefaultBaselineResolvers {
+		for _, br := range append(DefaultBaselineResolvers, DefaultBaselineResolversIPv6...) {
 			if addr == br {
 				continue loop
 			}
@@ -125,8 +148,8 @@ func (c *Config) AddResolver(resolver string) {
 
 // SetTrustedResolvers assigns the trusted resolver names provided in the parameter to the list in the configuration.
 func (c *Config) SetTrustedResolvers(resolvers ...string) {
-	c.Resolvers = []string{}
-	c.AddResolvers(resolvers...)
+	c.TrustedResolvers = []string{}
+	c.AddTrustedResolvers(resolvers...)
 }
 
 // AddTrustedResolvers appends the trusted resolver names provided in the parameter to the list in the configuration.
@@ -162,10 +185,141 @@ func (c *Config) loadResolverSettings(cfg *ini.File) error {
 		return nil
 	}
 
+	if err := c.loadEDNS0Settings(sec); err != nil {
+		return err
+	}
+
+	c.Authoritative = sec.Key("authoritative").MustBool(c.Authoritative)
+	if sec.HasKey("authoritative_qps") {
+		if qps := sec.Key("authoritative_qps").MustInt(0); qps > 0 {
+			c.AuthoritativeQPS = qps
+		} else {
+			return errors.New("the authoritative_qps setting must be greater than zero")
+		}
+	}
+
+	c.DNSCache = sec.Key("dns_cache").MustBool(c.DNSCache)
+	c.PreferIPv6 = sec.Key("prefer_ipv6").MustBool(c.PreferIPv6)
+
+	if err := c.loadResolverPoolSettings(sec); err != nil {
+		return err
+	}
+
 	c.Resolvers = stringset.Deduplicate(sec.Key("resolver").ValueWithShadows())
-	if len(c.Resolvers) == 0 {
+	if len(c.Resolvers) == 0 && !hasAnyKey(sec, "authoritative", "authoritative_qps", "dns_cache",
+		"prefer_ipv6", "edns0_buffer_size", "edns0_cookies", "edns0_client_subnet", "trusted_resolver",
+		"untrusted_qps", "trusted_qps", "trusted_confirmations") {
 		return errors.New("no resolver keys were found in the resolvers section")
 	}
 
 	return nil
 }
+
+// The untrusted resolvers provided by the resolver keys send the high volume of discovery queries,
+// and the trusted resolvers verify the answers before the names are reported.
+func (c *Config) loadResolverPoolSettings(sec *ini.Section) error {
+	if trusted := sec.Key("trusted_resolver").ValueWithShadows(); len(trusted) > 0 && trusted[0] != "" {
+		c.SetTrustedResolvers(trusted...)
+	}
+
+	for key, qps := range map[string]*int{
+		"untrusted_qps": &c.ResolversQPS,
+		"trusted_qps":   &c.TrustedQPS,
+	} {
+		if !sec.HasKey(key) {
+			continue
+		}
+		if n := sec.Key(key).MustInt(0); n > 0 {
+			*qps = n
+		} else {
+			return fmt.Errorf("the %s setting must be greater than zero", key)
+		}
+	}
+
+	if sec.HasKey("trusted_confirmations") {
+		if n := sec.Key("trusted_confirmations").MustInt(0); n > 0 {
+			c.TrustedConfirmations = n
+		} else {
+			return errors.New("the trusted_confirmations setting must be greater than zero")
+		}
+	}
+	return nil
+}
+
+// Each key in the resolvers.domains section is a zone, and the values are the resolvers for that zone.
+func (c *Config) loadDomainResolverSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("resolvers.domains")
+	if err != nil {
+		return nil
+	}
+
+	for _, key := range sec.Keys() {
+		zone := strings.ToLower(strings.Trim(strings.TrimSpace(key.Name()), "."))
+		if zone == "" {
+			continue
+		}
+
+		var addrs []string
+		for _, value := range key.ValueWithShadows() {
+			for _, addr := range strings.Split(value, ",") {
+				if addr = strings.TrimSpace(addr); addr != "" {
+					addrs = append(addrs, addr)
+				}
+			}
+		}
+		if len(addrs) == 0 {
+			return fmt.Errorf("no resolvers were provided for the %s zone in the resolvers.domains section", zone)
+		}
+
+		c.AddDomainResolvers(zone, addrs...)
+	}
+	return nil
+}
+
+// AddDomainResolvers appends the resolvers that will answer the queries for names within the zone,
+// instead of the untrusted and trusted resolvers.
+func (c *Config) AddDomainResolvers(zone string, resolvers ...string) {
+	c.Lock()
+	defer c.Unlock()
+
+	zone = strings.ToLower(strings.Trim(strings.TrimSpace(zone), "."))
+	if zone == "" || len(resolvers) == 0 {
+		return
+	}
+
+	if c.DomainResolvers == nil {
+		c.DomainResolvers = make(map[string][]string)
+	}
+	c.DomainResolvers[zone] = stringset.Deduplicate(append(c.DomainResolvers[zone], resolvers...))
+}
+
+func hasAnyKey(sec *ini.Section, keys ...string) bool {
+	for _, key := range keys {
+		if sec.HasKey(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) loadEDNS0Settings(sec *ini.Section) error {
+	if sec.HasKey("edns0_buffer_size") {
+		size := sec.Key("edns0_buffer_size").MustInt(0)
+		if size < minEDNS0BufferSize || size > maxEDNS0BufferSize {
+			return fmt.Errorf("the EDNS0 buffer size must be between %d and %d", minEDNS0BufferSize, maxEDNS0BufferSize)
+		}
+		c.EDNS0BufferSize = size
+	}
+
+	c.EDNS0Cookies = sec.Key("edns0_cookies").MustBool(false)
+
+	if subnet := strings.TrimSpace(sec.Key("edns0_client_subnet").String()); subnet != "" {
+		if subnet != "none" {
+			if _, _, err := net.ParseCIDR(subnet); err != nil {
+				return fmt.Errorf("the EDNS0 client subnet %s is not valid: %v", subnet, err)
+			}
+		}
+		c.EDNS0ClientSubnet = subnet
+	}
+	return nil
+}
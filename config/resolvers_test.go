@@ -7,6 +7,8 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+
+	"github.com/go-ini/ini"
 )
 
 func TestConfigSetResolvers(t *testing.T) {
@@ -42,3 +44,107 @@ func TestConfigSetResolvers(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadEDNS0Settings(t *testing.T) {
+	cfg, err := ini.Load([]byte(`
+[resolvers]
+edns0_buffer_size = 1232
+edns0_cookies = true
+edns0_client_subnet = 192.0.2.0/24
+`))
+	if err != nil {
+		t.Fatalf("Failed to load the test settings: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.loadResolverSettings(cfg); err != nil {
+		t.Fatalf("Failed to load the EDNS0 settings: %v", err)
+	}
+	if c.EDNS0BufferSize != 1232 || !c.EDNS0Cookies || c.EDNS0ClientSubnet != "192.0.2.0/24" {
+		t.Errorf("The EDNS0 settings were not loaded correctly")
+	}
+
+	for _, bad := range []string{"edns0_buffer_size = 100", "edns0_client_subnet = 192.0.2.0"} {
+		cfg, _ := ini.Load([]byte("[resolvers]\n" + bad))
+
+		if err := NewConfig().loadResolverSettings(cfg); err == nil {
+			t.Errorf("Failed to reject the invalid setting: %s", bad)
+		}
+	}
+}
+
+func TestLoadResolverPoolSettings(t *testing.T) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(`
+[resolvers]
+resolver = 8.8.8.8
+resolver = 1.1.1.1
+trusted_resolver = 9.9.9.9
+trusted_resolver = 208.67.222.222
+untrusted_qps = 15
+trusted_qps = 30
+trusted_confirmations = 2
+`))
+	if err != nil {
+		t.Fatalf("Failed to load the test settings: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.loadResolverSettings(cfg); err != nil {
+		t.Fatalf("Failed to load the resolver pool settings: %v", err)
+	}
+
+	sort.Strings(c.Resolvers)
+	sort.Strings(c.TrustedResolvers)
+	if !reflect.DeepEqual(c.Resolvers, []string{"1.1.1.1", "8.8.8.8"}) ||
+		!reflect.DeepEqual(c.TrustedResolvers, []string{"208.67.222.222", "9.9.9.9"}) {
+		t.Errorf("The resolver pools were not loaded: %v and %v", c.Resolvers, c.TrustedResolvers)
+	}
+	if c.ResolversQPS != 15 || c.TrustedQPS != 30 || c.TrustedConfirmations != 2 {
+		t.Errorf("The resolver pool limits and verification policy were not loaded")
+	}
+
+	if c := NewConfig(); c.TrustedConfirmations != 1 {
+		t.Errorf("The default verification policy requires %d confirmations", c.TrustedConfirmations)
+	}
+	for _, bad := range []string{"trusted_confirmations = 0", "trusted_qps = -5", "untrusted_qps = many"} {
+		cfg, _ := ini.Load([]byte("[resolvers]\n" + bad))
+
+		if err := NewConfig().loadResolverSettings(cfg); err == nil {
+			t.Errorf("Failed to reject the invalid setting: %s", bad)
+		}
+	}
+}
+
+func TestLoadDomainResolverSettings(t *testing.T) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(`
+[resolvers.domains]
+corp.example.com = 10.0.0.53
+corp.example.com = 10.0.0.54, 10.0.0.53
+Internal.Example.org. = 192.168.1.53:5353
+`))
+	if err != nil {
+		t.Fatalf("Failed to load the test settings: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.loadDomainResolverSettings(cfg); err != nil {
+		t.Fatalf("Failed to load the domain resolver settings: %v", err)
+	}
+
+	expected := map[string][]string{
+		"corp.example.com":     {"10.0.0.53", "10.0.0.54"},
+		"internal.example.org": {"192.168.1.53:5353"},
+	}
+	for zone, addrs := range c.DomainResolvers {
+		sort.Strings(addrs)
+		c.DomainResolvers[zone] = addrs
+	}
+	if !reflect.DeepEqual(c.DomainResolvers, expected) {
+		t.Errorf("Got %v, expected %v", c.DomainResolvers, expected)
+	}
+
+	bad, _ := ini.Load([]byte("[resolvers.domains]\ncorp.example.com = "))
+	if err := NewConfig().loadDomainResolverSettings(bad); err == nil {
+		t.Errorf("Failed to reject the zone without resolvers")
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Positions in the rules are provided as 0-9 followed by A-Z, as in hashcat.
+const rulePositions = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Labels longer than this are not valid DNS labels.
+const maxLabelLength = 63
+
+// AlterationRule is a "hashcat-style" rule that transforms the labels of resolved names into new guesses.
+type AlterationRule struct {
+	raw   string
+	funcs []ruleFunc
+}
+
+type ruleFunc func(word []byte) []byte
+
+// String returns the rule as it was provided.
+func (r *AlterationRule) String() string {
+	return r.raw
+}
+
+// Apply performs the rule on the label and returns the new label, or false when the rule
+// did not change the label or produced a label that is not valid within a DNS name.
+func (r *AlterationRule) Apply(label string) (string, bool) {
+	if label == "" {
+		return "", false
+	}
+
+	word := []byte(label)
+	for _, f := range r.funcs {
+		if word = f(word); len(word) == 0 || len(word) > 4*maxLabelLength {
+			return "", false
+		}
+	}
+
+	result := strings.ToLower(string(word))
+	if result == strings.ToLower(label) || !validLabel(result) {
+		return "", false
+	}
+	return result, true
+}
+
+// ParseAlterationRules parses each of the "hashcat-style" rules, ignoring the empty lines and comments.
+func ParseAlterationRules(lines []string) ([]*AlterationRule, error) {
+	var rules []*AlterationRule
+
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		r, err := ParseAlterationRule(line)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// ParseAlterationRule parses a rule written in the hashcat rule syntax. The supported functions are:
+// : l u c C t TN r d f { } $X ^X [ ] DN 'N xNM ONM iNX oNX sXY @X zN ZN q k K *NM
+func ParseAlterationRule(rule string) (*AlterationRule, error) {
+	r := &AlterationRule{raw: rule}
+
+	for i := 0; i < len(rule); {
+		op := rule[i]
+		if op == ' ' || op == '\t' {
+			i++
+			continue
+		}
+
+		nargs, ok := ruleArgs[op]
+		if !ok {
+			return nil, fmt.Errorf("Unsupported function '%c' in the alteration rule: %s", op, rule)
+		}
+		if i+1+nargs > len(rule) {
+			return nil, fmt.Errorf("Missing arguments for the function '%c' in the alteration rule: %s", op, rule)
+		}
+
+		args := rule[i+1 : i+1+nargs]
+		f, err := newRuleFunc(op, args)
+		if err != nil {
+			return nil, fmt.Errorf("%v in the alteration rule: %s", err, rule)
+		}
+
+		r.funcs = append(r.funcs, f)
+		i += 1 + nargs
+	}
+
+	if len(r.funcs) == 0 {
+		return nil, fmt.Errorf("The alteration rule is empty: %s", rule)
+	}
+	return r, nil
+}
+
+// The number of argument characters following each of the supported functions.
+var ruleArgs = map[byte]int{
+	':': 0, 'l': 0, 'u': 0, 'c': 0, 'C': 0, 't': 0, 'r': 0, 'd': 0, 'f': 0,
+	'{': 0, '}': 0, '[': 0, ']': 0, 'q': 0, 'k': 0, 'K': 0,
+	'T': 1, '$': 1, '^': 1, 'D': 1, '\'': 1, '@': 1, 'z': 1, 'Z': 1,
+	'x': 2, 'O': 2, 'i': 2, 'o': 2, 's': 2, '*': 2,
+}
+
+func newRuleFunc(op byte, args string) (ruleFunc, error) {
+	// The functions operating on positions validate them before building the closure
+	var pos []int
+	switch op {
+	case 'T', 'D', '\'', 'z', 'Z', 'x', 'O', 'i', 'o', '*':
+		n := 1
+		if op == 'x' || op == 'O' || op == '*' {
+			n = 2
+		}
+
+		for _, ch := range args[:n] {
+			p := strings.IndexRune(rulePositions, ch)
+			if p == -1 {
+				return nil, fmt.Errorf("Invalid position '%c'", ch)
+			}
+			pos = append(pos, p)
+		}
+	}
+
+	switch op {
+	case ':':
+		return func(w []byte) []byte { return w }, nil
+	case 'l':
+		return func(w []byte) []byte { return []byte(strings.ToLower(string(w))) }, nil
+	case 'u':
+		return func(w []byte) []byte { return []byte(strings.ToUpper(string(w))) }, nil
+	case 'c':
+		return func(w []byte) []byte {
+			w = []byte(strings.ToLower(string(w)))
+			w[0] = toUpper(w[0])
+			return w
+		}, nil
+	case 'C':
+		return func(w []byte) []byte {
+			w = []byte(strings.ToUpper(string(w)))
+			w[0] = toLower(w[0])
+			return w
+		}, nil
+	case 't':
+		return func(w []byte) []byte {
+			out := make([]byte, len(w))
+			for i, ch := range w {
+				out[i] = toggle(ch)
+			}
+			return out
+		}, nil
+	case 'T':
+		return func(w []byte) []byte {
+			if pos[0] >= len(w) {
+				return w
+			}
+			out := copyWord(w)
+			out[pos[0]] = toggle(out[pos[0]])
+			return out
+		}, nil
+	case 'r':
+		return func(w []byte) []byte {
+			out := make([]byte, len(w))
+			for i, ch := range w {
+				out[len(w)-1-i] = ch
+			}
+			return out
+		}, nil
+	case 'd':
+		return func(w []byte) []byte { return append(copyWord(w), w...) }, nil
+	case 'f':
+		return func(w []byte) []byte {
+			out := copyWord(w)
+			for i := len(w) - 1; i >= 0; i-- {
+				out = append(out, w[i])
+			}
+			return out
+		}, nil
+	case '{':
+		return func(w []byte) []byte { return append(copyWord(w[1:]), w[0]) }, nil
+	case '}':
+		return func(w []byte) []byte { return append([]byte{w[len(w)-1]}, w[:len(w)-1]...) }, nil
+	case '$':
+		return func(w []byte) []byte { return append(copyWord(w), args[0]) }, nil
+	case '^':
+		return func(w []byte) []byte { return append([]byte{args[0]}, w...) }, nil
+	case '[':
+		return func(w []byte) []byte { return copyWord(w[1:]) }, nil
+	case ']':
+		return func(w []byte) []byte { return copyWord(w[:len(w)-1]) }, nil
+	case 'D':
+		return func(w []byte) []byte {
+			if pos[0] >= len(w) {
+				return w
+			}
+			return append(copyWord(w[:pos[0]]), w[pos[0]+1:]...)
+		}, nil
+	case '\'':
+		return func(w []byte) []byte {
+			if pos[0] >= len(w) {
+				return w
+			}
+			return copyWord(w[:pos[0]])
+		}, nil
+	case 'x':
+		return func(w []byte) []byte {
+			if pos[0] >= len(w) || pos[0]+pos[1] > len(w) {
+				return w
+			}
+			return copyWord(w[pos[0] : pos[0]+pos[1]])
+		}, nil
+	case 'O':
+		return func(w []byte) []byte {
+			if pos[0] >= len(w) || pos[0]+pos[1] > len(w) {
+				return w
+			}
+			return append(copyWord(w[:pos[0]]), w[pos[0]+pos[1]:]...)
+		}, nil
+	case 'i':
+		return func(w []byte) []byte {
+			if pos[0] > len(w) {
+				return w
+			}
+			out := append(copyWord(w[:pos[0]]), args[1])
+			return append(out, w[pos[0]:]...)
+		}, nil
+	case 'o':
+		return func(w []byte) []byte {
+			if pos[0] >= len(w) {
+				return w
+			}
+			out := copyWord(w)
+			out[pos[0]] = args[1]
+			return out
+		}, nil
+	case 's':
+		return func(w []byte) []byte {
+			return []byte(strings.ReplaceAll(string(w), args[:1], args[1:]))
+		}, nil
+	case '@':
+		return func(w []byte) []byte {
+			return []byte(strings.ReplaceAll(string(w), args, ""))
+		}, nil
+	case 'z':
+		return func(w []byte) []byte {
+			out := make([]byte, 0, len(w)+pos[0])
+			for i := 0; i < pos[0]; i++ {
+				out = append(out, w[0])
+			}
+			return append(out, w...)
+		}, nil
+	case 'Z':
+		return func(w []byte) []byte {
+			out := copyWord(w)
+			for i := 0; i < pos[0]; i++ {
+				out = append(out, w[len(w)-1])
+			}
+			return out
+		}, nil
+	case 'q':
+		return func(w []byte) []byte {
+			out := make([]byte, 0, 2*len(w))
+			for _, ch := range w {
+				out = append(out, ch, ch)
+			}
+			return out
+		}, nil
+	case 'k':
+		return func(w []byte) []byte {
+			out := copyWord(w)
+			if len(out) > 1 {
+				out[0], out[1] = out[1], out[0]
+			}
+			return out
+		}, nil
+	case 'K':
+		return func(w []byte) []byte {
+			out := copyWord(w)
+			if n := len(out); n > 1 {
+				out[n-1], out[n-2] = out[n-2], out[n-1]
+			}
+			return out
+		}, nil
+	case '*':
+		return func(w []byte) []byte {
+			out := copyWord(w)
+			if pos[0] < len(out) && pos[1] < len(out) {
+				out[pos[0]], out[pos[1]] = out[pos[1]], out[pos[0]]
+			}
+			return out
+		}, nil
+	}
+	return nil, fmt.Errorf("Unsupported function '%c'", op)
+}
+
+func copyWord(w []byte) []byte {
+	return append([]byte(nil), w...)
+}
+
+func toUpper(ch byte) byte {
+	if ch >= 'a' && ch <= 'z' {
+		return ch - ('a' - 'A')
+	}
+	return ch
+}
+
+func toLower(ch byte) byte {
+	if ch >= 'A' && ch <= 'Z' {
+		return ch + ('a' - 'A')
+	}
+	return ch
+}
+
+func toggle(ch byte) byte {
+	if ch >= 'a' && ch <= 'z' {
+		return toUpper(ch)
+	}
+	return toLower(ch)
+}
+
+// Labels may only contain letters, digits, hyphens and underscores, and cannot begin or end with a hyphen.
+func validLabel(label string) bool {
+	if label == "" || len(label) > maxLabelLength || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for _, ch := range label {
+		if !(ch >= 'a' && ch <= 'z') && !(ch >= '0' && ch <= '9') && ch != '-' && ch != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+)
+
+func TestAlterationRuleApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		label    string
+		expected string
+		ok       bool
+	}{
+		{"Append", "$-$d$e$v", "api", "api-dev", true},
+		{"Prepend", "^-^v^e^d", "api", "dev-api", true},
+		{"Substitute", "so0", "portal", "p0rtal", true},
+		{"Truncate", "'3", "portal", "por", true},
+		{"Truncate beyond the label", "'9", "api", "", false},
+		{"Delete first and last", "[ ]", "xapix", "api", true},
+		{"Reverse", "r", "abc", "cba", true},
+		{"Duplicate", "d", "ab", "abab", true},
+		{"Reflect", "f", "ab", "abba", true},
+		{"Rotate", "{", "abc", "bca", true},
+		{"Insert", "i1-", "ab", "a-b", true},
+		{"Overwrite", "o0x", "abc", "xbc", true},
+		{"Delete at position", "D1", "abc", "ac", true},
+		{"Extract range", "x13", "mail01", "ail", true},
+		{"Omit range", "O12", "mail01", "ml01", true},
+		{"Purge", "@-", "dev-api", "devapi", true},
+		{"Duplicate the first and last", "z1 Z2", "ab", "aabbb", true},
+		{"Duplicate every character", "q", "ab", "aabb", true},
+		{"Swap", "*02", "abc", "cba", true},
+		{"Toggle does not change the name", "t", "api", "", false},
+		{"Leading hyphen", "^-", "api", "", false},
+		{"Invalid character", "$.", "api", "", false},
+		{"Label too long", "d d d d d", "abc", "", false},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseAlterationRule(tt.rule)
+		if err != nil {
+			t.Errorf("%s: failed to parse the rule %s: %v", tt.name, tt.rule, err)
+			continue
+		}
+
+		label, ok := r.Apply(tt.label)
+		if ok != tt.ok || label != tt.expected {
+			t.Errorf("%s: was expecting %s (%t), got %s (%t)", tt.name, tt.expected, tt.ok, label, ok)
+		}
+	}
+}
+
+func TestParseAlterationRules(t *testing.T) {
+	rules, err := ParseAlterationRules([]string{"# comment", "", "$1", "  sab  "})
+	if err != nil || len(rules) != 2 {
+		t.Errorf("Failed to parse the valid rules: %v", err)
+	}
+
+	for _, bad := range []string{"$", "s1", "Y", "T!", "x1"} {
+		if _, err := ParseAlterationRules([]string{bad}); err == nil {
+			t.Errorf("The invalid rule %s was accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ini/ini"
+)
+
+// The number of years searched for the next time matching a cron expression.
+const cronSearchYears = 5
+
+// The shorthands accepted in place of the cron expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule contains the settings of the recurring enumerations executed by the daemon.
+type Schedule struct {
+	Name string
+	// The cron expression selecting the start times of the enumerations
+	Cron    string   `ini:"cron"`
+	Domains []string `ini:"domains" delim:","`
+	Active  bool     `ini:"active"`
+	Brute   bool     `ini:"brute"`
+	Alts    bool     `ini:"alterations"`
+	Passive bool     `ini:"passive"`
+	// The number of minutes each enumeration is allowed to run, where zero does not limit them
+	Timeout int `ini:"timeout"`
+	// Posts the changes found by each enumeration to the chat webhooks
+	Notify bool `ini:"notify"`
+	// The named profile of the configuration file used by the enumerations
+	Profile string    `ini:"profile"`
+	Spec    *CronSpec `ini:"-"`
+}
+
+func (c *Config) loadScheduleSettings(cfg *ini.File) error {
+	for _, child := range cfg.Section("schedules").ChildSections() {
+		s := &Schedule{Notify: true}
+		name := strings.TrimPrefix(child.Name(), "schedules.")
+
+		if err := child.MapTo(s); err != nil {
+			return err
+		}
+
+		s.Name = name
+		spec, err := ParseCron(s.Cron)
+		if err != nil {
+			return fmt.Errorf("The %s schedule cron expression is not valid: %v", name, err)
+		}
+		s.Spec = spec
+
+		var domains []string
+		for _, d := range s.Domains {
+			if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+				domains = append(domains, d)
+			}
+		}
+		if len(domains) == 0 {
+			return fmt.Errorf("The %s schedule requires the domains setting", name)
+		}
+		if s.Passive && (s.Active || s.Brute || s.Alts) {
+			return fmt.Errorf("The %s schedule cannot be passive while using active, brute or alterations", name)
+		}
+		if s.Timeout < 0 {
+			return fmt.Errorf("The %s schedule requires a timeout of zero or more", name)
+		}
+		if s.Profile = strings.ToLower(strings.TrimSpace(s.Profile)); s.Profile != "" && !containsString(Profiles(cfg), s.Profile) {
+			return fmt.Errorf("The %s schedule selects the %s profile, which was not found", name, s.Profile)
+		}
+
+		s.Domains = domains
+		c.Schedules = append(c.Schedules, s)
+	}
+	return nil
+}
+
+// CronSpec is a parsed cron expression with the minute, hour, day of month, month and day of week fields.
+type CronSpec struct {
+	minutes, hours, days, months, weekdays map[int]struct{}
+	// The day of the month and day of the week fields restricting the days
+	anyDay, anyWeekday bool
+}
+
+// ParseCron parses the five fields of the cron expression, or one of the shorthands such as @daily.
+func ParseCron(expr string) (*CronSpec, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, found := cronMacros[strings.ToLower(expr)]; found {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("the expression %q must provide five fields", expr)
+	}
+
+	spec := &CronSpec{
+		anyDay:     strings.HasPrefix(fields[2], "*"),
+		anyWeekday: strings.HasPrefix(fields[4], "*"),
+	}
+	for _, f := range []struct {
+		set      *map[int]struct{}
+		field    string
+		min, max int
+	}{
+		{&spec.minutes, fields[0], 0, 59},
+		{&spec.hours, fields[1], 0, 23},
+		{&spec.days, fields[2], 1, 31},
+		{&spec.months, fields[3], 1, 12},
+		{&spec.weekdays, fields[4], 0, 7},
+	} {
+		set, err := parseCronField(f.field, f.min, f.max)
+		if err != nil {
+			return nil, err
+		}
+		*f.set = set
+	}
+	// Sunday can be provided as zero or seven
+	if _, found := spec.weekdays[7]; found {
+		spec.weekdays[0] = struct{}{}
+	}
+	return spec, nil
+}
+
+// Parses the comma separated values, ranges and steps of the cron field.
+func parseCronField(field string, min, max int) (map[int]struct{}, error) {
+	set := make(map[int]struct{})
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("the step of %q is not valid", part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		low, high := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			l, err1 := strconv.Atoi(bounds[0])
+			h, err2 := strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("the range %q is not valid", part)
+			}
+			low, high = l, h
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("the value %q is not valid", part)
+			}
+			low, high = n, n
+		}
+		if low < min || high > max || low > high {
+			return nil, fmt.Errorf("the values of %q must be between %d and %d", part, min, max)
+		}
+
+		for i := low; i <= high; i += step {
+			set[i] = struct{}{}
+		}
+	}
+	return set, nil
+}
+
+// Next returns the first time after t matching the cron expression,
+// or the zero time when no matching time is found within the following years.
+func (s *CronSpec) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(cronSearchYears, 0, 0)
+
+	for t.Before(end) {
+		if !s.match(s.months, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.match(s.hours, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.match(s.minutes, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSpec) match(set map[int]struct{}, v int) bool {
+	_, found := set[v]
+	return found
+}
+
+// When both the day of the month and day of the week are restricted, the day can match either of them.
+func (s *CronSpec) matchDay(t time.Time) bool {
+	day := s.match(s.days, t.Day())
+	weekday := s.match(s.weekdays, int(t.Weekday()))
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
@@ -0,0 +1,90 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadScheduleSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+		[schedules.corp]
+		cron = 0 3 * * 1-5
+		domains = Example.com, example.org
+		active = true
+		timeout = 120
+
+		[schedules.brands]
+		cron = @daily
+		domains = example.net
+		notify = false
+		`),
+	)
+	if err := c.loadScheduleSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(c.Schedules) != 2 {
+		t.Fatalf("%d schedules were loaded, expected 2", len(c.Schedules))
+	}
+	if s := c.Schedules[0]; s.Name != "corp" || len(s.Domains) != 2 || s.Domains[0] != "example.com" ||
+		!s.Active || s.Timeout != 120 || !s.Notify || s.Spec == nil {
+		t.Errorf("The corp schedule was not loaded: %v", s)
+	}
+	if s := c.Schedules[1]; s.Name != "brands" || s.Notify {
+		t.Errorf("The brands schedule was not loaded: %v", s)
+	}
+
+	for _, bad := range []string{
+		"[schedules.a]\ncron = 0 3 * *\ndomains = example.com",
+		"[schedules.a]\ncron = 0 3 * * *",
+		"[schedules.a]\ncron = 0 3 * * *\ndomains = example.com\npassive = true\nbrute = true",
+		"[schedules.a]\ncron = 0 3 * * *\ndomains = example.com\nprofile = stealth",
+	} {
+		cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(bad))
+		if err := NewConfig().loadScheduleSettings(cfg); err == nil {
+			t.Errorf("The settings were accepted: %s", bad)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Wednesday, January 5th 2022
+	start := time.Date(2022, time.January, 5, 10, 30, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		expr string
+		next time.Time
+	}{
+		{"*/15 * * * *", time.Date(2022, time.January, 5, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2022, time.January, 6, 3, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2022, time.January, 6, 10, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2022, time.January, 9, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2022, time.January, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 5", time.Date(2022, time.January, 7, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2022, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 29 2 *", time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	} {
+		spec, err := ParseCron(test.expr)
+		if err != nil {
+			t.Errorf("Failed to parse %s: %v", test.expr, err)
+			continue
+		}
+		if next := spec.Next(start); !next.Equal(test.next) {
+			t.Errorf("The next time of %s was %v, expected %v", test.expr, next, test.next)
+		}
+	}
+
+	for _, bad := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseCron(bad); err == nil {
+			t.Errorf("The expression %s was accepted", bad)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
@@ -14,6 +15,7 @@ import (
 	"github.com/OWASP/Amass/v3/net/dns"
 	"github.com/caffix/stringset"
 	"github.com/go-ini/ini"
+	"golang.org/x/net/publicsuffix"
 )
 
 // DomainRegex returns the Regexp object for the domain name identified by the parameter.
@@ -120,7 +122,7 @@ func hasPathSuffix(path, suffix string) bool {
 // no network scope has been set.
 func (c *Config) IsAddressInScope(addr string) bool {
 	ip := net.ParseIP(addr)
-	if ip == nil {
+	if ip == nil || c.IsAddressExcluded(addr) {
 		return false
 	}
 
@@ -142,6 +144,26 @@ func (c *Config) IsAddressInScope(addr string) bool {
 	return false
 }
 
+// IsAddressExcluded returns true if the addr parameter matches an address or netblock excluded from the scope.
+func (c *Config) IsAddressExcluded(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, a := range c.ExcludedAddresses {
+		if a.Equal(ip) {
+			return true
+		}
+	}
+	for _, cidr := range c.ExcludedCIDRs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // BlacklistSubdomain adds a subdomain name to the config blacklist.
 func (c *Config) BlacklistSubdomain(name string) {
 	c.blacklistLock.Lock()
@@ -154,18 +176,82 @@ func (c *Config) BlacklistSubdomain(name string) {
 	c.Blacklist = set.Slice()
 }
 
-// Blacklisted returns true is the name in the parameter ends with a subdomain name in the config blacklist.
+// BlacklistRegex adds a regular expression matching the names that will not be investigated.
+func (c *Config) BlacklistRegex(expr string) error {
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return err
+	}
+
+	c.blacklistLock.Lock()
+	defer c.blacklistLock.Unlock()
+
+	c.BlacklistPatterns = append(c.BlacklistPatterns, expr)
+	c.blacklistRegexps = append(c.blacklistRegexps, re)
+	return nil
+}
+
+// BlacklistGlob adds a glob pattern, such as *.dev.example.com, matching the names that will not
+// be investigated. The '*' wildcard matches any characters, including the dots, and '?' matches one.
+func (c *Config) BlacklistGlob(pattern string) error {
+	var b strings.Builder
+
+	b.WriteString("^")
+	for _, ch := range strings.TrimSpace(pattern) {
+		switch ch {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	b.WriteString("$")
+	return c.BlacklistRegex(b.String())
+}
+
+// BlacklistTLD adds a top-level domain, such as cn, or another public suffix, such as cloudfront.net,
+// whose names will not be investigated, including the names discovered through the CNAME records.
+func (c *Config) BlacklistTLD(tld string) error {
+	tld = strings.Trim(strings.ToLower(strings.TrimSpace(tld)), ".")
+	if tld == "" {
+		return errors.New("the top-level domain is empty")
+	}
+	if suffix, _ := publicsuffix.PublicSuffix(tld); suffix != tld {
+		return fmt.Errorf("%s is not a top-level domain or public suffix, but the glob *.%s can blacklist its names", tld, tld)
+	}
+
+	c.blacklistLock.Lock()
+	defer c.blacklistLock.Unlock()
+
+	c.BlacklistTLDs = stringset.Deduplicate(append(c.BlacklistTLDs, tld))
+	return nil
+}
+
+// Blacklisted returns true is the name in the parameter ends with a subdomain name in the config blacklist
+// or a blacklisted top-level domain, or matches one of the blacklisted patterns.
 func (c *Config) Blacklisted(name string) bool {
 	c.blacklistLock.Lock()
 	defer c.blacklistLock.Unlock()
 
-	n := strings.ToLower(strings.TrimSpace(name))
+	n := strings.Trim(strings.ToLower(strings.TrimSpace(name)), ".")
 
 	for _, bl := range c.Blacklist {
 		if hasPathSuffix(n, bl) {
 			return true
 		}
 	}
+	for _, tld := range c.BlacklistTLDs {
+		if hasPathSuffix(n, tld) {
+			return true
+		}
+	}
+	for _, re := range c.blacklistRegexps {
+		if re.MatchString(n) {
+			return true
+		}
+	}
 
 	return false
 }
@@ -217,9 +303,31 @@ func (c *Config) loadScopeSettings(cfg *ini.File) error {
 		}
 	}
 
-	// Load up all the blacklisted subdomain names
+	// Load up all the blacklisted subdomain names and patterns
 	if blacklisted, err := cfg.GetSection("scope.blacklisted"); err == nil {
 		c.Blacklist = stringset.Deduplicate(blacklisted.Key("subdomain").ValueWithShadows())
+
+		if blacklisted.HasKey("regex") {
+			for _, expr := range blacklisted.Key("regex").ValueWithShadows() {
+				if err := c.BlacklistRegex(expr); err != nil {
+					return fmt.Errorf("The blacklisted regex %s is not valid: %v", expr, err)
+				}
+			}
+		}
+		if blacklisted.HasKey("glob") {
+			for _, pattern := range blacklisted.Key("glob").ValueWithShadows() {
+				if err := c.BlacklistGlob(pattern); err != nil {
+					return fmt.Errorf("The blacklisted glob %s is not valid: %v", pattern, err)
+				}
+			}
+		}
+		if blacklisted.HasKey("tld") {
+			for _, tld := range blacklisted.Key("tld").ValueWithShadows() {
+				if err := c.BlacklistTLD(tld); err != nil {
+					return fmt.Errorf("The blacklisted TLD is not valid: %v", err)
+				}
+			}
+		}
 	}
 
 	return nil
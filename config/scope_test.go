@@ -364,6 +364,57 @@ func TestLoadScopeSettings(t *testing.T) {
 			assertionFunc: func(t *testing.T, c *Config) {
 			},
 		},
+		{
+			name: "failure - invalid regex in section scope.blacklisted",
+			args: args{cfg: []byte(`
+			[scope]
+			[scope.blacklisted]
+			regex = ^internal-(
+			`)},
+			wantErr: true,
+			assertionFunc: func(t *testing.T, c *Config) {
+			},
+		},
+		{
+			name: "success - regex and glob in section scope.blacklisted",
+			args: args{cfg: []byte(`
+			[scope]
+			[scope.blacklisted]
+			regex = ^internal-
+			glob = *.dev.example.com
+			`)},
+			wantErr: false,
+			assertionFunc: func(t *testing.T, c *Config) {
+				if len(c.BlacklistPatterns) != 2 || !c.Blacklisted("internal-vpn.example.com") || !c.Blacklisted("api.dev.example.com") {
+					t.Errorf("Config.loadScopeSettings() - failed to load the blacklisted patterns")
+				}
+			},
+		},
+		{
+			name: "success - tld in section scope.blacklisted",
+			args: args{cfg: []byte(`
+			[scope]
+			[scope.blacklisted]
+			tld = cloudfront.net
+			`)},
+			wantErr: false,
+			assertionFunc: func(t *testing.T, c *Config) {
+				if len(c.BlacklistTLDs) != 1 || !c.Blacklisted("abc.cloudfront.net") || c.Blacklisted("www.example.com") {
+					t.Errorf("Config.loadScopeSettings() - failed to load the blacklisted TLDs")
+				}
+			},
+		},
+		{
+			name: "failure - domain name as tld in section scope.blacklisted",
+			args: args{cfg: []byte(`
+			[scope]
+			[scope.blacklisted]
+			tld = sharepoint.com
+			`)},
+			wantErr: true,
+			assertionFunc: func(t *testing.T, c *Config) {
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -381,3 +432,43 @@ func TestLoadScopeSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigBlacklisted(t *testing.T) {
+	c := new(Config)
+	c.BlacklistSubdomain("tmp.example.com")
+	if err := c.BlacklistGlob("*.dev.example.com"); err != nil {
+		t.Fatalf("BlacklistGlob() error = %v", err)
+	}
+	if err := c.BlacklistRegex("^internal-"); err != nil {
+		t.Fatalf("BlacklistRegex() error = %v", err)
+	}
+	if err := c.BlacklistRegex("(unbalanced"); err == nil {
+		t.Errorf("BlacklistRegex() accepted an invalid regular expression")
+	}
+	if err := c.BlacklistTLD("CN"); err != nil {
+		t.Fatalf("BlacklistTLD() error = %v", err)
+	}
+	if err := c.BlacklistTLD(".cloudfront.net"); err != nil {
+		t.Fatalf("BlacklistTLD() error = %v", err)
+	}
+	if err := c.BlacklistTLD("sharepoint.com"); err == nil {
+		t.Errorf("BlacklistTLD() accepted a name that is not a public suffix")
+	}
+
+	for name, want := range map[string]bool{
+		"www.tmp.example.com":            true,
+		"api.DEV.example.com":            true,
+		"a.b.dev.example.com":            true,
+		"dev.example.com":                false,
+		"internal-vpn.example.com":       true,
+		"vpn.internal-example.com":       false,
+		"www.example.com":                false,
+		"www.example.com.cn":             true,
+		"d111111abcdef8.cloudfront.net.": true,
+		"cloudfront.example.com":         false,
+	} {
+		if got := c.Blacklisted(name); got != want {
+			t.Errorf("Blacklisted(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The host patterns of the Burp Suite advanced mode that only match a domain name and its subdomains,
+// such as ^.*\.example\.com$ and ^(.*\.)?example\.com$, which can also provide an address.
+var burpHostRE = regexp.MustCompile(`^\^?(?:\.\*\\\.|\(\.\*\\\.\)\?)?((?:[A-Za-z0-9-]+\\\.)+[A-Za-z0-9-]+)\$?$`)
+
+// The scope exported by the Burp Suite target settings.
+type burpScope struct {
+	Target struct {
+		Scope struct {
+			AdvancedMode bool             `json:"advanced_mode"`
+			Include      []burpScopeEntry `json:"include"`
+			Exclude      []burpScopeEntry `json:"exclude"`
+		} `json:"scope"`
+	} `json:"target"`
+}
+
+type burpScopeEntry struct {
+	Enabled bool `json:"enabled"`
+	// The host regular expression of the advanced mode
+	Host string `json:"host"`
+	// The URL prefix of the simple mode
+	Prefix string `json:"prefix"`
+}
+
+// LoadScopeFile adds the scope provided by the file to the configuration. The file is either the
+// JSON scope exported by Burp Suite, or a text file holding one entry per line: a domain name, an
+// address or range, a netblock, or an ASN such as AS13374. The lines starting with a minus sign
+// exclude the entry, where the names can also be excluded using globs or regular expressions
+// within slashes, such as -/^staging[0-9]+\./.
+func (c *Config) LoadScopeFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read the scope file %s: %v", path, err)
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := c.loadBurpScope(trimmed); err != nil {
+			return fmt.Errorf("The scope file %s: %v", path, err)
+		}
+		return nil
+	}
+
+	var num int
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		num++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var exclude bool
+		if strings.HasPrefix(line, "-") {
+			exclude = true
+			line = strings.TrimSpace(line[1:])
+		} else if strings.HasPrefix(line, "+") {
+			line = strings.TrimSpace(line[1:])
+		}
+		if err := c.addScopeEntry(line, exclude); err != nil {
+			return fmt.Errorf("The scope file %s line %d: %v", path, num, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func (c *Config) loadBurpScope(data []byte) error {
+	var scope burpScope
+
+	if err := json.Unmarshal(data, &scope); err != nil {
+		return fmt.Errorf("the Burp Suite scope is not valid: %v", err)
+	}
+
+	advanced := scope.Target.Scope.AdvancedMode
+	for _, e := range scope.Target.Scope.Include {
+		if !e.Enabled {
+			continue
+		}
+
+		entry, regex := burpEntry(e, advanced)
+		if regex {
+			return fmt.Errorf("the included host %s cannot be converted to a domain name", entry)
+		}
+		if err := c.addScopeEntry(entry, false); err != nil {
+			return err
+		}
+	}
+	for _, e := range scope.Target.Scope.Exclude {
+		if !e.Enabled {
+			continue
+		}
+
+		entry, regex := burpEntry(e, advanced)
+		if regex {
+			entry = "/" + entry + "/"
+		}
+		if err := c.addScopeEntry(entry, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the host matched by the Burp Suite scope entry, or its regular expression
+// when it cannot be converted to a domain name or address.
+func burpEntry(e burpScopeEntry, advanced bool) (string, bool) {
+	if !advanced {
+		if u, err := url.Parse(e.Prefix); err == nil && u.Hostname() != "" {
+			return u.Hostname(), false
+		}
+		return e.Prefix, false
+	}
+
+	if m := burpHostRE.FindStringSubmatch(e.Host); m != nil {
+		return strings.ReplaceAll(m[1], `\.`, "."), false
+	}
+	return e.Host, true
+}
+
+func (c *Config) addScopeEntry(entry string, exclude bool) error {
+	if entry == "" {
+		return fmt.Errorf("the entry is empty")
+	}
+	// Regular expressions matching the excluded names
+	if len(entry) > 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+		if !exclude {
+			return fmt.Errorf("the regular expression %s can only exclude names", entry)
+		}
+		if err := c.BlacklistRegex(entry[1 : len(entry)-1]); err != nil {
+			return fmt.Errorf("the regular expression %s is not valid: %v", entry, err)
+		}
+		return nil
+	}
+
+	if u := strings.ToUpper(entry); strings.HasPrefix(u, "AS") {
+		if asn, err := strconv.Atoi(u[2:]); err == nil {
+			if exclude {
+				return fmt.Errorf("the ASN %s cannot be excluded", entry)
+			}
+			if asn <= 0 {
+				return fmt.Errorf("%s is not a valid autonomous system number", entry)
+			}
+			c.ASNs = uniqueIntAppend(c.ASNs, strconv.Itoa(asn))
+			return nil
+		}
+	}
+
+	if _, ipnet, err := net.ParseCIDR(entry); err == nil {
+		if exclude {
+			c.ExcludedCIDRs = append(c.ExcludedCIDRs, ipnet)
+		} else {
+			c.CIDRs = append(c.CIDRs, ipnet)
+		}
+		return nil
+	}
+
+	var ips parseIPs
+	if net.ParseIP(strings.Split(entry, "-")[0]) != nil {
+		if err := ips.Set(entry); err != nil {
+			return err
+		}
+		if exclude {
+			c.ExcludedAddresses = append(c.ExcludedAddresses, ips...)
+		} else {
+			c.Addresses = append(c.Addresses, ips...)
+		}
+		return nil
+	}
+
+	name := strings.ToLower(entry)
+	if strings.ContainsAny(name, "*?") {
+		if exclude {
+			if err := c.BlacklistGlob(name); err != nil {
+				return fmt.Errorf("the glob %s is not valid: %v", entry, err)
+			}
+			return nil
+		}
+		// The subdomains of the root domain names are always in scope
+		if name = strings.TrimPrefix(name, "*."); strings.ContainsAny(name, "*?") {
+			return fmt.Errorf("the glob %s can only exclude names", entry)
+		}
+	}
+
+	if labels := strings.Split(name, "."); len(labels) < 2 || strings.Contains(name, "..") ||
+		strings.ContainsAny(name, " /:") {
+		return fmt.Errorf("%s is not a valid domain name, address, netblock or ASN", entry)
+	}
+	if exclude {
+		c.BlacklistSubdomain(name)
+	} else {
+		c.AddDomain(name)
+	}
+	return nil
+}
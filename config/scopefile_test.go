@@ -0,0 +1,125 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeScopeFile(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "scope.txt")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write the scope file: %v", err)
+	}
+	return path
+}
+
+func TestLoadScopeFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scope")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewConfig()
+	path := writeScopeFile(t, dir, `
+		# The scope of the engagement
+		example.com
+		+*.owasp.org
+		192.168.1.1-5
+		10.0.0.0/16
+		AS13374
+
+		- dev.example.com
+		-*.test.example.com
+		-/^staging[0-9]+\./
+		-10.0.5.0/24
+		-192.168.1.3
+	`)
+	if err := c.LoadScopeFile(path); err != nil {
+		t.Fatalf("Failed to load the scope file: %v", err)
+	}
+
+	if !c.IsDomainInScope("www.example.com") || !c.IsDomainInScope("www.owasp.org") {
+		t.Errorf("The domains were not added to the scope: %v", c.Domains())
+	}
+	if len(c.ASNs) != 1 || c.ASNs[0] != 13374 {
+		t.Errorf("The ASN was not added to the scope: %v", c.ASNs)
+	}
+	for _, name := range []string{"www.dev.example.com", "a.test.example.com", "staging2.example.com"} {
+		if !c.Blacklisted(name) {
+			t.Errorf("%s was not excluded from the scope", name)
+		}
+	}
+	if c.Blacklisted("www.example.com") {
+		t.Errorf("The name in scope was excluded")
+	}
+	for addr, expected := range map[string]bool{
+		"192.168.1.2": true,
+		"192.168.1.3": false,
+		"10.0.4.1":    true,
+		"10.0.5.1":    false,
+		"172.16.0.1":  false,
+	} {
+		if c.IsAddressInScope(addr) != expected {
+			t.Errorf("The scope of %s was not %v", addr, expected)
+		}
+	}
+
+	for _, bad := range []string{"/^www/", "-AS13374", "*.example.*", "localhost", "192.168.1.1-foo"} {
+		if err := NewConfig().LoadScopeFile(writeScopeFile(t, dir, bad)); err == nil {
+			t.Errorf("The scope entry %s was accepted", bad)
+		}
+	}
+}
+
+func TestLoadBurpScopeFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scope")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewConfig()
+	path := writeScopeFile(t, dir, `{
+		"target": {
+			"scope": {
+				"advanced_mode": true,
+				"include": [
+					{"enabled": true, "host": "^.*\\.example\\.com$", "protocol": "any"},
+					{"enabled": true, "host": "^(.*\\.)?owasp\\.org$", "protocol": "https"},
+					{"enabled": false, "host": "^disabled\\.com$", "protocol": "any"}
+				],
+				"exclude": [
+					{"enabled": true, "host": "^dev\\.example\\.com$", "protocol": "any"},
+					{"enabled": true, "host": "^staging[0-9]+\\.example\\.com$", "protocol": "any"}
+				]
+			}
+		}
+	}`)
+	if err := c.LoadScopeFile(path); err != nil {
+		t.Fatalf("Failed to load the Burp Suite scope: %v", err)
+	}
+
+	if !c.IsDomainInScope("www.example.com") || !c.IsDomainInScope("owasp.org") || c.IsDomainInScope("disabled.com") {
+		t.Errorf("The included hosts were not converted: %v", c.Domains())
+	}
+	if !c.Blacklisted("dev.example.com") || !c.Blacklisted("staging1.example.com") || c.Blacklisted("www.example.com") {
+		t.Errorf("The excluded hosts were not converted")
+	}
+
+	path = writeScopeFile(t, dir, `{"target":{"scope":{"include":[{"enabled":true,"prefix":"https://www.acme.com/app"}]}}}`)
+	if c := NewConfig(); c.LoadScopeFile(path) != nil || !c.IsDomainInScope("www.acme.com") {
+		t.Errorf("The URL prefix of the simple mode was not converted")
+	}
+
+	path = writeScopeFile(t, dir, `{"target":{"scope":{"advanced_mode":true,"include":[{"enabled":true,"host":"^api[0-9]+\\.acme\\.com$"}]}}}`)
+	if err := NewConfig().LoadScopeFile(path); err == nil {
+		t.Errorf("The included host regular expression was accepted")
+	}
+}
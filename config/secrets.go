@@ -0,0 +1,91 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+const defaultVaultMount = "secret"
+
+// Secrets contains the settings of the backend storing the data source credentials, which are
+// retrieved for the data sources providing the secret_path setting.
+type Secrets struct {
+	// The backend storing the secrets, which can be vault or aws
+	Backend string `ini:"backend"`
+	// The address of the Vault server and the token authorizing the reads
+	URL   string `ini:"url"`
+	Token string `ini:"token"`
+	// The mount of the Vault KV version 2 secrets engine
+	Mount string `ini:"mount"`
+	// The region and the keys of the AWS Secrets Manager
+	Region       string `ini:"region"`
+	AccessKey    string `ini:"access_key"`
+	SecretKey    string `ini:"secret_key"`
+	SessionToken string `ini:"session_token"`
+	// The endpoint of the AWS Secrets Manager, which is derived from the region when not provided
+	Endpoint string `ini:"endpoint"`
+}
+
+func (c *Config) loadSecretsSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("secrets")
+	if err != nil {
+		return nil
+	}
+
+	s := &Secrets{Mount: defaultVaultMount}
+	if err := sec.MapTo(s); err != nil {
+		return err
+	}
+
+	s.Backend = strings.ToLower(strings.TrimSpace(s.Backend))
+	switch s.Backend {
+	case "vault":
+		if s.URL == "" {
+			s.URL = os.Getenv("VAULT_ADDR")
+		}
+		if s.Token == "" {
+			s.Token = os.Getenv("VAULT_TOKEN")
+		}
+		if u, err := url.Parse(s.URL); err != nil || u.Host == "" {
+			return fmt.Errorf("The secrets section requires the URL of the Vault server: %s", s.URL)
+		}
+		if s.Token == "" {
+			return fmt.Errorf("The secrets section requires the token setting or the VAULT_TOKEN environment variable")
+		}
+		s.Mount = strings.Trim(s.Mount, "/")
+		if s.Mount == "" {
+			s.Mount = defaultVaultMount
+		}
+	case "aws":
+		if s.Region == "" {
+			s.Region = os.Getenv("AWS_REGION")
+		}
+		if s.AccessKey == "" {
+			s.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+			s.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+			s.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+		}
+		if s.Region == "" {
+			return fmt.Errorf("The secrets section requires the region of the AWS Secrets Manager")
+		}
+		if s.AccessKey == "" || s.SecretKey == "" {
+			return fmt.Errorf("The secrets section requires the access_key and secret_key settings")
+		}
+		if s.Endpoint == "" {
+			s.Endpoint = "https://secretsmanager." + s.Region + ".amazonaws.com"
+		}
+	default:
+		return fmt.Errorf("The secrets backend must be vault or aws: %s", s.Backend)
+	}
+
+	c.Secrets = s
+	return nil
+}
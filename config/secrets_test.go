@@ -0,0 +1,72 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadSecretsSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+		[secrets]
+		backend = vault
+		url = https://vault.example.com:8200
+		token = token
+
+		[data_sources]
+		[data_sources.Shodan]
+		secret_path = amass/shodan
+		`),
+	)
+	if err := c.loadSecretsSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if s := c.Secrets; s == nil || s.Backend != "vault" || s.Mount != defaultVaultMount {
+		t.Fatalf("The secrets settings were not loaded: %v", s)
+	}
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if path := c.GetDataSourceConfig("Shodan").SecretPath; path != "amass/shodan" {
+		t.Errorf("The secret path was %q", path)
+	}
+
+	cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+		[secrets]
+		backend = aws
+		region = us-west-2
+		access_key = id
+		secret_key = secret
+		`),
+	)
+	if err := c.loadSecretsSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if s := c.Secrets; s.Endpoint != "https://secretsmanager.us-west-2.amazonaws.com" {
+		t.Errorf("The endpoint of the AWS Secrets Manager was %s", s.Endpoint)
+	}
+
+	for _, bad := range []string{
+		"[secrets]\nbackend = keychain",
+		"[secrets]\nbackend = vault\nurl = vault\ntoken = token",
+		"[secrets]\nbackend = aws\naccess_key = id\nsecret_key = secret",
+		"[data_sources]\n[data_sources.Shodan]\nsecret_path = amass/shodan",
+	} {
+		cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(bad))
+
+		c := NewConfig()
+		err := c.loadSecretsSettings(cfg)
+		if err == nil {
+			err = c.loadDataSourceSettings(cfg)
+		}
+		if err == nil {
+			t.Errorf("The settings were accepted: %s", bad)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/go-ini/ini"
+)
+
+// DefaultReverseSweepConcurrency is the number of reverse DNS lookups performed concurrently during the sweeps.
+const DefaultReverseSweepConcurrency = 25
+
+// DefaultReverseSweepQPS is the number of reverse DNS lookups performed per second during the sweeps.
+const DefaultReverseSweepQPS = 250
+
+func (c *Config) loadReverseSweepSettings(cfg *ini.File) error {
+	sweeps, err := cfg.GetSection("reverse_sweeps")
+	if err != nil {
+		return nil
+	}
+
+	c.ReverseSweeps = sweeps.Key("enabled").MustBool(true)
+	if !c.ReverseSweeps {
+		return nil
+	}
+
+	c.ReverseSweepConcurrency = sweeps.Key("concurrency").MustInt(DefaultReverseSweepConcurrency)
+	if c.ReverseSweepConcurrency <= 0 {
+		return fmt.Errorf("the reverse_sweeps concurrency setting must be greater than zero: %d", c.ReverseSweepConcurrency)
+	}
+
+	c.ReverseSweepQPS = sweeps.Key("qps").MustInt(DefaultReverseSweepQPS)
+	if c.ReverseSweepQPS <= 0 {
+		return fmt.Errorf("the reverse_sweeps qps setting must be greater than zero: %d", c.ReverseSweepQPS)
+	}
+
+	c.ReverseSweepSize = sweeps.Key("sweep_size").MustInt(0)
+	if c.ReverseSweepSize < 0 {
+		return fmt.Errorf("the reverse_sweeps sweep_size setting cannot be negative: %d", c.ReverseSweepSize)
+	}
+	return nil
+}
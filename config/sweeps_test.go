@@ -0,0 +1,50 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadReverseSweepSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		wantErr     bool
+		enabled     bool
+		concurrency int
+		qps         int
+		size        int
+	}{
+		{"defaults", "[reverse_sweeps]\nenabled = true", false, true, DefaultReverseSweepConcurrency, DefaultReverseSweepQPS, 0},
+		{"custom", "[reverse_sweeps]\nconcurrency = 5\nqps = 20\nsweep_size = 256", false, true, 5, 20, 256},
+		{"disabled", "[reverse_sweeps]\nenabled = false\nqps = 20", false, false, DefaultReverseSweepConcurrency, DefaultReverseSweepQPS, 0},
+		{"invalid qps", "[reverse_sweeps]\nqps = 0", true, true, 0, 0, 0},
+		{"invalid size", "[reverse_sweeps]\nsweep_size = -1", true, true, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		cfg, err := ini.Load([]byte(test.cfg))
+		if err != nil {
+			t.Fatalf("%s: failed to load the test settings: %v", test.name, err)
+		}
+
+		c := NewConfig()
+		if err := c.loadReverseSweepSettings(cfg); (err != nil) != test.wantErr {
+			t.Errorf("%s: error = %v, wantErr %t", test.name, err, test.wantErr)
+			continue
+		}
+		if test.wantErr {
+			continue
+		}
+		if c.ReverseSweeps != test.enabled || c.ReverseSweepConcurrency != test.concurrency ||
+			c.ReverseSweepQPS != test.qps || c.ReverseSweepSize != test.size {
+			t.Errorf("%s: got enabled %t, concurrency %d, qps %d, sweep size %d", test.name,
+				c.ReverseSweeps, c.ReverseSweepConcurrency, c.ReverseSweepQPS, c.ReverseSweepSize)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"github.com/OWASP/Amass/v3/resources"
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadTakeoverSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("takeovers")
+	if err != nil {
+		return nil
+	}
+
+	c.Takeovers = sec.Key("enabled").MustBool(true)
+	c.TakeoverFingerprints = sec.Key("fingerprints").String()
+	// The fingerprints file is checked now, so the mistakes are not found after the enumeration
+	if c.TakeoverFingerprints != "" {
+		if _, err := resources.LoadTakeoverFingerprints(c.TakeoverFingerprints); err != nil {
+			return err
+		}
+	}
+	return nil
+}
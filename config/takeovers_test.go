@@ -0,0 +1,34 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadTakeoverSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, _ := ini.Load([]byte(`
+	[takeovers]
+	enabled = true
+	`))
+
+	if err := c.loadTakeoverSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !c.Takeovers || c.TakeoverFingerprints != "" {
+		t.Errorf("The takeover detection was not enabled")
+	}
+
+	cfg, _ = ini.Load([]byte(`
+	[takeovers]
+	fingerprints = /nonexistent/takeovers.json
+	`))
+	if err := NewConfig().loadTakeoverSettings(cfg); err == nil {
+		t.Errorf("The missing fingerprints file was accepted")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+const defaultUploadKey = "{domain}/{date}/{file}"
+
+// Upload contains the settings of the object storage receiving the output files after each enumeration.
+type Upload struct {
+	// The storage service, which can be s3, gs or azure
+	Service string
+	Bucket  string
+	// The template of the object keys, which can use {domain}, {date}, {time}, {uuid} and {file}
+	Key string `ini:"key"`
+	// The endpoint of an S3 compatible service, or of the Azure storage account
+	Endpoint  string `ini:"endpoint"`
+	Region    string `ini:"region"`
+	AccessKey string `ini:"access_key"`
+	SecretKey string `ini:"secret_key"`
+	// The shared access signature authorizing the writes to the Azure container
+	SASToken string `ini:"sas_token"`
+}
+
+func (c *Config) loadUploadSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("upload")
+	if err != nil {
+		return nil
+	}
+
+	up := &Upload{Key: defaultUploadKey}
+	if err := sec.MapTo(up); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(sec.Key("url").String())
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("The upload URL is not valid: %s", sec.Key("url").String())
+	}
+
+	up.Service = strings.ToLower(u.Scheme)
+	switch up.Service {
+	case "s3", "gs":
+		up.Bucket = u.Host
+		if up.AccessKey == "" && up.Service == "s3" {
+			up.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+			up.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		}
+		if up.AccessKey == "" || up.SecretKey == "" {
+			return fmt.Errorf("The upload to %s requires the access_key and secret_key settings", u.Host)
+		}
+	case "azure":
+		// The URL identifies the storage account and the container, such as azure://account/container
+		up.Bucket = strings.Trim(u.Path, "/")
+		if up.Bucket == "" || strings.Contains(up.Bucket, "/") {
+			return fmt.Errorf("The upload URL must identify the Azure container: %s", u.String())
+		}
+		if up.Endpoint == "" {
+			up.Endpoint = "https://" + u.Host + ".blob.core.windows.net"
+		}
+		if up.SASToken == "" {
+			up.SASToken = os.Getenv("AZURE_STORAGE_SAS_TOKEN")
+		}
+		if up.SASToken == "" {
+			return fmt.Errorf("The upload to the %s Azure container requires the sas_token setting", up.Bucket)
+		}
+	default:
+		return fmt.Errorf("The upload URL must use the s3, gs or azure scheme: %s", u.String())
+	}
+
+	if up.Region == "" {
+		up.Region = "us-east-1"
+		if up.Service == "gs" {
+			up.Region = "auto"
+		}
+	}
+	if strings.TrimSpace(up.Key) == "" {
+		up.Key = defaultUploadKey
+	}
+
+	c.Upload = up
+	return nil
+}
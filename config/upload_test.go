@@ -0,0 +1,47 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadUploadSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+		[upload]
+		url = azure://account/results
+		sas_token = sv=2020-08-04&sig=abc
+		key = amass/{domain}/{date}/{file}
+		`),
+	)
+	if err := c.loadUploadSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if up := c.Upload; up == nil || up.Service != "azure" || up.Bucket != "results" ||
+		up.Endpoint != "https://account.blob.core.windows.net" || up.Key != "amass/{domain}/{date}/{file}" {
+		t.Errorf("The upload settings were not loaded: %v", up)
+	}
+
+	cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte("[upload]\nurl = gs://bucket\naccess_key = id\nsecret_key = secret"))
+	c = NewConfig()
+	if err := c.loadUploadSettings(cfg); err != nil || c.Upload.Region != "auto" || c.Upload.Key != defaultUploadKey {
+		t.Errorf("The GCS settings were not loaded: %v %v", c.Upload, err)
+	}
+
+	for _, bad := range []string{
+		"[upload]\nurl = ftp://bucket",
+		"[upload]\nurl = gs://bucket",
+		"[upload]\nurl = azure://account\nsas_token = sig",
+	} {
+		cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(bad))
+		if err := NewConfig().loadUploadSettings(cfg); err == nil {
+			t.Errorf("The settings were accepted: %s", bad)
+		}
+	}
+}
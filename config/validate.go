@@ -0,0 +1,133 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+// The sections read by the configuration loaders, where an asterisk matches any name,
+// such as the name of a data source or of a credential set.
+var knownSections = []string{
+	"resolvers",
+	"resolvers.domains",
+	"scope",
+	"scope.domains",
+	"scope.blacklisted",
+	"graphdbs",
+	"graphdbs.*",
+	"integrations",
+	"integrations.*",
+	"webhooks",
+	"webhooks.*",
+	"email",
+	"upload",
+	"secrets",
+	"schedules",
+	"schedules.*",
+	"bruteforce",
+	"bruteforce.*",
+	"dns_records",
+	"reverse_sweeps",
+	"dnssec",
+	"http_probes",
+	"port_scan",
+	"takeovers",
+	"alterations",
+	"alterations.keywords",
+	"data_sources",
+	"data_sources.*",
+	"data_sources.*.*",
+}
+
+// ValidateSettings parses the configuration file and reports all the problems found, instead of
+// stopping at the first one or ignoring the entries the loaders skip, such as the sections that are
+// not known and the malformed ASNs and ports. The returned Config holds the settings that were loaded.
+func ValidateSettings(path string) (*Config, []error) {
+	var errs []error
+
+	cfg, err := readSettingsFile(path)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to load the configuration file: %v", err)}
+	}
+	if err := expandEnvironment(cfg); err != nil {
+		return nil, []error{err}
+	}
+
+	for _, sec := range cfg.Sections() {
+		if name := sec.Name(); !strings.EqualFold(name, ini.DefaultSection) && !knownSection(name) {
+			errs = append(errs, fmt.Errorf("The %s section is not used by Amass", name))
+		}
+	}
+	errs = append(errs, validateScope(cfg)...)
+
+	if dir := cfg.Section(ini.DefaultSection).Key("scripts_directory").String(); dir != "" {
+		if finfo, err := os.Stat(dir); err != nil || !finfo.IsDir() {
+			errs = append(errs, fmt.Errorf("The scripts directory %s does not exist", dir))
+		}
+	}
+	// The loaders are executed last, since the profile modifies the sections
+	c := NewConfig()
+	return c, append(c.loadSettings(cfg, true), errs...)
+}
+
+func knownSection(name string) bool {
+	name = strings.ToLower(name)
+	// The profiles provide the same sections, such as [profiles.stealth.resolvers]
+	if name == profilesSection {
+		return true
+	} else if strings.HasPrefix(name, profilesSection+".") {
+		if name = withoutProfile(name); name == "" {
+			return true
+		}
+	}
+
+	parts := strings.Split(name, ".")
+	for _, known := range knownSections {
+		kparts := strings.Split(known, ".")
+		if len(kparts) != len(parts) {
+			continue
+		}
+
+		match := true
+		for i, kp := range kparts {
+			if kp != "*" && kp != parts[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports the values of the scope section that the loader silently skips.
+func validateScope(cfg *ini.File) []error {
+	var errs []error
+
+	scope, err := cfg.GetSection("scope")
+	if err != nil {
+		return nil
+	}
+
+	for _, asn := range scope.Key("asn").ValueWithShadows() {
+		if n, err := strconv.Atoi(asn); asn != "" && (err != nil || n <= 0) {
+			errs = append(errs, fmt.Errorf("The scope ASN %s is not a valid autonomous system number", asn))
+		}
+	}
+	for _, port := range scope.Key("port").ValueWithShadows() {
+		if n, err := strconv.Atoi(port); port != "" && (err != nil || n <= 0 || n > 65535) {
+			errs = append(errs, fmt.Errorf("The scope port %s is not a valid port number", port))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,119 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestValidateSettings(t *testing.T) {
+	if _, errs := ValidateSettings("../examples/config.ini"); len(errs) > 0 {
+		t.Errorf("The example configuration file was not valid: %v", errs)
+	}
+
+	dir, err := ioutil.TempDir("", "validate")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.ini")
+	if err := ioutil.WriteFile(path, []byte(`
+scripts_directory = ./nonexistent_directory
+
+[scope]
+cidr = 10.0.0.0/33
+asn = AS13374
+port = 70000
+
+[scope.domain]
+domain = owasp.org
+
+[email]
+server = smtp.example.com
+
+[data_sources]
+
+[data_sources.Shodan]
+ttl = 10080
+
+[profiles.stealth.resolvers]
+resolver = 8.8.8.8
+
+[profiles.stealth.resolver]
+resolver = 8.8.8.8
+`), 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	c, errs := ValidateSettings(path)
+	if c == nil {
+		t.Fatal("The settings were not returned")
+	}
+
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	report := strings.Join(msgs, "\n")
+	for _, expected := range []string{
+		"10.0.0.0/33",
+		"email server",
+		"scope.domain section",
+		"profiles.stealth.resolver section",
+		"ASN AS13374",
+		"port 70000",
+		"nonexistent_directory",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("The problem with %q was not reported:\n%s", expected, report)
+		}
+	}
+	if len(errs) != 7 {
+		t.Errorf("%d problems were reported:\n%s", len(errs), report)
+	}
+	if c.GetDataSourceConfig("Shodan").TTL != 10080 {
+		t.Errorf("The sections after the email section were not loaded")
+	}
+}
+
+func TestKnownSections(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Failed to list the source files: %v", err)
+	}
+
+	// The sections read by the loaders, and whether the loader reads their child sections
+	re := regexp.MustCompile(`Section\("([^"]+)"\)(\.ChildSections\(\))?`)
+	var num int
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+
+		for _, m := range re.FindAllStringSubmatch(string(src), -1) {
+			num++
+			if !knownSection(m[1]) {
+				t.Errorf("The %s section read in %s is missing from knownSections", m[1], file)
+			}
+			if m[2] != "" && !knownSection(m[1]+".child") {
+				t.Errorf("The child sections of %s read in %s are missing from knownSections", m[1], file)
+			}
+		}
+	}
+	if num == 0 {
+		t.Error("No sections were found in the source files")
+	}
+}
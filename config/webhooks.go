@@ -0,0 +1,103 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+const (
+	defaultWebhookBatchSize = 50
+	defaultWebhookRetries   = 3
+)
+
+// The types of assets that can fire the webhooks.
+var webhookAssetTypes = []string{"fqdn", "address", "netblock"}
+
+// The payload formats, where the chat formats post messages to the incoming webhooks of the services.
+var webhookFormats = []string{"json", "slack", "discord", "teams"}
+
+// Webhook contains the settings of an endpoint notified when new assets enter the graph.
+type Webhook struct {
+	Name string
+	URL  string `ini:"url"`
+	// The payload format, which is json by default
+	Format string `ini:"format"`
+	// Used to sign the payloads with HMAC-SHA256, when provided
+	Secret string `ini:"secret"`
+	// The asset types that fire the webhook, which are all the types by default
+	Assets    []string `ini:"assets" delim:","`
+	BatchSize int      `ini:"batch_size"`
+	Retries   int      `ini:"retries"`
+}
+
+func (c *Config) loadWebhookSettings(cfg *ini.File) error {
+	for _, child := range cfg.Section("webhooks").ChildSections() {
+		wh := &Webhook{
+			BatchSize: defaultWebhookBatchSize,
+			Retries:   defaultWebhookRetries,
+		}
+		name := strings.TrimPrefix(child.Name(), "webhooks.")
+
+		if err := child.MapTo(wh); err != nil {
+			return err
+		}
+
+		wh.Name = name
+		if u, err := url.Parse(wh.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("The %s webhook URL is not valid: %s", name, wh.URL)
+		}
+		if wh.Format = strings.ToLower(strings.TrimSpace(wh.Format)); wh.Format == "" {
+			wh.Format = "json"
+		} else if !stringInList(wh.Format, webhookFormats) {
+			return fmt.Errorf("The %s webhook format is not supported: %s", name, wh.Format)
+		}
+		if wh.BatchSize <= 0 || wh.Retries < 0 {
+			return fmt.Errorf("The %s webhook requires a positive batch_size and retries of zero or more", name)
+		}
+
+		var assets []string
+		for _, a := range wh.Assets {
+			a = strings.ToLower(strings.TrimSpace(a))
+			if a == "" {
+				continue
+			}
+			if !stringInList(a, webhookAssetTypes) {
+				return fmt.Errorf("The %s webhook asset type is not supported: %s", name, a)
+			}
+			assets = append(assets, a)
+		}
+		if len(assets) == 0 {
+			assets = webhookAssetTypes
+		}
+
+		wh.Assets = assets
+		c.Webhooks = append(c.Webhooks, wh)
+	}
+	return nil
+}
+
+// Fires returns true when the asset type fires the webhook.
+func (wh *Webhook) Fires(atype string) bool {
+	return stringInList(atype, wh.Assets)
+}
+
+// Chat returns true when the webhook posts messages to a chat service.
+func (wh *Webhook) Chat() bool {
+	return wh.Format != "" && wh.Format != "json"
+}
+
+func stringInList(s string, list []string) bool {
+	for _, item := range list {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
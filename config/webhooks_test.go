@@ -0,0 +1,63 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadWebhookSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[webhooks.soar]
+		url = https://soar.example.com/hooks/amass
+		secret = shared
+		assets = fqdn, netblock
+		batch_size = 10
+
+		[webhooks.pipeline]
+		url = http://127.0.0.1:9000/assets
+
+		[webhooks.team]
+		url = https://hooks.slack.com/services/T000/B000/XXXX
+		format = Slack
+		`),
+	)
+	if err := c.loadWebhookSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(c.Webhooks) != 3 {
+		t.Fatalf("%d webhooks were loaded, expected 3", len(c.Webhooks))
+	}
+	if wh := c.Webhooks[0]; wh.Name != "soar" || wh.Secret != "shared" || wh.BatchSize != 10 || wh.Fires("address") || !wh.Fires("netblock") {
+		t.Errorf("The soar webhook settings were not loaded: %v", wh)
+	}
+	if wh := c.Webhooks[1]; wh.BatchSize != defaultWebhookBatchSize || wh.Retries != defaultWebhookRetries || !wh.Fires("address") || wh.Chat() {
+		t.Errorf("The pipeline webhook did not receive the defaults: %v", wh)
+	}
+	if wh := c.Webhooks[2]; wh.Format != "slack" || !wh.Chat() {
+		t.Errorf("The team webhook format was not loaded: %v", wh)
+	}
+
+	for _, bad := range []string{
+		"[webhooks.bad]\nurl = ftp://example.com",
+		"[webhooks.bad]\nurl = https://example.com\nassets = email",
+		"[webhooks.bad]\nurl = https://example.com\nbatch_size = 0",
+		"[webhooks.bad]\nurl = https://example.com\nformat = irc",
+	} {
+		cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(bad))
+		if err := NewConfig().loadWebhookSettings(cfg); err == nil {
+			t.Errorf("The settings were accepted: %s", bad)
+		}
+	}
+}
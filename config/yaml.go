@@ -0,0 +1,202 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-ini/ini"
+	"gopkg.in/yaml.v3"
+)
+
+// The sections of the configuration file that can be provided as YAML lists, and the repeated key
+// receiving the values of the list, such as 'resolvers: [1.1.1.1, 8.8.8.8]' in place of [resolvers].
+var yamlListKeys = map[string]string{
+	"resolvers":             "resolver",
+	"scope.domains":         "domain",
+	"scope.blacklisted":     "subdomain",
+	"data_sources.disabled": "data_source",
+}
+
+// IsYAMLFile returns true when the path has the extension of a YAML configuration file.
+func IsYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Parses the YAML configuration file into the sections and keys of the .ini format, so the YAML
+// settings are loaded by the same code. The nested mappings provide the sections, such as
+// data_sources.Shodan.Credentials, and the lists provide the keys that can be repeated.
+func loadYAMLSettings(opts ini.LoadOptions, path string) (*ini.File, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseYAMLSettings(opts, data)
+}
+
+func parseYAMLSettings(opts ini.LoadOptions, data []byte) (*ini.File, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	cfg := ini.Empty(opts)
+	if len(doc.Content) == 0 {
+		return cfg, nil
+	}
+	if root := doc.Content[0]; root.Kind != yaml.MappingNode {
+		return nil, errors.New("the YAML document must be a mapping of the settings")
+	}
+	return cfg, yamlSection(cfg, ini.DefaultSection, doc.Content[0])
+}
+
+func yamlSection(cfg *ini.File, name string, node *yaml.Node) error {
+	sec := cfg.Section(name)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, yamlAlias(node.Content[i+1])
+
+		path := key
+		if name != ini.DefaultSection {
+			path = name + "." + key
+		}
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			if _, err := sec.NewKey(key, yamlScalar(value)); err != nil {
+				return err
+			}
+		case yaml.SequenceNode:
+			target, tkey := sec, key
+			if k, found := yamlListKeys[strings.ToLower(withoutProfile(path))]; found {
+				target, tkey = cfg.Section(path), k
+			}
+
+			for _, item := range value.Content {
+				item = yamlAlias(item)
+				if item.Kind != yaml.ScalarNode {
+					return fmt.Errorf("the %s list can only hold values (line %d)", path, item.Line)
+				}
+				if _, err := target.NewKey(tkey, yamlScalar(item)); err != nil {
+					return err
+				}
+			}
+		case yaml.MappingNode:
+			if err := yamlSection(cfg, path, value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("the %s setting is not supported (line %d)", path, value.Line)
+		}
+	}
+	return nil
+}
+
+func yamlAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func yamlScalar(node *yaml.Node) string {
+	if node.ShortTag() == "!!null" {
+		return ""
+	}
+	return node.Value
+}
+
+// Removes the [profiles.NAME] prefix from the section name, since the profiles override the same sections.
+func withoutProfile(name string) string {
+	if !strings.HasPrefix(name, profilesSection+".") {
+		return name
+	}
+
+	parts := strings.SplitN(name, ".", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[2]
+}
+
+// ConvertINIToYAML returns the YAML document providing the settings of the .ini configuration file.
+// The sections become nested mappings and the repeated keys become lists. The comments are not kept.
+func ConvertINIToYAML(path string) ([]byte, error) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{AllowShadows: true}, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the configuration file: %v", err)
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, sec := range cfg.Sections() {
+		node := root
+		if sec.Name() != ini.DefaultSection {
+			for _, part := range strings.Split(sec.Name(), ".") {
+				node = yamlChild(node, part)
+			}
+		}
+
+		for _, key := range sec.Keys() {
+			value := &yaml.Node{Kind: yaml.ScalarNode, Value: key.String()}
+
+			if values := key.ValueWithShadows(); len(values) > 1 {
+				value = &yaml.Node{Kind: yaml.SequenceNode}
+				for _, v := range values {
+					value.Content = append(value.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: v})
+				}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key.Name()}, value)
+		}
+	}
+	yamlCollapseLists(root, "")
+
+	return yaml.Marshal(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}})
+}
+
+// Returns the mapping for the key within the node, which is added when not already present.
+func yamlChild(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key && node.Content[i+1].Kind == yaml.MappingNode {
+			return node.Content[i+1]
+		}
+	}
+
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+	return child
+}
+
+// Replaces the mappings that only hold the repeated key of a list section with the list of values.
+func yamlCollapseLists(node *yaml.Node, name string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		value := node.Content[i+1]
+		if value.Kind != yaml.MappingNode {
+			continue
+		}
+
+		path := node.Content[i].Value
+		if name != "" {
+			path = name + "." + path
+		}
+		yamlCollapseLists(value, path)
+
+		key, found := yamlListKeys[strings.ToLower(withoutProfile(path))]
+		if !found || len(value.Content) != 2 || !strings.EqualFold(value.Content[0].Value, key) {
+			continue
+		}
+
+		list := value.Content[1]
+		if list.Kind == yaml.ScalarNode {
+			list = &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{list}}
+		}
+		node.Content[i+1] = list
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+const testINISettings = `
+mode = active
+dns_budget = 50000
+
+[resolvers]
+resolver = 8.8.8.8
+resolver = 1.1.1.1
+
+[resolvers.domains]
+corp.example.com = 10.0.0.53
+
+[scope]
+port = 80
+port = 8443
+
+[scope.domains]
+domain = owasp.org
+domain = appsecusa.org
+
+[scope.blacklisted]
+subdomain = education.owasp.org
+
+[bruteforce]
+enabled = true
+timeout = 30
+
+[data_sources]
+minimum_ttl = 1440
+
+[data_sources.disabled]
+data_source = Ask
+data_source = Bing
+
+[data_sources.Shodan]
+ttl = 10080
+
+[data_sources.Shodan.Credentials]
+apikey = secret
+
+[profiles.stealth]
+mode = passive
+
+[profiles.stealth.resolvers]
+resolver = 9.9.9.9
+`
+
+func TestLoadYAMLSettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yaml")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yml")
+	if err := ioutil.WriteFile(path, []byte(`
+mode: active
+resolvers: [8.8.8.8, 1.1.1.1]
+scope:
+  port: [80, 8443]
+  domains:
+    - owasp.org
+    - appsecusa.org
+  blacklisted: [education.owasp.org]
+data_sources:
+  disabled: [Ask, Bing]
+  Shodan:
+    ttl: 10080
+    Credentials:
+      apikey: secret
+`), 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.LoadSettings(path); err != nil {
+		t.Fatalf("The YAML settings failed to load: %v", err)
+	}
+	if !c.Active || len(c.Resolvers) != 2 {
+		t.Errorf("The resolvers were not loaded: %v", c.Resolvers)
+	}
+	if len(c.Domains()) != 2 || !c.Blacklisted("education.owasp.org") || len(c.Ports) != 3 {
+		t.Errorf("The scope was not loaded: %v %v", c.Domains(), c.Ports)
+	}
+	if dsc := c.GetDataSourceConfig("Shodan"); dsc.TTL != 10080 || dsc.GetCredentials() == nil ||
+		dsc.GetCredentials().Key != "secret" {
+		t.Errorf("The Shodan settings were not loaded: %v", dsc)
+	}
+	if c.SourceFilter.Include || len(c.SourceFilter.Sources) != 2 {
+		t.Errorf("The disabled data sources were not loaded: %v", c.SourceFilter.Sources)
+	}
+
+	if err := NewConfig().LoadSettings("../examples/config.yaml"); err != nil {
+		t.Errorf("The example YAML file failed to load: %v", err)
+	}
+	if _, err := parseYAMLSettings(ini.LoadOptions{Insensitive: true, AllowShadows: true}, []byte("- resolvers")); err == nil {
+		t.Errorf("The YAML list was accepted as the settings")
+	}
+	if _, err := parseYAMLSettings(ini.LoadOptions{Insensitive: true, AllowShadows: true}, []byte("resolvers: [[8.8.8.8]]")); err == nil {
+		t.Errorf("The nested YAML list was accepted")
+	}
+}
+
+func TestConvertINIToYAML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yaml")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	inipath := filepath.Join(dir, "config.ini")
+	if err := ioutil.WriteFile(inipath, []byte(testINISettings), 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	data, err := ConvertINIToYAML(inipath)
+	if err != nil {
+		t.Fatalf("The conversion failed: %v", err)
+	}
+	if s := string(data); !strings.Contains(s, "disabled:\n        - Ask\n        - Bing") ||
+		!strings.Contains(s, "Shodan:") {
+		t.Errorf("The YAML document does not provide the lists and data sources:\n%s", s)
+	}
+
+	yamlpath := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(yamlpath, data, 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	for _, profile := range []string{"", "stealth"} {
+		fromINI, fromYAML := NewConfig(), NewConfig()
+		fromINI.Profile, fromYAML.Profile = profile, profile
+
+		if err := fromINI.LoadSettings(inipath); err != nil {
+			t.Fatalf("The .ini settings failed to load: %v", err)
+		}
+		if err := fromYAML.LoadSettings(yamlpath); err != nil {
+			t.Fatalf("The converted settings failed to load: %v\n%s", err, data)
+		}
+
+		if !reflect.DeepEqual(sortedStrings(fromINI.Resolvers), sortedStrings(fromYAML.Resolvers)) ||
+			!reflect.DeepEqual(fromINI.DomainResolvers, fromYAML.DomainResolvers) ||
+			!reflect.DeepEqual(sortedStrings(fromINI.Domains()), sortedStrings(fromYAML.Domains())) ||
+			!reflect.DeepEqual(fromINI.Blacklist, fromYAML.Blacklist) ||
+			!reflect.DeepEqual(fromINI.Ports, fromYAML.Ports) ||
+			fromINI.SourceFilter.Include != fromYAML.SourceFilter.Include ||
+			!reflect.DeepEqual(sortedStrings(fromINI.SourceFilter.Sources), sortedStrings(fromYAML.SourceFilter.Sources)) ||
+			fromINI.Active != fromYAML.Active || fromINI.Passive != fromYAML.Passive ||
+			fromINI.DNSBudget != fromYAML.DNSBudget || fromINI.BruteTimeout != fromYAML.BruteTimeout {
+			t.Errorf("The %q profile of the converted settings differs from the .ini settings:\n%s", profile, data)
+		}
+		if fromYAML.GetDataSourceConfig("Shodan").GetCredentials() == nil {
+			t.Errorf("The credentials were not converted:\n%s", data)
+		}
+	}
+}
+
+func sortedStrings(list []string) []string {
+	sort.Strings(list)
+	return list
+}
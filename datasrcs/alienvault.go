@@ -56,7 +56,7 @@ func (a *AlienVault) OnStart() error {
 		a.sys.Config().Log.Printf("%s: API key data was not provided", a.String())
 	}
 
-	a.SetRateLimit(1)
+	setRateLimit(a.sys, a, 1)
 	return nil
 }
 
@@ -68,11 +68,11 @@ func (a *AlienVault) requests() {
 		case in := <-a.Input():
 			switch req := in.(type) {
 			case *requests.DNSRequest:
-				a.CheckRateLimit()
-				a.dnsRequest(context.TODO(), req)
+				checkRateLimit(a.sys, a)
+				a.dnsRequest(sourceContext(a.sys, a), req)
 			case *requests.WhoisRequest:
-				a.CheckRateLimit()
-				a.whoisRequest(context.TODO(), req)
+				checkRateLimit(a.sys, a)
+				a.whoisRequest(sourceContext(a.sys, a), req)
 			}
 		}
 	}
@@ -86,7 +86,7 @@ func (a *AlienVault) dnsRequest(ctx context.Context, req *requests.DNSRequest) {
 	a.sys.Config().Log.Printf("Querying %s for %s subdomains", a.String(), req.Domain)
 	a.executeDNSQuery(ctx, req)
 
-	a.CheckRateLimit()
+	checkRateLimit(a.sys, a)
 	a.executeURLQuery(ctx, req)
 }
 
@@ -206,7 +206,7 @@ func (a *AlienVault) executeURLQuery(ctx context.Context, req *requests.DNSReque
 		pages := int(math.Ceil(float64(m.FullSize) / float64(m.Limit)))
 
 		for cur := m.PageNum + 1; cur <= pages; cur++ {
-			a.CheckRateLimit()
+			checkRateLimit(a.sys, a)
 			pageURL := u + "?page=" + strconv.Itoa(cur)
 			page, err = http.RequestWebPage(ctx, pageURL, nil, headers, nil)
 			if err != nil {
@@ -255,7 +255,7 @@ func extractNamesIPs(urls []avURL, names *stringset.Set, ips *stringset.Set, re
 
 func (a *AlienVault) executeWhoisQuery(ctx context.Context, req *requests.WhoisRequest) {
 	emails := a.queryWhoisForEmails(ctx, req)
-	a.CheckRateLimit()
+	checkRateLimit(a.sys, a)
 
 	newDomains := stringset.New()
 	defer newDomains.Close()
@@ -282,7 +282,7 @@ func (a *AlienVault) executeWhoisQuery(ctx context.Context, req *requests.WhoisR
 				newDomains.Insert(d.Domain)
 			}
 		}
-		a.CheckRateLimit()
+		checkRateLimit(a.sys, a)
 	}
 
 	if newDomains.Len() == 0 {
@@ -48,7 +48,7 @@ func (c *Cloudflare) OnStart() error {
 		c.sys.Config().Log.Printf("%s: API key data was not provided", c.String())
 	}
 
-	c.SetRateLimit(2)
+	setRateLimit(c.sys, c, 2)
 	return nil
 }
 
@@ -60,8 +60,8 @@ func (c *Cloudflare) requests() {
 		case in := <-c.Input():
 			switch req := in.(type) {
 			case *requests.DNSRequest:
-				c.CheckRateLimit()
-				c.dnsRequest(context.TODO(), req)
+				checkRateLimit(c.sys, c)
+				c.dnsRequest(sourceContext(c.sys, c), req)
 			}
 		}
 	}
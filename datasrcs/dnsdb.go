@@ -54,11 +54,12 @@ func (d *DNSDB) OnStart() error {
 		d.sys.Config().Log.Printf("%s: API key data was not provided", d.String())
 	}
 
-	d.SetRateLimit(1)
-	return d.checkConfig()
+	setRateLimit(d.sys, d, 1)
+	return d.CheckConfig()
 }
 
-func (d *DNSDB) checkConfig() error {
+// CheckConfig returns an error when the configuration does not provide the credentials required by the data source.
+func (d *DNSDB) CheckConfig() error {
 	creds := d.sys.Config().GetDataSourceConfig(d.String()).GetCredentials()
 
 	if creds == nil || creds.Key == "" {
@@ -78,8 +79,8 @@ func (d *DNSDB) requests() {
 		case in := <-d.Input():
 			switch req := in.(type) {
 			case *requests.DNSRequest:
-				d.CheckRateLimit()
-				d.dnsRequest(context.TODO(), req)
+				checkRateLimit(d.sys, d)
+				d.dnsRequest(sourceContext(d.sys, d), req)
 			}
 		}
 	}
@@ -94,7 +95,7 @@ func (d *DNSDB) dnsRequest(ctx context.Context, req *requests.DNSRequest) {
 		return
 	}
 
-	numRateLimitChecks(d, 120)
+	numRateLimitChecks(d.sys, d, 120)
 	d.sys.Config().Log.Printf("Querying %s for %s subdomains", d.String(), req.Domain)
 
 	headers := map[string]string{
@@ -53,7 +53,7 @@ func (f *FOFA) OnStart() error {
 		return errors.New(estr)
 	}
 
-	f.SetRateLimit(1)
+	setRateLimit(f.sys, f, 1)
 	return nil
 }
 
@@ -65,8 +65,8 @@ func (f *FOFA) requests() {
 		case in := <-f.Input():
 			switch req := in.(type) {
 			case *requests.DNSRequest:
-				f.CheckRateLimit()
-				f.dnsRequest(context.TODO(), req)
+				checkRateLimit(f.sys, f)
+				f.dnsRequest(sourceContext(f.sys, f), req)
 			}
 		}
 	}
@@ -102,6 +102,6 @@ func (f *FOFA) dnsRequest(ctx context.Context, req *requests.DNSRequest) {
 		for _, res := range results {
 			genNewNameEvent(ctx, f.sys, f, res.Domain)
 		}
-		f.CheckRateLimit()
+		checkRateLimit(f.sys, f)
 	}
 }
@@ -78,7 +78,7 @@ func (n *NetworksDB) OnStart() error {
 		n.hasAPIKey = false
 	}
 
-	n.SetRateLimit(1)
+	setRateLimit(n.sys, n, 1)
 	return nil
 }
 
@@ -90,11 +90,11 @@ func (n *NetworksDB) requests() {
 		case in := <-n.Input():
 			switch req := in.(type) {
 			case *requests.ASNRequest:
-				n.CheckRateLimit()
-				n.asnRequest(context.TODO(), req)
+				checkRateLimit(n.sys, n)
+				n.asnRequest(sourceContext(n.sys, n), req)
 			case *requests.WhoisRequest:
-				n.CheckRateLimit()
-				n.whoisRequest(context.TODO(), req)
+				checkRateLimit(n.sys, n)
+				n.whoisRequest(sourceContext(n.sys, n), req)
 			}
 		}
 	}
@@ -105,7 +105,7 @@ func (n *NetworksDB) asnRequest(ctx context.Context, req *requests.ASNRequest) {
 		return
 	}
 
-	numRateLimitChecks(n, 2)
+	numRateLimitChecks(n.sys, n, 2)
 	if n.hasAPIKey {
 		if req.Address != "" {
 			n.executeAPIASNAddrQuery(ctx, req.Address)
@@ -139,7 +139,7 @@ func (n *NetworksDB) executeASNAddrQuery(ctx context.Context, addr string) {
 		return
 	}
 
-	numRateLimitChecks(n, 3)
+	numRateLimitChecks(n.sys, n, 3)
 	u = networksdbBaseURL + matches[1]
 	page, err = http.RequestWebPage(ctx, u, nil, nil, nil)
 	if err != nil {
@@ -176,7 +176,7 @@ func (n *NetworksDB) getIPURL(addr string) string {
 }
 
 func (n *NetworksDB) executeASNQuery(ctx context.Context, asn int, addr string, netblocks *stringset.Set) {
-	numRateLimitChecks(n, 3)
+	numRateLimitChecks(n.sys, n, 3)
 	u := n.getASNURL(asn)
 	page, err := http.RequestWebPage(ctx, u, nil, nil, nil)
 	if err != nil {
@@ -242,7 +242,7 @@ func (n *NetworksDB) executeAPIASNAddrQuery(ctx context.Context, addr string) {
 		return
 	}
 
-	numRateLimitChecks(n, 3)
+	numRateLimitChecks(n.sys, n, 3)
 	asns := n.apiOrgInfoQuery(ctx, id)
 	if len(asns) == 0 {
 		n.sys.Config().Log.Printf("%s: %s: Failed to obtain ASNs associated with the organization", n.String(), id)
@@ -255,7 +255,7 @@ func (n *NetworksDB) executeAPIASNAddrQuery(ctx context.Context, addr string) {
 	ip := net.ParseIP(addr)
 loop:
 	for _, a := range asns {
-		numRateLimitChecks(n, 3)
+		numRateLimitChecks(n.sys, n, 3)
 		cidrs = n.apiNetblocksQuery(ctx, a)
 		defer cidrs.Close()
 
@@ -311,7 +311,7 @@ func (n *NetworksDB) executeAPIASNQuery(ctx context.Context, asn int, addr strin
 		prefix = netblocks.Slice()[0]
 	}
 
-	numRateLimitChecks(n, 3)
+	numRateLimitChecks(n.sys, n, 3)
 	req := n.apiASNInfoQuery(ctx, asn)
 	if req == nil {
 		n.sys.Config().Log.Printf("%s: %d: Failed to obtain ASN information", n.String(), asn)
@@ -327,7 +327,7 @@ func (n *NetworksDB) executeAPIASNQuery(ctx context.Context, asn int, addr strin
 }
 
 func (n *NetworksDB) apiIPQuery(ctx context.Context, addr string) (string, string) {
-	numRateLimitChecks(n, 3)
+	numRateLimitChecks(n.sys, n, 3)
 	u := n.getAPIIPURL()
 	params := url.Values{"ip": {addr}}
 	body := strings.NewReader(params.Encode())
@@ -368,7 +368,7 @@ func (n *NetworksDB) getAPIIPURL() string {
 }
 
 func (n *NetworksDB) apiOrgInfoQuery(ctx context.Context, id string) []int {
-	numRateLimitChecks(n, 3)
+	numRateLimitChecks(n.sys, n, 3)
 	u := n.getAPIOrgInfoURL()
 	params := url.Values{"id": {id}}
 	body := strings.NewReader(params.Encode())
@@ -404,7 +404,7 @@ func (n *NetworksDB) getAPIOrgInfoURL() string {
 }
 
 func (n *NetworksDB) apiASNInfoQuery(ctx context.Context, asn int) *requests.ASNRequest {
-	numRateLimitChecks(n, 3)
+	numRateLimitChecks(n.sys, n, 3)
 	u := n.getAPIASNInfoURL()
 	params := url.Values{"asn": {strconv.Itoa(asn)}}
 	body := strings.NewReader(params.Encode())
@@ -452,7 +452,7 @@ func (n *NetworksDB) getAPIASNInfoURL() string {
 func (n *NetworksDB) apiNetblocksQuery(ctx context.Context, asn int) *stringset.Set {
 	netblocks := stringset.New()
 
-	numRateLimitChecks(n, 3)
+	numRateLimitChecks(n.sys, n, 3)
 	u := n.getAPINetblocksURL()
 	params := url.Values{"asn": {strconv.Itoa(asn)}}
 	body := strings.NewReader(params.Encode())
@@ -506,7 +506,7 @@ func (n *NetworksDB) whoisRequest(ctx context.Context, req *requests.WhoisReques
 		return
 	}
 
-	numRateLimitChecks(n, 2)
+	numRateLimitChecks(n.sys, n, 2)
 	u := n.getDomainToIPURL(req.Domain)
 	page, err := http.RequestWebPage(ctx, u, nil, nil, nil)
 	if err != nil {
@@ -529,7 +529,7 @@ func (n *NetworksDB) whoisRequest(ctx context.Context, req *requests.WhoisReques
 			continue
 		}
 
-		numRateLimitChecks(n, 3)
+		numRateLimitChecks(n.sys, n, 3)
 		u = networksdbBaseURL + match[1]
 		page, err = http.RequestWebPage(ctx, u, nil, nil, nil)
 		if err != nil {
@@ -548,7 +548,7 @@ func (n *NetworksDB) whoisRequest(ctx context.Context, req *requests.WhoisReques
 			continue
 		}
 
-		numRateLimitChecks(n, 3)
+		numRateLimitChecks(n.sys, n, 3)
 		first, last := amassnet.FirstLast(cidr)
 		u := n.getDomainsInNetworkURL(first.String(), last.String())
 
@@ -65,7 +65,7 @@ func (r *RADb) OnStart() error {
 			}
 		}
 	}
-	r.SetRateLimit(1)
+	setRateLimit(r.sys, r, 1)
 	return nil
 }
 
@@ -95,8 +95,8 @@ func (r *RADb) requests() {
 		case in := <-r.Input():
 			switch req := in.(type) {
 			case *requests.ASNRequest:
-				r.CheckRateLimit()
-				r.asnRequest(context.TODO(), req)
+				checkRateLimit(r.sys, r)
+				r.asnRequest(sourceContext(r.sys, r), req)
 			}
 		}
 	}
@@ -107,7 +107,7 @@ func (r *RADb) asnRequest(ctx context.Context, req *requests.ASNRequest) {
 		return
 	}
 
-	r.CheckRateLimit()
+	checkRateLimit(r.sys, r)
 	if req.Address != "" {
 		r.executeASNAddrQuery(ctx, req.Address)
 		return
@@ -169,7 +169,7 @@ func (r *RADb) executeASNQuery(ctx context.Context, asn int, addr, prefix string
 		return
 	}
 
-	numRateLimitChecks(r, 2)
+	numRateLimitChecks(r.sys, r, 2)
 	url := r.getASNURL("arin", strconv.Itoa(asn))
 	headers := map[string]string{"Content-Type": "application/json"}
 	page, err := http.RequestWebPage(ctx, url, nil, headers, nil)
@@ -210,7 +210,7 @@ func (r *RADb) executeASNQuery(ctx context.Context, asn int, addr, prefix string
 		}
 	}
 
-	numRateLimitChecks(r, 2)
+	numRateLimitChecks(r.sys, r, 2)
 	blocks := stringset.New()
 	defer blocks.Close()
 
@@ -248,7 +248,7 @@ func (r *RADb) getASNURL(registry, asn string) string {
 func (r *RADb) netblocks(ctx context.Context, asn int) *stringset.Set {
 	netblocks := stringset.New()
 
-	numRateLimitChecks(r, 2)
+	numRateLimitChecks(r.sys, r, 2)
 	url := r.getNetblocksURL(strconv.Itoa(asn))
 	headers := map[string]string{"Content-Type": "application/json"}
 	page, err := http.RequestWebPage(ctx, url, nil, headers, nil)
@@ -309,7 +309,7 @@ func (r *RADb) getNetblocksURL(asn string) string {
 }
 
 func (r *RADb) ipToASN(ctx context.Context, cidr string) int {
-	numRateLimitChecks(r, 2)
+	numRateLimitChecks(r.sys, r, 2)
 	if r.addr == "" {
 		msg := resolve.QueryMsg(radbWhoisURL, dns.TypeA)
 		resp, err := r.sys.TrustedResolvers().QueryBlocking(ctx, msg)
@@ -5,8 +5,9 @@
 package scripting
 
 import (
+	"strings"
+
 	"github.com/OWASP/Amass/v3/config"
-	"github.com/caffix/service"
 	lua "github.com/yuin/gopher-lua"
 )
 
@@ -87,6 +88,13 @@ func (s *Script) config(L *lua.LState) int {
 	tb.RawSetString("recursive", lua.LBool(cfg.Recursive))
 	tb.RawSetString("min_for_recursive", lua.LNumber(cfg.MinForRecursive))
 	tb.RawSetString("max_depth", lua.LNumber(cfg.MaxDepth))
+	levels := L.NewTable()
+	for n, level := range cfg.BruteLevels {
+		if level.MinForRecursive >= 0 {
+			levels.RawSetInt(n, lua.LNumber(level.MinForRecursive))
+		}
+	}
+	tb.RawSetString("levels", levels)
 	r.RawSetString("brute_forcing", tb)
 
 	tb = L.NewTable()
@@ -96,6 +104,7 @@ func (s *Script) config(L *lua.LState) int {
 	tb.RawSetString("add_words", lua.LBool(cfg.AddWords))
 	tb.RawSetString("add_numbers", lua.LBool(cfg.AddNumbers))
 	tb.RawSetString("edit_distance", lua.LNumber(cfg.EditDistance))
+	tb.RawSetString("keywords", lua.LBool(len(cfg.AltKeywords) > 0))
 	r.RawSetString("alterations", tb)
 
 	L.Push(r)
@@ -151,12 +160,21 @@ func (s *Script) inScope(L *lua.LState) int {
 	return 1
 }
 
-// Wrapper so that scripts can obtain the brute force wordlist for the current enumeration.
+// Wrapper so that scripts can obtain the brute force wordlist for the current enumeration. The optional
+// name selects the wordlist of the level the name is found at below its root domain.
 func (s *Script) bruteWordlist(L *lua.LState) int {
 	tb := L.NewTable()
 
 	if _, err := extractContext(L.CheckUserData(1)); err == nil {
-		for _, word := range s.sys.Config().Wordlist {
+		var level int
+		cfg := s.sys.Config()
+
+		if name := strings.ToLower(L.OptString(2, "")); name != "" {
+			if domain := cfg.WhichDomain(name); domain != "" {
+				level = len(strings.Split(name, ".")) - len(strings.Split(domain, "."))
+			}
+		}
+		for _, word := range cfg.BruteWordlist(level) {
 			tb.Append(lua.LString(word))
 		}
 	}
@@ -187,21 +205,41 @@ func (s *Script) altWordlist(L *lua.LState) int {
 	return 1
 }
 
+// Wrapper so that scripts can obtain the names generated by the alteration rules for the current enumeration.
+func (s *Script) altRules(L *lua.LState) int {
+	tb := L.NewTable()
+
+	if _, err := extractContext(L.CheckUserData(1)); err == nil {
+		name := strings.ToLower(L.CheckString(2))
+
+		if parts := strings.SplitN(name, ".", 2); len(parts) == 2 {
+			rules, _ := config.ParseAlterationRules(s.sys.Config().AltRules)
+
+			for _, rule := range rules {
+				if label, ok := rule.Apply(parts[0]); ok {
+					tb.Append(lua.LString(label + "." + parts[1]))
+				}
+			}
+		}
+	}
+
+	if tb.Len() > 0 {
+		L.Push(tb)
+	} else {
+		L.Push(lua.LNil)
+	}
+	return 1
+}
+
 // Wrapper so scripts can set the data source rate limit.
 func (s *Script) setRateLimit(L *lua.LState) int {
 	s.seconds = L.CheckInt(1)
 	return 0
 }
 
-func numRateLimitChecks(srv service.Service, num int) {
-	for i := 0; i < num; i++ {
-		srv.CheckRateLimit()
-	}
-}
-
 // Wrapper so scripts can block until past the data source rate limit.
 func (s *Script) checkRateLimit(L *lua.LState) int {
-	numRateLimitChecks(s, s.seconds)
+	s.waitRateLimit(s.seconds)
 	return 0
 }
 
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/caffix/resolve"
 	"github.com/miekg/dns"
 	lua "github.com/yuin/gopher-lua"
@@ -49,7 +50,7 @@ func (s *Script) resolve(L *lua.LState) int {
 	}
 
 	tb := L.NewTable()
-	if ans := resolve.ExtractAnswers(resp); len(ans) > 0 {
+	if ans := resolvers.ExtractAnswers(resp); len(ans) > 0 {
 		if records := resolve.AnswersByType(ans, qtype); len(records) > 0 {
 			for _, rr := range records {
 				entry := L.NewTable()
@@ -82,7 +83,7 @@ func (s *Script) fwdQuery(ctx context.Context, name string, qtype uint16) (*dns.
 	return resp, err
 }
 
-func (s *Script) dnsQuery(ctx context.Context, msg *dns.Msg, r *resolve.Resolvers, attempts int) (*dns.Msg, error) {
+func (s *Script) dnsQuery(ctx context.Context, msg *dns.Msg, r *resolvers.Pool, attempts int) (*dns.Msg, error) {
 	for num := 0; num < attempts; num++ {
 		select {
 		case <-ctx.Done():
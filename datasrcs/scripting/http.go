@@ -136,9 +136,17 @@ func (s *Script) req(ctx context.Context, url, data string, headers map[string]s
 		body = strings.NewReader(data)
 	}
 
-	numRateLimitChecks(s, s.seconds)
-	resp, err := http.RequestWebPage(ctx, url, body, headers, auth)
+	reqCtx := ctx
+	if dsc != nil {
+		if timeout, _ := dsc.Limits(); timeout > 0 {
+			reqCtx = http.WithTimeout(ctx, timeout)
+		}
+	}
+
+	s.waitRateLimit(s.seconds)
+	resp, err := http.RequestWebPage(reqCtx, url, body, headers, auth)
 	if err != nil {
+		s.incErrors()
 		if cfg.Verbose {
 			cfg.Log.Printf("%s: %s: %v", s.String(), url, err)
 		}
@@ -7,9 +7,11 @@ package scripting
 import (
 	"context"
 	"net"
+	"strings"
 	"time"
 
 	amassnet "github.com/OWASP/Amass/v3/net"
+	amassdns "github.com/OWASP/Amass/v3/net/dns"
 	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
@@ -103,6 +105,96 @@ func (s *Script) newAddr(L *lua.LState) int {
 	return 0
 }
 
+// Wrapper so that scripts can send discovered email addresses to Amass.
+func (s *Script) newEmail(L *lua.LState) int {
+	if ctx, err := extractContext(L.CheckUserData(1)); err == nil && !contextExpired(ctx) {
+		email := strings.ToLower(strings.TrimSpace(L.CheckString(2)))
+
+		if parts := strings.Split(email, "@"); len(parts) == 2 && parts[0] != "" {
+			if domain := s.sys.Config().WhichDomain(parts[1]); domain != "" {
+				select {
+				case <-ctx.Done():
+				case <-s.Done():
+				default:
+					s.queue.Append(&requests.EmailRequest{
+						Email:  email,
+						Domain: domain,
+						Tag:    s.SourceType,
+						Source: s.String(),
+					})
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// The time formats accepted for the certificate validity period.
+var certTimeFormats = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// Wrapper so that scripts can send the metadata of observed certificates to Amass.
+func (s *Script) newCert(L *lua.LState) int {
+	if ctx, err := extractContext(L.CheckUserData(1)); err == nil && !contextExpired(ctx) {
+		if params := L.CheckTable(2); params != nil {
+			serial, _ := getStringField(L, params, "serial")
+			issuer, _ := getStringField(L, params, "issuer")
+			if serial == "" || issuer == "" {
+				return 0
+			}
+
+			var domain string
+			var names []string
+			seen := make(map[string]struct{})
+			if tbl, ok := L.GetField(params, "names").(*lua.LTable); ok {
+				tbl.ForEach(func(_, v lua.LValue) {
+					name := strings.ToLower(amassdns.RemoveAsteriskLabel(strings.TrimSpace(v.String())))
+					if _, found := seen[name]; name != "" && !found {
+						seen[name] = struct{}{}
+						names = append(names, name)
+						if d := s.sys.Config().WhichDomain(name); domain == "" && d != "" {
+							domain = d
+						}
+					}
+				})
+			}
+			// Only the certificates covering names in scope are accepted
+			if domain == "" {
+				return 0
+			}
+
+			subject, _ := getStringField(L, params, "subject")
+			before, _ := getStringField(L, params, "not_before")
+			after, _ := getStringField(L, params, "not_after")
+			select {
+			case <-ctx.Done():
+			case <-s.Done():
+			default:
+				s.queue.Append(&requests.CertRequest{
+					Serial:    serial,
+					Issuer:    issuer,
+					Subject:   subject,
+					Names:     names,
+					NotBefore: parseCertTime(before),
+					NotAfter:  parseCertTime(after),
+					Domain:    domain,
+					Tag:       s.SourceType,
+					Source:    s.String(),
+				})
+			}
+		}
+	}
+	return 0
+}
+
+func parseCertTime(s string) time.Time {
+	for _, layout := range certTimeFormats {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // Wrapper so that scripts can send discovered ASNs to Amass.
 func (s *Script) newASN(L *lua.LState) int {
 	if ctx, err := extractContext(L.CheckUserData(1)); err == nil && !contextExpired(ctx) {
@@ -6,6 +6,7 @@ package scripting
 
 import (
 	"testing"
+	"time"
 
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/caffix/stringset"
@@ -105,6 +106,87 @@ func TestNewAddrs(t *testing.T) {
 	}
 }
 
+func TestNewEmails(t *testing.T) {
+	expected := stringset.New("admin@owasp.org", "jeff.foley@owasp.org", "info@mail.owasp.org")
+	defer expected.Close()
+
+	ctx, sys := setupMockScriptEnv(`
+		name="emails"
+		type="testing"
+
+		function vertical(ctx, domain)
+			local emails = {"admin@owasp.org", "Jeff.Foley@OWASP.org",
+				"info@mail.owasp.org", "user@example.com", "nobody"}
+
+			for _, email in ipairs(emails) do
+				new_email(ctx, email)
+			end
+		end
+	`)
+	if ctx == nil || sys == nil {
+		t.Fatal("Failed to initialize the scripting environment")
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	sys.DataSources()[0].Input() <- &requests.DNSRequest{Domain: domain}
+
+	num := expected.Len()
+	for i := 0; i < num; i++ {
+		req := <-sys.DataSources()[0].Output()
+
+		if e, ok := req.(*requests.EmailRequest); !ok || !expected.Has(e.Email) || e.Domain != domain || e.Tag != "testing" || e.Source != "emails" {
+			t.Errorf("Email %d: %v was not found in the list of expected email addresses", i+1, req)
+		} else {
+			expected.Remove(e.Email)
+		}
+	}
+}
+
+func TestNewCerts(t *testing.T) {
+	ctx, sys := setupMockScriptEnv(`
+		name="certs"
+		type="testing"
+
+		function vertical(ctx, domain)
+			new_cert(ctx, {
+				['serial']="03ab",
+				['issuer']="C=US, O=Let's Encrypt, CN=R3",
+				['names']={"example.com"},
+			})
+			new_cert(ctx, {
+				['serial']="04cd",
+				['issuer']="C=US, O=Let's Encrypt, CN=R3",
+				['subject']="owasp.org",
+				['names']={"*.owasp.org", "owasp.org", "www.owasp.org"},
+				['not_before']="2021-03-01T12:00:00",
+				['not_after']="2021-05-30T12:00:00",
+			})
+		end
+	`)
+	if ctx == nil || sys == nil {
+		t.Fatal("Failed to initialize the scripting environment")
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	sys.DataSources()[0].Input() <- &requests.DNSRequest{Domain: domain}
+
+	req := <-sys.DataSources()[0].Output()
+	c, ok := req.(*requests.CertRequest)
+	if !ok || c.Serial != "04cd" || c.Domain != domain || c.Tag != "testing" || c.Source != "certs" {
+		t.Fatalf("The certificate in scope was not returned: %v", req)
+	}
+	if len(c.Names) != 2 || c.Names[0] != "owasp.org" || c.Names[1] != "www.owasp.org" {
+		t.Errorf("The certificate names were not returned: %v", c.Names)
+	}
+	if c.NotAfter.Month() != time.May || c.NotBefore.IsZero() {
+		t.Errorf("The certificate validity period was not parsed: %v", c)
+	}
+}
+
 func TestAssociated(t *testing.T) {
 	expected := map[string]*requests.WhoisRequest{
 		"owasp.org": {
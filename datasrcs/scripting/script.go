@@ -49,6 +49,9 @@ type Script struct {
 	ctx        context.Context
 	cancel     context.CancelFunc
 	queue      queue.Queue
+	statsLock  sync.Mutex
+	errors     int
+	waited     time.Duration
 }
 
 // NewScript returns he object initialized, but not yet started.
@@ -107,6 +110,7 @@ func (s *Script) newLuaState(cfg *config.Config) *lua.LState {
 	L.SetGlobal("datasrc_config", L.NewFunction(s.dataSourceConfig))
 	L.SetGlobal("brute_wordlist", L.NewFunction(s.bruteWordlist))
 	L.SetGlobal("alt_wordlist", L.NewFunction(s.altWordlist))
+	L.SetGlobal("alt_rules", L.NewFunction(s.altRules))
 	L.SetGlobal("log", L.NewFunction(s.log))
 	L.SetGlobal("find", L.NewFunction(s.find))
 	L.SetGlobal("submatch", L.NewFunction(s.submatch))
@@ -115,6 +119,8 @@ func (s *Script) newLuaState(cfg *config.Config) *lua.LState {
 	L.SetGlobal("send_names", L.NewFunction(s.sendNames))
 	L.SetGlobal("new_addr", L.NewFunction(s.newAddr))
 	L.SetGlobal("new_asn", L.NewFunction(s.newASN))
+	L.SetGlobal("new_email", L.NewFunction(s.newEmail))
+	L.SetGlobal("new_cert", L.NewFunction(s.newCert))
 	L.SetGlobal("associated", L.NewFunction(s.associated))
 	L.SetGlobal("in_scope", L.NewFunction(s.inScope))
 	L.SetGlobal("request", L.NewFunction(s.request))
@@ -198,7 +204,15 @@ func (s *Script) OnStart() error {
 	if s.seconds > 0 {
 		s.SetRateLimit(1)
 	}
-	return s.checkConfig()
+	// The delay provided by the configuration overrides the rate limit selected by the script
+	if _, delay := s.sys.Config().GetDataSourceConfig(s.String()).Limits(); delay >= time.Second {
+		s.seconds = int((delay + time.Second - 1) / time.Second)
+		s.SetRateLimit(1)
+	} else if delay > 0 {
+		s.seconds = 1
+		s.SetRateLimit(int(time.Second / delay))
+	}
+	return s.CheckConfig()
 }
 
 // OnStop implements the Service interface.
@@ -225,7 +239,8 @@ func (s *Script) OnStop() error {
 	return err
 }
 
-func (s *Script) checkConfig() error {
+// CheckConfig returns an error when the check callback of the script rejects the configuration.
+func (s *Script) CheckConfig() error {
 	L := s.luaState
 
 	if s.cbs.Check.Type() == lua.LTNil {
@@ -341,32 +356,32 @@ func (s *Script) dispatch(in interface{}) {
 	switch req := in.(type) {
 	case *requests.DNSRequest:
 		if s.cbs.Vertical.Type() != lua.LTNil && req != nil && req.Domain != "" {
-			s.CheckRateLimit()
+			s.waitRateLimit(1)
 			s.dnsRequest(s.ctx, req)
 		}
 	case *requests.ResolvedRequest:
 		if s.cbs.Resolved.Type() != lua.LTNil && req != nil && req.Name != "" && len(req.Records) > 0 {
-			s.CheckRateLimit()
+			s.waitRateLimit(1)
 			s.resolvedRequest(s.ctx, req)
 		}
 	case *requests.SubdomainRequest:
 		if s.cbs.Subdomain.Type() != lua.LTNil && req != nil && req.Name != "" {
-			s.CheckRateLimit()
+			s.waitRateLimit(1)
 			s.subdomainRequest(s.ctx, req)
 		}
 	case *requests.AddrRequest:
 		if s.cbs.Address.Type() != lua.LTNil && req != nil && req.Address != "" {
-			s.CheckRateLimit()
+			s.waitRateLimit(1)
 			s.addrRequest(s.ctx, req)
 		}
 	case *requests.ASNRequest:
 		if s.cbs.Asn.Type() != lua.LTNil && req != nil && (req.Address != "" || req.ASN != 0) {
-			s.CheckRateLimit()
+			s.waitRateLimit(1)
 			s.asnRequest(s.ctx, req)
 		}
 	case *requests.WhoisRequest:
 		if s.cbs.Horizontal.Type() != lua.LTNil {
-			s.CheckRateLimit()
+			s.waitRateLimit(1)
 			s.whoisRequest(s.ctx, req)
 		}
 	}
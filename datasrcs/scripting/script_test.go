@@ -5,11 +5,14 @@
 package scripting
 
 import (
+	"testing"
+	"time"
+
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/netmap"
-	"github.com/caffix/resolve"
 	"github.com/caffix/service"
 )
 
@@ -27,8 +30,8 @@ func setupMockScriptEnv(script string) (service.Service, systems.System) {
 func newMockSystem(cfg *config.Config) systems.System {
 	ss := &systems.SimpleSystem{
 		Cfg:      cfg,
-		Pool:     resolve.NewResolvers(),
-		Trusted:  resolve.NewResolvers(),
+		Pool:     resolvers.NewPool(),
+		Trusted:  resolvers.NewPool(),
 		Graph:    netmap.NewGraph(netmap.NewCayleyGraphMemory()),
 		ASNCache: requests.NewASNCache(),
 	}
@@ -39,3 +42,39 @@ func newMockSystem(cfg *config.Config) systems.System {
 	_ = ss.Trusted.AddResolvers(30, "8.8.8.8")
 	return ss
 }
+
+func TestConfiguredDelay(t *testing.T) {
+	sys := newMockSystem(config.NewConfig())
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(`
+		name="delay"
+		type="api"
+
+		function start()
+			set_rate_limit(5)
+		end
+	`, sys)
+	if s == nil {
+		t.Fatal("Failed to create the script")
+	}
+	defer func() { _ = s.OnStop() }()
+
+	dsc := sys.Config().GetDataSourceConfig("delay")
+	for _, test := range []struct {
+		delay   time.Duration
+		seconds int
+	}{
+		{0, 5},
+		{2500 * time.Millisecond, 3},
+		{250 * time.Millisecond, 1},
+	} {
+		dsc.Delay = test.delay
+		if err := s.OnStart(); err != nil {
+			t.Fatalf("Failed to start the script: %v", err)
+		}
+		if s.seconds != test.seconds {
+			t.Errorf("The %v delay waited %d seconds instead of %d", test.delay, s.seconds, test.seconds)
+		}
+	}
+}
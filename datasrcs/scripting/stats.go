@@ -0,0 +1,40 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import "time"
+
+// Stats returns the number of failed requests made by the script and the
+// total time spent waiting on the data source rate limit.
+func (s *Script) Stats() (int, time.Duration) {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+
+	return s.errors, s.waited
+}
+
+func (s *Script) incErrors() {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+
+	s.errors++
+}
+
+// Blocks until the rate limit has been checked num times, while tracking the time spent waiting.
+func (s *Script) waitRateLimit(num int) {
+	if num <= 0 {
+		return
+	}
+
+	start := time.Now()
+	for i := 0; i < num; i++ {
+		s.CheckRateLimit()
+	}
+
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+
+	s.waited += time.Since(start)
+}
@@ -6,16 +6,24 @@ package datasrcs
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/datasrcs/scripting"
+	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/service"
 	"github.com/caffix/stringset"
 )
 
+// The name global of the data source scripts, used to identify the scripts that fail to load.
+var scriptNameRE = regexp.MustCompile(`(?m)^name\s*=\s*"([^"]+)"`)
+
 // GetAllSources returns a slice of all data source services, initialized and ready.
 func GetAllSources(sys systems.System) []service.Service {
 	srvs := []service.Service{
@@ -74,6 +82,53 @@ func SelectedDataSources(cfg *config.Config, avail []service.Service) []service.
 	return results
 }
 
+// CheckDataSourceConfigs returns the problems of the data source sections of the configuration: the
+// sections and the disabled data sources that do not match the name of a data source, the scripts that
+// fail to load, and the configured data sources that lack the credentials they require.
+func CheckDataSourceConfigs(sys systems.System) []error {
+	var errs []error
+	// The configurations are obtained before the data sources request their own
+	configured := sys.Config().DataSourceConfigs()
+
+	if scripts, err := sys.Config().AcquireScripts(); err == nil {
+		for _, script := range scripts {
+			if s := scripting.NewScript(script, sys); s == nil {
+				name := "unnamed"
+				if m := scriptNameRE.FindStringSubmatch(script); m != nil {
+					name = m[1]
+				}
+				errs = append(errs, fmt.Errorf("the %s data source script failed to load", name))
+			}
+		}
+	}
+
+	srcs := make(map[string]service.Service)
+	for _, src := range GetAllSources(sys) {
+		srcs[strings.ToLower(src.String())] = src
+	}
+
+	for _, dsc := range configured {
+		src, found := srcs[dsc.Name]
+		if !found {
+			errs = append(errs, fmt.Errorf("the data_sources.%s section does not match the name of a data source", dsc.Name))
+			continue
+		}
+		// The credentials of the secrets backend may not have been retrieved
+		if dsc.SecretPath != "" && dsc.GetCredentials() == nil {
+			continue
+		}
+		if c, ok := src.(interface{ CheckConfig() error }); ok && c.CheckConfig() != nil {
+			errs = append(errs, fmt.Errorf("the %s data source is configured without the credentials it requires", src.String()))
+		}
+	}
+	for _, name := range sys.Config().SourceFilter.Sources {
+		if _, found := srcs[strings.ToLower(name)]; !found {
+			errs = append(errs, fmt.Errorf("the %s data source in data_sources.disabled does not exist", name))
+		}
+	}
+	return errs
+}
+
 func genNewNameEvent(ctx context.Context, sys systems.System, srv service.Service, name string) {
 	if domain := sys.Config().WhichDomain(name); domain != "" {
 		srv.Output() <- &requests.DNSRequest{
@@ -85,7 +140,39 @@ func genNewNameEvent(ctx context.Context, sys systems.System, srv service.Servic
 	}
 }
 
-func numRateLimitChecks(srv service.Service, num int) {
+// Returns the context of the requests made by the data source, which applies the HTTP timeout
+// provided by the configuration.
+func sourceContext(sys systems.System, srv service.Service) context.Context {
+	ctx := context.Background()
+
+	if timeout, _ := sys.Config().GetDataSourceConfig(srv.String()).Limits(); timeout > 0 {
+		ctx = http.WithTimeout(ctx, timeout)
+	}
+	return ctx
+}
+
+// Sets the number of requests per second of the data source, unless the configuration
+// provides the minimum delay between its requests.
+func setRateLimit(sys systems.System, srv service.Service, persec int) {
+	if _, delay := sys.Config().GetDataSourceConfig(srv.String()).Limits(); delay > 0 {
+		if persec = int(time.Second / delay); persec < 1 {
+			persec = 1
+		}
+	}
+	srv.SetRateLimit(persec)
+}
+
+func checkRateLimit(sys systems.System, srv service.Service) {
+	numRateLimitChecks(sys, srv, 1)
+}
+
+// Each check waits a second at most, so the checks are repeated when the
+// delay provided by the configuration is longer.
+func numRateLimitChecks(sys systems.System, srv service.Service, num int) {
+	if _, delay := sys.Config().GetDataSourceConfig(srv.String()).Limits(); delay > time.Second {
+		num *= int((delay + time.Second - 1) / time.Second)
+	}
+
 	for i := 0; i < num; i++ {
 		srv.CheckRateLimit()
 	}
@@ -64,12 +64,12 @@ func (t *Twitter) OnStart() error {
 		}
 	}
 
-	t.SetRateLimit(1)
-	return t.checkConfig()
+	setRateLimit(t.sys, t, 1)
+	return t.CheckConfig()
 }
 
-// CheckConfig implements the Service interface.
-func (t *Twitter) checkConfig() error {
+// CheckConfig returns an error when the configuration does not provide the credentials required by the data source.
+func (t *Twitter) CheckConfig() error {
 	creds := t.sys.Config().GetDataSourceConfig(t.String()).GetCredentials()
 
 	if creds == nil || creds.Key == "" || creds.Secret == "" {
@@ -89,8 +89,8 @@ func (t *Twitter) requests() {
 		case in := <-t.Input():
 			switch req := in.(type) {
 			case *requests.DNSRequest:
-				t.CheckRateLimit()
-				t.dnsRequest(context.TODO(), req)
+				checkRateLimit(t.sys, t)
+				t.dnsRequest(sourceContext(t.sys, t), req)
 			}
 		}
 	}
@@ -102,7 +102,7 @@ func (t *Twitter) dnsRequest(ctx context.Context, req *requests.DNSRequest) {
 		return
 	}
 
-	numRateLimitChecks(t, 2)
+	numRateLimitChecks(t.sys, t, 2)
 	t.sys.Config().Log.Printf("Querying %s for %s subdomains", t.String(), req.Domain)
 
 	searchParams := &twitter.SearchTweetParams{
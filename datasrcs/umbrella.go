@@ -56,12 +56,12 @@ func (u *Umbrella) OnStart() error {
 		u.sys.Config().Log.Printf("%s: API key data was not provided", u.String())
 	}
 
-	u.SetRateLimit(2)
-	return u.checkConfig()
+	setRateLimit(u.sys, u, 2)
+	return u.CheckConfig()
 }
 
-// CheckConfig implements the Service interface.
-func (u *Umbrella) checkConfig() error {
+// CheckConfig returns an error when the configuration does not provide the credentials required by the data source.
+func (u *Umbrella) CheckConfig() error {
 	creds := u.sys.Config().GetDataSourceConfig(u.String()).GetCredentials()
 
 	if creds == nil || creds.Key == "" {
@@ -81,17 +81,17 @@ func (u *Umbrella) requests() {
 		case in := <-u.Input():
 			switch req := in.(type) {
 			case *requests.DNSRequest:
-				u.CheckRateLimit()
-				u.dnsRequest(context.TODO(), req)
+				checkRateLimit(u.sys, u)
+				u.dnsRequest(sourceContext(u.sys, u), req)
 			case *requests.AddrRequest:
-				u.CheckRateLimit()
-				u.addrRequest(context.TODO(), req)
+				checkRateLimit(u.sys, u)
+				u.addrRequest(sourceContext(u.sys, u), req)
 			case *requests.ASNRequest:
-				u.CheckRateLimit()
-				u.asnRequest(context.TODO(), req)
+				checkRateLimit(u.sys, u)
+				u.asnRequest(sourceContext(u.sys, u), req)
 			case *requests.WhoisRequest:
-				u.CheckRateLimit()
-				u.whoisRequest(context.TODO(), req)
+				checkRateLimit(u.sys, u)
+				u.whoisRequest(sourceContext(u.sys, u), req)
 			}
 		}
 	}
@@ -225,7 +225,7 @@ func (u *Umbrella) executeASNAddrQuery(ctx context.Context, req *requests.ASNReq
 	if len(req.Netblocks) == 0 {
 		req.Netblocks = []string{strings.TrimSpace(req.Prefix)}
 
-		u.CheckRateLimit()
+		checkRateLimit(u.sys, u)
 		u.executeASNQuery(ctx, req)
 	}
 
@@ -266,7 +266,7 @@ func (u *Umbrella) executeASNQuery(ctx context.Context, req *requests.ASNRequest
 			req.Address = addr.String()
 			req.CC = netblock[0].Geo.CountryCode
 
-			u.CheckRateLimit()
+			checkRateLimit(u.sys, u)
 			u.executeASNAddrQuery(ctx, req)
 			return
 		}
@@ -332,7 +332,7 @@ func (u *Umbrella) queryWhois(ctx context.Context, domain string) *whoisRecord {
 	headers := u.restHeaders()
 	whoisURL := u.whoisRecordURL(domain)
 
-	u.CheckRateLimit()
+	checkRateLimit(u.sys, u)
 	record, err := http.RequestWebPage(ctx, whoisURL, nil, headers, nil)
 	if err != nil {
 		u.sys.Config().Log.Printf("%s: %s: %v", u.String(), whoisURL, err)
@@ -355,7 +355,7 @@ func (u *Umbrella) queryReverseWhois(ctx context.Context, apiURL string) []strin
 	var whois map[string]rWhoisResponse
 	// Umbrella provides data in 500 piece chunks
 	for count, more := 0, true; more; count = count + 500 {
-		u.CheckRateLimit()
+		checkRateLimit(u.sys, u)
 		fullAPIURL := fmt.Sprintf("%s&offset=%d", apiURL, count)
 		record, err := http.RequestWebPage(ctx, fullAPIURL, nil, headers, nil)
 		if err != nil {
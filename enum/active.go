@@ -5,14 +5,18 @@
 package enum
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	amassdns "github.com/OWASP/Amass/v3/net/dns"
 	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/OWASP/Amass/v3/resources"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
 	"github.com/caffix/resolve"
@@ -76,6 +80,10 @@ func (a *activeTask) Process(ctx context.Context, data pipeline.Data, tp pipelin
 	}
 
 	if ok {
+		// The pipeline is held while the active techniques fall behind
+		if !a.waitForCapacity(ctx) {
+			return nil, nil
+		}
 		a.queue.Append(&taskArgs{
 			Ctx:    ctx,
 			Data:   data.Clone(),
@@ -85,6 +93,22 @@ func (a *activeTask) Process(ctx context.Context, data pipeline.Data, tp pipelin
 	return data, nil
 }
 
+func (a *activeTask) waitForCapacity(ctx context.Context) bool {
+	t := time.NewTicker(100 * time.Millisecond)
+	defer t.Stop()
+
+	for a.queue.Len() >= maxActiveQueuedTasks {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-a.enum.done:
+			return false
+		case <-t.C:
+		}
+	}
+	return true
+}
+
 func (a *activeTask) processQueue() {
 	for {
 		select {
@@ -132,7 +156,6 @@ func (a *activeTask) crawlName(ctx context.Context, req *requests.DNSRequest, tp
 	}
 
 	cfg := a.enum.Config
-	var protocol string
 	for _, port := range cfg.Ports {
 		select {
 		case <-ctx.Done():
@@ -140,17 +163,15 @@ func (a *activeTask) crawlName(ctx context.Context, req *requests.DNSRequest, tp
 		default:
 		}
 
-		if port == 80 {
-			protocol = "http://"
-		} else if strings.HasSuffix(strconv.Itoa(port), "443") {
-			protocol = "https://"
-		} else if _, err := http.TLSConn(ctx, req.Name, port); err != nil {
-			protocol = "http://"
-		} else {
-			protocol = "https://"
+		u := serviceURL(ctx, req.Name, port)
+		if fp, err := http.FingerprintService(ctx, u); err == nil {
+			if err := a.enum.insertHTTPService(ctx, req.Name, fp, "Active Crawl"); err != nil {
+				cfg.Log.Print(err.Error())
+			}
+		} else if cfg.Verbose {
+			cfg.Log.Printf("Active Crawl: %v", err)
 		}
 
-		u := protocol + req.Name + ":" + strconv.Itoa(port)
 		names, err := http.Crawl(ctx, u, cfg.Domains(), 50)
 		if err != nil {
 			if cfg.Verbose {
@@ -174,6 +195,19 @@ func (a *activeTask) crawlName(ctx context.Context, req *requests.DNSRequest, tp
 	}
 }
 
+// Returns the URL of the web service on the port, using TLS when the port is expected to accept it.
+func serviceURL(ctx context.Context, name string, port int) string {
+	protocol := "http://"
+	if strings.HasSuffix(strconv.Itoa(port), "443") {
+		protocol = "https://"
+	} else if port != 80 {
+		if _, err := http.TLSConn(ctx, name, port); err == nil {
+			protocol = "https://"
+		}
+	}
+	return protocol + name + ":" + strconv.Itoa(port)
+}
+
 func (a *activeTask) certEnumeration(ctx context.Context, req *requests.AddrRequest, tp pipeline.TaskParams) {
 	defer func() { a.tokenPool <- struct{}{} }()
 
@@ -181,23 +215,44 @@ func (a *activeTask) certEnumeration(ctx context.Context, req *requests.AddrRequ
 		return
 	}
 
-	for _, name := range http.PullCertificateNames(ctx, req.Address, a.enum.Config.Ports) {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
+	for _, cert := range http.PullCertificates(ctx, req.Address, a.enum.Config.Ports) {
+		domain := req.Domain
+		names := http.CertificateNames(cert)
 
-		if n := strings.TrimSpace(name); n != "" {
-			if domain := a.enum.Config.WhichDomain(n); domain != "" {
-				a.enum.nameSrc.newName(&requests.DNSRequest{
-					Name:   n,
-					Domain: domain,
-					Tag:    requests.CERT,
-					Source: "Active Cert",
-				})
+		for _, name := range names {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			if n := strings.TrimSpace(name); n != "" {
+				if d := a.enum.Config.WhichDomain(n); d != "" {
+					if domain == "" {
+						domain = d
+					}
+					a.enum.nameSrc.newName(&requests.DNSRequest{
+						Name:   n,
+						Domain: d,
+						Tag:    requests.CERT,
+						Source: "Active Cert",
+					})
+				}
 			}
 		}
+
+		a.enum.nameSrc.newCert(&requests.CertRequest{
+			Serial:    cert.SerialNumber.Text(16),
+			Issuer:    cert.Issuer.String(),
+			Subject:   cert.Subject.CommonName,
+			Names:     names,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			Address:   req.Address,
+			Domain:    domain,
+			Tag:       requests.CERT,
+			Source:    "Active Cert",
+		})
 	}
 }
 
@@ -208,7 +263,7 @@ func (a *activeTask) zoneTransfer(ctx context.Context, req *requests.ZoneXFRRequ
 	default:
 	}
 
-	addr, err := a.nameserverAddr(ctx, req.Server)
+	addr, err := a.enum.nameserverAddr(ctx, req.Server)
 	if addr == "" {
 		a.enum.Config.Log.Printf("DNS: Zone XFR failed: %v", err)
 		return
@@ -239,45 +294,73 @@ func (a *activeTask) zoneTransfer(ctx context.Context, req *requests.ZoneXFRRequ
 func (a *activeTask) zoneWalk(ctx context.Context, req *requests.ZoneXFRRequest, tp pipeline.TaskParams) {
 	defer func() { a.tokenPool <- struct{}{} }()
 
-	addr, err := a.nameserverAddr(ctx, req.Server)
+	cfg := a.enum.Config
+	addr, err := a.enum.nameserverAddr(ctx, req.Server)
 	if addr == "" {
-		a.enum.Config.Log.Printf("DNS: Zone Walk failed: %v", err)
+		cfg.Log.Printf("DNS: Zone Walk failed: %v", err)
 		return
 	}
 
-	r := resolve.NewResolvers()
-	r.SetLogger(a.enum.Config.Log)
-	_ = r.AddResolvers(5, addr)
-	defer r.Stop()
+	p := resolvers.NewPool()
+	p.SetLogger(cfg.Log)
+	_ = p.AddResolvers(5, addr)
+	defer p.Stop()
 
-	names, err := r.NsecTraversal(ctx, req.Name)
+	walk, err := resolvers.WalkZone(ctx, p, req.Name)
 	if err != nil {
-		a.enum.Config.Log.Printf("DNS: Zone Walk failed: %s: %v", req.Name, err)
-		return
+		cfg.Log.Printf("DNS: Zone Walk failed: %s: %v", req.Name, err)
+		if walk == nil {
+			return
+		}
 	}
 
-	for _, nsec := range names {
-		name := resolve.RemoveLastDot(nsec.NextDomain)
+	names, source := walk.Names, "NSEC Walk"
+	if walk.NSEC3 != nil {
+		// The hashes collected from the zone are cracked offline using the brute forcing wordlist
+		names, source = walk.NSEC3.Crack(a.nsec3Wordlist()), "NSEC3 Walk"
+		cfg.Log.Printf("DNS: NSEC3 Walk: %s: cracked %d of %d hashes", req.Name, len(names), walk.NSEC3.Len())
+	}
 
-		if domain := a.enum.Config.WhichDomain(name); domain != "" {
+	for _, name := range names {
+		if domain := cfg.WhichDomain(name); domain != "" {
 			a.enum.nameSrc.newName(&requests.DNSRequest{
 				Name:   name,
 				Domain: domain,
 				Tag:    requests.DNS,
-				Source: "NSEC Walk",
+				Source: source,
 			})
 		}
 	}
 }
 
-func (a *activeTask) nameserverAddr(ctx context.Context, server string) (string, error) {
+func (a *activeTask) nsec3Wordlist() []string {
+	if words := a.enum.Config.Wordlist; len(words) > 0 {
+		return words
+	}
+
+	f, err := resources.GetResourceFile("namelist.txt")
+	if err != nil {
+		return nil
+	}
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if w := strings.TrimSpace(scanner.Text()); w != "" && !strings.HasPrefix(w, "#") {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+func (e *Enumeration) nameserverAddr(ctx context.Context, server string) (string, error) {
 	var err error
 	var found bool
 	var qtype uint16
 	var resp *dns.Msg
 
 	for _, t := range []uint16{dns.TypeA, dns.TypeAAAA} {
-		resp, err = a.enum.fwdQuery(ctx, server, t)
+		resp, err = e.fwdQuery(ctx, server, t)
 
 		if err == nil && resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0 {
 			qtype = t
@@ -0,0 +1,384 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/pipeline"
+)
+
+const checkpointFileName = "checkpoint.json"
+
+// The names handed to the pipeline within this period could still be in progress,
+// so they are saved with the pending names.
+const checkpointInflight = 2 * time.Minute
+
+// Checkpoint is the state of an enumeration saved periodically to the output directory,
+// so an interrupted enumeration can be resumed without repeating the completed work.
+type Checkpoint struct {
+	UUID    string    `json:"uuid"`
+	Domains []string  `json:"domains"`
+	Started time.Time `json:"started"`
+	Saved   time.Time `json:"saved"`
+	// The names and addresses accepted by the enumeration that were not processed yet
+	Pending []*CheckpointRequest `json:"pending"`
+	// The names in scope that were resolved, which are resolved again to rebuild the findings
+	Resolved []*CheckpointRequest `json:"resolved"`
+	// The domain and ASN requests completed by each data source
+	Sources map[string][]string `json:"sources"`
+	// The number of generated names counted against the guess budget
+	Guesses int `json:"guesses"`
+	// The filter of the names and addresses already accepted, which prevents the names
+	// generated again by brute forcing and alterations from being resolved again
+	Filter []byte `json:"filter"`
+}
+
+// CheckpointRequest is a name or address saved in the checkpoint.
+type CheckpointRequest struct {
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+	Domain  string `json:"domain"`
+	Tag     string `json:"tag"`
+	Source  string `json:"source"`
+}
+
+// CheckpointPath returns the path of the checkpoint file within the output directory.
+func CheckpointPath(cfg *config.Config) string {
+	return filepath.Join(config.OutputDirectory(cfg.Dir), checkpointFileName)
+}
+
+// LoadCheckpoint reads the checkpoint saved by an interrupted enumeration.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// Writes the checkpoint to a temporary file that replaces the previous checkpoint,
+// so an interruption while writing does not leave a truncated checkpoint behind.
+func saveCheckpoint(path string, cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+type inflightRequest struct {
+	req     *CheckpointRequest
+	entered time.Time
+}
+
+// checkpointTracker follows the progress of the enumeration saved in the checkpoints.
+type checkpointTracker struct {
+	sync.Mutex
+	started  time.Time
+	queued   map[string]*CheckpointRequest
+	inflight map[string]*inflightRequest
+	resolved map[string]*CheckpointRequest
+	// The requests completed by each data source and the last request each one accepted
+	sources  map[string]map[string]struct{}
+	accepted map[string]string
+}
+
+func newCheckpointTracker(cp *Checkpoint) *checkpointTracker {
+	t := &checkpointTracker{
+		started:  time.Now(),
+		queued:   make(map[string]*CheckpointRequest),
+		inflight: make(map[string]*inflightRequest),
+		resolved: make(map[string]*CheckpointRequest),
+		sources:  make(map[string]map[string]struct{}),
+		accepted: make(map[string]string),
+	}
+
+	if cp != nil {
+		t.started = cp.Started
+		for _, req := range cp.Resolved {
+			t.resolved[req.Name] = req
+		}
+		for src, keys := range cp.Sources {
+			t.sources[src] = make(map[string]struct{})
+			for _, key := range keys {
+				t.sources[src][key] = struct{}{}
+			}
+		}
+	}
+	return t
+}
+
+// Converts the names and addresses into the requests saved in the checkpoint.
+func checkpointRequest(data interface{}) (string, *CheckpointRequest) {
+	switch req := data.(type) {
+	case *requests.DNSRequest:
+		return "name:" + req.Name, &CheckpointRequest{
+			Name:   req.Name,
+			Domain: req.Domain,
+			Tag:    req.Tag,
+			Source: req.Source,
+		}
+	case *requests.AddrRequest:
+		return "addr:" + req.Address, &CheckpointRequest{
+			Address: req.Address,
+			Domain:  req.Domain,
+			Tag:     req.Tag,
+			Source:  req.Source,
+		}
+	}
+	return "", nil
+}
+
+// Converts the saved name or address back into the request processed by the pipeline.
+func (req *CheckpointRequest) request() pipeline.Data {
+	if req.Name != "" {
+		return &requests.DNSRequest{
+			Name:   req.Name,
+			Domain: req.Domain,
+			Tag:    req.Tag,
+			Source: req.Source,
+		}
+	}
+
+	return &requests.AddrRequest{
+		Address: req.Address,
+		InScope: true,
+		Domain:  req.Domain,
+		Tag:     req.Tag,
+		Source:  req.Source,
+	}
+}
+
+// Returns the key identifying the requests of the data sources that are not repeated
+// after resuming, which are the requests for the root domain names and the ASNs.
+func sourceRequestKey(data interface{}) string {
+	switch req := data.(type) {
+	case *requests.DNSRequest:
+		if req.Name == req.Domain {
+			return "domain:" + req.Domain
+		}
+	case *requests.ASNRequest:
+		if req.Address == "" {
+			return "asn:" + strconv.Itoa(req.ASN)
+		}
+	}
+	return ""
+}
+
+func (t *checkpointTracker) queue(data interface{}) {
+	if t == nil {
+		return
+	}
+
+	if key, req := checkpointRequest(data); req != nil {
+		t.Lock()
+		t.queued[key] = req
+		t.Unlock()
+	}
+}
+
+// Moves the name or address from the queue to the requests being processed by the pipeline.
+func (t *checkpointTracker) enter(data interface{}) {
+	if t == nil {
+		return
+	}
+
+	key, req := checkpointRequest(data)
+	if req == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	delete(t.queued, key)
+	t.inflight[key] = &inflightRequest{req: req, entered: time.Now()}
+}
+
+func (t *checkpointTracker) resolve(req *requests.DNSRequest) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.resolved[req.Name] = &CheckpointRequest{
+		Name:   req.Name,
+		Domain: req.Domain,
+		Tag:    req.Tag,
+		Source: req.Source,
+	}
+}
+
+// Records the request accepted by the data source. The data sources accept the next
+// request after finishing the previous one, which is then recorded as completed.
+func (t *checkpointTracker) sourceAccepted(src string, data interface{}) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if prev := t.accepted[src]; prev != "" {
+		if _, found := t.sources[src]; !found {
+			t.sources[src] = make(map[string]struct{})
+		}
+		t.sources[src][prev] = struct{}{}
+	}
+	t.accepted[src] = sourceRequestKey(data)
+}
+
+// Returns true when the data source completed the request before the enumeration was resumed.
+func (t *checkpointTracker) sourceCompleted(src string, data interface{}) bool {
+	if t == nil {
+		return false
+	}
+
+	key := sourceRequestKey(data)
+	if key == "" {
+		return false
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	_, found := t.sources[src][key]
+	return found
+}
+
+// Returns the checkpoint of the progress tracked, which the enumeration completes with its own state.
+func (t *checkpointTracker) checkpoint() *Checkpoint {
+	t.Lock()
+	defer t.Unlock()
+
+	cp := &Checkpoint{
+		Started: t.started,
+		Saved:   time.Now(),
+		Sources: make(map[string][]string),
+	}
+
+	for _, req := range t.queued {
+		cp.Pending = append(cp.Pending, req)
+	}
+	for key, in := range t.inflight {
+		// The requests processed long ago are already represented by the filter
+		if cp.Saved.Sub(in.entered) > checkpointInflight {
+			delete(t.inflight, key)
+			continue
+		}
+		cp.Pending = append(cp.Pending, in.req)
+	}
+	for _, req := range t.resolved {
+		cp.Resolved = append(cp.Resolved, req)
+	}
+	for src, keys := range t.sources {
+		for key := range keys {
+			cp.Sources[src] = append(cp.Sources[src], key)
+		}
+		sort.Strings(cp.Sources[src])
+	}
+
+	sortCheckpointRequests(cp.Pending)
+	sortCheckpointRequests(cp.Resolved)
+	return cp
+}
+
+func sortCheckpointRequests(reqs []*CheckpointRequest) {
+	sort.Slice(reqs, func(i, j int) bool {
+		if reqs[i].Name != reqs[j].Name {
+			return reqs[i].Name < reqs[j].Name
+		}
+		return reqs[i].Address < reqs[j].Address
+	})
+}
+
+// Resume continues the enumeration from the checkpoint when the enumeration is started.
+// The configuration is expected to use the UUID and domains of the checkpoint.
+func (e *Enumeration) Resume(cp *Checkpoint) {
+	e.resume = cp
+}
+
+// Saves the checkpoints until the enumeration is finished.
+func (e *Enumeration) manageCheckpoints() {
+	t := time.NewTicker(e.Config.CheckpointInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-e.ctx.Done():
+			return
+		case <-t.C:
+			e.saveCheckpoint()
+		}
+	}
+}
+
+func (e *Enumeration) saveCheckpoint() {
+	cp := e.checkpoints.checkpoint()
+	cp.UUID = e.Config.UUID.String()
+	cp.Domains = e.Config.Domains()
+	cp.Guesses = e.nameSrc.guessCount()
+
+	var buf bytes.Buffer
+	if err := e.nameSrc.saveFilter(&buf); err == nil {
+		cp.Filter = buf.Bytes()
+	}
+
+	if err := saveCheckpoint(CheckpointPath(e.Config), cp); err != nil {
+		e.Config.Log.Printf("Failed to save the checkpoint: %v", err)
+	}
+}
+
+// Brings the names and addresses of the checkpoint back into the enumeration.
+func (e *Enumeration) resumeCheckpoint(cp *Checkpoint) {
+	if len(cp.Filter) > 0 {
+		if err := e.nameSrc.loadFilter(bytes.NewReader(cp.Filter)); err != nil {
+			e.Config.Log.Printf("Failed to restore the names of the checkpoint: %v", err)
+		}
+	}
+	e.nameSrc.setGuessCount(cp.Guesses)
+
+	// The resolved names are processed again to rebuild the findings of the enumeration
+	seen := make(map[string]struct{})
+	for _, reqs := range [][]*CheckpointRequest{cp.Resolved, cp.Pending} {
+		for _, req := range reqs {
+			key := "name:" + req.Name
+			if req.Name == "" {
+				key = "addr:" + req.Address
+			}
+			if _, found := seen[key]; found {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			e.nameSrc.resubmit(req.request())
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestCheckpointTracker(t *testing.T) {
+	tracker := newCheckpointTracker(nil)
+
+	www := &requests.DNSRequest{Name: "www.owasp.org", Domain: "owasp.org", Tag: requests.DNS, Source: "DNS"}
+	mail := &requests.DNSRequest{Name: "mail.owasp.org", Domain: "owasp.org", Tag: requests.DNS, Source: "DNS"}
+	addr := &requests.AddrRequest{Address: "192.168.1.1", Domain: "owasp.org", Tag: requests.DNS, Source: "DNS"}
+	tracker.queue(www)
+	tracker.queue(mail)
+	tracker.queue(addr)
+	tracker.enter(www)
+	tracker.resolve(www)
+
+	cp := tracker.checkpoint()
+	if len(cp.Pending) != 3 {
+		t.Errorf("The checkpoint has %d pending requests, expected 3", len(cp.Pending))
+	}
+	if len(cp.Resolved) != 1 || cp.Resolved[0].Name != "www.owasp.org" {
+		t.Errorf("The checkpoint did not save the resolved name")
+	}
+	// The names processed long ago are no longer pending
+	tracker.inflight["name:www.owasp.org"].entered = time.Now().Add(-2 * checkpointInflight)
+	if cp = tracker.checkpoint(); len(cp.Pending) != 2 {
+		t.Errorf("The checkpoint has %d pending requests, expected 2", len(cp.Pending))
+	}
+
+	root := &requests.DNSRequest{Name: "owasp.org", Domain: "owasp.org"}
+	asn := &requests.ASNRequest{ASN: 26808}
+	tracker.sourceAccepted("Crtsh", root)
+	if tracker.sourceCompleted("Crtsh", root) {
+		t.Errorf("The request was completed before the data source accepted another")
+	}
+	tracker.sourceAccepted("Crtsh", asn)
+	if !tracker.sourceCompleted("Crtsh", root) {
+		t.Errorf("The request was not completed after the data source accepted another")
+	}
+	if tracker.sourceCompleted("Crtsh", asn) || tracker.sourceCompleted("DNSDumpster", root) {
+		t.Errorf("The requests in progress or fired to other data sources were completed")
+	}
+	if tracker.sourceCompleted("Crtsh", www) {
+		t.Errorf("The names other than the root domain names are never completed")
+	}
+
+	resumed := newCheckpointTracker(tracker.checkpoint())
+	if !resumed.sourceCompleted("Crtsh", root) {
+		t.Errorf("The completed request was not restored from the checkpoint")
+	}
+}
+
+func TestSaveCheckpoint(t *testing.T) {
+	dir, err := os.MkdirTemp("", "checkpoint")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, checkpointFileName)
+	cp := &Checkpoint{
+		UUID:     "3b241101-e2bb-4255-8caf-4136c566a962",
+		Domains:  []string{"owasp.org"},
+		Pending:  []*CheckpointRequest{{Address: "192.168.1.1", Domain: "owasp.org"}},
+		Resolved: []*CheckpointRequest{{Name: "www.owasp.org", Domain: "owasp.org"}},
+		Sources:  map[string][]string{"Crtsh": {"domain:owasp.org"}},
+		Guesses:  10,
+		Filter:   []byte{1, 2, 3},
+	}
+	if err := saveCheckpoint(path, cp); err != nil {
+		t.Fatalf("Failed to save the checkpoint: %v", err)
+	}
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Failed to load the checkpoint: %v", err)
+	}
+	if loaded.UUID != cp.UUID || len(loaded.Domains) != 1 || loaded.Guesses != 10 || len(loaded.Filter) != 3 {
+		t.Errorf("The checkpoint loaded does not match the checkpoint saved")
+	}
+	if len(loaded.Pending) != 1 || loaded.Pending[0].Address != "192.168.1.1" {
+		t.Errorf("The pending requests were not loaded")
+	}
+	if len(loaded.Sources["Crtsh"]) != 1 {
+		t.Errorf("The requests completed by the data sources were not loaded")
+	}
+}
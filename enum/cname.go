@@ -0,0 +1,89 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+)
+
+// The number of CNAME chains followed concurrently
+const cnameChainWorkers = 10
+
+// cnameFollower follows the CNAME chains of the in-scope names to the terminal target, so the
+// lookups of each chain do not hold the storage of the pipeline data.
+type cnameFollower struct {
+	*asyncWorker
+	enum *Enumeration
+}
+
+// newCNAMEFollower returns a cnameFollower for the provided Enumeration that has not been started yet.
+func newCNAMEFollower(e *Enumeration) *cnameFollower {
+	f := &cnameFollower{enum: e}
+
+	f.asyncWorker = newAsyncWorker(cnameChainWorkers, func(ctx context.Context, item interface{}) {
+		if chain, err := e.Sys.TrustedResolvers().FollowCNAMEChain(ctx, item.(string)); err == nil {
+			e.insertCNAMEChain(ctx, chain)
+		}
+	})
+	return f
+}
+
+// addName queues the chain of the in-scope name to be followed once, when it resolved to a CNAME record.
+func (f *cnameFollower) addName(req *requests.DNSRequest) {
+	if f == nil || req == nil || !req.Valid() {
+		return
+	}
+
+	var cname bool
+	for _, r := range req.Records {
+		if uint16(r.Type) == dns.TypeCNAME {
+			cname = true
+			break
+		}
+	}
+
+	name := strings.ToLower(req.Name)
+	if cname && f.enum.Config.IsDomainInScope(name) {
+		f.add(name, name)
+	}
+}
+
+// insertCNAMEChain stores each record of the chain in the graph, and flags the first name
+// of the chain when the terminal target does not exist.
+func (e *Enumeration) insertCNAMEChain(ctx context.Context, chain *resolvers.CNAMEChain) {
+	if len(chain.Names) == 0 || ctx.Err() != nil {
+		return
+	}
+
+	uuid := e.Config.UUID.String()
+	for i := 0; i < len(chain.Names)-1; i++ {
+		// The records of the blacklisted third-party hosts are not stored
+		if i > 0 && e.Config.Blacklisted(chain.Names[i]) {
+			break
+		}
+		if err := e.graph.UpsertCNAME(ctx, chain.Names[i], chain.Names[i+1], "DNS", uuid); err != nil {
+			e.Config.Log.Printf("%s failed to insert CNAME: %v", e.graph, err)
+			return
+		}
+	}
+
+	node := netmap.Node(chain.Names[0])
+	if err := e.graph.UpsertProperty(ctx, node, "cname_target", chain.Target); err != nil {
+		e.Config.Log.Printf("%s failed to insert the CNAME target: %v", e.graph, err)
+		return
+	}
+	if chain.Dangling {
+		e.Config.Log.Printf("Dangling CNAME: %s (the target %s does not exist)", chain, chain.Target)
+		if err := e.graph.UpsertProperty(ctx, node, "dangling_cname", chain.Target); err != nil {
+			e.Config.Log.Printf("%s failed to flag the dangling CNAME: %v", e.graph, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+)
+
+func TestCNAMEFollowerAddName(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	follower := newCNAMEFollower(&Enumeration{Config: cfg})
+	defer follower.stop()
+
+	aliased := func(name string) *requests.DNSRequest {
+		return &requests.DNSRequest{
+			Name:    name,
+			Domain:  "owasp.org",
+			Records: []requests.DNSAnswer{{Name: name, Type: int(dns.TypeCNAME), Data: "owasp.github.io"}},
+		}
+	}
+	follower.addName(aliased("blog.owasp.org"))
+	follower.addName(aliased("blog.owasp.org"))
+	follower.addName(aliased("blog.example.com"))
+	follower.addName(&requests.DNSRequest{
+		Name:    "www.owasp.org",
+		Domain:  "owasp.org",
+		Records: []requests.DNSAnswer{{Name: "www.owasp.org", Type: int(dns.TypeA), Data: "192.168.1.1"}},
+	})
+	if n := follower.items.Len(); n != 1 {
+		t.Errorf("%d names were queued, expected only the aliased name in scope", n)
+	}
+
+	var nilFollower *cnameFollower
+	nilFollower.addName(aliased("blog.owasp.org"))
+}
+
+func TestInsertCNAMEChain(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	e := &Enumeration{Config: cfg, graph: g}
+	e.insertCNAMEChain(ctx, &resolvers.CNAMEChain{
+		Names:    []string{"blog.owasp.org", "owasp.github.io", "gone.github.io"},
+		Target:   "gone.github.io",
+		Dangling: true,
+	})
+
+	if n, err := g.CountOutEdges(ctx, netmap.Node("owasp.github.io"), "cname_record"); err != nil || n != 1 {
+		t.Errorf("The records of the chain were not stored")
+	}
+	for _, predicate := range []string{"cname_target", "dangling_cname"} {
+		props, err := g.ReadProperties(ctx, netmap.Node("blog.owasp.org"), predicate)
+		if err != nil || len(props) != 1 || props[0].Value.Native().(string) != "gone.github.io" {
+			t.Errorf("The %s property was not stored", predicate)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+)
+
+// Returns the tag of each source that can add names and addresses to the graph.
+func (e *Enumeration) sourceTags() map[string]string {
+	tags := map[string]string{
+		"DNS":          requests.DNS,
+		"Reverse DNS":  requests.DNS,
+		"NSEC Walk":    requests.DNS,
+		"DNS Zone XFR": requests.AXFR,
+		"Active Crawl": requests.CRAWL,
+		"Active Cert":  requests.CERT,
+	}
+
+	for _, src := range e.srcs {
+		tags[src.String()] = src.Description()
+	}
+	for _, name := range e.Guessers() {
+		tags[name] = requests.ALT
+	}
+	return tags
+}
+
+// storeConfidence attaches the confidence property to the names and addresses of the enumeration.
+func (e *Enumeration) storeConfidence(ctx context.Context) error {
+	uuid := e.Config.UUID.String()
+	srcTags := e.sourceTags()
+
+	tagsOf := func(node netmap.Node) []string {
+		var tags []string
+
+		if srcs, err := e.graph.NodeSources(ctx, node, uuid); err == nil {
+			for _, src := range srcs {
+				tags = append(tags, srcTags[src])
+			}
+		}
+		return tags
+	}
+
+	names := make(map[string]int)
+	for _, name := range e.graph.EventFQDNs(ctx, uuid) {
+		node := netmap.Node(name)
+
+		var resolved bool
+		if edges, err := e.graph.ReadOutEdges(ctx, node, "a_record", "aaaa_record", "cname_record"); err == nil && len(edges) > 0 {
+			resolved = true
+		}
+
+		names[name] = requests.Confidence(resolved, tagsOf(node))
+		if err := e.replaceConfidence(ctx, node, names[name]); err != nil {
+			return err
+		}
+	}
+
+	addrs, err := e.graph.AllNodesOfType(ctx, netmap.TypeAddr, uuid)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		var conf int
+		// Addresses are as reliable as the best name resolving to them
+		if edges, err := e.graph.ReadInEdges(ctx, addr, "a_record", "aaaa_record"); err == nil {
+			for _, edge := range edges {
+				if c, found := names[e.graph.NodeToID(edge.From)]; found && c > conf {
+					conf = c
+				}
+			}
+		}
+		if conf == 0 {
+			conf = requests.Confidence(false, tagsOf(addr))
+		}
+
+		if err := e.replaceConfidence(ctx, addr, conf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Enumeration) replaceConfidence(ctx context.Context, node netmap.Node, conf int) error {
+	if props, err := e.graph.ReadProperties(ctx, node, requests.ConfidencePredicate); err == nil {
+		for _, p := range props {
+			_ = e.graph.DeleteProperty(ctx, node, p.Predicate, p.Value)
+		}
+	}
+
+	if err := e.graph.UpsertProperty(ctx, node, requests.ConfidencePredicate, strconv.Itoa(conf)); err != nil {
+		return fmt.Errorf("%s failed to store the confidence of %s: %v", e.graph, e.graph.NodeToID(node), err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// The stages of the enumeration reported by Progress.
+const (
+	StageStarting    = "starting"
+	StageEnumerating = "enumerating"
+	StageStoring     = "storing"
+	StageFinished    = "finished"
+)
+
+// Progress is a snapshot of the work waiting to be performed by a running enumeration.
+type Progress struct {
+	Stage  string
+	Paused bool
+	// The names and addresses waiting to enter the enumeration pipeline
+	QueuedNames int
+	// The requests waiting to be handed to each data source
+	SourceBacklog map[string]int
+	// The data sources that no longer receive requests
+	DisabledSources []string
+}
+
+// controls holds the changes requested while the enumeration is running.
+type controls struct {
+	sync.Mutex
+	// Open while the enumeration is paused
+	paused   chan struct{}
+	stage    string
+	disabled map[string]struct{}
+	backlog  map[string]int
+}
+
+// The closed channel returned while the enumeration is not paused.
+var running = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// Pause stops new names from entering the pipeline and new requests from being sent to the
+// data sources, until Unpause is called. The work already in progress is allowed to finish.
+func (e *Enumeration) Pause() {
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	if e.controls.paused == nil {
+		e.controls.paused = make(chan struct{})
+	}
+}
+
+// Unpause continues the enumeration after a call to Pause.
+func (e *Enumeration) Unpause() {
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	if e.controls.paused != nil {
+		close(e.controls.paused)
+		e.controls.paused = nil
+	}
+}
+
+// Paused returns true when the enumeration has been paused.
+func (e *Enumeration) Paused() bool {
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	return e.controls.paused != nil
+}
+
+// Returns a channel that is closed once the enumeration is not paused.
+func (e *Enumeration) unpaused() <-chan struct{} {
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	if e.controls.paused == nil {
+		return running
+	}
+	return e.controls.paused
+}
+
+// DisableSource stops sending requests to the named data source and discards its findings.
+func (e *Enumeration) DisableSource(name string) error {
+	var found bool
+	for _, src := range e.srcs {
+		if src.String() == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("the data source %s is not used by the enumeration", name)
+	}
+
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	if e.controls.disabled == nil {
+		e.controls.disabled = make(map[string]struct{})
+	}
+	e.controls.disabled[name] = struct{}{}
+	return nil
+}
+
+// EnableSource continues using the data source after a call to DisableSource.
+func (e *Enumeration) EnableSource(name string) {
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	delete(e.controls.disabled, name)
+}
+
+// SourceDisabled returns true when the named data source has been disabled.
+func (e *Enumeration) SourceDisabled(name string) bool {
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	_, found := e.controls.disabled[name]
+	return found
+}
+
+func (e *Enumeration) setStage(stage string) {
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	e.controls.stage = stage
+}
+
+func (e *Enumeration) setBacklog(name string, n int) {
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	if e.controls.backlog == nil {
+		e.controls.backlog = make(map[string]int)
+	}
+	e.controls.backlog[name] = n
+}
+
+// Progress returns the work waiting to be performed by the enumeration.
+func (e *Enumeration) Progress() *Progress {
+	e.controls.Lock()
+	defer e.controls.Unlock()
+
+	p := &Progress{
+		Stage:         e.controls.stage,
+		Paused:        e.controls.paused != nil,
+		SourceBacklog: make(map[string]int, len(e.controls.backlog)),
+	}
+	if e.nameSrc != nil {
+		p.QueuedNames = e.nameSrc.queue.Len()
+	}
+	if p.Stage == "" {
+		p.Stage = StageStarting
+	}
+	for name, n := range e.controls.backlog {
+		p.SourceBacklog[name] = n
+	}
+	for name := range e.controls.disabled {
+		p.DisabledSources = append(p.DisabledSources, name)
+	}
+	sort.Strings(p.DisabledSources)
+	return p
+}
+
+// Reload applies the settings of the reloaded configuration to the running enumeration. The data sources
+// receiving new credentials or rate limits are started again, the resolver pool adopts the new rate limit,
+// and the root domain names and ASNs added to the scope are sent through the pipeline and to the data sources.
+func (e *Enumeration) Reload(cfg *config.Config) *config.ReloadChanges {
+	changes := e.Config.Reload(cfg)
+
+	if changes.MaxDNSQueries && e.Sys != nil {
+		e.Sys.Resolvers().SetMaxQPS(e.Config.MaxDNSQueries)
+	}
+	for _, name := range changes.Sources {
+		for _, src := range e.srcs {
+			if !strings.EqualFold(src.String(), name) {
+				continue
+			}
+			if err := src.OnStart(); err != nil {
+				e.Config.Log.Printf("%s: failed to apply the reloaded configuration: %v", src.String(), err)
+			}
+		}
+	}
+
+	e.controls.Lock()
+	src, stage := e.nameSrc, e.controls.stage
+	e.controls.Unlock()
+	// The additions made before the enumeration starts are submitted with the rest of the scope
+	if src == nil || stage != StageEnumerating {
+		return changes
+	}
+
+	for _, domain := range changes.Domains {
+		req := &requests.DNSRequest{
+			Name:   domain,
+			Domain: domain,
+			Tag:    requests.DNS,
+			Source: "DNS",
+		}
+
+		src.newName(req)
+		e.sendRequests(req.Clone().(*requests.DNSRequest))
+	}
+	for _, asn := range changes.ASNs {
+		e.sendRequests(&requests.ASNRequest{ASN: asn})
+	}
+	return changes
+}
@@ -0,0 +1,65 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"testing"
+
+	"github.com/caffix/service"
+)
+
+func TestPause(t *testing.T) {
+	e := &Enumeration{}
+
+	select {
+	case <-e.unpaused():
+	default:
+		t.Errorf("The enumeration was paused before calling Pause")
+	}
+
+	if stage := e.Progress().Stage; stage != StageStarting {
+		t.Errorf("The stage was %s before the enumeration started", stage)
+	}
+
+	e.Pause()
+	ch := e.unpaused()
+	select {
+	case <-ch:
+		t.Errorf("The paused enumeration was not held")
+	default:
+	}
+	if !e.Paused() || !e.Progress().Paused {
+		t.Errorf("The enumeration was not reported as paused")
+	}
+
+	e.Unpause()
+	select {
+	case <-ch:
+	default:
+		t.Errorf("The enumeration was still held after calling Unpause")
+	}
+	if e.Paused() {
+		t.Errorf("The enumeration was reported as paused after calling Unpause")
+	}
+}
+
+func TestDisableSource(t *testing.T) {
+	e := &Enumeration{srcs: []service.Service{service.NewBaseService(nil, "Crtsh")}}
+
+	if err := e.DisableSource("Unknown"); err == nil {
+		t.Errorf("The unknown data source was disabled")
+	}
+	if err := e.DisableSource("Crtsh"); err != nil || !e.SourceDisabled("Crtsh") {
+		t.Errorf("The data source was not disabled: %v", err)
+	}
+	if p := e.Progress(); len(p.DisabledSources) != 1 || p.DisabledSources[0] != "Crtsh" {
+		t.Errorf("The disabled data sources were not reported: %v", p.DisabledSources)
+	}
+
+	e.EnableSource("Crtsh")
+	if e.SourceDisabled("Crtsh") {
+		t.Errorf("The data source was still disabled after calling EnableSource")
+	}
+}
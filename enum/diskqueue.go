@@ -0,0 +1,217 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/caffix/queue"
+)
+
+// diskQueue implements the queue.Queue interface for the names and addresses waiting to be resolved.
+// It holds a limited number of elements in memory, and the elements appended beyond the limit are
+// written to a file, which is read back as the elements in memory are consumed.
+type diskQueue struct {
+	sync.Mutex
+	mem    queue.Queue
+	max    int
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+	rfile  *os.File
+	reader *bufio.Reader
+	// The number of elements written to the file that were not read back yet
+	stored  int
+	log     *log.Logger
+	errOnce sync.Once
+}
+
+// Creates the file of the queue within the directory, which is removed by close.
+func newDiskQueue(dir string, max int, logger *log.Logger) (*diskQueue, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := ioutil.TempFile(dir, "queue-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+
+	return &diskQueue{
+		mem:    queue.NewQueue(),
+		max:    max,
+		path:   f.Name(),
+		file:   f,
+		writer: bufio.NewWriter(f),
+		log:    logger,
+	}, nil
+}
+
+// Append implements the queue.Queue interface.
+func (q *diskQueue) Append(data interface{}) {
+	q.AppendPriority(data, queue.PriorityNormal)
+}
+
+// AppendPriority implements the queue.Queue interface. The elements written to the file
+// are returned in the order they were appended, regardless of the priority.
+func (q *diskQueue) AppendPriority(data interface{}, priority int) {
+	q.Lock()
+	defer q.Unlock()
+
+	// The elements are only written to the file once the memory is full,
+	// and continue to be written while the file holds elements
+	if q.file == nil || (q.stored == 0 && q.mem.Len() < q.max) {
+		q.mem.AppendPriority(data, priority)
+		return
+	}
+
+	_, req := checkpointRequest(data)
+	if req == nil {
+		q.mem.AppendPriority(data, priority)
+		return
+	}
+
+	line, err := json.Marshal(req)
+	if err == nil {
+		_, err = q.writer.Write(append(line, '\n'))
+	}
+	if err != nil {
+		q.fail(err)
+		q.mem.AppendPriority(data, priority)
+		return
+	}
+	q.stored++
+}
+
+// Signal implements the queue.Queue interface.
+func (q *diskQueue) Signal() <-chan struct{} {
+	return q.mem.Signal()
+}
+
+// Next implements the queue.Queue interface.
+func (q *diskQueue) Next() (interface{}, bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	// The elements in memory are refilled from the file before running low,
+	// so the signals of the memory queue continue while the file holds elements
+	if q.stored > 0 && q.mem.Len() <= q.max/2 {
+		q.load(q.max - q.mem.Len())
+	}
+	return q.mem.Next()
+}
+
+// Process implements the queue.Queue interface.
+func (q *diskQueue) Process(callback func(interface{})) {
+	element, ok := q.Next()
+
+	for ok {
+		callback(element)
+		element, ok = q.Next()
+	}
+}
+
+// Empty implements the queue.Queue interface.
+func (q *diskQueue) Empty() bool {
+	return q.Len() == 0
+}
+
+// Len implements the queue.Queue interface.
+func (q *diskQueue) Len() int {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.mem.Len() + q.stored
+}
+
+// Reads up to num elements from the file into memory. The file is emptied once all of its
+// elements were read back, so the disk space is released during the enumeration.
+func (q *diskQueue) load(num int) {
+	if err := q.writer.Flush(); err != nil {
+		q.fail(err)
+		return
+	}
+	if q.reader == nil {
+		f, err := os.Open(q.path)
+		if err != nil {
+			q.fail(err)
+			return
+		}
+		q.rfile = f
+		q.reader = bufio.NewReader(f)
+	}
+
+	for i := 0; i < num && q.stored > 0; i++ {
+		line, err := q.reader.ReadBytes('\n')
+		if err != nil {
+			q.fail(err)
+			return
+		}
+		q.stored--
+
+		var req CheckpointRequest
+		if err := json.Unmarshal(line, &req); err == nil {
+			q.mem.Append(req.request())
+		}
+	}
+
+	if q.stored == 0 {
+		q.reset()
+	}
+}
+
+func (q *diskQueue) reset() {
+	if err := q.file.Truncate(0); err != nil {
+		q.fail(err)
+		return
+	}
+	if _, err := q.file.Seek(0, 0); err != nil {
+		q.fail(err)
+		return
+	}
+	q.writer.Reset(q.file)
+	q.closeReader()
+}
+
+func (q *diskQueue) closeReader() {
+	if q.rfile != nil {
+		q.rfile.Close()
+		q.rfile = nil
+		q.reader = nil
+	}
+}
+
+// Stops using the file after an error, and the elements that could not be read back are lost.
+func (q *diskQueue) fail(err error) {
+	q.errOnce.Do(func() {
+		q.log.Printf("The queue file %s failed and the names are kept in memory: %v", q.path, err)
+	})
+	q.removeFile()
+}
+
+// Removes the file of the queue, discarding the elements that were not read back.
+func (q *diskQueue) close() {
+	q.Lock()
+	defer q.Unlock()
+
+	q.removeFile()
+}
+
+func (q *diskQueue) removeFile() {
+	if q.file == nil {
+		return
+	}
+
+	q.closeReader()
+	q.file.Close()
+	os.Remove(q.path)
+	q.file = nil
+	q.stored = 0
+}
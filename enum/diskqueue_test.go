@@ -0,0 +1,73 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestDiskQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "queue")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, err := newDiskQueue(dir, 4, log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("Failed to create the disk queue: %v", err)
+	}
+	defer q.close()
+
+	num := 20
+	for i := 0; i < num; i++ {
+		q.Append(&requests.DNSRequest{
+			Name:   strconv.Itoa(i) + ".owasp.org",
+			Domain: "owasp.org",
+			Tag:    requests.DNS,
+			Source: "DNS",
+		})
+	}
+	q.Append(&requests.AddrRequest{Address: "192.168.1.1", Domain: "owasp.org", Tag: requests.DNS, Source: "DNS"})
+	if l := q.Len(); l != num+1 {
+		t.Errorf("The queue has %d elements, expected %d", l, num+1)
+	}
+	if q.mem.Len() != 4 || q.stored != num-3 {
+		t.Errorf("The queue holds %d elements in memory and %d on disk", q.mem.Len(), q.stored)
+	}
+
+	for i := 0; i < num; i++ {
+		element, ok := q.Next()
+		if !ok {
+			t.Fatalf("The queue was empty after %d elements", i)
+		}
+		if req, ok := element.(*requests.DNSRequest); !ok || req.Name != strconv.Itoa(i)+".owasp.org" || req.Domain != "owasp.org" {
+			t.Errorf("The element %d was not returned in order: %v", i, element)
+		}
+	}
+	if element, ok := q.Next(); !ok {
+		t.Errorf("The address was not read back from the disk")
+	} else if req, ok := element.(*requests.AddrRequest); !ok || req.Address != "192.168.1.1" || !req.InScope {
+		t.Errorf("The address was not read back from the disk: %v", element)
+	}
+	if !q.Empty() {
+		t.Errorf("The queue was not empty after reading all the elements")
+	}
+	// The file is emptied once all the elements were read back
+	if info, err := os.Stat(q.path); err != nil || info.Size() != 0 {
+		t.Errorf("The queue file was not emptied: %v", err)
+	}
+
+	q.close()
+	if _, err := os.Stat(q.path); !os.IsNotExist(err) {
+		t.Errorf("The queue file was not removed")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	amassnet "github.com/OWASP/Amass/v3/net"
 	amassdns "github.com/OWASP/Amass/v3/net/dns"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/resolve"
 	"github.com/miekg/dns"
@@ -21,6 +22,9 @@ import (
 
 const maxDNSQueryAttempts int = 10
 
+// The maximum number of subdomains having their zone records and service names queried concurrently.
+const maxSubdomainQueryTasks int = 100
+
 // InitialQueryTypes include the DNS record types that are queried for a discovered name.
 var InitialQueryTypes = []uint16{
 	dns.TypeCNAME,
@@ -28,17 +32,27 @@ var InitialQueryTypes = []uint16{
 	dns.TypeAAAA,
 }
 
+// zoneRecordTypes include the DNS record types that describe the services, certificate
+// authorities and DNSSEC delegation of a discovered zone.
+var zoneRecordTypes = []uint16{
+	dns.TypeNAPTR,
+	dns.TypeCAA,
+	dns.TypeDS,
+}
+
 // dnsTask is the task that handles all DNS name resolution requests within the pipeline.
 type dnsTask struct {
-	enum *Enumeration
-	done chan struct{}
+	enum      *Enumeration
+	done      chan struct{}
+	subTokens chan struct{}
 }
 
 // newDNSTask returns a dNSTask specific to the provided Enumeration.
 func newDNSTask(e *Enumeration) *dnsTask {
 	return &dnsTask{
-		enum: e,
-		done: make(chan struct{}, 2),
+		enum:      e,
+		done:      make(chan struct{}, 2),
+		subTokens: make(chan struct{}, maxSubdomainQueryTasks),
 	}
 }
 
@@ -69,12 +83,21 @@ func (dt *dnsTask) rootTaskFunc() pipeline.TaskFunc {
 			return data, nil
 		}
 
-		if dt.enum.Config.IsDomainInScope(r.Name) {
-			go func() {
-				dt.subdomainQueries(ctx, r, tp)
-				dt.queryServiceNames(ctx, r, tp)
-			}()
+		if !dt.enum.Config.IsDomainInScope(r.Name) {
+			return data, nil
 		}
+		// The pipeline is held while the maximum number of subdomains are being queried
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case dt.subTokens <- struct{}{}:
+		}
+		go func() {
+			defer func() { <-dt.subTokens }()
+
+			dt.subdomainQueries(ctx, r, tp)
+			dt.queryServiceNames(ctx, r, tp)
+		}()
 		return data, nil
 	})
 }
@@ -96,7 +119,7 @@ func (dt *dnsTask) Process(ctx context.Context, data pipeline.Data, tp pipeline.
 			return nil, nil
 		}
 	case *requests.AddrRequest:
-		if dt.processRevRequest(ctx, v.Address, tp) || v.InScope {
+		if dt.enum.reverseDNS(ctx, v.Address) || v.InScope {
 			return data, nil
 		}
 		return nil, nil
@@ -119,27 +142,18 @@ loop:
 		}
 
 		msg := resolve.QueryMsg(req.Name, qtype)
-		resp, err := dt.enum.dnsQuery(ctx, msg, dt.enum.Sys.Resolvers(), maxDNSQueryAttempts)
-		if err != nil && err.Error() != "no record of this type" {
-			return nil, err
-		} else if err == nil && resp == nil {
-			return nil, errors.New("failed to resolve name")
-		}
-
-		resp, err = dt.enum.dnsQuery(ctx, msg, dt.enum.Sys.TrustedResolvers(), maxDNSQueryAttempts)
+		resp, err := dt.enum.resolveFwd(ctx, msg, req.Domain)
 		if err != nil {
 			if err.Error() == "no record of this type" {
 				continue
 			}
 			return nil, err
-		} else if resp == nil && err == nil {
-			return nil, errors.New("failed to resolve name")
 		}
 		if dt.enum.wildcardDetected(ctx, req, resp) {
 			return nil, errors.New("wildcard detected")
 		}
 
-		ans := resolve.ExtractAnswers(resp)
+		ans := resolvers.ExtractAnswers(resp)
 		if len(ans) == 0 {
 			continue
 		}
@@ -174,7 +188,56 @@ func (e *Enumeration) fwdQuery(ctx context.Context, name string, qtype uint16) (
 	return resp, err
 }
 
-func (e *Enumeration) dnsQuery(ctx context.Context, msg *dns.Msg, r *resolve.Resolvers, attempts int) (*dns.Msg, error) {
+// Resolves the message using the untrusted resolvers, and then the trusted resolvers to verify the answers.
+// In the authoritative mode, the message is sent to the name servers of the zone instead.
+func (e *Enumeration) resolveFwd(ctx context.Context, msg *dns.Msg, domain string) (*dns.Msg, error) {
+	if e.auth != nil {
+		return e.authQuery(ctx, msg, domain)
+	}
+
+	resp, err := e.dnsQuery(ctx, msg, e.Sys.Resolvers(), maxDNSQueryAttempts)
+	if err != nil && err.Error() != "no record of this type" {
+		return nil, err
+	} else if err == nil && resp == nil {
+		return nil, errors.New("failed to resolve name")
+	}
+
+	resp, err = e.verifyQuery(ctx, msg, maxDNSQueryAttempts)
+	if resp == nil && err == nil {
+		err = errors.New("failed to resolve name")
+	}
+	return resp, err
+}
+
+// Verifies the answers of the untrusted resolvers using the trusted resolvers, where the number of
+// trusted resolvers confirming the answers is selected by the verification policy of the configuration.
+func (e *Enumeration) verifyQuery(ctx context.Context, msg *dns.Msg, attempts int) (*dns.Msg, error) {
+	if num := e.Config.TrustedConfirmations; num > 1 {
+		return e.Sys.TrustedResolvers().Confirm(ctx, msg, num)
+	}
+	return e.dnsQuery(ctx, msg, e.Sys.TrustedResolvers(), attempts)
+}
+
+func (e *Enumeration) authQuery(ctx context.Context, msg *dns.Msg, domain string) (*dns.Msg, error) {
+	for num := 0; num < maxDNSQueryAttempts; num++ {
+		resp, err := e.auth.Query(ctx, msg, domain)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Rcode == dns.RcodeNameError {
+			return nil, errors.New("name does not exist")
+		}
+		if resp.Rcode == dns.RcodeSuccess && len(resp.Answer) == 0 {
+			return nil, errors.New("no record of this type")
+		}
+		if resp.Rcode == dns.RcodeSuccess {
+			return resp, nil
+		}
+	}
+	return nil, errors.New("failed to resolve name")
+}
+
+func (e *Enumeration) dnsQuery(ctx context.Context, msg *dns.Msg, r *resolvers.Pool, attempts int) (*dns.Msg, error) {
 	for num := 0; num < attempts; num++ {
 		select {
 		case <-ctx.Done():
@@ -206,7 +269,9 @@ func (e *Enumeration) wildcardDetected(ctx context.Context, req *requests.DNSReq
 	return false
 }
 
-func (dt *dnsTask) processRevRequest(ctx context.Context, addr string, tp pipeline.TaskParams) bool {
+// reverseDNS performs the PTR lookup for the address and submits the name of the record to the
+// enumeration when the answer matches the regular expression of an in-scope domain.
+func (e *Enumeration) reverseDNS(ctx context.Context, addr string) bool {
 	select {
 	case <-ctx.Done():
 		return false
@@ -222,17 +287,17 @@ func (dt *dnsTask) processRevRequest(ctx context.Context, addr string, tp pipeli
 		return false
 	}
 
-	resp, err := dt.enum.dnsQuery(ctx, msg, dt.enum.Sys.Resolvers(), maxDNSQueryAttempts)
+	resp, err := e.dnsQuery(ctx, msg, e.Sys.Resolvers(), maxDNSQueryAttempts)
 	if err != nil || resp == nil {
 		return false
 	}
 
-	resp, err = dt.enum.dnsQuery(ctx, msg, dt.enum.Sys.TrustedResolvers(), maxDNSQueryAttempts)
+	resp, err = e.verifyQuery(ctx, msg, maxDNSQueryAttempts)
 	if err != nil || resp == nil {
 		return false
 	}
 
-	ans := resolve.ExtractAnswers(resp)
+	ans := resolvers.ExtractAnswers(resp)
 	if len(ans) == 0 {
 		return false
 	}
@@ -247,11 +312,11 @@ func (dt *dnsTask) processRevRequest(ctx context.Context, addr string, tp pipeli
 		return false
 	}
 	// Check that the name discovered is in scope
-	d := dt.enum.Config.WhichDomain(answer)
+	d := e.Config.WhichDomain(answer)
 	if d == "" {
 		return false
 	}
-	if re := dt.enum.Config.DomainRegex(d); re == nil || re.FindString(answer) != answer {
+	if re := e.Config.DomainRegex(d); re == nil || re.FindString(answer) != answer {
 		return false
 	}
 
@@ -261,7 +326,7 @@ func (dt *dnsTask) processRevRequest(ctx context.Context, addr string, tp pipeli
 		return true
 	}
 
-	dt.enum.nameSrc.newName(&requests.DNSRequest{
+	e.nameSrc.newName(&requests.DNSRequest{
 		Name:   ptr,
 		Domain: domain,
 		Records: []requests.DNSAnswer{{
@@ -276,14 +341,15 @@ func (dt *dnsTask) processRevRequest(ctx context.Context, addr string, tp pipeli
 }
 
 func (dt *dnsTask) subdomainQueries(ctx context.Context, req *requests.DNSRequest, tp pipeline.TaskParams) {
-	ch := make(chan []requests.DNSAnswer, 4)
+	ch := make(chan []requests.DNSAnswer, 5)
 
 	go dt.queryNS(ctx, req.Name, req.Domain, ch, tp)
 	go dt.queryMX(ctx, req.Name, ch)
 	go dt.querySOA(ctx, req.Name, ch)
-	go dt.querySPF(ctx, req.Name, ch)
+	go dt.queryTXT(ctx, req.Name, ch)
+	go dt.queryZoneRecords(ctx, req.Name, ch)
 
-	for i := 0; i < 4; i++ {
+	for i := 0; i < 5; i++ {
 		if rr := <-ch; rr != nil {
 			req.Records = append(req.Records, rr...)
 		}
@@ -297,7 +363,7 @@ func (dt *dnsTask) subdomainQueries(ctx context.Context, req *requests.DNSReques
 func (dt *dnsTask) queryNS(ctx context.Context, name, domain string, ch chan []requests.DNSAnswer, tp pipeline.TaskParams) {
 	// Obtain the DNS answers for the NS records related to the domain
 	if resp, err := dt.enum.fwdQuery(ctx, name, dns.TypeNS); err == nil {
-		ans := resolve.ExtractAnswers(resp)
+		ans := resolvers.ExtractAnswers(resp)
 		rr := resolve.AnswersByType(ans, dns.TypeNS)
 
 		var records []requests.DNSAnswer
@@ -320,7 +386,7 @@ func (dt *dnsTask) queryNS(ctx context.Context, name, domain string, ch chan []r
 func (dt *dnsTask) queryMX(ctx context.Context, name string, ch chan []requests.DNSAnswer) {
 	// Obtain the DNS answers for the MX records related to the domain
 	if resp, err := dt.enum.fwdQuery(ctx, name, dns.TypeMX); err == nil {
-		ans := resolve.ExtractAnswers(resp)
+		ans := resolvers.ExtractAnswers(resp)
 		rr := resolve.AnswersByType(ans, dns.TypeMX)
 		ch <- convertAnswers(rr)
 		return
@@ -331,7 +397,7 @@ func (dt *dnsTask) queryMX(ctx context.Context, name string, ch chan []requests.
 func (dt *dnsTask) querySOA(ctx context.Context, name string, ch chan []requests.DNSAnswer) {
 	// Obtain the DNS answers for the SOA records related to the domain
 	if resp, err := dt.enum.fwdQuery(ctx, name, dns.TypeSOA); err == nil {
-		ans := resolve.ExtractAnswers(resp)
+		ans := resolvers.ExtractAnswers(resp)
 		rr := resolve.AnswersByType(ans, dns.TypeSOA)
 
 		var records []requests.DNSAnswer
@@ -341,25 +407,45 @@ func (dt *dnsTask) querySOA(ctx context.Context, name string, ch chan []requests
 			records = append(records, convertAnswers([]*resolve.ExtractedAnswer{a})...)
 		}
 		ch <- records
+		return
 	}
+	ch <- nil
 }
 
-func (dt *dnsTask) querySPF(ctx context.Context, name string, ch chan []requests.DNSAnswer) {
-	// Obtain the DNS answers for the SPF records related to the domain
-	if resp, err := dt.enum.fwdQuery(ctx, name, dns.TypeSPF); err == nil {
-		ans := resolve.ExtractAnswers(resp)
-		rr := resolve.AnswersByType(ans, dns.TypeSPF)
-		ch <- convertAnswers(rr)
-		return
+func (dt *dnsTask) queryTXT(ctx context.Context, name string, ch chan []requests.DNSAnswer) {
+	var records []requests.DNSAnswer
+	// SPF policies are published in TXT records, and occasionally using the obsolete SPF record type
+	for _, qtype := range []uint16{dns.TypeTXT, dns.TypeSPF} {
+		if resp, err := dt.enum.fwdQuery(ctx, name, qtype); err == nil {
+			rr := resolve.AnswersByType(resolvers.ExtractAnswers(resp), qtype)
+			records = append(records, convertAnswers(rr)...)
+		}
 	}
-	ch <- nil
+	ch <- records
+}
+
+func (dt *dnsTask) queryZoneRecords(ctx context.Context, name string, ch chan []requests.DNSAnswer) {
+	var records []requests.DNSAnswer
+
+	for _, qtype := range zoneRecordTypes {
+		if resp, err := dt.enum.fwdQuery(ctx, name, qtype); err == nil {
+			rr := resolve.AnswersByType(resolvers.ExtractAnswers(resp), qtype)
+			records = append(records, convertAnswers(rr)...)
+		}
+	}
+	ch <- records
 }
 
 func (dt *dnsTask) queryServiceNames(ctx context.Context, req *requests.DNSRequest, tp pipeline.TaskParams) {
 	var wg sync.WaitGroup
 
-	wg.Add(len(popularSRVRecords))
-	for _, name := range popularSRVRecords {
+	services := dt.enum.Config.SRVServices
+	if len(services) == 0 {
+		services = popularSRVRecords
+	}
+
+	wg.Add(len(services))
+	for _, name := range services {
 		go dt.querySingleServiceName(ctx, name+"."+req.Name, req.Domain, &wg, tp)
 	}
 	wg.Wait()
@@ -379,7 +465,7 @@ func (dt *dnsTask) querySingleServiceName(ctx context.Context, name, domain stri
 		return
 	}
 
-	ans := resolve.ExtractAnswers(resp)
+	ans := resolvers.ExtractAnswers(resp)
 	if len(ans) == 0 {
 		return
 	}
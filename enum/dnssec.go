@@ -0,0 +1,80 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+)
+
+// dnssecValidator validates the DNSSEC chains of the resolved names in scope, using its own
+// concurrency so the DS and DNSKEY queries do not hold the storage of the pipeline data.
+type dnssecValidator struct {
+	*asyncWorker
+	enum *Enumeration
+}
+
+type dnssecName struct {
+	name  string
+	qtype uint16
+}
+
+// newDNSSECValidator returns a dnssecValidator for the provided Enumeration that has not been started yet.
+func newDNSSECValidator(e *Enumeration) *dnssecValidator {
+	workers := e.Config.DNSSECConcurrency
+	if workers <= 0 {
+		workers = config.DefaultDNSSECConcurrency
+	}
+
+	validator := resolvers.NewValidator(e.Sys.TrustedResolvers())
+	return &dnssecValidator{
+		enum: e,
+		asyncWorker: newAsyncWorker(workers, func(ctx context.Context, item interface{}) {
+			n := item.(*dnssecName)
+
+			e.insertDNSSECStatus(ctx, n.name, validator.Validate(ctx, n.name, n.qtype))
+		}),
+	}
+}
+
+// addName queues the in-scope name to be validated once, when it resolved to an alias or address.
+func (v *dnssecValidator) addName(req *requests.DNSRequest) {
+	if v == nil || req == nil || !req.Valid() {
+		return
+	}
+
+	var qtype uint16
+	for _, r := range req.Records {
+		if t := uint16(r.Type); t == dns.TypeCNAME || t == dns.TypeA || t == dns.TypeAAAA {
+			qtype = t
+			break
+		}
+	}
+
+	name := strings.ToLower(req.Name)
+	if qtype != 0 && v.enum.Config.IsDomainInScope(name) {
+		v.add(name, &dnssecName{name: name, qtype: qtype})
+	}
+}
+
+// insertDNSSECStatus stores the DNSSEC status of the name as a property of the FQDN node.
+func (e *Enumeration) insertDNSSECStatus(ctx context.Context, name, status string) {
+	if status == resolvers.DNSSECIndeterminate || ctx.Err() != nil {
+		return
+	}
+	if status == resolvers.DNSSECBogus {
+		e.Config.Log.Printf("WARNING: DNSSEC validation failed for %s; the answers may have been "+
+			"intercepted or the delegation is broken", name)
+	}
+	if err := e.graph.UpsertProperty(ctx, netmap.Node(name), "dnssec", status); err != nil {
+		e.Config.Log.Printf("%s failed to insert the DNSSEC status: %v", e.graph, err)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+)
+
+func TestDNSSECValidatorAddName(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	v := &dnssecValidator{
+		asyncWorker: newAsyncWorker(1, nil),
+		enum:        &Enumeration{Config: cfg},
+	}
+	defer v.stop()
+
+	resolved := func(name string, qtype uint16) *requests.DNSRequest {
+		return &requests.DNSRequest{
+			Name:    name,
+			Domain:  "owasp.org",
+			Records: []requests.DNSAnswer{{Name: name, Type: int(qtype), Data: "192.168.1.1"}},
+		}
+	}
+	v.addName(resolved("www.owasp.org", dns.TypeA))
+	v.addName(resolved("www.owasp.org", dns.TypeA))
+	v.addName(resolved("www.example.com", dns.TypeA))
+	v.addName(resolved("owasp.org", dns.TypeTXT))
+	if n := v.items.Len(); n != 1 {
+		t.Fatalf("%d names were queued, expected only the resolved name in scope", n)
+	}
+	if e, _ := v.items.Next(); e.(*dnssecName).qtype != dns.TypeA {
+		t.Errorf("The queued name does not provide the record type to be validated")
+	}
+
+	var nilValidator *dnssecValidator
+	nilValidator.addName(resolved("www.owasp.org", dns.TypeA))
+}
+
+func TestInsertDNSSECStatus(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	e := &Enumeration{Config: cfg, graph: g}
+	for _, name := range []string{"www.owasp.org", "dev.owasp.org"} {
+		if _, err := g.UpsertFQDN(ctx, name, "DNS", cfg.UUID.String()); err != nil {
+			t.Fatalf("Failed to insert the FQDN: %v", err)
+		}
+	}
+
+	e.insertDNSSECStatus(ctx, "www.owasp.org", resolvers.DNSSECSecure)
+	e.insertDNSSECStatus(ctx, "dev.owasp.org", resolvers.DNSSECIndeterminate)
+	if props, err := g.ReadProperties(ctx, netmap.Node("www.owasp.org"), "dnssec"); err != nil ||
+		len(props) != 1 || props[0].Value.Native().(string) != resolvers.DNSSECSecure {
+		t.Errorf("The DNSSEC status was not stored")
+	}
+	if props, _ := g.ReadProperties(ctx, netmap.Node("dev.owasp.org"), "dnssec"); len(props) != 0 {
+		t.Errorf("The indeterminate DNSSEC status was stored")
+	}
+}
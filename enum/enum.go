@@ -6,19 +6,34 @@ package enum
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/netmap"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
 	"github.com/caffix/service"
+	"github.com/caffix/stringset"
 )
 
 const maxActivePipelineTasks int = 25
 
+// The maximum number of names waiting for the active techniques, such as the certificate grabs.
+const maxActiveQueuedTasks int = 10000
+
+// The maximum number of requests waiting to be sent to each data source. Beyond it, the oldest
+// requests for the resolved names are dropped, while the requests for root domain names and ASNs are kept.
+const maxSourceBacklog int = 10000
+
 // Enumeration is the object type used to execute a DNS enumeration.
 type Enumeration struct {
 	Config   *config.Config
@@ -32,23 +47,47 @@ type Enumeration struct {
 	dnsTask  *dnsTask
 	store    *dataManager
 	requests queue.Queue
+	stats    *sourceStats
+	auth     *resolvers.Authoritative
+	sweeper  *reverseSweeper
+	dnssec   *dnssecValidator
+	cnames   *cnameFollower
+	prober   *httpProber
+	scanner  *portScanner
+	guessers *guessers
+	// Orders the candidate names by their probability of resolving
+	scorer *nameScorer
+	// The names confirmed within the freshness window that are not resolved again
+	fresh *stringset.Set
+	// The progress saved in the checkpoints and the checkpoint being resumed
+	checkpoints *checkpointTracker
+	resume      *Checkpoint
+	// Pausing the enumeration and disabling data sources while it is running
+	controls controls
 }
 
 // NewEnumeration returns an initialized Enumeration that has not been started yet.
 func NewEnumeration(cfg *config.Config, sys systems.System, graph *netmap.Graph) *Enumeration {
-	return &Enumeration{
+	srcs := datasrcs.SelectedDataSources(cfg, sys.DataSources())
+
+	e := &Enumeration{
 		Config:   cfg,
 		Sys:      sys,
 		graph:    graph,
-		srcs:     datasrcs.SelectedDataSources(cfg, sys.DataSources()),
+		srcs:     srcs,
 		requests: queue.NewQueue(),
+		stats:    newSourceStats(srcs),
 	}
+	e.guessers = newGuessers(e)
+	return e
 }
 
 // Start begins the vertical domain correlation process.
 func (e *Enumeration) Start(ctx context.Context) error {
 	e.done = make(chan struct{})
 	defer close(e.done)
+	e.setStage(StageStarting)
+	defer e.setStage(StageFinished)
 
 	if err := e.Config.CheckSettings(); err != nil {
 		return err
@@ -58,17 +97,93 @@ func (e *Enumeration) Start(ctx context.Context) error {
 	var cancel context.CancelFunc
 	e.ctx, cancel = context.WithCancel(ctx)
 	defer cancel()
+	if e.Config.CheckpointInterval > 0 {
+		e.checkpoints = newCheckpointTracker(e.resume)
+	}
 	go e.manageDataSrcRequests()
 
 	if !e.Config.Passive {
 		e.dnsTask = newDNSTask(e)
 		e.store = newDataManager(e)
+		e.cnames = newCNAMEFollower(e)
+		defer e.cnames.stop()
+		e.scorer = newNameScorer(e.Config.Wordlist, e.stats)
+		// The labels resolved by the previous enumerations of the domains also order the candidates
+		if err := e.scorer.load(markovModelsPath(e.Config)); err != nil && !os.IsNotExist(err) {
+			e.Config.Log.Printf("Failed to load the Markov models: %v", err)
+		}
 		e.subTask = newSubdomainTask(e)
 		defer e.subTask.Stop()
+
+		if e.Config.Authoritative {
+			e.auth = resolvers.NewAuthoritative(e.Sys.TrustedResolvers(), e.Config.AuthoritativeQPS)
+			e.auth.SetLogger(e.Config.Log)
+			e.auth.SetPreferIPv6(e.Config.PreferIPv6)
+			defer e.auth.Stop()
+		}
+		if e.Config.ReverseSweeps {
+			e.sweeper = newReverseSweeper(e)
+			defer e.sweeper.stop()
+		}
+		if e.Config.DNSSEC {
+			e.dnssec = newDNSSECValidator(e)
+			defer e.dnssec.stop()
+		}
+		if e.Config.HTTPProbes {
+			e.prober = newHTTPProber(e)
+			defer e.prober.stop()
+		}
+		if e.Config.PortScan {
+			e.scanner = newPortScanner(e)
+			defer e.scanner.stop()
+		}
+		if e.Config.Alterations && len(e.Config.AltRules) > 0 {
+			g, err := newRuleGuesser(e.Config)
+			if err != nil {
+				return err
+			}
+			e.AddGuesser(g, e.Config.AltRulesBudget)
+		}
+		if e.Config.Alterations && len(e.Config.AltKeywords) > 0 {
+			e.AddGuesser(newKeywordGuesser(e.Config), 0)
+		}
+		if e.Config.Alterations {
+			for _, command := range e.Config.AltGuessers {
+				g, err := newExternalGuesser(command)
+				if err != nil {
+					return err
+				}
+				e.AddGuesser(g, 0)
+			}
+		}
+		// The names are copied before the input source accepts names from the data sources
+		e.copyFreshNames()
+		if e.fresh != nil {
+			defer e.fresh.Close()
+		}
 	}
 	// The pipeline input source will receive all the names
-	e.nameSrc = newEnumSource(e)
-	defer e.nameSrc.Stop()
+	src := newEnumSource(e)
+	// The queue of the input source is reported by Progress while the enumeration is running
+	e.controls.Lock()
+	e.nameSrc = src
+	e.controls.Unlock()
+	defer src.Stop()
+	if e.resume != nil {
+		e.resumeCheckpoint(e.resume)
+	}
+	if e.checkpoints != nil {
+		go e.manageCheckpoints()
+	}
+	if e.sweeper != nil {
+		e.sweeper.start()
+	}
+	for _, w := range e.asyncWorkers() {
+		w.start(e.ctx)
+	}
+	if !e.Config.Passive {
+		go e.guessers.process(e.ctx)
+	}
 
 	var stages []pipeline.Stage
 	if !e.Config.Passive {
@@ -94,12 +209,42 @@ func (e *Enumeration) Start(ctx context.Context) error {
 	go e.submitProvidedNames()
 
 	var err error
+	e.setStage(StageEnumerating)
 	if p := pipeline.NewPipeline(stages...); e.Config.Passive {
 		err = p.Execute(e.ctx, e.nameSrc, e.makeOutputSink())
+		e.setStage(StageStoring)
 	} else {
 		err = p.ExecuteBuffered(e.ctx, e.nameSrc, e.makeOutputSink(), 50)
+		e.setStage(StageStoring)
 		// Ensure all data has been stored
 		<-e.store.Stop()
+		// The names and addresses resolved last are still being followed, validated, probed and scanned
+		for _, w := range e.asyncWorkers() {
+			w.wait(e.ctx)
+		}
+		// The candidates are checked once all the names and records are in the graph
+		if e.Config.Active && e.Config.Takeovers && ctx.Err() == nil {
+			e.checkTakeovers(e.ctx)
+		}
+	}
+	if serr := e.storeSourceStats(context.Background()); serr != nil {
+		e.Config.Log.Print(serr.Error())
+	}
+	if cerr := e.storeConfidence(context.Background()); cerr != nil {
+		e.Config.Log.Print(cerr.Error())
+	}
+	if e.scorer != nil {
+		if serr := e.scorer.save(markovModelsPath(e.Config)); serr != nil {
+			e.Config.Log.Printf("Failed to save the Markov models: %v", serr)
+		}
+	}
+	if e.checkpoints != nil {
+		// Keep the checkpoint when the enumeration was interrupted, so it can be resumed
+		if ctx.Err() != nil {
+			e.saveCheckpoint()
+		} else {
+			_ = os.Remove(CheckpointPath(e.Config))
+		}
 	}
 	return err
 }
@@ -144,6 +289,8 @@ func (e *Enumeration) manageDataSrcRequests() {
 
 	finished := make(chan string, len(e.srcs))
 	requestsMap := make(map[string][]interface{})
+	// The request most recently fired to each data source
+	fired := make(map[string]interface{})
 loop:
 	for {
 		select {
@@ -157,32 +304,72 @@ loop:
 				continue loop
 			}
 			for name := range nameToSrc {
+				if e.SourceDisabled(name) {
+					continue
+				}
+				// Do not repeat the requests completed before the enumeration was resumed
+				if e.checkpoints.sourceCompleted(name, element) {
+					continue
+				}
 				if len(requestsMap[name]) == 0 && !pending[name] {
 					go e.fireRequest(nameToSrc[name], element, finished)
+					fired[name] = element
 					pending[name] = true
 				} else {
-					requestsMap[name] = append(requestsMap[name], element)
+					requestsMap[name] = trimBacklog(append(requestsMap[name], element))
+					e.setBacklog(name, len(requestsMap[name]))
 				}
 			}
 		case name := <-finished:
+			e.checkpoints.sourceAccepted(name, fired[name])
+			// The backlog of a disabled data source is dropped
+			if e.SourceDisabled(name) {
+				requestsMap[name] = nil
+			}
 			if len(requestsMap[name]) == 0 {
 				pending[name] = false
+				e.setBacklog(name, 0)
 				continue loop
 			}
 
 			go e.fireRequest(nameToSrc[name], requestsMap[name][0], finished)
+			fired[name] = requestsMap[name][0]
 			requestsMap[name] = requestsMap[name][1:]
+			e.setBacklog(name, len(requestsMap[name]))
 		}
 	}
 	e.requests.Process(func(e interface{}) {})
 }
 
+// Drops the oldest request for a resolved name once the backlog exceeds the maximum, so the data
+// sources that fall behind do not hold the records of every name resolved by the enumeration.
+func trimBacklog(backlog []interface{}) []interface{} {
+	if len(backlog) <= maxSourceBacklog {
+		return backlog
+	}
+
+	for i, element := range backlog {
+		if _, ok := element.(*requests.ResolvedRequest); ok {
+			return append(backlog[:i], backlog[i+1:]...)
+		}
+	}
+	return backlog
+}
+
 func (e *Enumeration) fireRequest(srv service.Service, req interface{}, finished chan string) {
+	// Hold the request while the enumeration is paused
+	select {
+	case <-e.done:
+	case <-e.ctx.Done():
+	case <-e.unpaused():
+	}
+
 	select {
 	case <-e.done:
 	case <-e.ctx.Done():
 	case <-srv.Done():
 	case srv.Input() <- req:
+		e.stats.incRequests(srv.String())
 	}
 	finished <- srv.String()
 }
@@ -195,6 +382,7 @@ func (e *Enumeration) makeOutputSink() pipeline.SinkFunc {
 
 		req, ok := data.(*requests.DNSRequest)
 		if ok && req != nil && req.Name != "" && e.Config.IsDomainInScope(req.Name) {
+			e.checkpoints.resolve(req)
 			if _, err := e.graph.UpsertFQDN(e.ctx, req.Name, req.Source, e.Config.UUID.String()); err != nil {
 				e.Config.Log.Print(err.Error())
 			}
@@ -203,6 +391,154 @@ func (e *Enumeration) makeOutputSink() pipeline.SinkFunc {
 	})
 }
 
+// insertEmail stores the email address in the graph and links it to the FQDN of the mail domain.
+func (e *Enumeration) insertEmail(ctx context.Context, req *requests.EmailRequest) error {
+	uuid := e.Config.UUID.String()
+	host := req.Email[strings.LastIndex(req.Email, "@")+1:]
+
+	if _, err := e.graph.UpsertFQDN(ctx, host, req.Source, uuid); err != nil {
+		return fmt.Errorf("%s failed to insert the email domain: %v", e.graph, err)
+	}
+
+	email, err := e.graph.UpsertNode(ctx, req.Email, "email")
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the email address: %v", e.graph, err)
+	}
+	if err := e.graph.AddNodeToEvent(ctx, email, req.Source, uuid); err != nil {
+		return fmt.Errorf("%s failed to add the email address to the event: %v", e.graph, err)
+	}
+	return e.graph.UpsertEdge(ctx, &netmap.Edge{
+		Predicate: "email",
+		From:      netmap.Node(host),
+		To:        email,
+	})
+}
+
+// insertCert stores the certificate metadata in the graph and links the certificate
+// to the address it was obtained from and the names in scope that it covers.
+func (e *Enumeration) insertCert(ctx context.Context, req *requests.CertRequest) error {
+	uuid := e.Config.UUID.String()
+	serial := requests.NormalizeSerial(req.Serial)
+
+	cert, err := e.graph.UpsertNode(ctx, requests.CertNodeID(serial), "certificate")
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the certificate: %v", e.graph, err)
+	}
+	if err := e.graph.AddNodeToEvent(ctx, cert, req.Source, uuid); err != nil {
+		return fmt.Errorf("%s failed to add the certificate to the event: %v", e.graph, err)
+	}
+
+	props := map[string]string{
+		"serial":  serial,
+		"issuer":  req.Issuer,
+		"subject": req.Subject,
+	}
+	if !req.NotBefore.IsZero() {
+		props["not_before"] = req.NotBefore.UTC().Format(time.RFC3339)
+	}
+	if !req.NotAfter.IsZero() {
+		props["not_after"] = req.NotAfter.UTC().Format(time.RFC3339)
+	}
+	for pred, val := range props {
+		if val == "" {
+			continue
+		}
+		if err := e.graph.UpsertProperty(ctx, cert, pred, val); err != nil {
+			return fmt.Errorf("%s failed to insert the certificate %s: %v", e.graph, pred, err)
+		}
+	}
+
+	for _, name := range req.Names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if err := e.graph.UpsertProperty(ctx, cert, "san", name); err != nil {
+			return fmt.Errorf("%s failed to insert the certificate SAN: %v", e.graph, err)
+		}
+		if !e.Config.IsDomainInScope(name) {
+			continue
+		}
+
+		fqdn, err := e.graph.UpsertFQDN(ctx, name, req.Source, uuid)
+		if err != nil {
+			return fmt.Errorf("%s failed to insert the certificate name: %v", e.graph, err)
+		}
+		if err := e.graph.UpsertEdge(ctx, &netmap.Edge{
+			Predicate: "certificate",
+			From:      fqdn,
+			To:        cert,
+		}); err != nil {
+			return fmt.Errorf("%s failed to link the certificate name: %v", e.graph, err)
+		}
+	}
+
+	if req.Address == "" {
+		return nil
+	}
+	addr, err := e.graph.UpsertAddress(ctx, req.Address, req.Source, uuid)
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the certificate address: %v", e.graph, err)
+	}
+	return e.graph.UpsertEdge(ctx, &netmap.Edge{
+		Predicate: "certificate",
+		From:      addr,
+		To:        cert,
+	})
+}
+
+// The properties describing the web service, which are replaced each time the service is probed.
+var httpServicePredicates = []string{"status_code", "server", "title", "redirect", "technology"}
+
+func (e *Enumeration) insertHTTPService(ctx context.Context, name string, fp *http.Fingerprint, source string) error {
+	uuid := e.Config.UUID.String()
+
+	fqdn, err := e.graph.UpsertFQDN(ctx, name, source, uuid)
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the web service name: %v", e.graph, err)
+	}
+
+	svc, err := e.graph.UpsertNode(ctx, fp.URL, "http_service")
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the web service: %v", e.graph, err)
+	}
+	if err := e.graph.AddNodeToEvent(ctx, svc, source, uuid); err != nil {
+		return fmt.Errorf("%s failed to add the web service to the event: %v", e.graph, err)
+	}
+	if err := e.graph.UpsertEdge(ctx, &netmap.Edge{
+		Predicate: "http_service",
+		From:      fqdn,
+		To:        svc,
+	}); err != nil {
+		return fmt.Errorf("%s failed to link the web service: %v", e.graph, err)
+	}
+
+	if props, err := e.graph.ReadProperties(ctx, svc, httpServicePredicates...); err == nil {
+		for _, p := range props {
+			_ = e.graph.DeleteProperty(ctx, svc, p.Predicate, p.Value)
+		}
+	}
+
+	props := map[string][]string{
+		"status_code": {strconv.Itoa(fp.StatusCode)},
+		"server":      {fp.Server},
+		"title":       {fp.Title},
+		"redirect":    {fp.Redirect},
+		"technology":  fp.Technologies,
+	}
+	for pred, vals := range props {
+		for _, val := range vals {
+			if val == "" {
+				continue
+			}
+			if err := e.graph.UpsertProperty(ctx, svc, pred, val); err != nil {
+				return fmt.Errorf("%s failed to insert the web service %s: %v", e.graph, pred, err)
+			}
+		}
+	}
+	return nil
+}
+
 func (e *Enumeration) submitKnownNames() {
 	srcTags := make(map[string]string)
 	for _, src := range e.Sys.DataSources() {
@@ -226,9 +562,12 @@ func (e *Enumeration) readNamesFromDatabase(db *netmap.Graph, stags map[string]s
 			}
 
 			domain := e.Config.WhichDomain(name)
-			if domain == "" {
+			if domain == "" || e.isFresh(name) {
 				continue
 			}
+			if !e.nameSrc.waitForCapacity() {
+				return
+			}
 			if srcs, err := db.NodeSources(e.ctx, netmap.Node(name), event); err == nil {
 				src := srcs[0]
 				tag := stags[src]
@@ -251,6 +590,9 @@ func (e *Enumeration) submitProvidedNames() {
 		default:
 		}
 		if domain := e.Config.WhichDomain(name); domain != "" {
+			if !e.nameSrc.waitForCapacity() {
+				return
+			}
 			e.nameSrc.newName(&requests.DNSRequest{
 				Name:   name,
 				Domain: domain,
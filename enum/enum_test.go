@@ -0,0 +1,203 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/queue"
+)
+
+func TestInsertCert(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	e := &Enumeration{Config: cfg, graph: g}
+	for _, addr := range []string{"192.168.1.1", "192.168.1.2"} {
+		if err := e.insertCert(ctx, &requests.CertRequest{
+			Serial:   "00:0A:BC",
+			Issuer:   "CN=R3,O=Let's Encrypt,C=US",
+			Names:    []string{"www.owasp.org", "www.example.com"},
+			NotAfter: time.Date(2021, time.May, 30, 12, 0, 0, 0, time.UTC),
+			Address:  addr,
+			Domain:   "owasp.org",
+			Tag:      requests.CERT,
+			Source:   "Active Cert",
+		}); err != nil {
+			t.Fatalf("Failed to insert the certificate: %v", err)
+		}
+	}
+
+	cert := netmap.Node("cert:abc")
+	if props, err := g.ReadProperties(ctx, cert, "not_after"); err != nil || len(props) != 1 {
+		t.Fatalf("The certificate expiration was not stored: %v", err)
+	}
+	if props, err := g.ReadProperties(ctx, cert, "san"); err != nil || len(props) != 2 {
+		t.Errorf("The certificate names were not stored")
+	}
+	// Both addresses and the name in scope share the certificate
+	edges, err := g.ReadInEdges(ctx, cert, "certificate")
+	if err != nil || len(edges) != 3 {
+		t.Errorf("The certificate was linked to %d nodes, expected 3", len(edges))
+	}
+}
+
+func TestInsertHTTPService(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	e := &Enumeration{Config: cfg, graph: g}
+	for _, fp := range []*http.Fingerprint{
+		{
+			URL:          "https://www.owasp.org:443",
+			StatusCode:   200,
+			Server:       "nginx",
+			Title:        "OWASP",
+			Technologies: []string{"Nginx", "PHP"},
+		},
+		{
+			URL:          "https://www.owasp.org:443",
+			StatusCode:   301,
+			Server:       "cloudflare",
+			Technologies: []string{"Cloudflare"},
+		},
+	} {
+		if err := e.insertHTTPService(ctx, "www.owasp.org", fp, "Active Crawl"); err != nil {
+			t.Fatalf("Failed to insert the web service: %v", err)
+		}
+	}
+
+	svc := netmap.Node("https://www.owasp.org:443")
+	// The second probe replaces the properties from the first
+	props, err := g.ReadProperties(ctx, svc, httpServicePredicates...)
+	if err != nil || len(props) != 3 {
+		t.Fatalf("The web service has %d properties, expected 3", len(props))
+	}
+	for _, p := range props {
+		if p.Predicate == "status_code" && p.Value.Native().(string) != "301" {
+			t.Errorf("The status code was not replaced")
+		}
+	}
+	if edges, err := g.ReadInEdges(ctx, svc, "http_service"); err != nil || len(edges) != 1 {
+		t.Errorf("The web service was not linked to the name")
+	}
+}
+
+func TestStoreConfidence(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	uuid := cfg.UUID.String()
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	e := &Enumeration{Config: cfg, graph: g}
+	e.guessers = newGuessers(e)
+
+	if err := g.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", uuid); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if _, err := g.UpsertFQDN(ctx, "www.owasp.org", "Active Cert", uuid); err != nil {
+		t.Fatalf("Failed to insert the name: %v", err)
+	}
+	if _, err := g.UpsertFQDN(ctx, "dev.owasp.org", "Active Crawl", uuid); err != nil {
+		t.Fatalf("Failed to insert the name: %v", err)
+	}
+
+	// Scoring the enumeration again replaces the previous values
+	for i := 0; i < 2; i++ {
+		if err := e.storeConfidence(ctx); err != nil {
+			t.Fatalf("Failed to store the confidence: %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		"www.owasp.org": strconv.Itoa(requests.ConfidenceCorroborated),
+		"192.168.1.1":   strconv.Itoa(requests.ConfidenceCorroborated),
+		"dev.owasp.org": strconv.Itoa(requests.ConfidenceReported),
+	}
+	for id, conf := range expected {
+		props, err := g.ReadProperties(ctx, netmap.Node(id), requests.ConfidencePredicate)
+		if err != nil || len(props) != 1 {
+			t.Errorf("%s has %d confidence values, expected one", id, len(props))
+			continue
+		}
+		if v := props[0].Value.Native().(string); v != conf {
+			t.Errorf("%s has confidence %s, expected %s", id, v, conf)
+		}
+	}
+}
+
+func TestWaitForCapacity(t *testing.T) {
+	src := &enumSource{
+		queue: queue.NewQueue(),
+		done:  make(chan struct{}),
+		limit: 2,
+	}
+	src.queue.Append(&requests.DNSRequest{Name: "www.owasp.org"})
+	if !src.waitForCapacity() {
+		t.Fatalf("The producer was held before the queue reached the limit")
+	}
+
+	src.queue.Append(&requests.DNSRequest{Name: "dev.owasp.org"})
+	released := make(chan bool, 1)
+	go func() { released <- src.waitForCapacity() }()
+
+	select {
+	case <-released:
+		t.Fatalf("The producer was not held by the full queue")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	src.queue.Next()
+	select {
+	case ok := <-released:
+		if !ok {
+			t.Errorf("The producer was not allowed to continue")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("The producer was still held after the queue had room")
+	}
+
+	src.queue.Append(&requests.DNSRequest{Name: "api.owasp.org"})
+	src.markDone()
+	if src.waitForCapacity() {
+		t.Errorf("The producer continued after the enumeration was done")
+	}
+}
+
+func TestTrimBacklog(t *testing.T) {
+	var backlog []interface{}
+
+	backlog = append(backlog, &requests.DNSRequest{Name: "owasp.org", Domain: "owasp.org"})
+	for i := 0; i < maxSourceBacklog; i++ {
+		backlog = trimBacklog(append(backlog, &requests.ResolvedRequest{Name: strconv.Itoa(i) + ".owasp.org"}))
+	}
+	if len(backlog) != maxSourceBacklog {
+		t.Fatalf("The backlog holds %d requests, expected %d", len(backlog), maxSourceBacklog)
+	}
+	if _, ok := backlog[0].(*requests.DNSRequest); !ok {
+		t.Errorf("The request for the root domain name was dropped")
+	}
+	if req := backlog[1].(*requests.ResolvedRequest); req.Name != "1.owasp.org" {
+		t.Errorf("The oldest request for a resolved name was not dropped: %s", req.Name)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// Maximum time an external guesser is given to generate the names for a single resolved name
+	externalGuessTimeout = 30 * time.Second
+	// Maximum number of names read from an external guesser for a single resolved name
+	maxExternalGuesses = 10000
+)
+
+// externalGuesser pipes each resolved name to an external command, such as dnsgen or alterx,
+// and reads the generated names from its standard output, one name per line.
+type externalGuesser struct {
+	path string
+	args []string
+}
+
+func newExternalGuesser(command string) (*externalGuesser, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("The external guesser command is empty")
+	}
+
+	path, err := exec.LookPath(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("Unable to find the external guesser %s: %v", fields[0], err)
+	}
+	return &externalGuesser{
+		path: path,
+		args: fields[1:],
+	}, nil
+}
+
+// String implements the Guesser interface.
+func (x *externalGuesser) String() string {
+	return "External Guesser: " + filepath.Base(x.path)
+}
+
+// Guess implements the Guesser interface.
+func (x *externalGuesser) Guess(ctx context.Context, name, domain string) []string {
+	name = strings.ToLower(name)
+	domain = strings.ToLower(domain)
+
+	ctx, cancel := context.WithTimeout(ctx, externalGuessTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, x.path, x.args...)
+	cmd.Stdin = strings.NewReader(name + "\n")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var names []string
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		n := strings.Trim(strings.ToLower(strings.TrimSpace(scanner.Text())), ".")
+		// Only the names within the domain of the resolved name are accepted
+		if n == "" || n == name || (n != domain && !strings.HasSuffix(n, "."+domain)) {
+			continue
+		}
+		if _, found := seen[n]; found {
+			continue
+		}
+
+		seen[n] = struct{}{}
+		names = append(names, n)
+		if len(names) >= maxExternalGuesses {
+			break
+		}
+	}
+	// The command is stopped when it generates more names than will be accepted
+	cancel()
+	_ = cmd.Wait()
+	return names
+}
@@ -0,0 +1,103 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"net"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+)
+
+// Copies the names resolved by the enumerations finished within the freshness window into this
+// enumeration, along with their addresses, so they are reported without being resolved again.
+// The names confirmed earlier are left to be resolved again by readNamesFromDatabase.
+func (e *Enumeration) copyFreshNames() {
+	hours := e.Config.FreshnessHours
+	if hours <= 0 {
+		return
+	}
+
+	e.fresh = stringset.New()
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	for _, db := range e.Sys.GraphDatabases() {
+		e.copyFreshNamesFromDatabase(db, since)
+	}
+
+	if n := e.fresh.Len(); n > 0 {
+		e.Config.Log.Printf("Reusing %d names confirmed within the last %d hours without resolving them again", n, hours)
+	}
+}
+
+func (e *Enumeration) copyFreshNamesFromDatabase(db *netmap.Graph, since time.Time) {
+	uuid := e.Config.UUID.String()
+
+	for _, event := range db.EventsInScope(e.ctx, e.Config.Domains()...) {
+		if event == uuid {
+			continue
+		}
+		if _, finish := db.EventDateRange(e.ctx, event); finish.Before(since) {
+			continue
+		}
+
+		var names []string
+		for _, name := range db.EventFQDNs(e.ctx, event) {
+			// The root domain names are always processed, since the data sources start from them
+			if d := e.Config.WhichDomain(name); d != "" && d != name && !e.fresh.Has(name) {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		pairs, err := db.NamesToAddrs(e.ctx, event, names...)
+		if err != nil {
+			continue
+		}
+		for _, p := range pairs {
+			select {
+			case <-e.ctx.Done():
+				return
+			default:
+			}
+
+			if p.Name == "" || p.Addr == "" {
+				continue
+			}
+			if e.copyFreshName(db, event, p.Name, p.Addr) {
+				e.fresh.Insert(p.Name)
+			}
+		}
+	}
+}
+
+// Inserts the name confirmed by the earlier event and its address into this enumeration.
+func (e *Enumeration) copyFreshName(db *netmap.Graph, event, name, addr string) bool {
+	uuid := e.Config.UUID.String()
+
+	source := "DNS"
+	if srcs, err := db.NodeSources(e.ctx, netmap.Node(name), event); err == nil && len(srcs) > 0 {
+		source = srcs[0]
+	}
+	if _, err := e.graph.UpsertFQDN(e.ctx, name, source, uuid); err != nil {
+		return false
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	if ip.To4() != nil {
+		return e.graph.UpsertA(e.ctx, name, addr, "DNS", uuid) == nil
+	}
+	return e.graph.UpsertAAAA(e.ctx, name, addr, "DNS", uuid) == nil
+}
+
+// Returns true when the name was copied from an enumeration finished within the freshness window.
+func (e *Enumeration) isFresh(name string) bool {
+	return e.fresh != nil && e.fresh.Has(name)
+}
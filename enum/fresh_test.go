@@ -0,0 +1,58 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/netmap"
+)
+
+func TestCopyFreshNames(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	earlier := config.NewConfig().UUID.String()
+	if err := g.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", earlier); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := g.UpsertAAAA(ctx, "www.owasp.org", "2001:db8::1", "DNS", earlier); err != nil {
+		t.Fatalf("Failed to insert the AAAA record: %v", err)
+	}
+	// Names without addresses were not confirmed by the earlier enumeration
+	if _, err := g.UpsertFQDN(ctx, "dev.owasp.org", "Crtsh", earlier); err != nil {
+		t.Fatalf("Failed to insert the name: %v", err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	e := &Enumeration{
+		Config: cfg,
+		Sys:    &systems.SimpleSystem{Cfg: cfg, Graph: g},
+		graph:  g,
+		ctx:    ctx,
+	}
+
+	e.copyFreshNames()
+	if e.isFresh("www.owasp.org") {
+		t.Errorf("The names were reused without a freshness window")
+	}
+
+	cfg.FreshnessHours = 24
+	e.copyFreshNames()
+	defer e.fresh.Close()
+	if !e.isFresh("www.owasp.org") || e.isFresh("dev.owasp.org") || e.isFresh("owasp.org") {
+		t.Errorf("The names confirmed within the freshness window were not selected")
+	}
+
+	pairs, err := g.NamesToAddrs(ctx, cfg.UUID.String(), "www.owasp.org")
+	if err != nil || len(pairs) != 2 {
+		t.Errorf("The addresses of the fresh name were not copied into the enumeration: %v", err)
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/queue"
+)
+
+// Guesser is implemented by the generators that guess new names from the names resolved during
+// the enumeration. The value returned by String is used as the source of the generated names.
+type Guesser interface {
+	// String returns the name of the guesser
+	String() string
+
+	// Guess returns the new names generated from the resolved name
+	Guess(ctx context.Context, name, domain string) []string
+}
+
+// GuessFeedback is implemented by the guessers that bias further generation toward the
+// patterns that produced names that resolved.
+type GuessFeedback interface {
+	// Resolved is called when a name generated by the guesser resolves
+	Resolved(name string)
+}
+
+const (
+	// Parents receiving this many guesses without a single hit are no longer guessed into
+	maxGuessesWithoutHit = 100
+	// Limits the number of generated names remembered by a guesser to attribute the hits
+	maxTrackedGuesses = 100000
+)
+
+// guesser tracks the number of names submitted by a Guesser against its budget.
+type guesser struct {
+	Guesser
+	budget    int
+	submitted int
+}
+
+// guessers provides the resolved names to each Guesser and submits the generated names to the enumeration.
+type guessers struct {
+	sync.Mutex
+	enum    *Enumeration
+	list    []*guesser
+	queue   queue.Queue
+	parents map[string]*parentGuesses
+}
+
+// parentGuesses counts the guesses made within a subdomain and the guesses that resolved.
+type parentGuesses struct {
+	guessed int
+	hits    int
+}
+
+func newGuessers(e *Enumeration) *guessers {
+	return &guessers{
+		enum:    e,
+		queue:   queue.NewQueue(),
+		parents: make(map[string]*parentGuesses),
+	}
+}
+
+// AddGuesser registers the Guesser with the enumeration, which submits at most budget of the generated
+// names, or all of them when the budget is zero. Guessers must be added before the enumeration is started.
+func (e *Enumeration) AddGuesser(g Guesser, budget int) {
+	if budget < 0 {
+		budget = 0
+	}
+
+	e.guessers.Lock()
+	defer e.guessers.Unlock()
+
+	e.guessers.list = append(e.guessers.list, &guesser{
+		Guesser: g,
+		budget:  budget,
+	})
+}
+
+// Guessers returns the names of the guessers registered with the enumeration.
+func (e *Enumeration) Guessers() []string {
+	e.guessers.Lock()
+	defer e.guessers.Unlock()
+
+	var names []string
+	for _, g := range e.guessers.list {
+		names = append(names, g.String())
+	}
+	return names
+}
+
+// resolved queues the resolved name to be provided to the guessers.
+func (gs *guessers) resolved(req *requests.DNSRequest) {
+	gs.Lock()
+	defer gs.Unlock()
+
+	// Only the names are kept, since the records of the resolved names are not used by the guessers
+	if len(gs.list) > 0 {
+		gs.queue.Append(&requests.DNSRequest{
+			Name:   req.Name,
+			Domain: req.Domain,
+		})
+	}
+}
+
+// hit attributes the resolved name to the guesser that generated it, and to the subdomain it belongs to.
+func (gs *guessers) hit(req *requests.DNSRequest) {
+	if req.Tag != requests.ALT {
+		return
+	}
+
+	gs.Lock()
+	var g *guesser
+	for _, cur := range gs.list {
+		if cur.String() == req.Source {
+			g = cur
+			break
+		}
+	}
+	if g != nil {
+		if p, found := gs.parents[guessParent(req.Name)]; found {
+			p.hits++
+		}
+	}
+	gs.Unlock()
+
+	if g == nil {
+		return
+	}
+	if f, ok := g.Guesser.(GuessFeedback); ok {
+		f.Resolved(req.Name)
+	}
+}
+
+// Returns true once the subdomain has received enough guesses without any of them resolving.
+func (gs *guessers) throttled(parent string) bool {
+	gs.Lock()
+	defer gs.Unlock()
+
+	p, found := gs.parents[parent]
+	return found && p.hits == 0 && p.guessed >= maxGuessesWithoutHit
+}
+
+// Counts a guess submitted within the subdomain and against the budget of the guesser.
+func (gs *guessers) guessed(g *guesser, parent string) {
+	gs.Lock()
+	defer gs.Unlock()
+
+	g.submitted++
+	p, found := gs.parents[parent]
+	if !found {
+		p = new(parentGuesses)
+		gs.parents[parent] = p
+	}
+
+	p.guessed++
+	if p.hits == 0 && p.guessed == maxGuessesWithoutHit {
+		gs.enum.Config.Log.Printf("Guessing: %s: No hits after %d guesses, no longer guessing names within it", parent, p.guessed)
+	}
+}
+
+func guessParent(name string) string {
+	if parts := strings.SplitN(name, ".", 2); len(parts) == 2 {
+		return parts[1]
+	}
+	return name
+}
+
+// The guessers are executed outside the pipeline, so slow generators do not delay the resolutions.
+func (gs *guessers) process(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			gs.queue.Process(func(e interface{}) {})
+			return
+		case <-gs.queue.Signal():
+		}
+
+		e, ok := gs.queue.Next()
+		if !ok {
+			continue
+		}
+
+		req := e.(*requests.DNSRequest)
+		for _, g := range gs.active() {
+			gs.guess(ctx, g, req)
+		}
+	}
+}
+
+// Returns the guessers that have not exhausted their budget.
+func (gs *guessers) active() []*guesser {
+	gs.Lock()
+	defer gs.Unlock()
+
+	var list []*guesser
+	for _, g := range gs.list {
+		if g.budget == 0 || g.submitted < g.budget {
+			list = append(list, g)
+		}
+	}
+	return list
+}
+
+func (gs *guessers) guess(ctx context.Context, g *guesser, req *requests.DNSRequest) {
+	src := g.String()
+	gs.enum.stats.incRequests(src)
+
+	for _, name := range g.Guess(ctx, req.Name, req.Domain) {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		gs.Lock()
+		exhausted := g.budget > 0 && g.submitted >= g.budget
+		gs.Unlock()
+		if exhausted {
+			gs.enum.Config.Log.Printf("%s: The budget of %d guesses has been exhausted", src, g.budget)
+			return
+		}
+
+		parent := guessParent(name)
+		// Subdomains where the guesses never land are skipped
+		if gs.throttled(parent) {
+			continue
+		}
+		// The guesses wait for the queue of names to be resolved
+		if !gs.enum.nameSrc.waitForCapacity() {
+			return
+		}
+
+		unique := gs.enum.nameSrc.newName(&requests.DNSRequest{
+			Name:   name,
+			Domain: req.Domain,
+			Tag:    requests.ALT,
+			Source: src,
+		})
+		gs.enum.stats.incNames(src, unique)
+		// Only the names new to the enumeration are resolved, so the others are not counted
+		if unique {
+			gs.guessed(g, parent)
+		}
+	}
+}
+
+// ruleGuesser applies the "hashcat-style" alteration rules to the first label of the resolved names.
+// The names generated by the rules with the most hits are provided first.
+type ruleGuesser struct {
+	sync.Mutex
+	rules  []*config.AlterationRule
+	hits   []int
+	origin map[string]int
+}
+
+func newRuleGuesser(cfg *config.Config) (*ruleGuesser, error) {
+	rules, err := config.ParseAlterationRules(cfg.AltRules)
+	if err != nil {
+		return nil, err
+	}
+	return &ruleGuesser{
+		rules:  rules,
+		hits:   make([]int, len(rules)),
+		origin: make(map[string]int),
+	}, nil
+}
+
+// String implements the Guesser interface.
+func (r *ruleGuesser) String() string {
+	return "Alteration Rules"
+}
+
+// Guess implements the Guesser interface.
+func (r *ruleGuesser) Guess(ctx context.Context, name, domain string) []string {
+	parts := strings.SplitN(strings.ToLower(name), ".", 2)
+	// Do not alter the root domain names
+	if len(parts) < 2 || len(name) <= len(domain) {
+		return nil
+	}
+
+	type guess struct {
+		name string
+		rule int
+	}
+
+	var guesses []guess
+	for i, rule := range r.rules {
+		if label, ok := rule.Apply(parts[0]); ok {
+			guesses = append(guesses, guess{name: label + "." + parts[1], rule: i})
+		}
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	sort.SliceStable(guesses, func(i, j int) bool {
+		return r.hits[guesses[i].rule] > r.hits[guesses[j].rule]
+	})
+
+	var names []string
+	for _, g := range guesses {
+		if _, found := r.origin[g.name]; !found && len(r.origin) < maxTrackedGuesses {
+			r.origin[g.name] = g.rule
+		}
+		names = append(names, g.name)
+	}
+	return names
+}
+
+// Resolved implements the GuessFeedback interface.
+func (r *ruleGuesser) Resolved(name string) {
+	r.Lock()
+	defer r.Unlock()
+
+	if i, found := r.origin[name]; found {
+		r.hits[i]++
+		delete(r.origin, name)
+	}
+}
+
+// keywordGuesser combines the keyword sets from the configuration with the first label of the resolved
+// names. Keywords found in the label are exchanged for the other keywords in the same set, and the
+// keywords of the sets not found in the label are added as a prefix and a suffix.
+type keywordGuesser struct {
+	sets [][]string
+}
+
+func newKeywordGuesser(cfg *config.Config) *keywordGuesser {
+	var names []string
+	for name := range cfg.AltKeywords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sets [][]string
+	for _, name := range names {
+		words := append([]string(nil), cfg.AltKeywords[name]...)
+		// Longer keywords are matched first, so us-east-1 is not mistaken for us-east
+		sort.Slice(words, func(i, j int) bool {
+			if len(words[i]) != len(words[j]) {
+				return len(words[i]) > len(words[j])
+			}
+			return words[i] < words[j]
+		})
+		sets = append(sets, words)
+	}
+	return &keywordGuesser{sets: sets}
+}
+
+// String implements the Guesser interface.
+func (k *keywordGuesser) String() string {
+	return "Alteration Keywords"
+}
+
+// Guess implements the Guesser interface.
+func (k *keywordGuesser) Guess(ctx context.Context, name, domain string) []string {
+	parts := strings.SplitN(strings.ToLower(name), ".", 2)
+	// Do not alter the root domain names
+	if len(parts) < 2 || len(name) <= len(domain) {
+		return nil
+	}
+
+	label := parts[0]
+	seen := make(map[string]struct{})
+	var names []string
+	add := func(l string) {
+		if _, found := seen[l]; !found && l != label && len(l) <= 63 {
+			seen[l] = struct{}{}
+			names = append(names, l+"."+parts[1])
+		}
+	}
+
+	for _, set := range k.sets {
+		if found := keywordInLabel(label, set); found != "" {
+			padded := "-" + label + "-"
+			for _, word := range set {
+				if word != found {
+					add(strings.Trim(strings.Replace(padded, "-"+found+"-", "-"+word+"-", 1), "-"))
+				}
+			}
+			continue
+		}
+
+		for _, word := range set {
+			add(word + "-" + label)
+			add(label + "-" + word)
+		}
+	}
+	return names
+}
+
+// Returns the keyword from the set that is one of the hyphen-separated parts of the label.
+func keywordInLabel(label string, set []string) string {
+	padded := "-" + label + "-"
+
+	for _, word := range set {
+		if strings.Contains(padded, "-"+word+"-") {
+			return word
+		}
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+func TestRuleGuesser(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AltRules = []string{"$-$d$e$v", "so0", "t"}
+
+	g, err := newRuleGuesser(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create the rule guesser: %v", err)
+	}
+
+	names := g.Guess(context.Background(), "api.portal.owasp.org", "owasp.org")
+	if len(names) != 1 || names[0] != "api-dev.portal.owasp.org" {
+		t.Errorf("The rules generated the wrong names: %v", names)
+	}
+	if names := g.Guess(context.Background(), "owasp.org", "owasp.org"); len(names) > 0 {
+		t.Errorf("The rules altered the root domain name: %v", names)
+	}
+
+	cfg.AltRules = []string{"Y"}
+	if _, err := newRuleGuesser(cfg); err == nil {
+		t.Errorf("The rule guesser accepted an invalid rule")
+	}
+}
+
+func TestGuesserBudgets(t *testing.T) {
+	e := &Enumeration{Config: config.NewConfig()}
+	e.guessers = newGuessers(e)
+	g, _ := newRuleGuesser(e.Config)
+
+	e.AddGuesser(g, 2)
+	e.AddGuesser(g, 0)
+	if names := e.Guessers(); len(names) != 2 || names[0] != g.String() {
+		t.Errorf("The guessers were not registered: %v", names)
+	}
+
+	e.guessers.list[0].submitted = 2
+	e.guessers.list[1].submitted = 1000
+	if active := e.guessers.active(); len(active) != 1 || active[0].budget != 0 {
+		t.Errorf("The guesser that exhausted its budget remained active")
+	}
+}
+
+func TestRuleGuesserFeedback(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AltRules = []string{"$1", "$2"}
+	g, _ := newRuleGuesser(cfg)
+
+	names := g.Guess(context.Background(), "api.owasp.org", "owasp.org")
+	if len(names) != 2 || names[0] != "api1.owasp.org" {
+		t.Fatalf("The rules generated the wrong names: %v", names)
+	}
+
+	g.Resolved("api2.owasp.org")
+	if names := g.Guess(context.Background(), "www.owasp.org", "owasp.org"); len(names) != 2 || names[0] != "www2.owasp.org" {
+		t.Errorf("The productive rule was not applied first: %v", names)
+	}
+}
+
+func TestGuessersThrottleParents(t *testing.T) {
+	e := &Enumeration{Config: config.NewConfig()}
+	e.guessers = newGuessers(e)
+	g, _ := newRuleGuesser(e.Config)
+	e.AddGuesser(g, 0)
+	gs := e.guessers
+
+	for i := 0; i < maxGuessesWithoutHit; i++ {
+		gs.guessed(gs.list[0], "dev.owasp.org")
+		gs.guessed(gs.list[0], "www.owasp.org")
+	}
+	gs.hit(&requests.DNSRequest{Name: "app.www.owasp.org", Tag: requests.ALT, Source: g.String()})
+
+	if !gs.throttled("dev.owasp.org") {
+		t.Errorf("The subdomain without hits was not throttled")
+	}
+	if gs.throttled("www.owasp.org") || gs.throttled("owasp.org") {
+		t.Errorf("The productive subdomains were throttled")
+	}
+}
+
+func TestKeywordGuesser(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddAltKeywords("environment", "prod", "dev")
+	cfg.AddAltKeywords("region", "us-east", "us-east-1")
+	g := newKeywordGuesser(cfg)
+
+	names := g.Guess(context.Background(), "api-prod.owasp.org", "owasp.org")
+	expected := map[string]struct{}{
+		"api-dev.owasp.org":            {},
+		"us-east-api-prod.owasp.org":   {},
+		"api-prod-us-east.owasp.org":   {},
+		"us-east-1-api-prod.owasp.org": {},
+		"api-prod-us-east-1.owasp.org": {},
+	}
+	if len(names) != len(expected) {
+		t.Errorf("The keywords generated %d names, expected %d: %v", len(names), len(expected), names)
+	}
+	for _, n := range names {
+		if _, found := expected[n]; !found {
+			t.Errorf("The keywords generated an unexpected name: %s", n)
+		}
+	}
+
+	names = g.Guess(context.Background(), "db-us-east-1.owasp.org", "owasp.org")
+	found := make(map[string]struct{})
+	for _, n := range names {
+		found[n] = struct{}{}
+	}
+	if _, ok := found["db-us-east.owasp.org"]; !ok || len(names) != 5 {
+		t.Errorf("The keywords generated the wrong names: %v", names)
+	}
+	if _, ok := found["db-us-east-1-1.owasp.org"]; ok {
+		t.Errorf("The shorter keyword was matched within the longer keyword")
+	}
+}
+
+func TestGuessBudget(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxGuesses = 2
+	src := &enumSource{enum: &Enumeration{Config: cfg}}
+
+	for i := 0; i < cfg.MaxGuesses; i++ {
+		if src.guessBudgetExhausted() {
+			t.Fatalf("The guess budget was exhausted after %d guesses", i)
+		}
+		src.countGuess()
+	}
+	if !src.guessBudgetExhausted() {
+		t.Errorf("The guess budget was not exhausted after %d guesses", cfg.MaxGuesses)
+	}
+
+	cfg.MaxGuesses = 0
+	if src.guessBudgetExhausted() {
+		t.Errorf("The guesses were limited without a budget")
+	}
+}
+
+func TestDNSBudget(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen for the DNS test server: %v", err)
+	}
+	srv := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetRcode(req, dns.RcodeNameError)
+		_ = w.WriteMsg(resp)
+	})}
+	go func() { _ = srv.ActivateAndServe() }()
+	defer func() { _ = srv.Shutdown() }()
+
+	pool := resolvers.NewPool()
+	defer pool.Stop()
+	_ = pool.AddResolvers(10, pc.LocalAddr().String())
+
+	cfg := config.NewConfig()
+	cfg.DNSBudget = 2
+	src := &enumSource{enum: &Enumeration{Config: cfg, Sys: &systems.SimpleSystem{Cfg: cfg, Pool: pool}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < cfg.DNSBudget; i++ {
+		if src.dnsBudgetExhausted() {
+			t.Fatalf("The DNS query budget was exhausted after %d queries", i)
+		}
+		_, _ = pool.QueryBlocking(ctx, resolve.QueryMsg("www.owasp.org", dns.TypeA))
+	}
+	if !src.dnsBudgetExhausted() {
+		t.Errorf("The DNS query budget was not exhausted after %d queries", cfg.DNSBudget)
+	}
+
+	cfg.DNSBudget = 0
+	if src.dnsBudgetExhausted() {
+		t.Errorf("The DNS queries were limited without a budget")
+	}
+}
+
+func TestStageTimeout(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.BruteTimeout = 50 * time.Millisecond
+	src := &enumSource{enum: &Enumeration{Config: cfg}}
+
+	if src.stageExpired(requests.BRUTE) {
+		t.Fatalf("The brute forcing stage expired before generating its first name")
+	}
+	time.Sleep(2 * cfg.BruteTimeout)
+	if !src.stageExpired(requests.BRUTE) {
+		t.Errorf("The brute forcing stage did not expire after its time budget")
+	}
+	if src.stageExpired(requests.ALT) {
+		t.Errorf("The alterations expired without a time budget")
+	}
+}
+
+func TestExternalGuesser(t *testing.T) {
+	if _, err := newExternalGuesser(""); err == nil {
+		t.Errorf("The external guesser accepted an empty command")
+	}
+	if _, err := newExternalGuesser("nonexistent-amass-guesser"); err == nil {
+		t.Errorf("The external guesser accepted a missing command")
+	}
+
+	// The command generates a name within the domain and one outside of it
+	g, err := newExternalGuesser("sed -e p -e s/^/dev-/p -e s/owasp.org/example.com/")
+	if err != nil {
+		t.Skipf("The sed command is not available: %v", err)
+	}
+
+	names := g.Guess(context.Background(), "API.owasp.org", "owasp.org")
+	if len(names) != 1 || names[0] != "dev-api.owasp.org" {
+		t.Errorf("The external guesser returned the wrong names: %v", names)
+	}
+}
@@ -6,45 +6,51 @@ package enum
 
 import (
 	"context"
-	"net"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/net/dns"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
 	"github.com/caffix/service"
 	bf "github.com/tylertreat/BoomFilters"
 )
 
-const (
-	waitForDuration  = 10 * time.Second
-	defaultSweepSize = 500
-	activeSweepSize  = 1000
-)
+const waitForDuration = 10 * time.Second
+
+// The names waiting to be resolved are limited to this many seconds of queries at the maximum rate,
+// so the producers outside the pipeline slow down when the resolution is saturated.
+const queuedSecondsOfQueries = 10
 
 // enumSource handles the filtering and release of new Data in the enumeration.
 type enumSource struct {
-	enum        *Enumeration
-	queue       queue.Queue
-	dups        queue.Queue
-	sweeps      queue.Queue
-	filter      *bf.StableBloomFilter
-	sweepLock   sync.Mutex
-	sweepFilter *bf.StableBloomFilter
-	subre       *regexp.Regexp
-	done        chan struct{}
-	doneOnce    sync.Once
-	release     chan struct{}
-	inputsig    chan uint32
-	max         int
-	countLock   sync.Mutex
-	count       uint32
+	enum       *Enumeration
+	queue      queue.Queue
+	dups       queue.Queue
+	filterLock sync.Mutex
+	filter     *bf.StableBloomFilter
+	subre      *regexp.Regexp
+	done       chan struct{}
+	doneOnce   sync.Once
+	release    chan struct{}
+	inputsig   chan uint32
+	max        int
+	limit      int
+	countLock  sync.Mutex
+	count      uint32
+	guesses    int32
+	budgetOnce sync.Once
+	stageLock  sync.Mutex
+	stages     map[string]time.Time
+	stagesDone map[string]bool
 }
 
 // newEnumSource returns an initialized input source for the enumeration pipeline.
@@ -55,17 +61,27 @@ func newEnumSource(e *Enumeration) *enumSource {
 	}
 
 	r := &enumSource{
-		enum:        e,
-		queue:       queue.NewQueue(),
-		dups:        queue.NewQueue(),
-		sweeps:      queue.NewQueue(),
-		filter:      bf.NewDefaultStableBloomFilter(1000000, 0.01),
-		sweepFilter: bf.NewDefaultStableBloomFilter(1000000, 0.01),
-		subre:       dns.AnySubdomainRegex(),
-		done:        make(chan struct{}),
-		release:     make(chan struct{}, qps),
-		inputsig:    make(chan uint32, qps*2),
-		max:         qps,
+		enum:     e,
+		queue:    queue.NewQueue(),
+		dups:     queue.NewQueue(),
+		filter:   bf.NewDefaultStableBloomFilter(1000000, 0.01),
+		subre:    dns.AnySubdomainRegex(),
+		done:     make(chan struct{}),
+		release:  make(chan struct{}, qps),
+		inputsig: make(chan uint32, qps*2),
+		max:      qps,
+		limit:    qps * queuedSecondsOfQueries,
+	}
+	// The names beyond the limit are written to disk instead of holding the producers
+	if e.Config.DiskQueue {
+		dir := config.OutputDirectory(e.Config.Dir)
+
+		if q, err := newDiskQueue(dir, r.limit, e.Config.Log); err == nil {
+			r.queue = q
+			r.limit = 0
+		} else {
+			e.Config.Log.Printf("Failed to create the queue file in %s: %v", dir, err)
+		}
 	}
 	// Monitor the enumeration for completion or termination
 	go func() {
@@ -90,11 +106,12 @@ func newEnumSource(e *Enumeration) *enumSource {
 
 func (r *enumSource) Stop() {
 	r.markDone()
+	if q, ok := r.queue.(*diskQueue); ok {
+		q.close()
+	}
 	r.queue.Process(func(e interface{}) {})
 	r.dups.Process(func(e interface{}) {})
-	r.sweeps.Process(func(e interface{}) {})
 	r.filter.Reset()
-	r.sweepFilter.Reset()
 }
 
 func (r *enumSource) markDone() {
@@ -103,35 +120,187 @@ func (r *enumSource) markDone() {
 	})
 }
 
-func (r *enumSource) newName(req *requests.DNSRequest) {
+func (r *enumSource) newName(req *requests.DNSRequest) bool {
 	select {
 	case <-r.done:
-		return
+		return false
 	default:
 	}
 
 	if req.Name == "" || !req.Valid() {
-		return
+		return false
 	}
 	// Clean up the newly discovered name and domain
 	requests.SanitizeDNSRequest(req)
 	// Check that the name is valid
 	if r.subre.FindString(req.Name) != req.Name {
-		return
+		return false
 	}
 	if r.enum.Config.Blacklisted(req.Name) {
-		return
+		return false
+	}
+	// The names confirmed by the recent enumerations were already copied into this one
+	if r.enum.isFresh(req.Name) {
+		return false
 	}
 	// Do not further evaluate service subdomains
 	for _, label := range strings.Split(req.Name, ".") {
 		l := strings.ToLower(label)
 
 		if l == "_tcp" || l == "_udp" || l == "_tls" {
-			return
+			return false
 		}
 	}
-	if r.accept(req.Name, req.Tag, req.Source, true) {
-		r.queue.Append(req)
+	// Generated names are no longer resolved once the guess budget has been consumed
+	guess := req.Tag == requests.ALT
+	if guess && r.guessBudgetExhausted() {
+		return false
+	}
+	// The brute forcing and alterations stop once the DNS query budget has been consumed,
+	// while the names already discovered continue to be resolved
+	if (guess || req.Tag == requests.BRUTE) && (r.dnsBudgetExhausted() || r.stageExpired(req.Tag)) {
+		return false
+	}
+	if !r.accept(req.Name, req.Tag, req.Source, true) {
+		return false
+	}
+	if guess {
+		r.countGuess()
+	}
+
+	r.queue.AppendPriority(req, r.enum.scorer.priority(req))
+	r.enum.checkpoints.queue(req)
+	return true
+}
+
+// Blocks the producers outside the pipeline, such as the guessers and the names read from the graph
+// database, while the queue holds the maximum number of names. The names discovered by the pipeline
+// stages are always accepted, since holding them would stall the pipeline that empties the queue.
+// Returns false once the enumeration is done.
+func (r *enumSource) waitForCapacity() bool {
+	t := time.NewTicker(100 * time.Millisecond)
+	defer t.Stop()
+
+	for r.limit > 0 && r.queue.Len() >= r.limit {
+		select {
+		case <-r.done:
+			return false
+		case <-t.C:
+		}
+	}
+
+	select {
+	case <-r.done:
+		return false
+	default:
+	}
+	return true
+}
+
+// Brings the name or address saved in a checkpoint back into the enumeration. The filter
+// restored from the checkpoint already holds it, so it is queued without being checked.
+func (r *enumSource) resubmit(req pipeline.Data) {
+	priority := discoveredPriority
+	if name, ok := req.(*requests.DNSRequest); ok {
+		priority = r.enum.scorer.priority(name)
+	}
+
+	r.queue.AppendPriority(req, priority)
+	r.enum.checkpoints.queue(req)
+}
+
+func (r *enumSource) saveFilter(w io.Writer) error {
+	r.filterLock.Lock()
+	defer r.filterLock.Unlock()
+
+	_, err := r.filter.WriteTo(w)
+	return err
+}
+
+func (r *enumSource) loadFilter(rd io.Reader) error {
+	r.filterLock.Lock()
+	defer r.filterLock.Unlock()
+
+	_, err := r.filter.ReadFrom(rd)
+	return err
+}
+
+func (r *enumSource) guessBudgetExhausted() bool {
+	max := r.enum.Config.MaxGuesses
+
+	return max > 0 && int(atomic.LoadInt32(&r.guesses)) >= max
+}
+
+func (r *enumSource) dnsBudgetExhausted() bool {
+	max := r.enum.Config.DNSBudget
+	if max <= 0 {
+		return false
+	}
+
+	var num int
+	for _, pool := range []*resolvers.Pool{r.enum.Sys.Resolvers(), r.enum.Sys.TrustedResolvers()} {
+		if pool != nil {
+			num += pool.Queries()
+		}
+	}
+	if num < max {
+		return false
+	}
+
+	r.budgetOnce.Do(func() {
+		r.enum.Config.Log.Printf("The budget of %d DNS queries has been consumed, brute forcing and alterations have stopped", max)
+	})
+	return true
+}
+
+// Returns true once the time budget of the brute forcing or alterations has elapsed, which
+// starts when the stage generates its first name.
+func (r *enumSource) stageExpired(tag string) bool {
+	var limit time.Duration
+	switch tag {
+	case requests.BRUTE:
+		limit = r.enum.Config.BruteTimeout
+	case requests.ALT:
+		limit = r.enum.Config.AltTimeout
+	}
+	if limit <= 0 {
+		return false
+	}
+
+	r.stageLock.Lock()
+	defer r.stageLock.Unlock()
+
+	if r.stages == nil {
+		r.stages = make(map[string]time.Time)
+		r.stagesDone = make(map[string]bool)
+	}
+	start, found := r.stages[tag]
+	if !found {
+		r.stages[tag] = time.Now()
+		return false
+	}
+	if time.Since(start) < limit {
+		return false
+	}
+
+	if !r.stagesDone[tag] {
+		r.stagesDone[tag] = true
+		r.enum.Config.Log.Printf("The %s stage has used its time budget of %s and will not generate more names", tag, limit)
+	}
+	return true
+}
+
+func (r *enumSource) guessCount() int {
+	return int(atomic.LoadInt32(&r.guesses))
+}
+
+func (r *enumSource) setGuessCount(num int) {
+	atomic.StoreInt32(&r.guesses, int32(num))
+}
+
+func (r *enumSource) countGuess() {
+	if num := int(atomic.AddInt32(&r.guesses, 1)); num == r.enum.Config.MaxGuesses {
+		r.enum.Config.Log.Printf("The budget of %d generated names has been consumed, no more guesses will be resolved", num)
 	}
 }
 
@@ -146,15 +315,49 @@ func (r *enumSource) newAddr(req *requests.AddrRequest) {
 		return
 	}
 
-	r.queue.Append(req)
-	// Does the address fall into a reserved address range?
-	if yes, _ := amassnet.IsReservedAddress(req.Address); !yes {
-		// Queue the request for later use in reverse DNS sweeps
-		r.sweeps.Append(req)
+	r.queue.AppendPriority(req, discoveredPriority)
+	r.enum.checkpoints.queue(req)
+	// Queue the address for the reverse DNS sweeps of the surrounding netblock
+	if r.enum.sweeper != nil {
+		r.enum.sweeper.addAddress(req.Address)
+	}
+	r.enum.scanner.addAddress(req.Address)
+}
+
+func (r *enumSource) newEmail(req *requests.EmailRequest) {
+	select {
+	case <-r.done:
+		return
+	default:
+	}
+
+	if !req.Valid() || !r.accept(req.Email, req.Tag, req.Source, false) {
+		return
+	}
+	if err := r.enum.insertEmail(r.enum.ctx, req); err != nil {
+		r.enum.Config.Log.Print(err.Error())
+	}
+}
+
+func (r *enumSource) newCert(req *requests.CertRequest) {
+	select {
+	case <-r.done:
+		return
+	default:
+	}
+
+	if !req.Valid() || !r.accept(req.Serial+req.Address, req.Tag, req.Source, false) {
+		return
+	}
+	if err := r.enum.insertCert(r.enum.ctx, req); err != nil {
+		r.enum.Config.Log.Print(err.Error())
 	}
 }
 
 func (r *enumSource) accept(s, tag, source string, name bool) bool {
+	r.filterLock.Lock()
+	defer r.filterLock.Unlock()
+
 	trusted := requests.TrustedTag(tag)
 	// Do not submit names from untrusted sources, after already receiving the name
 	// from a trusted source
@@ -187,6 +390,16 @@ func (r *enumSource) accept(s, tag, source string, name bool) bool {
 
 // Next implements the pipeline InputSource interface.
 func (r *enumSource) Next(ctx context.Context) bool {
+	// Hold the pipeline input while the enumeration is paused
+	select {
+	case <-r.done:
+		return false
+	case <-ctx.Done():
+		r.markDone()
+		return false
+	case <-r.enum.unpaused():
+	}
+
 	low := make(chan struct{}, 1)
 	// Mark low if below 10%
 	if p := (float32(r.queue.Len()) / float32(r.max)) * 100; p < 10 {
@@ -206,6 +419,11 @@ func (r *enumSource) Next(ctx context.Context) bool {
 			r.markDone()
 			return false
 		case <-t.C:
+			// Names can still be discovered while the reverse DNS sweeps are running
+			if r.enum.sweeper != nil && r.enum.sweeper.active() {
+				t.Reset(waitForDuration)
+				continue
+			}
 			r.markDone()
 			return false
 		case <-r.queue.Signal():
@@ -224,6 +442,7 @@ func (r *enumSource) Data() pipeline.Data {
 
 	if element, ok := r.queue.Next(); ok {
 		data = element.(pipeline.Data)
+		r.enum.checkpoints.enter(data)
 		// Signal that new input was added to the pipeline
 		r.inputsig <- r.incrementCount()
 	}
@@ -260,7 +479,6 @@ func (r *enumSource) fillQueue() {
 		if fill := unfilled - len(r.release); fill > 0 {
 			r.releaseOutput(fill)
 		}
-		go r.requestSweeps()
 	}
 }
 
@@ -281,6 +499,10 @@ func (r *enumSource) monitorDataSrcOutput(srv service.Service) {
 		case <-srv.Done():
 			return
 		case in := <-srv.Output():
+			if r.enum.SourceDisabled(srv.String()) {
+				continue
+			}
+
 			select {
 			case <-r.done:
 				return
@@ -291,76 +513,18 @@ func (r *enumSource) monitorDataSrcOutput(srv service.Service) {
 
 			switch req := in.(type) {
 			case *requests.DNSRequest:
-				r.newName(req)
+				r.enum.stats.incNames(srv.String(), r.newName(req))
 			case *requests.AddrRequest:
 				r.newAddr(req)
+			case *requests.EmailRequest:
+				r.newEmail(req)
+			case *requests.CertRequest:
+				r.newCert(req)
 			}
 		}
 	}
 }
 
-func (r *enumSource) requestSweeps() {
-	r.sweepLock.Lock()
-	defer r.sweepLock.Unlock()
-
-	for {
-		if unfilled := r.max - r.queue.Len(); unfilled <= 0 {
-			break
-		}
-		if e, ok := r.sweeps.Next(); ok {
-			// Generate the additional addresses to sweep across
-			_ = r.sweepAddrs(r.enum.ctx, e.(*requests.AddrRequest))
-		}
-	}
-}
-
-func (r *enumSource) sweepAddrs(ctx context.Context, req *requests.AddrRequest) int {
-	size := defaultSweepSize
-	if r.enum.Config.Active {
-		size = activeSweepSize
-	}
-
-	var count int
-	cidr := r.addrCIDR(req.Address)
-	for _, ip := range amassnet.CIDRSubset(cidr, req.Address, size) {
-		select {
-		case <-ctx.Done():
-			return count
-		default:
-		}
-
-		if a := ip.String(); !r.sweepFilter.TestAndAdd([]byte(a)) {
-			count++
-			r.queue.Append(&requests.AddrRequest{
-				Address: a,
-				Domain:  req.Domain,
-				Tag:     req.Tag,
-				Source:  req.Source,
-			})
-		}
-	}
-	return count
-}
-
-func (r *enumSource) addrCIDR(addr string) *net.IPNet {
-	if asn := r.enum.Sys.Cache().AddrSearch(addr); asn != nil {
-		if _, cidr, err := net.ParseCIDR(asn.Prefix); err == nil {
-			return cidr
-		}
-	}
-
-	ip := net.ParseIP(addr)
-	mask := net.CIDRMask(18, 32)
-	if amassnet.IsIPv6(ip) {
-		mask = net.CIDRMask(64, 128)
-	}
-
-	return &net.IPNet{
-		IP:   ip.Mask(mask),
-		Mask: mask,
-	}
-}
-
 // This goroutine ensures that duplicate names from other sources are shown in the Graph.
 func (r *enumSource) processDupNames() {
 	countdown := r.max * 2
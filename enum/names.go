@@ -67,12 +67,17 @@ func (r *subdomainTask) Process(ctx context.Context, data pipeline.Data, tp pipe
 		}
 	}
 
+	r.enum.stats.incResolved(req.Source)
+	r.enum.checkpoints.resolve(req)
+	r.enum.guessers.hit(req)
+	r.enum.scorer.learn(req)
 	if r.checkForSubdomains(ctx, req, tp) {
 		r.enum.sendRequests(&requests.ResolvedRequest{
 			Name:    req.Name,
 			Domain:  req.Domain,
 			Records: req.Records,
 		})
+		r.enum.guessers.resolved(req)
 	}
 	return req, nil
 }
@@ -102,7 +107,7 @@ func (r *subdomainTask) checkForSubdomains(ctx context.Context, req *requests.DN
 		return false
 	} else if times > 1 && r.cnames.Has(sub) {
 		return false
-	} else if times > r.enum.Config.MinForRecursive {
+	} else if times > r.enum.Config.MinForRecursiveAt(len(nlabels)-1-len(dlabels)) {
 		return true
 	}
 
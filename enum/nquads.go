@@ -0,0 +1,109 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/nquads"
+)
+
+// ExportEvent writes the quads of the event, and of the nodes, edges and properties it stored, in the N-Quads format.
+func ExportEvent(ctx context.Context, db *netmap.Graph, uuid string, w io.Writer) error {
+	quads, err := db.ReadEventQuads(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	qw := nquads.NewWriter(w)
+	if _, err := qw.WriteQuads(quads); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+// ImportEvent merges the quads written by ExportEvent into the event identified by the uuid, so the
+// graphs stored by several enumerations, such as the workers of a distributed enumeration, form one event.
+func ImportEvent(ctx context.Context, db *netmap.Graph, uuid string, r io.Reader) error {
+	var types, others []quad.Quad
+	// The events of the exported graph are replaced by the event receiving the quads
+	events := make(map[string]bool)
+
+	qr := nquads.NewReader(r, false)
+	for {
+		q, err := qr.ReadQuad()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read the graph quads: %v", err)
+		}
+
+		if quadValue(q.Predicate) != "type" {
+			others = append(others, q)
+		} else if ntype := quadValue(q.Object); ntype == netmap.TypeEvent {
+			events[quadValue(q.Subject)] = true
+		} else {
+			types = append(types, q)
+		}
+	}
+	rename := func(id string) string {
+		if events[id] {
+			return uuid
+		}
+		return id
+	}
+
+	if _, err := db.UpsertEvent(ctx, uuid); err != nil {
+		return err
+	}
+	// The nodes are created before the edges and properties referencing them
+	for _, q := range types {
+		if _, err := db.UpsertNode(ctx, quadValue(q.Subject), quadValue(q.Object)); err != nil {
+			return err
+		}
+	}
+	for _, q := range others {
+		from, pred := quadValue(q.Subject), quadValue(q.Predicate)
+
+		if to, ok := q.Object.(quad.IRI); ok {
+			if err := db.UpsertEdge(ctx, &netmap.Edge{
+				Predicate: pred,
+				From:      netmap.Node(rename(from)),
+				To:        netmap.Node(rename(string(to))),
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		// The start and finish of the exported events are replaced by those of the receiving event
+		if events[from] {
+			continue
+		}
+		if err := db.UpsertProperty(ctx, netmap.Node(from), pred, quadValue(q.Object)); err != nil {
+			return err
+		}
+	}
+
+	// Update the time the event finished
+	_, err := db.UpsertEvent(ctx, uuid)
+	return err
+}
+
+func quadValue(v quad.Value) string {
+	switch n := quad.NativeOf(v).(type) {
+	case quad.IRI:
+		return string(n)
+	case string:
+		return n
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(n)
+	}
+}
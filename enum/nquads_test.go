@@ -0,0 +1,77 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+)
+
+func TestExportImportEvent(t *testing.T) {
+	ctx := context.Background()
+	merged := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer merged.Close()
+
+	// Each worker stores the findings of its own event in its own graph
+	first := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer first.Close()
+	e := &Enumeration{Config: config.NewConfig(), graph: first}
+	worker1 := e.Config.UUID.String()
+	if err := first.UpsertCNAME(ctx, "blog.owasp.org", "owasp.github.io", "DNS", worker1); err != nil {
+		t.Fatalf("Failed to insert the CNAME record: %v", err)
+	}
+	if err := first.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", worker1); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := e.insertOpenPorts(ctx, "192.168.1.1", []int{443}, []int{443}); err != nil {
+		t.Fatalf("Failed to insert the open port: %v", err)
+	}
+
+	second := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer second.Close()
+	if err := second.UpsertNS(ctx, "dev.owasp.org", "ns1.digitalocean.com", "DNS", "worker2"); err != nil {
+		t.Fatalf("Failed to insert the NS record: %v", err)
+	}
+
+	for worker, g := range map[string]*netmap.Graph{worker1: first, "worker2": second} {
+		var buf bytes.Buffer
+		if err := ExportEvent(ctx, g, worker, &buf); err != nil {
+			t.Fatalf("Failed to export the %s event: %v", worker, err)
+		}
+		if err := ImportEvent(ctx, merged, "merged", &buf); err != nil {
+			t.Fatalf("Failed to import the %s event: %v", worker, err)
+		}
+	}
+
+	if events := merged.EventList(ctx); len(events) != 1 || events[0] != "merged" {
+		t.Errorf("Got the events %v, expected only the merged event", events)
+	}
+	names := stringset.New(merged.EventFQDNs(ctx, "merged")...)
+	defer names.Close()
+	for _, name := range []string{"blog.owasp.org", "www.owasp.org", "dev.owasp.org"} {
+		if !names.Has(name) {
+			t.Errorf("The name %s was not merged into the event", name)
+		}
+	}
+
+	if edges, err := merged.ReadOutEdges(ctx, netmap.Node("blog.owasp.org"), "cname_record"); err != nil || len(edges) != 1 {
+		t.Errorf("The CNAME record was not merged: %v", err)
+	}
+	if edges, err := merged.ReadOutEdges(ctx, netmap.Node("dev.owasp.org"), "ns_record"); err != nil || len(edges) != 1 {
+		t.Errorf("The NS record was not merged: %v", err)
+	}
+	if props, err := merged.ReadProperties(ctx, netmap.Node("192.168.1.1"), OpenPortPredicate); err != nil ||
+		len(props) != 1 || props[0].Value.Native() != "443/tcp" {
+		t.Errorf("The open port was not merged: %v", props)
+	}
+	if start, finish := merged.EventDateRange(ctx, "merged"); start.IsZero() || finish.Before(start) {
+		t.Errorf("The merged event has the times %v and %v", start, finish)
+	}
+}
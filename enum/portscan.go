@@ -0,0 +1,183 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/caffix/netmap"
+	"go.uber.org/ratelimit"
+)
+
+// OpenPortPredicate is the graph property holding the open ports observed on an address, such as "443/tcp",
+// or "443/tcp https nginx 1.18" when imported from nmap.
+const OpenPortPredicate = "open_port"
+
+const (
+	// The number of addresses scanned concurrently, while the connections are limited by the rate
+	portScanWorkers = 10
+	portScanTimeout = 2 * time.Second
+)
+
+// portScanner performs the TCP connect scans of the in-scope addresses, using its own
+// rate limit separate from the enumeration pipeline.
+type portScanner struct {
+	*asyncWorker
+	enum  *Enumeration
+	ports []int
+	rate  ratelimit.Limiter
+}
+
+// newPortScanner returns a portScanner for the provided Enumeration that has not been started yet,
+// with the addresses provided in the configuration already queued.
+func newPortScanner(e *Enumeration) *portScanner {
+	ports := e.Config.PortScanPorts
+	if len(ports) == 0 {
+		ports = config.DefaultPortScanPorts
+	}
+
+	rate := e.Config.PortScanRate
+	if rate <= 0 {
+		rate = config.DefaultPortScanRate
+	}
+
+	s := &portScanner{
+		enum:  e,
+		ports: ports,
+		rate:  ratelimit.New(rate, ratelimit.WithoutSlack),
+	}
+	s.asyncWorker = newAsyncWorker(portScanWorkers, func(ctx context.Context, item interface{}) {
+		addr := item.(string)
+
+		if open := s.scan(ctx, addr); ctx.Err() == nil {
+			if err := e.insertOpenPorts(ctx, addr, s.ports, open); err != nil {
+				e.Config.Log.Print(err.Error())
+			}
+		}
+	})
+
+	for _, ip := range e.Config.Addresses {
+		s.addAddress(ip.String())
+	}
+	return s
+}
+
+// addAddress queues the in-scope address to be scanned once.
+func (s *portScanner) addAddress(addr string) {
+	if s == nil || net.ParseIP(addr) == nil {
+		return
+	}
+	// The reserved and excluded addresses are never scanned
+	if yes, _ := amassnet.IsReservedAddress(addr); yes || s.enum.Config.IsAddressExcluded(addr) {
+		return
+	}
+
+	s.add(addr, addr)
+}
+
+// Returns the ports accepting TCP connections on the address.
+func (s *portScanner) scan(ctx context.Context, addr string) []int {
+	var open []int
+
+	for _, port := range s.ports {
+		select {
+		case <-ctx.Done():
+			return open
+		default:
+		}
+
+		s.rate.Take()
+		if portOpen(ctx, addr, port) {
+			open = append(open, port)
+		}
+	}
+	return open
+}
+
+func portOpen(ctx context.Context, addr string, port int) bool {
+	ctx, cancel := context.WithTimeout(ctx, portScanTimeout)
+	defer cancel()
+
+	conn, err := amassnet.DialContext(ctx, "tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+
+	_ = conn.Close()
+	return true
+}
+
+// Stores the open ports of the address and removes the ports scanned that are no longer open. The ports
+// already stored with more details, such as those imported from nmap, are kept while they remain open.
+func (e *Enumeration) insertOpenPorts(ctx context.Context, addr string, scanned, open []int) error {
+	node, err := e.graph.ReadNode(ctx, addr, netmap.TypeAddr)
+	if err != nil {
+		if node, err = e.graph.UpsertAddress(ctx, addr, "Port Scan", e.Config.UUID.String()); err != nil {
+			return fmt.Errorf("%s failed to insert the scanned address: %v", e.graph, err)
+		}
+	}
+
+	isOpen := make(map[int]bool, len(open))
+	for _, port := range open {
+		isOpen[port] = true
+	}
+	wasScanned := make(map[int]bool, len(scanned))
+	for _, port := range scanned {
+		wasScanned[port] = true
+	}
+
+	stored := make(map[int]bool)
+	if props, err := e.graph.ReadProperties(ctx, node, OpenPortPredicate); err == nil {
+		for _, p := range props {
+			v, ok := p.Value.Native().(string)
+			if !ok {
+				continue
+			}
+
+			port, tcp := parseOpenPort(v)
+			if !tcp || !wasScanned[port] {
+				continue
+			}
+			if isOpen[port] {
+				stored[port] = true
+			} else {
+				_ = e.graph.DeleteProperty(ctx, node, p.Predicate, p.Value)
+			}
+		}
+	}
+
+	for _, port := range open {
+		if stored[port] {
+			continue
+		}
+		if err := e.graph.UpsertProperty(ctx, node, OpenPortPredicate, strconv.Itoa(port)+"/tcp"); err != nil {
+			return fmt.Errorf("%s failed to insert the open port: %v", e.graph, err)
+		}
+	}
+	return nil
+}
+
+// Returns the port number of the open port property and whether it was observed over TCP.
+func parseOpenPort(value string) (int, bool) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	parts := strings.SplitN(fields[0], "/", 2)
+	if len(parts) != 2 || parts[1] != "tcp" {
+		return 0, false
+	}
+
+	port, err := strconv.Atoi(parts[0])
+	return port, err == nil
+}
@@ -0,0 +1,91 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/caffix/netmap"
+)
+
+func TestPortScannerAddAddress(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	s := newPortScanner(&Enumeration{Config: cfg})
+	defer s.stop()
+	if len(s.ports) != len(config.DefaultPortScanPorts) {
+		t.Errorf("The default ports were not selected")
+	}
+
+	s.addAddress("72.237.4.113")
+	s.addAddress("72.237.4.113")
+	s.addAddress("192.168.1.1")
+	s.addAddress("not an address")
+	if n := s.items.Len(); n != 1 {
+		t.Errorf("%d addresses were queued, expected only the public address", n)
+	}
+}
+
+func TestPortOpen(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	_, p, _ := net.SplitHostPort(l.Addr().String())
+	port, _ := strconv.Atoi(p)
+
+	if !portOpen(context.Background(), "127.0.0.1", port) {
+		t.Errorf("The listening port was not reported open")
+	}
+	l.Close()
+	if portOpen(context.Background(), "127.0.0.1", port) {
+		t.Errorf("The closed port was reported open")
+	}
+}
+
+func TestInsertOpenPorts(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	if err := g.UpsertA(ctx, "www.owasp.org", "72.237.4.113", "DNS", cfg.UUID.String()); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	node := netmap.Node("72.237.4.113")
+	// The ports observed by an earlier nmap scan
+	for _, v := range []string{"443/tcp https nginx", "22/tcp ssh", "53/udp domain"} {
+		if err := g.UpsertProperty(ctx, node, OpenPortPredicate, v); err != nil {
+			t.Fatalf("Failed to insert the open port: %v", err)
+		}
+	}
+
+	e := &Enumeration{Config: cfg, graph: g}
+	if err := e.insertOpenPorts(ctx, "72.237.4.113", []int{22, 443, 8080}, []int{443, 8080}); err != nil {
+		t.Fatalf("Failed to insert the open ports: %v", err)
+	}
+
+	props, err := g.ReadProperties(ctx, node, OpenPortPredicate)
+	if err != nil {
+		t.Fatalf("Failed to read the open ports: %v", err)
+	}
+	var ports []string
+	for _, p := range props {
+		ports = append(ports, p.Value.Native().(string))
+	}
+	sort.Strings(ports)
+	if got := strings.Join(ports, ","); got != "443/tcp https nginx,53/udp domain,8080/tcp" {
+		t.Errorf("The open ports were %s, expected 443/tcp https nginx,53/udp domain,8080/tcp", got)
+	}
+}
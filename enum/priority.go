@@ -0,0 +1,259 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// The candidates generated by brute forcing and alterations are spread across this many priority
+// levels by their score, and the names discovered by the data sources and the pipeline are queued
+// above them, so the most likely names are resolved first when the enumeration is time-boxed.
+const (
+	candidatePriorityLevels = 10
+	discoveredPriority      = candidatePriorityLevels
+)
+
+const (
+	// The characters of the labels and the markers of their start and end known to the Markov model
+	markovAlphabetSize = 40
+	// The labels resolved before the Markov model takes part in the scores
+	minMarkovLabels = 10
+)
+
+// The file keeping the Markov models of the root domains between the enumerations
+const markovModelsFileName = "markov_models.json"
+
+// nameScorer estimates the probability that the candidate names resolve using the rank of the label
+// in the brute forcing wordlist, a Markov model of the labels resolved within the root domain, and
+// the share of the names from the same source that resolved.
+type nameScorer struct {
+	sync.Mutex
+	ranks map[string]int
+	words int
+	stats *sourceStats
+	// The Markov models of the root domains, which are kept between the enumerations
+	models map[string]*markovModel
+}
+
+// markovModel counts the character transitions within the labels resolved under a root domain.
+type markovModel struct {
+	labels      int
+	transitions map[[2]byte]int
+	followers   map[byte]int
+}
+
+// savedMarkovModel is the Markov model of a root domain saved in the markov_models.json file,
+// where the transitions are keyed by their two characters.
+type savedMarkovModel struct {
+	Labels      int            `json:"labels"`
+	Transitions map[string]int `json:"transitions"`
+}
+
+func newNameScorer(wordlist []string, stats *sourceStats) *nameScorer {
+	s := &nameScorer{
+		ranks:  make(map[string]int, len(wordlist)),
+		stats:  stats,
+		models: make(map[string]*markovModel),
+	}
+
+	// The wordlists are expected to list the most common words first
+	for _, word := range wordlist {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if _, found := s.ranks[word]; !found && word != "" {
+			s.ranks[word] = s.words
+			s.words++
+		}
+	}
+	return s
+}
+
+// Returns the priority of the name waiting to be resolved.
+func (s *nameScorer) priority(req *requests.DNSRequest) int {
+	if s == nil || (req.Tag != requests.BRUTE && req.Tag != requests.ALT) {
+		return discoveredPriority
+	}
+
+	p := int(s.score(req) * candidatePriorityLevels)
+	if p >= candidatePriorityLevels {
+		p = candidatePriorityLevels - 1
+	}
+	return p
+}
+
+// Returns the average of the scores available for the candidate name, between zero and one.
+func (s *nameScorer) score(req *requests.DNSRequest) float64 {
+	label := firstLabel(req.Name)
+	scores := []float64{s.reputation(req.Source)}
+
+	if rank, found := s.ranks[label]; found {
+		scores = append(scores, 1-float64(rank)/float64(s.words))
+	}
+	if p, ok := s.markov(req.Domain, label); ok {
+		scores = append(scores, p)
+	}
+
+	var total float64
+	for _, score := range scores {
+		total += score
+	}
+	return total / float64(len(scores))
+}
+
+// Returns the share of the unique names from the source that resolved, which starts at one half.
+func (s *nameScorer) reputation(source string) float64 {
+	if s.stats == nil {
+		return 0.5
+	}
+
+	s.stats.Lock()
+	defer s.stats.Unlock()
+
+	c := s.stats.get(source)
+	return float64(c.Resolved+1) / float64(c.UniqueNames+2)
+}
+
+// Returns the geometric mean of the transition probabilities of the label within the root domain,
+// compared with the probability of the transitions when all the characters are equally likely.
+func (s *nameScorer) markov(domain, label string) (float64, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	m, found := s.models[strings.ToLower(domain)]
+	if !found || m.labels < minMarkovLabels {
+		return 0, false
+	}
+
+	var logp float64
+	chars := markovChars(label)
+	for i := 1; i < len(chars); i++ {
+		count := m.transitions[[2]byte{chars[i-1], chars[i]}]
+		total := m.followers[chars[i-1]]
+		// The unseen transitions keep a small probability
+		logp += math.Log(float64(count+1) / float64(total+markovAlphabetSize))
+	}
+
+	p := math.Exp(logp / float64(len(chars)-1))
+	return p / (p + 1/float64(markovAlphabetSize)), true
+}
+
+// Trains the Markov model of the root domain using the first label of the resolved name.
+func (s *nameScorer) learn(req *requests.DNSRequest) {
+	if s == nil || req.Domain == "" || req.Name == req.Domain {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	m := s.model(strings.ToLower(req.Domain))
+	chars := markovChars(firstLabel(req.Name))
+	for i := 1; i < len(chars); i++ {
+		m.add([2]byte{chars[i-1], chars[i]}, 1)
+	}
+	m.labels++
+}
+
+// Returns the Markov model of the root domain, which is created when missing.
+func (s *nameScorer) model(domain string) *markovModel {
+	m, found := s.models[domain]
+	if !found {
+		m = &markovModel{
+			transitions: make(map[[2]byte]int),
+			followers:   make(map[byte]int),
+		}
+		s.models[domain] = m
+	}
+	return m
+}
+
+func (m *markovModel) add(transition [2]byte, count int) {
+	m.transitions[transition] += count
+	m.followers[transition[0]] += count
+}
+
+// Returns the path of the file keeping the Markov models between the enumerations, within the output directory.
+func markovModelsPath(cfg *config.Config) string {
+	return filepath.Join(config.OutputDirectory(cfg.Dir), markovModelsFileName)
+}
+
+// Adds the Markov models saved by the previous enumerations to the models of the scorer.
+func (s *nameScorer) load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var saved map[string]*savedMarkovModel
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	for domain, sm := range saved {
+		if sm == nil {
+			continue
+		}
+
+		m := s.model(strings.ToLower(domain))
+		for key, count := range sm.Transitions {
+			if len(key) == 2 && count > 0 {
+				m.add([2]byte{key[0], key[1]}, count)
+			}
+		}
+		m.labels += sm.Labels
+	}
+	return nil
+}
+
+// Writes the Markov models to a temporary file that replaces the previous file, so an
+// interruption while writing does not leave truncated models behind.
+func (s *nameScorer) save(path string) error {
+	s.Lock()
+	saved := make(map[string]*savedMarkovModel, len(s.models))
+	for domain, m := range s.models {
+		sm := &savedMarkovModel{
+			Labels:      m.labels,
+			Transitions: make(map[string]int, len(m.transitions)),
+		}
+
+		for t, count := range m.transitions {
+			sm.Transitions[string(t[:])] = count
+		}
+		saved[domain] = sm
+	}
+	s.Unlock()
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Returns the characters of the label between the markers of its start and end.
+func markovChars(label string) []byte {
+	return []byte("^" + label + "$")
+}
+
+func firstLabel(name string) string {
+	return strings.ToLower(strings.SplitN(name, ".", 2)[0])
+}
@@ -0,0 +1,98 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestNameScorer(t *testing.T) {
+	stats := newSourceStats(nil)
+	s := newNameScorer([]string{"www", "mail", "dev", "zz9"}, stats)
+
+	brute := func(label string) *requests.DNSRequest {
+		return &requests.DNSRequest{Name: label + ".owasp.org", Domain: "owasp.org", Tag: requests.BRUTE, Source: "Brute Forcing"}
+	}
+	if p := s.priority(&requests.DNSRequest{Name: "zz9.owasp.org", Domain: "owasp.org", Tag: requests.CERT}); p != discoveredPriority {
+		t.Errorf("The discovered name received the priority %d, expected %d", p, discoveredPriority)
+	}
+	if p := s.priority(brute("www")); p >= discoveredPriority || p <= s.priority(brute("zz9")) {
+		t.Errorf("The wordlist rank did not order the candidates")
+	}
+
+	for _, label := range []string{"api", "app", "apps", "api2", "app1", "appdev", "apitest", "apis", "apple", "apac"} {
+		s.learn(&requests.DNSRequest{Name: label + ".owasp.org", Domain: "owasp.org"})
+	}
+	alt := func(label string) *requests.DNSRequest {
+		return &requests.DNSRequest{Name: label + ".owasp.org", Domain: "owasp.org", Tag: requests.ALT, Source: "Alteration Rules"}
+	}
+	if s.score(alt("apps2")) <= s.score(alt("qxjv")) {
+		t.Errorf("The Markov model did not favor the labels similar to the resolved names")
+	}
+
+	// The sources with more names resolved are trusted more
+	for i := 0; i < 10; i++ {
+		stats.incNames("Brute Forcing", true)
+		stats.incNames("Alteration Rules", true)
+		stats.incResolved("Alteration Rules")
+	}
+	if s.reputation("Alteration Rules") <= s.reputation("Brute Forcing") {
+		t.Errorf("The source reputation did not follow the resolved names")
+	}
+
+	var nilScorer *nameScorer
+	if p := nilScorer.priority(brute("www")); p != discoveredPriority {
+		t.Errorf("The names were not queued in order without a scorer")
+	}
+}
+
+func TestNameScorerModels(t *testing.T) {
+	dir, err := ioutil.TempDir("", "markov")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, markovModelsFileName)
+
+	s := newNameScorer(nil, nil)
+	for _, label := range []string{"api", "app", "apps", "api2", "app1", "appdev", "apitest", "apis", "apple", "apac"} {
+		s.learn(&requests.DNSRequest{Name: label + ".owasp.org", Domain: "owasp.org"})
+	}
+	if _, ok := s.markov("example.com", "apps2"); ok {
+		t.Errorf("The Markov model of the domain was used for another domain")
+	}
+	expected, ok := s.markov("owasp.org", "apps2")
+	if !ok {
+		t.Fatalf("The Markov model of the domain was not used")
+	}
+	if err := s.save(path); err != nil {
+		t.Fatalf("Failed to save the Markov models: %v", err)
+	}
+
+	// The models saved by the previous enumeration are used from the start
+	loaded := newNameScorer(nil, nil)
+	if err := loaded.load(path); err != nil {
+		t.Fatalf("Failed to load the Markov models: %v", err)
+	}
+	if p, ok := loaded.markov("owasp.org", "apps2"); !ok || p != expected {
+		t.Errorf("The loaded Markov model returned %f, expected %f", p, expected)
+	}
+	loaded.learn(&requests.DNSRequest{Name: "www.owasp.org", Domain: "owasp.org"})
+	if m := loaded.models["owasp.org"]; m.labels != 11 {
+		t.Errorf("The loaded Markov model has %d labels, expected 11", m.labels)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to write the Markov models: %v", err)
+	}
+	if err := newNameScorer(nil, nil).load(path); err == nil {
+		t.Errorf("The malformed Markov models were loaded")
+	}
+}
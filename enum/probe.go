@@ -0,0 +1,141 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+)
+
+// httpProber probes the web services of the resolved names in scope on the configured ports,
+// using its own concurrency separate from the enumeration pipeline.
+type httpProber struct {
+	*asyncWorker
+	enum  *Enumeration
+	ports []int
+}
+
+// newHTTPProber returns a httpProber for the provided Enumeration that has not been started yet.
+func newHTTPProber(e *Enumeration) *httpProber {
+	ports := e.Config.HTTPProbePorts
+	if len(ports) == 0 {
+		ports = e.Config.Ports
+	}
+
+	workers := e.Config.HTTPProbeConcurrency
+	if workers <= 0 {
+		workers = config.DefaultHTTPProbeConcurrency
+	}
+
+	p := &httpProber{enum: e, ports: ports}
+	p.asyncWorker = newAsyncWorker(workers, func(ctx context.Context, item interface{}) {
+		p.probe(ctx, item.(string))
+	})
+	return p
+}
+
+// addName queues the name to be probed once, when it resolved to an address.
+func (p *httpProber) addName(req *requests.DNSRequest) {
+	if p == nil || req == nil || !req.Valid() {
+		return
+	}
+
+	name := strings.ToLower(req.Name)
+	if p.enum.Config.WhichDomain(name) != "" && hasAddressRecord(req) {
+		p.add(name, name)
+	}
+}
+
+func hasAddressRecord(req *requests.DNSRequest) bool {
+	for _, r := range req.Records {
+		if t := uint16(r.Type); t == dns.TypeA || t == dns.TypeAAAA {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *httpProber) probe(ctx context.Context, name string) {
+	cfg := p.enum.Config
+
+	for _, port := range p.ports {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		// The web services on the ports crawled by the active techniques are already fingerprinted
+		if cfg.Active && containsPort(cfg.Ports, port) {
+			continue
+		}
+
+		fp, err := http.FingerprintService(ctx, serviceURL(ctx, name, port))
+		if err != nil {
+			if cfg.Verbose {
+				cfg.Log.Printf("HTTP Probe: %v", err)
+			}
+			continue
+		}
+		if err := p.enum.insertHTTPService(ctx, name, fp, "HTTP Probe"); err != nil {
+			cfg.Log.Print(err.Error())
+		}
+	}
+}
+
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadHTTPServices returns the web services responding at the name that are stored in the graph.
+func ReadHTTPServices(ctx context.Context, db *netmap.Graph, name string) []requests.HTTPInfo {
+	edges, err := db.ReadOutEdges(ctx, netmap.Node(name), "http_service")
+	if err != nil {
+		return nil
+	}
+
+	var services []requests.HTTPInfo
+	for _, edge := range edges {
+		props, err := db.ReadProperties(ctx, edge.To, httpServicePredicates...)
+		if err != nil {
+			continue
+		}
+
+		info := requests.HTTPInfo{URL: db.NodeToID(edge.To)}
+		for _, p := range props {
+			v, ok := p.Value.Native().(string)
+			if !ok {
+				continue
+			}
+
+			switch p.Predicate {
+			case "status_code":
+				info.StatusCode, _ = strconv.Atoi(v)
+			case "redirect":
+				info.Redirect = v
+			case "title":
+				info.Title = v
+			case "server":
+				info.Server = v
+			}
+		}
+		if info.StatusCode != 0 {
+			services = append(services, info)
+		}
+	}
+	return services
+}
@@ -0,0 +1,78 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+)
+
+func TestHTTPProberAddName(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	cfg.HTTPProbePorts = []int{8080}
+
+	prober := newHTTPProber(&Enumeration{Config: cfg})
+	defer prober.stop()
+	if len(prober.ports) != 1 || prober.workers != config.DefaultHTTPProbeConcurrency {
+		t.Errorf("The probe settings were not applied")
+	}
+
+	resolved := func(name string) *requests.DNSRequest {
+		return &requests.DNSRequest{
+			Name:    name,
+			Domain:  "owasp.org",
+			Records: []requests.DNSAnswer{{Name: name, Type: int(dns.TypeA), Data: "192.168.1.1"}},
+		}
+	}
+	prober.addName(resolved("www.owasp.org"))
+	prober.addName(resolved("www.owasp.org"))
+	prober.addName(resolved("www.example.com"))
+	prober.addName(&requests.DNSRequest{Name: "dev.owasp.org", Domain: "owasp.org"})
+	if n := prober.items.Len(); n != 1 {
+		t.Errorf("%d names were queued, expected only the resolved name in scope", n)
+	}
+
+	var nilProber *httpProber
+	nilProber.addName(resolved("www.owasp.org"))
+}
+
+func TestReadHTTPServices(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	e := &Enumeration{Config: cfg, graph: g}
+	if err := e.insertHTTPService(ctx, "www.owasp.org", &http.Fingerprint{
+		URL:        "http://www.owasp.org:80",
+		StatusCode: 200,
+		Server:     "nginx",
+		Title:      "Login",
+		Redirect:   "https://www.owasp.org/login",
+	}, "HTTP Probe"); err != nil {
+		t.Fatalf("Failed to insert the web service: %v", err)
+	}
+
+	services := ReadHTTPServices(ctx, g, "www.owasp.org")
+	if len(services) != 1 {
+		t.Fatalf("Returned %d web services, expected 1", len(services))
+	}
+	if s := services[0]; s.URL != "http://www.owasp.org:80" || s.StatusCode != 200 || s.Title != "Login" ||
+		s.Server != "nginx" || s.Redirect != "https://www.owasp.org/login" {
+		t.Errorf("The web service was not read back correctly: %+v", s)
+	}
+	if services := ReadHTTPServices(ctx, g, "dev.owasp.org"); len(services) != 0 {
+		t.Errorf("Web services were returned for a name without them")
+	}
+}
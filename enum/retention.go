@@ -0,0 +1,197 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+)
+
+// PruneEvents removes the events that finished more than days ago, and the events beyond the keep
+// most recent events of each domain. The nodes only discovered during the removed events are also
+// removed from the graph. When domains are provided, only the events in scope are considered.
+// The number of events removed is returned.
+func PruneEvents(ctx context.Context, db *netmap.Graph, domains []string, days, keep int) (int, error) {
+	return pruneEvents(ctx, db, domains, days, keep, time.Now())
+}
+
+func pruneEvents(ctx context.Context, db *netmap.Graph, domains []string, days, keep int, now time.Time) (int, error) {
+	if days <= 0 && keep <= 0 {
+		return 0, nil
+	}
+
+	var events []string
+	if len(domains) > 0 {
+		events = db.EventsInScope(ctx, domains...)
+	} else {
+		events = db.EventList(ctx)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	remove := expiredEvents(ctx, db, events, days, keep, now)
+	if len(remove) == 0 {
+		return 0, nil
+	}
+	if err := RemoveEvents(ctx, db, remove); err != nil {
+		return 0, err
+	}
+	return len(remove), nil
+}
+
+// RemoveEvents removes the events, along with the nodes only discovered during them.
+func RemoveEvents(ctx context.Context, db *netmap.Graph, remove []string) error {
+	if len(remove) == 0 {
+		return nil
+	}
+
+	candidates := stringset.New()
+	defer candidates.Close()
+	sources := stringset.New()
+	defer sources.Close()
+
+	for _, event := range remove {
+		if nodes, err := db.AllOutNodes(ctx, netmap.Node(event)); err == nil {
+			for _, node := range nodes {
+				candidates.Insert(db.NodeToID(node))
+			}
+		}
+		// The data source nodes are shared by all the events and hold the cached responses
+		if edges, err := db.ReadOutEdges(ctx, netmap.Node(event), "used"); err == nil {
+			for _, edge := range edges {
+				sources.Insert(db.NodeToID(edge.To))
+			}
+		}
+	}
+
+	for _, event := range remove {
+		if err := DeleteGraphNode(ctx, db, event); err != nil {
+			return err
+		}
+	}
+	// Nodes that remain part of other events are kept
+	for _, event := range db.EventList(ctx) {
+		if nodes, err := db.AllOutNodes(ctx, netmap.Node(event)); err == nil {
+			for _, node := range nodes {
+				candidates.Remove(db.NodeToID(node))
+			}
+		}
+	}
+
+	candidates.Subtract(sources)
+	for _, id := range candidates.Slice() {
+		if err := DeleteGraphNode(ctx, db, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteGraphNode removes the edges and properties of the node. The labeled type property cannot
+// be removed using the graph API, so the node remains without any edges to or from other nodes.
+func DeleteGraphNode(ctx context.Context, db *netmap.Graph, id string) error {
+	node := netmap.Node(id)
+	// DeleteNode only removes the edges leaving the node
+	if edges, err := db.ReadInEdges(ctx, node); err == nil {
+		for _, edge := range edges {
+			if err := db.DeleteEdge(ctx, edge); err != nil {
+				return err
+			}
+		}
+	}
+	// The node may have been removed previously along with another event
+	_ = db.DeleteNode(ctx, node)
+	return nil
+}
+
+// EventDateRange returns the start and finish times of the event,
+// including the events that were compacted into it.
+func EventDateRange(ctx context.Context, db *netmap.Graph, uuid string) (time.Time, time.Time) {
+	start, finish := db.EventDateRange(ctx, uuid)
+
+	if props, err := db.ReadProperties(ctx, netmap.Node(uuid), requests.LastSeenPredicate); err == nil {
+		for _, p := range props {
+			if v, ok := p.Value.Native().(string); ok {
+				if t, err := time.Parse(time.RFC3339, v); err == nil && t.After(finish) {
+					finish = t
+				}
+			}
+		}
+	}
+	return start, finish
+}
+
+// OrderedEvents sorts the events from the oldest to the most recent, and returns them
+// along with the start and finish times of each event.
+func OrderedEvents(ctx context.Context, events []string, db *netmap.Graph) ([]string, []time.Time, []time.Time) {
+	sort.Slice(events, func(i, j int) bool {
+		var less bool
+
+		e1, l1 := EventDateRange(ctx, db, events[i])
+		e2, l2 := EventDateRange(ctx, db, events[j])
+		if l2.After(l1) || e1.Before(e2) {
+			less = true
+		}
+
+		return less
+	})
+
+	var earliest, latest []time.Time
+	for _, event := range events {
+		e, l := EventDateRange(ctx, db, event)
+
+		earliest = append(earliest, e)
+		latest = append(latest, l)
+	}
+
+	return events, earliest, latest
+}
+
+// Returns the events that fall outside of the retention policy at the provided time.
+func expiredEvents(ctx context.Context, db *netmap.Graph, events []string, days, keep int, now time.Time) []string {
+	events, earliest, latest := OrderedEvents(ctx, events, db)
+
+	var cutoff time.Time
+	if days > 0 {
+		cutoff = now.AddDate(0, 0, -days)
+	}
+
+	counts := make(map[string]int)
+	var expired []string
+	// The events are ordered from the oldest to the most recent
+	for i := len(events) - 1; i >= 0; i-- {
+		// Events without a start time were pruned previously
+		if earliest[i].IsZero() {
+			continue
+		}
+		if !cutoff.IsZero() && latest[i].Before(cutoff) {
+			expired = append(expired, events[i])
+			continue
+		}
+		if keep <= 0 {
+			continue
+		}
+
+		domains := db.EventDomains(ctx, events[i])
+		// Events are kept while they are among the most recent for at least one of their domains
+		retain := len(domains) == 0
+		for _, domain := range domains {
+			counts[domain]++
+			if counts[domain] <= keep {
+				retain = true
+			}
+		}
+		if !retain {
+			expired = append(expired, events[i])
+		}
+	}
+	return expired
+}
@@ -0,0 +1,124 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+)
+
+// Inserts the names into the event, which finishes at the provided time.
+func insertRetentionEvent(t *testing.T, g *netmap.Graph, uuid string, finish time.Time, names ...string) {
+	ctx := context.Background()
+
+	for _, name := range names {
+		if _, err := g.UpsertFQDN(ctx, name, "DNS", uuid); err != nil {
+			t.Fatalf("Failed to insert the FQDN: %v", err)
+		}
+	}
+	// The compacted events extend the finish time using the last seen property
+	if err := g.UpsertProperty(ctx, netmap.Node(uuid), requests.LastSeenPredicate, finish.UTC().Format(time.RFC3339)); err != nil {
+		t.Fatalf("Failed to set the finish time of the event: %v", err)
+	}
+}
+
+func eventPruned(g *netmap.Graph, uuid string) bool {
+	start, _ := EventDateRange(context.Background(), g, uuid)
+	return start.IsZero()
+}
+
+func hasEvent(g *netmap.Graph, name, uuid string) bool {
+	edges, err := g.ReadInEdges(context.Background(), netmap.Node(name))
+	if err != nil {
+		return false
+	}
+
+	for _, edge := range edges {
+		if g.NodeToID(edge.From) == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPruneEventsKeep(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	base := time.Now().Add(time.Hour)
+	insertRetentionEvent(t, g, "event1", base, "owasp.org", "old.owasp.org")
+	insertRetentionEvent(t, g, "event2", base.Add(time.Hour), "owasp.org", "www.owasp.org")
+	insertRetentionEvent(t, g, "event3", base.Add(2*time.Hour), "owasp.org", "www.owasp.org")
+
+	if n, err := pruneEvents(ctx, g, nil, 0, 2, base); err != nil || n != 1 {
+		t.Fatalf("Removed %d events, expected the oldest event: %v", n, err)
+	}
+	if !eventPruned(g, "event1") || eventPruned(g, "event2") || eventPruned(g, "event3") {
+		t.Errorf("The events beyond the two most recent were not the ones removed")
+	}
+	if hasEvent(g, "old.owasp.org", "event1") {
+		t.Errorf("The name only discovered during the removed event was kept")
+	}
+	if !hasEvent(g, "www.owasp.org", "event3") {
+		t.Errorf("The name discovered during the kept events was removed")
+	}
+
+	if n, _ := pruneEvents(ctx, g, nil, 0, 0, base); n != 0 {
+		t.Errorf("Removed %d events without a retention policy", n)
+	}
+}
+
+func TestPruneEventsAge(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	base := time.Now().Add(time.Hour)
+	insertRetentionEvent(t, g, "expired", base, "owasp.org", "old.owasp.org", "www.owasp.org")
+	insertRetentionEvent(t, g, "recent", base.AddDate(0, 0, 15), "owasp.org", "www.owasp.org")
+
+	// The expired event finished twenty days before, and the recent event five days before
+	if n, err := pruneEvents(ctx, g, nil, 10, 0, base.AddDate(0, 0, 20)); err != nil || n != 1 {
+		t.Fatalf("Removed %d events, expected the event older than the cutoff: %v", n, err)
+	}
+	if !eventPruned(g, "expired") || eventPruned(g, "recent") {
+		t.Errorf("The event older than the cutoff was not the one removed")
+	}
+	if n, err := g.CountInEdges(ctx, netmap.Node("old.owasp.org")); err == nil && n > 0 {
+		t.Errorf("The name only discovered during the expired event still has %d edges", n)
+	}
+	// The name shared with the recent event is kept along with its records
+	if !hasEvent(g, "www.owasp.org", "recent") {
+		t.Errorf("The name shared with the recent event was removed")
+	}
+	if names := g.EventFQDNs(ctx, "recent"); len(names) != 2 {
+		t.Errorf("The recent event provides %d names, expected 2", len(names))
+	}
+}
+
+func TestPruneEventsScope(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	base := time.Now().Add(time.Hour)
+	insertRetentionEvent(t, g, "owasp", base, "owasp.org", "www.owasp.org")
+	insertRetentionEvent(t, g, "example", base, "example.com", "www.example.com")
+
+	if n, err := pruneEvents(ctx, g, []string{"owasp.org"}, 10, 0, base.AddDate(0, 0, 20)); err != nil || n != 1 {
+		t.Fatalf("Removed %d events, expected only the event in scope: %v", n, err)
+	}
+	if !eventPruned(g, "owasp") || eventPruned(g, "example") {
+		t.Errorf("The event out of scope was removed")
+	}
+	if !hasEvent(g, "www.example.com", "example") {
+		t.Errorf("The name of the event out of scope was removed")
+	}
+}
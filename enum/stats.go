@@ -0,0 +1,111 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/service"
+)
+
+// SourceStatsPredicate is the event property used to store the per-source statistics.
+const SourceStatsPredicate = "source_stats"
+
+// statsReporter is implemented by data sources that track their own failures and rate limiting.
+type statsReporter interface {
+	Stats() (int, time.Duration)
+}
+
+// sourceStats collects the counters for each data source used by the enumeration.
+type sourceStats struct {
+	sync.Mutex
+	counters map[string]*requests.SourceStats
+}
+
+func newSourceStats(srcs []service.Service) *sourceStats {
+	s := &sourceStats{counters: make(map[string]*requests.SourceStats)}
+
+	for _, src := range srcs {
+		s.counters[src.String()] = &requests.SourceStats{Source: src.String()}
+	}
+	return s
+}
+
+func (s *sourceStats) get(source string) *requests.SourceStats {
+	c, found := s.counters[source]
+	if !found {
+		c = &requests.SourceStats{Source: source}
+		s.counters[source] = c
+	}
+	return c
+}
+
+func (s *sourceStats) incRequests(source string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.get(source).Requests++
+}
+
+func (s *sourceStats) incNames(source string, unique bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	c := s.get(source)
+	c.Names++
+	if unique {
+		c.UniqueNames++
+	}
+}
+
+func (s *sourceStats) incResolved(source string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.get(source).Resolved++
+}
+
+// SourceStats returns the counters collected for each data source used by the enumeration.
+func (e *Enumeration) SourceStats() []*requests.SourceStats {
+	e.stats.Lock()
+	defer e.stats.Unlock()
+
+	var list []*requests.SourceStats
+	for _, src := range e.srcs {
+		c := *e.stats.get(src.String())
+
+		if r, ok := src.(statsReporter); ok {
+			c.Errors, c.RateLimitWait = r.Stats()
+		}
+		list = append(list, &c)
+	}
+	for _, name := range e.Guessers() {
+		c := *e.stats.get(name)
+		list = append(list, &c)
+	}
+	return requests.MergeSourceStats(list)
+}
+
+// storeSourceStats saves the per-source statistics as a property of the enumeration event.
+func (e *Enumeration) storeSourceStats(ctx context.Context) error {
+	data, err := json.Marshal(e.SourceStats())
+	if err != nil {
+		return err
+	}
+
+	event, err := e.graph.UpsertEvent(ctx, e.Config.UUID.String())
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the event: %v", e.graph, err)
+	}
+	if err := e.graph.UpsertProperty(ctx, event, SourceStatsPredicate, string(data)); err != nil {
+		return fmt.Errorf("%s failed to store the data source statistics: %v", e.graph, err)
+	}
+	return nil
+}
@@ -71,6 +71,10 @@ func (dm *dataManager) Process(ctx context.Context, data pipeline.Data, tp pipel
 		id = v.Name
 		if err := dm.dnsRequest(ctx, v, tp); err != nil {
 			dm.enum.Config.Log.Print(err.Error())
+		} else if !dm.filter.Test([]byte(id)) {
+			dm.enum.dnssec.addName(v)
+			dm.enum.cnames.addName(v)
+			dm.enum.prober.addName(v)
 		}
 	case *requests.AddrRequest:
 		if v == nil {
@@ -131,6 +135,10 @@ func (dm *dataManager) dnsRequest(ctx context.Context, req *requests.DNSRequest,
 			err = dm.insertSOA(ctx, req, i, tp)
 		case dns.TypeSPF:
 			err = dm.insertSPF(ctx, req, i, tp)
+		case dns.TypeNAPTR:
+			err = dm.insertNAPTR(ctx, req, i, tp)
+		case dns.TypeCAA, dns.TypeDS:
+			err = dm.insertRecordProperty(ctx, req, i)
 		}
 		if err != nil {
 			break
@@ -312,6 +320,45 @@ func (dm *dataManager) insertSPF(ctx context.Context, req *requests.DNSRequest,
 	return nil
 }
 
+func (dm *dataManager) insertNAPTR(ctx context.Context, req *requests.DNSRequest, recidx int, tp pipeline.TaskParams) error {
+	// The replacement field is the last value of the record data
+	fields := strings.Fields(req.Records[recidx].Data)
+	if len(fields) == 0 {
+		return errors.New("failed to extract NAPTR info from the DNS answer data")
+	}
+
+	target := resolve.RemoveLastDot(fields[len(fields)-1])
+	if domain := dm.enum.Config.WhichDomain(target); domain != "" && target != "" {
+		dm.enum.nameSrc.newName(&requests.DNSRequest{
+			Name:   target,
+			Domain: domain,
+			Tag:    requests.DNS,
+			Source: "DNS",
+		})
+	}
+	return dm.insertRecordProperty(ctx, req, recidx)
+}
+
+// insertRecordProperty stores the record data as a property of the FQDN node, using
+// the record type as the predicate, such as caa_record.
+func (dm *dataManager) insertRecordProperty(ctx context.Context, req *requests.DNSRequest, recidx int) error {
+	rec := req.Records[recidx]
+	if rec.Data == "" {
+		return errors.New("failed to extract the record data from the DNS answer")
+	}
+
+	node, err := dm.enum.graph.UpsertFQDN(ctx, rec.Name, req.Source, dm.enum.Config.UUID.String())
+	if err != nil {
+		return fmt.Errorf("%s failed to insert FQDN: %v", dm.enum.graph, err)
+	}
+
+	predicate := strings.ToLower(dns.TypeToString[uint16(rec.Type)]) + "_record"
+	if err := dm.enum.graph.UpsertProperty(ctx, node, predicate, rec.Data); err != nil {
+		return fmt.Errorf("%s failed to insert %s record: %v", dm.enum.graph, dns.TypeToString[uint16(rec.Type)], err)
+	}
+	return nil
+}
+
 func (dm *dataManager) findNamesAndAddresses(ctx context.Context, data, domain string, tp pipeline.TaskParams) {
 	ipre := regexp.MustCompile(amassnet.IPv4RE)
 	for _, ip := range ipre.FindAllString(data, -1) {
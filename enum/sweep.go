@@ -0,0 +1,267 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/caffix/queue"
+	"github.com/caffix/stringset"
+	bf "github.com/tylertreat/BoomFilters"
+	"go.uber.org/ratelimit"
+)
+
+const (
+	defaultSweepSize = 500
+	activeSweepSize  = 1000
+	// IPv4 netblocks with a prefix length of at least 16 are swept entirely
+	minFullSweepPrefix = 16
+	asnCheckDelay      = 5 * time.Second
+	expandCheckDelay   = 250 * time.Millisecond
+)
+
+// reverseSweeper performs the reverse DNS sweeps across the netblocks associated with the in-scope
+// addresses and ASNs, using its own concurrency and rate limits separate from the enumeration pipeline.
+type reverseSweeper struct {
+	enum       *Enumeration
+	netblocks  queue.Queue
+	addrs      queue.Queue
+	filter     *bf.StableBloomFilter
+	swept      *stringset.Set
+	rate       ratelimit.Limiter
+	workers    int
+	size       int
+	maxPending int
+	inflight   int32
+	done       chan struct{}
+	doneOnce   sync.Once
+}
+
+// sweepRequest describes a netblock to be swept, either around the address or entirely.
+type sweepRequest struct {
+	cidr *net.IPNet
+	addr string
+}
+
+// newReverseSweeper returns a reverseSweeper for the provided Enumeration that has not been started yet.
+func newReverseSweeper(e *Enumeration) *reverseSweeper {
+	size := e.Config.ReverseSweepSize
+	if size == 0 {
+		size = defaultSweepSize
+		if e.Config.Active {
+			size = activeSweepSize
+		}
+	}
+
+	workers := e.Config.ReverseSweepConcurrency
+	if workers <= 0 {
+		workers = 1
+	}
+
+	return &reverseSweeper{
+		enum:       e,
+		netblocks:  queue.NewQueue(),
+		addrs:      queue.NewQueue(),
+		filter:     bf.NewDefaultStableBloomFilter(1000000, 0.01),
+		swept:      stringset.New(),
+		rate:       ratelimit.New(e.Config.ReverseSweepQPS, ratelimit.WithoutSlack),
+		workers:    workers,
+		size:       size,
+		maxPending: workers * 100,
+		done:       make(chan struct{}),
+	}
+}
+
+// start launches the goroutines performing the sweeps and queues the netblocks provided in the configuration.
+func (s *reverseSweeper) start() {
+	for _, cidr := range s.enum.Config.CIDRs {
+		s.addNetblock(cidr)
+	}
+	for _, ip := range s.enum.Config.Addresses {
+		s.addAddress(ip.String())
+	}
+
+	go s.expandNetblocks()
+	go s.checkASNs()
+	for i := 0; i < s.workers; i++ {
+		go s.lookups()
+	}
+}
+
+// stop terminates the sweeps and releases the queued addresses.
+func (s *reverseSweeper) stop() {
+	s.doneOnce.Do(func() {
+		close(s.done)
+	})
+
+	s.netblocks.Process(func(e interface{}) {})
+	s.addrs.Process(func(e interface{}) {})
+	s.filter.Reset()
+	s.swept.Close()
+}
+
+// active returns true while the sweeps have addresses remaining to be queried.
+func (s *reverseSweeper) active() bool {
+	return s.netblocks.Len() > 0 || s.addrs.Len() > 0 || atomic.LoadInt32(&s.inflight) > 0
+}
+
+// addAddress queues a sweep around the address within the netblock it belongs to.
+func (s *reverseSweeper) addAddress(addr string) {
+	if yes, _ := amassnet.IsReservedAddress(addr); yes {
+		return
+	}
+
+	if cidr := s.addrCIDR(addr); cidr != nil {
+		s.netblocks.Append(&sweepRequest{
+			cidr: cidr,
+			addr: addr,
+		})
+	}
+}
+
+// addNetblock queues a sweep of the netblock, unless it has already been swept.
+func (s *reverseSweeper) addNetblock(cidr *net.IPNet) {
+	if key := cidr.String(); !s.swept.Has(key) {
+		s.swept.Insert(key)
+		s.netblocks.Append(&sweepRequest{cidr: cidr})
+	}
+}
+
+// Netblocks associated with the in-scope ASNs are swept once the data sources provide them.
+func (s *reverseSweeper) checkASNs() {
+	if len(s.enum.Config.ASNs) == 0 {
+		return
+	}
+
+	t := time.NewTicker(asnCheckDelay)
+	defer t.Stop()
+
+	for {
+		for _, asn := range s.enum.Config.ASNs {
+			req := s.enum.Sys.Cache().ASNSearch(asn)
+			if req == nil {
+				continue
+			}
+
+			for _, netblock := range req.Netblocks {
+				if _, cidr, err := net.ParseCIDR(netblock); err == nil {
+					s.addNetblock(cidr)
+				}
+			}
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// The netblocks are only expanded into addresses as the lookups keep up, which bounds the memory used.
+func (s *reverseSweeper) expandNetblocks() {
+	t := time.NewTicker(expandCheckDelay)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.netblocks.Signal():
+		case <-t.C:
+		}
+
+		for s.addrs.Len() < s.maxPending {
+			e, ok := s.netblocks.Next()
+			if !ok {
+				break
+			}
+			s.expand(e.(*sweepRequest))
+		}
+	}
+}
+
+func (s *reverseSweeper) expand(req *sweepRequest) {
+	var ips []net.IP
+
+	ones, bits := req.cidr.Mask.Size()
+	switch {
+	case req.addr != "":
+		ips = amassnet.CIDRSubset(req.cidr, req.addr, s.size)
+	case bits == 32 && ones >= minFullSweepPrefix:
+		ips = amassnet.AllHosts(req.cidr)
+	default:
+		// Large IPv4 and IPv6 netblocks are only swept from the start of the range
+		ip := net.ParseIP(req.cidr.IP.String())
+		for i := 0; i < s.size; i++ {
+			if amassnet.IPInc(ip); !req.cidr.Contains(ip) {
+				break
+			}
+			ips = append(ips, net.ParseIP(ip.String()))
+		}
+	}
+
+	for _, ip := range ips {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		// The addresses excluded from the scope are not queried
+		if a := ip.String(); !s.enum.Config.IsAddressExcluded(a) && !s.filter.TestAndAdd([]byte(a)) {
+			s.addrs.Append(a)
+		}
+	}
+}
+
+func (s *reverseSweeper) lookups() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.enum.ctx.Done():
+			return
+		case <-s.addrs.Signal():
+		}
+
+		e, ok := s.addrs.Next()
+		if !ok {
+			continue
+		}
+
+		atomic.AddInt32(&s.inflight, 1)
+		s.rate.Take()
+		_ = s.enum.reverseDNS(s.enum.ctx, e.(string))
+		atomic.AddInt32(&s.inflight, -1)
+	}
+}
+
+func (s *reverseSweeper) addrCIDR(addr string) *net.IPNet {
+	if asn := s.enum.Sys.Cache().AddrSearch(addr); asn != nil {
+		if _, cidr, err := net.ParseCIDR(asn.Prefix); err == nil {
+			return cidr
+		}
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil
+	}
+
+	mask := net.CIDRMask(18, 32)
+	if amassnet.IsIPv6(ip) {
+		mask = net.CIDRMask(64, 128)
+	}
+
+	return &net.IPNet{
+		IP:   ip.Mask(mask),
+		Mask: mask,
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"net"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+)
+
+func TestReverseSweepExpand(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.ReverseSweepSize = 10
+	s := newReverseSweeper(&Enumeration{Config: cfg})
+	defer s.stop()
+
+	_, cidr, _ := net.ParseCIDR("72.237.4.0/24")
+	s.expand(&sweepRequest{cidr: cidr, addr: "72.237.4.100"})
+	if num := s.addrs.Len(); num != 11 {
+		t.Errorf("The sweep around the address queued %d addresses, expected 11", num)
+	}
+
+	s.addNetblock(cidr)
+	s.addNetblock(cidr)
+	if num := s.netblocks.Len(); num != 1 {
+		t.Errorf("The netblock was queued %d times, expected once", num)
+	}
+
+	e, _ := s.netblocks.Next()
+	s.expand(e.(*sweepRequest))
+	// The addresses already swept are not queued again
+	if num := s.addrs.Len(); num != 254 {
+		t.Errorf("The sweep of the netblock queued %d addresses, expected 254", num)
+	}
+
+	_, large, _ := net.ParseCIDR("2001:db8::/32")
+	s.expand(&sweepRequest{cidr: large})
+	if num := s.addrs.Len(); num != 254+10 {
+		t.Errorf("The sweep of the large netblock queued %d addresses, expected 10", num-254)
+	}
+}
+
+func TestReverseSweepExclusions(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.ReverseSweepSize = 10
+	_, excluded, _ := net.ParseCIDR("72.237.4.100/30")
+	cfg.ExcludedCIDRs = []*net.IPNet{excluded}
+	s := newReverseSweeper(&Enumeration{Config: cfg})
+	defer s.stop()
+
+	_, cidr, _ := net.ParseCIDR("72.237.4.0/24")
+	s.expand(&sweepRequest{cidr: cidr, addr: "72.237.4.100"})
+	if num := s.addrs.Len(); num != 7 {
+		t.Errorf("The sweep around the address queued %d addresses, expected 7", num)
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resources"
+	"github.com/caffix/netmap"
+	"github.com/caffix/resolve"
+	"github.com/caffix/stringset"
+	"github.com/miekg/dns"
+)
+
+// The confidence of the takeover candidates, which reflects the evidence collected for them.
+const (
+	// The CNAME target or the name servers belong to a service prone to subdomain takeovers
+	TakeoverPossible = 50
+	// The CNAME target does not resolve to an address, or the name servers failed to answer for the zone
+	TakeoverLikely = 75
+	// The service answered with its signature of a resource that is not claimed
+	TakeoverConfirmed = 100
+)
+
+// TakeoverPredicate links the names to the takeover candidates stored in the graph.
+const TakeoverPredicate = "takeover"
+
+// The properties describing the takeover candidate, which are replaced each time the name is checked.
+var takeoverPredicates = []string{"service", "record", "confidence", "evidence"}
+
+const (
+	// The longest CNAME chain followed while searching for takeover candidates
+	maxCNAMEChain = 10
+	// The number of takeover candidates verified concurrently
+	maxTakeoverChecks = 10
+	takeoverTimeout   = 10 * time.Second
+)
+
+// The port of the name servers queried directly while verifying the NS delegations.
+var takeoverNSPort = "53"
+
+// TakeoverCandidate is a name aliased or delegated to a service prone to subdomain takeovers.
+type TakeoverCandidate struct {
+	Name    string
+	Service string
+	Record  string
+	// The CNAME chain of the name, or the name followed by the name servers of the service
+	Chain []string
+	// The CNAME target never resolved to an address
+	Dangling   bool
+	Confidence int
+	Evidence   string
+	fp         *resources.TakeoverFingerprint
+}
+
+// TakeoverCandidates returns the names in scope that are aliased or delegated to services prone
+// to subdomain takeovers, with the confidence and evidence stored when the candidates were checked.
+func TakeoverCandidates(ctx context.Context, db *netmap.Graph, uuids, domains []string, fps []*resources.TakeoverFingerprint) []*TakeoverCandidate {
+	names := stringset.New()
+	defer names.Close()
+
+	for _, uuid := range uuids {
+		for _, name := range db.EventFQDNs(ctx, uuid) {
+			if len(domains) == 0 || nameInDomains(name, domains) {
+				names.Insert(name)
+			}
+		}
+	}
+
+	var candidates []*TakeoverCandidate
+	for _, name := range names.Slice() {
+		found := cnameCandidates(ctx, db, name, fps)
+		found = append(found, nsCandidates(ctx, db, name, fps)...)
+
+		stored := ReadTakeovers(ctx, db, name)
+		for _, c := range found {
+			for _, t := range stored {
+				if t.Service == c.Service && t.Record == c.Record {
+					c.Confidence, c.Evidence = t.Confidence, t.Evidence
+				}
+			}
+		}
+		candidates = append(candidates, found...)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Name == candidates[j].Name {
+			return candidates[i].Record < candidates[j].Record
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates
+}
+
+func nameInDomains(name string, domains []string) bool {
+	n := strings.ToLower(strings.TrimSpace(name))
+
+	for _, d := range domains {
+		if d = strings.ToLower(d); n == d || strings.HasSuffix(n, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Walks the CNAME chain of the name and checks each target against the fingerprints.
+func cnameCandidates(ctx context.Context, db *netmap.Graph, name string, fps []*resources.TakeoverFingerprint) []*TakeoverCandidate {
+	chain := []string{name}
+	seen := stringset.New(name)
+	defer seen.Close()
+
+	var match *resources.TakeoverFingerprint
+	for cur := name; len(chain) <= maxCNAMEChain; {
+		edges, err := db.ReadOutEdges(ctx, netmap.Node(cur), "cname_record")
+		if err != nil || len(edges) == 0 {
+			break
+		}
+
+		target := db.NodeToID(edges[0].To)
+		if seen.Has(target) {
+			break
+		}
+		seen.Insert(target)
+		chain = append(chain, target)
+
+		if match == nil {
+			for _, fp := range fps {
+				if fp.MatchCNAME(target) {
+					match = fp
+					break
+				}
+			}
+		}
+		cur = target
+	}
+	if match == nil {
+		return nil
+	}
+
+	last := chain[len(chain)-1]
+	count, err := db.CountOutEdges(ctx, netmap.Node(last), "a_record", "aaaa_record")
+	c := &TakeoverCandidate{
+		Name:       name,
+		Service:    match.Service,
+		Record:     "CNAME",
+		Chain:      chain,
+		Dangling:   err == nil && count == 0,
+		Confidence: TakeoverPossible,
+		Evidence:   strings.Join(chain, " -> "),
+		fp:         match,
+	}
+	if c.Dangling {
+		c.Confidence = TakeoverLikely
+		c.Evidence += "; the target does not resolve to an address"
+	}
+	return []*TakeoverCandidate{c}
+}
+
+// Checks the name servers the name has been delegated to against the fingerprints.
+func nsCandidates(ctx context.Context, db *netmap.Graph, name string, fps []*resources.TakeoverFingerprint) []*TakeoverCandidate {
+	edges, err := db.ReadOutEdges(ctx, netmap.Node(name), "ns_record")
+	if err != nil || len(edges) == 0 {
+		return nil
+	}
+
+	var services []*resources.TakeoverFingerprint
+	servers := make(map[string][]string)
+	for _, edge := range edges {
+		ns := db.NodeToID(edge.To)
+
+		for _, fp := range fps {
+			if fp.MatchNS(ns) {
+				if _, found := servers[fp.Service]; !found {
+					services = append(services, fp)
+				}
+				servers[fp.Service] = append(servers[fp.Service], ns)
+				break
+			}
+		}
+	}
+
+	var candidates []*TakeoverCandidate
+	for _, fp := range services {
+		sort.Strings(servers[fp.Service])
+		candidates = append(candidates, &TakeoverCandidate{
+			Name:       name,
+			Service:    fp.Service,
+			Record:     "NS",
+			Chain:      append([]string{name}, servers[fp.Service]...),
+			Confidence: TakeoverPossible,
+			Evidence:   name + " delegated to " + strings.Join(servers[fp.Service], ", "),
+			fp:         fp,
+		})
+	}
+	return candidates
+}
+
+// ReadTakeovers returns the takeover candidates of the name stored in the graph.
+func ReadTakeovers(ctx context.Context, db *netmap.Graph, name string) []requests.TakeoverInfo {
+	edges, err := db.ReadOutEdges(ctx, netmap.Node(name), TakeoverPredicate)
+	if err != nil {
+		return nil
+	}
+
+	var takeovers []requests.TakeoverInfo
+	for _, edge := range edges {
+		props, err := db.ReadProperties(ctx, edge.To, takeoverPredicates...)
+		if err != nil {
+			continue
+		}
+
+		var t requests.TakeoverInfo
+		for _, p := range props {
+			v, ok := p.Value.Native().(string)
+			if !ok {
+				continue
+			}
+
+			switch p.Predicate {
+			case "service":
+				t.Service = v
+			case "record":
+				t.Record = v
+			case "confidence":
+				t.Confidence, _ = strconv.Atoi(v)
+			case "evidence":
+				t.Evidence = v
+			}
+		}
+		if t.Service != "" {
+			takeovers = append(takeovers, t)
+		}
+	}
+	return takeovers
+}
+
+// Checks the names of the enumeration aliased or delegated to services prone to subdomain takeovers,
+// and stores the candidates with the evidence collected from the services.
+func (e *Enumeration) checkTakeovers(ctx context.Context) {
+	fps, err := resources.LoadTakeoverFingerprints(e.Config.TakeoverFingerprints)
+	if err != nil {
+		e.Config.Log.Printf("Takeovers: %v", err)
+		return
+	}
+
+	uuid := e.Config.UUID.String()
+	candidates := TakeoverCandidates(ctx, e.graph, []string{uuid}, e.Config.Domains(), fps)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxTakeoverChecks)
+	for _, c := range candidates {
+		select {
+		case <-ctx.Done():
+			return
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(c *TakeoverCandidate) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			e.verifyTakeover(ctx, c)
+			e.Config.Log.Printf("Takeover candidate: %s (%s %s, confidence %d): %s", c.Name, c.Service, c.Record, c.Confidence, c.Evidence)
+			if err := e.insertTakeover(ctx, c); err != nil {
+				e.Config.Log.Print(err.Error())
+			}
+		}(c)
+	}
+	wg.Wait()
+}
+
+func (e *Enumeration) verifyTakeover(ctx context.Context, c *TakeoverCandidate) {
+	if c.Record == "NS" {
+		e.verifyDelegation(ctx, c)
+		return
+	}
+
+	if c.fp.NXDomain {
+		if props, err := e.graph.ReadProperties(ctx, netmap.Node(c.Name), "dangling_cname"); err == nil && len(props) > 0 {
+			c.Confidence = TakeoverConfirmed
+			c.Evidence = strings.Join(c.Chain, " -> ") + "; the target does not exist"
+			if target, ok := props[0].Value.Native().(string); ok {
+				c.Evidence = strings.Join(c.Chain, " -> ") + "; the target " + target + " does not exist"
+			}
+			return
+		}
+	}
+	if c.fp.Fingerprint == "" {
+		return
+	}
+
+	for _, scheme := range []string{"https://", "http://"} {
+		u := scheme + c.Name
+		// The pages of the resources that are not claimed are usually served with error codes
+		body, _ := http.RequestWebPage(http.WithTimeout(ctx, takeoverTimeout), u, nil, nil, nil)
+		if c.fp.MatchBody(body) {
+			c.Confidence = TakeoverConfirmed
+			c.Evidence = strings.Join(c.Chain, " -> ") + "; " + u + " served \"" + c.fp.Fingerprint + "\""
+			return
+		}
+	}
+}
+
+// Queries the name servers of the service directly, which refuse the zones no longer claimed by an account.
+// The name servers also fail for zones they serve, so a SERVFAIL only makes the takeover likely.
+func (e *Enumeration) verifyDelegation(ctx context.Context, c *TakeoverCandidate) {
+	client := &dns.Client{Timeout: takeoverTimeout}
+
+	for _, ns := range c.Chain[1:] {
+		addr, err := e.nameserverAddr(ctx, ns)
+		if err != nil {
+			continue
+		}
+
+		resp, _, err := client.ExchangeContext(ctx, resolve.QueryMsg(c.Name, dns.TypeSOA), net.JoinHostPort(addr, takeoverNSPort))
+		if err != nil {
+			continue
+		}
+
+		switch resp.Rcode {
+		case dns.RcodeRefused:
+			c.Confidence = TakeoverConfirmed
+			c.Evidence += "; " + ns + " answered REFUSED for the zone"
+			return
+		case dns.RcodeServerFailure:
+			if c.Confidence < TakeoverLikely {
+				c.Confidence = TakeoverLikely
+				c.Evidence += "; " + ns + " answered SERVFAIL for the zone"
+			}
+		}
+	}
+}
+
+func (e *Enumeration) insertTakeover(ctx context.Context, c *TakeoverCandidate) error {
+	uuid := e.Config.UUID.String()
+	source := "Takeover Check"
+
+	fqdn, err := e.graph.UpsertFQDN(ctx, c.Name, "DNS", uuid)
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the takeover candidate name: %v", e.graph, err)
+	}
+
+	node, err := e.graph.UpsertNode(ctx, "takeover:"+c.Name+":"+c.Record+":"+c.Service, "takeover")
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the takeover candidate: %v", e.graph, err)
+	}
+	if err := e.graph.AddNodeToEvent(ctx, node, source, uuid); err != nil {
+		return fmt.Errorf("%s failed to add the takeover candidate to the event: %v", e.graph, err)
+	}
+	if err := e.graph.UpsertEdge(ctx, &netmap.Edge{
+		Predicate: TakeoverPredicate,
+		From:      fqdn,
+		To:        node,
+	}); err != nil {
+		return fmt.Errorf("%s failed to link the takeover candidate: %v", e.graph, err)
+	}
+
+	if props, err := e.graph.ReadProperties(ctx, node, takeoverPredicates...); err == nil {
+		for _, p := range props {
+			_ = e.graph.DeleteProperty(ctx, node, p.Predicate, p.Value)
+		}
+	}
+
+	props := map[string]string{
+		"service":    c.Service,
+		"record":     c.Record,
+		"confidence": strconv.Itoa(c.Confidence),
+		"evidence":   c.Evidence,
+	}
+	for pred, val := range props {
+		if err := e.graph.UpsertProperty(ctx, node, pred, val); err != nil {
+			return fmt.Errorf("%s failed to insert the takeover candidate %s: %v", e.graph, pred, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/OWASP/Amass/v3/resources"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+)
+
+func TestTakeoverCandidates(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	uuid := cfg.UUID.String()
+
+	if err := g.UpsertCNAME(ctx, "blog.owasp.org", "owasp.github.io", "DNS", uuid); err != nil {
+		t.Fatalf("Failed to insert the CNAME record: %v", err)
+	}
+	if err := g.UpsertNS(ctx, "dev.owasp.org", "ns1.digitalocean.com", "DNS", uuid); err != nil {
+		t.Fatalf("Failed to insert the NS record: %v", err)
+	}
+	if err := g.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", uuid); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+
+	fps, err := resources.GetTakeoverFingerprints()
+	if err != nil {
+		t.Fatalf("Failed to load the takeover fingerprints: %v", err)
+	}
+
+	candidates := TakeoverCandidates(ctx, g, []string{uuid}, cfg.Domains(), fps)
+	if len(candidates) != 2 {
+		t.Fatalf("Returned %d takeover candidates, expected 2", len(candidates))
+	}
+	if c := candidates[0]; c.Name != "blog.owasp.org" || c.Record != "CNAME" || !c.Dangling || c.Confidence != TakeoverLikely {
+		t.Errorf("The dangling CNAME was not reported as a likely takeover: %+v", c)
+	}
+	if c := candidates[1]; c.Name != "dev.owasp.org" || c.Record != "NS" || c.Confidence != TakeoverPossible {
+		t.Errorf("The delegation was not reported as a possible takeover: %+v", c)
+	}
+
+	e := &Enumeration{Config: cfg, graph: g}
+	c := candidates[0]
+	c.Confidence = TakeoverConfirmed
+	c.Evidence = "the service signature was observed"
+	// The properties are replaced each time the name is checked
+	for i := 0; i < 2; i++ {
+		if err := e.insertTakeover(ctx, c); err != nil {
+			t.Fatalf("Failed to insert the takeover candidate: %v", err)
+		}
+	}
+
+	takeovers := ReadTakeovers(ctx, g, "blog.owasp.org")
+	if len(takeovers) != 1 || takeovers[0].Confidence != TakeoverConfirmed || takeovers[0].Evidence != c.Evidence {
+		t.Errorf("The stored takeover candidate was not read back: %+v", takeovers)
+	}
+	if candidates := TakeoverCandidates(ctx, g, []string{uuid}, cfg.Domains(), fps); candidates[0].Confidence != TakeoverConfirmed {
+		t.Errorf("The stored confidence was not returned with the takeover candidate")
+	}
+}
+
+func TestVerifyDelegation(t *testing.T) {
+	var rcode int32
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen for the DNS test server: %v", err)
+	}
+	// The server resolves the name server of the service, and answers for the zone with the selected code
+	srv := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		switch q := req.Question[0]; {
+		case q.Qtype == dns.TypeA && q.Name == "ns1.digitalocean.com.":
+			resp.SetReply(req)
+			resp.Answer = append(resp.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+				A:   net.ParseIP("127.0.0.1"),
+			})
+		case q.Qtype == dns.TypeSOA:
+			resp.SetRcode(req, int(atomic.LoadInt32(&rcode)))
+		default:
+			resp.SetRcode(req, dns.RcodeNameError)
+		}
+		_ = w.WriteMsg(resp)
+	})}
+	go func() { _ = srv.ActivateAndServe() }()
+	defer func() { _ = srv.Shutdown() }()
+
+	_, port, _ := net.SplitHostPort(pc.LocalAddr().String())
+	defer func(p string) { takeoverNSPort = p }(takeoverNSPort)
+	takeoverNSPort = port
+
+	pool := resolvers.NewPool()
+	defer pool.Stop()
+	_ = pool.AddResolvers(10, pc.LocalAddr().String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	uuid := cfg.UUID.String()
+	if err := g.UpsertNS(ctx, "dev.owasp.org", "ns1.digitalocean.com", "DNS", uuid); err != nil {
+		t.Fatalf("Failed to insert the NS record: %v", err)
+	}
+	fps, err := resources.GetTakeoverFingerprints()
+	if err != nil {
+		t.Fatalf("Failed to load the takeover fingerprints: %v", err)
+	}
+	e := &Enumeration{Config: cfg, Sys: &systems.SimpleSystem{Cfg: cfg, Pool: pool, Trusted: pool}, graph: g}
+
+	for _, test := range []struct {
+		rcode      int
+		confidence int
+		evidence   string
+	}{
+		{dns.RcodeRefused, TakeoverConfirmed, "ns1.digitalocean.com answered REFUSED"},
+		// The name servers also fail for the zones they serve
+		{dns.RcodeServerFailure, TakeoverLikely, "ns1.digitalocean.com answered SERVFAIL"},
+		{dns.RcodeSuccess, TakeoverPossible, ""},
+	} {
+		atomic.StoreInt32(&rcode, int32(test.rcode))
+
+		candidates := TakeoverCandidates(ctx, g, []string{uuid}, cfg.Domains(), fps)
+		if len(candidates) != 1 || candidates[0].Record != "NS" {
+			t.Fatalf("The delegation was not returned as a takeover candidate: %v", candidates)
+		}
+
+		c := candidates[0]
+		e.verifyTakeover(ctx, c)
+		if c.Confidence != test.confidence {
+			t.Errorf("The %s answer resulted in the confidence %d, expected %d", dns.RcodeToString[test.rcode], c.Confidence, test.confidence)
+		}
+		if test.evidence != "" && !strings.Contains(c.Evidence, test.evidence) {
+			t.Errorf("The %s answer was not provided as evidence: %s", dns.RcodeToString[test.rcode], c.Evidence)
+		}
+	}
+}
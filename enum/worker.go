@@ -0,0 +1,127 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/caffix/queue"
+	"github.com/caffix/stringset"
+)
+
+// How often wait checks whether the queued items have been processed
+const probeCheckDelay = 100 * time.Millisecond
+
+// asyncWorker processes each queued item once using its own goroutines, so the slow lookups and
+// probes of the enumeration features do not hold the storage of the pipeline data.
+type asyncWorker struct {
+	items    queue.Queue
+	seen     *stringset.Set
+	workers  int
+	process  func(ctx context.Context, item interface{})
+	inflight int32
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopOnce sync.Once
+}
+
+// newAsyncWorker returns an asyncWorker calling process with the queued items from the
+// provided number of goroutines, which has not been started yet.
+func newAsyncWorker(workers int, process func(ctx context.Context, item interface{})) *asyncWorker {
+	return &asyncWorker{
+		items:   queue.NewQueue(),
+		seen:    stringset.New(),
+		workers: workers,
+		process: process,
+	}
+}
+
+// start launches the goroutines processing the queued items until the context expires or the worker is stopped.
+func (w *asyncWorker) start(ctx context.Context) {
+	w.ctx, w.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < w.workers; i++ {
+		go w.processItems()
+	}
+}
+
+// stop terminates the processing and releases the queued items.
+func (w *asyncWorker) stop() {
+	w.stopOnce.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+	})
+
+	w.items.Process(func(e interface{}) {})
+	w.seen.Close()
+}
+
+// wait blocks until the queued items have been processed or the context expires.
+func (w *asyncWorker) wait(ctx context.Context) {
+	if w == nil {
+		return
+	}
+
+	t := time.NewTicker(probeCheckDelay)
+	defer t.Stop()
+
+	for w.items.Len() > 0 || atomic.LoadInt32(&w.inflight) > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// add queues the item to be processed once for the key.
+func (w *asyncWorker) add(key string, item interface{}) {
+	if w == nil || w.seen.Has(key) {
+		return
+	}
+
+	w.seen.Insert(key)
+	w.items.Append(item)
+}
+
+func (w *asyncWorker) processItems() {
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-w.items.Signal():
+		}
+
+		// The item is counted before it leaves the queue, so wait does not miss it
+		atomic.AddInt32(&w.inflight, 1)
+		if e, ok := w.items.Next(); ok {
+			w.process(w.ctx, e)
+		}
+		atomic.AddInt32(&w.inflight, -1)
+	}
+}
+
+// Returns the async workers of the features enabled for the enumeration.
+func (e *Enumeration) asyncWorkers() []*asyncWorker {
+	var workers []*asyncWorker
+
+	if e.cnames != nil {
+		workers = append(workers, e.cnames.asyncWorker)
+	}
+	if e.dnssec != nil {
+		workers = append(workers, e.dnssec.asyncWorker)
+	}
+	if e.prober != nil {
+		workers = append(workers, e.prober.asyncWorker)
+	}
+	if e.scanner != nil {
+		workers = append(workers, e.scanner.asyncWorker)
+	}
+	return workers
+}
@@ -0,0 +1,57 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncWorker(t *testing.T) {
+	var lock sync.Mutex
+	var processed []string
+
+	w := newAsyncWorker(3, func(ctx context.Context, item interface{}) {
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		defer lock.Unlock()
+		processed = append(processed, item.(string))
+	})
+	defer w.stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	w.start(ctx)
+
+	for _, name := range []string{"www.owasp.org", "dev.owasp.org", "www.owasp.org", "api.owasp.org"} {
+		w.add(name, name)
+	}
+	w.wait(ctx)
+
+	lock.Lock()
+	sort.Strings(processed)
+	if len(processed) != 3 || processed[0] != "api.owasp.org" || processed[1] != "dev.owasp.org" || processed[2] != "www.owasp.org" {
+		t.Errorf("The items were not processed once each: %v", processed)
+	}
+	lock.Unlock()
+
+	// The stopped worker releases the items that are queued later
+	w.stop()
+	w.add("mail.owasp.org", "mail.owasp.org")
+	time.Sleep(5 * probeCheckDelay)
+	lock.Lock()
+	if len(processed) != 3 {
+		t.Errorf("The stopped worker processed the item: %v", processed)
+	}
+	lock.Unlock()
+
+	var nilWorker *asyncWorker
+	nilWorker.add("www.owasp.org", "www.owasp.org")
+	nilWorker.wait(context.Background())
+}
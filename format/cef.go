@@ -0,0 +1,132 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// The formats of the messages sent to the SIEMs.
+const (
+	SIEMFormatCEF  = "cef"
+	SIEMFormatLEEF = "leef"
+)
+
+const (
+	// The event class identifying the discovery of a name by the SIEMs
+	siemEventID = "name-discovered"
+	// The discoveries are informational events on the scale from 0 to 10
+	siemSeverity = 3
+)
+
+// SIEMMessage returns the finding formatted as a CEF or LEEF message.
+func SIEMMessage(siemFormat string, o *requests.Output) (string, error) {
+	switch strings.ToLower(siemFormat) {
+	case SIEMFormatCEF:
+		return CEFMessage(o), nil
+	case SIEMFormatLEEF:
+		return LEEFMessage(o), nil
+	}
+	return "", fmt.Errorf("the SIEM message format %s is not supported", siemFormat)
+}
+
+// CEFMessage returns the finding formatted as an ArcSight Common Event Format (CEF) message.
+func CEFMessage(o *requests.Output) string {
+	var ext []string
+
+	for _, f := range siemFields(o) {
+		// The custom fields are described by the labels
+		if f.label != "" {
+			ext = append(ext, f.cef+"Label="+f.label)
+		}
+		ext = append(ext, f.cef+"="+cefEscapeExtension(f.value))
+	}
+
+	return fmt.Sprintf("CEF:0|OWASP|Amass|%s|%s|%s|%d|%s", cefEscapeHeader(Version),
+		siemEventID, cefEscapeHeader("Name discovered: "+o.Name), siemSeverity, strings.Join(ext, " "))
+}
+
+// LEEFMessage returns the finding formatted as an IBM QRadar Log Event Extended Format (LEEF) 1.0 message.
+func LEEFMessage(o *requests.Output) string {
+	var attrs []string
+
+	for _, f := range siemFields(o) {
+		attrs = append(attrs, f.leef+"="+leefEscape(f.value))
+	}
+	return fmt.Sprintf("LEEF:1.0|OWASP|Amass|%s|%s|%s", Version, siemEventID, strings.Join(attrs, "\t"))
+}
+
+type siemField struct {
+	cef   string
+	label string
+	leef  string
+	value string
+}
+
+// Returns the attributes of the finding using the keys of each format.
+func siemFields(o *requests.Output) []siemField {
+	var asns, netblocks []string
+	seen := make(map[string]struct{})
+	for _, a := range o.Addresses {
+		if asn := strconv.Itoa(a.ASN); a.ASN != 0 && !hasKey(seen, "asn"+asn) {
+			asns = append(asns, asn)
+		}
+		if a.CIDRStr != "" && !hasKey(seen, "cidr"+a.CIDRStr) {
+			netblocks = append(netblocks, a.CIDRStr)
+		}
+	}
+
+	var dst, conf string
+	addrs := outputAddresses(o)
+	if len(addrs) > 0 {
+		dst = addrs[0]
+	}
+	if o.Confidence > 0 {
+		conf = strconv.Itoa(o.Confidence)
+	}
+
+	var results []siemField
+	for _, f := range []siemField{
+		{cef: "dhost", leef: "dstHost", value: o.Name},
+		{cef: "dst", leef: "dst", value: dst},
+		{cef: "cat", leef: "cat", value: o.Tag},
+		{cef: "cs1", label: "domain", leef: "domain", value: o.Domain},
+		{cef: "cs2", label: "addresses", leef: "addresses", value: strings.Join(addrs, ",")},
+		{cef: "cs3", label: "asn", leef: "asn", value: strings.Join(asns, ",")},
+		{cef: "cs4", label: "netblocks", leef: "netblocks", value: strings.Join(netblocks, ",")},
+		{cef: "cs5", label: "sources", leef: "sources", value: strings.Join(o.Sources, ",")},
+		{cef: "cn1", label: "confidence", leef: "confidence", value: conf},
+	} {
+		if f.value != "" {
+			results = append(results, f)
+		}
+	}
+	return results
+}
+
+func hasKey(seen map[string]struct{}, key string) bool {
+	if _, found := seen[key]; found {
+		return true
+	}
+
+	seen[key] = struct{}{}
+	return false
+}
+
+func cefEscapeHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r", " ", "\n", " ").Replace(s)
+}
+
+func cefEscapeExtension(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "=", `\=`, "\r", `\r`, "\n", `\n`).Replace(s)
+}
+
+func leefEscape(s string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(s)
+}
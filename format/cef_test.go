@@ -0,0 +1,50 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"net"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestSIEMMessage(t *testing.T) {
+	o := &requests.Output{
+		Name:    "www.owasp.org",
+		Domain:  "owasp.org",
+		Tag:     requests.DNS,
+		Sources: []string{"DNS", "crt=sh"},
+		Addresses: []requests.AddressInfo{
+			{Address: net.ParseIP("192.168.1.1"), ASN: 26808, CIDRStr: "192.168.1.0/24"},
+		},
+	}
+
+	cef, err := SIEMMessage("CEF", o)
+	if err != nil {
+		t.Fatalf("Failed to format the CEF message: %v", err)
+	}
+	expected := "CEF:0|OWASP|Amass|" + Version + "|name-discovered|Name discovered: www.owasp.org|3|" +
+		"dhost=www.owasp.org dst=192.168.1.1 cat=dns cs1Label=domain cs1=owasp.org cs2Label=addresses cs2=192.168.1.1 " +
+		"cs3Label=asn cs3=26808 cs4Label=netblocks cs4=192.168.1.0/24 cs5Label=sources cs5=DNS,crt\\=sh"
+	if cef != expected {
+		t.Errorf("The CEF message was:\n%s\nexpected:\n%s", cef, expected)
+	}
+
+	leef, err := SIEMMessage("leef", o)
+	if err != nil {
+		t.Fatalf("Failed to format the LEEF message: %v", err)
+	}
+	expected = "LEEF:1.0|OWASP|Amass|" + Version + "|name-discovered|" +
+		"dstHost=www.owasp.org\tdst=192.168.1.1\tcat=dns\tdomain=owasp.org\taddresses=192.168.1.1\t" +
+		"asn=26808\tnetblocks=192.168.1.0/24\tsources=DNS,crt=sh"
+	if leef != expected {
+		t.Errorf("The LEEF message was:\n%s\nexpected:\n%s", leef, expected)
+	}
+
+	if _, err := SIEMMessage("xml", o); err == nil {
+		t.Errorf("An unsupported format was accepted")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/stringset"
+)
+
+// CSVColumns are the columns available for the CSV output, in the default order.
+var CSVColumns = []string{"name", "domain", "addresses", "asn", "netblock", "sources", "first_seen", "tag"}
+
+// ParseCSVColumns returns the columns selected by the comma-separated list.
+// All the columns are selected when the list is empty.
+func ParseCSVColumns(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return CSVColumns, nil
+	}
+
+	valid := stringset.New(CSVColumns...)
+	defer valid.Close()
+
+	var columns []string
+	for _, c := range strings.Split(list, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if !valid.Has(c) {
+			return nil, fmt.Errorf("%s is not a valid CSV column, choose from %s", c, strings.Join(CSVColumns, ", "))
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+// CSVWriter writes the findings as CSV rows containing the selected columns.
+// Columns holding multiple values separate them with spaces.
+type CSVWriter struct {
+	cw      *csv.Writer
+	columns []string
+}
+
+// NewCSVWriter returns a CSVWriter after writing the header row to the writer.
+func NewCSVWriter(w io.Writer, columns []string) (*CSVWriter, error) {
+	c := &CSVWriter{
+		cw:      csv.NewWriter(w),
+		columns: columns,
+	}
+
+	if err := c.cw.Write(columns); err != nil {
+		return nil, err
+	}
+	c.cw.Flush()
+	return c, c.cw.Error()
+}
+
+// AppendCSVWriter returns a CSVWriter adding rows to the CSV output that already starts with the header row.
+func AppendCSVWriter(w io.Writer, columns []string) *CSVWriter {
+	return &CSVWriter{
+		cw:      csv.NewWriter(w),
+		columns: columns,
+	}
+}
+
+// Write adds the row for the finding, first observed at the provided time, and flushes it to the writer.
+func (c *CSVWriter) Write(o *requests.Output, firstSeen time.Time) error {
+	row := make([]string, 0, len(c.columns))
+
+	for _, col := range c.columns {
+		var value string
+
+		switch col {
+		case "name":
+			value = o.Name
+		case "domain":
+			value = o.Domain
+		case "addresses":
+			var addrs []string
+			for _, a := range o.Addresses {
+				addrs = append(addrs, a.Address.String())
+			}
+			value = strings.Join(addrs, " ")
+		case "asn":
+			var asns []string
+			for _, a := range o.Addresses {
+				if a.ASN != 0 {
+					asns = appendUnique(asns, strconv.Itoa(a.ASN))
+				}
+			}
+			value = strings.Join(asns, " ")
+		case "netblock":
+			var cidrs []string
+			for _, a := range o.Addresses {
+				if a.CIDRStr != "" {
+					cidrs = appendUnique(cidrs, a.CIDRStr)
+				}
+			}
+			value = strings.Join(cidrs, " ")
+		case "sources":
+			value = strings.Join(o.Sources, " ")
+		case "first_seen":
+			if !firstSeen.IsZero() {
+				value = firstSeen.UTC().Format(time.RFC3339)
+			}
+		case "tag":
+			value = o.Tag
+		}
+		row = append(row, value)
+	}
+
+	if err := c.cw.Write(row); err != nil {
+		return err
+	}
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+func appendUnique(values []string, v string) []string {
+	for _, existing := range values {
+		if existing == v {
+			return values
+		}
+	}
+	return append(values, v)
+}
@@ -0,0 +1,53 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestParseCSVColumns(t *testing.T) {
+	if cols, err := ParseCSVColumns(""); err != nil || len(cols) != len(CSVColumns) {
+		t.Errorf("An empty list did not select all the columns: %v", cols)
+	}
+	if cols, err := ParseCSVColumns("Name, asn"); err != nil || len(cols) != 2 || cols[0] != "name" || cols[1] != "asn" {
+		t.Errorf("The columns were not parsed: %v %v", cols, err)
+	}
+	if _, err := ParseCSVColumns("name,bogus"); err == nil {
+		t.Errorf("An invalid column was accepted")
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewCSVWriter(&buf, []string{"name", "addresses", "asn", "netblock", "first_seen"})
+	if err != nil {
+		t.Fatalf("Failed to create the CSV writer: %v", err)
+	}
+
+	o := &requests.Output{
+		Name:   "www.owasp.org",
+		Domain: "owasp.org",
+		Addresses: []requests.AddressInfo{
+			{Address: net.ParseIP("192.168.1.1"), ASN: 26808, CIDRStr: "192.168.1.0/24"},
+			{Address: net.ParseIP("192.168.1.2"), ASN: 26808, CIDRStr: "192.168.1.0/24"},
+		},
+	}
+	if err := w.Write(o, time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Failed to write the CSV row: %v", err)
+	}
+
+	expected := "name,addresses,asn,netblock,first_seen\n" +
+		"www.owasp.org,192.168.1.1 192.168.1.2,26808,192.168.1.0/24,2022-04-01T12:00:00Z\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("The CSV output was:\n%s\nexpected:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// The types of changes reported by the diff.
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// Diff contains the differences between the findings of two enumerations or databases.
+type Diff struct {
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	Changes []DiffChange `json:"changes"`
+}
+
+// DiffChange describes a name that was added, removed or had the addresses change.
+type DiffChange struct {
+	Change           string   `json:"change"`
+	Name             string   `json:"name"`
+	Domain           string   `json:"domain"`
+	Addresses        []string `json:"addresses,omitempty"`
+	AddedAddresses   []string `json:"added_addresses,omitempty"`
+	RemovedAddresses []string `json:"removed_addresses,omitempty"`
+}
+
+// DiffOutput compares the older findings with the newer findings. The changes are sorted by name.
+func DiffOutput(older, newer []*requests.Output) *Diff {
+	oldmap := outputByName(older)
+	newmap := outputByName(newer)
+
+	d := new(Diff)
+	for name, o := range newmap {
+		addrs := outputAddresses(o)
+
+		prev, found := oldmap[name]
+		if !found {
+			d.Changes = append(d.Changes, DiffChange{
+				Change:    DiffAdded,
+				Name:      name,
+				Domain:    o.Domain,
+				Addresses: addrs,
+			})
+			continue
+		}
+
+		prevAddrs := outputAddresses(prev)
+		added := subtractStrings(addrs, prevAddrs)
+		removed := subtractStrings(prevAddrs, addrs)
+		if len(added) > 0 || len(removed) > 0 {
+			d.Changes = append(d.Changes, DiffChange{
+				Change:           DiffChanged,
+				Name:             name,
+				Domain:           o.Domain,
+				Addresses:        addrs,
+				AddedAddresses:   added,
+				RemovedAddresses: removed,
+			})
+		}
+	}
+
+	for name, o := range oldmap {
+		if _, found := newmap[name]; !found {
+			d.Changes = append(d.Changes, DiffChange{
+				Change:    DiffRemoved,
+				Name:      name,
+				Domain:    o.Domain,
+				Addresses: outputAddresses(o),
+			})
+		}
+	}
+
+	sort.Slice(d.Changes, func(i, j int) bool {
+		return d.Changes[i].Name < d.Changes[j].Name
+	})
+	return d
+}
+
+// WriteDiffJSON writes the differences to the writer as a JSON document.
+func WriteDiffJSON(w io.Writer, d *Diff) error {
+	enc := json.NewEncoder(w)
+
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteDiffCSV writes the differences to the writer as CSV, with the addresses separated by spaces.
+func WriteDiffCSV(w io.Writer, d *Diff) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"change", "name", "domain", "addresses", "added_addresses", "removed_addresses"}); err != nil {
+		return err
+	}
+	for _, c := range d.Changes {
+		if err := cw.Write([]string{
+			c.Change,
+			c.Name,
+			c.Domain,
+			strings.Join(c.Addresses, " "),
+			strings.Join(c.AddedAddresses, " "),
+			strings.Join(c.RemovedAddresses, " "),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func outputByName(output []*requests.Output) map[string]*requests.Output {
+	names := make(map[string]*requests.Output, len(output))
+
+	for _, o := range output {
+		if prev, found := names[o.Name]; found {
+			// Combine the addresses of names provided more than once
+			merged := *prev
+			merged.Addresses = append(append([]requests.AddressInfo(nil), prev.Addresses...), o.Addresses...)
+			names[o.Name] = &merged
+			continue
+		}
+		names[o.Name] = o
+	}
+	return names
+}
+
+func outputAddresses(o *requests.Output) []string {
+	seen := make(map[string]struct{})
+
+	var addrs []string
+	for _, a := range o.Addresses {
+		if a.Address == nil {
+			continue
+		}
+		if addr := a.Address.String(); addr != "" {
+			if _, found := seen[addr]; !found {
+				seen[addr] = struct{}{}
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+
+	sort.Strings(addrs)
+	return addrs
+}
+
+// Returns the strings in a that are not in b.
+func subtractStrings(a, b []string) []string {
+	set := make(map[string]struct{}, len(b))
+	for _, s := range b {
+		set[s] = struct{}{}
+	}
+
+	var diff []string
+	for _, s := range a {
+		if _, found := set[s]; !found {
+			diff = append(diff, s)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,82 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func diffTestOutput(name string, addrs ...string) *requests.Output {
+	o := &requests.Output{Name: name, Domain: "owasp.org"}
+
+	for _, addr := range addrs {
+		o.Addresses = append(o.Addresses, requests.AddressInfo{Address: net.ParseIP(addr)})
+	}
+	return o
+}
+
+func TestDiffOutput(t *testing.T) {
+	older := []*requests.Output{
+		diffTestOutput("www.owasp.org", "192.168.1.1"),
+		diffTestOutput("old.owasp.org", "192.168.1.2"),
+		diffTestOutput("mail.owasp.org", "192.168.1.3"),
+	}
+	newer := []*requests.Output{
+		diffTestOutput("www.owasp.org", "192.168.1.1", "192.168.1.10"),
+		diffTestOutput("mail.owasp.org", "192.168.1.3"),
+		diffTestOutput("new.owasp.org", "192.168.1.4"),
+	}
+
+	d := DiffOutput(older, newer)
+	if len(d.Changes) != 3 {
+		t.Fatalf("The diff returned %d changes, expected 3: %v", len(d.Changes), d.Changes)
+	}
+
+	expected := []struct {
+		change string
+		name   string
+	}{
+		{DiffAdded, "new.owasp.org"},
+		{DiffRemoved, "old.owasp.org"},
+		{DiffChanged, "www.owasp.org"},
+	}
+	for i, e := range expected {
+		if c := d.Changes[i]; c.Change != e.change || c.Name != e.name {
+			t.Errorf("Change %d was %s %s, expected %s %s", i, c.Change, c.Name, e.change, e.name)
+		}
+	}
+	if c := d.Changes[2]; len(c.AddedAddresses) != 1 || c.AddedAddresses[0] != "192.168.1.10" || len(c.RemovedAddresses) != 0 {
+		t.Errorf("The address changes were not identified: %v", c)
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	d := DiffOutput(nil, []*requests.Output{diffTestOutput("www.owasp.org", "192.168.1.1", "192.168.1.2")})
+
+	var buf bytes.Buffer
+	if err := WriteDiffJSON(&buf, d); err != nil {
+		t.Fatalf("Failed to write the JSON: %v", err)
+	}
+
+	var decoded Diff
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded.Changes) != 1 {
+		t.Errorf("Failed to decode the JSON: %v", err)
+	}
+
+	buf.Reset()
+	if err := WriteDiffCSV(&buf, d); err != nil {
+		t.Fatalf("Failed to write the CSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[1] != "added,www.owasp.org,owasp.org,192.168.1.1 192.168.1.2,," {
+		t.Errorf("The CSV was not written correctly: %v", lines)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// MarkdownReport contains the findings and the changes presented by the Markdown report.
+type MarkdownReport struct {
+	Title     string
+	Generated time.Time
+	// The period covered by the findings
+	Period string
+	Output []*requests.Output
+	// The changes since the previous enumerations, which can be nil
+	Diff *Diff
+}
+
+// WriteMarkdownReport writes the report to the writer as a Markdown document. Each domain has
+// a section with the tables of the new names, the changed records and the infrastructure.
+func WriteMarkdownReport(w io.Writer, rep *MarkdownReport) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# %s\n\n", rep.Title)
+	fmt.Fprintf(bw, "Generated by OWASP Amass %s on %s.\n", Version, rep.Generated.UTC().Format("2006-01-02 15:04 MST"))
+	if rep.Period != "" {
+		fmt.Fprintf(bw, "The findings cover the enumerations between %s.\n", rep.Period)
+	}
+	if rep.Diff != nil {
+		fmt.Fprintf(bw, "The changes are relative to the enumerations between %s.\n", rep.Diff.From)
+	}
+
+	domains := make(map[string][]*requests.Output)
+	for _, o := range rep.Output {
+		domains[o.Domain] = append(domains[o.Domain], o)
+	}
+	changes := make(map[string][]DiffChange)
+	if rep.Diff != nil {
+		for _, c := range rep.Diff.Changes {
+			changes[c.Domain] = append(changes[c.Domain], c)
+			if _, found := domains[c.Domain]; !found {
+				domains[c.Domain] = nil
+			}
+		}
+	}
+
+	var names []string
+	for domain := range domains {
+		names = append(names, domain)
+	}
+	sort.Strings(names)
+
+	for _, domain := range names {
+		writeMarkdownDomain(bw, domain, domains[domain], changes[domain], rep.Diff != nil)
+	}
+	return bw.Flush()
+}
+
+func writeMarkdownDomain(w io.Writer, domain string, output []*requests.Output, changes []DiffChange, diff bool) {
+	tags := make(map[string]int)
+	asns := make(map[int]*ASNSummaryData)
+	addrs := make(map[string]struct{})
+	for _, o := range output {
+		UpdateSummaryData(o, tags, asns)
+		for _, a := range outputAddresses(o) {
+			addrs[a] = struct{}{}
+		}
+	}
+
+	fmt.Fprintf(w, "\n## %s\n\n", markdownEscape(domain))
+	fmt.Fprintf(w, "%d names and %d addresses were discovered.\n", len(output), len(addrs))
+
+	if diff {
+		var added, changed []DiffChange
+		for _, c := range changes {
+			if c.Change == DiffAdded {
+				added = append(added, c)
+			} else {
+				changed = append(changed, c)
+			}
+		}
+
+		fmt.Fprint(w, "\n### New Names\n\n")
+		if len(added) == 0 {
+			fmt.Fprintln(w, "No new names were discovered.")
+		} else {
+			fmt.Fprintln(w, "| Name | Addresses |")
+			fmt.Fprintln(w, "|------|-----------|")
+			for _, c := range added {
+				fmt.Fprintf(w, "| %s | %s |\n", markdownEscape(c.Name), markdownEscape(strings.Join(c.Addresses, ", ")))
+			}
+		}
+
+		fmt.Fprint(w, "\n### Changed Records\n\n")
+		if len(changed) == 0 {
+			fmt.Fprintln(w, "No records were changed.")
+		} else {
+			fmt.Fprintln(w, "| Name | Change | Added Addresses | Removed Addresses |")
+			fmt.Fprintln(w, "|------|--------|-----------------|-------------------|")
+			for _, c := range changed {
+				added, removed := c.AddedAddresses, c.RemovedAddresses
+				// The addresses of a removed name are no longer present
+				if c.Change == DiffRemoved {
+					removed = c.Addresses
+				}
+
+				fmt.Fprintf(w, "| %s | %s | %s | %s |\n", markdownEscape(c.Name), c.Change,
+					markdownEscape(strings.Join(added, ", ")), markdownEscape(strings.Join(removed, ", ")))
+			}
+		}
+	}
+
+	fmt.Fprint(w, "\n### Infrastructure\n\n")
+	if len(asns) == 0 {
+		fmt.Fprintln(w, "No infrastructure was identified.")
+		return
+	}
+
+	var numbers []int
+	for asn := range asns {
+		numbers = append(numbers, asn)
+	}
+	sort.Ints(numbers)
+
+	fmt.Fprintln(w, "| ASN | Description | Netblock | Names |")
+	fmt.Fprintln(w, "|-----|-------------|----------|-------|")
+	for _, asn := range numbers {
+		data := asns[asn]
+
+		var cidrs []string
+		for cidr := range data.Netblocks {
+			cidrs = append(cidrs, cidr)
+		}
+		sort.Strings(cidrs)
+
+		for _, cidr := range cidrs {
+			fmt.Fprintf(w, "| %d | %s | %s | %d |\n", asn, markdownEscape(data.Name), cidr, data.Netblocks[cidr])
+		}
+	}
+}
+
+// Escapes the characters that would otherwise break the table cells or be rendered as formatting.
+func markdownEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "\n", " ").Replace(s)
+}
@@ -0,0 +1,55 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestWriteMarkdownReport(t *testing.T) {
+	addr := func(ip string) requests.AddressInfo {
+		return requests.AddressInfo{Address: net.ParseIP(ip), ASN: 26808, Description: "UTEXAS", CIDRStr: "192.168.1.0/24"}
+	}
+
+	older := []*requests.Output{
+		{Name: "www.owasp.org", Domain: "owasp.org", Addresses: []requests.AddressInfo{addr("192.168.1.1")}},
+		{Name: "old.owasp.org", Domain: "owasp.org", Addresses: []requests.AddressInfo{addr("192.168.1.3")}},
+	}
+	newer := []*requests.Output{
+		{Name: "www.owasp.org", Domain: "owasp.org", Addresses: []requests.AddressInfo{addr("192.168.1.2")}},
+		{Name: "new_host.owasp.org", Domain: "owasp.org", Addresses: []requests.AddressInfo{addr("192.168.1.4")}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdownReport(&buf, &MarkdownReport{
+		Title:     "Amass Report",
+		Generated: time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC),
+		Output:    newer,
+		Diff:      DiffOutput(older, newer),
+	}); err != nil {
+		t.Fatalf("Failed to write the report: %v", err)
+	}
+
+	report := buf.String()
+	for _, expected := range []string{
+		"# Amass Report\n",
+		"## owasp.org\n",
+		"2 names and 2 addresses were discovered.",
+		"| new\\_host.owasp.org | 192.168.1.4 |",
+		"| old.owasp.org | removed |  | 192.168.1.3 |",
+		"| www.owasp.org | changed | 192.168.1.2 | 192.168.1.1 |",
+		"| 26808 | UTEXAS | 192.168.1.0/24 | 2 |",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("The report is missing %q:\n%s", expected, report)
+		}
+	}
+}
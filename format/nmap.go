@@ -0,0 +1,235 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// The formats of the scan target lists.
+const (
+	TargetsFormatNmap    = "nmap"
+	TargetsFormatMasscan = "masscan"
+)
+
+type scanTargetGroup struct {
+	cidr  string
+	asn   int
+	desc  string
+	addrs map[string]net.IP
+	names map[string]struct{}
+}
+
+// WriteScanTargets writes the addresses and names of the findings as a target list for nmap (-iL)
+// or masscan (-iL), grouped by the netblocks containing the addresses. Masscan only scans addresses,
+// so the names are only included in the nmap target lists.
+func WriteScanTargets(w io.Writer, output []*requests.Output, targetsFormat string) error {
+	targetsFormat = strings.ToLower(targetsFormat)
+	if targetsFormat != TargetsFormatNmap && targetsFormat != TargetsFormatMasscan {
+		return fmt.Errorf("the scan target format %s is not supported", targetsFormat)
+	}
+
+	groups := make(map[string]*scanTargetGroup)
+	for _, o := range output {
+		for _, a := range o.Addresses {
+			if a.Address == nil {
+				continue
+			}
+
+			grp, found := groups[a.CIDRStr]
+			if !found {
+				grp = &scanTargetGroup{
+					cidr:  a.CIDRStr,
+					asn:   a.ASN,
+					desc:  a.Description,
+					addrs: make(map[string]net.IP),
+					names: make(map[string]struct{}),
+				}
+				groups[a.CIDRStr] = grp
+			}
+
+			grp.addrs[a.Address.String()] = a.Address
+			grp.names[o.Name] = struct{}{}
+		}
+	}
+
+	var cidrs []string
+	for cidr := range groups {
+		cidrs = append(cidrs, cidr)
+	}
+	// The addresses without a known netblock are listed last
+	sort.Slice(cidrs, func(i, j int) bool {
+		if cidrs[i] == "" || cidrs[j] == "" {
+			return cidrs[j] == ""
+		}
+		return cidrs[i] < cidrs[j]
+	})
+
+	bw := bufio.NewWriter(w)
+	for i, cidr := range cidrs {
+		grp := groups[cidr]
+
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		writeScanTargetHeader(bw, grp)
+
+		var ips []net.IP
+		for _, ip := range grp.addrs {
+			ips = append(ips, ip)
+		}
+		sort.Slice(ips, func(i, j int) bool {
+			return bytes.Compare(ips[i].To16(), ips[j].To16()) < 0
+		})
+		for _, ip := range ips {
+			fmt.Fprintln(bw, ip.String())
+		}
+
+		if targetsFormat == TargetsFormatNmap {
+			var names []string
+			for name := range grp.names {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				fmt.Fprintln(bw, name)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+func writeScanTargetHeader(w io.Writer, grp *scanTargetGroup) {
+	if grp.cidr == "" {
+		fmt.Fprintln(w, "# Unknown netblock")
+		return
+	}
+
+	header := "# " + grp.cidr
+	if grp.asn != 0 {
+		header += " AS" + strconv.Itoa(grp.asn)
+	}
+	if desc := strings.TrimSpace(grp.desc); desc != "" {
+		header += " " + strings.NewReplacer("\r", " ", "\n", " ").Replace(desc)
+	}
+	fmt.Fprintln(w, header)
+}
+
+// NmapHost is a host found by an nmap or masscan scan, along with its open ports.
+type NmapHost struct {
+	Address   string
+	Hostnames []string
+	Ports     []NmapPort
+}
+
+// NmapPort is an open port found by an nmap or masscan scan.
+type NmapPort struct {
+	Protocol string
+	Port     int
+	Service  string
+	Product  string
+	Version  string
+}
+
+// String returns the port as "443/tcp https nginx 1.18", omitting the service details not detected.
+func (p NmapPort) String() string {
+	parts := []string{strconv.Itoa(p.Port) + "/" + p.Protocol}
+
+	for _, s := range []string{p.Service, p.Product, p.Version} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+type nmapRun struct {
+	XMLName xml.Name `xml:"nmaprun"`
+	Hosts   []struct {
+		Status struct {
+			State string `xml:"state,attr"`
+		} `xml:"status"`
+		Addresses []struct {
+			Addr     string `xml:"addr,attr"`
+			AddrType string `xml:"addrtype,attr"`
+		} `xml:"address"`
+		Hostnames []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"hostnames>hostname"`
+		Ports []struct {
+			Protocol string `xml:"protocol,attr"`
+			PortID   int    `xml:"portid,attr"`
+			State    struct {
+				State string `xml:"state,attr"`
+			} `xml:"state"`
+			Service struct {
+				Name    string `xml:"name,attr"`
+				Product string `xml:"product,attr"`
+				Version string `xml:"version,attr"`
+			} `xml:"service"`
+		} `xml:"ports>port"`
+	} `xml:"host"`
+}
+
+// ParseNmapXML returns the hosts and open ports from the XML output of nmap (-oX) or masscan (-oX).
+// The hosts that were not up are omitted, but the hosts without open ports are returned.
+func ParseNmapXML(r io.Reader) ([]*NmapHost, error) {
+	var run nmapRun
+
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return nil, fmt.Errorf("failed to parse the nmap XML: %v", err)
+	}
+
+	var hosts []*NmapHost
+	for _, h := range run.Hosts {
+		// Masscan does not provide the host status
+		if h.Status.State != "" && h.Status.State != "up" {
+			continue
+		}
+
+		host := new(NmapHost)
+		for _, a := range h.Addresses {
+			if a.AddrType == "ipv4" || a.AddrType == "ipv6" {
+				host.Address = a.Addr
+				break
+			}
+		}
+		if net.ParseIP(host.Address) == nil {
+			continue
+		}
+
+		for _, n := range h.Hostnames {
+			if n.Name != "" {
+				host.Hostnames = append(host.Hostnames, strings.ToLower(n.Name))
+			}
+		}
+		for _, p := range h.Ports {
+			if p.State.State != "open" {
+				continue
+			}
+
+			host.Ports = append(host.Ports, NmapPort{
+				Protocol: p.Protocol,
+				Port:     p.PortID,
+				Service:  p.Service.Name,
+				Product:  p.Service.Product,
+				Version:  p.Service.Version,
+			})
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
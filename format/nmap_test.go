@@ -0,0 +1,94 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestWriteScanTargets(t *testing.T) {
+	output := []*requests.Output{
+		{
+			Name: "www.owasp.org",
+			Addresses: []requests.AddressInfo{
+				{Address: net.ParseIP("192.168.1.20"), CIDRStr: "192.168.1.0/24", ASN: 26808, Description: "OWASP"},
+			},
+		},
+		{
+			Name: "mail.owasp.org",
+			Addresses: []requests.AddressInfo{
+				{Address: net.ParseIP("192.168.1.3"), CIDRStr: "192.168.1.0/24", ASN: 26808, Description: "OWASP"},
+				{Address: net.ParseIP("10.0.0.1")},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteScanTargets(&buf, output, TargetsFormatNmap); err != nil {
+		t.Fatalf("Failed to write the nmap targets: %v", err)
+	}
+	expected := "# 192.168.1.0/24 AS26808 OWASP\n192.168.1.3\n192.168.1.20\nmail.owasp.org\nwww.owasp.org\n\n" +
+		"# Unknown netblock\n10.0.0.1\nmail.owasp.org\n"
+	if buf.String() != expected {
+		t.Errorf("The nmap targets were:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if err := WriteScanTargets(&buf, output, TargetsFormatMasscan); err != nil {
+		t.Fatalf("Failed to write the masscan targets: %v", err)
+	}
+	if strings.Contains(buf.String(), "owasp.org\n") {
+		t.Errorf("The masscan targets included names:\n%s", buf.String())
+	}
+
+	if err := WriteScanTargets(&buf, output, "zmap"); err == nil {
+		t.Errorf("An unsupported format was accepted")
+	}
+}
+
+func TestParseNmapXML(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<nmaprun scanner="nmap">
+  <host>
+    <status state="up"/>
+    <address addr="192.168.1.20" addrtype="ipv4"/>
+    <address addr="00:11:22:33:44:55" addrtype="mac"/>
+    <hostnames><hostname name="WWW.owasp.org" type="user"/></hostnames>
+    <ports>
+      <port protocol="tcp" portid="22"><state state="closed"/><service name="ssh"/></port>
+      <port protocol="tcp" portid="443"><state state="open"/><service name="https" product="nginx" version="1.18"/></port>
+    </ports>
+  </host>
+  <host>
+    <status state="down"/>
+    <address addr="192.168.1.21" addrtype="ipv4"/>
+  </host>
+</nmaprun>`
+
+	hosts, err := ParseNmapXML(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Failed to parse the nmap XML: %v", err)
+	}
+	if len(hosts) != 1 {
+		t.Fatalf("%d hosts were returned, expected 1", len(hosts))
+	}
+
+	h := hosts[0]
+	if h.Address != "192.168.1.20" || len(h.Hostnames) != 1 || h.Hostnames[0] != "www.owasp.org" {
+		t.Errorf("The host was parsed as %+v", h)
+	}
+	if len(h.Ports) != 1 || h.Ports[0].String() != "443/tcp https nginx 1.18" {
+		t.Errorf("The open ports were parsed as %v", h.Ports)
+	}
+
+	if _, err := ParseNmapXML(strings.NewReader("not xml")); err == nil {
+		t.Errorf("The invalid XML was accepted")
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"os"
+	"strings"
+)
+
+// RotateFile renames the output file to the path with the .1 suffix, replacing the file rotated
+// previously. The rename is atomic, so the previous output remains available in one of the files
+// when the program is interrupted. Missing and empty files are not rotated.
+func RotateFile(path string) error {
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		return nil
+	}
+	return os.Rename(path, path+".1")
+}
+
+// RecoverLines returns the complete lines of the output file, after removing the incomplete line
+// left at the end by an interrupted write. A missing file provides no lines.
+func RecoverLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	end := bytes.LastIndexByte(data, '\n') + 1
+	if end < len(data) {
+		if err := os.Truncate(path, int64(end)); err != nil {
+			return nil, err
+		}
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data[:end]), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,47 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecoverLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outfile")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "amass.json")
+	if lines, err := RecoverLines(path); err != nil || len(lines) != 0 {
+		t.Errorf("The missing file returned %v: %v", lines, err)
+	}
+
+	complete := "{\"name\":\"www.owasp.org\"}\n{\"name\":\"api.owasp.org\"}\n"
+	if err := ioutil.WriteFile(path, []byte(complete+"{\"name\":\"ma"), 0644); err != nil {
+		t.Fatalf("Failed to write the output file: %v", err)
+	}
+	lines, err := RecoverLines(path)
+	if err != nil || len(lines) != 2 || lines[1] != "{\"name\":\"api.owasp.org\"}" {
+		t.Fatalf("The complete lines were not recovered: %v: %v", lines, err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != complete {
+		t.Errorf("The incomplete line was not removed: %q", data)
+	}
+
+	if err := RotateFile(path); err != nil {
+		t.Fatalf("Failed to rotate the output file: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("The output file remained after the rotation")
+	}
+	if data, _ := ioutil.ReadFile(path + ".1"); string(data) != complete {
+		t.Errorf("The rotated file contains %q", data)
+	}
+}
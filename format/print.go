@@ -10,6 +10,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/OWASP/Amass/v3/requests"
@@ -146,6 +147,37 @@ func FprintEnumerationSummary(out io.Writer, total int, tags map[string]int, asn
 	}
 }
 
+// PrintSourceStats outputs the data source statistics table to stderr.
+func PrintSourceStats(stats []*requests.SourceStats) {
+	FprintSourceStats(color.Error, stats)
+}
+
+// FprintSourceStats outputs the table of counters collected for each data source.
+func FprintSourceStats(out io.Writer, stats []*requests.SourceStats) {
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out)
+	b.Fprintf(out, "%-24s%10s%10s%10s%10s%10s%16s\n", "Data Source", "Requests", "Names", "Unique", "Resolved", "Errors", "Rate Limited")
+	for i := 0; i < 9; i++ {
+		b.Fprint(out, "----------")
+	}
+	fmt.Fprintln(out)
+
+	for _, s := range stats {
+		fmt.Fprintf(out, "%s%s%s%s%s%s%s\n",
+			green(fmt.Sprintf("%-24s", s.Source)),
+			yellow(fmt.Sprintf("%10d", s.Requests)),
+			yellow(fmt.Sprintf("%10d", s.Names)),
+			yellow(fmt.Sprintf("%10d", s.UniqueNames)),
+			yellow(fmt.Sprintf("%10d", s.Resolved)),
+			yellow(fmt.Sprintf("%10d", s.Errors)),
+			yellow(fmt.Sprintf("%16s", s.RateLimitWait.Round(time.Second))),
+		)
+	}
+}
+
 // PrintBanner outputs the Amass banner to stderr.
 func PrintBanner() {
 	FprintBanner(color.Error)
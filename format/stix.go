@@ -0,0 +1,140 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/google/uuid"
+)
+
+// The STIX 2.1 namespace used to generate the deterministic identifiers of cyber-observable objects.
+var stixNamespace = uuid.MustParse("00abedb4-aa42-466c-9c01-fed23315a9b7")
+
+const stixSpecVersion = "2.1"
+
+// STIXBundle is a STIX 2.1 bundle containing the objects and relationships of the findings.
+type STIXBundle struct {
+	Type    string        `json:"type"`
+	ID      string        `json:"id"`
+	Objects []interface{} `json:"objects"`
+}
+
+// STIXObservable is a STIX 2.1 domain-name, ipv4-addr, ipv6-addr or autonomous-system object.
+type STIXObservable struct {
+	Type        string `json:"type"`
+	SpecVersion string `json:"spec_version"`
+	ID          string `json:"id"`
+	Value       string `json:"value,omitempty"`
+	Number      int    `json:"number,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// STIXRelationship is a STIX 2.1 relationship object linking two of the observables.
+type STIXRelationship struct {
+	Type             string `json:"type"`
+	SpecVersion      string `json:"spec_version"`
+	ID               string `json:"id"`
+	Created          string `json:"created"`
+	Modified         string `json:"modified"`
+	RelationshipType string `json:"relationship_type"`
+	SourceRef        string `json:"source_ref"`
+	TargetRef        string `json:"target_ref"`
+}
+
+// STIXOutput converts the findings into a STIX 2.1 bundle. The names are linked to their addresses by
+// resolves-to relationships, and the addresses are linked to their autonomous systems by belongs-to
+// relationships. The relationships are created at the provided time.
+func STIXOutput(output []*requests.Output, created time.Time) *STIXBundle {
+	b := &STIXBundle{
+		Type: "bundle",
+		ID:   "bundle--" + uuid.New().String(),
+	}
+
+	ts := created.UTC().Format("2006-01-02T15:04:05.000Z")
+	seen := make(map[string]struct{})
+	add := func(id string, obj interface{}) {
+		if _, found := seen[id]; !found {
+			seen[id] = struct{}{}
+			b.Objects = append(b.Objects, obj)
+		}
+	}
+	relate := func(rtype, source, target string) {
+		// The identifier is derived from the objects, so the relationship is not repeated across exports
+		id := "relationship--" + uuid.NewSHA1(stixNamespace, []byte(rtype+source+target)).String()
+
+		add(id, &STIXRelationship{
+			Type:             "relationship",
+			SpecVersion:      stixSpecVersion,
+			ID:               id,
+			Created:          ts,
+			Modified:         ts,
+			RelationshipType: rtype,
+			SourceRef:        source,
+			TargetRef:        target,
+		})
+	}
+
+	for _, o := range output {
+		name := stixObservable("domain-name", o.Name)
+		add(name.ID, name)
+
+		for _, a := range o.Addresses {
+			if a.Address == nil {
+				continue
+			}
+
+			atype := "ipv4-addr"
+			if a.Address.To4() == nil {
+				atype = "ipv6-addr"
+			}
+			addr := stixObservable(atype, a.Address.String())
+			add(addr.ID, addr)
+			relate("resolves-to", name.ID, addr.ID)
+
+			if a.ASN == 0 {
+				continue
+			}
+			as := &STIXObservable{
+				Type:        "autonomous-system",
+				SpecVersion: stixSpecVersion,
+				ID:          stixID("autonomous-system", `{"number":`+strconv.Itoa(a.ASN)+`}`),
+				Number:      a.ASN,
+				Name:        a.Description,
+			}
+			add(as.ID, as)
+			relate("belongs-to", addr.ID, as.ID)
+		}
+	}
+	return b
+}
+
+func stixObservable(otype, value string) *STIXObservable {
+	v, _ := json.Marshal(map[string]string{"value": value})
+
+	return &STIXObservable{
+		Type:        otype,
+		SpecVersion: stixSpecVersion,
+		ID:          stixID(otype, string(v)),
+		Value:       value,
+	}
+}
+
+// Returns the deterministic identifier generated from the canonical JSON of the ID contributing properties.
+func stixID(otype, contrib string) string {
+	return otype + "--" + uuid.NewSHA1(stixNamespace, []byte(contrib)).String()
+}
+
+// WriteSTIXJSON writes the STIX 2.1 bundle to the writer as a JSON document.
+func WriteSTIXJSON(w io.Writer, b *STIXBundle) error {
+	enc := json.NewEncoder(w)
+
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
@@ -0,0 +1,72 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestSTIXOutput(t *testing.T) {
+	output := []*requests.Output{
+		{
+			Name:   "www.owasp.org",
+			Domain: "owasp.org",
+			Addresses: []requests.AddressInfo{
+				{Address: net.ParseIP("198.51.100.3"), ASN: 26808, Description: "OWASP"},
+				{Address: net.ParseIP("2001:db8::1")},
+			},
+		},
+		{
+			Name:      "mail.owasp.org",
+			Domain:    "owasp.org",
+			Addresses: []requests.AddressInfo{{Address: net.ParseIP("198.51.100.3"), ASN: 26808}},
+		},
+	}
+
+	b := STIXOutput(output, time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC))
+	if b.Type != "bundle" {
+		t.Errorf("The bundle type was %s", b.Type)
+	}
+
+	counts := make(map[string]int)
+	ids := make(map[string]bool)
+	for _, obj := range b.Objects {
+		switch v := obj.(type) {
+		case *STIXObservable:
+			counts[v.Type]++
+			ids[v.ID] = true
+		case *STIXRelationship:
+			counts[v.RelationshipType]++
+			if !ids[v.SourceRef] || !ids[v.TargetRef] {
+				t.Errorf("The relationship %s references objects not in the bundle", v.ID)
+			}
+			if v.Created != "2022-04-01T12:00:00.000Z" {
+				t.Errorf("The relationship was created at %s", v.Created)
+			}
+		}
+	}
+
+	expected := map[string]int{
+		"domain-name":       2,
+		"ipv4-addr":         1,
+		"ipv6-addr":         1,
+		"autonomous-system": 1,
+		"resolves-to":       3,
+		"belongs-to":        1,
+	}
+	for k, n := range expected {
+		if counts[k] != n {
+			t.Errorf("The bundle contained %d %s objects, expected %d", counts[k], k, n)
+		}
+	}
+	// The identifiers of the observables are derived from their values
+	if a, b := stixObservable("ipv4-addr", "198.51.100.3").ID, stixObservable("ipv4-addr", "198.51.100.3").ID; a != b {
+		t.Errorf("The identifiers %s and %s were not deterministic", a, b)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// CMDB creates or updates the assets of a generic REST endpoint, such as a configuration management database.
+// Each finding is sent as the JSON output of Amass using a PUT request to the URL followed by the FQDN.
+type CMDB struct {
+	base    string
+	headers map[string]string
+}
+
+// NewCMDB returns the integration with the REST endpoint in the settings.
+func NewCMDB(in *config.Integration) *CMDB {
+	c := &CMDB{
+		base:    strings.TrimSuffix(in.URL, "/") + "/",
+		headers: make(map[string]string),
+	}
+
+	if in.Key != "" {
+		c.headers["Authorization"] = "Bearer " + in.Key
+	}
+	return c
+}
+
+// String implements the Stringer interface.
+func (c *CMDB) String() string {
+	return "CMDB"
+}
+
+// Push implements the Integration interface.
+func (c *CMDB) Push(ctx context.Context, output []*requests.Output) (int, error) {
+	var num int
+
+	for _, o := range output {
+		if err := doJSON(ctx, http.MethodPut, c.base+url.PathEscape(o.Name), c.headers, o, nil); err != nil {
+			return num, err
+		}
+		num++
+	}
+	return num, nil
+}
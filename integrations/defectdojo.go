@@ -0,0 +1,111 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/stringset"
+)
+
+// DefectDojo creates or updates the endpoints of a DefectDojo product for the discovered names.
+type DefectDojo struct {
+	base    string
+	product int
+	headers map[string]string
+}
+
+type ddEndpoint struct {
+	ID      int      `json:"id,omitempty"`
+	Host    string   `json:"host"`
+	Product int      `json:"product"`
+	Tags    []string `json:"tags"`
+}
+
+type ddEndpointList struct {
+	Count   int           `json:"count"`
+	Results []*ddEndpoint `json:"results"`
+}
+
+// NewDefectDojo returns the integration with the DefectDojo server in the settings.
+func NewDefectDojo(in *config.Integration) *DefectDojo {
+	return &DefectDojo{
+		base:    strings.TrimSuffix(in.URL, "/") + "/api/v2/endpoints/",
+		product: in.ProductID,
+		headers: map[string]string{"Authorization": "Token " + in.Key},
+	}
+}
+
+// String implements the Stringer interface.
+func (d *DefectDojo) String() string {
+	return "DefectDojo"
+}
+
+// Push implements the Integration interface. The endpoints are keyed on the FQDN within the product.
+func (d *DefectDojo) Push(ctx context.Context, output []*requests.Output) (int, error) {
+	var num int
+
+	for _, o := range output {
+		tags := stringset.New("amass")
+		if o.Tag != "" {
+			tags.Insert(strings.ToLower(o.Tag))
+		}
+		tags.InsertMany(o.UserTags...)
+
+		existing, err := d.endpoint(ctx, o.Name)
+		if err != nil {
+			tags.Close()
+			return num, err
+		}
+
+		if existing != nil {
+			tags.InsertMany(existing.Tags...)
+			err = doJSON(ctx, http.MethodPatch, d.base+strconv.Itoa(existing.ID)+"/",
+				d.headers, map[string][]string{"tags": sortedTags(tags)}, nil)
+		} else {
+			err = doJSON(ctx, http.MethodPost, d.base, d.headers, &ddEndpoint{
+				Host:    o.Name,
+				Product: d.product,
+				Tags:    sortedTags(tags),
+			}, nil)
+		}
+		tags.Close()
+		if err != nil {
+			return num, err
+		}
+		num++
+	}
+	return num, nil
+}
+
+// Returns the endpoint of the product for the host, or nil when it does not exist.
+func (d *DefectDojo) endpoint(ctx context.Context, host string) (*ddEndpoint, error) {
+	q := url.Values{}
+	q.Set("host", host)
+	q.Set("product", strconv.Itoa(d.product))
+
+	var list ddEndpointList
+	if err := doJSON(ctx, http.MethodGet, d.base+"?"+q.Encode(), d.headers, nil, &list); err != nil {
+		return nil, err
+	}
+	if len(list.Results) == 0 {
+		return nil, nil
+	}
+	return list.Results[0], nil
+}
+
+func sortedTags(tags *stringset.Set) []string {
+	s := tags.Slice()
+
+	sort.Strings(s)
+	return s
+}
@@ -0,0 +1,149 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package integrations
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/format"
+)
+
+const emailTimeout = time.Minute
+
+// EmailChanges is the data provided to the subject and body templates of the email messages.
+type EmailChanges struct {
+	Domains     []string
+	Enumeration string
+	Added       int
+	Removed     int
+	Changed     int
+	Changes     []format.DiffChange
+}
+
+// Mailer sends the messages summarizing the changes to the recipients provided by the configuration.
+type Mailer struct {
+	cfg *config.Email
+}
+
+// NewMailer returns the Mailer for the email settings, or nil when they were not provided.
+func NewMailer(cfg *config.Config) *Mailer {
+	if cfg.Email == nil {
+		return nil
+	}
+	return &Mailer{cfg: cfg.Email}
+}
+
+// Send renders the message using the changes and delivers it through the SMTP server.
+func (m *Mailer) Send(changes *EmailChanges) error {
+	msg, err := m.message(changes, time.Now())
+	if err != nil {
+		return err
+	}
+
+	c, err := m.dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if m.cfg.Username != "" {
+		host, _, _ := net.SplitHostPort(m.cfg.Server)
+		if err := c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, host)); err != nil {
+			return fmt.Errorf("the SMTP authentication failed: %v", err)
+		}
+	}
+	if err := c.Mail(m.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range m.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("the recipient %s was rejected: %v", to, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Connects to the SMTP server using the TLS mode of the configuration.
+func (m *Mailer) dial() (*smtp.Client, error) {
+	host, _, _ := net.SplitHostPort(m.cfg.Server)
+	tlscfg := &tls.Config{ServerName: host}
+	d := &net.Dialer{Timeout: emailTimeout}
+
+	var conn net.Conn
+	var err error
+	if m.cfg.TLS == "tls" {
+		conn, err = tls.DialWithDialer(d, "tcp", m.cfg.Server, tlscfg)
+	} else {
+		conn, err = d.Dial("tcp", m.cfg.Server)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the SMTP server %s: %v", m.cfg.Server, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(emailTimeout))
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if m.cfg.TLS == "starttls" {
+		if err := c.StartTLS(tlscfg); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("the SMTP server %s failed to start TLS: %v", m.cfg.Server, err)
+		}
+	}
+	return c, nil
+}
+
+// Returns the headers and the quoted-printable body of the message.
+func (m *Mailer) message(changes *EmailChanges, now time.Time) ([]byte, error) {
+	subject, body, err := m.cfg.Render(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the email templates: %v", err)
+	}
+
+	var buf bytes.Buffer
+	for _, h := range [][2]string{
+		{"From", m.cfg.From},
+		{"To", strings.Join(m.cfg.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=utf-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	} {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h[0], h[1])
+	}
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
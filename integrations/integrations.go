@@ -0,0 +1,105 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	amasshttp "github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+const httpTimeout = 30 * time.Second
+
+// The client used to push the findings verifies the certificates presented by the servers,
+// since the requests carry the credentials of the integrations.
+var client = &http.Client{
+	Timeout:   httpTimeout,
+	Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+}
+
+// Integration pushes the findings into another system.
+type Integration interface {
+	fmt.Stringer
+
+	// Push creates or updates the assets for the findings and returns the number pushed.
+	Push(ctx context.Context, output []*requests.Output) (int, error)
+}
+
+// NewIntegrations returns the integrations provided by the configuration.
+func NewIntegrations(cfg *config.Config) []Integration {
+	var results []Integration
+
+	for _, in := range cfg.Integrations {
+		switch in.System {
+		case "defectdojo":
+			results = append(results, NewDefectDojo(in))
+		case "cmdb":
+			results = append(results, NewCMDB(in))
+		case "splunk":
+			results = append(results, NewSplunk(in))
+		}
+	}
+	return results
+}
+
+// Sends the request with the JSON body and decodes the JSON response into the result, when provided.
+func doJSON(ctx context.Context, method, u string, headers map[string]string, body, result interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+
+		data, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+	return doRequest(ctx, method, u, headers, data, result)
+}
+
+// Sends the request with the encoded JSON body and decodes the JSON response into the result, when provided.
+func doRequest(ctx context.Context, method, u string, headers map[string]string, data []byte, result interface{}) error {
+	var r io.Reader
+	if data != nil {
+		r = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("User-Agent", amasshttp.UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, u, resp.Status, bytes.TrimSpace(msg))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,130 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+)
+
+// The name of the credential sets retrieved from the secrets backend.
+const secretsCredentialsName = "secrets"
+
+// LoadSecrets retrieves the credentials of the data sources providing the secret_path setting from the
+// secrets backend, so the keys of shared deployments are never stored on disk. The secret is a JSON object
+// holding the apikey, secret, username and password fields required by the data source.
+func LoadSecrets(ctx context.Context, cfg *config.Config) error {
+	if cfg.Secrets == nil {
+		return nil
+	}
+
+	for _, dsc := range cfg.DataSourceConfigs() {
+		if dsc.SecretPath == "" {
+			continue
+		}
+
+		var fields map[string]string
+		var err error
+		switch cfg.Secrets.Backend {
+		case "vault":
+			fields, err = readVaultSecret(ctx, cfg.Secrets, dsc.SecretPath)
+		case "aws":
+			fields, err = readAWSSecret(ctx, cfg.Secrets, dsc.SecretPath, time.Now())
+		default:
+			err = fmt.Errorf("the secrets backend %s is not supported", cfg.Secrets.Backend)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to retrieve the %s credentials from %s: %v", dsc.Name, dsc.SecretPath, err)
+		}
+
+		creds := &config.Credentials{Name: secretsCredentialsName}
+		for k, v := range fields {
+			switch strings.ToLower(k) {
+			case "apikey", "api_key", "key":
+				creds.Key = v
+			case "secret":
+				creds.Secret = v
+			case "username":
+				creds.Username = v
+			case "password":
+				creds.Password = v
+			}
+		}
+		if creds.Key == "" && creds.Secret == "" && creds.Username == "" && creds.Password == "" {
+			return fmt.Errorf("the %s secret does not provide the %s credentials", dsc.SecretPath, dsc.Name)
+		}
+		if err := dsc.AddCredentials(creds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reads the secret from the KV version 2 secrets engine of Vault.
+func readVaultSecret(ctx context.Context, s *config.Secrets, path string) (map[string]string, error) {
+	var resp struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+
+	u := strings.TrimSuffix(s.URL, "/") + "/v1/" + s.Mount + "/data/" + strings.Trim(path, "/")
+	if err := doRequest(ctx, http.MethodGet, u, map[string]string{"X-Vault-Token": s.Token}, nil, &resp); err != nil {
+		return nil, err
+	}
+	return secretFields(resp.Data.Data), nil
+}
+
+// Reads the secret string of the AWS Secrets Manager, which must hold a JSON object.
+func readAWSSecret(ctx context.Context, s *config.Secrets, id string, now time.Time) (map[string]string, error) {
+	target, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := json.Marshal(map[string]string{"SecretId": id})
+	headers := map[string]string{
+		"Content-Type": "application/x-amz-json-1.1",
+		"X-Amz-Target": "secretsmanager.GetSecretValue",
+	}
+	signAWSV4(&awsSigner{
+		Service:      "secretsmanager",
+		Region:       s.Region,
+		AccessKey:    s.AccessKey,
+		SecretKey:    s.SecretKey,
+		SessionToken: s.SessionToken,
+	}, http.MethodPost, target, headers, body, now)
+
+	var resp struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := doRequest(ctx, http.MethodPost, target.String(), headers, body, &resp); err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(resp.SecretString), &fields); err != nil {
+		return nil, fmt.Errorf("the secret string is not a JSON object: %v", err)
+	}
+	return secretFields(fields), nil
+}
+
+func secretFields(data map[string]interface{}) map[string]string {
+	fields := make(map[string]string, len(data))
+
+	for k, v := range data {
+		if s, ok := v.(string); ok {
+			fields[k] = s
+		}
+	}
+	return fields
+}
@@ -0,0 +1,167 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+const (
+	// The path of the HEC endpoint receiving the events in JSON
+	splunkEventPath = "/services/collector/event"
+	// The number of events sent in each request when the batch size is not configured
+	splunkDefaultBatchSize = 100
+)
+
+// Splunk sends the findings to the HTTP Event Collector (HEC) of Splunk. Each finding becomes an
+// event with the amass:asset source type, followed by an amass:summary event describing the findings.
+type Splunk struct {
+	endpoint  string
+	index     string
+	host      string
+	batchSize int
+	headers   map[string]string
+}
+
+type splunkEvent struct {
+	Time       float64     `json:"time"`
+	Host       string      `json:"host,omitempty"`
+	Source     string      `json:"source"`
+	SourceType string      `json:"sourcetype"`
+	Index      string      `json:"index,omitempty"`
+	Event      interface{} `json:"event"`
+}
+
+type splunkSummary struct {
+	Domains   []string `json:"domains"`
+	Names     int      `json:"names"`
+	Addresses int      `json:"addresses"`
+	Netblocks int      `json:"netblocks"`
+	ASNs      int      `json:"asns"`
+}
+
+// NewSplunk returns the integration with the HTTP Event Collector in the settings.
+// The URL can be the base URL of the collector or the complete URL of the event endpoint.
+func NewSplunk(in *config.Integration) *Splunk {
+	endpoint := strings.TrimSuffix(in.URL, "/")
+	if u, err := url.Parse(endpoint); err == nil && (u.Path == "" || u.Path == "/") {
+		endpoint += splunkEventPath
+	}
+
+	host, _ := os.Hostname()
+	s := &Splunk{
+		endpoint:  endpoint,
+		index:     in.Index,
+		host:      host,
+		batchSize: in.BatchSize,
+		headers:   map[string]string{"Authorization": "Splunk " + in.Key},
+	}
+
+	if s.batchSize <= 0 {
+		s.batchSize = splunkDefaultBatchSize
+	}
+	return s
+}
+
+// String implements the Stringer interface.
+func (s *Splunk) String() string {
+	return "Splunk"
+}
+
+// Push implements the Integration interface.
+func (s *Splunk) Push(ctx context.Context, output []*requests.Output) (int, error) {
+	var num int
+	now := float64(time.Now().UnixNano()) / float64(time.Second)
+
+	for start := 0; start < len(output); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(output) {
+			end = len(output)
+		}
+
+		var events []*splunkEvent
+		for _, o := range output[start:end] {
+			events = append(events, s.event(now, "amass:asset", o))
+		}
+		if err := s.send(ctx, events); err != nil {
+			return num, err
+		}
+		num += len(events)
+	}
+
+	return num, s.send(ctx, []*splunkEvent{s.event(now, "amass:summary", splunkSummaryOf(output))})
+}
+
+func (s *Splunk) event(t float64, sourcetype string, data interface{}) *splunkEvent {
+	return &splunkEvent{
+		Time:       t,
+		Host:       s.host,
+		Source:     "amass",
+		SourceType: sourcetype,
+		Index:      s.index,
+		Event:      data,
+	}
+}
+
+// The collector accepts several events in the same request, each as a separate JSON object.
+func (s *Splunk) send(ctx context.Context, events []*splunkEvent) error {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return doRequest(ctx, http.MethodPost, s.endpoint, s.headers, buf.Bytes(), nil)
+}
+
+func splunkSummaryOf(output []*requests.Output) *splunkSummary {
+	domains := make(map[string]struct{})
+	addrs := make(map[string]struct{})
+	netblocks := make(map[string]struct{})
+	asns := make(map[int]struct{})
+
+	for _, o := range output {
+		if o.Domain != "" {
+			domains[o.Domain] = struct{}{}
+		}
+		for _, a := range o.Addresses {
+			if a.Address != nil {
+				addrs[a.Address.String()] = struct{}{}
+			}
+			if a.CIDRStr != "" {
+				netblocks[a.CIDRStr] = struct{}{}
+			}
+			if a.ASN != 0 {
+				asns[a.ASN] = struct{}{}
+			}
+		}
+	}
+
+	summary := &splunkSummary{
+		Domains:   []string{},
+		Names:     len(output),
+		Addresses: len(addrs),
+		Netblocks: len(netblocks),
+		ASNs:      len(asns),
+	}
+	for d := range domains {
+		summary.Domains = append(summary.Domains, d)
+	}
+	sort.Strings(summary.Domains)
+	return summary
+}
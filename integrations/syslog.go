@@ -0,0 +1,131 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package integrations
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+const (
+	// The messages are sent using the local0 facility and the informational severity.
+	syslogPriority = 16*8 + 6
+	// The time permitted to establish the connection and to send each message.
+	syslogTimeout = 10 * time.Second
+)
+
+// Syslog sends the findings to a syslog receiver, such as a SIEM, as CEF or LEEF messages.
+// The messages follow RFC 5424 and are framed by a newline when sent over TCP or TLS.
+type Syslog struct {
+	network  string
+	addr     string
+	format   string
+	hostname string
+	conn     net.Conn
+}
+
+// NewSyslog returns the Syslog connected to the receiver identified by the URL, such as
+// udp://siem.example.com:514, tcp://siem.example.com:514 or tls://siem.example.com:6514.
+func NewSyslog(rawurl, siemFormat string) (*Syslog, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("the syslog URL %s is not valid", rawurl)
+	}
+
+	network := strings.ToLower(u.Scheme)
+	addr := u.Host
+	if u.Port() == "" {
+		port := "514"
+		if network == "tls" {
+			port = "6514"
+		}
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	switch network {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("the syslog URL %s must use the udp, tcp or tls scheme", rawurl)
+	}
+	if _, err := format.SIEMMessage(siemFormat, &requests.Output{}); err != nil {
+		return nil, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	s := &Syslog{
+		network:  network,
+		addr:     addr,
+		format:   siemFormat,
+		hostname: hostname,
+	}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write sends the finding to the syslog receiver.
+func (s *Syslog) Write(o *requests.Output) error {
+	msg, err := format.SIEMMessage(s.format, o)
+	if err != nil {
+		return err
+	}
+
+	line := fmt.Sprintf("<%d>1 %s %s amass - - - %s", syslogPriority,
+		time.Now().UTC().Format(time.RFC3339), s.hostname, msg)
+	if s.network != "udp" {
+		line += "\n"
+	}
+
+	if err = s.send(line); err != nil && s.network != "udp" {
+		// The receiver could have closed the connection, so one attempt is made to connect again
+		_ = s.conn.Close()
+		if err = s.connect(); err == nil {
+			err = s.send(line)
+		}
+	}
+	return err
+}
+
+// Close closes the connection to the syslog receiver.
+func (s *Syslog) Close() error {
+	return s.conn.Close()
+}
+
+func (s *Syslog) connect() error {
+	var err error
+	dialer := &net.Dialer{Timeout: syslogTimeout}
+
+	if s.network == "tls" {
+		s.conn, err = tls.DialWithDialer(dialer, "tcp", s.addr, &tls.Config{MinVersion: tls.VersionTLS12})
+	} else {
+		s.conn, err = dialer.Dial(s.network, s.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to the syslog receiver at %s: %v", s.addr, err)
+	}
+	return nil
+}
+
+func (s *Syslog) send(line string) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(syslogTimeout)); err != nil {
+		return err
+	}
+
+	_, err := s.conn.Write([]byte(line))
+	return err
+}
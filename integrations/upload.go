@@ -0,0 +1,192 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package integrations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+)
+
+// The characters of the domain names and file names that are replaced in the object keys.
+var uploadKeyReplacer = strings.NewReplacer("/", "_", "\\", "_", " ", "_")
+
+// Uploader writes the output files to an object storage bucket, such as Amazon S3, Google Cloud
+// Storage or Azure Blob Storage. The S3 and GCS uploads are authorized by AWS Signature Version 4,
+// using the HMAC keys of GCS, and the Azure uploads by the shared access signature of the container.
+type Uploader struct {
+	cfg *config.Upload
+	now func() time.Time
+}
+
+// NewUploader returns the Uploader writing to the object storage in the settings.
+func NewUploader(cfg *config.Upload) *Uploader {
+	return &Uploader{cfg: cfg, now: time.Now}
+}
+
+// String implements the Stringer interface.
+func (u *Uploader) String() string {
+	return u.cfg.Service + "://" + u.cfg.Bucket
+}
+
+// ObjectKey returns the key of the file expanded from the key template of the settings. The domains
+// of the enumeration are joined by underscores, and the date and time are the start of the enumeration.
+func (u *Uploader) ObjectKey(domains []string, uuid string, start time.Time, file string) string {
+	domain := "all"
+	if len(domains) > 0 {
+		domain = strings.Join(domains, "_")
+	}
+
+	start = start.UTC()
+	return strings.TrimPrefix(strings.NewReplacer(
+		"{domain}", uploadKeyReplacer.Replace(domain),
+		"{date}", start.Format("2006-01-02"),
+		"{time}", start.Format("150405"),
+		"{uuid}", uuid,
+		"{file}", uploadKeyReplacer.Replace(path.Base(file)),
+	).Replace(u.cfg.Key), "/")
+}
+
+// Upload writes the data to the object identified by the key.
+func (u *Uploader) Upload(ctx context.Context, key, contentType string, data []byte) error {
+	headers := map[string]string{"Content-Type": contentType}
+
+	if u.cfg.Service == "azure" {
+		headers["x-ms-blob-type"] = "BlockBlob"
+		headers["x-ms-version"] = "2020-04-08"
+
+		target := strings.TrimSuffix(u.cfg.Endpoint, "/") + "/" + u.cfg.Bucket + "/" +
+			uriEncode(key) + "?" + strings.TrimPrefix(u.cfg.SASToken, "?")
+		return doRequest(ctx, http.MethodPut, target, headers, data, nil)
+	}
+
+	target := u.s3ObjectURL(key)
+	u.signV4(target, headers, data)
+	return doRequest(ctx, http.MethodPut, target.String(), headers, data, nil)
+}
+
+// Returns the path-style URL of the object, which is supported by AWS, GCS and the S3 compatible services.
+func (u *Uploader) s3ObjectURL(key string) *url.URL {
+	endpoint := u.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + u.cfg.Region + ".amazonaws.com"
+		if u.cfg.Service == "gs" {
+			endpoint = "https://storage.googleapis.com"
+		}
+	}
+
+	target, _ := url.Parse(endpoint)
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + u.cfg.Bucket + "/" + key
+	target.RawPath = uriEncode(target.Path)
+	return target
+}
+
+// Adds the AWS Signature Version 4 headers authorizing the PUT request.
+func (u *Uploader) signV4(target *url.URL, headers map[string]string, data []byte) {
+	signAWSV4(&awsSigner{
+		Service:   "s3",
+		Region:    u.cfg.Region,
+		AccessKey: u.cfg.AccessKey,
+		SecretKey: u.cfg.SecretKey,
+	}, http.MethodPut, target, headers, data, u.now())
+}
+
+// The credentials and the service signing the requests sent to AWS.
+type awsSigner struct {
+	Service      string
+	Region       string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// Adds the AWS Signature Version 4 headers authorizing the request without a query string.
+func signAWSV4(s *awsSigner, method string, target *url.URL, headers map[string]string, data []byte, now time.Time) {
+	t := now.UTC()
+	amzdate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	payload := sha256.Sum256(data)
+
+	headers["Host"] = target.Host
+	headers["x-amz-date"] = amzdate
+	headers["x-amz-content-sha256"] = hex.EncodeToString(payload[:])
+	if s.SessionToken != "" {
+		headers["x-amz-security-token"] = s.SessionToken
+	}
+
+	var names []string
+	canonical := make(map[string]string)
+	for k, v := range headers {
+		name := strings.ToLower(k)
+		names = append(names, name)
+		canonical[name] = strings.TrimSpace(v)
+	}
+	sort.Strings(names)
+
+	var hdrs strings.Builder
+	for _, name := range names {
+		hdrs.WriteString(name + ":" + canonical[name] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	req := strings.Join([]string{
+		method,
+		path,
+		"",
+		hdrs.String(),
+		signed,
+		headers["x-amz-content-sha256"],
+	}, "\n")
+	reqHash := sha256.Sum256([]byte(req))
+
+	scope := date + "/" + s.Region + "/" + s.Service + "/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzdate + "\n" + scope + "\n" + hex.EncodeToString(reqHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	for _, part := range []string{s.Region, s.Service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	headers["Authorization"] = fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signed, hex.EncodeToString(hmacSHA256(key, toSign)))
+	// The Host header is provided by the request
+	delete(headers, "Host")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// Encodes all the characters of the path except the slashes and the unreserved characters of RFC 3986.
+func uriEncode(s string) string {
+	var b strings.Builder
+
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
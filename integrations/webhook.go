@@ -0,0 +1,241 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package integrations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+)
+
+const (
+	// WebhookSignatureHeader provides the HMAC-SHA256 of the payload when the webhook has a secret.
+	WebhookSignatureHeader = "X-Amass-Signature"
+
+	// The assets waiting in the batch are delivered after this interval, even when the batch is not full.
+	webhookFlushInterval = 10 * time.Second
+	// The first delay before a failed delivery is attempted again, which doubles with each attempt.
+	webhookRetryDelay = time.Second
+	// The assets listed in each chat message, since the services limit the length of the messages.
+	chatMaxAssets = 25
+	// Discord rejects messages longer than this number of characters.
+	discordMaxLength = 2000
+)
+
+// WebhookAsset is a new asset carried by the webhook payloads.
+type WebhookAsset struct {
+	// The asset type, which can be fqdn, address or netblock
+	Type  string `json:"type"`
+	Asset string `json:"asset"`
+	// The name that led to the discovery of the address or netblock
+	Name      string   `json:"name,omitempty"`
+	Domain    string   `json:"domain,omitempty"`
+	Sources   []string `json:"sources,omitempty"`
+	EventID   string   `json:"event_id"`
+	Timestamp string   `json:"timestamp"`
+}
+
+type webhookPayload struct {
+	Webhook string          `json:"webhook"`
+	Assets  []*WebhookAsset `json:"assets"`
+}
+
+// Webhook delivers the new assets to the endpoint in batches, attempting the failed deliveries again.
+// The webhooks using the chat formats post the assets as alerts to Slack, Discord or Microsoft Teams.
+type Webhook struct {
+	cfg   *config.Webhook
+	queue chan *WebhookAsset
+	done  chan struct{}
+	lock  sync.Mutex
+	errs  []error
+}
+
+// NewWebhook returns the Webhook that delivers the assets to the configured endpoint.
+func NewWebhook(cfg *config.Webhook) *Webhook {
+	w := &Webhook{
+		cfg:   cfg,
+		queue: make(chan *WebhookAsset, cfg.BatchSize),
+		done:  make(chan struct{}),
+	}
+
+	go w.deliver()
+	return w
+}
+
+// NewWebhooks returns the webhooks provided by the configuration.
+func NewWebhooks(cfg *config.Config) []*Webhook {
+	var results []*Webhook
+
+	for _, wh := range cfg.Webhooks {
+		results = append(results, NewWebhook(wh))
+	}
+	return results
+}
+
+// String implements the fmt.Stringer interface.
+func (w *Webhook) String() string {
+	return w.cfg.Name
+}
+
+// Notify adds the asset to the batch, when the asset type fires the webhook.
+func (w *Webhook) Notify(asset *WebhookAsset) {
+	if w.cfg.Fires(asset.Type) {
+		w.queue <- asset
+	}
+}
+
+// Close delivers the remaining assets and returns the errors of the deliveries that failed.
+func (w *Webhook) Close() []error {
+	close(w.queue)
+	<-w.done
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.errs
+}
+
+func (w *Webhook) deliver() {
+	defer close(w.done)
+
+	t := time.NewTicker(webhookFlushInterval)
+	defer t.Stop()
+
+	var batch []*WebhookAsset
+	flush := func() {
+		if len(batch) > 0 {
+			w.send(batch)
+			batch = nil
+		}
+	}
+
+	for {
+		select {
+		case asset, ok := <-w.queue:
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, asset)
+			if len(batch) >= w.cfg.BatchSize {
+				flush()
+			}
+		case <-t.C:
+			flush()
+		}
+	}
+}
+
+// Message posts the text to the webhooks using the chat formats, such as the summary of a run.
+// The webhooks using the JSON format only receive the assets, so the text is not delivered to them.
+func (w *Webhook) Message(text string) error {
+	if !w.cfg.Chat() {
+		return nil
+	}
+
+	data, err := chatPayload(w.cfg.Format, text)
+	if err != nil {
+		return err
+	}
+	return w.post(data, nil)
+}
+
+func (w *Webhook) send(batch []*WebhookAsset) {
+	var err error
+	var data []byte
+
+	if w.cfg.Chat() {
+		data, err = chatPayload(w.cfg.Format, assetsMessage(batch))
+	} else {
+		data, err = json.Marshal(&webhookPayload{Webhook: w.cfg.Name, Assets: batch})
+	}
+	if err != nil {
+		w.addError(err)
+		return
+	}
+
+	headers := make(map[string]string)
+	if w.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.cfg.Secret))
+		_, _ = mac.Write(data)
+		headers[WebhookSignatureHeader] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	if err := w.post(data, headers); err != nil {
+		w.addError(err)
+	}
+}
+
+// Posts the payload to the endpoint, attempting the failed deliveries again.
+func (w *Webhook) post(data []byte, headers map[string]string) error {
+	var err error
+
+	delay := webhookRetryDelay
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+		err = doRequest(ctx, http.MethodPost, w.cfg.URL, headers, data, nil)
+		cancel()
+
+		if err == nil || attempt >= w.cfg.Retries {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return err
+}
+
+func (w *Webhook) addError(err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.errs = append(w.errs, err)
+}
+
+// Returns the alert listing the new assets.
+func assetsMessage(batch []*WebhookAsset) string {
+	lines := []string{fmt.Sprintf("OWASP Amass discovered %d new assets:", len(batch))}
+
+	for i, a := range batch {
+		if i == chatMaxAssets {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(batch)-chatMaxAssets))
+			break
+		}
+
+		line := fmt.Sprintf("- %s %s", a.Type, a.Asset)
+		if a.Name != "" {
+			line += " (" + a.Name + ")"
+		}
+		if len(a.Sources) > 0 {
+			line += " via " + strings.Join(a.Sources, ", ")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns the body accepted by the incoming webhooks of the chat service.
+func chatPayload(format, text string) ([]byte, error) {
+	switch format {
+	case "discord":
+		if runes := []rune(text); len(runes) > discordMaxLength {
+			text = string(runes[:discordMaxLength-3]) + "..."
+		}
+		return json.Marshal(map[string]string{"content": text})
+	case "teams":
+		// Teams only renders the line breaks of separate paragraphs
+		return json.Marshal(map[string]string{"text": strings.ReplaceAll(text, "\n", "\n\n")})
+	}
+	return json.Marshal(map[string]string{"text": text})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/OWASP/Amass/v3/datasrcs"
 	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/resolve"
@@ -155,7 +156,7 @@ func (c *Collection) makeDNSTaskFunc() pipeline.TaskFunc {
 			ans := resolve.ExtractAnswers(resp)
 
 			if len(ans) > 0 {
-				d := strings.TrimSpace(resolve.FirstProperSubdomain(c.ctx, c.Sys.TrustedResolvers(), ans[0].Data))
+				d := strings.TrimSpace(resolvers.FirstProperSubdomain(c.ctx, c.Sys.TrustedResolvers(), ans[0].Data))
 
 				if d != "" {
 					go pipeline.SendData(ctx, "filter", &requests.Output{
@@ -180,7 +181,8 @@ func (c *Collection) makeFilterTaskFunc() pipeline.TaskFunc {
 		default:
 		}
 
-		if req, ok := data.(*requests.Output); ok && req != nil && !c.filter.TestAndAdd([]byte(req.Domain)) {
+		if req, ok := data.(*requests.Output); ok && req != nil &&
+			!c.Config.Blacklisted(req.Domain) && !c.filter.TestAndAdd([]byte(req.Domain)) {
 			return data, nil
 		}
 		return nil, nil
@@ -282,7 +284,8 @@ func (c *Collection) collect(req *requests.WhoisRequest) {
 	c.timeChan <- time.Now()
 
 	for _, name := range req.NewDomains {
-		if d, err := publicsuffix.EffectiveTLDPlusOne(name); err == nil && !c.filter.TestAndAdd([]byte(d)) {
+		if d, err := publicsuffix.EffectiveTLDPlusOne(name); err == nil &&
+			!c.Config.Blacklisted(d) && !c.filter.TestAndAdd([]byte(d)) {
 			c.Output <- &requests.Output{
 				Name:    d,
 				Domain:  d,
@@ -0,0 +1,126 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// The largest response body read while fingerprinting a web service.
+const maxFingerprintBody = 1 << 20
+
+// Fingerprint describes the web service responding at the URL.
+type Fingerprint struct {
+	URL          string
+	StatusCode   int
+	Server       string
+	Title        string
+	Technologies []string
+	// The URL the request was redirected to, when it differs from the URL requested
+	Redirect string
+}
+
+type techSignature struct {
+	Name    string
+	Header  string
+	Cookie  string
+	Pattern string
+}
+
+// The signatures identifying technologies using the response headers, cookies and body.
+var techSignatures = []techSignature{
+	{Name: "Nginx", Header: "Server", Pattern: "nginx"},
+	{Name: "Apache", Header: "Server", Pattern: "apache"},
+	{Name: "Microsoft IIS", Header: "Server", Pattern: "microsoft-iis"},
+	{Name: "Cloudflare", Header: "Server", Pattern: "cloudflare"},
+	{Name: "Amazon S3", Header: "Server", Pattern: "amazons3"},
+	{Name: "PHP", Header: "X-Powered-By", Pattern: "php"},
+	{Name: "ASP.NET", Header: "X-Powered-By", Pattern: "asp.net"},
+	{Name: "Express", Header: "X-Powered-By", Pattern: "express"},
+	{Name: "Next.js", Header: "X-Powered-By", Pattern: "next.js"},
+	{Name: "Varnish", Header: "Via", Pattern: "varnish"},
+	{Name: "PHP", Cookie: "PHPSESSID"},
+	{Name: "Java", Cookie: "JSESSIONID"},
+	{Name: "ASP.NET", Cookie: "ASP.NET_SessionId"},
+	{Name: "Laravel", Cookie: "laravel_session"},
+	{Name: "WordPress", Pattern: "/wp-content/"},
+	{Name: "Drupal", Pattern: "drupal.settings"},
+	{Name: "Joomla", Pattern: "content=\"joomla"},
+	{Name: "Shopify", Pattern: "cdn.shopify.com"},
+	{Name: "Next.js", Pattern: "__next_data__"},
+	{Name: "Angular", Pattern: "ng-version="},
+	{Name: "React", Pattern: "data-reactroot"},
+}
+
+// FingerprintService requests the URL and returns the status code, server header,
+// page title, redirect target and technologies detected in the response.
+func FingerprintService(ctx context.Context, u string) (*Fingerprint, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Close = true
+
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", Accept)
+	req.Header.Set("Accept-Language", AcceptLang)
+
+	resp, err := DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxFingerprintBody))
+	fp := &Fingerprint{
+		URL:        u,
+		StatusCode: resp.StatusCode,
+		Server:     resp.Header.Get("Server"),
+	}
+	if final := resp.Request.URL.String(); final != u {
+		fp.Redirect = final
+	}
+
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body))); err == nil {
+		fp.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	}
+
+	fp.Technologies = detectTechnologies(resp, strings.ToLower(string(body)))
+	return fp, nil
+}
+
+func detectTechnologies(resp *http.Response, body string) []string {
+	found := make(map[string]string)
+	for _, sig := range techSignatures {
+		switch {
+		case sig.Header != "":
+			if strings.Contains(strings.ToLower(resp.Header.Get(sig.Header)), sig.Pattern) {
+				found[strings.ToLower(sig.Name)] = sig.Name
+			}
+		case sig.Cookie != "":
+			for _, c := range resp.Cookies() {
+				if c.Name == sig.Cookie {
+					found[strings.ToLower(sig.Name)] = sig.Name
+				}
+			}
+		case strings.Contains(body, sig.Pattern):
+			found[strings.ToLower(sig.Name)] = sig.Name
+		}
+	}
+
+	var results []string
+	for _, name := range found {
+		results = append(results, name)
+	}
+
+	sort.Strings(results)
+	return results
+}
@@ -109,6 +109,15 @@ func CheckCookie(urlString string, cookieName string) bool {
 	return found
 }
 
+// The context key providing the timeout of the requests made by RequestWebPage.
+type timeoutKey struct{}
+
+// WithTimeout returns a context that makes RequestWebPage wait for the response during the timeout,
+// instead of the timeout of DefaultClient, so each data source can be given its own timeout.
+func WithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
 // RequestWebPage returns a string containing the entire response for the provided URL when successful.
 func RequestWebPage(ctx context.Context, u string, body io.Reader, hvals map[string]string, auth *BasicAuth) (string, error) {
 	method := "GET"
@@ -133,8 +142,15 @@ func RequestWebPage(ctx context.Context, u string, body io.Reader, hvals map[str
 		req.Header.Set(k, v)
 	}
 
+	client := DefaultClient
+	if timeout, ok := ctx.Value(timeoutKey{}).(time.Duration); ok && timeout > 0 {
+		c := *DefaultClient
+		c.Timeout = timeout
+		client = &c
+	}
+
 	var in string
-	resp, err := DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err == nil {
 		defer func() { _ = resp.Body.Close() }()
 
@@ -270,22 +286,37 @@ func whichDomain(name string, scope []string) string {
 // PullCertificateNames attempts to pull a cert from one or more ports on an IP.
 func PullCertificateNames(ctx context.Context, addr string, ports []int) []string {
 	var names []string
+	// Create the new requests from names found within the certs
+	for _, cert := range PullCertificates(ctx, addr, ports) {
+		names = append(names, namesFromCert(cert)...)
+	}
+	return names
+}
+
+// PullCertificates attempts to pull the leaf certificate from one or more ports on an IP.
+func PullCertificates(ctx context.Context, addr string, ports []int) []*x509.Certificate {
+	var certs []*x509.Certificate
 	// Check hosts for certificates that contain subdomain names
 	for _, port := range ports {
 		if c, err := TLSConn(ctx, addr, port); err == nil {
 			// Get the correct certificate in the chain
-			certChain := c.ConnectionState().PeerCertificates
-			// Create the new requests from names found within the cert
-			names = append(names, namesFromCert(certChain[0])...)
+			if certChain := c.ConnectionState().PeerCertificates; len(certChain) > 0 {
+				certs = append(certs, certChain[0])
+			}
 		}
 
 		select {
 		case <-ctx.Done():
-			return names
+			return certs
 		default:
 		}
 	}
-	return names
+	return certs
+}
+
+// CertificateNames returns the subdomain names found within the certificate.
+func CertificateNames(cert *x509.Certificate) []string {
+	return namesFromCert(cert)
 }
 
 // TLSConn attempts to make a TLS connection with the host on given port
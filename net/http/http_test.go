@@ -15,6 +15,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/caffix/resolve"
 	"github.com/caffix/stringset"
 	"github.com/miekg/dns"
@@ -136,6 +137,21 @@ func TestRequestWebPage(t *testing.T) {
 	}
 }
 
+func TestRequestWebPageTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, "Success")
+	}))
+	defer ts.Close()
+
+	if _, err := RequestWebPage(WithTimeout(context.Background(), 50*time.Millisecond), ts.URL, nil, nil, nil); err == nil {
+		t.Errorf("The request was not limited by the timeout")
+	}
+	if resp, err := RequestWebPage(WithTimeout(context.Background(), 5*time.Second), ts.URL, nil, nil, nil); err != nil || resp != "Success" {
+		t.Errorf("The request failed within the timeout: %v", err)
+	}
+}
+
 func TestCrawl(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -217,7 +233,7 @@ func TestCrawl(t *testing.T) {
 }
 
 func TestPullCertificateNames(t *testing.T) {
-	r := resolve.NewResolvers()
+	r := resolvers.NewPool()
 	if r == nil {
 		t.Errorf("Failed to setup the DNS resolver")
 	}
@@ -290,3 +306,46 @@ func TestCleanName(t *testing.T) {
 		}
 	}
 }
+
+func TestFingerprintService(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx/1.18.0")
+		w.Header().Set("X-Powered-By", "PHP/7.4.3")
+		http.SetCookie(w, &http.Cookie{Name: "PHPSESSID", Value: "abc"})
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `<html><head><title> OWASP Amass </title>
+			<link rel="stylesheet" href="/wp-content/themes/style.css"></head></html>`)
+	}))
+	defer ts.Close()
+
+	fp, err := FingerprintService(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("Failed to fingerprint the service: %v", err)
+	}
+	if fp.StatusCode != http.StatusForbidden || fp.Server != "nginx/1.18.0" || fp.Title != "OWASP Amass" {
+		t.Errorf("The response was not fingerprinted correctly: %+v", fp)
+	}
+	if techs := strings.Join(fp.Technologies, ","); techs != "Nginx,PHP,WordPress" {
+		t.Errorf("The technologies detected were %s, expected Nginx,PHP,WordPress", techs)
+	}
+}
+
+func TestFingerprintServiceRedirect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusFound)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Login</title></head></html>`)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	fp, err := FingerprintService(context.Background(), ts.URL+"/")
+	if err != nil {
+		t.Fatalf("Failed to fingerprint the service: %v", err)
+	}
+	if fp.Redirect != ts.URL+"/login" || fp.StatusCode != http.StatusOK || fp.Title != "Login" {
+		t.Errorf("The redirect target was not recorded: %+v", fp)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+// ConfidencePredicate is the property used to store the confidence of names and addresses in the graph.
+const ConfidencePredicate = "confidence"
+
+// Confidence levels assigned to the names and addresses discovered by the enumeration.
+const (
+	// ConfidenceGuess is assigned to generated names that were not resolved.
+	ConfidenceGuess = 25
+	// ConfidenceReported is assigned to assertions made by sources without being resolved.
+	ConfidenceReported = 50
+	// ConfidenceResolved is assigned to resolved assertions made by a single source or generated.
+	ConfidenceResolved = 75
+	// ConfidenceCorroborated is assigned to resolved assertions made by multiple sources.
+	ConfidenceCorroborated = 100
+)
+
+// GuessTag returns true when the tag identifies names generated by the enumeration.
+func GuessTag(tag string) bool {
+	return tag == ALT || tag == BRUTE || tag == GUESS
+}
+
+// Confidence returns the confidence in an assertion, based on whether it was confirmed by
+// resolving the name and the tags of the sources that made the assertion, one per source.
+func Confidence(resolved bool, tags []string) int {
+	var reported int
+
+	for _, tag := range tags {
+		if !GuessTag(tag) {
+			reported++
+		}
+	}
+
+	switch {
+	case resolved && reported > 1:
+		return ConfidenceCorroborated
+	case resolved:
+		return ConfidenceResolved
+	case reported > 0:
+		return ConfidenceReported
+	}
+	return ConfidenceGuess
+}
@@ -0,0 +1,30 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import "testing"
+
+func TestConfidence(t *testing.T) {
+	tests := []struct {
+		resolved bool
+		tags     []string
+		expected int
+	}{
+		{true, []string{API, CERT}, ConfidenceCorroborated},
+		{true, []string{SCRAPE}, ConfidenceResolved},
+		{true, []string{ALT}, ConfidenceResolved},
+		{true, []string{ALT, BRUTE}, ConfidenceResolved},
+		{false, []string{API, SCRAPE}, ConfidenceReported},
+		{false, []string{SCRAPE, ALT}, ConfidenceReported},
+		{false, []string{ALT}, ConfidenceGuess},
+		{false, nil, ConfidenceGuess},
+	}
+
+	for _, test := range tests {
+		if c := Confidence(test.resolved, test.tags); c != test.expected {
+			t.Errorf("Resolved %t with %v returned %d, expected %d", test.resolved, test.tags, c, test.expected)
+		}
+	}
+}
@@ -44,6 +44,13 @@ const (
 	OutputTopic        = "amass:output"
 )
 
+// The properties storing the interval during which the nodes were observed by the events
+// consolidated into a single event, and the finish of the last event consolidated.
+const (
+	FirstSeenPredicate = "first_seen"
+	LastSeenPredicate  = "last_seen"
+)
+
 // DNSAnswer is the type used by Amass to represent a DNS record.
 type DNSAnswer struct {
 	Name string `json:"name"`
@@ -286,23 +293,152 @@ type WhoisRequest struct {
 	Source     string
 }
 
+// EmailRequest handles email addresses discovered during the enumeration.
+type EmailRequest struct {
+	Email  string
+	Domain string
+	Tag    string
+	Source string
+}
+
+// Clone implements pipeline Data.
+func (e *EmailRequest) Clone() pipeline.Data {
+	return &EmailRequest{
+		Email:  e.Email,
+		Domain: e.Domain,
+		Tag:    e.Tag,
+		Source: e.Source,
+	}
+}
+
+// MarkAsProcessed implements pipeline Data.
+func (e *EmailRequest) MarkAsProcessed() {}
+
+// Valid performs input validation of the receiver.
+func (e *EmailRequest) Valid() bool {
+	parts := strings.Split(e.Email, "@")
+	if len(parts) != 2 || parts[0] == "" {
+		return false
+	}
+	if _, ok := dns.IsDomainName(parts[1]); !ok {
+		return false
+	}
+	if _, ok := dns.IsDomainName(e.Domain); !ok {
+		return false
+	}
+	if !dns.IsSubDomain(e.Domain, parts[1]) {
+		return false
+	}
+	return true
+}
+
+// CertRequest handles the metadata of TLS certificates observed during the enumeration.
+type CertRequest struct {
+	Serial    string
+	Issuer    string
+	Subject   string
+	Names     []string
+	NotBefore time.Time
+	NotAfter  time.Time
+	Address   string
+	Domain    string
+	Tag       string
+	Source    string
+}
+
+// Clone implements pipeline Data.
+func (c *CertRequest) Clone() pipeline.Data {
+	return &CertRequest{
+		Serial:    c.Serial,
+		Issuer:    c.Issuer,
+		Subject:   c.Subject,
+		Names:     append([]string(nil), c.Names...),
+		NotBefore: c.NotBefore,
+		NotAfter:  c.NotAfter,
+		Address:   c.Address,
+		Domain:    c.Domain,
+		Tag:       c.Tag,
+		Source:    c.Source,
+	}
+}
+
+// MarkAsProcessed implements pipeline Data.
+func (c *CertRequest) MarkAsProcessed() {}
+
+// Valid performs input validation of the receiver.
+func (c *CertRequest) Valid() bool {
+	if c.Serial == "" || c.Issuer == "" {
+		return false
+	}
+	if c.Address != "" && net.ParseIP(c.Address) == nil {
+		return false
+	}
+	if _, ok := dns.IsDomainName(c.Domain); !ok {
+		return false
+	}
+	return true
+}
+
+// NormalizeSerial returns the hexadecimal certificate serial number without
+// colons and leading zeros, since sources provide the serial numbers both ways.
+func NormalizeSerial(serial string) string {
+	serial = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(serial), ":", ""))
+
+	if s := strings.TrimLeft(serial, "0"); s != "" {
+		return s
+	}
+	return serial
+}
+
+// CertNodeID returns the identifier of the graph node for the certificate with the serial number.
+func CertNodeID(serial string) string {
+	return "cert:" + NormalizeSerial(serial)
+}
+
 // Output contains all the output data for an enumerated DNS name.
 type Output struct {
-	Name      string        `json:"name"`
-	Domain    string        `json:"domain"`
-	Addresses []AddressInfo `json:"addresses"`
-	Tag       string        `json:"tag"`
-	Sources   []string      `json:"sources"`
+	Name       string         `json:"name"`
+	Domain     string         `json:"domain"`
+	Addresses  []AddressInfo  `json:"addresses"`
+	Tag        string         `json:"tag"`
+	Sources    []string       `json:"sources"`
+	Confidence int            `json:"confidence,omitempty"`
+	UserTags   []string       `json:"user_tags,omitempty"`
+	Notes      []string       `json:"notes,omitempty"`
+	Takeovers  []TakeoverInfo `json:"takeovers,omitempty"`
+	HTTP       []HTTPInfo     `json:"http,omitempty"`
+}
+
+// HTTPInfo describes a web service responding at the name.
+type HTTPInfo struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Redirect   string `json:"redirect,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Server     string `json:"server,omitempty"`
+}
+
+// TakeoverInfo is a subdomain takeover candidate of the name, with the evidence collected for it.
+type TakeoverInfo struct {
+	Service    string `json:"service"`
+	Record     string `json:"record"`
+	Confidence int    `json:"confidence"`
+	Evidence   string `json:"evidence"`
 }
 
 // Clone implements pipeline Data.
 func (o *Output) Clone() pipeline.Data {
 	return &Output{
-		Name:      o.Name,
-		Domain:    o.Domain,
-		Addresses: append([]AddressInfo(nil), o.Addresses...),
-		Tag:       o.Tag,
-		Sources:   append([]string(nil), o.Sources...),
+		Name:       o.Name,
+		Domain:     o.Domain,
+		Addresses:  append([]AddressInfo(nil), o.Addresses...),
+		Tag:        o.Tag,
+		Sources:    append([]string(nil), o.Sources...),
+		Confidence: o.Confidence,
+		UserTags:   append([]string(nil), o.UserTags...),
+		Notes:      append([]string(nil), o.Notes...),
+		Takeovers:  append([]TakeoverInfo(nil), o.Takeovers...),
+		HTTP:       append([]HTTPInfo(nil), o.HTTP...),
 	}
 }
 
@@ -339,6 +475,7 @@ type AddressInfo struct {
 	CIDRStr     string     `json:"cidr"`
 	ASN         int        `json:"asn"`
 	Description string     `json:"desc"`
+	Confidence  int        `json:"confidence,omitempty"`
 }
 
 // TrustedTag returns true when the tag parameter is of a type that should be trusted even
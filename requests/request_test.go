@@ -500,3 +500,54 @@ func TestASNRequestValid(t *testing.T) {
 		})
 	}
 }
+
+func TestEmailRequestValid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		req     EmailRequest
+		success bool
+	}{
+		{
+			name: "Valid test",
+			req: EmailRequest{
+				Email:  "admin@mail.example.com",
+				Domain: "example.com",
+				Tag:    "test",
+				Source: "test",
+			},
+			success: true,
+		},
+		{
+			name: "Invalid test - Missing local part",
+			req: EmailRequest{
+				Email:  "@example.com",
+				Domain: "example.com",
+				Tag:    "test",
+				Source: "test",
+			},
+			success: false,
+		},
+		{
+			name: "Invalid test - Out of scope",
+			req: EmailRequest{
+				Email:  "admin@example.org",
+				Domain: "example.com",
+				Tag:    "test",
+				Source: "test",
+			},
+			success: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.success {
+				valid := test.req.Valid()
+				require.True(t, valid)
+			} else {
+				valid := test.req.Valid()
+				require.False(t, valid)
+			}
+		})
+	}
+}
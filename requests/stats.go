@@ -0,0 +1,64 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import (
+	"sort"
+	"time"
+)
+
+// SourceStats contains the counters collected for a data source during an enumeration.
+type SourceStats struct {
+	Source        string        `json:"source"`
+	Requests      int           `json:"requests"`
+	Names         int           `json:"names"`
+	UniqueNames   int           `json:"unique_names"`
+	Resolved      int           `json:"resolved"`
+	Errors        int           `json:"errors"`
+	RateLimitWait time.Duration `json:"rate_limit_wait"`
+}
+
+// MergeSourceStats combines the counters for each data source and returns them sorted
+// by the number of unique names contributed.
+func MergeSourceStats(stats ...[]*SourceStats) []*SourceStats {
+	merged := make(map[string]*SourceStats)
+
+	for _, list := range stats {
+		for _, s := range list {
+			if s == nil || s.Source == "" {
+				continue
+			}
+
+			m, found := merged[s.Source]
+			if !found {
+				m = &SourceStats{Source: s.Source}
+				merged[s.Source] = m
+			}
+
+			m.Requests += s.Requests
+			m.Names += s.Names
+			m.UniqueNames += s.UniqueNames
+			m.Resolved += s.Resolved
+			m.Errors += s.Errors
+			m.RateLimitWait += s.RateLimitWait
+		}
+	}
+
+	var results []*SourceStats
+	for _, s := range merged {
+		results = append(results, s)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].UniqueNames != results[j].UniqueNames {
+			return results[i].UniqueNames > results[j].UniqueNames
+		}
+		if results[i].Names != results[j].Names {
+			return results[i].Names > results[j].Names
+		}
+		return results[i].Source < results[j].Source
+	})
+	return results
+}
@@ -0,0 +1,37 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSourceStats(t *testing.T) {
+	first := []*SourceStats{
+		{Source: "crtsh", Requests: 1, Names: 10, UniqueNames: 5},
+		{Source: "HackerTarget", Requests: 1, Names: 3, UniqueNames: 1, Errors: 1},
+		nil,
+	}
+	second := []*SourceStats{
+		{Source: "HackerTarget", Requests: 2, Names: 12, UniqueNames: 8, RateLimitWait: time.Second},
+		{Source: ""},
+	}
+
+	merged := MergeSourceStats(first, second)
+	require.Len(t, merged, 2)
+	require.Equal(t, &SourceStats{
+		Source:        "HackerTarget",
+		Requests:      3,
+		Names:         15,
+		UniqueNames:   9,
+		Errors:        1,
+		RateLimitWait: time.Second,
+	}, merged[0])
+	require.Equal(t, "crtsh", merged[1].Source)
+	require.Equal(t, 5, merged[1].UniqueNames)
+}
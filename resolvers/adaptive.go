@@ -0,0 +1,147 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.uber.org/ratelimit"
+)
+
+const (
+	rateAdjustInterval = 5 * time.Second
+	minAdaptiveQPS     = 1
+	// The adaptive rate of a resolver can grow to this multiple of the QPS it was added with
+	maxAdaptiveFactor = 10
+	// Loss rates that allow the rate to increase or cause the rate to be reduced
+	lossIncreaseThreshold = 0.02
+	lossDecreaseThreshold = 0.1
+	minRateSamples        = 10
+)
+
+// adaptiveRate limits the queries sent to a resolver. When adjustments are enabled, the rate is
+// increased additively while the loss stays low, and decreased multiplicatively when losses occur.
+type adaptiveRate struct {
+	sync.Mutex
+	qps     int
+	min     int
+	max     int
+	limiter ratelimit.Limiter
+	sent    int
+	lost    int
+}
+
+func newAdaptiveRate(qps int) *adaptiveRate {
+	return &adaptiveRate{
+		qps:     qps,
+		min:     minAdaptiveQPS,
+		max:     qps * maxAdaptiveFactor,
+		limiter: ratelimit.New(qps),
+	}
+}
+
+// Take blocks until the next query can be sent to the resolver.
+func (a *adaptiveRate) Take() {
+	a.Lock()
+	limiter := a.limiter
+	a.Unlock()
+
+	limiter.Take()
+}
+
+// QPS returns the current rate of the resolver.
+func (a *adaptiveRate) QPS() int {
+	a.Lock()
+	defer a.Unlock()
+
+	return a.qps
+}
+
+// Timeouts and refusals are considered losses, since both occur when a resolver is overwhelmed.
+func (a *adaptiveRate) record(resp *dns.Msg, err error) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.sent++
+	if err != nil || resp == nil || resp.Rcode == dns.RcodeRefused {
+		a.lost++
+	}
+}
+
+// Returns true when the rate was changed, and resets the counts for the next interval.
+func (a *adaptiveRate) adjust(interval time.Duration) bool {
+	a.Lock()
+	defer a.Unlock()
+
+	sent, lost := a.sent, a.lost
+	a.sent, a.lost = 0, 0
+	if sent < minRateSamples {
+		return false
+	}
+
+	qps := a.qps
+	loss := float64(lost) / float64(sent)
+	switch {
+	case loss > lossDecreaseThreshold:
+		qps /= 2
+	case loss < lossIncreaseThreshold && float64(sent) >= float64(a.qps)*interval.Seconds()/2:
+		// The rate is only increased for resolvers that are in demand
+		step := qps / 10
+		if step < 1 {
+			step = 1
+		}
+		qps += step
+	}
+
+	if qps < a.min {
+		qps = a.min
+	} else if qps > a.max {
+		qps = a.max
+	}
+	if qps == a.qps {
+		return false
+	}
+
+	a.qps = qps
+	a.limiter = ratelimit.New(qps)
+	return true
+}
+
+// SetAdaptiveQPS enables or disables the adjustment of the query rate of each resolver
+// based on the losses experienced. The QPS provided when adding a resolver is the initial rate.
+func (p *Pool) SetAdaptiveQPS(enabled bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.adaptive = enabled
+}
+
+func (p *Pool) rateAdjustments() {
+	t := time.NewTicker(rateAdjustInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-t.C:
+		}
+
+		p.Lock()
+		if p.adaptive {
+			for _, m := range p.list {
+				m.rate.adjust(rateAdjustInterval)
+			}
+		}
+		rrl, logger := p.rrl, p.log
+		p.Unlock()
+
+		if rrl != nil {
+			rrl.adjust(rateAdjustInterval, logger)
+		}
+	}
+}
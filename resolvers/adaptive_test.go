@@ -0,0 +1,59 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func TestAdaptiveRateAdjust(t *testing.T) {
+	a := newAdaptiveRate(10)
+	success := &dns.Msg{}
+
+	send := func(num, lost int) {
+		for i := 0; i < num; i++ {
+			if i < lost {
+				a.record(nil, errors.New("timeout"))
+			} else {
+				a.record(success, nil)
+			}
+		}
+	}
+
+	send(50, 0)
+	if !a.adjust(rateAdjustInterval) || a.QPS() != 11 {
+		t.Errorf("The rate did not increase while the resolver was in demand: %d", a.QPS())
+	}
+
+	send(10, 0)
+	if a.adjust(rateAdjustInterval) || a.QPS() != 11 {
+		t.Errorf("The rate increased while the resolver was not in demand: %d", a.QPS())
+	}
+
+	send(50, 10)
+	if !a.adjust(rateAdjustInterval) || a.QPS() != 5 {
+		t.Errorf("The rate did not back off after the losses: %d", a.QPS())
+	}
+
+	for i := 0; i < 5; i++ {
+		send(50, 50)
+		a.adjust(rateAdjustInterval)
+	}
+	if a.QPS() != minAdaptiveQPS {
+		t.Errorf("The rate fell below the minimum: %d", a.QPS())
+	}
+
+	for i := 0; i < 1000; i++ {
+		send(1000, 0)
+		a.adjust(time.Second)
+	}
+	if a.QPS() != 10*maxAdaptiveFactor {
+		t.Errorf("The rate exceeded the maximum: %d", a.QPS())
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"strings"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+// ExtractAnswers returns information from the DNS Answer section of the provided Msg. The character
+// strings of TXT and SPF records are concatenated without separators, as described in RFC 7208,
+// so that long records split across several strings are recorded completely. The NAPTR, CAA and
+// DS records are provided using the presentation format of the record data.
+func ExtractAnswers(msg *dns.Msg) []*resolve.ExtractedAnswer {
+	var answers []*resolve.ExtractedAnswer
+
+	for _, a := range resolve.ExtractAnswers(msg) {
+		if a.Type != dns.TypeTXT {
+			answers = append(answers, a)
+		}
+	}
+
+	for _, rr := range msg.Answer {
+		var value string
+
+		switch v := rr.(type) {
+		case *dns.TXT:
+			value = strings.Join(v.Txt, "")
+		case *dns.SPF:
+			value = strings.Join(v.Txt, "")
+		case *dns.NAPTR, *dns.CAA, *dns.DS:
+			value = strings.TrimPrefix(rr.String(), rr.Header().String())
+		default:
+			continue
+		}
+
+		if value = strings.TrimSpace(value); value != "" {
+			answers = append(answers, &resolve.ExtractedAnswer{
+				Name: strings.ToLower(resolve.RemoveLastDot(rr.Header().Name)),
+				Type: rr.Header().Rrtype,
+				Data: value,
+			})
+		}
+	}
+	return answers
+}
@@ -0,0 +1,47 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestExtractAnswers(t *testing.T) {
+	records := []string{
+		`example.com. 300 IN TXT "v=spf1 include:_spf.example.com " "-all"`,
+		`example.com. 300 IN NAPTR 100 10 "S" "SIP+D2U" "" _sip._udp.example.com.`,
+		`example.com. 300 IN CAA 0 issue "letsencrypt.org"`,
+		`example.com. 300 IN DS 12345 13 2 3490A6806D47F17A34C29E2CE80E8A999FFBE4BE`,
+		`www.example.com. 300 IN A 192.168.1.1`,
+	}
+	expected := map[uint16]string{
+		dns.TypeTXT:   "v=spf1 include:_spf.example.com -all",
+		dns.TypeNAPTR: `100 10 "S" "SIP+D2U" "" _sip._udp.example.com.`,
+		dns.TypeCAA:   `0 issue "letsencrypt.org"`,
+		dns.TypeDS:    "12345 13 2 3490A6806D47F17A34C29E2CE80E8A999FFBE4BE",
+		dns.TypeA:     "192.168.1.1",
+	}
+
+	msg := new(dns.Msg)
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			t.Fatalf("Failed to parse the test record %s: %v", record, err)
+		}
+		msg.Answer = append(msg.Answer, rr)
+	}
+
+	ans := ExtractAnswers(msg)
+	if len(ans) != len(expected) {
+		t.Fatalf("Got %d answers, expected %d", len(ans), len(expected))
+	}
+	for _, a := range ans {
+		if data := expected[a.Type]; a.Data != data {
+			t.Errorf("%s record: got %s, expected %s", dns.TypeToString[a.Type], a.Data, data)
+		}
+	}
+}
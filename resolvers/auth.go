@@ -0,0 +1,224 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+const maxReferrals = 3
+
+// Authoritative sends DNS queries directly to the authoritative name servers of each zone,
+// bypassing the caching performed by recursive resolvers. The name servers are discovered
+// using the provided recursive resolvers.
+type Authoritative struct {
+	sync.Mutex
+	recursive *Pool
+	qps       int
+	port      string
+	preferV6  bool
+	log       *log.Logger
+	zones     map[string]*authZone
+}
+
+type authZone struct {
+	sync.Mutex
+	pool *Pool
+	err  error
+}
+
+// NewAuthoritative returns an Authoritative that sends the provided number of queries per second to each name server.
+func NewAuthoritative(recursive *Pool, qps int) *Authoritative {
+	return &Authoritative{
+		recursive: recursive,
+		qps:       qps,
+		port:      "53",
+		log:       log.New(ioutil.Discard, "", 0),
+		zones:     make(map[string]*authZone),
+	}
+}
+
+// SetLogger assigns a new logger to the Authoritative and the pools created for each zone.
+func (a *Authoritative) SetLogger(l *log.Logger) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.log = l
+}
+
+// SetPreferIPv6 determines if the name servers are queried over IPv6 when the transport is available.
+func (a *Authoritative) SetPreferIPv6(prefer bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.preferV6 = prefer
+}
+
+// Stop releases the pools created for each zone.
+func (a *Authoritative) Stop() {
+	a.Lock()
+	defer a.Unlock()
+
+	for _, z := range a.zones {
+		z.Lock()
+		if z.pool != nil {
+			z.pool.Stop()
+		}
+		z.Unlock()
+	}
+	a.zones = make(map[string]*authZone)
+}
+
+// Query sends the message to the authoritative name servers of the zone. Referrals to the
+// name servers of delegated zones are followed.
+func (a *Authoritative) Query(ctx context.Context, msg *dns.Msg, zone string) (*dns.Msg, error) {
+	if msg == nil || len(msg.Question) == 0 {
+		return nil, errors.New("the DNS message did not contain a question")
+	}
+
+	zone = strings.ToLower(resolve.RemoveLastDot(zone))
+	for i := 0; i <= maxReferrals; i++ {
+		pool, err := a.zonePool(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := pool.QueryBlocking(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+
+		sub := referral(resp, zone)
+		if sub == "" {
+			return resp, nil
+		}
+		zone = sub
+	}
+	return nil, fmt.Errorf("too many referrals were received for %s", msg.Question[0].Name)
+}
+
+// Returns the delegated zone when the response is a referral from the provided zone.
+func referral(resp *dns.Msg, zone string) string {
+	if resp.Rcode != dns.RcodeSuccess || len(resp.Answer) > 0 || resp.Authoritative {
+		return ""
+	}
+
+	for _, rr := range resp.Ns {
+		if ns, ok := rr.(*dns.NS); ok {
+			sub := strings.ToLower(resolve.RemoveLastDot(ns.Hdr.Name))
+
+			if sub != zone && strings.HasSuffix(sub, "."+zone) {
+				return sub
+			}
+		}
+	}
+	return ""
+}
+
+// Returns the pool of authoritative name servers for the zone, discovering them the first time.
+func (a *Authoritative) zonePool(ctx context.Context, zone string) (*Pool, error) {
+	a.Lock()
+	z, found := a.zones[zone]
+	if !found {
+		z = new(authZone)
+		a.zones[zone] = z
+	}
+	logger := a.log
+	a.Unlock()
+
+	z.Lock()
+	defer z.Unlock()
+
+	if z.pool != nil || z.err != nil {
+		return z.pool, z.err
+	}
+
+	addrs, err := a.nameServerAddrs(ctx, zone)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+			// Do not remember the failure when the query was cancelled
+			return nil, err
+		default:
+		}
+		z.err = err
+		return nil, err
+	}
+
+	pool := NewPool()
+	pool.SetLogger(logger)
+	pool.SetNegativeCaching(true)
+	pool.SetRRLDetection(true)
+	if err := pool.AddResolvers(a.qps, addrs...); err != nil {
+		pool.Stop()
+		z.err = err
+		return nil, err
+	}
+
+	z.pool = pool
+	logger.Printf("Authoritative: %s: sending queries to %s", zone, strings.Join(addrs, ", "))
+	return pool, nil
+}
+
+func (a *Authoritative) nameServerAddrs(ctx context.Context, zone string) ([]string, error) {
+	resp, err := a.query(ctx, zone, dns.TypeNS)
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []string
+	for _, ns := range resolve.AnswersByType(resolve.ExtractAnswers(resp), dns.TypeNS) {
+		for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+			if qtype == dns.TypeAAAA && !IPv6Available() {
+				continue
+			}
+
+			resp, err := a.query(ctx, ns.Data, qtype)
+			if err != nil {
+				continue
+			}
+
+			for _, rr := range resolve.AnswersByType(resolve.ExtractAnswers(resp), qtype) {
+				addrs = append(addrs, net.JoinHostPort(rr.Data, a.port))
+			}
+		}
+	}
+
+	a.Lock()
+	prefer := a.preferV6
+	a.Unlock()
+
+	addrs = FilterByTransport(addrs, prefer)
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("failed to obtain the authoritative name servers for %s", zone)
+	}
+	return addrs, nil
+}
+
+func (a *Authoritative) query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	for i := 0; i < maxQueryAttempts; i++ {
+		resp, err := a.recursive.QueryBlocking(ctx, resolve.QueryMsg(name, qtype))
+		if err != nil {
+			return nil, err
+		}
+		if resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0 {
+			return resp, nil
+		}
+		if resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError {
+			break
+		}
+	}
+	return nil, fmt.Errorf("the %s query for %s failed", dns.TypeToString[qtype], name)
+}
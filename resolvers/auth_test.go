@@ -0,0 +1,119 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+func startTestServer(t *testing.T, addr string, handler dns.HandlerFunc) string {
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		t.Fatalf("Failed to listen for the DNS test server: %v", err)
+	}
+
+	srv := &dns.Server{PacketConn: pc, Handler: handler}
+	started := make(chan struct{})
+	srv.NotifyStartedFunc = func() { close(started) }
+	go func() { _ = srv.ActivateAndServe() }()
+	<-started
+	t.Cleanup(func() { _ = srv.Shutdown() })
+
+	return pc.LocalAddr().String()
+}
+
+func TestAuthoritativeQuery(t *testing.T) {
+	records := map[string]dns.RR{
+		"example.com./NS":          &dns.NS{Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 300}, Ns: "ns.example.com."},
+		"dev.example.com./NS":      &dns.NS{Hdr: dns.RR_Header{Name: "dev.example.com.", Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 300}, Ns: "ns.dev.example.com."},
+		"ns.example.com./A":        aRecord("ns.example.com.", "127.0.0.1"),
+		"ns.dev.example.com./A":    aRecord("ns.dev.example.com.", "127.0.0.2"),
+		"www.example.com./A":       aRecord("www.example.com.", "192.168.1.1"),
+		"www.dev.example.com./A":   aRecord("www.dev.example.com.", "192.168.2.1"),
+		"cache.example.com./A":     aRecord("cache.example.com.", "10.0.0.1"),
+		"cache.dev.example.com./A": aRecord("cache.dev.example.com.", "10.0.0.1"),
+	}
+	key := func(q dns.Question) string {
+		return strings.ToLower(q.Name) + "/" + dns.TypeToString[q.Qtype]
+	}
+
+	// The recursive resolver provides stale answers for the names that were changed
+	recursive := startTestServer(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		if rr, found := records[key(req.Question[0])]; found {
+			resp.Answer = append(resp.Answer, rr)
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	authoritative := func(zone, ip string) dns.HandlerFunc {
+		return func(w dns.ResponseWriter, req *dns.Msg) {
+			q := req.Question[0]
+			resp := new(dns.Msg)
+			resp.SetReply(req)
+
+			if zone == "example.com." && dns.IsSubDomain("dev.example.com.", q.Name) {
+				resp.Ns = append(resp.Ns, records["dev.example.com./NS"])
+			} else if strings.HasPrefix(q.Name, "cache.") {
+				resp.Authoritative = true
+				resp.Answer = append(resp.Answer, aRecord(q.Name, ip))
+			} else if rr, found := records[key(q)]; found {
+				resp.Authoritative = true
+				resp.Answer = append(resp.Answer, rr)
+			} else {
+				resp.Authoritative = true
+				resp.Rcode = dns.RcodeNameError
+			}
+			_ = w.WriteMsg(resp)
+		}
+	}
+	addr := startTestServer(t, "127.0.0.1:0", authoritative("example.com.", "192.168.1.10"))
+	_, port, _ := net.SplitHostPort(addr)
+	startTestServer(t, "127.0.0.2:"+port, authoritative("dev.example.com.", "192.168.2.10"))
+
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(100, recursive)
+
+	a := NewAuthoritative(p, 100)
+	defer a.Stop()
+	a.port = port
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{"www.example.com", "192.168.1.1"},
+		{"cache.example.com", "192.168.1.10"},
+		{"www.dev.example.com", "192.168.2.1"},
+		{"cache.dev.example.com", "192.168.2.10"},
+	}
+	for _, c := range cases {
+		resp, err := a.Query(ctx, resolve.QueryMsg(c.name, dns.TypeA), "example.com")
+		if err != nil {
+			t.Errorf("%s: the authoritative query failed: %v", c.name, err)
+			continue
+		}
+		if ans := resolve.ExtractAnswers(resp); len(ans) != 1 || ans[0].Data != c.expected {
+			t.Errorf("%s: failed to obtain the answer from the authoritative name server", c.name)
+		}
+	}
+
+	resp, err := a.Query(ctx, resolve.QueryMsg("missing.example.com", dns.TypeA), "example.com")
+	if err != nil || resp.Rcode != dns.RcodeNameError {
+		t.Errorf("Failed to obtain the NXDOMAIN response from the authoritative name server")
+	}
+}
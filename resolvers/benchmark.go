@@ -0,0 +1,315 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+	"golang.org/x/net/publicsuffix"
+)
+
+// DefaultBenchmarkNames are well-known names that are expected to resolve from any location.
+var DefaultBenchmarkNames = []string{
+	"www.owasp.org",
+	"www.google.com",
+	"www.cloudflare.com",
+	"www.wikipedia.org",
+	"www.microsoft.com",
+}
+
+// BenchmarkOptions controls the measurements performed by a Benchmarker.
+type BenchmarkOptions struct {
+	// The names used to measure the latency and to detect false answers
+	Names []string
+	// Number of queries for nonexistent names sent to measure the throughput
+	Queries int
+	// Number of queries sent to the resolver at the same time during the throughput test
+	Concurrency int
+	// The amount of time to wait for each response
+	Timeout time.Duration
+}
+
+// DefaultBenchmarkOptions returns the options used when none are provided to NewBenchmarker.
+func DefaultBenchmarkOptions() *BenchmarkOptions {
+	return &BenchmarkOptions{
+		Names:       DefaultBenchmarkNames,
+		Queries:     100,
+		Concurrency: 10,
+		Timeout:     2 * time.Second,
+	}
+}
+
+// BenchmarkResult contains the measurements obtained for a single DNS resolver.
+type BenchmarkResult struct {
+	// The address of the resolver, as it would be provided to AddResolvers
+	Address string
+	// Number of queries sent and responses received during all the tests
+	Queries   int
+	Responses int
+	// The median response time for the names in the options
+	Latency time.Duration
+	// Number of responses received per second during the throughput test
+	QPS float64
+	// Number of answers for the names that disagree with the baseline resolvers
+	Lies int
+	// Number of answers provided for nonexistent names, such as NXDOMAIN redirection
+	WildcardLies int
+	// The reason the resolver should not be used, or empty when it can be used
+	Failure string
+}
+
+// ResponseRate returns the fraction of the queries that received a response.
+func (r *BenchmarkResult) ResponseRate() float64 {
+	if r.Queries == 0 {
+		return 0
+	}
+	return float64(r.Responses) / float64(r.Queries)
+}
+
+// Honest returns true when the resolver did not provide any false answers.
+func (r *BenchmarkResult) Honest() bool {
+	return r.Lies == 0 && r.WildcardLies == 0
+}
+
+// Benchmarker measures the latency, throughput and honesty of DNS resolvers, using the
+// answers obtained from the baseline resolvers as the reference.
+type Benchmarker struct {
+	opts *BenchmarkOptions
+	// Answers provided by the baseline resolvers for the names in the options
+	truth map[string]*dns.Msg
+	// Domains without a DNS wildcard, which are used to generate nonexistent names
+	domains []string
+}
+
+// NewBenchmarker returns a Benchmarker that obtains the reference answers using the baseline pool.
+// DefaultBenchmarkOptions are used when the opts parameter is nil.
+func NewBenchmarker(ctx context.Context, baseline *Pool, opts *BenchmarkOptions) (*Benchmarker, error) {
+	if opts == nil {
+		opts = DefaultBenchmarkOptions()
+	}
+	if opts.Queries <= 0 || opts.Concurrency <= 0 {
+		return nil, errors.New("the benchmark queries and concurrency must be greater than zero")
+	}
+
+	b := &Benchmarker{
+		opts:  opts,
+		truth: make(map[string]*dns.Msg),
+	}
+
+	seen := make(map[string]struct{})
+	for _, name := range opts.Names {
+		name = strings.ToLower(resolve.RemoveLastDot(strings.TrimSpace(name)))
+		if name == "" {
+			continue
+		}
+
+		resp, err := baseline.QueryBlocking(ctx, resolve.QueryMsg(name, dns.TypeA))
+		if err != nil || resp.Rcode != dns.RcodeSuccess || len(resp.Answer) == 0 {
+			continue
+		}
+		b.truth[name] = resp
+
+		domain, err := publicsuffix.EffectiveTLDPlusOne(name)
+		if err != nil {
+			continue
+		}
+		if _, found := seen[domain]; found {
+			continue
+		}
+		seen[domain] = struct{}{}
+		// Domains with a DNS wildcard cannot be used to detect false answers for nonexistent names
+		if resp, err := baseline.QueryBlocking(ctx, resolve.QueryMsg(resolve.UnlikelyName(domain), dns.TypeA)); err == nil &&
+			resp.Rcode == dns.RcodeNameError {
+			b.domains = append(b.domains, domain)
+		}
+	}
+
+	if len(b.truth) == 0 || len(b.domains) == 0 {
+		return nil, errors.New("the baseline resolvers failed to provide the reference answers")
+	}
+	return b, nil
+}
+
+// Benchmark performs the tests against the resolver at the provided address.
+func (b *Benchmarker) Benchmark(ctx context.Context, addr string) *BenchmarkResult {
+	result := &BenchmarkResult{Address: strings.TrimSpace(addr)}
+
+	res, err := NewResolver(result.Address)
+	if err != nil {
+		result.Failure = err.Error()
+		return result
+	}
+	defer res.Stop()
+
+	res.SetTimeout(b.opts.Timeout)
+	result.Address = res.String()
+
+	b.checkAnswers(ctx, res, result)
+	b.checkThroughput(ctx, res, result)
+
+	switch {
+	case result.Responses == 0:
+		result.Failure = "no responses were received"
+	case result.Lies > 0:
+		result.Failure = "false answers were provided for existing names"
+	case result.WildcardLies > 0:
+		result.Failure = "answers were provided for nonexistent names"
+	}
+	return result
+}
+
+// Measures the latency using the names with reference answers, and compares the answers.
+func (b *Benchmarker) checkAnswers(ctx context.Context, res Resolver, result *BenchmarkResult) {
+	var rtts []time.Duration
+
+	for name, truth := range b.truth {
+		start := time.Now()
+		resp, err := res.Exchange(ctx, resolve.QueryMsg(name, dns.TypeA))
+		result.Queries++
+		if err != nil || resp == nil {
+			continue
+		}
+
+		rtts = append(rtts, time.Since(start))
+		result.Responses++
+		if !consistentAnswers(truth, resp) {
+			result.Lies++
+		}
+	}
+
+	if len(rtts) > 0 {
+		sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+		result.Latency = rtts[len(rtts)/2]
+	}
+}
+
+// Sends queries for nonexistent names, which also reveals resolvers that answer for any name.
+func (b *Benchmarker) checkThroughput(ctx context.Context, res Resolver, result *BenchmarkResult) {
+	var lock sync.Mutex
+	var responses, lies int
+
+	names := make(chan string, b.opts.Concurrency)
+	go func() {
+		defer close(names)
+
+		for i := 0; i < b.opts.Queries; i++ {
+			var name string
+			for name == "" {
+				name = resolve.UnlikelyName(b.domains[i%len(b.domains)])
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case names <- name:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < b.opts.Concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for name := range names {
+				resp, err := res.Exchange(ctx, resolve.QueryMsg(name, dns.TypeA))
+				if err != nil || resp == nil {
+					continue
+				}
+
+				lock.Lock()
+				responses++
+				if resp.Rcode == dns.RcodeSuccess && len(resolve.AnswersByType(ExtractAnswers(resp), dns.TypeA)) > 0 {
+					lies++
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	result.Queries += b.opts.Queries
+	result.Responses += responses
+	result.WildcardLies = lies
+	if elapsed > 0 {
+		result.QPS = float64(responses) / elapsed.Seconds()
+	}
+}
+
+// The answers are consistent when the resolver provides addresses for the name that are not
+// reserved, unless the baseline also provided reserved addresses. The addresses themselves
+// are not compared, since content delivery networks answer differently by location.
+func consistentAnswers(truth, resp *dns.Msg) bool {
+	if resp.Rcode != dns.RcodeSuccess {
+		return false
+	}
+
+	addrs := resolve.AnswersByType(ExtractAnswers(resp), dns.TypeA)
+	if len(addrs) == 0 {
+		return false
+	}
+
+	for _, a := range resolve.AnswersByType(ExtractAnswers(truth), dns.TypeA) {
+		if blockedAddress(a.Data) {
+			return true
+		}
+	}
+	for _, a := range addrs {
+		if blockedAddress(a.Data) {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolvers that block names commonly answer with the unspecified address or a reserved address.
+func blockedAddress(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil || ip.IsUnspecified() {
+		return true
+	}
+
+	yes, _ := amassnet.IsReservedAddress(addr)
+	return yes
+}
+
+// RankBenchmarkResults returns the resolvers that provided honest answers for at least the minimum
+// fraction of the queries, ordered by latency with the higher throughput breaking ties.
+func RankBenchmarkResults(results []*BenchmarkResult, minRate float64) []*BenchmarkResult {
+	var ranked []*BenchmarkResult
+
+	seen := make(map[string]struct{})
+	for _, r := range results {
+		if r == nil || r.Failure != "" || !r.Honest() || r.ResponseRate() < minRate {
+			continue
+		}
+		if _, found := seen[r.Address]; found {
+			continue
+		}
+		seen[r.Address] = struct{}{}
+		ranked = append(ranked, r)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Latency != ranked[j].Latency {
+			return ranked[i].Latency < ranked[j].Latency
+		}
+		return ranked[i].QPS > ranked[j].QPS
+	})
+	return ranked
+}
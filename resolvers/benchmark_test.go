@@ -0,0 +1,87 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func TestBenchmarker(t *testing.T) {
+	honest := func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		if strings.EqualFold(req.Question[0].Name, "www.example.com.") {
+			resp.Answer = append(resp.Answer, aRecord(req.Question[0].Name, "93.184.216.34"))
+		} else {
+			resp.Rcode = dns.RcodeNameError
+		}
+		_ = w.WriteMsg(resp)
+	}
+
+	baseline := NewPool()
+	defer baseline.Stop()
+	_ = baseline.AddResolvers(100, startTestServer(t, "127.0.0.1:0", honest))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	b, err := NewBenchmarker(ctx, baseline, &BenchmarkOptions{
+		Names:       []string{"www.example.com"},
+		Queries:     20,
+		Concurrency: 5,
+		Timeout:     time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create the benchmarker: %v", err)
+	}
+
+	good := startTestServer(t, "127.0.0.1:0", honest)
+	// Redirects the nonexistent names to a search page
+	redirect := startTestServer(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Answer = append(resp.Answer, aRecord(req.Question[0].Name, "93.184.216.34"))
+		_ = w.WriteMsg(resp)
+	})
+	// Blocks the names by providing a reserved address
+	blocking := startTestServer(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		if strings.EqualFold(req.Question[0].Name, "www.example.com.") {
+			resp.Answer = append(resp.Answer, aRecord(req.Question[0].Name, "0.0.0.0"))
+		} else {
+			resp.Rcode = dns.RcodeNameError
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	var results []*BenchmarkResult
+	for _, addr := range []string{good, redirect, blocking, "not-an-address"} {
+		results = append(results, b.Benchmark(ctx, addr))
+	}
+
+	if r := results[0]; r.Failure != "" || r.Responses != r.Queries || r.QPS <= 0 || r.Latency <= 0 {
+		t.Errorf("The honest resolver was not measured correctly: %+v", r)
+	}
+	if r := results[1]; r.WildcardLies != 20 || r.Lies != 0 || r.Failure == "" {
+		t.Errorf("The redirecting resolver was not detected: %+v", r)
+	}
+	if r := results[2]; r.Lies != 1 || r.WildcardLies != 0 || r.Failure == "" {
+		t.Errorf("The blocking resolver was not detected: %+v", r)
+	}
+	if r := results[3]; r.Failure == "" {
+		t.Errorf("The invalid address did not fail")
+	}
+
+	ranked := RankBenchmarkResults(append(results, results[0]), 0.9)
+	if len(ranked) != 1 || ranked[0].Address != good {
+		t.Errorf("The results were not ranked correctly: %v", ranked)
+	}
+}
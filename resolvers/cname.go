@@ -0,0 +1,129 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+const (
+	// Resolvers commonly refuse to follow chains longer than this
+	maxCNAMEChainLength = 16
+	cnameQueryAttempts  = 3
+)
+
+// CNAMEChain describes the CNAME records followed from a name to the terminal target.
+type CNAMEChain struct {
+	// The name followed by each target in the chain, in order
+	Names []string
+	// The last name in the chain, which has no CNAME record
+	Target string
+	// Dangling is true when the terminal target does not exist (NXDOMAIN)
+	Dangling bool
+}
+
+// String returns the chain formatted as a list of the names.
+func (c *CNAMEChain) String() string {
+	return strings.Join(c.Names, " -> ")
+}
+
+// FollowCNAMEChain follows the CNAME records starting at the provided name until reaching
+// a target without a CNAME record, and reports whether that target exists. An error is
+// returned when the name has no CNAME record, or the chain loops or is too long.
+func (p *Pool) FollowCNAMEChain(ctx context.Context, name string) (*CNAMEChain, error) {
+	name = strings.ToLower(resolve.RemoveLastDot(name))
+	chain := &CNAMEChain{Names: []string{name}}
+
+	for current := name; ; {
+		resp, err := p.cnameQuery(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+
+		next, found, err := chain.extend(resp)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Rcode == dns.RcodeNameError {
+			chain.Dangling = true
+			break
+		}
+		// Query the target directly when the resolver did not provide its records
+		if next == current || found {
+			break
+		}
+		current = next
+	}
+
+	if len(chain.Names) < 2 {
+		return nil, fmt.Errorf("%s has no CNAME record", name)
+	}
+	chain.Target = chain.Names[len(chain.Names)-1]
+	return chain, nil
+}
+
+// Appends the CNAME targets provided in the response, and returns the last name in the chain,
+// along with an indication of whether the response included other records for that name.
+func (c *CNAMEChain) extend(resp *dns.Msg) (string, bool, error) {
+	last := c.Names[len(c.Names)-1]
+
+	for {
+		var next string
+		for _, rr := range resp.Answer {
+			if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(resolve.RemoveLastDot(cname.Hdr.Name), last) {
+				next = strings.ToLower(resolve.RemoveLastDot(cname.Target))
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+
+		for _, n := range c.Names {
+			if n == next {
+				return "", false, fmt.Errorf("the CNAME chain %s loops back to %s", c, next)
+			}
+		}
+		if len(c.Names) > maxCNAMEChainLength {
+			return "", false, fmt.Errorf("the CNAME chain %s is too long", c)
+		}
+
+		c.Names = append(c.Names, next)
+		last = next
+	}
+
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype != dns.TypeCNAME && strings.EqualFold(resolve.RemoveLastDot(rr.Header().Name), last) {
+			return last, true, nil
+		}
+	}
+	return last, false, nil
+}
+
+func (p *Pool) cnameQuery(ctx context.Context, name string) (*dns.Msg, error) {
+	msg := resolve.QueryMsg(name, dns.TypeA)
+
+	for i := 0; i < cnameQueryAttempts; i++ {
+		resp, err := p.QueryBlocking(ctx, msg)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+			continue
+		}
+		if resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError {
+			return resp, nil
+		}
+	}
+	return nil, errors.New("failed to resolve the CNAME chain")
+}
@@ -0,0 +1,86 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func TestFollowCNAMEChain(t *testing.T) {
+	cnames := map[string]string{
+		"www.example.com.":     "cdn.example.com.",
+		"cdn.example.com.":     "example.azurewebsites.net.",
+		"shop.example.com.":    "stale.herokuapp.com.",
+		"loop1.example.com.":   "loop2.example.com.",
+		"loop2.example.com.":   "loop1.example.com.",
+		"partial.example.com.": "www.example.com.",
+	}
+	// Only the first record in the chain is provided, so the pool must query each target
+	addr := startTestServer(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		name := strings.ToLower(req.Question[0].Name)
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		switch {
+		case cnames[name] != "":
+			resp.Answer = append(resp.Answer, cnameRecord(name, cnames[name]))
+			if name == "www.example.com." {
+				resp.Answer = append(resp.Answer, cnameRecord(cnames[name], cnames[cnames[name]]),
+					aRecord(cnames[cnames[name]], "192.168.1.1"))
+			}
+		case name == "example.azurewebsites.net.":
+			resp.Answer = append(resp.Answer, aRecord(name, "192.168.1.1"))
+		case name == "plain.example.com.":
+			resp.Answer = append(resp.Answer, aRecord(name, "192.168.1.2"))
+		default:
+			resp.Rcode = dns.RcodeNameError
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(100, addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name     string
+		expected []string
+		dangling bool
+		err      bool
+	}{
+		{"www.example.com", []string{"www.example.com", "cdn.example.com", "example.azurewebsites.net"}, false, false},
+		{"partial.example.com", []string{"partial.example.com", "www.example.com", "cdn.example.com", "example.azurewebsites.net"}, false, false},
+		{"shop.example.com", []string{"shop.example.com", "stale.herokuapp.com"}, true, false},
+		{"loop1.example.com", nil, false, true},
+		{"plain.example.com", nil, false, true},
+	}
+
+	for _, test := range tests {
+		chain, err := p.FollowCNAMEChain(ctx, test.name)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: an error was not returned for the chain %s", test.name, chain)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: failed to follow the CNAME chain: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(chain.Names, test.expected) || chain.Dangling != test.dangling ||
+			chain.Target != test.expected[len(test.expected)-1] {
+			t.Errorf("%s: got chain %s (dangling %t), expected %v (dangling %t)",
+				test.name, chain, chain.Dangling, test.expected, test.dangling)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/miekg/dns"
+)
+
+// Confirm sends the DNS message to distinct resolvers of the pool until the number of resolvers
+// provided in the parameter have answered it, so a single resolver returning false answers cannot
+// add names to the results. When the pool has fewer active resolvers, all of them must confirm the
+// answer. A negative answer from any of the resolvers refutes the name, and the resolvers that do
+// not respond are skipped. The response of the first resolver confirming the answer is returned.
+func (p *Pool) Confirm(ctx context.Context, msg *dns.Msg, num int) (*dns.Msg, error) {
+	if msg == nil || len(msg.Question) == 0 {
+		return nil, errors.New("the message does not provide a question")
+	}
+	if zp := p.zoneResolvers(msg.Question[0].Name); zp != nil {
+		return zp.Confirm(ctx, msg, num)
+	}
+
+	active := p.activeMembers()
+	if len(active) == 0 {
+		return nil, errors.New("no resolvers are available")
+	}
+	if num > len(active) {
+		num = len(active)
+	} else if num < 1 {
+		num = 1
+	}
+
+	var first *dns.Msg
+	var confirmed int
+	for _, m := range active {
+		select {
+		case <-ctx.Done():
+			return nil, errors.New("the context expired")
+		case <-p.done:
+			return nil, errors.New("the resolver pool has been stopped")
+		default:
+		}
+
+		p.Lock()
+		rate := p.rate
+		p.Unlock()
+		if rate != nil {
+			rate.Take()
+		}
+
+		req := &request{
+			ctx:    ctx,
+			msg:    p.applyEDNS0(msg),
+			result: make(chan *dns.Msg, 1),
+		}
+		p.exchange(m, req)
+
+		resp := <-req.result
+		switch {
+		case resp.Rcode == dns.RcodeNameError:
+			return nil, errors.New("name does not exist")
+		case resp.Rcode == dns.RcodeSuccess && len(resp.Answer) == 0:
+			return nil, errors.New("no record of this type")
+		case resp.Rcode == dns.RcodeSuccess:
+			if first == nil {
+				first = resp
+			}
+			if confirmed++; confirmed >= num {
+				return first, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("the answer was confirmed by %d of the %d resolvers required", confirmed, num)
+}
+
+// Returns the resolvers that have not been evicted in random order.
+func (p *Pool) activeMembers() []*member {
+	p.Lock()
+	defer p.Unlock()
+
+	var active []*member
+	for _, m := range p.list {
+		if !m.evicted {
+			active = append(active, m)
+		}
+	}
+
+	rand.Shuffle(len(active), func(i, j int) {
+		active[i], active[j] = active[j], active[i]
+	})
+	return active
+}
@@ -0,0 +1,54 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+func TestPoolConfirm(t *testing.T) {
+	success := func() int { return dns.RcodeSuccess }
+	failure := func() int { return dns.RcodeServerFailure }
+	msg := resolve.QueryMsg("www.example.com", dns.TypeA)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	agreeing := NewPool()
+	defer agreeing.Stop()
+	_ = agreeing.AddResolvers(100, healthTestServer(t, success, true), healthTestServer(t, success, true))
+
+	if resp, err := agreeing.Confirm(ctx, msg, 2); err != nil || len(resp.Answer) == 0 {
+		t.Errorf("The answer of the agreeing resolvers was not confirmed: %v", err)
+	}
+	// All the resolvers must confirm the answer when the pool is smaller than the policy
+	if _, err := agreeing.Confirm(ctx, msg, 5); err != nil {
+		t.Errorf("The answer was not confirmed by all the resolvers of the pool: %v", err)
+	}
+
+	lying := NewPool()
+	defer lying.Stop()
+	_ = lying.AddResolvers(100, healthTestServer(t, success, true), healthTestServer(t, success, false))
+
+	if _, err := lying.Confirm(ctx, msg, 2); err == nil {
+		t.Errorf("The answer refuted by a resolver was confirmed")
+	}
+
+	unresponsive := NewPool()
+	defer unresponsive.Stop()
+	_ = unresponsive.AddResolvers(100, healthTestServer(t, success, true), healthTestServer(t, failure, false))
+
+	if _, err := unresponsive.Confirm(ctx, msg, 2); err == nil {
+		t.Errorf("The answer was confirmed by fewer resolvers than required")
+	}
+	if _, err := unresponsive.Confirm(ctx, msg, 1); err != nil {
+		t.Errorf("The resolver that failed to respond was not skipped: %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// Responses are not kept longer than a day, regardless of the TTLs provided
+	maxDiskCacheTTL     = 24 * time.Hour
+	diskCacheFlushDelay = time.Second
+	diskCacheOpenWait   = 5 * time.Second
+)
+
+// DiskCache is a DNS response cache stored on disk, which allows repeated enumerations of
+// the same scope to reuse the responses obtained by previous runs until the TTLs expire.
+type DiskCache struct {
+	sync.Mutex
+	db      *bolt.DB
+	pending map[string]map[string][]byte
+	done    chan struct{}
+	flushed chan struct{}
+}
+
+// OpenDiskCache opens the DNS cache database at the provided file path, creating it when necessary.
+// Expired responses are removed from the database when it is opened.
+func OpenDiskCache(path string) (*DiskCache, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: diskCacheOpenWait})
+	if err != nil {
+		return nil, err
+	}
+
+	c := &DiskCache{
+		db:      db,
+		pending: make(map[string]map[string][]byte),
+		done:    make(chan struct{}),
+		flushed: make(chan struct{}),
+	}
+	if err := c.purge(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	go c.flushWrites()
+	return c, nil
+}
+
+// Close writes the pending responses to the database and releases the file.
+func (c *DiskCache) Close() error {
+	c.Lock()
+	select {
+	case <-c.done:
+		c.Unlock()
+		return nil
+	default:
+	}
+	close(c.done)
+	c.Unlock()
+
+	<-c.flushed
+	return c.db.Close()
+}
+
+// Get returns the cached response for the message from the named bucket, with the TTLs
+// reduced by the amount of time the response has been cached, or nil when not available.
+func (c *DiskCache) Get(bucket string, msg *dns.Msg) *dns.Msg {
+	if !cacheable(msg) {
+		return nil
+	}
+	key := cacheKey(msg.Question[0].Name, msg.Question[0].Qtype)
+
+	c.Lock()
+	value, found := c.pending[bucket][key]
+	c.Unlock()
+
+	if !found {
+		_ = c.db.View(func(tx *bolt.Tx) error {
+			if b := tx.Bucket([]byte(bucket)); b != nil {
+				if v := b.Get([]byte(key)); v != nil {
+					// The value is only valid during the transaction
+					value = append([]byte(nil), v...)
+				}
+			}
+			return nil
+		})
+	}
+	if len(value) <= 16 {
+		return nil
+	}
+
+	now := time.Now()
+	expires := time.Unix(int64(binary.BigEndian.Uint64(value[:8])), 0)
+	stored := time.Unix(int64(binary.BigEndian.Uint64(value[8:16])), 0)
+	if !now.Before(expires) {
+		return nil
+	}
+
+	resp := new(dns.Msg)
+	if err := resp.Unpack(value[16:]); err != nil {
+		return nil
+	}
+
+	resp.Id = msg.Id
+	elapsed := uint32(now.Sub(stored).Seconds())
+	for _, section := range [][]dns.RR{resp.Answer, resp.Ns, resp.Extra} {
+		for _, rr := range section {
+			if hdr := rr.Header(); hdr.Rrtype != dns.TypeOPT {
+				if hdr.Ttl > elapsed {
+					hdr.Ttl -= elapsed
+				} else {
+					hdr.Ttl = 0
+				}
+			}
+		}
+	}
+	return resp
+}
+
+// Put adds the response to the named bucket when it contains answers, or is a negative answer
+// providing the SOA record of the zone. The response is kept for the smallest TTL provided.
+func (c *DiskCache) Put(bucket string, msg, resp *dns.Msg) {
+	if !cacheable(msg) || resp == nil || resp.Truncated || len(resp.Question) == 0 ||
+		!strings.EqualFold(resp.Question[0].Name, msg.Question[0].Name) {
+		return
+	}
+
+	ttl, ok := responseTTL(resp)
+	if !ok || ttl == 0 {
+		return
+	}
+
+	d := time.Duration(ttl) * time.Second
+	if d > maxDiskCacheTTL {
+		d = maxDiskCacheTTL
+	}
+
+	packed, err := resp.Pack()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	value := make([]byte, 16, 16+len(packed))
+	binary.BigEndian.PutUint64(value[:8], uint64(now.Add(d).Unix()))
+	binary.BigEndian.PutUint64(value[8:16], uint64(now.Unix()))
+	value = append(value, packed...)
+
+	c.Lock()
+	defer c.Unlock()
+
+	if _, found := c.pending[bucket]; !found {
+		c.pending[bucket] = make(map[string][]byte)
+	}
+	c.pending[bucket][cacheKey(msg.Question[0].Name, msg.Question[0].Qtype)] = value
+}
+
+// Returns the smallest TTL of the answers, or the negative caching TTL for NXDOMAIN and NODATA responses.
+func responseTTL(resp *dns.Msg) (uint32, bool) {
+	switch {
+	case resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0:
+		ttl := resp.Answer[0].Header().Ttl
+		for _, rr := range resp.Answer[1:] {
+			if rr.Header().Ttl < ttl {
+				ttl = rr.Header().Ttl
+			}
+		}
+		return ttl, true
+	case resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError:
+		for _, rr := range resp.Ns {
+			if soa, ok := rr.(*dns.SOA); ok {
+				return negativeTTL(soa), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Writes the pending responses in a single transaction to avoid a disk sync for each response.
+func (c *DiskCache) flushWrites() {
+	defer close(c.flushed)
+
+	t := time.NewTicker(diskCacheFlushDelay)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			_ = c.flush()
+			return
+		case <-t.C:
+			_ = c.flush()
+		}
+	}
+}
+
+func (c *DiskCache) flush() error {
+	c.Lock()
+	pending := c.pending
+	c.pending = make(map[string]map[string][]byte)
+	c.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		for bucket, entries := range pending {
+			b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+			if err != nil {
+				return err
+			}
+
+			for key, value := range entries {
+				if err := b.Put([]byte(key), value); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Removes the expired responses from all the buckets.
+func (c *DiskCache) purge() error {
+	now := uint64(time.Now().Unix())
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			var expired [][]byte
+
+			if err := b.ForEach(func(k, v []byte) error {
+				if len(v) <= 16 || binary.BigEndian.Uint64(v[:8]) <= now {
+					expired = append(expired, append([]byte(nil), k...))
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			for _, k := range expired {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// SetDiskCache assigns the DNS cache shared between enumerations and the bucket used to keep the
+// responses obtained by this pool. A nil value stops the use of the cache.
+func (p *Pool) SetDiskCache(c *DiskCache, bucket string) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.disk = c
+	p.diskBucket = bucket
+}
+
+func (p *Pool) getDiskCache() (*DiskCache, string) {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.disk, p.diskBucket
+}
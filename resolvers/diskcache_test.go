@@ -0,0 +1,94 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+func TestDiskCacheSharedBetweenRuns(t *testing.T) {
+	var queries int32
+	addr := startTestServer(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		atomic.AddInt32(&queries, 1)
+
+		q := req.Question[0]
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		switch strings.ToLower(q.Name) {
+		case "www.example.com.":
+			resp.Answer = append(resp.Answer, aRecord(q.Name, "192.168.1.1"))
+		case "volatile.example.com.":
+			a := aRecord(q.Name, "192.168.1.2")
+			a.Hdr.Ttl = 0
+			resp.Answer = append(resp.Answer, a)
+		default:
+			resp.Rcode = dns.RcodeNameError
+			resp.Ns = append(resp.Ns, &dns.SOA{
+				Hdr:    dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 3600},
+				Ns:     "ns.example.com.",
+				Mbox:   "admin.example.com.",
+				Minttl: 300,
+			})
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	path := filepath.Join(t.TempDir(), "dns_cache.db")
+	names := []string{"www.example.com", "missing.example.com", "volatile.example.com"}
+	run := func() {
+		c, err := OpenDiskCache(path)
+		if err != nil {
+			t.Fatalf("Failed to open the DNS cache: %v", err)
+		}
+		defer c.Close()
+
+		p := NewPool()
+		defer p.Stop()
+		_ = p.AddResolvers(100, addr)
+		p.SetDiskCache(c, "test")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		for _, name := range names {
+			resp, err := p.QueryBlocking(ctx, resolve.QueryMsg(name, dns.TypeA))
+			if err != nil || (resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError) {
+				t.Fatalf("The query for %s failed", name)
+			}
+		}
+	}
+
+	run()
+	if num := atomic.LoadInt32(&queries); num != 3 {
+		t.Fatalf("The first run sent %d queries, expected 3", num)
+	}
+	// Only the response with a zero TTL is obtained again
+	run()
+	if num := atomic.LoadInt32(&queries); num != 4 {
+		t.Errorf("The second run sent %d queries, expected 1", num-3)
+	}
+
+	c, err := OpenDiskCache(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen the DNS cache: %v", err)
+	}
+	defer c.Close()
+
+	msg := resolve.QueryMsg("www.example.com", dns.TypeA)
+	if resp := c.Get("test", msg); resp == nil || resp.Id != msg.Id || len(resp.Answer) != 1 {
+		t.Errorf("The cached response was not returned correctly")
+	}
+	if resp := c.Get("other", msg); resp != nil {
+		t.Errorf("The response was returned for a different bucket")
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DNSSEC validation results.
+const (
+	DNSSECSecure        = "secure"
+	DNSSECInsecure      = "insecure"
+	DNSSECBogus         = "bogus"
+	DNSSECIndeterminate = "indeterminate"
+)
+
+// RootTrustAnchor is the DS record for the root zone KSK-2017.
+const RootTrustAnchor = ". 86400 IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"
+
+const maxValidationAttempts = 3
+
+// Validator performs DNSSEC validation of DNS answers using the chain of trust from the root zone.
+type Validator struct {
+	sync.Mutex
+	pool     *Pool
+	anchors  []*dns.DS
+	zones    map[string]*zoneTrust
+	insecure map[string]struct{}
+}
+
+type zoneTrust struct {
+	sync.Mutex
+	status string
+	keys   []*dns.DNSKEY
+}
+
+// NewValidator returns a Validator that sends queries using the provided Pool.
+func NewValidator(p *Pool) *Validator {
+	v := &Validator{
+		pool:     p,
+		zones:    make(map[string]*zoneTrust),
+		insecure: make(map[string]struct{}),
+	}
+
+	if rr, err := dns.NewRR(RootTrustAnchor); err == nil {
+		if ds, ok := rr.(*dns.DS); ok {
+			v.anchors = append(v.anchors, ds)
+		}
+	}
+	return v
+}
+
+// Validate returns the DNSSEC status of the answer for the provided name and record type.
+func (v *Validator) Validate(ctx context.Context, name string, qtype uint16) string {
+	name = dns.Fqdn(strings.ToLower(name))
+	// Names beneath an insecure zone cannot be validated as secure
+	if v.insecureAncestor(name) {
+		return DNSSECInsecure
+	}
+
+	resp, err := v.query(ctx, name, qtype)
+	if err != nil || resp.Rcode != dns.RcodeSuccess || len(resp.Answer) == 0 {
+		return DNSSECIndeterminate
+	}
+
+	sigs := rrsigs(resp.Answer)
+	if len(sigs) == 0 {
+		// The answer is only expected to be unsigned when the zone is insecure
+		zone, err := v.zoneOf(ctx, name)
+		if err != nil {
+			return DNSSECIndeterminate
+		}
+		status, _ := v.zoneStatus(ctx, zone)
+		if status == DNSSECSecure {
+			return DNSSECBogus
+		}
+		return status
+	}
+
+	status := DNSSECSecure
+	for _, rrset := range rrsets(resp.Answer) {
+		s := v.verifyRRset(ctx, rrset, sigs, "")
+		if s == DNSSECBogus {
+			return s
+		}
+		if s != DNSSECSecure {
+			status = s
+		}
+	}
+	return status
+}
+
+// Checks the RRSIGs covering the RRset using the keys of the signing zone. When the child
+// zone is provided, the signer must be one of the zones above it.
+func (v *Validator) verifyRRset(ctx context.Context, rrset []dns.RR, sigs []*dns.RRSIG, child string) string {
+	hdr := rrset[0].Header()
+
+	var covering []*dns.RRSIG
+	for _, sig := range sigs {
+		if sig.TypeCovered == hdr.Rrtype && strings.EqualFold(sig.Hdr.Name, hdr.Name) {
+			covering = append(covering, sig)
+		}
+	}
+	if len(covering) == 0 {
+		return DNSSECBogus
+	}
+
+	signer := dns.Fqdn(strings.ToLower(covering[0].SignerName))
+	if child != "" && (signer == child || !dns.IsSubDomain(signer, child)) {
+		return DNSSECBogus
+	}
+
+	status, keys := v.zoneStatus(ctx, signer)
+	if status != DNSSECSecure {
+		return status
+	}
+	if verifySignatures(rrset, covering, keys) {
+		return DNSSECSecure
+	}
+	return DNSSECBogus
+}
+
+// Returns the validation status and the trusted keys for the provided zone.
+func (v *Validator) zoneStatus(ctx context.Context, zone string) (string, []*dns.DNSKEY) {
+	zone = dns.Fqdn(strings.ToLower(zone))
+
+	v.Lock()
+	zt, found := v.zones[zone]
+	if !found {
+		zt = new(zoneTrust)
+		v.zones[zone] = zt
+	}
+	v.Unlock()
+
+	zt.Lock()
+	defer zt.Unlock()
+
+	if zt.status != "" {
+		return zt.status, zt.keys
+	}
+
+	status, keys := v.establishTrust(ctx, zone)
+	if status != DNSSECIndeterminate {
+		zt.status = status
+		zt.keys = keys
+	}
+	if status == DNSSECInsecure {
+		v.Lock()
+		v.insecure[zone] = struct{}{}
+		v.Unlock()
+	}
+	return status, keys
+}
+
+func (v *Validator) establishTrust(ctx context.Context, zone string) (string, []*dns.DNSKEY) {
+	var dsset []*dns.DS
+
+	if zone == "." {
+		dsset = v.anchors
+	} else {
+		resp, err := v.query(ctx, zone, dns.TypeDS)
+		if err != nil || (resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError) {
+			return DNSSECIndeterminate, nil
+		}
+
+		for _, rr := range resp.Answer {
+			if ds, ok := rr.(*dns.DS); ok && strings.EqualFold(ds.Hdr.Name, zone) {
+				dsset = append(dsset, ds)
+			}
+		}
+		if len(dsset) == 0 {
+			// The parent zone decides if the absence of the DS records can be trusted
+			return v.deniedDelegation(ctx, zone, resp)
+		}
+		if status := v.verifyRRset(ctx, dsToRRs(dsset), rrsigs(resp.Answer), zone); status != DNSSECSecure {
+			return status, nil
+		}
+	}
+
+	resp, err := v.query(ctx, zone, dns.TypeDNSKEY)
+	if err != nil || resp.Rcode != dns.RcodeSuccess {
+		return DNSSECIndeterminate, nil
+	}
+
+	sigs := rrsigs(resp.Answer)
+	if zone == "." && len(sigs) == 0 {
+		// The resolvers are not providing the DNSSEC records
+		return DNSSECIndeterminate, nil
+	}
+
+	var keys []*dns.DNSKEY
+	for _, rr := range resp.Answer {
+		if key, ok := rr.(*dns.DNSKEY); ok && strings.EqualFold(key.Hdr.Name, zone) {
+			keys = append(keys, key)
+		}
+	}
+
+	var trusted []*dns.DNSKEY
+	for _, key := range keys {
+		if keyMatchesDS(key, dsset) {
+			trusted = append(trusted, key)
+		}
+	}
+	// The DNSKEY RRset must be signed by a key referenced from the parent zone
+	if len(trusted) == 0 || !verifySignatures(keysToRRs(keys), sigs, trusted) {
+		return DNSSECBogus, nil
+	}
+	return DNSSECSecure, keys
+}
+
+// Determines the status of a zone when the parent did not provide DS records.
+func (v *Validator) deniedDelegation(ctx context.Context, zone string, resp *dns.Msg) (string, []*dns.DNSKEY) {
+	sigs := rrsigs(resp.Ns)
+	if len(sigs) == 0 {
+		parent := parentZone(zone)
+		if status, _ := v.zoneStatus(ctx, parent); status == DNSSECSecure {
+			// A secure parent is required to sign the denial of existence
+			return DNSSECBogus, nil
+		}
+		return DNSSECInsecure, nil
+	}
+
+	status := DNSSECInsecure
+	for _, rrset := range rrsets(resp.Ns) {
+		if t := rrset[0].Header().Rrtype; t != dns.TypeNSEC && t != dns.TypeNSEC3 {
+			continue
+		}
+		if s := v.verifyRRset(ctx, rrset, sigs, zone); s == DNSSECBogus {
+			return DNSSECBogus, nil
+		}
+	}
+	return status, nil
+}
+
+// Returns the name of the zone containing the provided name using the SOA record.
+func (v *Validator) zoneOf(ctx context.Context, name string) (string, error) {
+	resp, err := v.query(ctx, name, dns.TypeSOA)
+	if err != nil {
+		return "", err
+	}
+
+	for _, rr := range append(resp.Answer, resp.Ns...) {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return strings.ToLower(soa.Hdr.Name), nil
+		}
+	}
+	return "", errors.New("failed to identify the zone containing the name")
+}
+
+func (v *Validator) insecureAncestor(name string) bool {
+	v.Lock()
+	defer v.Unlock()
+
+	labels := dns.SplitDomainName(name)
+	for i := range labels {
+		zone := dns.Fqdn(strings.Join(labels[i:], "."))
+
+		if _, found := v.insecure[zone]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// Sends the query with the DO bit set and checking disabled, so that bogus data is returned.
+func (v *Validator) query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.SetEdns0(dns.DefaultMsgSize, true)
+	msg.CheckingDisabled = true
+
+	var err error
+	var resp *dns.Msg
+	for i := 0; i < maxValidationAttempts; i++ {
+		resp, err = v.pool.QueryBlocking(ctx, msg)
+		if err == nil && resp != nil && (resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError) {
+			return resp, nil
+		}
+	}
+	if err == nil {
+		err = errors.New("the DNSSEC query failed")
+	}
+	return resp, err
+}
+
+func verifySignatures(rrset []dns.RR, sigs []*dns.RRSIG, keys []*dns.DNSKEY) bool {
+	now := time.Now()
+
+	for _, sig := range sigs {
+		if sig.TypeCovered != rrset[0].Header().Rrtype || !sig.ValidityPeriod(now) {
+			continue
+		}
+		for _, key := range keys {
+			if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+				continue
+			}
+			if err := sig.Verify(key, rrset); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func keyMatchesDS(key *dns.DNSKEY, dsset []*dns.DS) bool {
+	for _, ds := range dsset {
+		if key.KeyTag() != ds.KeyTag || key.Algorithm != ds.Algorithm {
+			continue
+		}
+		if d := key.ToDS(ds.DigestType); d != nil && strings.EqualFold(d.Digest, ds.Digest) {
+			return true
+		}
+	}
+	return false
+}
+
+func rrsigs(rrs []dns.RR) []*dns.RRSIG {
+	var sigs []*dns.RRSIG
+
+	for _, rr := range rrs {
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			sigs = append(sigs, sig)
+		}
+	}
+	return sigs
+}
+
+// Groups the records by owner name and type, excluding the signatures.
+func rrsets(rrs []dns.RR) [][]dns.RR {
+	var keys []string
+	sets := make(map[string][]dns.RR)
+
+	for _, rr := range rrs {
+		hdr := rr.Header()
+		if hdr.Rrtype == dns.TypeRRSIG {
+			continue
+		}
+
+		key := strings.ToLower(hdr.Name) + "/" + dns.TypeToString[hdr.Rrtype]
+		if _, found := sets[key]; !found {
+			keys = append(keys, key)
+		}
+		sets[key] = append(sets[key], rr)
+	}
+
+	var results [][]dns.RR
+	for _, key := range keys {
+		results = append(results, sets[key])
+	}
+	return results
+}
+
+func dsToRRs(dsset []*dns.DS) []dns.RR {
+	var rrs []dns.RR
+
+	for _, ds := range dsset {
+		rrs = append(rrs, ds)
+	}
+	return rrs
+}
+
+func keysToRRs(keys []*dns.DNSKEY) []dns.RR {
+	var rrs []dns.RR
+
+	for _, key := range keys {
+		rrs = append(rrs, key)
+	}
+	return rrs
+}
+
+func parentZone(zone string) string {
+	labels := dns.SplitDomainName(zone)
+	if len(labels) <= 1 {
+		return "."
+	}
+	return dns.Fqdn(strings.Join(labels[1:], "."))
+}
@@ -0,0 +1,158 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"crypto"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+type signedZone struct {
+	name string
+	key  *dns.DNSKEY
+	priv crypto.Signer
+}
+
+func newSignedZone(t *testing.T, name string) *signedZone {
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: name, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+
+	priv, err := key.Generate(256)
+	if err != nil {
+		t.Fatalf("Failed to generate the key for %s: %v", name, err)
+	}
+	return &signedZone{name: name, key: key, priv: priv.(crypto.Signer)}
+}
+
+func (z *signedZone) sign(t *testing.T, rrset []dns.RR) *dns.RRSIG {
+	hdr := rrset[0].Header()
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Name: hdr.Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: hdr.Ttl},
+		Inception:  uint32(time.Now().Add(-time.Hour).Unix()),
+		Expiration: uint32(time.Now().Add(time.Hour).Unix()),
+		KeyTag:     z.key.KeyTag(),
+		SignerName: z.name,
+		Algorithm:  z.key.Algorithm,
+	}
+
+	if err := sig.Sign(z.priv, rrset); err != nil {
+		t.Fatalf("Failed to sign the %s RRset: %v", hdr.Name, err)
+	}
+	return sig
+}
+
+func aRecord(name, addr string) *dns.A {
+	return &dns.A{
+		Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+		A:   net.ParseIP(addr),
+	}
+}
+
+// Builds the answers for a small signed hierarchy: the root zone, a signed zone and an unsigned zone.
+func dnssecTestServer(t *testing.T) (string, *dns.DS) {
+	root := newSignedZone(t, ".")
+	signed := newSignedZone(t, "signed.")
+
+	answers := make(map[string][]dns.RR)
+	authority := make(map[string][]dns.RR)
+	add := func(z *signedZone, key string, section map[string][]dns.RR, rrs ...dns.RR) {
+		section[key] = append(rrs, z.sign(t, rrs))
+	}
+
+	add(root, "./DNSKEY", answers, root.key)
+	add(signed, "signed./DNSKEY", answers, signed.key)
+	add(root, "signed./DS", answers, signed.key.ToDS(dns.SHA256))
+	add(signed, "www.signed./A", answers, aRecord("www.signed.", "192.168.1.1"))
+	// The signature will not match the tampered address
+	bogus := aRecord("bogus.signed.", "192.168.1.2")
+	answers["bogus.signed./A"] = []dns.RR{bogus, signed.sign(t, []dns.RR{aRecord("bogus.signed.", "192.168.1.3")})}
+	answers["nosig.signed./A"] = []dns.RR{aRecord("nosig.signed.", "192.168.1.4")}
+	answers["nosig.signed./SOA"] = []dns.RR{&dns.SOA{
+		Hdr: dns.RR_Header{Name: "signed.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 300},
+		Ns:  "ns.signed.", Mbox: "admin.signed.", Minttl: 300,
+	}}
+	// The unsigned zone has an authenticated denial of the DS records
+	add(root, "unsigned./DS", authority, &dns.NSEC{
+		Hdr:        dns.RR_Header{Name: "unsigned.", Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 300},
+		NextDomain: "zzz.",
+		TypeBitMap: []uint16{dns.TypeNS, dns.TypeRRSIG, dns.TypeNSEC},
+	})
+	answers["www.unsigned./A"] = []dns.RR{aRecord("www.unsigned.", "192.168.2.1")}
+	answers["www.unsigned./SOA"] = []dns.RR{&dns.SOA{
+		Hdr: dns.RR_Header{Name: "unsigned.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 300},
+		Ns:  "ns.unsigned.", Mbox: "admin.unsigned.", Minttl: 300,
+	}}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen for the DNS test server: %v", err)
+	}
+
+	srv := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			q := req.Question[0]
+			key := strings.ToLower(q.Name) + "/" + dns.TypeToString[q.Qtype]
+
+			resp := new(dns.Msg)
+			resp.SetReply(req)
+			resp.Answer = answers[key]
+			resp.Ns = authority[key]
+			if key == "nosig.signed./SOA" || key == "www.unsigned./SOA" {
+				resp.Ns, resp.Answer = resp.Answer, nil
+			}
+			_ = w.WriteMsg(resp)
+		}),
+	}
+
+	started := make(chan struct{})
+	srv.NotifyStartedFunc = func() { close(started) }
+	go func() { _ = srv.ActivateAndServe() }()
+	<-started
+	t.Cleanup(func() { _ = srv.Shutdown() })
+
+	return pc.LocalAddr().String(), root.key.ToDS(dns.SHA256)
+}
+
+func TestValidate(t *testing.T) {
+	addr, anchor := dnssecTestServer(t)
+
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(100, addr)
+
+	v := NewValidator(p)
+	v.anchors = []*dns.DS{anchor}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{"www.signed", DNSSECSecure},
+		{"bogus.signed", DNSSECBogus},
+		{"nosig.signed", DNSSECBogus},
+		{"www.unsigned", DNSSECInsecure},
+		{"missing.signed", DNSSECIndeterminate},
+	}
+
+	for _, c := range cases {
+		if got := v.Validate(ctx, c.name, dns.TypeA); got != c.expected {
+			t.Errorf("%s: got %s, expected %s", c.name, got, c.expected)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	dohMediaType      = "application/dns-message"
+	defaultDoHTimeout = 5 * time.Second
+	maxDoHRespLen     = 65535
+)
+
+// DoHResolver sends DNS queries to a DNS-over-HTTPS endpoint, as described in RFC 8484.
+type DoHResolver struct {
+	sync.Mutex
+	endpoint string
+	client   *http.Client
+	timeout  time.Duration
+}
+
+// NewDoHResolver returns a DoHResolver for the provided endpoint, such as https://cloudflare-dns.com/dns-query.
+func NewDoHResolver(endpoint string) (*DoHResolver, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("%s is not a valid DNS-over-HTTPS endpoint", endpoint)
+	}
+	if u.Path == "" {
+		u.Path = "/dns-query"
+	}
+
+	return &DoHResolver{
+		endpoint: u.String(),
+		timeout:  defaultDoHTimeout,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   10 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				ForceAttemptHTTP2:   true,
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 100,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+				TLSClientConfig: &tls.Config{
+					MinVersion:         tls.VersionTLS12,
+					ClientSessionCache: tls.NewLRUClientSessionCache(0),
+				},
+			},
+		},
+	}, nil
+}
+
+// String implements the Resolver interface.
+func (r *DoHResolver) String() string {
+	return r.endpoint
+}
+
+// SetTimeout implements the Resolver interface.
+func (r *DoHResolver) SetTimeout(d time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.timeout = d
+}
+
+// Stop implements the Resolver interface.
+func (r *DoHResolver) Stop() {
+	r.client.CloseIdleConnections()
+}
+
+// Exchange implements the Resolver interface.
+func (r *DoHResolver) Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	if msg == nil || len(msg.Question) == 0 {
+		return nil, errors.New("the DNS message did not contain a question")
+	}
+
+	r.Lock()
+	timeout := r.timeout
+	r.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// The message ID should be zero to make the responses cache friendly
+	query := msg.Copy()
+	query.Id = 0
+	data, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dohMediaType)
+	req.Header.Set("Accept", dohMediaType)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%d: %s", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDoHRespLen))
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(dns.Msg)
+	if err := m.Unpack(body); err != nil {
+		return nil, err
+	}
+	// Restore the ID expected by the caller
+	m.Id = msg.Id
+	return m, nil
+}
@@ -0,0 +1,120 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+func dohTestServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != dohMediaType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		req := new(dns.Msg)
+		if err := req.Unpack(body); err != nil || req.Id != 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Answer = append(resp.Answer, &dns.A{
+			Hdr: dns.RR_Header{
+				Name:   req.Question[0].Name,
+				Rrtype: dns.TypeA,
+				Class:  dns.ClassINET,
+				Ttl:    60,
+			},
+			A: net.ParseIP("192.168.1.1"),
+		})
+
+		data, _ := resp.Pack()
+		w.Header().Set("Content-Type", dohMediaType)
+		_, _ = w.Write(data)
+	}))
+}
+
+func TestNewDoHResolver(t *testing.T) {
+	if _, err := NewDoHResolver("http://example.com/dns-query"); err == nil {
+		t.Errorf("Failed to reject an endpoint without the https scheme")
+	}
+
+	r, err := NewDoHResolver("https://cloudflare-dns.com")
+	if err != nil {
+		t.Fatalf("Failed to create the DoH resolver: %v", err)
+	}
+	if r.String() != "https://cloudflare-dns.com/dns-query" {
+		t.Errorf("Failed to add the default path to the endpoint: %s", r.String())
+	}
+}
+
+func TestDoHExchange(t *testing.T) {
+	srv := dohTestServer()
+	defer srv.Close()
+
+	r, err := NewDoHResolver(srv.URL + "/dns-query")
+	if err != nil {
+		t.Fatalf("Failed to create the DoH resolver: %v", err)
+	}
+	defer r.Stop()
+	r.client = srv.Client()
+
+	msg := resolve.QueryMsg("www.example.com", dns.TypeA)
+	resp, err := r.Exchange(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("The DoH exchange failed: %v", err)
+	}
+	if resp.Id != msg.Id {
+		t.Errorf("Failed to restore the message ID: got %d, expected %d", resp.Id, msg.Id)
+	}
+	if ans := resolve.ExtractAnswers(resp); len(ans) != 1 || ans[0].Data != "192.168.1.1" {
+		t.Errorf("Failed to obtain the correct answers from the DoH response")
+	}
+}
+
+func TestPoolDoHQuery(t *testing.T) {
+	srv := dohTestServer()
+	defer srv.Close()
+
+	p := NewPool()
+	defer p.Stop()
+
+	if err := p.AddResolvers(10, srv.URL+"/dns-query"); err != nil {
+		t.Fatalf("Failed to add the DoH resolver: %v", err)
+	}
+	if p.Len() != 1 || p.QPS() != 10 {
+		t.Fatalf("The pool did not account for the DoH resolver")
+	}
+	p.list[0].res.(*DoHResolver).client = srv.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := p.QueryBlocking(ctx, resolve.QueryMsg("www.example.com", dns.TypeA))
+	if err != nil || resp.Rcode != dns.RcodeSuccess {
+		t.Fatalf("The query through the pool failed")
+	}
+	if ans := resolve.ExtractAnswers(resp); len(ans) != 1 || ans[0].Data != "192.168.1.1" {
+		t.Errorf("Failed to obtain the correct answers through the pool")
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	defaultDoTPort    = "853"
+	defaultDoTTimeout = 5 * time.Second
+	maxIdleDoTConns   = 4
+)
+
+// DoTResolver sends DNS queries to a DNS-over-TLS server, as described in RFC 7858.
+// Connections are kept open for reuse and TLS sessions are resumed when reconnecting.
+type DoTResolver struct {
+	sync.Mutex
+	address string
+	client  *dns.Client
+	idle    chan *dns.Conn
+	done    chan struct{}
+	timeout time.Duration
+}
+
+// NewDoTResolver returns a DoTResolver for the provided address, such as tls://1.1.1.1 or
+// tls://dns.google:853. The server name used to verify the certificate can be provided
+// with the servername query parameter, as in tls://1.1.1.1?servername=cloudflare-dns.com.
+func NewDoTResolver(addr string) (*DoTResolver, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "tls" || u.Hostname() == "" {
+		return nil, fmt.Errorf("%s is not a valid DNS-over-TLS address", addr)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultDoTPort
+	}
+
+	servername := u.Query().Get("servername")
+	if servername == "" {
+		servername = u.Hostname()
+	}
+
+	return &DoTResolver{
+		address: net.JoinHostPort(u.Hostname(), port),
+		client: &dns.Client{
+			Net: "tcp-tls",
+			TLSConfig: &tls.Config{
+				ServerName:         servername,
+				MinVersion:         tls.VersionTLS12,
+				ClientSessionCache: tls.NewLRUClientSessionCache(maxIdleDoTConns),
+			},
+			Timeout: defaultDoTTimeout,
+		},
+		idle:    make(chan *dns.Conn, maxIdleDoTConns),
+		done:    make(chan struct{}),
+		timeout: defaultDoTTimeout,
+	}, nil
+}
+
+// String implements the Resolver interface.
+func (r *DoTResolver) String() string {
+	return "tls://" + r.address
+}
+
+// SetTimeout implements the Resolver interface.
+func (r *DoTResolver) SetTimeout(d time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.timeout = d
+}
+
+// Stop implements the Resolver interface.
+func (r *DoTResolver) Stop() {
+	r.Lock()
+	defer r.Unlock()
+
+	select {
+	case <-r.done:
+		return
+	default:
+	}
+
+	close(r.done)
+	for {
+		select {
+		case conn := <-r.idle:
+			_ = conn.Close()
+		default:
+			return
+		}
+	}
+}
+
+// Exchange implements the Resolver interface.
+func (r *DoTResolver) Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	if msg == nil || len(msg.Question) == 0 {
+		return nil, errors.New("the DNS message did not contain a question")
+	}
+
+	r.Lock()
+	timeout := r.timeout
+	r.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, reused, err := r.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.exchangeWithConn(ctx, conn, msg)
+	if err != nil && reused {
+		// The server may have closed the idle connection, so try once more using a new one
+		_ = conn.Close()
+		conn, err = r.client.DialContext(ctx, r.address)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = r.exchangeWithConn(ctx, conn, msg)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	r.putConn(conn)
+	return resp, nil
+}
+
+func (r *DoTResolver) exchangeWithConn(ctx context.Context, conn *dns.Conn, msg *dns.Msg) (*dns.Msg, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := conn.WriteMsg(msg); err != nil {
+		return nil, err
+	}
+
+	resp, err := conn.ReadMsg()
+	if err != nil {
+		return nil, err
+	}
+	if resp.Id != msg.Id {
+		return nil, errors.New("the DNS response message ID did not match the query")
+	}
+	return resp, nil
+}
+
+// Returns an idle connection to the server, or establishes a new one.
+func (r *DoTResolver) getConn(ctx context.Context) (*dns.Conn, bool, error) {
+	select {
+	case <-r.done:
+		return nil, false, errors.New("the resolver has been stopped")
+	case conn := <-r.idle:
+		return conn, true, nil
+	default:
+	}
+
+	conn, err := r.client.DialContext(ctx, r.address)
+	return conn, false, err
+}
+
+// Keeps the connection open for reuse, unless enough idle connections are already available.
+func (r *DoTResolver) putConn(conn *dns.Conn) {
+	r.Lock()
+	defer r.Unlock()
+
+	select {
+	case <-r.done:
+		_ = conn.Close()
+		return
+	default:
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	select {
+	case r.idle <- conn:
+	default:
+		_ = conn.Close()
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+type countingListener struct {
+	net.Listener
+	sync.Mutex
+	accepted int
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.Lock()
+		l.accepted++
+		l.Unlock()
+	}
+	return conn, err
+}
+
+func (l *countingListener) count() int {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.accepted
+}
+
+func selfSignedCert(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate the key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create the certificate: %v", err)
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func dotTestServer(t *testing.T) (*dns.Server, *countingListener) {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{selfSignedCert(t)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to listen for the DoT test server: %v", err)
+	}
+
+	cl := &countingListener{Listener: l}
+	srv := &dns.Server{
+		Listener: cl,
+		Net:      "tcp-tls",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			resp := new(dns.Msg)
+			resp.SetReply(req)
+			resp.Answer = append(resp.Answer, &dns.A{
+				Hdr: dns.RR_Header{
+					Name:   req.Question[0].Name,
+					Rrtype: dns.TypeA,
+					Class:  dns.ClassINET,
+					Ttl:    60,
+				},
+				A: net.ParseIP("192.168.1.1"),
+			})
+			_ = w.WriteMsg(resp)
+		}),
+	}
+
+	started := make(chan struct{})
+	srv.NotifyStartedFunc = func() { close(started) }
+	go func() { _ = srv.ActivateAndServe() }()
+	<-started
+	return srv, cl
+}
+
+func TestNewDoTResolver(t *testing.T) {
+	if _, err := NewDoTResolver("1.1.1.1"); err == nil {
+		t.Errorf("Failed to reject an address without the tls scheme")
+	}
+
+	r, err := NewDoTResolver("tls://1.1.1.1?servername=cloudflare-dns.com")
+	if err != nil {
+		t.Fatalf("Failed to create the DoT resolver: %v", err)
+	}
+	if r.String() != "tls://1.1.1.1:853" {
+		t.Errorf("Failed to add the default port to the address: %s", r.String())
+	}
+	if r.client.TLSConfig.ServerName != "cloudflare-dns.com" {
+		t.Errorf("Failed to assign the server name: %s", r.client.TLSConfig.ServerName)
+	}
+}
+
+func TestDoTExchangeReusesConnections(t *testing.T) {
+	srv, l := dotTestServer(t)
+	defer func() { _ = srv.Shutdown() }()
+
+	r, err := NewDoTResolver("tls://" + l.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to create the DoT resolver: %v", err)
+	}
+	defer r.Stop()
+	r.client.TLSConfig.InsecureSkipVerify = true
+
+	for i := 0; i < 3; i++ {
+		msg := resolve.QueryMsg("www.example.com", dns.TypeA)
+
+		resp, err := r.Exchange(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("The DoT exchange failed: %v", err)
+		}
+		if ans := resolve.ExtractAnswers(resp); len(ans) != 1 || ans[0].Data != "192.168.1.1" {
+			t.Errorf("Failed to obtain the correct answers from the DoT response")
+		}
+	}
+	if c := l.count(); c != 1 {
+		t.Errorf("The DoT resolver opened %d connections instead of reusing one", c)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+
+	"github.com/miekg/dns"
+)
+
+// EDNS0Options contains the EDNS0 settings applied to the queries sent by a Pool.
+type EDNS0Options struct {
+	// BufferSize is the advertised UDP payload size. Zero keeps the default size
+	BufferSize uint16
+
+	// Cookies enables the client cookies described in RFC 7873
+	Cookies bool
+
+	// ClientSubnet replaces the client subnet sent with the queries. A nil value keeps
+	// the default subnet, which does not disclose the location of the client
+	ClientSubnet *net.IPNet
+
+	// DisableClientSubnet removes the client subnet option from the queries
+	DisableClientSubnet bool
+}
+
+// ParseClientSubnet returns the network for a client subnet setting, such as 192.0.2.0/24.
+func ParseClientSubnet(subnet string) (*net.IPNet, error) {
+	ip, ipnet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid client subnet: %v", subnet, err)
+	}
+
+	ipnet.IP = ip.Mask(ipnet.Mask)
+	return ipnet, nil
+}
+
+// SetEDNS0Options assigns the EDNS0 settings applied to the queries sent by the pool.
+func (p *Pool) SetEDNS0Options(opts *EDNS0Options) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.edns0 = opts
+	if opts != nil && opts.Cookies && p.cookie == "" {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err == nil {
+			p.cookie = hex.EncodeToString(b)
+		}
+	}
+}
+
+// Returns a copy of the message using the EDNS0 settings of the pool.
+func (p *Pool) applyEDNS0(msg *dns.Msg) *dns.Msg {
+	p.Lock()
+	opts := p.edns0
+	cookie := p.cookie
+	p.Unlock()
+
+	if opts == nil {
+		return msg
+	}
+
+	m := msg.Copy()
+	opt := m.IsEdns0()
+	if opt == nil {
+		m.SetEdns0(dns.DefaultMsgSize, false)
+		opt = m.IsEdns0()
+	}
+	if opts.BufferSize > 0 {
+		opt.SetUDPSize(opts.BufferSize)
+	}
+
+	var options []dns.EDNS0
+	for _, o := range opt.Option {
+		switch o.Option() {
+		case dns.EDNS0SUBNET:
+			if opts.ClientSubnet != nil || opts.DisableClientSubnet {
+				continue
+			}
+		case dns.EDNS0COOKIE:
+			if opts.Cookies {
+				continue
+			}
+		}
+		options = append(options, o)
+	}
+
+	if subnet := opts.ClientSubnet; subnet != nil && !opts.DisableClientSubnet {
+		ones, _ := subnet.Mask.Size()
+		ecs := &dns.EDNS0_SUBNET{
+			Code:          dns.EDNS0SUBNET,
+			Family:        1,
+			SourceNetmask: uint8(ones),
+			Address:       subnet.IP.To4(),
+		}
+		if ecs.Address == nil {
+			ecs.Family = 2
+			ecs.Address = subnet.IP.To16()
+		}
+		options = append(options, ecs)
+	}
+	if opts.Cookies && cookie != "" {
+		options = append(options, &dns.EDNS0_COOKIE{
+			Code:   dns.EDNS0COOKIE,
+			Cookie: cookie,
+		})
+	}
+
+	opt.Option = options
+	return m
+}
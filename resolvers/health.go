@@ -0,0 +1,326 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	healthCheckInterval = 10 * time.Second
+	// Number of health check intervals between the tests of the evicted resolvers
+	retestIntervals = 3
+	// Number of health check intervals between the poisoning checks of the active resolvers
+	poisonCheckIntervals = 6
+	healthProbeTimeout   = 10 * time.Second
+)
+
+// HealthOptions contains the settings used to evict resolvers that perform poorly during an enumeration.
+type HealthOptions struct {
+	// MinSamples is the number of queries required before the rates of a resolver are evaluated
+	MinSamples int
+
+	// MaxTimeoutRate is the largest acceptable fraction of queries not receiving a response
+	MaxTimeoutRate float64
+
+	// MaxServFailRate is the largest acceptable fraction of queries receiving a SERVFAIL response
+	MaxServFailRate float64
+
+	// MaxRefusedRate is the largest acceptable fraction of queries being refused
+	MaxRefusedRate float64
+
+	// MaxPoisonRate is the largest acceptable fraction of responses answering a question other than the one asked
+	MaxPoisonRate float64
+
+	// DetectPoisoning enables the checks for resolvers providing answers for names that do not exist
+	DetectPoisoning bool
+}
+
+// DefaultHealthOptions returns the settings used to evict resolvers from the untrusted pools.
+func DefaultHealthOptions() *HealthOptions {
+	return &HealthOptions{
+		MinSamples:      25,
+		MaxTimeoutRate:  0.5,
+		MaxServFailRate: 0.5,
+		MaxRefusedRate:  0.25,
+		MaxPoisonRate:   0.05,
+		DetectPoisoning: true,
+	}
+}
+
+type healthStats struct {
+	sync.Mutex
+	queries   int
+	timeouts  int
+	servfails int
+	refused   int
+	poisoned  int
+	// The queries and SERVFAIL responses of each zone, so failures shared by the pool can be excluded
+	zones map[string]*zoneStats
+}
+
+type zoneStats struct {
+	queries   int
+	servfails int
+}
+
+func (s *healthStats) record(msg, resp *dns.Msg, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.zones == nil {
+		s.zones = make(map[string]*zoneStats)
+	}
+	zone := rrlZoneName(msg)
+	z, found := s.zones[zone]
+	if !found {
+		z = new(zoneStats)
+		s.zones[zone] = z
+	}
+
+	s.queries++
+	z.queries++
+	if err != nil || resp == nil {
+		s.timeouts++
+		return
+	}
+
+	switch resp.Rcode {
+	case dns.RcodeServerFailure:
+		s.servfails++
+		z.servfails++
+	case dns.RcodeRefused:
+		s.refused++
+	}
+	// Answers for a question other than the one asked indicate a poisoned response
+	if len(resp.Question) != len(msg.Question) || (len(msg.Question) > 0 &&
+		(resp.Question[0].Qtype != msg.Question[0].Qtype ||
+			!strings.EqualFold(resp.Question[0].Name, msg.Question[0].Name))) {
+		s.poisoned++
+	}
+}
+
+// Returns the zones that received SERVFAIL responses for the queries sent to the resolver.
+func (s *healthStats) servfailZones() map[string]bool {
+	s.Lock()
+	defer s.Unlock()
+
+	zones := make(map[string]bool, len(s.zones))
+	for zone, z := range s.zones {
+		zones[zone] = z.servfails > 0
+	}
+	return zones
+}
+
+// Returns the reason for evicting the resolver, or an empty string, and resets the statistics.
+// The SERVFAIL responses for the shared zones are excluded, since they indicate a broken zone.
+func (s *healthStats) evaluate(opts *HealthOptions, shared map[string]bool) string {
+	s.Lock()
+	defer s.Unlock()
+
+	defer func() {
+		s.queries, s.timeouts, s.servfails, s.refused, s.poisoned = 0, 0, 0, 0, 0
+		s.zones = nil
+	}()
+
+	queries, servfails := s.queries, s.servfails
+	for zone, z := range s.zones {
+		if shared[zone] {
+			queries -= z.servfails
+			servfails -= z.servfails
+		}
+	}
+	if queries <= 0 || queries < opts.MinSamples {
+		return ""
+	}
+
+	total := float64(queries)
+	if rate := float64(s.poisoned) / total; rate > opts.MaxPoisonRate {
+		return fmt.Sprintf("%.0f%% of the answers were poisoned", rate*100)
+	}
+	if rate := float64(s.timeouts) / total; rate > opts.MaxTimeoutRate {
+		return fmt.Sprintf("%.0f%% of the queries timed out", rate*100)
+	}
+	if rate := float64(servfails) / total; rate > opts.MaxServFailRate {
+		return fmt.Sprintf("%.0f%% of the queries received SERVFAIL", rate*100)
+	}
+	if rate := float64(s.refused) / total; rate > opts.MaxRefusedRate {
+		return fmt.Sprintf("%.0f%% of the queries were refused", rate*100)
+	}
+	return ""
+}
+
+// SetHealthOptions assigns the settings used to evict and reinstate resolvers. A nil value disables the eviction.
+func (p *Pool) SetHealthOptions(opts *HealthOptions) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.health = opts
+	if opts == nil {
+		for _, m := range p.list {
+			m.evicted = false
+		}
+	}
+}
+
+// Evicted returns the addresses of the resolvers currently evicted from the pool.
+func (p *Pool) Evicted() []string {
+	p.Lock()
+	defer p.Unlock()
+
+	var addrs []string
+	for _, m := range p.list {
+		if m.evicted {
+			addrs = append(addrs, m.res.String())
+		}
+	}
+	return addrs
+}
+
+func (p *Pool) healthChecks() {
+	t := time.NewTicker(healthCheckInterval)
+	defer t.Stop()
+
+	for i := 1; ; i++ {
+		select {
+		case <-p.done:
+			return
+		case <-t.C:
+		}
+
+		p.Lock()
+		opts := p.health
+		p.Unlock()
+		if opts == nil {
+			continue
+		}
+
+		p.checkHealth(opts, i%poisonCheckIntervals == 0)
+		if i%retestIntervals == 0 {
+			p.retestEvicted(opts)
+		}
+	}
+}
+
+// Evaluates the statistics of the active resolvers and evicts those performing poorly.
+func (p *Pool) checkHealth(opts *HealthOptions, poisonChecks bool) {
+	p.Lock()
+	var active []*member
+	for _, m := range p.list {
+		if !m.evicted {
+			active = append(active, m)
+		}
+	}
+	p.Unlock()
+
+	shared := sharedServFailZones(active)
+	for _, m := range active {
+		reason := m.stats.evaluate(opts, shared)
+		if reason == "" && poisonChecks && opts.DetectPoisoning && p.answersNonexistentNames(m) {
+			reason = "answers were provided for names that do not exist"
+		}
+		if reason != "" {
+			p.evict(m, reason)
+		}
+	}
+}
+
+// Returns the zones that received SERVFAIL responses from every resolver queried for them, when
+// more than one resolver was queried. These zones are broken, rather than the resolvers.
+func sharedServFailZones(active []*member) map[string]bool {
+	queried := make(map[string]int)
+	failed := make(map[string]int)
+	for _, m := range active {
+		for zone, servfail := range m.stats.servfailZones() {
+			queried[zone]++
+			if servfail {
+				failed[zone]++
+			}
+		}
+	}
+
+	shared := make(map[string]bool)
+	for zone, num := range queried {
+		if num > 1 && failed[zone] == num {
+			shared[zone] = true
+		}
+	}
+	return shared
+}
+
+func (p *Pool) evict(m *member, reason string) {
+	p.Lock()
+	defer p.Unlock()
+
+	var active int
+	for _, cur := range p.list {
+		if !cur.evicted {
+			active++
+		}
+	}
+	// The last active resolver is never evicted
+	if m.evicted || active <= 1 {
+		return
+	}
+
+	m.evicted = true
+	p.log.Printf("Resolver %s has been evicted: %s", m.res.String(), reason)
+}
+
+// Tests the evicted resolvers and reinstates those providing correct responses.
+func (p *Pool) retestEvicted(opts *HealthOptions) {
+	p.Lock()
+	var evicted []*member
+	for _, m := range p.list {
+		if m.evicted {
+			evicted = append(evicted, m)
+		}
+	}
+	p.Unlock()
+
+	for _, m := range evicted {
+		if !p.healthy(m) || (opts.DetectPoisoning && p.answersNonexistentNames(m)) {
+			continue
+		}
+
+		_ = m.stats.evaluate(opts, nil)
+		p.Lock()
+		m.evicted = false
+		p.Unlock()
+		p.log.Printf("Resolver %s has been reinstated", m.res.String())
+	}
+}
+
+// Checks that the resolver is able to provide the name servers for the root zone.
+func (p *Pool) healthy(m *member) bool {
+	resp, err := p.probe(m, ".", dns.TypeNS)
+
+	return err == nil && resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0
+}
+
+// Checks if the resolver provides answers for a name that cannot exist.
+func (p *Pool) answersNonexistentNames(m *member) bool {
+	resp, err := p.probe(m, randomLabel()+".com", dns.TypeA)
+
+	return err == nil && resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0
+}
+
+func (p *Pool) probe(m *member, name string, qtype uint16) (*dns.Msg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthProbeTimeout)
+	defer cancel()
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.SetEdns0(dns.DefaultMsgSize, false)
+
+	m.rate.Take()
+	return m.res.Exchange(ctx, msg)
+}
@@ -0,0 +1,171 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+// Starts a DNS server that answers the root NS query, provides NXDOMAIN for other names,
+// and responds with the rcode returned by the provided function when it is not zero.
+func healthTestServer(t *testing.T, rcode func() int, hijack bool) string {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen for the DNS test server: %v", err)
+	}
+
+	srv := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			q := req.Question[0]
+			resp := new(dns.Msg)
+			resp.SetReply(req)
+
+			switch {
+			case rcode() != dns.RcodeSuccess:
+				resp.Rcode = rcode()
+			case q.Name == "." && q.Qtype == dns.TypeNS:
+				resp.Answer = append(resp.Answer, &dns.NS{
+					Hdr: dns.RR_Header{Name: ".", Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 300},
+					Ns:  "a.root-servers.net.",
+				})
+			case hijack:
+				resp.Answer = append(resp.Answer, aRecord(q.Name, "192.168.1.1"))
+			default:
+				resp.Rcode = dns.RcodeNameError
+			}
+			_ = w.WriteMsg(resp)
+		}),
+	}
+
+	started := make(chan struct{})
+	srv.NotifyStartedFunc = func() { close(started) }
+	go func() { _ = srv.ActivateAndServe() }()
+	<-started
+	t.Cleanup(func() { _ = srv.Shutdown() })
+
+	return pc.LocalAddr().String()
+}
+
+func TestPoolEvictionAndReinstatement(t *testing.T) {
+	var failing int32 = 1
+	success := func() int { return dns.RcodeSuccess }
+	good := healthTestServer(t, success, false)
+	bad := healthTestServer(t, func() int {
+		if atomic.LoadInt32(&failing) == 1 {
+			return dns.RcodeServerFailure
+		}
+		return dns.RcodeSuccess
+	}, false)
+	hijacker := healthTestServer(t, success, true)
+
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(1000, good, bad, hijacker)
+
+	opts := DefaultHealthOptions()
+	opts.MinSamples = 5
+	p.SetHealthOptions(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for i := 0; i < 100; i++ {
+		_, _ = p.QueryBlocking(ctx, resolve.QueryMsg("www.example.com", dns.TypeA))
+	}
+
+	p.checkHealth(opts, true)
+	if evicted := p.Evicted(); len(evicted) != 2 {
+		t.Fatalf("Got %v evicted, expected %s and %s", evicted, bad, hijacker)
+	}
+
+	// The failing resolver has recovered, while the other continues to answer nonexistent names
+	atomic.StoreInt32(&failing, 0)
+	p.retestEvicted(opts)
+	if evicted := p.Evicted(); len(evicted) != 1 || evicted[0] != hijacker {
+		t.Errorf("Got %v evicted, expected only %s", evicted, hijacker)
+	}
+
+	for i := 0; i < 20; i++ {
+		resp, err := p.QueryBlocking(ctx, resolve.QueryMsg("www.example.com", dns.TypeA))
+		if err != nil || len(resp.Answer) > 0 {
+			t.Fatalf("The evicted resolver was selected for a query")
+		}
+	}
+}
+
+func TestHealthStatsPoisoning(t *testing.T) {
+	opts := DefaultHealthOptions()
+	opts.MinSamples = 20
+	msg := resolve.QueryMsg("www.example.com", dns.TypeA)
+	poisoned := new(dns.Msg)
+	poisoned.SetReply(resolve.QueryMsg("www.example.org", dns.TypeA))
+
+	s := new(healthStats)
+	s.record(msg, poisoned, nil)
+	if reason := s.evaluate(opts, nil); reason != "" {
+		t.Errorf("A single poisoned answer caused the eviction before the minimum samples: %s", reason)
+	}
+
+	s.record(msg, poisoned, nil)
+	for i := 0; i < opts.MinSamples; i++ {
+		resp := new(dns.Msg)
+		s.record(msg, resp.SetReply(msg), nil)
+	}
+	if reason := s.evaluate(opts, nil); reason != "" {
+		t.Errorf("A poisoned answer below the maximum rate caused the eviction: %s", reason)
+	}
+
+	for i := 0; i < opts.MinSamples; i++ {
+		s.record(msg, poisoned, nil)
+	}
+	if reason := s.evaluate(opts, nil); reason == "" {
+		t.Errorf("The poisoned answers above the maximum rate did not cause the eviction")
+	}
+}
+
+func TestSharedServFailZones(t *testing.T) {
+	opts := DefaultHealthOptions()
+	opts.MinSamples = 10
+	servfail := func(name string) (*dns.Msg, *dns.Msg, error) {
+		msg := resolve.QueryMsg(name, dns.TypeA)
+		resp := new(dns.Msg)
+		return msg, resp.SetRcode(msg, dns.RcodeServerFailure), nil
+	}
+	success := func(name string) (*dns.Msg, *dns.Msg, error) {
+		msg := resolve.QueryMsg(name, dns.TypeA)
+		resp := new(dns.Msg)
+		return msg, resp.SetReply(msg), nil
+	}
+
+	first := &member{stats: new(healthStats)}
+	second := &member{stats: new(healthStats)}
+	for i := 0; i < opts.MinSamples; i++ {
+		// Both resolvers fail to resolve the names in the broken zone
+		first.stats.record(servfail("www.broken.com"))
+		second.stats.record(servfail("mail.broken.com"))
+		// Only the second resolver fails to resolve the names in the healthy zone
+		first.stats.record(success("www.example.com"))
+		second.stats.record(servfail("www.example.com"))
+	}
+
+	shared := sharedServFailZones([]*member{first, second})
+	if len(shared) != 1 || !shared["broken.com"] {
+		t.Fatalf("Got %v shared zones, expected only broken.com", shared)
+	}
+	if reason := first.stats.evaluate(opts, shared); reason != "" {
+		t.Errorf("The SERVFAIL responses for the broken zone caused the eviction: %s", reason)
+	}
+	if reason := second.stats.evaluate(opts, shared); reason == "" {
+		t.Errorf("The SERVFAIL responses for the healthy zone did not cause the eviction")
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// RFC 2308 recommends that negative answers are not cached for longer than three hours
+	maxNegativeTTL     = 3 * time.Hour
+	negCachePurgeDelay = time.Minute
+)
+
+// negativeCache remembers the names that do not exist (NXDOMAIN) and the names without records
+// of a type (NODATA) for the negative caching TTL provided by the SOA record of the zone.
+type negativeCache struct {
+	sync.Mutex
+	entries map[string]*negativeEntry
+}
+
+type negativeEntry struct {
+	rcode   int
+	soa     *dns.SOA
+	expires time.Time
+}
+
+func newNegativeCache() *negativeCache {
+	return &negativeCache{entries: make(map[string]*negativeEntry)}
+}
+
+// SetNegativeCaching enables or disables the caching of NXDOMAIN and NODATA responses.
+func (p *Pool) SetNegativeCaching(enabled bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	if !enabled {
+		p.negative = nil
+	} else if p.negative == nil {
+		p.negative = newNegativeCache()
+	}
+}
+
+func (p *Pool) getNegativeCache() *negativeCache {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.negative
+}
+
+func (p *Pool) negativeCachePurges() {
+	t := time.NewTicker(negCachePurgeDelay)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-t.C:
+		}
+
+		if c := p.getNegativeCache(); c != nil {
+			c.purge()
+		}
+	}
+}
+
+// Returns a response built from the cache when the name of the message is known not to exist,
+// or to have no records of the requested type.
+func (c *negativeCache) lookup(msg *dns.Msg) *dns.Msg {
+	if !cacheable(msg) {
+		return nil
+	}
+
+	q := msg.Question[0]
+	now := time.Now()
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, key := range []string{cacheKey(q.Name, 0), cacheKey(q.Name, q.Qtype)} {
+		e, found := c.entries[key]
+		if !found {
+			continue
+		}
+		if now.After(e.expires) {
+			delete(c.entries, key)
+			continue
+		}
+
+		resp := new(dns.Msg)
+		resp.SetReply(msg)
+		resp.RecursionAvailable = true
+		resp.Rcode = e.rcode
+		soa := dns.Copy(e.soa).(*dns.SOA)
+		// The remaining time is provided to the caller like a caching resolver would
+		soa.Hdr.Ttl = uint32(e.expires.Sub(now).Seconds())
+		resp.Ns = append(resp.Ns, soa)
+		return resp
+	}
+	return nil
+}
+
+// Adds the response when it is a negative answer containing the SOA record of the zone.
+func (c *negativeCache) insert(msg, resp *dns.Msg) {
+	if !cacheable(msg) || resp == nil || len(resp.Question) == 0 ||
+		!strings.EqualFold(resp.Question[0].Name, msg.Question[0].Name) {
+		return
+	}
+
+	var qtype uint16
+	switch {
+	case resp.Rcode == dns.RcodeNameError:
+		// The name does not exist for any of the record types
+	case resp.Rcode == dns.RcodeSuccess && len(resp.Answer) == 0:
+		qtype = msg.Question[0].Qtype
+	default:
+		return
+	}
+
+	var soa *dns.SOA
+	for _, rr := range resp.Ns {
+		if s, ok := rr.(*dns.SOA); ok {
+			soa = s
+			break
+		}
+	}
+	// Negative answers without the SOA record are not cached, as described in RFC 2308
+	if soa == nil {
+		return
+	}
+
+	ttl := time.Duration(negativeTTL(soa)) * time.Second
+	if ttl <= 0 {
+		return
+	}
+	if ttl > maxNegativeTTL {
+		ttl = maxNegativeTTL
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.entries[cacheKey(msg.Question[0].Name, qtype)] = &negativeEntry{
+		rcode:   resp.Rcode,
+		soa:     soa,
+		expires: time.Now().Add(ttl),
+	}
+}
+
+func (c *negativeCache) purge() {
+	now := time.Now()
+
+	c.Lock()
+	defer c.Unlock()
+
+	for key, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Queries requesting DNSSEC records are not cached, since the proofs of nonexistence are not kept.
+func cacheable(msg *dns.Msg) bool {
+	if msg == nil || len(msg.Question) == 0 || msg.Question[0].Qclass != dns.ClassINET {
+		return false
+	}
+	if opt := msg.IsEdns0(); opt != nil && opt.Do() {
+		return false
+	}
+	return true
+}
+
+// The negative caching TTL is the smaller of the SOA record TTL and the SOA MINIMUM field.
+func negativeTTL(soa *dns.SOA) uint32 {
+	if soa.Minttl < soa.Hdr.Ttl {
+		return soa.Minttl
+	}
+	return soa.Hdr.Ttl
+}
+
+func cacheKey(name string, qtype uint16) string {
+	return strings.ToLower(dns.Fqdn(name)) + "/" + strconv.Itoa(int(qtype))
+}
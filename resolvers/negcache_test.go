@@ -0,0 +1,98 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+func TestPoolNegativeCaching(t *testing.T) {
+	var lock sync.Mutex
+	counts := make(map[string]int)
+	soa := &dns.SOA{
+		Hdr:     dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 3600},
+		Ns:      "ns.example.com.",
+		Mbox:    "admin.example.com.",
+		Serial:  1,
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  60,
+	}
+
+	addr := startTestServer(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		q := req.Question[0]
+		lock.Lock()
+		counts[strings.ToLower(q.Name)+dns.TypeToString[q.Qtype]]++
+		lock.Unlock()
+
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		switch strings.ToLower(q.Name) {
+		case "www.example.com.":
+			if q.Qtype == dns.TypeA {
+				resp.Answer = append(resp.Answer, aRecord(q.Name, "192.168.1.1"))
+			} else {
+				resp.Ns = append(resp.Ns, soa)
+			}
+		case "nosoa.example.com.":
+			resp.Rcode = dns.RcodeNameError
+		default:
+			resp.Rcode = dns.RcodeNameError
+			resp.Ns = append(resp.Ns, soa)
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(100, addr)
+	p.SetNegativeCaching(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	queries := []struct {
+		name  string
+		qtype uint16
+		rcode int
+	}{
+		{"missing.example.com", dns.TypeA, dns.RcodeNameError},
+		{"missing.example.com", dns.TypeAAAA, dns.RcodeNameError},
+		{"www.example.com", dns.TypeAAAA, dns.RcodeSuccess},
+		{"www.example.com", dns.TypeA, dns.RcodeSuccess},
+		{"nosoa.example.com", dns.TypeA, dns.RcodeNameError},
+	}
+	for i := 0; i < 3; i++ {
+		for _, q := range queries {
+			resp, err := p.QueryBlocking(ctx, resolve.QueryMsg(q.name, q.qtype))
+			if err != nil || resp.Rcode != q.rcode {
+				t.Fatalf("The %s query for %s did not receive the expected response", dns.TypeToString[q.qtype], q.name)
+			}
+		}
+	}
+
+	expected := map[string]int{
+		"missing.example.com.A":    1,
+		"missing.example.com.AAAA": 0,
+		"www.example.com.AAAA":     1,
+		"www.example.com.A":        3,
+		"nosoa.example.com.A":      3,
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	for key, num := range expected {
+		if counts[key] != num {
+			t.Errorf("%s was queried %d times, expected %d", key, counts[key], num)
+		}
+	}
+}
@@ -0,0 +1,466 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/caffix/queue"
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+	"go.uber.org/ratelimit"
+)
+
+// Resolver is implemented by the DNS transports that can be added to a Pool.
+type Resolver interface {
+	// String returns the address of the DNS resolver
+	String() string
+
+	// Exchange sends the DNS message to the resolver and returns the response
+	Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error)
+
+	// SetTimeout updates the amount of time the resolver will wait for response messages
+	SetTimeout(d time.Duration)
+
+	// Stop releases the resources held by the resolver
+	Stop()
+}
+
+// Pool is a pool of DNS resolvers that combines classic resolvers with resolvers
+// using alternative transports, such as DNS-over-HTTPS and DNS-over-TLS. A single
+// detection resolver is used for all the DNS wildcard tests.
+type Pool struct {
+	sync.Mutex
+	done       chan struct{}
+	log        *log.Logger
+	list       []*member
+	addrs      map[string]struct{}
+	queue      queue.Queue
+	qps        int
+	maxSet     bool
+	rate       ratelimit.Limiter
+	timeout    time.Duration
+	edns0      *EDNS0Options
+	cookie     string
+	health     *HealthOptions
+	adaptive   bool
+	detector   *member
+	wildcards  map[string]*wildcard
+	threshold  float64
+	negative   *negativeCache
+	disk       *DiskCache
+	diskBucket string
+	zones      map[string]*Pool
+	rrl        *rrlDetector
+	queries    uint64
+}
+
+type member struct {
+	res     Resolver
+	rate    *adaptiveRate
+	stats   *healthStats
+	evicted bool
+}
+
+type request struct {
+	ctx    context.Context
+	msg    *dns.Msg
+	result chan *dns.Msg
+}
+
+// NewPool returns an initialized Pool without any resolvers.
+func NewPool() *Pool {
+	p := &Pool{
+		done:      make(chan struct{}),
+		log:       log.New(ioutil.Discard, "", 0),
+		addrs:     make(map[string]struct{}),
+		queue:     queue.NewQueue(),
+		wildcards: make(map[string]*wildcard),
+		threshold: DefaultWildcardThreshold,
+	}
+
+	go p.sendQueries()
+	go p.healthChecks()
+	go p.rateAdjustments()
+	go p.negativeCachePurges()
+	return p
+}
+
+// Len returns the number of resolvers that have been added to the pool.
+func (p *Pool) Len() int {
+	p.Lock()
+	defer p.Unlock()
+
+	return len(p.list)
+}
+
+// Pending returns the number of queries waiting to be sent to the resolvers.
+func (p *Pool) Pending() int {
+	return p.queue.Len()
+}
+
+// Queries returns the number of queries sent to the resolvers of the pool and its zones, which
+// excludes the queries answered by the caches.
+func (p *Pool) Queries() int {
+	num := int(atomic.LoadUint64(&p.queries))
+
+	p.Lock()
+	zones := make([]*Pool, 0, len(p.zones))
+	for _, zp := range p.zones {
+		zones = append(zones, zp)
+	}
+	p.Unlock()
+
+	for _, zp := range zones {
+		num += zp.Queries()
+	}
+	return num
+}
+
+// SetLogger assigns a new logger to the resolver pool.
+func (p *Pool) SetLogger(l *log.Logger) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.log = l
+}
+
+// SetTimeout updates the amount of time this pool will wait for response messages.
+func (p *Pool) SetTimeout(d time.Duration) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.timeout = d
+	for _, m := range p.list {
+		m.res.SetTimeout(d)
+	}
+	if p.detector != nil && !p.isMember(p.detector) {
+		p.detector.res.SetTimeout(d)
+	}
+}
+
+// QPS returns the maximum queries per second provided by the resolver pool.
+func (p *Pool) QPS() int {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.maxSet {
+		return p.qps
+	}
+
+	var qps int
+	for _, m := range p.list {
+		if !m.evicted {
+			qps += m.rate.QPS()
+		}
+	}
+	return qps
+}
+
+// SetMaxQPS allows a preferred maximum number of queries per second to be specified for the pool.
+func (p *Pool) SetMaxQPS(qps int) {
+	p.Lock()
+	defer p.Unlock()
+
+	if qps > 0 {
+		p.qps = qps
+		p.maxSet = true
+		p.rate = ratelimit.New(qps)
+		return
+	}
+
+	p.maxSet = false
+	p.rate = nil
+	p.qps = 0
+}
+
+// AddResolvers initializes and adds new resolvers to the pool of resolvers. Addresses
+// starting with the https:// scheme are used as DNS-over-HTTPS resolvers, addresses
+// starting with the tls:// scheme are used as DNS-over-TLS resolvers, and all other
+// addresses are used as classic DNS resolvers.
+func (p *Pool) AddResolvers(qps int, addrs ...string) error {
+	if qps == 0 {
+		return errors.New("failed to provide a maximum number of queries per second greater than zero")
+	}
+
+	var added int
+	for _, addr := range addrs {
+		addr = strings.TrimSpace(addr)
+
+		res, err := p.newResolver(addr)
+		if err != nil {
+			p.log.Printf("Failed to add the %s resolver: %v", addr, err)
+			continue
+		}
+		p.addResolver(res, qps)
+		added++
+	}
+
+	if added == 0 && len(addrs) > 0 {
+		return errors.New("failed to add any of the provided resolvers")
+	}
+	return nil
+}
+
+func (p *Pool) newResolver(addr string) (Resolver, error) {
+	p.Lock()
+	timeout := p.timeout
+	p.Unlock()
+
+	res, err := NewResolver(addr)
+	if err == nil && timeout > 0 {
+		res.SetTimeout(timeout)
+	}
+	return res, err
+}
+
+// NewResolver returns the Resolver for the provided address, using the transport selected by
+// the scheme of the address, in the same manner as AddResolvers.
+func NewResolver(addr string) (Resolver, error) {
+	switch lower := strings.ToLower(addr); {
+	case strings.HasPrefix(lower, "https://"):
+		return NewDoHResolver(addr)
+	case strings.HasPrefix(lower, "tls://"):
+		return NewDoTResolver(addr)
+	}
+	return NewUDPResolver(addr)
+}
+
+func (p *Pool) addResolver(res Resolver, qps int) {
+	p.Lock()
+	defer p.Unlock()
+
+	if _, found := p.addrs[res.String()]; found {
+		res.Stop()
+		return
+	}
+
+	m := &member{
+		res:   res,
+		rate:  newAdaptiveRate(qps),
+		stats: new(healthStats),
+	}
+	p.addrs[res.String()] = struct{}{}
+	p.list = append(p.list, m)
+	// Provides the wildcard detection with a resolver when one has not been set
+	if p.detector == nil {
+		p.detector = m
+	}
+}
+
+func (p *Pool) isMember(m *member) bool {
+	for _, cur := range p.list {
+		if cur == m {
+			return true
+		}
+	}
+	return false
+}
+
+// Stop will release resources for the resolver pool and all added resolvers.
+func (p *Pool) Stop() {
+	p.Lock()
+	defer p.Unlock()
+
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+
+	close(p.done)
+	if p.detector != nil && !p.isMember(p.detector) {
+		p.detector.res.Stop()
+	}
+	for _, m := range p.list {
+		m.res.Stop()
+	}
+	p.queue.Process(func(e interface{}) {
+		req := e.(*request)
+		req.result <- noResponse(req.msg)
+	})
+}
+
+// Query queues the provided DNS message and returns the response on the provided channel.
+func (p *Pool) Query(ctx context.Context, msg *dns.Msg, ch chan *dns.Msg) {
+	if msg == nil {
+		ch <- msg
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-p.done:
+	default:
+		if len(msg.Question) > 0 {
+			if zp := p.zoneResolvers(msg.Question[0].Name); zp != nil {
+				zp.Query(ctx, msg, ch)
+				return
+			}
+		}
+		if c := p.getNegativeCache(); c != nil {
+			if resp := c.lookup(msg); resp != nil {
+				ch <- resp
+				return
+			}
+		}
+		if c, bucket := p.getDiskCache(); c != nil {
+			if resp := c.Get(bucket, msg); resp != nil {
+				ch <- resp
+				return
+			}
+		}
+
+		p.queue.Append(&request{
+			ctx:    ctx,
+			msg:    p.applyEDNS0(msg),
+			result: ch,
+		})
+		return
+	}
+	ch <- noResponse(msg)
+}
+
+// QueryChan queues the provided DNS message and sends the response on the returned channel.
+func (p *Pool) QueryChan(ctx context.Context, msg *dns.Msg) chan *dns.Msg {
+	ch := make(chan *dns.Msg, 1)
+	p.Query(ctx, msg, ch)
+	return ch
+}
+
+// QueryBlocking queues the provided DNS message and returns the associated response message.
+func (p *Pool) QueryBlocking(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	select {
+	case <-ctx.Done():
+		return msg, errors.New("the context expired")
+	default:
+	}
+
+	ch := p.QueryChan(ctx, msg)
+
+	select {
+	case <-ctx.Done():
+		return msg, errors.New("the context expired")
+	case resp := <-ch:
+		var err error
+		if resp == nil {
+			err = errors.New("query failed")
+		}
+		return resp, err
+	}
+}
+
+func (p *Pool) sendQueries() {
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-p.queue.Signal():
+			p.Lock()
+			rate := p.rate
+			p.Unlock()
+
+			if rate != nil {
+				rate.Take()
+			}
+			if e, ok := p.queue.Next(); ok {
+				p.dispatch(e.(*request))
+			}
+		}
+	}
+}
+
+func (p *Pool) dispatch(req *request) {
+	select {
+	case <-req.ctx.Done():
+		req.result <- noResponse(req.msg)
+		return
+	default:
+	}
+
+	if m := p.selectMember(); m != nil {
+		go p.exchange(m, req)
+		return
+	}
+	req.result <- noResponse(req.msg)
+}
+
+// Weighted random selection across the resolvers that have not been evicted, using the current QPS of each resolver.
+func (p *Pool) selectMember() *member {
+	p.Lock()
+	defer p.Unlock()
+
+	var total int
+	weights := make([]int, len(p.list))
+	for i, m := range p.list {
+		if !m.evicted {
+			weights[i] = m.rate.QPS()
+			total += weights[i]
+		}
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	sel := rand.Intn(total)
+	for i, m := range p.list {
+		if sel < weights[i] {
+			return m
+		}
+		sel -= weights[i]
+	}
+	return nil
+}
+
+func (p *Pool) exchange(m *member, req *request) {
+	rrl := p.getRRLDetector()
+	var zone string
+	if rrl != nil {
+		zone = rrlZoneName(req.msg)
+		rrl.take(zone)
+	}
+	m.rate.Take()
+	atomic.AddUint64(&p.queries, 1)
+
+	var resp *dns.Msg
+	var truncated bool
+	var err error
+	if tr, ok := m.res.(truncatingResolver); ok {
+		resp, truncated, err = tr.exchangeTruncated(req.ctx, req.msg)
+	} else {
+		resp, err = m.res.Exchange(req.ctx, req.msg)
+	}
+	m.stats.record(req.msg, resp, err)
+	m.rate.record(resp, err)
+	// Queries cancelled by the caller provide no information about the zone
+	if rrl != nil && req.ctx.Err() == nil {
+		rrl.record(zone, resp, truncated, err)
+	}
+	if c := p.getNegativeCache(); c != nil && err == nil {
+		c.insert(req.msg, resp)
+	}
+	if c, bucket := p.getDiskCache(); c != nil && err == nil {
+		c.Put(bucket, req.msg, resp)
+	}
+	if err != nil || resp == nil {
+		resp = noResponse(req.msg)
+	}
+	req.result <- resp
+}
+
+func noResponse(msg *dns.Msg) *dns.Msg {
+	resp := msg.Copy()
+
+	resp.Rcode = resolve.RcodeNoResponse
+	return resp
+}
@@ -0,0 +1,175 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+var largeTXTSet = [][]string{
+	{"v=spf1 include:_spf.example.com include:mail.", "example.com ip4:192.168.1.1 -all"},
+	{"google-site-verification=" + strings.Repeat("a", 200)},
+	{"ms=" + strings.Repeat("b", 200)},
+}
+
+// Responds over UDP with the TC bit set and provides the complete answer over TCP.
+func truncatingTestServer(t *testing.T) string {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen for the UDP test server: %v", err)
+	}
+
+	l, err := net.Listen("tcp", pc.LocalAddr().String())
+	if err != nil {
+		_ = pc.Close()
+		t.Fatalf("Failed to listen for the TCP test server: %v", err)
+	}
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		if w.RemoteAddr().Network() == "udp" {
+			resp.Truncated = true
+			_ = w.WriteMsg(resp)
+			return
+		}
+
+		for _, txt := range largeTXTSet {
+			resp.Answer = append(resp.Answer, &dns.TXT{
+				Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 300},
+				Txt: txt,
+			})
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	for _, srv := range []*dns.Server{{PacketConn: pc, Handler: handler}, {Listener: l, Handler: handler}} {
+		s := srv
+		started := make(chan struct{})
+		s.NotifyStartedFunc = func() { close(started) }
+		go func() { _ = s.ActivateAndServe() }()
+		<-started
+		t.Cleanup(func() { _ = s.Shutdown() })
+	}
+	return pc.LocalAddr().String()
+}
+
+func TestPoolTruncatedResponse(t *testing.T) {
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(10, truncatingTestServer(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := p.QueryBlocking(ctx, resolve.QueryMsg("example.com", dns.TypeTXT))
+	if err != nil || resp.Rcode != dns.RcodeSuccess {
+		t.Fatalf("The query for the truncated response failed")
+	}
+	if resp.Truncated {
+		t.Errorf("The truncated response was not retried over TCP")
+	}
+	if n := p.Queries(); n != 1 {
+		t.Errorf("The pool counted %d queries instead of one", n)
+	}
+
+	ans := resolve.AnswersByType(ExtractAnswers(resp), dns.TypeTXT)
+	if len(ans) != len(largeTXTSet) {
+		t.Fatalf("Got %d TXT answers, expected %d", len(ans), len(largeTXTSet))
+	}
+	for i, txt := range largeTXTSet {
+		if expected := strings.Join(txt, ""); ans[i].Data != expected {
+			t.Errorf("Got %s, expected %s", ans[i].Data, expected)
+		}
+	}
+}
+
+func TestPoolEDNS0Options(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen for the DNS test server: %v", err)
+	}
+
+	opts := make(chan *dns.OPT, 1)
+	srv := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			select {
+			case opts <- req.IsEdns0():
+			default:
+			}
+
+			resp := new(dns.Msg)
+			resp.SetReply(req)
+			_ = w.WriteMsg(resp)
+		}),
+	}
+	started := make(chan struct{})
+	srv.NotifyStartedFunc = func() { close(started) }
+	go func() { _ = srv.ActivateAndServe() }()
+	<-started
+	defer func() { _ = srv.Shutdown() }()
+
+	subnet, err := ParseClientSubnet("192.0.2.10/24")
+	if err != nil {
+		t.Fatalf("Failed to parse the client subnet: %v", err)
+	}
+
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(10, pc.LocalAddr().String())
+	p.SetEDNS0Options(&EDNS0Options{
+		BufferSize:   1232,
+		Cookies:      true,
+		ClientSubnet: subnet,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	msg := resolve.QueryMsg("www.example.com", dns.TypeA)
+	if _, err := p.QueryBlocking(ctx, msg); err != nil {
+		t.Fatalf("The query failed: %v", err)
+	}
+	if len(msg.IsEdns0().Option) != 1 || msg.IsEdns0().UDPSize() != dns.DefaultMsgSize {
+		t.Errorf("The EDNS0 options modified the message provided by the caller")
+	}
+
+	opt := <-opts
+	if opt == nil {
+		t.Fatalf("The query did not include the EDNS0 record")
+	}
+	if opt.UDPSize() != 1232 {
+		t.Errorf("Got a buffer size of %d, expected 1232", opt.UDPSize())
+	}
+
+	var ecs *dns.EDNS0_SUBNET
+	var cookie *dns.EDNS0_COOKIE
+	for _, o := range opt.Option {
+		switch v := o.(type) {
+		case *dns.EDNS0_SUBNET:
+			if ecs != nil {
+				t.Errorf("The query included more than one client subnet")
+			}
+			ecs = v
+		case *dns.EDNS0_COOKIE:
+			cookie = v
+		}
+	}
+	if ecs == nil || ecs.SourceNetmask != 24 || !ecs.Address.Equal(net.ParseIP("192.0.2.0")) {
+		t.Errorf("The query did not include the configured client subnet")
+	}
+	if cookie == nil || len(cookie.Cookie) != 16 {
+		t.Errorf("The query did not include a client cookie")
+	}
+}
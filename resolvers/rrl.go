@@ -0,0 +1,218 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+	"go.uber.org/ratelimit"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	minRRLSamples = 20
+	minRRLQPS     = 1
+	// Fraction of the queries for a zone that must be truncated or lost before RRL is suspected
+	rrlLossThreshold = 0.2
+	// The zone must lose this multiple of the fraction lost by the queries for all other zones
+	rrlZoneFactor = 3
+	// Limited zones recover while the fraction lost stays at or below this value
+	rrlRecoverThreshold = 0.05
+)
+
+// truncatingResolver is implemented by the transports that retry truncated responses
+// over another transport, which hides the truncation from the response returned by Exchange.
+type truncatingResolver interface {
+	exchangeTruncated(ctx context.Context, msg *dns.Msg) (*dns.Msg, bool, error)
+}
+
+// rrlDetector tracks the truncated and lost responses for each zone, since authoritative servers
+// using response rate limiting (RRL) truncate or drop the responses once a zone receives too many
+// queries. The queries for a zone showing these signs are slowed down, instead of losing the answers.
+type rrlDetector struct {
+	sync.Mutex
+	zones map[string]*rrlZone
+	sent  int
+	lost  int
+}
+
+type rrlZone struct {
+	sent    int
+	lost    int
+	qps     int
+	ceiling int
+	limiter ratelimit.Limiter
+}
+
+func newRRLDetector() *rrlDetector {
+	return &rrlDetector{zones: make(map[string]*rrlZone)}
+}
+
+// SetRRLDetection enables or disables the detection of response rate limiting performed by the
+// authoritative servers of each zone, and the automatic slowdown of the queries for those zones.
+func (p *Pool) SetRRLDetection(enabled bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	if !enabled {
+		p.rrl = nil
+	} else if p.rrl == nil {
+		p.rrl = newRRLDetector()
+	}
+}
+
+func (p *Pool) getRRLDetector() *rrlDetector {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.rrl
+}
+
+// LimitedZones returns the zones currently having their queries slowed down, along with the rate.
+func (p *Pool) LimitedZones() map[string]int {
+	limited := make(map[string]int)
+
+	if d := p.getRRLDetector(); d != nil {
+		d.Lock()
+		for name, z := range d.zones {
+			if z.limiter != nil {
+				limited[name] = z.qps
+			}
+		}
+		d.Unlock()
+	}
+	return limited
+}
+
+// Returns the registered domain of the question name, since the rate limits are applied per zone.
+func rrlZoneName(msg *dns.Msg) string {
+	if len(msg.Question) == 0 {
+		return ""
+	}
+
+	name := strings.ToLower(resolve.RemoveLastDot(msg.Question[0].Name))
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(name); err == nil {
+		return domain
+	}
+	return name
+}
+
+// take blocks until the next query can be sent for the zone.
+func (d *rrlDetector) take(zone string) {
+	d.Lock()
+	var limiter ratelimit.Limiter
+	if z, found := d.zones[zone]; found {
+		limiter = z.limiter
+	}
+	d.Unlock()
+
+	if limiter != nil {
+		limiter.Take()
+	}
+}
+
+// Truncated responses, timeouts and SERVFAIL responses are all considered losses, since recursive
+// resolvers commonly return SERVFAIL after their queries to the authoritative servers go unanswered.
+func (d *rrlDetector) record(zone string, resp *dns.Msg, truncated bool, err error) {
+	lost := truncated || err != nil || resp == nil || resp.Truncated || resp.Rcode == dns.RcodeServerFailure
+
+	d.Lock()
+	defer d.Unlock()
+
+	z, found := d.zones[zone]
+	if !found {
+		z = new(rrlZone)
+		d.zones[zone] = z
+	}
+
+	d.sent++
+	z.sent++
+	if lost {
+		d.lost++
+		z.lost++
+	}
+}
+
+// Evaluates the losses of each zone during the interval, and resets the counts for the next interval.
+func (d *rrlDetector) adjust(interval time.Duration, l *log.Logger) {
+	d.Lock()
+	defer d.Unlock()
+
+	for name, z := range d.zones {
+		sent, lost := z.sent, z.lost
+		z.sent, z.lost = 0, 0
+
+		if z.limiter != nil {
+			d.adjustLimited(name, z, sent, lost, l)
+			continue
+		}
+		if sent == 0 {
+			delete(d.zones, name)
+			continue
+		}
+		if sent < minRRLSamples {
+			continue
+		}
+
+		var others float64
+		if osent := d.sent - sent; osent > 0 {
+			others = float64(d.lost-lost) / float64(osent)
+		}
+		// Losses across all the zones are caused by the resolvers or the network, not RRL
+		loss := float64(lost) / float64(sent)
+		if loss <= rrlLossThreshold || loss <= others*rrlZoneFactor {
+			continue
+		}
+
+		rate := int(float64(sent) / interval.Seconds())
+		z.ceiling = rate
+		z.setRate(rate / 2)
+		l.Printf("DNS: %s: Response rate limiting detected (%.0f%% of the queries were truncated or lost), reducing to %d queries per second",
+			name, loss*100, z.qps)
+	}
+	d.sent, d.lost = 0, 0
+}
+
+func (d *rrlDetector) adjustLimited(name string, z *rrlZone, sent, lost int, l *log.Logger) {
+	if sent == 0 {
+		return
+	}
+
+	loss := float64(lost) / float64(sent)
+	switch {
+	case loss > rrlLossThreshold:
+		if qps := z.qps / 2; qps >= minRRLQPS && qps != z.qps {
+			z.setRate(qps)
+			l.Printf("DNS: %s: Response rate limiting continues, reducing to %d queries per second", name, z.qps)
+		}
+	case loss <= rrlRecoverThreshold:
+		step := z.qps / 4
+		if step < 1 {
+			step = 1
+		}
+		if qps := z.qps + step; qps < z.ceiling {
+			z.setRate(qps)
+			return
+		}
+
+		z.qps, z.ceiling, z.limiter = 0, 0, nil
+		l.Printf("DNS: %s: The queries are no longer slowed down", name)
+	}
+}
+
+func (z *rrlZone) setRate(qps int) {
+	if qps < minRRLQPS {
+		qps = minRRLQPS
+	}
+
+	z.qps = qps
+	z.limiter = ratelimit.New(qps)
+}
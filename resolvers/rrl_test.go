@@ -0,0 +1,119 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+func TestRRLDetectorAdjust(t *testing.T) {
+	d := newRRLDetector()
+	l := log.New(ioutil.Discard, "", 0)
+	success := &dns.Msg{}
+
+	send := func(zone string, num, lost int) {
+		for i := 0; i < num; i++ {
+			if i < lost {
+				d.record(zone, nil, false, errors.New("timeout"))
+			} else {
+				d.record(zone, success, false, nil)
+			}
+		}
+	}
+	limit := func(zone string) int {
+		if z, found := d.zones[zone]; found && z.limiter != nil {
+			return z.qps
+		}
+		return 0
+	}
+
+	send("owasp.org", 100, 5)
+	send("example.com", 100, 5)
+	d.adjust(time.Second, l)
+	if limit("owasp.org") != 0 || limit("example.com") != 0 {
+		t.Errorf("The zones were limited without signs of RRL")
+	}
+
+	// Losses across all the zones are not caused by RRL
+	send("owasp.org", 100, 50)
+	send("example.com", 100, 50)
+	d.adjust(time.Second, l)
+	if limit("owasp.org") != 0 || limit("example.com") != 0 {
+		t.Errorf("The zones were limited while all the zones experienced losses")
+	}
+
+	send("owasp.org", 100, 50)
+	send("example.com", 100, 5)
+	d.adjust(time.Second, l)
+	if limit("owasp.org") != 50 || limit("example.com") != 0 {
+		t.Errorf("The zone was not limited after the RRL: %d", limit("owasp.org"))
+	}
+
+	send("owasp.org", 50, 25)
+	d.adjust(time.Second, l)
+	if limit("owasp.org") != 25 {
+		t.Errorf("The zone was not slowed down further while RRL continued: %d", limit("owasp.org"))
+	}
+
+	for i := 0; i < 100 && limit("owasp.org") != 0; i++ {
+		send("owasp.org", 25, 0)
+		d.adjust(time.Second, l)
+	}
+	if limit("owasp.org") != 0 {
+		t.Errorf("The zone did not recover after the losses stopped: %d", limit("owasp.org"))
+	}
+
+	d.adjust(time.Second, l)
+	if _, found := d.zones["example.com"]; found {
+		t.Errorf("The idle zone was not removed")
+	}
+}
+
+func TestPoolRRLTruncation(t *testing.T) {
+	// Only UDP is served, so the retries of the truncated responses over TCP fail
+	addr := startTestServer(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		name := req.Question[0].Name
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		if strings.HasSuffix(name, ".limited.com.") {
+			resp.Truncated = true
+		} else {
+			resp.Answer = append(resp.Answer, aRecord(name, "192.168.1.1"))
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	p := NewPool()
+	defer p.Stop()
+	p.SetTimeout(time.Second)
+	p.SetRRLDetection(true)
+	if err := p.AddResolvers(1000, addr); err != nil {
+		t.Fatalf("Failed to add the test resolver: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < minRRLSamples; i++ {
+		for _, domain := range []string{"limited.com", "owasp.org"} {
+			_, _ = p.QueryBlocking(ctx, resolve.QueryMsg(fmt.Sprintf("www%d.%s", i, domain), dns.TypeA))
+		}
+	}
+
+	p.getRRLDetector().adjust(rateAdjustInterval, log.New(ioutil.Discard, "", 0))
+	limited := p.LimitedZones()
+	if _, found := limited["limited.com"]; !found || len(limited) != 1 {
+		t.Errorf("The truncated responses did not cause the zone to be limited: %v", limited)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+const maxQueryAttempts int = 5
+
+// FirstProperSubdomain returns the first subdomain name using the provided name and
+// Pool that responds successfully to a DNS query for the NS record type.
+func FirstProperSubdomain(ctx context.Context, p *Pool, name string) string {
+	var domain string
+	// Obtain all parts of the subdomain name
+	labels := strings.Split(strings.TrimSpace(name), ".")
+loop:
+	for i := 0; i < len(labels)-1; i++ {
+		sub := strings.Join(labels[i:], ".")
+
+		for j := 0; j < maxQueryAttempts; j++ {
+			resp, err := p.QueryBlocking(ctx, resolve.QueryMsg(sub, dns.TypeNS))
+			if err != nil || resp.Rcode == dns.RcodeNameError {
+				continue loop
+			}
+			if resp.Rcode == dns.RcodeSuccess {
+				if len(resp.Answer) == 0 {
+					continue loop
+				}
+				if d := resolve.AnswersByType(resolve.ExtractAnswers(resp), dns.TypeNS); len(d) > 0 {
+					domain = sub
+					break loop
+				}
+			}
+		}
+	}
+	return domain
+}
@@ -0,0 +1,93 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"net"
+	"sync"
+)
+
+// Well-known resolver addresses used to check for a route, since connecting a UDP socket sends no packets
+const (
+	ipv4RouteCheckAddr = "8.8.8.8:53"
+	ipv6RouteCheckAddr = "[2001:4860:4860::8888]:53"
+)
+
+var (
+	transportOnce sync.Once
+	ipv4Available bool
+	ipv6Available bool
+)
+
+// IPv4Available returns true when the host has a route for sending DNS queries over IPv4.
+func IPv4Available() bool {
+	transportOnce.Do(checkTransports)
+	return ipv4Available
+}
+
+// IPv6Available returns true when the host has a route for sending DNS queries over IPv6.
+func IPv6Available() bool {
+	transportOnce.Do(checkTransports)
+	return ipv6Available
+}
+
+func checkTransports() {
+	ipv4Available = routeAvailable("udp4", ipv4RouteCheckAddr)
+	ipv6Available = routeAvailable("udp6", ipv6RouteCheckAddr)
+}
+
+func routeAvailable(network, addr string) bool {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	local, ok := conn.LocalAddr().(*net.UDPAddr)
+	return ok && local.IP.IsGlobalUnicast() && !local.IP.IsLinkLocalUnicast()
+}
+
+// FilterByTransport removes the resolver IP addresses that cannot be reached using the transports
+// available on the host. When preferIPv6 is true and IPv6 is available, only the IPv6 addresses
+// are kept, as long as some were provided. Addresses that are not IP addresses, such as the URLs
+// of DNS-over-HTTPS resolvers, are always kept. The addresses are returned unchanged when no
+// transport could be detected, or when none of the addresses can be reached.
+func FilterByTransport(addrs []string, preferIPv6 bool) []string {
+	var v4, v6, other []string
+	for _, addr := range addrs {
+		host := addr
+		if h, _, err := net.SplitHostPort(addr); err == nil {
+			host = h
+		}
+
+		switch ip := net.ParseIP(host); {
+		case ip == nil:
+			other = append(other, addr)
+		case ip.To4() != nil:
+			v4 = append(v4, addr)
+		default:
+			v6 = append(v6, addr)
+		}
+	}
+
+	if !IPv4Available() && !IPv6Available() {
+		return addrs
+	}
+	if preferIPv6 && IPv6Available() && len(v6) > 0 {
+		return append(v6, other...)
+	}
+
+	var selected []string
+	if IPv4Available() {
+		selected = append(selected, v4...)
+	}
+	if IPv6Available() {
+		selected = append(selected, v6...)
+	}
+	if len(selected) == 0 && len(other) == 0 {
+		return addrs
+	}
+	return append(selected, other...)
+}
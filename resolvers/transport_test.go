@@ -0,0 +1,77 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+func TestFilterByTransport(t *testing.T) {
+	transportOnce.Do(func() {})
+	v4, v6 := ipv4Available, ipv6Available
+	defer func() { ipv4Available, ipv6Available = v4, v6 }()
+
+	addrs := []string{"8.8.8.8", "[2001:4860:4860::8888]:53", "https://dns.google/dns-query", "1.1.1.1:53", "2606:4700:4700::1111"}
+	tests := []struct {
+		ipv4, ipv6, prefer bool
+		expected           []string
+	}{
+		{true, true, false, []string{"8.8.8.8", "1.1.1.1:53", "[2001:4860:4860::8888]:53", "2606:4700:4700::1111", "https://dns.google/dns-query"}},
+		{true, true, true, []string{"[2001:4860:4860::8888]:53", "2606:4700:4700::1111", "https://dns.google/dns-query"}},
+		{true, false, true, []string{"8.8.8.8", "1.1.1.1:53", "https://dns.google/dns-query"}},
+		{false, true, false, []string{"[2001:4860:4860::8888]:53", "2606:4700:4700::1111", "https://dns.google/dns-query"}},
+		{false, false, false, addrs},
+	}
+
+	for _, test := range tests {
+		ipv4Available, ipv6Available = test.ipv4, test.ipv6
+
+		if got := FilterByTransport(addrs, test.prefer); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("IPv4 %t, IPv6 %t, prefer IPv6 %t: got %v, expected %v", test.ipv4, test.ipv6, test.prefer, got, test.expected)
+		}
+	}
+
+	// The addresses are kept when none of them can be reached
+	ipv4Available, ipv6Available = false, true
+	if got := FilterByTransport([]string{"8.8.8.8"}, true); len(got) != 1 {
+		t.Errorf("The unreachable addresses were not returned unchanged: %v", got)
+	}
+}
+
+func TestPoolIPv6Resolver(t *testing.T) {
+	pc, err := net.ListenPacket("udp6", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 is not available on the loopback interface")
+	}
+	_ = pc.Close()
+
+	addr := startTestServer(t, "[::1]:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Answer = append(resp.Answer, aRecord(req.Question[0].Name, "192.168.1.1"))
+		_ = w.WriteMsg(resp)
+	})
+
+	p := NewPool()
+	defer p.Stop()
+	if err := p.AddResolvers(10, addr); err != nil {
+		t.Fatalf("Failed to add the IPv6 resolver %s: %v", addr, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := p.QueryBlocking(ctx, resolve.QueryMsg("www.example.com", dns.TypeA))
+	if err != nil || resp.Rcode != dns.RcodeSuccess || len(resp.Answer) != 1 {
+		t.Errorf("The query sent over IPv6 failed")
+	}
+}
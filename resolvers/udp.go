@@ -0,0 +1,214 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	defaultUDPTimeout = 2 * time.Second
+	maxUDPMsgSize     = 65535
+)
+
+// UDPResolver sends DNS queries to a classic DNS server over a single UDP socket.
+// Truncated responses are retried over TCP against the same server.
+type UDPResolver struct {
+	sync.Mutex
+	address  string
+	conn     *dns.Conn
+	wlock    sync.Mutex
+	inflight map[string]chan *dns.Msg
+	done     chan struct{}
+	timeout  time.Duration
+}
+
+// NewUDPResolver returns a UDPResolver for the provided IP address, using port 53 when no port is provided.
+func NewUDPResolver(addr string) (*UDPResolver, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "53")
+	}
+
+	host, _, _ := net.SplitHostPort(addr)
+	if net.ParseIP(host) == nil {
+		return nil, errors.New(addr + " is not a valid DNS resolver IP address")
+	}
+
+	c := dns.Client{UDPSize: maxUDPMsgSize}
+	conn, err := c.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	r := &UDPResolver{
+		address:  addr,
+		conn:     conn,
+		inflight: make(map[string]chan *dns.Msg),
+		done:     make(chan struct{}),
+		timeout:  defaultUDPTimeout,
+	}
+
+	go r.responses()
+	return r, nil
+}
+
+// String implements the Resolver interface.
+func (r *UDPResolver) String() string {
+	return r.address
+}
+
+// SetTimeout implements the Resolver interface.
+func (r *UDPResolver) SetTimeout(d time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.timeout = d
+}
+
+// Stop implements the Resolver interface.
+func (r *UDPResolver) Stop() {
+	r.Lock()
+	defer r.Unlock()
+
+	select {
+	case <-r.done:
+		return
+	default:
+	}
+
+	close(r.done)
+	_ = r.conn.Close()
+}
+
+// Exchange implements the Resolver interface.
+func (r *UDPResolver) Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	resp, _, err := r.exchangeTruncated(ctx, msg)
+	return resp, err
+}
+
+// Also returns true when the response received over UDP was truncated and retried over TCP.
+func (r *UDPResolver) exchangeTruncated(ctx context.Context, msg *dns.Msg) (*dns.Msg, bool, error) {
+	if msg == nil || len(msg.Question) == 0 {
+		return nil, false, errors.New("the DNS message did not contain a question")
+	}
+
+	// The message ID is replaced to avoid collisions with other queries for the same name
+	m := msg.Copy()
+	ch, key := r.register(m)
+	defer r.unregister(key)
+
+	r.Lock()
+	timeout := r.timeout
+	r.Unlock()
+
+	r.wlock.Lock()
+	_ = r.conn.SetWriteDeadline(time.Now().Add(timeout))
+	err := r.conn.WriteMsg(m)
+	r.wlock.Unlock()
+	if err != nil {
+		return nil, false, err
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	var resp *dns.Msg
+	select {
+	case <-ctx.Done():
+		return nil, false, errors.New("the context expired")
+	case <-r.done:
+		return nil, false, errors.New("the resolver has been stopped")
+	case <-t.C:
+		return nil, false, errors.New("the DNS query timed out")
+	case resp = <-ch:
+	}
+
+	truncated := resp.Truncated
+	if truncated {
+		resp, err = r.tcpExchange(ctx, m, timeout)
+		if err != nil {
+			return nil, true, err
+		}
+	}
+
+	resp.Id = msg.Id
+	return resp, truncated, nil
+}
+
+func (r *UDPResolver) tcpExchange(ctx context.Context, msg *dns.Msg, timeout time.Duration) (*dns.Msg, error) {
+	client := dns.Client{
+		Net:     "tcp",
+		Timeout: timeout,
+	}
+
+	resp, _, err := client.ExchangeContext(ctx, msg, r.address)
+	return resp, err
+}
+
+func (r *UDPResolver) register(msg *dns.Msg) (chan *dns.Msg, string) {
+	ch := make(chan *dns.Msg, 1)
+	name := strings.ToLower(msg.Question[0].Name)
+
+	r.Lock()
+	defer r.Unlock()
+
+	for {
+		msg.Id = dns.Id()
+
+		key := xchgKey(msg.Id, name)
+		if _, found := r.inflight[key]; !found {
+			r.inflight[key] = ch
+			return ch, key
+		}
+	}
+}
+
+func (r *UDPResolver) unregister(key string) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.inflight, key)
+}
+
+func (r *UDPResolver) responses() {
+	for {
+		m, err := r.conn.ReadMsg()
+		if err != nil {
+			select {
+			case <-r.done:
+				return
+			default:
+			}
+			continue
+		}
+		if len(m.Question) == 0 {
+			continue
+		}
+
+		r.Lock()
+		ch, found := r.inflight[xchgKey(m.Id, strings.ToLower(m.Question[0].Name))]
+		r.Unlock()
+
+		if found {
+			select {
+			case ch <- m:
+			default:
+			}
+		}
+	}
+}
+
+func xchgKey(id uint16, name string) string {
+	return strconv.Itoa(int(id)) + name
+}
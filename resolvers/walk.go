@@ -0,0 +1,287 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	maxWalkAttempts    = 3
+	maxNSECWalkNames   = 100000
+	maxNSEC3Queries    = 10000
+	maxNSEC3Misses     = 50
+	maxNSEC3HashTrials = 10000
+)
+
+// ZoneWalk contains the results of walking a DNSSEC signed zone using the authenticated denial of existence.
+type ZoneWalk struct {
+	Zone string
+	// Names discovered by following the NSEC chain
+	Names []string
+	// NSEC3 contains the hashed names collected when the zone uses NSEC3
+	NSEC3 *NSEC3Chain
+}
+
+// NSEC3Chain contains the hashed owner names collected from a zone that uses NSEC3 records.
+type NSEC3Chain struct {
+	Zone       string
+	Hash       uint8
+	Iterations uint16
+	Salt       string
+	next       map[string]string
+	owners     []string
+}
+
+// WalkZone detects the type of authenticated denial of existence used by the zone and either
+// follows the NSEC chain or collects the NSEC3 hashes. The Pool should send the queries
+// directly to an authoritative server for the zone.
+func WalkZone(ctx context.Context, p *Pool, domain string) (*ZoneWalk, error) {
+	zone := dns.Fqdn(strings.ToLower(domain))
+
+	resp, err := walkQuery(ctx, p, randomLabel()+"."+zone, dns.TypeA)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rr := range resp.Ns {
+		switch v := rr.(type) {
+		case *dns.NSEC:
+			names, err := nsecWalk(ctx, p, zone)
+			return &ZoneWalk{Zone: zone, Names: names}, err
+		case *dns.NSEC3:
+			chain := newNSEC3Chain(zone, v)
+			err := chain.collect(ctx, p, resp)
+			return &ZoneWalk{Zone: zone, NSEC3: chain}, err
+		}
+	}
+	return nil, fmt.Errorf("the %s zone did not provide NSEC or NSEC3 records", zone)
+}
+
+// Follows the NextDomain links of the NSEC records until the chain returns to the zone apex.
+func nsecWalk(ctx context.Context, p *Pool, zone string) ([]string, error) {
+	var names []string
+	seen := map[string]struct{}{zone: {}}
+
+	for name := zone; len(names) < maxNSECWalkNames; {
+		select {
+		case <-ctx.Done():
+			return names, errors.New("the context expired during the NSEC walk")
+		default:
+		}
+
+		nsec, err := nsecAt(ctx, p, name)
+		if err != nil {
+			return names, err
+		}
+
+		next := strings.ToLower(nsec.NextDomain)
+		if _, found := seen[next]; found || !dns.IsSubDomain(zone, next) {
+			break
+		}
+		seen[next] = struct{}{}
+
+		if !strings.HasPrefix(next, "*.") {
+			names = append(names, strings.TrimSuffix(next, "."))
+		}
+		name = next
+	}
+	return names, nil
+}
+
+// Returns the NSEC record owned by the name. Servers that do not answer queries for the NSEC type
+// still return the record in the authority section when denying the name immediately following it.
+func nsecAt(ctx context.Context, p *Pool, name string) (*dns.NSEC, error) {
+	for _, qname := range []string{name, "\\000." + name} {
+		qtype := dns.TypeNSEC
+		if qname != name {
+			qtype = dns.TypeA
+		}
+
+		resp, err := walkQuery(ctx, p, qname, qtype)
+		if err != nil {
+			continue
+		}
+		for _, rr := range append(resp.Answer, resp.Ns...) {
+			if nsec, ok := rr.(*dns.NSEC); ok && strings.EqualFold(nsec.Hdr.Name, name) {
+				return nsec, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("the NSEC record for %s was not found", name)
+}
+
+func newNSEC3Chain(zone string, rr *dns.NSEC3) *NSEC3Chain {
+	return &NSEC3Chain{
+		Zone:       zone,
+		Hash:       rr.Hash,
+		Iterations: rr.Iterations,
+		Salt:       rr.Salt,
+		next:       make(map[string]string),
+	}
+}
+
+// Len returns the number of hashed owner names collected.
+func (c *NSEC3Chain) Len() int {
+	return len(c.owners)
+}
+
+// Complete returns true when the collected records cover the entire NSEC3 chain of the zone.
+func (c *NSEC3Chain) Complete() bool {
+	if len(c.next) == 0 {
+		return false
+	}
+
+	for _, next := range c.next {
+		if _, found := c.next[next]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Crack hashes each word as a label of the zone and returns the names matching collected hashes.
+func (c *NSEC3Chain) Crack(words []string) []string {
+	var names []string
+	seen := make(map[string]struct{})
+
+	for _, word := range words {
+		word = strings.Trim(strings.ToLower(strings.TrimSpace(word)), ".")
+		if word == "" {
+			continue
+		}
+		if _, found := seen[word]; found {
+			continue
+		}
+		seen[word] = struct{}{}
+
+		name := word + "." + c.Zone
+		if _, found := c.next[dns.HashName(name, c.Hash, c.Iterations, c.Salt)]; found {
+			names = append(names, strings.TrimSuffix(name, "."))
+		}
+	}
+	return names
+}
+
+// Queries names hashing into the gaps of the chain until it is complete, or no progress is made.
+func (c *NSEC3Chain) collect(ctx context.Context, p *Pool, resp *dns.Msg) error {
+	c.add(resp)
+
+	for i, misses := 0, 0; i < maxNSEC3Queries && misses < maxNSEC3Misses && !c.Complete(); i++ {
+		select {
+		case <-ctx.Done():
+			return errors.New("the context expired during the NSEC3 hash collection")
+		default:
+		}
+
+		label := c.uncoveredLabel()
+		if label == "" {
+			break
+		}
+
+		resp, err := walkQuery(ctx, p, label+"."+c.Zone, dns.TypeA)
+		if err != nil || c.add(resp) == 0 {
+			misses++
+			continue
+		}
+		misses = 0
+	}
+	return nil
+}
+
+// Adds the NSEC3 records from the response and returns the number of new hashes.
+func (c *NSEC3Chain) add(resp *dns.Msg) int {
+	var added int
+
+	for _, rr := range resp.Ns {
+		nsec3, ok := rr.(*dns.NSEC3)
+		if !ok || nsec3.Hash != c.Hash || nsec3.Iterations != c.Iterations || !strings.EqualFold(nsec3.Salt, c.Salt) {
+			continue
+		}
+
+		labels := dns.SplitDomainName(nsec3.Hdr.Name)
+		if len(labels) == 0 || !strings.EqualFold(dns.Fqdn(strings.Join(labels[1:], ".")), c.Zone) {
+			continue
+		}
+
+		owner := strings.ToUpper(labels[0])
+		if _, found := c.next[owner]; !found {
+			c.owners = append(c.owners, owner)
+			added++
+		}
+		c.next[owner] = strings.ToUpper(nsec3.NextDomain)
+	}
+
+	if added > 0 {
+		sort.Strings(c.owners)
+	}
+	return added
+}
+
+// Returns a label within the zone that hashes into a gap not yet covered by the collected records.
+func (c *NSEC3Chain) uncoveredLabel() string {
+	for i := 0; i < maxNSEC3HashTrials; i++ {
+		label := randomLabel()
+
+		if !c.covered(dns.HashName(label+"."+c.Zone, c.Hash, c.Iterations, c.Salt)) {
+			return label
+		}
+	}
+	return ""
+}
+
+func (c *NSEC3Chain) covered(hash string) bool {
+	if len(c.owners) == 0 {
+		return false
+	}
+
+	// Find the closest owner hash preceding the provided hash
+	idx := sort.SearchStrings(c.owners, hash)
+	if idx < len(c.owners) && c.owners[idx] == hash {
+		return true
+	}
+	if idx == 0 {
+		idx = len(c.owners)
+	}
+
+	owner := c.owners[idx-1]
+	next := c.next[owner]
+	if owner < next {
+		return hash > owner && hash < next
+	}
+	// The last record in the chain wraps around to the first hash
+	return hash > owner || hash < next
+}
+
+func walkQuery(ctx context.Context, p *Pool, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	msg.SetEdns0(dns.DefaultMsgSize, true)
+
+	var err error
+	var resp *dns.Msg
+	for i := 0; i < maxWalkAttempts; i++ {
+		resp, err = p.QueryBlocking(ctx, msg)
+		if err == nil && resp != nil && (resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError) {
+			return resp, nil
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("the zone walking query for %s failed", name)
+	}
+	return nil, err
+}
+
+func randomLabel() string {
+	return "amass" + strconv.FormatUint(rand.Uint64(), 36)
+}
@@ -0,0 +1,143 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Serves an NSEC signed zone named nsec.test and an NSEC3 signed zone named nsec3.test.
+func walkTestServer(t *testing.T) string {
+	var nsecs []dns.RR
+	chain := []string{"nsec.test.", "a.nsec.test.", "mail.nsec.test.", "www.nsec.test."}
+	for i, name := range chain {
+		nsecs = append(nsecs, &dns.NSEC{
+			Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 300},
+			NextDomain: chain[(i+1)%len(chain)],
+			TypeBitMap: []uint16{dns.TypeA, dns.TypeRRSIG, dns.TypeNSEC},
+		})
+	}
+
+	var hashes []string
+	for _, name := range []string{"nsec3.test.", "www.nsec3.test.", "mail.nsec3.test.", "dev.nsec3.test."} {
+		hashes = append(hashes, dns.HashName(name, dns.SHA1, 2, "ABCD"))
+	}
+	sort.Strings(hashes)
+
+	var nsec3s []*dns.NSEC3
+	for i, hash := range hashes {
+		nsec3s = append(nsec3s, &dns.NSEC3{
+			Hdr:        dns.RR_Header{Name: hash + ".nsec3.test.", Rrtype: dns.TypeNSEC3, Class: dns.ClassINET, Ttl: 300},
+			Hash:       dns.SHA1,
+			Iterations: 2,
+			SaltLength: 2,
+			Salt:       "ABCD",
+			HashLength: 20,
+			NextDomain: hashes[(i+1)%len(hashes)],
+			TypeBitMap: []uint16{dns.TypeA, dns.TypeRRSIG},
+		})
+	}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen for the DNS test server: %v", err)
+	}
+
+	srv := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			q := req.Question[0]
+			name := strings.ToLower(q.Name)
+
+			resp := new(dns.Msg)
+			resp.SetReply(req)
+			if dns.IsSubDomain("nsec3.test.", name) {
+				// Only the record covering the hash of the name is returned
+				hash := dns.HashName(name, dns.SHA1, 2, "ABCD")
+				resp.Rcode = dns.RcodeNameError
+				for _, rr := range nsec3s {
+					owner := strings.ToUpper(dns.SplitDomainName(rr.Hdr.Name)[0])
+					if rr.Cover(name) || owner == hash {
+						resp.Ns = append(resp.Ns, rr)
+					}
+				}
+			} else if q.Qtype == dns.TypeNSEC {
+				for _, rr := range nsecs {
+					if rr.Header().Name == name {
+						resp.Answer = append(resp.Answer, rr)
+					}
+				}
+			} else {
+				resp.Rcode = dns.RcodeNameError
+				resp.Ns = nsecs
+			}
+			_ = w.WriteMsg(resp)
+		}),
+	}
+
+	started := make(chan struct{})
+	srv.NotifyStartedFunc = func() { close(started) }
+	go func() { _ = srv.ActivateAndServe() }()
+	<-started
+	t.Cleanup(func() { _ = srv.Shutdown() })
+
+	return pc.LocalAddr().String()
+}
+
+func TestWalkZoneNSEC(t *testing.T) {
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(100, walkTestServer(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	walk, err := WalkZone(ctx, p, "nsec.test")
+	if err != nil {
+		t.Fatalf("The NSEC walk failed: %v", err)
+	}
+	if walk.NSEC3 != nil {
+		t.Errorf("The NSEC zone was detected as using NSEC3")
+	}
+
+	expected := []string{"a.nsec.test", "mail.nsec.test", "www.nsec.test"}
+	if strings.Join(walk.Names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Got %v, expected %v", walk.Names, expected)
+	}
+}
+
+func TestWalkZoneNSEC3(t *testing.T) {
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(100, walkTestServer(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	walk, err := WalkZone(ctx, p, "nsec3.test")
+	if err != nil {
+		t.Fatalf("The NSEC3 hash collection failed: %v", err)
+	}
+	if walk.NSEC3 == nil {
+		t.Fatalf("The NSEC3 zone was not detected")
+	}
+	if !walk.NSEC3.Complete() || walk.NSEC3.Len() != 4 {
+		t.Errorf("Failed to collect the entire NSEC3 chain: %d hashes", walk.NSEC3.Len())
+	}
+
+	names := walk.NSEC3.Crack([]string{"www", "ftp", "mail", "dev", "www"})
+	sort.Strings(names)
+	expected := []string{"dev.nsec3.test", "mail.nsec3.test", "www.nsec3.test"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Got %v, expected %v", names, expected)
+	}
+}
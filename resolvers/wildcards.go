@@ -0,0 +1,383 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+// DefaultWildcardThreshold is the confidence at which a response is considered a DNS wildcard match.
+const DefaultWildcardThreshold = 0.5
+
+const (
+	// Number of unlikely names queried when a subdomain is first tested for a DNS wildcard
+	initialWildcardProbes = 3
+	// Maximum number of unlikely names queried for a DNS wildcard providing rotating answers
+	maxWildcardProbes     = 12
+	wildcardProbeInterval = 30 * time.Second
+	// Evidence provided by answers related to a rotating DNS wildcard without matching it exactly
+	patternEvidence = 0.75
+	networkEvidence = 0.6
+	ttlEvidence     = 0.2
+)
+
+var wildcardQueryTypes = []uint16{
+	dns.TypeA,
+	dns.TypeAAAA,
+}
+
+// wildcard is the fingerprint of the answers provided for unlikely names within a subdomain.
+// Each count is the number of probes that provided the value.
+type wildcard struct {
+	sync.Mutex
+	sub      string
+	probes   int
+	answered int
+	last     time.Time
+	ips      map[string]int
+	nets     map[string]int
+	targets  map[string]int
+	patterns map[string]int
+	ttls     map[uint32]int
+}
+
+func newWildcard(sub string) *wildcard {
+	return &wildcard{
+		sub:      sub,
+		ips:      make(map[string]int),
+		nets:     make(map[string]int),
+		targets:  make(map[string]int),
+		patterns: make(map[string]int),
+		ttls:     make(map[uint32]int),
+	}
+}
+
+// SetDetectionResolver sets the provided DNS resolver as responsible for wildcard detection.
+func (p *Pool) SetDetectionResolver(qps int, addr string) {
+	res, err := p.newResolver(strings.TrimSpace(addr))
+	if err != nil {
+		p.log.Printf("Failed to set the %s wildcard detection resolver: %v", addr, err)
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.detector != nil && !p.isMember(p.detector) {
+		p.detector.res.Stop()
+	}
+	p.detector = &member{
+		res:   res,
+		rate:  newAdaptiveRate(qps),
+		stats: new(healthStats),
+	}
+	p.wildcards = make(map[string]*wildcard)
+}
+
+// SetWildcardThreshold sets the confidence at which WildcardDetected reports a DNS wildcard match.
+func (p *Pool) SetWildcardThreshold(threshold float64) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.threshold = threshold
+}
+
+// WildcardDetected returns true when the provided DNS response could be a wildcard match.
+func (p *Pool) WildcardDetected(ctx context.Context, resp *dns.Msg, domain string) bool {
+	p.Lock()
+	threshold := p.threshold
+	p.Unlock()
+
+	conf := p.WildcardConfidence(ctx, resp, domain)
+	return conf > 0 && conf >= threshold
+}
+
+// WildcardConfidence returns the confidence, between zero and one, that the provided DNS
+// response was provided by a DNS wildcard within the domain. Subdomains having wildcards
+// that rotate their answers are probed again over time to improve the fingerprint.
+func (p *Pool) WildcardConfidence(ctx context.Context, resp *dns.Msg, domain string) float64 {
+	if resp == nil || len(resp.Question) == 0 {
+		return 0
+	}
+	// The zones with their own resolvers are tested using those resolvers
+	if zp := p.zoneResolvers(resp.Question[0].Name); zp != nil {
+		return zp.WildcardConfidence(ctx, resp, domain)
+	}
+
+	name := strings.ToLower(resolve.RemoveLastDot(resp.Question[0].Name))
+	domain = strings.ToLower(resolve.RemoveLastDot(domain))
+
+	base := len(strings.Split(domain, "."))
+	labels := strings.Split(name, ".")
+	if len(labels) > base {
+		labels = labels[1:]
+	}
+
+	var conf float64
+	// Check for a DNS wildcard at each label starting with the root domain
+	for i := len(labels) - base; i >= 0; i-- {
+		w := p.getWildcard(ctx, strings.Join(labels[i:], "."))
+		if w == nil {
+			break
+		}
+
+		if c := w.confidence(resp); c > conf {
+			conf = c
+		}
+		if conf >= 1 {
+			break
+		}
+	}
+	return conf
+}
+
+func (p *Pool) getWildcard(ctx context.Context, sub string) *wildcard {
+	p.Lock()
+	detector := p.detector
+	if detector == nil {
+		p.Unlock()
+		return nil
+	}
+
+	w, found := p.wildcards[sub]
+	if !found {
+		w = newWildcard(sub)
+		p.wildcards[sub] = w
+	}
+	w.Lock()
+	p.Unlock()
+	defer w.Unlock()
+
+	if !found {
+		for i := 0; i < initialWildcardProbes; i++ {
+			p.wildcardProbe(ctx, detector, w)
+		}
+		if w.answered > 0 {
+			p.log.Printf("DNS wildcard detected: Resolver %s: *.%s (%s)", detector.res.String(), sub, w.describe())
+		}
+	} else if w.rotating() && w.probes < maxWildcardProbes && time.Since(w.last) >= wildcardProbeInterval {
+		p.wildcardProbe(ctx, detector, w)
+	}
+	return w
+}
+
+// Queries an unlikely name within the subdomain and adds the answers to the fingerprint.
+func (p *Pool) wildcardProbe(ctx context.Context, m *member, w *wildcard) {
+	var name string
+	for name == "" {
+		name = resolve.UnlikelyName(w.sub)
+	}
+	label := strings.Split(name, ".")[0]
+
+	var definitive bool
+	var answers []dns.RR
+	for _, qtype := range wildcardQueryTypes {
+		resp, ok := p.wildcardQuery(ctx, m, name, qtype)
+		if ok {
+			definitive = true
+			answers = append(answers, resp.Answer...)
+		}
+	}
+
+	w.last = time.Now()
+	// Probes failing to obtain a response do not count against the wildcard
+	if definitive {
+		w.add(answers, label)
+	}
+}
+
+// Returns true when the response indicates if the name exists.
+func (p *Pool) wildcardQuery(ctx context.Context, m *member, name string, qtype uint16) (*dns.Msg, bool) {
+	for i := 0; i < maxQueryAttempts; i++ {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		default:
+		}
+
+		m.rate.Take()
+		resp, err := m.res.Exchange(ctx, p.applyEDNS0(resolve.QueryMsg(name, qtype)))
+		if err == nil && resp != nil && (resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError) {
+			return resp, true
+		}
+	}
+	return nil, false
+}
+
+func (w *wildcard) add(answers []dns.RR, label string) {
+	w.probes++
+	if len(answers) == 0 {
+		return
+	}
+	w.answered++
+
+	ips := make(map[string]struct{})
+	nets := make(map[string]struct{})
+	targets := make(map[string]struct{})
+	patterns := make(map[string]struct{})
+	ttls := make(map[uint32]struct{})
+	for _, rr := range answers {
+		ttls[rr.Header().Ttl] = struct{}{}
+
+		switch v := rr.(type) {
+		case *dns.A:
+			ips[v.A.String()] = struct{}{}
+			nets[networkKey(v.A)] = struct{}{}
+		case *dns.AAAA:
+			ips[v.AAAA.String()] = struct{}{}
+			nets[networkKey(v.AAAA)] = struct{}{}
+		case *dns.CNAME:
+			target := strings.ToLower(resolve.RemoveLastDot(v.Target))
+
+			targets[target] = struct{}{}
+			for _, pattern := range targetPatterns(target, label) {
+				patterns[pattern] = struct{}{}
+			}
+		}
+	}
+
+	for ip := range ips {
+		w.ips[ip]++
+	}
+	for n := range nets {
+		w.nets[n]++
+	}
+	for t := range targets {
+		w.targets[t]++
+	}
+	for pattern := range patterns {
+		w.patterns[pattern]++
+	}
+	for ttl := range ttls {
+		w.ttls[ttl]++
+	}
+}
+
+// Returns true when no address or CNAME target was provided by every probe answered by the wildcard.
+func (w *wildcard) rotating() bool {
+	if w.answered == 0 {
+		return false
+	}
+
+	for _, counts := range []map[string]int{w.ips, w.targets} {
+		for _, c := range counts {
+			if c == w.answered {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Returns the confidence that the response was provided by the wildcard. The fraction of probes
+// answered by the wildcard is scaled by the strongest evidence found in the response answers.
+func (w *wildcard) confidence(resp *dns.Msg) float64 {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.answered == 0 {
+		return 0
+	}
+
+	consistency := float64(w.answered) / float64(w.probes)
+	if len(resp.Answer) == 0 {
+		return consistency
+	}
+
+	label := strings.Split(strings.ToLower(resp.Question[0].Name), ".")[0]
+	rotating := w.rotating()
+
+	var evidence float64
+	for _, rr := range resp.Answer {
+		var ev float64
+
+		switch v := rr.(type) {
+		case *dns.A:
+			ev = w.addressEvidence(v.A, rotating)
+		case *dns.AAAA:
+			ev = w.addressEvidence(v.AAAA, rotating)
+		case *dns.CNAME:
+			target := strings.ToLower(resolve.RemoveLastDot(v.Target))
+
+			if w.targets[target] > 0 {
+				ev = 1
+				break
+			}
+			for _, pattern := range targetPatterns(target, label) {
+				if w.patterns[pattern] != w.answered {
+					continue
+				}
+				// Targets containing the queried label are stronger evidence than a shared parent domain
+				if strings.HasPrefix(pattern, "*.") {
+					ev = patternEvidence
+				} else {
+					ev = 1
+					break
+				}
+			}
+		}
+
+		if ev > 0 && ev < 1 && w.ttls[rr.Header().Ttl] > 0 {
+			ev += ttlEvidence
+		}
+		if ev > evidence {
+			evidence = ev
+		}
+	}
+
+	if evidence > 1 {
+		evidence = 1
+	}
+	return consistency * evidence
+}
+
+func (w *wildcard) addressEvidence(ip net.IP, rotating bool) float64 {
+	if w.ips[ip.String()] > 0 {
+		return 1
+	}
+	// Addresses from the same network are only considered when the wildcard rotates its answers
+	if c := w.nets[networkKey(ip)]; rotating && c > 0 {
+		return networkEvidence * float64(c) / float64(w.answered)
+	}
+	return 0
+}
+
+func (w *wildcard) describe() string {
+	desc := "static answers"
+	if w.rotating() {
+		desc = "rotating answers"
+	}
+	return fmt.Sprintf("%s, %d/%d probes answered", desc, w.answered, w.probes)
+}
+
+// Returns the patterns that identify CNAME targets generated for each query: the target with the
+// queried label replaced, and the parent domain of a target using a random label.
+func targetPatterns(target, label string) []string {
+	var patterns []string
+
+	if label != "" && strings.Contains(target, label) {
+		patterns = append(patterns, strings.ReplaceAll(target, label, "{label}"))
+	}
+	if parts := strings.SplitN(target, ".", 2); len(parts) == 2 && strings.Contains(parts[1], ".") {
+		patterns = append(patterns, "*."+parts[1])
+	}
+	return patterns
+}
+
+// Returns the /24 network for IPv4 addresses and the /64 network for IPv6 addresses.
+func networkKey(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 32)).String() + "/24"
+	}
+	return ip.Mask(net.CIDRMask(64, 128)).String() + "/64"
+}
@@ -0,0 +1,92 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func cnameRecord(name, target string) *dns.CNAME {
+	return &dns.CNAME{
+		Hdr:    dns.RR_Header{Name: name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: 300},
+		Target: target,
+	}
+}
+
+func answerMsg(name string, answers ...dns.RR) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), dns.TypeA)
+	msg.Answer = answers
+	return msg
+}
+
+func TestPoolWildcardConfidence(t *testing.T) {
+	var next uint32
+	// The rotating.com wildcard provides a different address for each query, and the
+	// cname.com wildcard provides a CNAME target generated from the queried label
+	addr := startTestServer(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+		q := req.Question[0]
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		name := strings.ToLower(q.Name)
+		switch {
+		case q.Qtype != dns.TypeA:
+		case name == "www.static.com.":
+			resp.Answer = append(resp.Answer, aRecord(name, "192.168.1.1"))
+		case strings.HasSuffix(name, ".rotating.com."):
+			ip := fmt.Sprintf("10.0.0.%d", atomic.AddUint32(&next, 1))
+			a := aRecord(name, ip)
+			a.Hdr.Ttl = 60
+			resp.Answer = append(resp.Answer, a)
+		case strings.HasSuffix(name, ".cname.com."):
+			label := strings.Split(name, ".")[0]
+			resp.Answer = append(resp.Answer, cnameRecord(name, label+".cname.com.edge.net."))
+		default:
+			resp.Rcode = dns.RcodeNameError
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	p := NewPool()
+	defer p.Stop()
+	_ = p.AddResolvers(100, addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rotated := aRecord("www.rotating.com.", "10.0.0.200")
+	rotated.Hdr.Ttl = 60
+	tests := []struct {
+		msg      *dns.Msg
+		domain   string
+		min, max float64
+	}{
+		{answerMsg("www.static.com", aRecord("www.static.com.", "192.168.1.1")), "static.com", 0, 0},
+		{answerMsg("www.rotating.com", rotated), "rotating.com", DefaultWildcardThreshold, 1},
+		{answerMsg("mail.rotating.com", aRecord("mail.rotating.com.", "192.168.1.1")), "rotating.com", 0, 0},
+		{answerMsg("www.cname.com", cnameRecord("www.cname.com.", "www.cname.com.edge.net.")), "cname.com", 1, 1},
+		{answerMsg("mail.cname.com", cnameRecord("mail.cname.com.", "mail.example.net.")), "cname.com", 0, 0},
+	}
+
+	for _, test := range tests {
+		name := test.msg.Question[0].Name
+
+		conf := p.WildcardConfidence(ctx, test.msg, test.domain)
+		if conf < test.min || conf > test.max {
+			t.Errorf("%s: got a confidence of %.2f, expected between %.2f and %.2f", name, conf, test.min, test.max)
+		}
+		if detected := p.WildcardDetected(ctx, test.msg, test.domain); detected != (test.min >= DefaultWildcardThreshold) {
+			t.Errorf("%s: WildcardDetected returned %t", name, detected)
+		}
+	}
+}
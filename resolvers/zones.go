@@ -0,0 +1,51 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"strings"
+
+	"github.com/caffix/resolve"
+)
+
+// SetZoneResolvers assigns the pool that receives the queries for names within the zone, instead of
+// the resolvers in this pool. This supports split-horizon DNS, where internal zones are only answered
+// by specific name servers. The pool is not stopped with this pool, and a nil value removes the override.
+func (p *Pool) SetZoneResolvers(zone string, zp *Pool) {
+	zone = strings.ToLower(strings.Trim(strings.TrimSpace(zone), "."))
+	if zone == "" {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if zp == nil {
+		delete(p.zones, zone)
+		return
+	}
+	if p.zones == nil {
+		p.zones = make(map[string]*Pool)
+	}
+	p.zones[zone] = zp
+}
+
+// Returns the pool assigned to the most specific zone containing the name, or nil when not overridden.
+func (p *Pool) zoneResolvers(name string) *Pool {
+	p.Lock()
+	defer p.Unlock()
+
+	if len(p.zones) == 0 {
+		return nil
+	}
+
+	name = strings.ToLower(resolve.RemoveLastDot(name))
+	for labels := strings.Split(name, "."); len(labels) > 0; labels = labels[1:] {
+		if zp, found := p.zones[strings.Join(labels, ".")]; found {
+			return zp
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+func TestPoolZoneResolvers(t *testing.T) {
+	server := func(addr string) string {
+		return startTestServer(t, "127.0.0.1:0", func(w dns.ResponseWriter, req *dns.Msg) {
+			resp := new(dns.Msg)
+			resp.SetReply(req)
+			resp.Answer = append(resp.Answer, aRecord(req.Question[0].Name, addr))
+			_ = w.WriteMsg(resp)
+		})
+	}
+
+	public := NewPool()
+	defer public.Stop()
+	_ = public.AddResolvers(100, server("192.0.2.1"))
+
+	internal := NewPool()
+	defer internal.Stop()
+	_ = internal.AddResolvers(100, server("10.0.0.1"))
+	public.SetZoneResolvers("Corp.Example.com.", internal)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"www.example.com", "192.0.2.1"},
+		{"corp.example.com", "10.0.0.1"},
+		{"mail.corp.example.com", "10.0.0.1"},
+		{"notcorp.example.com", "192.0.2.1"},
+	}
+	for _, test := range tests {
+		resp, err := public.QueryBlocking(ctx, resolve.QueryMsg(test.name, dns.TypeA))
+		if err != nil || len(resp.Answer) != 1 {
+			t.Errorf("%s: the query failed", test.name)
+			continue
+		}
+		if a, ok := resp.Answer[0].(*dns.A); !ok || a.A.String() != test.expected {
+			t.Errorf("%s: got %v, expected the answer from %s", test.name, resp.Answer[0], test.expected)
+		}
+	}
+
+	public.SetZoneResolvers("corp.example.com", nil)
+	if resp, err := public.QueryBlocking(ctx, resolve.QueryMsg("corp.example.com", dns.TypeA)); err != nil ||
+		len(resp.Answer) != 1 || resp.Answer[0].(*dns.A).A.String() != "192.0.2.1" {
+		t.Errorf("The zone override was not removed")
+	}
+}
@@ -7,15 +7,19 @@ import (
 	"compress/gzip"
 	"embed"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
+	"io/ioutil"
 	"net"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 )
 
-//go:embed scripts ip2asn-combined.tsv.gz alterations.txt namelist.txt user_agents.txt
+//go:embed scripts ip2asn-combined.tsv.gz alterations.txt namelist.txt takeovers.json user_agents.txt
 var resourceFS embed.FS
 
 // IP2ASN is a range record provided by the iptoasn.com service.
@@ -68,6 +72,108 @@ func GetIP2ASNData() ([]*IP2ASN, error) {
 	return ranges, nil
 }
 
+// TakeoverFingerprint identifies a service prone to subdomain takeovers when a name is left
+// pointing at a resource that is no longer claimed.
+type TakeoverFingerprint struct {
+	Service     string   `json:"service"`
+	CNAMEs      []string `json:"cname"`
+	NSs         []string `json:"ns"`
+	Fingerprint string   `json:"fingerprint"`
+	NXDomain    bool     `json:"nxdomain"`
+	cnameREs    []*regexp.Regexp
+	nsREs       []*regexp.Regexp
+}
+
+// MatchCNAME returns true when the CNAME target belongs to the service.
+func (t *TakeoverFingerprint) MatchCNAME(name string) bool {
+	return matchAny(t.cnameREs, name)
+}
+
+// MatchNS returns true when the name server belongs to the service.
+func (t *TakeoverFingerprint) MatchNS(name string) bool {
+	return matchAny(t.nsREs, name)
+}
+
+// MatchBody returns true when the web page served for the name shows the resource is not claimed.
+func (t *TakeoverFingerprint) MatchBody(body string) bool {
+	return t.Fingerprint != "" && strings.Contains(body, t.Fingerprint)
+}
+
+func matchAny(res []*regexp.Regexp, name string) bool {
+	for _, re := range res {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTakeoverFingerprints returns the services prone to subdomain takeovers read from the 'takeovers.json' file.
+func GetTakeoverFingerprints() ([]*TakeoverFingerprint, error) {
+	data, err := resourceFS.ReadFile("takeovers.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the 'takeovers.json' file: %v", err)
+	}
+	return parseTakeoverFingerprints(data, "takeovers.json")
+}
+
+// LoadTakeoverFingerprints returns the fingerprints of the 'takeovers.json' file updated by the file
+// at the path, which replaces the fingerprints of the same services and adds the new services.
+func LoadTakeoverFingerprints(path string) ([]*TakeoverFingerprint, error) {
+	fps, err := GetTakeoverFingerprints()
+	if err != nil || path == "" {
+		return fps, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the takeover fingerprints file %s: %v", path, err)
+	}
+	updates, err := parseTakeoverFingerprints(data, path)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[string]int, len(fps))
+	for i, fp := range fps {
+		services[strings.ToLower(fp.Service)] = i
+	}
+	for _, fp := range updates {
+		if i, found := services[strings.ToLower(fp.Service)]; found {
+			fps[i] = fp
+			continue
+		}
+		services[strings.ToLower(fp.Service)] = len(fps)
+		fps = append(fps, fp)
+	}
+	return fps, nil
+}
+
+func parseTakeoverFingerprints(data []byte, file string) ([]*TakeoverFingerprint, error) {
+	var fps []*TakeoverFingerprint
+	if err := json.Unmarshal(data, &fps); err != nil {
+		return nil, fmt.Errorf("failed to parse the '%s' file: %v", file, err)
+	}
+
+	for _, fp := range fps {
+		for _, p := range fp.CNAMEs {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("the %s CNAME pattern %s is not valid: %v", fp.Service, p, err)
+			}
+			fp.cnameREs = append(fp.cnameREs, re)
+		}
+		for _, p := range fp.NSs {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("the %s NS pattern %s is not valid: %v", fp.Service, p, err)
+			}
+			fp.nsREs = append(fp.nsREs, re)
+		}
+	}
+	return fps, nil
+}
+
 func GetDefaultScripts() ([]string, error) {
 	var scripts []string
 
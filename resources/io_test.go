@@ -2,6 +2,9 @@ package resources
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -20,3 +23,70 @@ func TestGetDefaultScripts(t *testing.T) {
 	}
 }
 
+func TestGetTakeoverFingerprints(t *testing.T) {
+	fps, err := GetTakeoverFingerprints()
+	if err != nil {
+		t.Fatalf("GetTakeoverFingerprints() error = %v", err)
+	}
+
+	var cname, ns bool
+	for _, fp := range fps {
+		if fp.MatchCNAME("example.github.io") {
+			cname = true
+		}
+		if fp.MatchNS("ns-1234.awsdns-12.org") {
+			ns = true
+		}
+		if fp.MatchCNAME("www.example.com") || fp.MatchNS("ns1.example.com") {
+			t.Errorf("the %s fingerprint matched a name outside of the service", fp.Service)
+		}
+	}
+	if !cname || !ns {
+		t.Errorf("the fingerprints failed to match the CNAME and NS records of the services")
+	}
+}
+
+func TestLoadTakeoverFingerprints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "takeovers")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "takeovers.json")
+	if err := ioutil.WriteFile(path, []byte(`[
+		{"service": "GitHub Pages", "cname": ["\\.github\\.example$"], "fingerprint": "Updated"},
+		{"service": "Example Hosting", "cname": ["\\.hosting\\.example$"], "nxdomain": true}
+	]`), 0644); err != nil {
+		t.Fatalf("Failed to write the fingerprints file: %v", err)
+	}
+
+	defaults, _ := GetTakeoverFingerprints()
+	fps, err := LoadTakeoverFingerprints(path)
+	if err != nil {
+		t.Fatalf("LoadTakeoverFingerprints() error = %v", err)
+	}
+	if len(fps) != len(defaults)+1 {
+		t.Errorf("%d fingerprints were loaded, expected %d", len(fps), len(defaults)+1)
+	}
+
+	var updated, added bool
+	for _, fp := range fps {
+		switch fp.Service {
+		case "GitHub Pages":
+			updated = fp.MatchCNAME("www.github.example") && !fp.MatchCNAME("example.github.io") && fp.MatchBody("The page was Updated")
+		case "Example Hosting":
+			added = fp.MatchCNAME("app.hosting.example") && fp.NXDomain
+		}
+	}
+	if !updated || !added {
+		t.Errorf("The fingerprints file did not update the services")
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`[{"service": "Bad", "cname": ["("]}]`), 0644); err != nil {
+		t.Fatalf("Failed to write the fingerprints file: %v", err)
+	}
+	if _, err := LoadTakeoverFingerprints(path); err == nil {
+		t.Errorf("The invalid pattern was accepted")
+	}
+}
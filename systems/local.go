@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"sync"
@@ -18,6 +19,7 @@ import (
 	"github.com/OWASP/Amass/v3/limits"
 	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/OWASP/Amass/v3/resources"
 	"github.com/caffix/netmap"
 	"github.com/caffix/resolve"
@@ -27,8 +29,10 @@ import (
 // LocalSystem implements a System to be executed within a single process.
 type LocalSystem struct {
 	Cfg               *config.Config
-	pool              *resolve.Resolvers
-	trusted           *resolve.Resolvers
+	pool              *resolvers.Pool
+	trusted           *resolvers.Pool
+	zonePools         []*resolvers.Pool
+	dnsCache          *resolvers.DiskCache
 	graphs            []*netmap.Graph
 	cache             *requests.ASNCache
 	done              chan struct{}
@@ -78,6 +82,11 @@ func NewLocalSystem(cfg *config.Config) (*LocalSystem, error) {
 		allSources: make(chan chan []service.Service, 10),
 	}
 
+	// Send the queries for split-horizon zones to the resolvers provided for them
+	if err := sys.setupDomainResolvers(); err != nil {
+		_ = sys.Shutdown()
+		return nil, err
+	}
 	// Load the ASN information into the cache
 	if err := sys.loadCacheData(); err != nil {
 		_ = sys.Shutdown()
@@ -88,6 +97,10 @@ func NewLocalSystem(cfg *config.Config) (*LocalSystem, error) {
 		_ = sys.Shutdown()
 		return nil, err
 	}
+	// Share the DNS responses with later enumerations when requested
+	if cfg.DNSCache {
+		sys.setupDNSCache()
+	}
 	// Setup the correct graph database handler
 	if err := sys.setupGraphDBs(); err != nil {
 		_ = sys.Shutdown()
@@ -104,12 +117,12 @@ func (l *LocalSystem) Config() *config.Config {
 }
 
 // Resolvers implements the System interface.
-func (l *LocalSystem) Resolvers() *resolve.Resolvers {
+func (l *LocalSystem) Resolvers() *resolvers.Pool {
 	return l.pool
 }
 
 // TrustedResolvers implements the System interface.
-func (l *LocalSystem) TrustedResolvers() *resolve.Resolvers {
+func (l *LocalSystem) TrustedResolvers() *resolvers.Pool {
 	return l.trusted
 }
 
@@ -198,6 +211,12 @@ func (l *LocalSystem) Shutdown() error {
 
 	l.pool.Stop()
 	l.trusted.Stop()
+	for _, zp := range l.zonePools {
+		zp.Stop()
+	}
+	if l.dnsCache != nil {
+		_ = l.dnsCache.Close()
+	}
 	l.cache = nil
 	return nil
 }
@@ -217,6 +236,42 @@ func (l *LocalSystem) setupOutputDirectory() error {
 	return nil
 }
 
+// The DNS cache is optional, so the enumeration continues without it when the file cannot be opened.
+func (l *LocalSystem) setupDNSCache() {
+	path := filepath.Join(config.OutputDirectory(l.Cfg.Dir), "dns_cache.db")
+
+	c, err := resolvers.OpenDiskCache(path)
+	if err != nil {
+		l.Cfg.Log.Printf("System: Failed to open the DNS cache at %s: %v", path, err)
+		return
+	}
+
+	l.dnsCache = c
+	l.pool.SetDiskCache(c, "untrusted")
+	l.trusted.SetDiskCache(c, "trusted")
+}
+
+// Each zone provided with its own resolvers receives a pool shared by the untrusted and trusted resolvers.
+func (l *LocalSystem) setupDomainResolvers() error {
+	for zone, addrs := range l.Cfg.DomainResolvers {
+		zp := resolvers.NewPool()
+		l.zonePools = append(l.zonePools, zp)
+
+		zp.SetLogger(l.Cfg.Log)
+		zp.SetEDNS0Options(edns0Options(l.Cfg))
+		if err := zp.AddResolvers(l.Cfg.TrustedQPS, addrs...); err != nil {
+			return fmt.Errorf("System: Failed to add the resolvers for the %s zone: %v", zone, err)
+		}
+		zp.SetAdaptiveQPS(true)
+		zp.SetNegativeCaching(true)
+		zp.SetRRLDetection(true)
+
+		l.pool.SetZoneResolvers(zone, zp)
+		l.trusted.SetZoneResolvers(zone, zp)
+	}
+	return nil
+}
+
 // Select the graph that will store the System findings.
 func (l *LocalSystem) setupGraphDBs() error {
 	cfg := l.Config()
@@ -228,7 +283,12 @@ func (l *LocalSystem) setupGraphDBs() error {
 	dbs = append(dbs, cfg.GraphDBs...)
 
 	for _, db := range dbs {
-		cayley := netmap.NewCayleyGraph(db.System, db.URL, db.Options)
+		u, err := db.ConnectionURL()
+		if err != nil {
+			return fmt.Errorf("System: %v", err)
+		}
+
+		cayley := netmap.NewCayleyGraph(db.System, u, db.Options)
 		if cayley == nil {
 			return fmt.Errorf("System: Failed to create the %s graph", db.System)
 		}
@@ -295,24 +355,33 @@ func (l *LocalSystem) loadCacheData() error {
 	return nil
 }
 
-func trustedResolvers(cfg *config.Config, max int) (*resolve.Resolvers, int) {
+func trustedResolvers(cfg *config.Config, max int) (*resolvers.Pool, int) {
 	var num int
-	pool := resolve.NewResolvers()
+	pool := resolvers.NewPool()
 
 	if len(cfg.TrustedResolvers) > 0 {
-		num = len(cfg.TrustedResolvers)
-		_ = pool.AddResolvers(cfg.TrustedQPS, cfg.TrustedResolvers...)
+		addrs := resolvers.FilterByTransport(cfg.TrustedResolvers, cfg.PreferIPv6)
+
+		num = len(addrs)
+		_ = pool.AddResolvers(cfg.TrustedQPS, addrs...)
 	} else {
-		num = len(config.DefaultBaselineResolvers)
-		_ = pool.AddResolvers(cfg.TrustedQPS, config.DefaultBaselineResolvers...)
-		pool.SetDetectionResolver(cfg.TrustedQPS, "8.8.8.8")
+		addrs := baselineResolvers(cfg)
+
+		num = len(addrs)
+		_ = pool.AddResolvers(cfg.TrustedQPS, addrs...)
+		// The first baseline resolver is Google for both transports
+		pool.SetDetectionResolver(cfg.TrustedQPS, addrs[0])
 	}
 
 	pool.SetLogger(cfg.Log)
+	pool.SetEDNS0Options(edns0Options(cfg))
+	pool.SetAdaptiveQPS(true)
+	pool.SetNegativeCaching(true)
+	pool.SetRRLDetection(true)
 	return pool, num
 }
 
-func untrustedResolvers(cfg *config.Config, max int) (*resolve.Resolvers, int) {
+func untrustedResolvers(cfg *config.Config, max int) (*resolvers.Pool, int) {
 	if max <= 0 {
 		return nil, 0
 	}
@@ -321,31 +390,62 @@ func untrustedResolvers(cfg *config.Config, max int) (*resolve.Resolvers, int) {
 			return pool, num
 		}
 		// Failed to use the public DNS resolvers database
-		cfg.Resolvers = config.DefaultBaselineResolvers
+		cfg.Resolvers = baselineResolvers(cfg)
 	}
 	return customResolverSetup(cfg, max)
 }
 
-func customResolverSetup(cfg *config.Config, max int) (*resolve.Resolvers, int) {
+// Returns the baseline resolvers reachable over IPv6 when the transport is preferred, or is the only one available.
+func baselineResolvers(cfg *config.Config) []string {
+	if resolvers.IPv6Available() && (cfg.PreferIPv6 || !resolvers.IPv4Available()) {
+		return config.DefaultBaselineResolversIPv6
+	}
+	return config.DefaultBaselineResolvers
+}
+
+func customResolverSetup(cfg *config.Config, max int) (*resolvers.Pool, int) {
+	cfg.Resolvers = resolvers.FilterByTransport(cfg.Resolvers, cfg.PreferIPv6)
+
 	num := len(cfg.Resolvers)
 	if num > max {
 		num = max
 		cfg.Resolvers = cfg.Resolvers[:num]
 	}
 
-	pool := resolve.NewResolvers()
+	pool := resolvers.NewPool()
 	pool.SetLogger(cfg.Log)
+	pool.SetEDNS0Options(edns0Options(cfg))
 	_ = pool.AddResolvers(cfg.ResolversQPS, cfg.Resolvers...)
-	pool.SetThresholdOptions(&resolve.ThresholdOptions{
-		ThresholdValue:      200,
-		CountTimeouts:       true,
-		CountServerFailures: true,
-		CountQueryRefusals:  true,
-	})
+	pool.SetHealthOptions(resolvers.DefaultHealthOptions())
+	pool.SetAdaptiveQPS(true)
+	pool.SetNegativeCaching(true)
+	pool.SetRRLDetection(true)
 	return pool, num
 }
 
-func publicResolverSetup(cfg *config.Config, max int) (*resolve.Resolvers, int) {
+// Returns the EDNS0 settings for the resolver pools, or nil when the defaults should be used.
+func edns0Options(cfg *config.Config) *resolvers.EDNS0Options {
+	if cfg.EDNS0BufferSize == 0 && !cfg.EDNS0Cookies && cfg.EDNS0ClientSubnet == "" {
+		return nil
+	}
+
+	opts := &resolvers.EDNS0Options{
+		BufferSize: uint16(cfg.EDNS0BufferSize),
+		Cookies:    cfg.EDNS0Cookies,
+	}
+	if cfg.EDNS0ClientSubnet == "none" {
+		opts.DisableClientSubnet = true
+	} else if cfg.EDNS0ClientSubnet != "" {
+		if subnet, err := resolvers.ParseClientSubnet(cfg.EDNS0ClientSubnet); err == nil {
+			opts.ClientSubnet = subnet
+		} else {
+			cfg.Log.Printf("%v", err)
+		}
+	}
+	return opts
+}
+
+func publicResolverSetup(cfg *config.Config, max int) (*resolvers.Pool, int) {
 	addrs := config.PublicResolvers
 	num := len(config.PublicResolvers)
 
@@ -363,19 +463,21 @@ func publicResolverSetup(cfg *config.Config, max int) (*resolve.Resolvers, int)
 	}
 
 	addrs = checkAddresses(addrs)
+	addrs = resolvers.FilterByTransport(addrs, cfg.PreferIPv6)
 	addrs = runSubnetChecks(addrs)
 
-	r := resolve.NewResolvers()
+	r := resolvers.NewPool()
 	r.SetLogger(cfg.Log)
+	r.SetEDNS0Options(edns0Options(cfg))
 	_ = r.AddResolvers(cfg.ResolversQPS, addrs...)
-	r.SetThresholdOptions(&resolve.ThresholdOptions{
-		ThresholdValue:      100,
-		CountTimeouts:       true,
-		CountFormatErrors:   true,
-		CountServerFailures: true,
-		CountNotImplemented: true,
-		CountQueryRefusals:  true,
-	})
+	// The public resolvers are held to a higher standard than the resolvers provided by the user
+	opts := resolvers.DefaultHealthOptions()
+	opts.MaxTimeoutRate = 0.25
+	opts.MaxServFailRate = 0.25
+	r.SetHealthOptions(opts)
+	r.SetAdaptiveQPS(true)
+	r.SetNegativeCaching(true)
+	r.SetRRLDetection(true)
 	return r, len(addrs)
 }
 
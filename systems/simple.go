@@ -9,15 +9,15 @@ import (
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/caffix/netmap"
-	"github.com/caffix/resolve"
 	"github.com/caffix/service"
 )
 
 type SimpleSystem struct {
 	Cfg      *config.Config
-	Pool     *resolve.Resolvers
-	Trusted  *resolve.Resolvers
+	Pool     *resolvers.Pool
+	Trusted  *resolvers.Pool
 	Graph    *netmap.Graph
 	ASNCache *requests.ASNCache
 	Service  service.Service
@@ -27,10 +27,10 @@ type SimpleSystem struct {
 func (ss *SimpleSystem) Config() *config.Config { return ss.Cfg }
 
 // Resolvers implements the System interface.
-func (ss *SimpleSystem) Resolvers() *resolve.Resolvers { return ss.Pool }
+func (ss *SimpleSystem) Resolvers() *resolvers.Pool { return ss.Pool }
 
 // TrustedResolvers implements the System interface.
-func (ss *SimpleSystem) TrustedResolvers() *resolve.Resolvers { return ss.Trusted }
+func (ss *SimpleSystem) TrustedResolvers() *resolvers.Pool { return ss.Trusted }
 
 // Cache implements the System interface.
 func (ss *SimpleSystem) Cache() *requests.ASNCache { return ss.ASNCache }
@@ -10,8 +10,8 @@ import (
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/caffix/netmap"
-	"github.com/caffix/resolve"
 	"github.com/caffix/service"
 )
 
@@ -21,10 +21,10 @@ type System interface {
 	Config() *config.Config
 
 	// Returns the pool that handles queries using untrusted DNS resolvers
-	Resolvers() *resolve.Resolvers
+	Resolvers() *resolvers.Pool
 
 	// Returns the pool that handles queries using trusted DNS resolvers
-	TrustedResolvers() *resolve.Resolvers
+	TrustedResolvers() *resolvers.Pool
 
 	// Returns the cache populated by the system
 	Cache() *requests.ASNCache
@@ -0,0 +1,291 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+	"golang.org/x/net/publicsuffix"
+)
+
+// The infrastructure that the names are collapsed into by ClusterGraph.
+const (
+	ClusterASN      = "asn"
+	ClusterNetblock = "netblock"
+)
+
+// The longest chain of CNAME records followed to the addresses of a name.
+const maxClusterCNAMEs = 10
+
+type cluster struct {
+	id        int
+	names     map[int]struct{}
+	sources   map[string]struct{}
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+type clusterBuilder struct {
+	nodes    []Node
+	clusters map[string]*cluster
+	links    map[Edge]map[int]struct{}
+}
+
+// ClusterGraph collapses the names into the autonomous systems or netblocks hosting their addresses,
+// producing a map of the providers hosting each domain. Each domain is linked to the clusters hosting
+// its names, and the edges are labeled with the number of names. The netblocks are linked to the
+// autonomous systems announcing them. The names without addresses are not represented.
+func ClusterGraph(nodes []Node, edges []Edge, by string) ([]Node, []Edge, error) {
+	if by != ClusterASN && by != ClusterNetblock {
+		return nil, nil, fmt.Errorf("the cluster type %s must be %s or %s", by, ClusterASN, ClusterNetblock)
+	}
+
+	out := make(map[int][]Edge)
+	for _, e := range edges {
+		out[e.From] = append(out[e.From], e)
+	}
+	// The netblocks and autonomous systems are identified using the graph
+	// when the nodes of the addresses do not provide them
+	containedBy := make(map[int]int)
+	announcedBy := make(map[int]int)
+	for _, e := range edges {
+		switch e.Title {
+		case "contains":
+			containedBy[e.To] = e.From
+		case "prefix":
+			announcedBy[e.To] = e.From
+		}
+	}
+
+	var domains []string
+	for _, n := range nodes {
+		if n.Type == "domain" {
+			domains = append(domains, n.Label)
+		}
+	}
+
+	b := &clusterBuilder{
+		clusters: make(map[string]*cluster),
+		links:    make(map[Edge]map[int]struct{}),
+	}
+	for _, n := range nodes {
+		if n.ActualType != netmap.TypeFQDN {
+			continue
+		}
+
+		for _, addr := range nameAddresses(n.ID, out) {
+			a := nodes[addr]
+
+			cidr, asn, desc := a.Netblock, a.ASN, ""
+			if block, found := containedBy[addr]; found {
+				cidr = nodes[block].Label
+
+				if id, found := announcedBy[block]; found {
+					if num, err := strconv.Atoi(nodes[id].Label); err == nil {
+						asn = num
+					}
+					desc = asDescription(nodes[id])
+				}
+			}
+
+			domain := nameDomain(n.Label, domains)
+			from := b.node(n, "domain:"+domain, Node{
+				Type:       "domain",
+				Label:      domain,
+				ActualType: netmap.TypeFQDN,
+			})
+			as := b.node(n, "as:"+strconv.Itoa(asn), asClusterNode(asn, desc))
+
+			if by == ClusterASN {
+				b.link(from, as, "hosted_by", n.ID)
+				continue
+			}
+			label := cidr
+			if label == "" {
+				label = "unknown"
+			}
+
+			block := b.node(n, "netblock:"+label, Node{
+				Type:       "netblock",
+				Label:      label,
+				ActualType: netmap.TypeNetblock,
+				ASN:        asn,
+				Netblock:   cidr,
+			})
+			b.link(from, block, "hosted_by", n.ID)
+			b.link(as, block, "prefix", -1)
+		}
+	}
+
+	return b.finish(), b.sortedEdges(), nil
+}
+
+// Returns the addresses of the name, following the CNAME records to the names holding them.
+func nameAddresses(id int, out map[int][]Edge) []int {
+	var addrs []int
+	seen := map[int]struct{}{id: {}}
+
+	for i, current := 0, []int{id}; i <= maxClusterCNAMEs && len(current) > 0; i++ {
+		var next []int
+
+		for _, cur := range current {
+			for _, e := range out[cur] {
+				switch e.Title {
+				case "a_record", "aaaa_record":
+					addrs = append(addrs, e.To)
+				case "cname_record":
+					if _, found := seen[e.To]; !found {
+						seen[e.To] = struct{}{}
+						next = append(next, e.To)
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return addrs
+}
+
+// Returns the domain of interest containing the name, or the registered domain of the name.
+func nameDomain(name string, domains []string) string {
+	var result string
+
+	for _, d := range domains {
+		if (name == d || strings.HasSuffix(name, "."+d)) && len(d) > len(result) {
+			result = d
+		}
+	}
+	if result != "" {
+		return result
+	}
+
+	if d, err := publicsuffix.EffectiveTLDPlusOne(name); err == nil {
+		return d
+	}
+	return name
+}
+
+func asDescription(n Node) string {
+	if i := strings.Index(n.Title, ", Desc: "); i >= 0 {
+		return n.Title[i+len(", Desc: "):]
+	}
+	return ""
+}
+
+func asClusterNode(asn int, desc string) Node {
+	if asn == 0 {
+		return Node{Type: "as", Label: "unknown", ActualType: netmap.TypeAS}
+	}
+
+	label := strconv.Itoa(asn)
+	if desc != "" {
+		desc = ", Desc: " + desc
+	}
+	return Node{
+		Type:       "as",
+		Label:      label,
+		Title:      "as: " + label + desc,
+		ActualType: netmap.TypeAS,
+		ASN:        asn,
+	}
+}
+
+// Returns the identifier of the cluster node, which is created when it is not already present,
+// and adds the name to the cluster.
+func (b *clusterBuilder) node(name Node, key string, n Node) int {
+	c, found := b.clusters[key]
+	if !found {
+		c = &cluster{
+			id:      len(b.nodes),
+			names:   make(map[int]struct{}),
+			sources: make(map[string]struct{}),
+		}
+		b.clusters[key] = c
+
+		n.ID = c.id
+		b.nodes = append(b.nodes, n)
+	}
+
+	c.names[name.ID] = struct{}{}
+	for _, src := range name.Sources {
+		c.sources[src] = struct{}{}
+	}
+	if name.Source != "" {
+		c.sources[name.Source] = struct{}{}
+	}
+	if !name.FirstSeen.IsZero() && (c.firstSeen.IsZero() || name.FirstSeen.Before(c.firstSeen)) {
+		c.firstSeen = name.FirstSeen
+	}
+	if name.LastSeen.After(c.lastSeen) {
+		c.lastSeen = name.LastSeen
+	}
+	return c.id
+}
+
+// Links the nodes, counting the names represented by the edge when name is not negative.
+func (b *clusterBuilder) link(from, to int, title string, name int) {
+	e := Edge{From: from, To: to, Title: title}
+
+	names, found := b.links[e]
+	if !found {
+		names = make(map[int]struct{})
+		b.links[e] = names
+	}
+	if name >= 0 {
+		names[name] = struct{}{}
+	}
+}
+
+// Completes the cluster nodes with the number of names, sources and times of the names they represent.
+func (b *clusterBuilder) finish() []Node {
+	for _, c := range b.clusters {
+		n := &b.nodes[c.id]
+
+		for src := range c.sources {
+			n.Sources = append(n.Sources, src)
+		}
+		sort.Strings(n.Sources)
+		if len(n.Sources) > 0 {
+			n.Source = n.Sources[0]
+		}
+		n.FirstSeen = c.firstSeen
+		n.LastSeen = c.lastSeen
+
+		title := n.Title
+		if title == "" {
+			title = n.Type + ": " + n.Label
+		}
+		n.Title = title + ", Names: " + strconv.Itoa(len(c.names))
+	}
+	return b.nodes
+}
+
+func (b *clusterBuilder) sortedEdges() []Edge {
+	var edges []Edge
+
+	for e, names := range b.links {
+		switch len(names) {
+		case 0:
+		case 1:
+			e.Label = "1 name"
+		default:
+			e.Label = fmt.Sprintf("%d names", len(names))
+		}
+		edges = append(edges, e)
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
@@ -0,0 +1,72 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"testing"
+)
+
+func TestClusterGraph(t *testing.T) {
+	nodes, edges := filterTestGraph()
+
+	if _, _, err := ClusterGraph(nodes, edges, "provider"); err == nil {
+		t.Errorf("ClusterGraph did not return an error for the unknown cluster type")
+	}
+
+	n, e, err := ClusterGraph(nodes, edges, ClusterASN)
+	if err != nil {
+		t.Fatalf("ClusterGraph returned an error: %v", err)
+	}
+
+	titles := make(map[string]string)
+	for _, node := range n {
+		titles[node.Label] = node.Title
+	}
+	expected := map[string]string{
+		"owasp.org":   "domain: owasp.org, Names: 3",
+		"example.com": "domain: example.com, Names: 1",
+		"64512":       "as: 64512, Names: 3",
+		"64513":       "as: 64513, Names: 1",
+	}
+	if len(titles) != len(expected) {
+		t.Errorf("The clusters were %v, expected %v", titles, expected)
+	}
+	for label, title := range expected {
+		if titles[label] != title {
+			t.Errorf("The cluster %s had the title %q, expected %q", label, titles[label], title)
+		}
+	}
+
+	labels := make(map[string]string)
+	for _, edge := range e {
+		labels[n[edge.From].Label+">"+n[edge.To].Label] = edge.Label
+	}
+	for link, label := range map[string]string{
+		"owasp.org>64512":   "2 names",
+		"owasp.org>64513":   "1 name",
+		"example.com>64512": "1 name",
+	} {
+		if labels[link] != label {
+			t.Errorf("The edge %s had the label %q, expected %q", link, labels[link], label)
+		}
+	}
+	if len(e) != 3 {
+		t.Errorf("ClusterGraph returned %d edges, expected 3", len(e))
+	}
+
+	n, e, err = ClusterGraph(nodes, edges, ClusterNetblock)
+	if err != nil {
+		t.Fatalf("ClusterGraph returned an error: %v", err)
+	}
+	// The address announced by AS64513 was not assigned to a netblock
+	if len(n) != 6 || len(e) != 5 {
+		t.Errorf("ClusterGraph returned %d nodes and %d edges, expected 6 and 5", len(n), len(e))
+	}
+	for _, node := range n {
+		if node.Type == "netblock" && node.Label == "192.168.1.0/24" && node.Title != "netblock: 192.168.1.0/24, Names: 3" {
+			t.Errorf("The netblock had the title %q", node.Title)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+)
+
+// NodeTypes contains the types assigned to the nodes of the visualizations.
+var NodeTypes = []string{"domain", "subdomain", "ns", "mx", "ptr", "address", "netblock", "as"}
+
+// Filter selects the names included in the visualization. The infrastructure connected
+// to the selected names, such as their addresses, netblocks and autonomous systems, is kept.
+type Filter struct {
+	// Only include the names within these domains
+	Domains []string
+	// Only include the names discovered by this data source
+	Source string
+	// Only include the names having this user-defined tag
+	Tag string
+	// Only include the names resolving to addresses announced by this autonomous system
+	ASN int
+	// Only include the names first seen within this interval, where the zero times are not applied
+	Since time.Time
+	Until time.Time
+	// Only include the nodes of these types, such as subdomain, address or netblock
+	Types []string
+}
+
+// Empty returns true when the filter does not exclude any nodes.
+func (f *Filter) Empty() bool {
+	return len(f.Domains) == 0 && f.Source == "" && f.Tag == "" && f.ASN == 0 &&
+		f.Since.IsZero() && f.Until.IsZero() && len(f.Types) == 0
+}
+
+func (f *Filter) allowed(n Node) bool {
+	if len(f.Domains) > 0 && !inDomains(n.Label, f.Domains) {
+		return false
+	}
+	if f.Source != "" && !stringInSlice(f.Source, n.Sources) && n.Source != f.Source {
+		return false
+	}
+	if f.Tag != "" && !stringInSlice(f.Tag, n.Tags) {
+		return false
+	}
+	if !f.Since.IsZero() && (n.FirstSeen.IsZero() || n.FirstSeen.Before(f.Since)) {
+		return false
+	}
+	if !f.Until.IsZero() && (n.FirstSeen.IsZero() || n.FirstSeen.After(f.Until)) {
+		return false
+	}
+	return true
+}
+
+func inDomains(name string, domains []string) bool {
+	for _, d := range domains {
+		d = strings.ToLower(d)
+
+		if name == d || strings.HasSuffix(name, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterGraph returns the names allowed by the filter and the infrastructure of those names, along with the
+// edges between the nodes that were kept. The infrastructure is reached by following the records of the names
+// to the addresses, and then the netblocks containing the addresses and the autonomous systems announcing them.
+func FilterGraph(nodes []Node, edges []Edge, f *Filter) ([]Node, []Edge) {
+	if f == nil || f.Empty() {
+		return nodes, edges
+	}
+
+	var announced map[int]struct{}
+	if f.ASN != 0 {
+		announced = namesInASN(nodes, edges, f.ASN)
+	}
+
+	keep := make(map[int]struct{})
+	names := make(map[int]struct{})
+	for _, n := range nodes {
+		if n.ActualType != netmap.TypeFQDN {
+			continue
+		}
+
+		names[n.ID] = struct{}{}
+		if _, found := announced[n.ID]; f.allowed(n) && (f.ASN == 0 || found) {
+			keep[n.ID] = struct{}{}
+		}
+	}
+
+	out := make(map[int][]int)
+	in := make(map[int][]int)
+	for _, e := range edges {
+		out[e.From] = append(out[e.From], e.To)
+		in[e.To] = append(in[e.To], e.From)
+	}
+	// The names lead to the infrastructure, which then leads to the nodes containing it
+	queue := make([]int, 0, len(keep))
+	for id := range keep {
+		queue = append(queue, id)
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		next := in[id]
+		if _, found := names[id]; found {
+			next = out[id]
+		}
+		for _, n := range next {
+			if _, found := keep[n]; found {
+				continue
+			}
+			if _, found := names[n]; found {
+				continue
+			}
+
+			keep[n] = struct{}{}
+			queue = append(queue, n)
+		}
+	}
+
+	if len(f.Types) > 0 {
+		for _, n := range nodes {
+			if !stringInSlice(n.Type, f.Types) {
+				delete(keep, n.ID)
+			}
+		}
+	}
+	return subgraph(nodes, edges, keep)
+}
+
+// Returns the names resolving to addresses announced by the autonomous system, including
+// the names that reach those names through CNAME records.
+func namesInASN(nodes []Node, edges []Edge, asn int) map[int]struct{} {
+	names := make(map[int]struct{})
+
+	for _, e := range edges {
+		if (e.Title == "a_record" || e.Title == "aaaa_record") && e.To < len(nodes) && nodes[e.To].ASN == asn {
+			names[e.From] = struct{}{}
+		}
+	}
+
+	for added := true; added; {
+		added = false
+
+		for _, e := range edges {
+			if e.Title != "cname_record" {
+				continue
+			}
+			if _, found := names[e.To]; !found {
+				continue
+			}
+			if _, found := names[e.From]; !found {
+				names[e.From] = struct{}{}
+				added = true
+			}
+		}
+	}
+	return names
+}
+
+// Neighborhood returns the nodes within the number of hops from the nodes having the label,
+// ignoring the direction of the edges, along with the edges between the nodes that were kept.
+func Neighborhood(nodes []Node, edges []Edge, label string, hops int) ([]Node, []Edge, error) {
+	dist := make(map[int]int)
+	var queue []int
+	for _, n := range nodes {
+		if strings.EqualFold(n.Label, label) {
+			dist[n.ID] = 0
+			queue = append(queue, n.ID)
+		}
+	}
+	if len(queue) == 0 {
+		return nil, nil, fmt.Errorf("the node %s was not found in the graph", label)
+	}
+
+	adj := make(map[int][]int)
+	for _, e := range edges {
+		adj[e.From] = append(adj[e.From], e.To)
+		adj[e.To] = append(adj[e.To], e.From)
+	}
+
+	keep := make(map[int]struct{})
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		keep[id] = struct{}{}
+		if dist[id] >= hops {
+			continue
+		}
+		for _, next := range adj[id] {
+			if _, found := dist[next]; !found {
+				dist[next] = dist[id] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	nodes, edges = subgraph(nodes, edges, keep)
+	return nodes, edges, nil
+}
+
+// ParseFilterTime accepts a date, such as 2022-04-01, or an RFC 3339 time. When end is true,
+// a date identifies the end of the day, so the interval includes the entire day.
+func ParseFilterTime(s string, end bool) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		if end {
+			t = t.Add(24*time.Hour - time.Nanosecond)
+		}
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("the time %s must be a date (2006-01-02) or an RFC 3339 time", s)
+	}
+	return t, nil
+}
+
+// Returns the nodes identified by the keep set, assigning new identifiers that match their
+// positions in the slice, and the edges between the nodes that were kept.
+func subgraph(nodes []Node, edges []Edge, keep map[int]struct{}) ([]Node, []Edge) {
+	var results []Node
+	idToIdx := make(map[int]int)
+	for _, n := range nodes {
+		if _, found := keep[n.ID]; !found {
+			continue
+		}
+
+		idToIdx[n.ID] = len(results)
+		n.ID = len(results)
+		results = append(results, n)
+	}
+
+	var kept []Edge
+	for _, e := range edges {
+		from, found := idToIdx[e.From]
+		if !found {
+			continue
+		}
+		to, found := idToIdx[e.To]
+		if !found {
+			continue
+		}
+
+		e.From = from
+		e.To = to
+		kept = append(kept, e)
+	}
+	return results, kept
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"testing"
+	"time"
+)
+
+func filterTestGraph() ([]Node, []Edge) {
+	day := time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC)
+	nodes := []Node{
+		{ID: 0, Type: "domain", Label: "owasp.org", ActualType: "fqdn", Sources: []string{"DNS"}, FirstSeen: day},
+		{ID: 1, Type: "subdomain", Label: "www.owasp.org", ActualType: "fqdn",
+			Sources: []string{"Crtsh"}, Tags: []string{"cert"}, FirstSeen: day.Add(48 * time.Hour)},
+		{ID: 2, Type: "subdomain", Label: "www.example.com", ActualType: "fqdn", Sources: []string{"DNS"}, FirstSeen: day},
+		{ID: 3, Type: "address", Label: "192.168.1.1", ActualType: "ipaddr", ASN: 64512},
+		{ID: 4, Type: "address", Label: "192.168.1.2", ActualType: "ipaddr", ASN: 64512},
+		{ID: 5, Type: "netblock", Label: "192.168.1.0/24", ActualType: "netblock"},
+		{ID: 6, Type: "as", Label: "64512", ActualType: "as", ASN: 64512},
+		{ID: 7, Type: "address", Label: "10.0.0.1", ActualType: "ipaddr", ASN: 64513},
+		{ID: 8, Type: "subdomain", Label: "cdn.owasp.org", ActualType: "fqdn", Sources: []string{"DNS"}, FirstSeen: day},
+	}
+	edges := []Edge{
+		{From: 0, To: 1, Title: "root"},
+		{From: 1, To: 3, Title: "a_record"},
+		{From: 2, To: 4, Title: "a_record"},
+		{From: 5, To: 3, Title: "contains"},
+		{From: 5, To: 4, Title: "contains"},
+		{From: 6, To: 5, Title: "prefix"},
+		{From: 0, To: 8, Title: "root"},
+		{From: 8, To: 7, Title: "a_record"},
+		{From: 0, To: 1, Title: "cname_record"},
+	}
+	return nodes, edges
+}
+
+func TestFilterGraph(t *testing.T) {
+	nodes, edges := filterTestGraph()
+
+	if n, e := FilterGraph(nodes, edges, &Filter{}); len(n) != len(nodes) || len(e) != len(edges) {
+		t.Errorf("The empty filter removed nodes or edges")
+	}
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		labels []string
+		edges  int
+	}{
+		{"domain", &Filter{Domains: []string{"OWASP.org"}}, []string{"owasp.org",
+			"www.owasp.org", "cdn.owasp.org", "192.168.1.1", "10.0.0.1", "192.168.1.0/24", "64512"}, 7},
+		{"source", &Filter{Source: "DNS"}, []string{"owasp.org", "www.example.com",
+			"cdn.owasp.org", "192.168.1.2", "10.0.0.1", "192.168.1.0/24", "64512"}, 5},
+		{"tag", &Filter{Tag: "cert"}, []string{"www.owasp.org", "192.168.1.1", "192.168.1.0/24", "64512"}, 3},
+		{"since", &Filter{Since: time.Date(2022, time.April, 2, 0, 0, 0, 0, time.UTC)},
+			[]string{"www.owasp.org", "192.168.1.1", "192.168.1.0/24", "64512"}, 3},
+		{"until", &Filter{Until: time.Date(2022, time.April, 2, 0, 0, 0, 0, time.UTC)}, []string{"owasp.org",
+			"www.example.com", "cdn.owasp.org", "192.168.1.2", "10.0.0.1", "192.168.1.0/24", "64512"}, 5},
+		// The domain reaches the address announced by the autonomous system through the CNAME record
+		{"asn", &Filter{ASN: 64512}, []string{"owasp.org", "www.owasp.org",
+			"www.example.com", "192.168.1.1", "192.168.1.2", "192.168.1.0/24", "64512"}, 7},
+		{"types", &Filter{Domains: []string{"owasp.org"}, Types: []string{"domain", "subdomain"}},
+			[]string{"owasp.org", "www.owasp.org", "cdn.owasp.org"}, 3},
+	}
+
+	for _, test := range tests {
+		n, e := FilterGraph(nodes, edges, test.filter)
+
+		var labels []string
+		for i, node := range n {
+			if node.ID != i {
+				t.Errorf("%s: The node %s has the identifier %d, expected %d", test.name, node.Label, node.ID, i)
+			}
+			labels = append(labels, node.Label)
+		}
+		if len(labels) != len(test.labels) {
+			t.Errorf("%s: The filter kept %v, expected %v", test.name, labels, test.labels)
+			continue
+		}
+		for _, l := range test.labels {
+			if !stringInSlice(l, labels) {
+				t.Errorf("%s: The filter removed %s", test.name, l)
+			}
+		}
+		if len(e) != test.edges {
+			t.Errorf("%s: The filter kept %d edges, expected %d", test.name, len(e), test.edges)
+		}
+		for _, edge := range e {
+			if edge.From >= len(n) || edge.To >= len(n) {
+				t.Errorf("%s: The edge %v refers to a removed node", test.name, edge)
+			}
+		}
+	}
+}
+
+func TestNeighborhood(t *testing.T) {
+	nodes, edges := filterTestGraph()
+
+	if _, _, err := Neighborhood(nodes, edges, "missing.owasp.org", 2); err == nil {
+		t.Errorf("Neighborhood did not return an error for the missing node")
+	}
+
+	n, e, err := Neighborhood(nodes, edges, "192.168.1.0/24", 1)
+	if err != nil {
+		t.Fatalf("Neighborhood returned an error: %v", err)
+	}
+
+	var labels []string
+	for _, node := range n {
+		labels = append(labels, node.Label)
+	}
+	if len(labels) != 4 || !stringInSlice("64512", labels) || !stringInSlice("192.168.1.2", labels) {
+		t.Errorf("The neighborhood was %v, expected the netblock, its addresses and autonomous system", labels)
+	}
+	if len(e) != 3 {
+		t.Errorf("The neighborhood had %d edges, expected 3", len(e))
+	}
+
+	if n, _, _ := Neighborhood(nodes, edges, "192.168.1.0/24", 2); len(n) != 6 {
+		t.Errorf("The neighborhood within two hops had %d nodes, expected 6", len(n))
+	}
+}
+
+func TestParseFilterTime(t *testing.T) {
+	if ts, err := ParseFilterTime("2022-04-01", true); err != nil || ts.Day() != 1 || ts.Hour() != 23 {
+		t.Errorf("The end of the day was parsed as %v: %v", ts, err)
+	}
+	if ts, err := ParseFilterTime("2022-04-01T12:00:00Z", false); err != nil || ts.Hour() != 12 {
+		t.Errorf("The RFC 3339 time was parsed as %v: %v", ts, err)
+	}
+	if _, err := ParseFilterTime("yesterday", false); err == nil {
+		t.Errorf("ParseFilterTime did not return an error for the invalid time")
+	}
+}
@@ -16,6 +16,7 @@ const (
 	xmlNSVIZ string = "http://www.gephi.org/gexf/viz"
 
 	classNode string = "node"
+	classEdge string = "edge"
 
 	modeStatic string = "static"
 
@@ -75,11 +76,11 @@ type gexfMeta struct {
 }
 
 type gexfGraph struct {
-	Mode     string         `xml:"mode,attr,omitempty"`
-	EdgeType string         `xml:"defaultedgetype,attr,omitempty"`
-	Attrs    gexfAttributes `xml:"attributes,omitempty"`
-	Nodes    []gexfNode     `xml:"nodes>node,omitempty"`
-	Edges    []gexfEdge     `xml:"edges>edge,omitempty"`
+	Mode     string           `xml:"mode,attr,omitempty"`
+	EdgeType string           `xml:"defaultedgetype,attr,omitempty"`
+	Attrs    []gexfAttributes `xml:"attributes,omitempty"`
+	Nodes    []gexfNode       `xml:"nodes>node,omitempty"`
+	Edges    []gexfEdge       `xml:"edges>edge,omitempty"`
 }
 
 type gexf struct {
@@ -110,6 +111,11 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) error {
 	}
 	bufwr.Flush()
 
+	var nodeAttrs []gexfAttribute
+	for i, title := range nodeAttributeTitles {
+		nodeAttrs = append(nodeAttrs, gexfAttribute{ID: strconv.Itoa(i), Title: title, Type: "string"})
+	}
+
 	doc := &gexf{
 		XMLName: xml.Name{
 			Space: xmlNS,
@@ -125,12 +131,11 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) error {
 		Graph: gexfGraph{
 			Mode:     modeStatic,
 			EdgeType: edgeTypeDirected,
-			Attrs: gexfAttributes{
-				Class: classNode,
-				Attrs: []gexfAttribute{
-					{ID: "0", Title: "Title", Type: "string"},
-					{ID: "1", Title: "Source", Type: "string"},
-					{ID: "2", Title: "Type", Type: "string"},
+			Attrs: []gexfAttributes{
+				{Class: classNode, Attrs: nodeAttrs},
+				{
+					Class: classEdge,
+					Attrs: []gexfAttribute{{ID: "0", Title: "Predicate", Type: "string"}},
 				},
 			},
 		},
@@ -158,14 +163,17 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) error {
 			color = gexfBlue
 		}
 
+		var attrs []gexfAttrValue
+		for i, v := range nodeAttributeValues(n) {
+			if v != "" {
+				attrs = append(attrs, gexfAttrValue{For: strconv.Itoa(i), Value: v})
+			}
+		}
+
 		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
 			ID:    strconv.Itoa(idx),
 			Label: n.Label,
-			Attrs: []gexfAttrValue{
-				{For: "0", Value: n.Title},
-				{For: "1", Value: n.Source},
-				{For: "2", Value: n.Type},
-			},
+			Attrs: attrs,
 			Color: color,
 		})
 	}
@@ -176,6 +184,7 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) error {
 			Label:  e.Label,
 			Source: strconv.Itoa(e.From),
 			Target: strconv.Itoa(e.To),
+			Attrs:  []gexfAttrValue{{For: "0", Value: e.Title}},
 		})
 	}
 
@@ -23,6 +23,17 @@ const expectedGexfOutput = `<creator>OWASP Amass - https://github.com/OWASP/Amas
               <attribute id="0" title="Title" type="string"></attribute>
               <attribute id="1" title="Source" type="string"></attribute>
               <attribute id="2" title="Type" type="string"></attribute>
+              <attribute id="3" title="FirstSeen" type="string"></attribute>
+              <attribute id="4" title="LastSeen" type="string"></attribute>
+              <attribute id="5" title="Sources" type="string"></attribute>
+              <attribute id="6" title="RecordTypes" type="string"></attribute>
+              <attribute id="7" title="ASN" type="string"></attribute>
+              <attribute id="8" title="Netblock" type="string"></attribute>
+              <attribute id="9" title="Tags" type="string"></attribute>
+              <attribute id="10" title="Confidence" type="string"></attribute>
+          </attributes>
+          <attributes class="edge">
+              <attribute id="0" title="Predicate" type="string"></attribute>
           </attributes>
           <nodes>
               <node id="0" label="owasp.org">
@@ -39,6 +50,14 @@ const expectedGexfOutput = `<creator>OWASP Amass - https://github.com/OWASP/Amas
                       <attvalue for="0" value="address: 205.251.199.98"></attvalue>
                       <attvalue for="1" value="DNS"></attvalue>
                       <attvalue for="2" value="address"></attvalue>
+                      <attvalue for="3" value="2021-03-01T12:00:00Z"></attvalue>
+                      <attvalue for="4" value="2021-03-02T12:00:00Z"></attvalue>
+                      <attvalue for="5" value="DNS"></attvalue>
+                      <attvalue for="6" value="a_record"></attvalue>
+                      <attvalue for="7" value="16509"></attvalue>
+                      <attvalue for="8" value="205.251.192.0/21"></attvalue>
+                      <attvalue for="9" value="in-scope"></attvalue>
+                      <attvalue for="10" value="75"></attvalue>
                   </attvalues>
                   <parents></parents>
                   <viz:color r="243" g="156" b="18"></viz:color>
@@ -46,7 +65,9 @@ const expectedGexfOutput = `<creator>OWASP Amass - https://github.com/OWASP/Amas
           </nodes>
           <edges>
               <edge id="0" source="0" target="1">
-                  <attvalues></attvalues>
+                  <attvalues>
+                      <attvalue for="0" value="a_record"></attvalue>
+                  </attvalues>
               </edge>
           </edges>
       </graph>
@@ -0,0 +1,110 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package viz
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+const graphMLNS string = "http://graphml.graphdrawing.org/xmlns"
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphML struct {
+	XMLName xml.Name
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+// WriteGraphMLData generates a GraphML file to import the Amass graph into tools such as yEd and Gephi.
+func WriteGraphMLData(output io.Writer, nodes []Node, edges []Edge) error {
+	bufwr := bufio.NewWriter(output)
+
+	if _, err := bufwr.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"); err != nil {
+		return err
+	}
+
+	doc := &graphML{
+		XMLName: xml.Name{
+			Space: graphMLNS,
+			Local: "graphml",
+		},
+		Graph: graphMLGraph{
+			ID:          "amass",
+			EdgeDefault: edgeTypeDirected,
+		},
+	}
+
+	doc.Keys = append(doc.Keys, graphMLKey{ID: "label", For: classNode, AttrName: "Label", AttrType: "string"})
+	for i, title := range nodeAttributeTitles {
+		doc.Keys = append(doc.Keys, graphMLKey{
+			ID:       "n" + strconv.Itoa(i),
+			For:      classNode,
+			AttrName: title,
+			AttrType: "string",
+		})
+	}
+	doc.Keys = append(doc.Keys, graphMLKey{ID: "e0", For: classEdge, AttrName: "Predicate", AttrType: "string"})
+
+	for idx, n := range nodes {
+		data := []graphMLData{{Key: "label", Value: n.Label}}
+
+		for i, v := range nodeAttributeValues(n) {
+			if v != "" {
+				data = append(data, graphMLData{Key: "n" + strconv.Itoa(i), Value: v})
+			}
+		}
+
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:   "n" + strconv.Itoa(idx),
+			Data: data,
+		})
+	}
+
+	for idx, e := range edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     "e" + strconv.Itoa(idx),
+			Source: "n" + strconv.Itoa(e.From),
+			Target: "n" + strconv.Itoa(e.To),
+			Data:   []graphMLData{{Key: "e0", Value: e.Title}},
+		})
+	}
+
+	enc := xml.NewEncoder(bufwr)
+	enc.Indent("", "  ")
+	defer bufwr.Flush()
+	return enc.Encode(doc)
+}
@@ -0,0 +1,43 @@
+package viz
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWriteGraphMLData(t *testing.T) {
+	buf := bytes.NewBufferString("")
+	err := WriteGraphMLData(buf, testNodes(), testEdges())
+	assert.Nil(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, expectedGraphMLOutput, "GraphML output should contain")
+}
+
+const expectedGraphMLOutput = `<graph id="amass" edgedefault="directed">
+    <node id="n0">
+      <data key="label">owasp.org</data>
+      <data key="n0">domain: owasp.org</data>
+      <data key="n1">DNS</data>
+      <data key="n2">domain</data>
+    </node>
+    <node id="n1">
+      <data key="label">205.251.199.98</data>
+      <data key="n0">address: 205.251.199.98</data>
+      <data key="n1">DNS</data>
+      <data key="n2">address</data>
+      <data key="n3">2021-03-01T12:00:00Z</data>
+      <data key="n4">2021-03-02T12:00:00Z</data>
+      <data key="n5">DNS</data>
+      <data key="n6">a_record</data>
+      <data key="n7">16509</data>
+      <data key="n8">205.251.192.0/21</data>
+      <data key="n9">in-scope</data>
+      <data key="n10">75</data>
+    </node>
+    <edge id="e0" source="n0" target="n1">
+      <data key="e0">a_record</data>
+    </edge>
+  </graph>
+</graphml>`
@@ -0,0 +1,137 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+)
+
+// Finding is a finding of a running enumeration, along with the time it was observed.
+type Finding struct {
+	Output *requests.Output
+	Seen   time.Time
+}
+
+type graphMerger struct {
+	nodes []Node
+	edges []Edge
+	ids   map[string]int
+	links map[Edge]struct{}
+}
+
+// MergeFindings adds the names, addresses, netblocks and autonomous systems of the findings
+// to the nodes and edges, such as the data read from the graph database. The nodes already
+// present are updated with the sources and times of the findings, and the provided slices
+// are not modified.
+func MergeFindings(nodes []Node, edges []Edge, findings []*Finding) ([]Node, []Edge) {
+	m := &graphMerger{
+		nodes: append([]Node(nil), nodes...),
+		edges: append([]Edge(nil), edges...),
+		ids:   make(map[string]int),
+		links: make(map[Edge]struct{}),
+	}
+	for _, n := range m.nodes {
+		m.ids[n.ActualType+":"+n.Label] = n.ID
+	}
+	for _, e := range m.edges {
+		m.links[Edge{From: e.From, To: e.To, Title: e.Title}] = struct{}{}
+	}
+
+	for _, f := range findings {
+		o := f.Output
+
+		ntype := "subdomain"
+		if o.Name == o.Domain {
+			ntype = "domain"
+		}
+		name := m.node(netmap.TypeFQDN, ntype, o.Name, "", f, o.Sources)
+		if o.Confidence > m.nodes[name].Confidence {
+			m.nodes[name].Confidence = o.Confidence
+		}
+
+		for _, a := range o.Addresses {
+			if a.Address == nil {
+				continue
+			}
+
+			rtype := "a_record"
+			if a.Address.To4() == nil {
+				rtype = "aaaa_record"
+			}
+
+			addr := m.node(netmap.TypeAddr, "address", a.Address.String(), "", f, o.Sources)
+			m.nodes[addr].ASN = a.ASN
+			m.nodes[addr].Netblock = a.CIDRStr
+			m.edge(name, addr, rtype)
+
+			if a.CIDRStr == "" {
+				continue
+			}
+			cidr := m.node(netmap.TypeNetblock, "netblock", a.CIDRStr, "", f, nil)
+			m.edge(cidr, addr, "contains")
+
+			if a.ASN == 0 {
+				continue
+			}
+			as := m.node(netmap.TypeAS, "as", strconv.Itoa(a.ASN), ", Desc: "+a.Description, f, nil)
+			m.nodes[as].ASN = a.ASN
+			m.edge(as, cidr, "prefix")
+		}
+	}
+	return m.nodes, m.edges
+}
+
+// Returns the identifier of the node, which is created when it is not already present.
+func (m *graphMerger) node(atype, vtype, label, desc string, f *Finding, sources []string) int {
+	key := atype + ":" + label
+
+	id, found := m.ids[key]
+	if !found {
+		id = len(m.nodes)
+		m.ids[key] = id
+		m.nodes = append(m.nodes, Node{
+			ID:         id,
+			Type:       vtype,
+			Label:      label,
+			Title:      vtype + ": " + label + desc,
+			ActualType: atype,
+		})
+	}
+
+	n := &m.nodes[id]
+	// The slice can be shared with the nodes provided by the caller
+	srcs := append([]string(nil), n.Sources...)
+	for _, src := range sources {
+		if !stringInSlice(src, srcs) {
+			srcs = append(srcs, src)
+		}
+	}
+	sort.Strings(srcs)
+	n.Sources = srcs
+	if n.Source == "" && len(n.Sources) > 0 {
+		n.Source = n.Sources[0]
+	}
+	if n.FirstSeen.IsZero() || f.Seen.Before(n.FirstSeen) {
+		n.FirstSeen = f.Seen
+	}
+	if f.Seen.After(n.LastSeen) {
+		n.LastSeen = f.Seen
+	}
+	return id
+}
+
+func (m *graphMerger) edge(from, to int, title string) {
+	e := Edge{From: from, To: to, Title: title}
+
+	if _, found := m.links[e]; !found {
+		m.links[e] = struct{}{}
+		m.edges = append(m.edges, e)
+	}
+}
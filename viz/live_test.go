@@ -0,0 +1,75 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestMergeFindings(t *testing.T) {
+	nodes, edges := testNodes(), testEdges()
+	seen := time.Date(2022, time.April, 1, 12, 0, 0, 0, time.UTC)
+	findings := []*Finding{
+		{
+			Seen: seen,
+			Output: &requests.Output{
+				Name:       "www.owasp.org",
+				Domain:     "owasp.org",
+				Sources:    []string{"Crtsh"},
+				Confidence: 50,
+				Addresses: []requests.AddressInfo{{
+					Address:     net.ParseIP("205.251.199.98"),
+					CIDRStr:     "205.251.192.0/21",
+					ASN:         16509,
+					Description: "AMAZON-02",
+				}},
+			},
+		},
+		{
+			Seen: seen.Add(time.Minute),
+			Output: &requests.Output{
+				Name:    "www.owasp.org",
+				Domain:  "owasp.org",
+				Sources: []string{"DNS"},
+			},
+		},
+	}
+
+	merged, medges := MergeFindings(nodes, edges, findings)
+	// The address was already present, while the name, netblock and autonomous system are new
+	if len(merged) != 5 {
+		t.Fatalf("MergeFindings returned %d nodes, expected 5", len(merged))
+	}
+	if len(medges) != 4 {
+		t.Errorf("MergeFindings returned %d edges, expected 4", len(medges))
+	}
+	if len(nodes) != 2 || len(nodes[1].Sources) != 1 {
+		t.Errorf("MergeFindings modified the provided nodes")
+	}
+
+	www := merged[2]
+	if www.Label != "www.owasp.org" || www.Type != "subdomain" {
+		t.Fatalf("The third node was %s %s, expected the subdomain www.owasp.org", www.Type, www.Label)
+	}
+	if len(www.Sources) != 2 || www.Source != "Crtsh" {
+		t.Errorf("The name had the sources %v, expected both data sources", www.Sources)
+	}
+	if !www.FirstSeen.Equal(seen) || !www.LastSeen.Equal(seen.Add(time.Minute)) {
+		t.Errorf("The name was seen between %v and %v", www.FirstSeen, www.LastSeen)
+	}
+	if www.Confidence != 50 {
+		t.Errorf("The name had the confidence %d, expected 50", www.Confidence)
+	}
+	if addr := merged[1]; len(addr.Sources) != 2 {
+		t.Errorf("The address had the sources %v, expected the source of the finding to be added", addr.Sources)
+	}
+	if as := merged[4]; as.Type != "as" || as.Title != "as: 16509, Desc: AMAZON-02" {
+		t.Errorf("The fifth node was %q, expected the autonomous system", as.Title)
+	}
+}
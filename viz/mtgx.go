@@ -0,0 +1,197 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"net"
+	"strconv"
+)
+
+const maltegoNS string = "http://maltego.paterva.com/xml/mtgx"
+
+type mtgxKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+}
+
+type mtgxProperty struct {
+	Name        string `xml:"name,attr"`
+	DisplayName string `xml:"displayName,attr"`
+	Type        string `xml:"type,attr"`
+	Value       string `xml:"mtg:Value"`
+}
+
+type mtgxEntity struct {
+	Type       string         `xml:"type,attr"`
+	Properties []mtgxProperty `xml:"mtg:Properties>mtg:Property"`
+}
+
+type mtgxLink struct {
+	Type       string         `xml:"type,attr"`
+	Properties []mtgxProperty `xml:"mtg:Properties>mtg:Property"`
+}
+
+type mtgxNodeData struct {
+	Key    string     `xml:"key,attr"`
+	Entity mtgxEntity `xml:"mtg:MaltegoEntity"`
+}
+
+type mtgxEdgeData struct {
+	Key  string   `xml:"key,attr"`
+	Link mtgxLink `xml:"mtg:MaltegoLink"`
+}
+
+type mtgxNode struct {
+	ID   string       `xml:"id,attr"`
+	Data mtgxNodeData `xml:"data"`
+}
+
+type mtgxEdge struct {
+	ID     string       `xml:"id,attr"`
+	Source string       `xml:"source,attr"`
+	Target string       `xml:"target,attr"`
+	Data   mtgxEdgeData `xml:"data"`
+}
+
+type mtgxGraph struct {
+	ID          string     `xml:"id,attr"`
+	EdgeDefault string     `xml:"edgedefault,attr"`
+	Nodes       []mtgxNode `xml:"node"`
+	Edges       []mtgxEdge `xml:"edge"`
+}
+
+type mtgxGraphML struct {
+	XMLName xml.Name  `xml:"graphml"`
+	NS      string    `xml:"xmlns,attr"`
+	MTGNS   string    `xml:"xmlns:mtg,attr"`
+	Keys    []mtgxKey `xml:"key"`
+	Graph   mtgxGraph `xml:"graph"`
+}
+
+// WriteMaltegoGraph generates a Maltego graph file (mtgx) that can be opened directly by Maltego.
+// The names, addresses, netblocks and autonomous systems become entities of the matching types.
+func WriteMaltegoGraph(output io.Writer, nodes []Node, edges []Edge) error {
+	zw := zip.NewWriter(output)
+
+	vw, err := zw.Create("Version.properties")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(vw, "maltego.graph.version=1.2\n"); err != nil {
+		return err
+	}
+
+	doc := &mtgxGraphML{
+		NS:    graphMLNS,
+		MTGNS: maltegoNS,
+		Keys: []mtgxKey{
+			{ID: "d0", For: classNode, AttrName: "MaltegoEntity"},
+			{ID: "d1", For: classEdge, AttrName: "MaltegoLink"},
+		},
+		Graph: mtgxGraph{
+			ID:          "G",
+			EdgeDefault: edgeTypeDirected,
+		},
+	}
+
+	included := make(map[int]struct{})
+	for idx, n := range nodes {
+		entity, ok := maltegoEntity(n)
+		if !ok {
+			continue
+		}
+
+		included[idx] = struct{}{}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, mtgxNode{
+			ID:   "n" + strconv.Itoa(idx),
+			Data: mtgxNodeData{Key: "d0", Entity: entity},
+		})
+	}
+
+	for idx, e := range edges {
+		if _, found := included[e.From]; !found {
+			continue
+		}
+		if _, found := included[e.To]; !found {
+			continue
+		}
+
+		doc.Graph.Edges = append(doc.Graph.Edges, mtgxEdge{
+			ID:     "e" + strconv.Itoa(idx),
+			Source: "n" + strconv.Itoa(e.From),
+			Target: "n" + strconv.Itoa(e.To),
+			Data: mtgxEdgeData{
+				Key: "d1",
+				Link: mtgxLink{
+					Type: "maltego.link.manual-link",
+					Properties: []mtgxProperty{{
+						Name:        "maltego.link.manual.type",
+						DisplayName: "Label",
+						Type:        "string",
+						Value:       e.Title,
+					}},
+				},
+			},
+		})
+	}
+
+	gw, err := zw.Create("Graphs/Graph1.graphml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(gw, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(gw)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Returns the Maltego entity representing the node, or false when Maltego has no matching entity type.
+func maltegoEntity(n Node) (mtgxEntity, bool) {
+	var etype, prop, display string
+	value := n.Label
+
+	switch n.Type {
+	case "domain":
+		etype, prop, display = "maltego.Domain", "fqdn", "Domain Name"
+	case "subdomain", "cname", "ptr":
+		etype, prop, display = "maltego.DNSName", "fqdn", "DNS Name"
+	case "ns":
+		etype, prop, display = "maltego.NSRecord", "fqdn", "NS Record"
+	case "mx":
+		etype, prop, display = "maltego.MXRecord", "fqdn", "MX Record"
+	case "address":
+		etype, prop, display = "maltego.IPv4Address", "ipv4-address", "IP Address"
+		if ip := net.ParseIP(n.Label); ip != nil && ip.To4() == nil {
+			etype, prop, display = "maltego.IPv6Address", "ipv6-address", "IPv6 Address"
+		}
+	case "netblock":
+		etype, prop, display = "maltego.Netblock", "ipv4-range", "IP Range"
+		value = cidrToMaltegoNetblock(n.Label)
+	case "as":
+		etype, prop, display = "maltego.AS", "as.number", "AS Number"
+	default:
+		return mtgxEntity{}, false
+	}
+
+	return mtgxEntity{
+		Type: etype,
+		Properties: []mtgxProperty{{
+			Name:        prop,
+			DisplayName: display,
+			Type:        "string",
+			Value:       value,
+		}},
+	}, true
+}
@@ -0,0 +1,37 @@
+package viz
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMaltegoGraph(t *testing.T) {
+	buf := bytes.NewBufferString("")
+	err := WriteMaltegoGraph(buf, testNodes(), testEdges())
+	assert.Nil(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+
+	var graph string
+	for _, f := range zr.File {
+		if f.Name != "Graphs/Graph1.graphml" {
+			continue
+		}
+
+		rc, err := f.Open()
+		assert.Nil(t, err)
+		data, _ := ioutil.ReadAll(rc)
+		_ = rc.Close()
+		graph = string(data)
+	}
+
+	assert.Contains(t, graph, `<mtg:MaltegoEntity type="maltego.Domain">`, "Maltego graph should contain")
+	assert.Contains(t, graph, `<mtg:MaltegoEntity type="maltego.IPv4Address">`, "Maltego graph should contain")
+	assert.Contains(t, graph, `<mtg:Value>205.251.199.98</mtg:Value>`, "Maltego graph should contain")
+	assert.Contains(t, graph, `<edge id="e0" source="n0" target="n1">`, "Maltego graph should contain")
+}
@@ -0,0 +1,345 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+)
+
+const serverPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <title>OWASP Amass Network Mapping</title>
+    <script src="https://d3js.org/d3.v4.min.js"></script>
+    <style>
+        body { margin: 0; font-family: 'Open Sans', sans-serif; font-size: 13px; }
+        form { position: absolute; top: 0; left: 0; right: 0; padding: 8px; background-color: #f4f4f4; border-bottom: 1px solid #999; }
+        form label { margin-right: 12px; }
+        #status { float: right; color: #555; }
+        svg { display: block; }
+        line { stroke: #aaa; }
+        circle { stroke: #333; stroke-width: 1px; }
+    </style>
+</head>
+<body>
+<form id="filters">
+    <label>Domain <select name="domain"><option value="">All</option></select></label>
+    <label>Source <select name="source"><option value="">All</option></select></label>
+    <label>Tag <select name="tag"><option value="">All</option></select></label>
+    <label>Discovered since <input type="date" name="since"></label>
+    <label>until <input type="date" name="until"></label>
+    <span id="status"></span>
+</form>
+<svg id="graph"></svg>
+
+<script>
+/* global d3 */
+
+var colors = {
+    "subdomain": "green",
+    "domain": "red",
+    "address": "orange",
+    "ptr": "yellow",
+    "ns": "cyan",
+    "mx": "purple",
+    "netblock": "pink",
+    "as": "blue"
+};
+
+var width = window.innerWidth,
+    height = window.innerHeight,
+    svg = d3.select("#graph").attr("width", width).attr("height", height),
+    view = svg.append("g"),
+    link = view.append("g").selectAll("line"),
+    node = view.append("g").selectAll("circle"),
+    nodesByKey = {};
+
+svg.call(d3.zoom().scaleExtent([1 / 10, 8]).on("zoom", function() {
+    view.attr("transform", d3.event.transform);
+}));
+
+var simulation = d3.forceSimulation()
+    .force("link", d3.forceLink().id(function(d) { return d.key; }).distance(40))
+    .force("charge", d3.forceManyBody().strength(-60).distanceMax(width))
+    .force("center", d3.forceCenter(width / 2, height / 2))
+    .on("tick", function() {
+        link.attr("x1", function(d) { return d.source.x; })
+            .attr("y1", function(d) { return d.source.y; })
+            .attr("x2", function(d) { return d.target.x; })
+            .attr("y2", function(d) { return d.target.y; });
+        node.attr("cx", function(d) { return d.x; })
+            .attr("cy", function(d) { return d.y; });
+    });
+
+function fillSelect(name, values) {
+    var sel = d3.select("select[name=" + name + "]"),
+        current = sel.property("value");
+
+    var opts = sel.selectAll("option.value").data(values, function(d) { return d; });
+    opts.exit().remove();
+    opts.enter().append("option").attr("class", "value")
+        .attr("value", function(d) { return d; })
+        .text(function(d) { return d; });
+    sel.property("value", current);
+}
+
+function query() {
+    var params = [];
+
+    d3.selectAll("#filters select, #filters input").each(function() {
+        if (this.value) {
+            params.push(this.name + "=" + encodeURIComponent(this.value));
+        }
+    });
+    return params.join("&");
+}
+
+function refresh() {
+    d3.json("graph?" + query(), function(err, data) {
+        if (err) {
+            d3.select("#status").text("The graph could not be obtained");
+            return;
+        }
+
+        fillSelect("domain", data.domains);
+        fillSelect("source", data.sources);
+        fillSelect("tag", data.tags);
+
+        var changed = data.nodes.length !== Object.keys(nodesByKey).length,
+            next = {};
+        // Preserve the positions of the nodes already displayed
+        data.nodes.forEach(function(n) {
+            var old = nodesByKey[n.key];
+
+            if (old) {
+                n.x = old.x; n.y = old.y; n.vx = old.vx; n.vy = old.vy;
+            } else {
+                changed = true;
+            }
+            next[n.key] = n;
+        });
+        nodesByKey = next;
+
+        link = link.data(data.edges, function(d) { return d.source + ">" + d.target + ":" + d.label; });
+        link.exit().remove();
+        link = link.enter().append("line").merge(link);
+
+        node = node.data(data.nodes, function(d) { return d.key; });
+        node.exit().remove();
+        var added = node.enter().append("circle")
+            .attr("r", function(d) { return d.type === "domain" || d.type === "as" ? 8 : 5; })
+            .call(d3.drag()
+                .on("start", function(d) {
+                    if (!d3.event.active) simulation.alphaTarget(0.3).restart();
+                    d.fx = d.x; d.fy = d.y;
+                })
+                .on("drag", function(d) { d.fx = d3.event.x; d.fy = d3.event.y; })
+                .on("end", function(d) {
+                    if (!d3.event.active) simulation.alphaTarget(0);
+                    d.fx = null; d.fy = null;
+                }));
+        added.append("title");
+        node = added.merge(node)
+            .attr("fill", function(d) { return colors[d.type] || "gray"; });
+        node.select("title").text(function(d) {
+            var text = d.title;
+
+            if (d.sources) text += "\nSources: " + d.sources.join(", ");
+            if (d.tags) text += "\nTags: " + d.tags.join(", ");
+            if (d.first_seen) text += "\nFirst seen: " + d.first_seen;
+            return text;
+        });
+
+        simulation.nodes(data.nodes);
+        simulation.force("link").links(data.edges);
+        if (changed) {
+            simulation.alpha(0.3).restart();
+        }
+
+        d3.select("#status").text(data.names + " names and " + data.nodes.length +
+            " nodes, updated at " + new Date().toLocaleTimeString());
+    });
+}
+
+d3.selectAll("#filters select, #filters input").on("change", refresh);
+refresh();
+setInterval(refresh, {{INTERVAL}});
+</script>
+</body>
+</html>
+`
+
+// The interval between the refreshes of the graph requested by the live web UI.
+const serverRefreshInterval = 5 * time.Second
+
+type serverNode struct {
+	// Identifies the node across the refreshes of the graph
+	Key       string   `json:"key"`
+	Label     string   `json:"label"`
+	Type      string   `json:"type"`
+	Title     string   `json:"title"`
+	Sources   []string `json:"sources,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	FirstSeen string   `json:"first_seen,omitempty"`
+}
+
+type serverEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Label  string `json:"label"`
+}
+
+type serverGraph struct {
+	Names int          `json:"names"`
+	Nodes []serverNode `json:"nodes"`
+	Edges []serverEdge `json:"edges"`
+	// The values offered by the filters, which are obtained from the complete graph
+	Domains []string `json:"domains"`
+	Sources []string `json:"sources"`
+	Tags    []string `json:"tags"`
+}
+
+// NewServer returns the handler of the live web UI, which renders the graph using a D3 force layout.
+// The page requests the graph every few seconds and the load function is called for each request, so
+// the graph is updated while an enumeration is running. The graph can be filtered by the domain, data
+// source, tag and discovery time of the names, using the domain, source, tag, since and until parameters.
+func NewServer(load func() ([]Node, []Edge)) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/graph", func(w http.ResponseWriter, r *http.Request) {
+		f, err := parseServerFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		nodes, edges := load()
+		graph := serverFilterValues(nodes)
+		nodes, edges = FilterGraph(nodes, edges, f)
+
+		for _, n := range nodes {
+			if n.ActualType == netmap.TypeFQDN {
+				graph.Names++
+			}
+
+			var first string
+			if !n.FirstSeen.IsZero() {
+				first = n.FirstSeen.UTC().Format(time.RFC3339)
+			}
+			graph.Nodes = append(graph.Nodes, serverNode{
+				Key:       n.ActualType + ":" + n.Label,
+				Label:     n.Label,
+				Type:      n.Type,
+				Title:     n.Title,
+				Sources:   n.Sources,
+				Tags:      n.Tags,
+				FirstSeen: first,
+			})
+		}
+		for _, e := range edges {
+			graph.Edges = append(graph.Edges, serverEdge{
+				Source: graph.Nodes[e.From].Key,
+				Target: graph.Nodes[e.To].Key,
+				Label:  e.Title,
+			})
+		}
+		if graph.Nodes == nil {
+			graph.Nodes = []serverNode{}
+		}
+		if graph.Edges == nil {
+			graph.Edges = []serverEdge{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(graph)
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = io.WriteString(w, serverPageHTML())
+	})
+	return mux
+}
+
+func serverPageHTML() string {
+	return strings.Replace(serverPage, "{{INTERVAL}}", fmt.Sprint(serverRefreshInterval.Milliseconds()), 1)
+}
+
+func parseServerFilter(r *http.Request) (*Filter, error) {
+	q := r.URL.Query()
+	f := &Filter{
+		Source: q.Get("source"),
+		Tag:    q.Get("tag"),
+	}
+	if d := q.Get("domain"); d != "" {
+		f.Domains = []string{d}
+	}
+
+	var err error
+	if s := q.Get("since"); s != "" {
+		if f.Since, err = ParseFilterTime(s, false); err != nil {
+			return nil, err
+		}
+	}
+	if s := q.Get("until"); s != "" {
+		if f.Until, err = ParseFilterTime(s, true); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Returns the graph holding the domains, sources and tags of the names offered by the filters.
+func serverFilterValues(nodes []Node) *serverGraph {
+	domains := make(map[string]struct{})
+	sources := make(map[string]struct{})
+	tags := make(map[string]struct{})
+
+	for _, n := range nodes {
+		if n.ActualType != netmap.TypeFQDN {
+			continue
+		}
+		if n.Type == "domain" {
+			domains[n.Label] = struct{}{}
+		}
+		for _, src := range n.Sources {
+			sources[src] = struct{}{}
+		}
+		for _, tag := range n.Tags {
+			tags[tag] = struct{}{}
+		}
+	}
+
+	return &serverGraph{
+		Domains: sortedKeys(domains),
+		Sources: sortedKeys(sources),
+		Tags:    sortedKeys(tags),
+	}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := []string{}
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,61 @@
+// Copyright © by Jeff Foley 2017-2022. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	srv := httptest.NewServer(NewServer(filterTestGraph))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/graph?domain=owasp.org&until=2022-04-03")
+	if err != nil {
+		t.Fatalf("Failed to request the graph: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var graph serverGraph
+	if err := json.NewDecoder(resp.Body).Decode(&graph); err != nil {
+		t.Fatalf("Failed to decode the graph: %v", err)
+	}
+	if graph.Names != 3 || len(graph.Nodes) != 7 || len(graph.Edges) != 7 {
+		t.Errorf("The graph had %d names, %d nodes and %d edges, expected 3, 7 and 7",
+			graph.Names, len(graph.Nodes), len(graph.Edges))
+	}
+	if e := graph.Edges[0]; e.Source != "fqdn:owasp.org" || e.Target != "fqdn:www.owasp.org" {
+		t.Errorf("The first edge was from %s to %s", e.Source, e.Target)
+	}
+	// The values offered by the filters are obtained from the complete graph
+	if len(graph.Domains) != 1 || len(graph.Sources) != 2 || len(graph.Tags) != 1 {
+		t.Errorf("The filters offered the domains %v, sources %v and tags %v", graph.Domains, graph.Sources, graph.Tags)
+	}
+
+	resp, err = http.Get(srv.URL + "/graph?since=yesterday")
+	if err != nil {
+		t.Fatalf("Failed to request the graph: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("The invalid time returned the status %d, expected %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	resp, err = http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatalf("Failed to request the page: %v", err)
+	}
+	defer resp.Body.Close()
+
+	page, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(page), "setInterval(refresh, 5000)") {
+		t.Errorf("The page does not refresh the graph every five seconds")
+	}
+}
@@ -5,8 +5,12 @@ package viz
 
 import (
 	"context"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/OWASP/Amass/v3/requests"
 	"github.com/caffix/netmap"
 	"github.com/caffix/stringset"
 	"github.com/cayleygraph/quad"
@@ -27,8 +31,21 @@ type Node struct {
 	Title      string
 	Source     string
 	ActualType string
+	// Attributes provided by the formats that support them
+	Sources     []string
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	RecordTypes []string
+	ASN         int
+	Netblock    string
+	Tags        []string
+	Confidence  int
 }
 
+// The DNS record types that can reference a node.
+var vizRecordTypes = []string{"cname_record", "a_record", "aaaa_record", "ptr_record",
+	"srv_record", "ns_record", "mx_record", "service"}
+
 // VizData returns the current state of the Graph as viz package Nodes and Edges.
 func VizData(ctx context.Context, g *netmap.Graph, uuids []string) ([]Node, []Edge) {
 	quads, err := g.ReadEventQuads(ctx, uuids...)
@@ -43,6 +60,18 @@ func VizData(ctx context.Context, g *netmap.Graph, uuids []string) ([]Node, []Ed
 		}
 	}
 
+	events := make(map[string][2]time.Time, len(uuids))
+	for _, uuid := range uuids {
+		events[uuid] = eventTimes(nodeQuads[uuid])
+	}
+	// Index the quads by the object to find the references to each node
+	objQuads := make(map[string][]quad.Quad)
+	for _, q := range quads {
+		if k := valToStr(q.Get(quad.Object)); k != "" {
+			objQuads[k] = append(objQuads[k], q)
+		}
+	}
+
 	var idx int
 	var nodes []Node
 	nodeToIdx := make(map[string]int)
@@ -78,6 +107,11 @@ func VizData(ctx context.Context, g *netmap.Graph, uuids []string) ([]Node, []Ed
 			ActualType: ntype,
 		}
 
+		setNodeAttributes(&n, subject, events, objQuads)
+		setCompactedTimes(&n, qs)
+		n.Tags = nodeTags(qs)
+		n.Confidence = nodeConfidence(qs)
+
 		n.ID = idx
 		// Keep track of which indices nodes were assigned to
 		nodeToIdx[subject] = idx
@@ -88,6 +122,160 @@ func VizData(ctx context.Context, g *netmap.Graph, uuids []string) ([]Node, []Ed
 	return nodes, vizEdges(nodes, nodeToIdx, nodeQuads)
 }
 
+// The attributes of the nodes provided by the formats that support them.
+var nodeAttributeTitles = []string{"Title", "Source", "Type", "FirstSeen",
+	"LastSeen", "Sources", "RecordTypes", "ASN", "Netblock", "Tags", "Confidence"}
+
+// Returns the node attribute values in the order of nodeAttributeTitles.
+func nodeAttributeValues(n Node) []string {
+	var first, last, asn, conf string
+
+	if !n.FirstSeen.IsZero() {
+		first = n.FirstSeen.UTC().Format(time.RFC3339)
+	}
+	if !n.LastSeen.IsZero() {
+		last = n.LastSeen.UTC().Format(time.RFC3339)
+	}
+	if n.ASN != 0 {
+		asn = strconv.Itoa(n.ASN)
+	}
+	if n.Confidence != 0 {
+		conf = strconv.Itoa(n.Confidence)
+	}
+
+	return []string{n.Title, n.Source, n.Type, first, last,
+		strings.Join(n.Sources, ","), strings.Join(n.RecordTypes, ","), asn, n.Netblock,
+		strings.Join(n.Tags, ","), conf}
+}
+
+// Returns the start and finish times of the event.
+func eventTimes(quads []quad.Quad) [2]time.Time {
+	var times [2]time.Time
+
+	for _, q := range quads {
+		t, ok := q.Get(quad.Object).Native().(time.Time)
+		if !ok {
+			continue
+		}
+
+		switch valToStr(q.Get(quad.Predicate)) {
+		case "start":
+			times[0] = t
+		case "finish":
+			times[1] = t
+		}
+	}
+	return times
+}
+
+// Sets the sources, first and last seen times, record types, ASN and netblock of the node.
+func setNodeAttributes(n *Node, id string, events map[string][2]time.Time, objQuads map[string][]quad.Quad) {
+	sources := stringset.New()
+	defer sources.Close()
+	records := stringset.New()
+	defer records.Close()
+	rtypes := stringset.New(vizRecordTypes...)
+	defer rtypes.Close()
+
+	for _, q := range objQuads[id] {
+		subject := valToStr(q.Get(quad.Subject))
+		pred := valToStr(q.Get(quad.Predicate))
+
+		if times, found := events[subject]; found {
+			if pred == "" || pred == "domain" || pred == "used" {
+				continue
+			}
+
+			sources.Insert(pred)
+			if !times[0].IsZero() && (n.FirstSeen.IsZero() || times[0].Before(n.FirstSeen)) {
+				n.FirstSeen = times[0]
+			}
+			if times[1].After(n.LastSeen) {
+				n.LastSeen = times[1]
+			}
+			continue
+		}
+
+		switch {
+		case rtypes.Has(pred):
+			records.Insert(pred)
+		case pred == "contains" && n.Netblock == "":
+			n.Netblock = subject
+		case pred == "prefix" && n.ASN == 0:
+			n.ASN, _ = strconv.Atoi(subject)
+		}
+	}
+
+	switch n.Type {
+	case "as":
+		n.ASN, _ = strconv.Atoi(id)
+	case "address":
+		// The ASN of an address is found through the netblock containing it
+		for _, q := range objQuads[n.Netblock] {
+			if valToStr(q.Get(quad.Predicate)) == "prefix" {
+				n.ASN, _ = strconv.Atoi(valToStr(q.Get(quad.Subject)))
+				break
+			}
+		}
+	}
+
+	n.Sources = sources.Slice()
+	sort.Strings(n.Sources)
+	n.RecordTypes = records.Slice()
+	sort.Strings(n.RecordTypes)
+}
+
+// Extends the first and last seen times of the node using the interval stored when events were compacted.
+func setCompactedTimes(n *Node, quads []quad.Quad) {
+	for _, q := range quads {
+		pred := valToStr(q.Get(quad.Predicate))
+		if pred != requests.FirstSeenPredicate && pred != requests.LastSeenPredicate {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, valToStr(q.Get(quad.Object)))
+		if err != nil {
+			continue
+		}
+		if pred == requests.FirstSeenPredicate && (n.FirstSeen.IsZero() || t.Before(n.FirstSeen)) {
+			n.FirstSeen = t
+		}
+		if pred == requests.LastSeenPredicate && t.After(n.LastSeen) {
+			n.LastSeen = t
+		}
+	}
+}
+
+// Returns the user-defined tags attached to the node.
+func nodeTags(quads []quad.Quad) []string {
+	var tags []string
+
+	for _, q := range quads {
+		if p := valToStr(q.Get(quad.Predicate)); p == "user_tag" {
+			if obj := valToStr(q.Get(quad.Object)); obj != "" {
+				tags = append(tags, obj)
+			}
+		}
+	}
+
+	sort.Strings(tags)
+	return tags
+}
+
+// Returns the highest confidence stored for the node, or zero when the node has not been scored.
+func nodeConfidence(quads []quad.Quad) int {
+	var conf int
+
+	for _, q := range quads {
+		if p := valToStr(q.Get(quad.Predicate)); p == requests.ConfidencePredicate {
+			if c, err := strconv.Atoi(valToStr(q.Get(quad.Object))); err == nil && c > conf {
+				conf = c
+			}
+		}
+	}
+	return conf
+}
+
 func getType(quads []quad.Quad) string {
 	var t string
 
@@ -159,7 +347,7 @@ func vizEdges(nodes []Node, nodeToIdx map[string]int, quads map[string][]quad.Qu
 	for _, n := range nodes {
 		e := outEdges(quads[n.Label], "root", "cname_record",
 			"a_record", "aaaa_record", "ptr_record", "service",
-			"srv_record", "ns_record", "mx_record", "contains", "prefix")
+			"srv_record", "ns_record", "mx_record", "contains", "prefix", "certificate", "http_service")
 
 		for _, edge := range e {
 			pred := valToStr(edge.Get(quad.Predicate))
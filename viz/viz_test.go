@@ -6,8 +6,10 @@ package viz
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/caffix/netmap"
+	"github.com/cayleygraph/quad"
 )
 
 func TestViz(t *testing.T) {
@@ -31,6 +33,9 @@ func TestViz(t *testing.T) {
 			if err != nil {
 				t.Errorf("Error inserting A record.\n%v", err)
 			}
+			if err := g.UpsertProperty(context.Background(), netmap.Node(tc.addr), "confidence", "75"); err != nil {
+				t.Errorf("Error inserting the confidence.\n%v", err)
+			}
 			gotNode, gotEdge := VizData(context.Background(), g, []string{tc.eventID})
 			if gotNode == nil {
 				t.Errorf("Failed to obtain node.\n%v", gotNode)
@@ -38,6 +43,20 @@ func TestViz(t *testing.T) {
 			if gotEdge == nil {
 				t.Errorf("Failed to obtain edge.\n%v", gotEdge)
 			}
+			for _, n := range gotNode {
+				if n.Label != tc.addr {
+					continue
+				}
+				if len(n.Sources) != 1 || n.Sources[0] != tc.source {
+					t.Errorf("The address node sources were %v, expected %s", n.Sources, tc.source)
+				}
+				if len(n.RecordTypes) != 1 || n.RecordTypes[0] != "a_record" {
+					t.Errorf("The address node record types were %v, expected a_record", n.RecordTypes)
+				}
+				if n.Confidence != 75 {
+					t.Errorf("The address node confidence was %d, expected 75", n.Confidence)
+				}
+			}
 
 		})
 	}
@@ -65,12 +84,38 @@ func testNodes() []Node {
 			ActualType: "fqdn",
 		},
 		{
-			ID:         1,
-			Type:       "address",
-			Label:      "205.251.199.98",
-			Title:      "address: 205.251.199.98",
-			Source:     "DNS",
-			ActualType: "ipaddr",
+			ID:          1,
+			Type:        "address",
+			Label:       "205.251.199.98",
+			Title:       "address: 205.251.199.98",
+			Source:      "DNS",
+			ActualType:  "ipaddr",
+			Sources:     []string{"DNS"},
+			FirstSeen:   time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC),
+			LastSeen:    time.Date(2021, time.March, 2, 12, 0, 0, 0, time.UTC),
+			RecordTypes: []string{"a_record"},
+			ASN:         16509,
+			Netblock:    "205.251.192.0/21",
+			Tags:        []string{"in-scope"},
+			Confidence:  75,
 		},
 	}
 }
+
+func TestSetCompactedTimes(t *testing.T) {
+	n := Node{
+		FirstSeen: time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC),
+		LastSeen:  time.Date(2021, time.March, 2, 12, 0, 0, 0, time.UTC),
+	}
+
+	setCompactedTimes(&n, []quad.Quad{
+		quad.Make(quad.IRI("www.owasp.org"), quad.IRI("first_seen"), "2021-01-01T12:00:00Z", nil),
+		quad.Make(quad.IRI("www.owasp.org"), quad.IRI("last_seen"), "2021-02-01T12:00:00Z", nil),
+	})
+	if !n.FirstSeen.Equal(time.Date(2021, time.January, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("The first seen time was %v, expected the compacted time", n.FirstSeen)
+	}
+	if !n.LastSeen.Equal(time.Date(2021, time.March, 2, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("The last seen time was %v, expected the time from the event", n.LastSeen)
+	}
+}